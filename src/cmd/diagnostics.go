@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/benn-herrera/xplatter/loader"
+	"github.com/benn-herrera/xplatter/resolver"
+	"github.com/benn-herrera/xplatter/validate"
+	"github.com/spf13/cobra"
+)
+
+// failure is an error that stopped a command before or after validation,
+// with the rule ID --format json reports it under.
+type failure struct {
+	rule string
+	err  error
+}
+
+func (f *failure) Error() string { return f.err.Error() }
+func (f *failure) Unwrap() error { return f.err }
+
+func fail(rule string, err error) error {
+	return &failure{rule: rule, err: err}
+}
+
+// checkFormat rejects a --format other than text or json. In json format it
+// turns off progress output, since stdout carries the diagnostics.
+func checkFormat(format string) error {
+	switch format {
+	case "text":
+		return nil
+	case "json":
+		quiet, verbose = true, false
+		return nil
+	}
+	return fmt.Errorf("unknown format %q (want text or json)", format)
+}
+
+// reportDiagnostics finishes a validate or generate run. In text format it
+// returns err as is. In json format it writes the validation errors and
+// warnings, or the error that stopped the run, to stdout as a JSON array, and
+// returns an error without message when there are errors. result may be nil
+// if the run stopped before validation.
+func reportDiagnostics(cmd *cobra.Command, format, apiDefPath string, result *validate.ValidationResult, err error) error {
+	if format != "json" {
+		return err
+	}
+	var diags []validate.Diagnostic
+	if result != nil {
+		diags = result.Diagnostics()
+	}
+	if err != nil && (result == nil || result.IsValid()) {
+		diags = append(diags, errorDiagnostics(apiDefPath, err)...)
+	}
+	if werr := validate.WriteDiagnostics(os.Stdout, diags); werr != nil {
+		return werr
+	}
+	if err != nil {
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+	}
+	return err
+}
+
+// errorDiagnostics converts an error other than a failed validation to
+// diagnostics: one per violation of a schema error, else a single one located
+// at the .fbs position of a schema parse error or at the API definition.
+func errorDiagnostics(apiDefPath string, err error) []validate.Diagnostic {
+	var schemaErr *loader.SchemaError
+	if errors.As(err, &schemaErr) {
+		var diags []validate.Diagnostic
+		for _, v := range schemaErr.Violations {
+			diags = append(diags, validate.Diagnostic{
+				Rule:     v.Rule,
+				Severity: "error",
+				File:     schemaErr.File,
+				Line:     v.Line,
+				Column:   v.Column,
+				Path:     v.Path,
+				Message:  v.Message,
+				Fix:      v.Fix,
+			})
+		}
+		return diags
+	}
+	rule := "error"
+	var f *failure
+	if errors.As(err, &f) {
+		rule = f.rule
+	}
+	var parseErr *resolver.ParseError
+	if errors.As(err, &parseErr) && parseErr.Pos.File != "" {
+		return []validate.Diagnostic{{
+			Rule:     rule,
+			Severity: "error",
+			File:     parseErr.Pos.File,
+			Line:     parseErr.Pos.Line,
+			Column:   parseErr.Pos.Col,
+			Message:  parseErr.Msg,
+		}}
+	}
+	return []validate.Diagnostic{{Rule: rule, Severity: "error", File: apiDefPath, Message: err.Error()}}
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/benn-herrera/xplatter/diff"
+	"github.com/benn-herrera/xplatter/loader"
+	"github.com/benn-herrera/xplatter/resolver"
+	"github.com/benn-herrera/xplatter/validate"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of diff, besides 0 and 1 for an error.
+const (
+	diffExitVersion  = 2 // the version bump is smaller than the changes need
+	diffExitBreaking = 3 // --fail-on-breaking and a breaking change
+)
+
+var (
+	diffFormat         string
+	diffOutput         string
+	diffIncludes       []string
+	diffFailOnBreaking bool
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [old-api-definition.yaml] [new-api-definition.yaml]",
+	Short: "Classify the changes between two API definitions and check the version bump",
+	Long: `Compares two API definitions, with the FlatBuffers types each uses, and
+classifies every change as compatible, an addition, or breaking the C ABI or
+the source API. Then checks that api.version was bumped enough for the
+changes.
+
+Exit codes: 0 if the version bump covers the changes, 1 on error or if the
+version went down, 2 if the version bump is too small, 3 if --fail-on-breaking is set and a change is
+breaking.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffFormat, "format", "text", "Output format: text or json")
+	diffCmd.Flags().StringVarP(&diffOutput, "output", "o", "", "Write the report to a file instead of stdout")
+	diffCmd.Flags().StringSliceVarP(&diffIncludes, "include-dir", "I", nil, "Directory to search for included .fbs files (repeatable)")
+	diffCmd.Flags().BoolVar(&diffFailOnBreaking, "fail-on-breaking", false, "Exit 3 on any breaking change, even with a major version bump")
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	if !slices.Contains(diff.Formats, diffFormat) {
+		return fmt.Errorf("unknown format %q (want text or json)", diffFormat)
+	}
+
+	oldDef, err := loadDiffDefinition(args[0])
+	if err != nil {
+		return err
+	}
+	newDef, err := loadDiffDefinition(args[1])
+	if err != nil {
+		return err
+	}
+	report := diff.Compare(*oldDef, *newDef)
+
+	out := os.Stdout
+	if diffOutput != "" {
+		f, err := os.Create(diffOutput)
+		if err != nil {
+			return fmt.Errorf("creating %s: %w", diffOutput, err)
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := diff.WriteReport(out, diffFormat, report); err != nil {
+		return err
+	}
+
+	// The report already explains a failed check.
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	if report.VersionErr != nil {
+		return report.VersionErr
+	}
+	if !report.VersionOK() {
+		return &exitError{code: diffExitVersion, err: errors.New(report.VersionSummary())}
+	}
+	if diffFailOnBreaking && report.Count(diff.LevelBreaking) > 0 {
+		return &exitError{code: diffExitBreaking, err: fmt.Errorf("%d breaking change(s)", report.Count(diff.LevelBreaking))}
+	}
+	return nil
+}
+
+// loadDiffDefinition loads and validates an API definition and the
+// FlatBuffers schemas it lists, found next to it as for generate.
+func loadDiffDefinition(path string) (*diff.Definition, error) {
+	def, srcMap, err := loader.LoadAPIDefinition(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading API definition %s: %w", path, err)
+	}
+	fbsSet, err := resolver.LoadFBSFiles(schemaSearchDirs(filepath.Dir(path)), diffIncludes, def.FlatBuffers)
+	if err != nil {
+		return nil, fmt.Errorf("parsing FlatBuffers schemas of %s: %w", path, err)
+	}
+	// Comparing assumes each definition is valid.
+	if result := validate.Validate(def, fbsSet.Types, path, srcMap); !result.IsValid() {
+		return nil, fmt.Errorf("semantic validation of %s failed:\n%s", path, result.Error())
+	}
+	return &diff.Definition{API: def, Types: fbsSet.Types}, nil
+}
@@ -13,13 +13,17 @@ import (
 )
 
 var (
-	genOutput    string
-	genFlatc     string
-	genImplLang  string
-	genTargets   []string
-	genDryRun    bool
-	genClean     bool
-	genSkipFlatc bool
+	genOutput     string
+	genFlatc      string
+	genImplLang   string
+	genTargets    []string
+	genDryRun     bool
+	genClean      bool
+	genSkipFlatc  bool
+	genIncludes   []string
+	genFormat     string
+	genLocked     bool
+	genUpdateLock bool
 )
 
 var generateCmd = &cobra.Command{
@@ -37,12 +41,25 @@ func init() {
 	generateCmd.Flags().BoolVar(&genDryRun, "dry-run", false, "Show what would be generated without writing")
 	generateCmd.Flags().BoolVar(&genClean, "clean", false, "Remove previously generated files first")
 	generateCmd.Flags().BoolVar(&genSkipFlatc, "skip-flatc", false, "Skip flatc invocation even if flatc is available")
+	generateCmd.Flags().StringSliceVarP(&genIncludes, "include-dir", "I", nil, "Directory to search for included .fbs files (repeatable)")
+	generateCmd.Flags().StringVar(&genFormat, "format", "text", "Diagnostics format: text or json")
+	generateCmd.Flags().BoolVar(&genLocked, "locked", false, "Fail if the C ABI no longer matches "+gen.ABILockFile)
+	generateCmd.Flags().BoolVar(&genUpdateLock, "update-lock", false, "Rewrite "+gen.ABILockFile+" with the current C ABI")
+	generateCmd.MarkFlagsMutuallyExclusive("locked", "update-lock")
 	rootCmd.AddCommand(generateCmd)
 }
 
 func runGenerate(cmd *cobra.Command, args []string) error {
-	apiDefPath := args[0]
+	if err := checkFormat(genFormat); err != nil {
+		return err
+	}
+	result, err := generateAPI(args[0])
+	return reportDiagnostics(cmd, genFormat, args[0], result, err)
+}
 
+// generateAPI validates an API definition and generates its code. The
+// result is nil if loading failed.
+func generateAPI(apiDefPath string) (*validate.ValidationResult, error) {
 	if !quiet {
 		fmt.Printf("Generating from %s\n", apiDefPath)
 	}
@@ -50,7 +67,7 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	// Load and schema-validate
 	def, srcMap, err := loader.LoadAPIDefinition(apiDefPath)
 	if err != nil {
-		return fmt.Errorf("loading API definition: %w", err)
+		return nil, fail("load", fmt.Errorf("loading API definition: %w", err))
 	}
 
 	// Apply CLI overrides
@@ -64,16 +81,33 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	// Resolve FlatBuffers types — search YAML dir first, then exe-sibling schemas dir
 	baseDir := filepath.Dir(apiDefPath)
 	searchDirs := schemaSearchDirs(baseDir)
-	resolvedTypes, err := resolver.ParseFBSFiles(searchDirs, def.FlatBuffers)
+	fbsSet, err := resolver.LoadFBSFiles(searchDirs, genIncludes, def.FlatBuffers)
 	if err != nil {
-		return fmt.Errorf("parsing FlatBuffers schemas: %w", err)
+		return nil, fail("flatbuffers", fmt.Errorf("parsing FlatBuffers schemas: %w", err))
 	}
+	resolvedTypes := fbsSet.Types
 
 	// Semantic validation
 	result := validate.Validate(def, resolvedTypes, apiDefPath, srcMap)
 	if !result.IsValid() {
-		return fmt.Errorf("validation failed:\n%s", result.Error())
+		return result, fmt.Errorf("validation failed:\n%s", result.Error())
+	}
+	validate.CheckSchemaUse(result, def, fbsSet)
+	validate.CheckReservedWords(result, def, gen.ReservedIn)
+
+	// Create generation context
+	ctx := gen.NewContext(def, resolvedTypes, genOutput, apiDefPath)
+	ctx.Version = Version
+	ctx.Verbose = verbose
+	ctx.DryRun = genDryRun
+
+	// Check the C ABI against the lock file before writing anything
+	lockPath := filepath.Join(baseDir, gen.ABILockFile)
+	lock, err := checkABILock(result, lockPath, gen.BuildABILock(ctx))
+	if err != nil {
+		return result, err
 	}
+	printWarnings(result)
 
 	// Clean output directory if requested
 	if genClean {
@@ -90,40 +124,27 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	if !genSkipFlatc && len(def.FlatBuffers) > 0 {
 		flatcPath, err := resolver.ResolveFlatc(genFlatc)
 		if err != nil {
-			return fmt.Errorf("flatc is required but not found: %w\n\nProvide flatc via --flatc flag, XPLATTER_FLATC_PATH env var, or ensure it is in PATH.\nUse --skip-flatc to skip FlatBuffers codegen (generated bindings will be incomplete).", err)
-		}
-
-		// Resolve absolute paths for .fbs files using same search dirs
-		fbsFiles := make([]string, len(def.FlatBuffers))
-		for i, p := range def.FlatBuffers {
-			resolved, err := resolver.ResolveFBSPath(p, searchDirs)
-			if err != nil {
-				return fmt.Errorf("resolving %s for flatc: %w", p, err)
-			}
-			fbsFiles[i] = resolved
+			return result, fail("flatc", fmt.Errorf("flatc is required but not found: %w\n\nProvide flatc via --flatc flag, XPLATTER_FLATC_PATH env var, or ensure it is in PATH.\nUse --skip-flatc to skip FlatBuffers codegen (generated bindings will be incomplete).", err))
 		}
 
+		// Generate code for included schemas too, since bindings reference their types
 		flatcCount, err = gen.RunFlatc(&gen.FlatcConfig{
-			FlatcPath: flatcPath,
-			FBSFiles:  fbsFiles,
-			OutputDir: genOutput,
-			Targets:   def.EffectiveTargets(),
-			ImplLang:  def.API.ImplLang,
-			DryRun:    genDryRun,
-			Verbose:   verbose,
-			Quiet:     quiet,
+			FlatcPath:   flatcPath,
+			FBSFiles:    fbsSet.Files,
+			IncludeDirs: append(append([]string{}, genIncludes...), searchDirs...),
+			OutputDir:   genOutput,
+			Targets:     def.EffectiveTargets(),
+			ImplLang:    def.API.ImplLang,
+			GoModule:    gen.GoFlatcModule(def.API.Name),
+			DryRun:      genDryRun,
+			Verbose:     verbose,
+			Quiet:       quiet,
 		})
 		if err != nil {
-			return fmt.Errorf("flatc: %w", err)
+			return result, fail("flatc", fmt.Errorf("flatc: %w", err))
 		}
 	}
 
-	// Create generation context
-	ctx := gen.NewContext(def, resolvedTypes, genOutput, apiDefPath)
-	ctx.Version = Version
-	ctx.Verbose = verbose
-	ctx.DryRun = genDryRun
-
 	// Determine which generators to run
 	generatorNames := []string{"cheader"} // Always generate C header
 
@@ -158,7 +179,7 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 
 		files, err := g.Generate(ctx)
 		if err != nil {
-			return fmt.Errorf("generator %s failed: %w", name, err)
+			return result, fail("generator", fmt.Errorf("generator %s failed: %w", name, err))
 		}
 		allFiles = append(allFiles, files...)
 	}
@@ -184,15 +205,17 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		}
 
 		if genDryRun {
-			fmt.Printf("  Would write: %s\n", outPath)
+			if genFormat == "text" {
+				fmt.Printf("  Would write: %s\n", outPath)
+			}
 			continue
 		}
 
 		if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
-			return fmt.Errorf("creating directory for %s: %w", outPath, err)
+			return result, fail("write", fmt.Errorf("creating directory for %s: %w", outPath, err))
 		}
 		if err := os.WriteFile(outPath, f.Content, 0644); err != nil {
-			return fmt.Errorf("writing %s: %w", outPath, err)
+			return result, fail("write", fmt.Errorf("writing %s: %w", outPath, err))
 		}
 
 		written++
@@ -201,6 +224,12 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if lock != nil {
+		if err := writeABILock(lockPath, lock); err != nil {
+			return result, err
+		}
+	}
+
 	if !quiet {
 		skippedMsg := ""
 		if skipped > 0 {
@@ -212,7 +241,7 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 			fmt.Printf("Generated %d files in %s%s\n", written, genOutput, skippedMsg)
 		}
 	}
-	return nil
+	return result, nil
 }
 
 // schemaSearchDirs returns directories to search for .fbs files:
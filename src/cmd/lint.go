@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/benn-herrera/xplatter/lint"
+	"github.com/benn-herrera/xplatter/loader"
+	"github.com/benn-herrera/xplatter/resolver"
+	"github.com/benn-herrera/xplatter/validate"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintConfig   string
+	lintFormat   string
+	lintOutput   string
+	lintIncludes []string
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [api-definition.yaml]",
+	Short: "Check a valid API definition for likely design problems",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runLint,
+}
+
+func init() {
+	lintCmd.Flags().StringVarP(&lintConfig, "config", "c", "", "Lint config file (default: "+lint.DefaultConfigFile+" next to the API definition, if present)")
+	lintCmd.Flags().StringVar(&lintFormat, "format", "text", "Output format: text, json or sarif")
+	lintCmd.Flags().StringVarP(&lintOutput, "output", "o", "", "Write findings to a file instead of stdout")
+	lintCmd.Flags().StringSliceVarP(&lintIncludes, "include-dir", "I", nil, "Directory to search for included .fbs files (repeatable)")
+	rootCmd.AddCommand(lintCmd)
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	apiDefPath := args[0]
+	baseDir := filepath.Dir(apiDefPath)
+
+	if !slices.Contains(lint.Formats, lintFormat) {
+		return fmt.Errorf("unknown format %q (want text, json or sarif)", lintFormat)
+	}
+	cfg, err := loadLintConfig(baseDir)
+	if err != nil {
+		return err
+	}
+
+	def, srcMap, err := loader.LoadAPIDefinition(apiDefPath)
+	if err != nil {
+		return fmt.Errorf("loading API definition: %w", err)
+	}
+	fbsSet, err := resolver.LoadFBSFiles(schemaSearchDirs(baseDir), lintIncludes, def.FlatBuffers)
+	if err != nil {
+		return fmt.Errorf("parsing FlatBuffers schemas: %w", err)
+	}
+
+	// Lint rules assume a valid definition.
+	result := validate.Validate(def, fbsSet.Types, apiDefPath, srcMap)
+	if !result.IsValid() {
+		return fmt.Errorf("semantic validation failed:\n%s", result.Error())
+	}
+
+	findings := lint.Run(def, fbsSet.Types, apiDefPath, srcMap, cfg)
+
+	out := os.Stdout
+	if lintOutput != "" {
+		f, err := os.Create(lintOutput)
+		if err != nil {
+			return fmt.Errorf("creating %s: %w", lintOutput, err)
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := lint.WriteReport(out, lintFormat, findings, cfg, Version); err != nil {
+		return err
+	}
+
+	errors := 0
+	for _, f := range findings {
+		if f.Severity == lint.SeverityError {
+			errors++
+		}
+	}
+	if errors > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("lint found %d error(s)", errors)
+	}
+	if !quiet && lintFormat == "text" && len(findings) == 0 {
+		fmt.Fprintln(out, "No lint findings.")
+	}
+	return nil
+}
+
+// loadLintConfig loads the --config file, else the default config file in
+// baseDir if there is one, else the default config.
+func loadLintConfig(baseDir string) (*lint.Config, error) {
+	if lintConfig != "" {
+		return lint.LoadConfig(lintConfig)
+	}
+	path := filepath.Join(baseDir, lint.DefaultConfigFile)
+	if _, err := os.Stat(path); err != nil {
+		return lint.DefaultConfig(), nil
+	}
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Using lint config %s\n", path)
+	}
+	return lint.LoadConfig(path)
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/benn-herrera/xplatter/gen"
+	"github.com/benn-herrera/xplatter/validate"
+)
+
+// checkABILock compares the current C ABI with the lock file at lockPath. It
+// returns the lock to write: current if the file does not exist yet, or if it
+// differs and --update-lock is set, else nil. With --locked, a missing or
+// differing lock file is an error; otherwise each difference is a warning.
+func checkABILock(result *validate.ValidationResult, lockPath string, current *gen.ABILock) (*gen.ABILock, error) {
+	data, err := os.ReadFile(lockPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		if genLocked {
+			return nil, fail("lock", fmt.Errorf("%s does not exist; run generate without --locked to create it", lockPath))
+		}
+		return current, nil
+	}
+	if err != nil {
+		return nil, fail("lock", fmt.Errorf("reading %s: %w", lockPath, err))
+	}
+	locked, err := gen.ParseABILock(data)
+	if err != nil {
+		return nil, fail("lock", fmt.Errorf("%s: %w", lockPath, err))
+	}
+
+	drift := locked.Drift(current)
+	switch {
+	case len(drift) == 0:
+		return nil, nil
+	case genUpdateLock:
+		if !quiet {
+			fmt.Printf("Updating %s (%d change(s)):\n", lockPath, len(drift))
+			for _, d := range drift {
+				fmt.Printf("  %s\n", d)
+			}
+		}
+		return current, nil
+	case genLocked:
+		var lines []string
+		for _, d := range drift {
+			lines = append(lines, "  "+d.String())
+		}
+		return nil, fail("lock", fmt.Errorf("C ABI no longer matches %s:\n%s\nRerun generate with --update-lock to accept the changes.", lockPath, strings.Join(lines, "\n")))
+	}
+	for _, d := range drift {
+		result.Warnings = append(result.Warnings, validate.ValidationError{
+			File:    lockPath,
+			Path:    d.Subject,
+			Rule:    "abi-drift",
+			Message: d.Change,
+			Fix:     "rerun generate with --update-lock to accept it",
+		})
+	}
+	return nil, nil
+}
+
+// writeABILock writes a lock file, or only reports it in a dry run.
+func writeABILock(lockPath string, lock *gen.ABILock) error {
+	if genDryRun {
+		if genFormat == "text" {
+			fmt.Printf("  Would write: %s\n", lockPath)
+		}
+		return nil
+	}
+	data, err := lock.Marshal()
+	if err != nil {
+		return fail("write", fmt.Errorf("encoding %s: %w", lockPath, err))
+	}
+	if err := os.WriteFile(lockPath, data, 0644); err != nil {
+		return fail("write", fmt.Errorf("writing %s: %w", lockPath, err))
+	}
+	if verbose {
+		fmt.Printf("  Wrote: %s\n", lockPath)
+	}
+	return nil
+}
@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 
+	"github.com/benn-herrera/xplatter/gen"
 	"github.com/benn-herrera/xplatter/loader"
 	"github.com/benn-herrera/xplatter/resolver"
 	"github.com/benn-herrera/xplatter/validate"
@@ -11,7 +13,9 @@ import (
 )
 
 var (
-	valFlatc string
+	valFlatc    string
+	valIncludes []string
+	valFormat   string
 )
 
 var validateCmd = &cobra.Command{
@@ -23,12 +27,22 @@ var validateCmd = &cobra.Command{
 
 func init() {
 	validateCmd.Flags().StringVarP(&valFlatc, "flatc", "f", "", "Path to FlatBuffers compiler")
+	validateCmd.Flags().StringSliceVarP(&valIncludes, "include-dir", "I", nil, "Directory to search for included .fbs files (repeatable)")
+	validateCmd.Flags().StringVar(&valFormat, "format", "text", "Diagnostics format: text or json")
 	rootCmd.AddCommand(validateCmd)
 }
 
 func runValidate(cmd *cobra.Command, args []string) error {
-	apiDefPath := args[0]
+	if err := checkFormat(valFormat); err != nil {
+		return err
+	}
+	result, err := validateAPI(args[0])
+	return reportDiagnostics(cmd, valFormat, args[0], result, err)
+}
 
+// validateAPI loads and validates an API definition. The result is nil if
+// loading failed.
+func validateAPI(apiDefPath string) (*validate.ValidationResult, error) {
 	if !quiet {
 		fmt.Printf("Validating %s\n", apiDefPath)
 	}
@@ -36,7 +50,7 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	// Load and schema-validate the API definition
 	def, srcMap, err := loader.LoadAPIDefinition(apiDefPath)
 	if err != nil {
-		return fmt.Errorf("loading API definition: %w", err)
+		return nil, fail("load", fmt.Errorf("loading API definition: %w", err))
 	}
 
 	if verbose {
@@ -49,23 +63,38 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	// Resolve FlatBuffers types — search YAML dir first, then exe-sibling schemas dir
 	baseDir := filepath.Dir(apiDefPath)
 	searchDirs := schemaSearchDirs(baseDir)
-	resolvedTypes, err := resolver.ParseFBSFiles(searchDirs, def.FlatBuffers)
+	fbsSet, err := resolver.LoadFBSFiles(searchDirs, valIncludes, def.FlatBuffers)
 	if err != nil {
-		return fmt.Errorf("parsing FlatBuffers schemas: %w", err)
+		return nil, fail("flatbuffers", fmt.Errorf("parsing FlatBuffers schemas: %w", err))
 	}
+	resolvedTypes := fbsSet.Types
 
 	if verbose {
+		fmt.Printf("  Parsed schema files: %d (including includes)\n", len(fbsSet.Files))
 		fmt.Printf("  Resolved types: %d\n", len(resolvedTypes))
 	}
 
 	// Run semantic validation
 	result := validate.Validate(def, resolvedTypes, apiDefPath, srcMap)
 	if !result.IsValid() {
-		return fmt.Errorf("semantic validation failed:\n%s", result.Error())
+		return result, fmt.Errorf("semantic validation failed:\n%s", result.Error())
 	}
+	validate.CheckSchemaUse(result, def, fbsSet)
+	validate.CheckReservedWords(result, def, gen.ReservedIn)
+	printWarnings(result)
 
 	if !quiet {
 		fmt.Println("Validation passed.")
 	}
-	return nil
+	return result, nil
+}
+
+// printWarnings reports validation warnings on stderr unless --quiet is set.
+func printWarnings(result *validate.ValidationResult) {
+	if quiet {
+		return
+	}
+	for _, w := range result.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w.Error())
+	}
 }
@@ -0,0 +1,423 @@
+// Package diff compares two versions of an API definition, with the
+// FlatBuffers types each uses, and classifies every difference by what it
+// does to existing callers: compatible, an addition, or a break of the C ABI
+// or of the source API of the bindings. It then checks that the version bump
+// between the two is large enough for the changes.
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/benn-herrera/xplatter/gen"
+	"github.com/benn-herrera/xplatter/model"
+	"github.com/benn-herrera/xplatter/resolver"
+)
+
+// Level is how a change affects existing callers.
+type Level int
+
+const (
+	LevelCompatible Level = iota // existing callers are unaffected
+	LevelAddition                // new API surface
+	LevelBreaking                // existing callers must change or rebuild
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelAddition:
+		return "addition"
+	case LevelBreaking:
+		return "breaking"
+	default:
+		return "compatible"
+	}
+}
+
+// Change is a single difference between two definitions.
+type Change struct {
+	Level        Level
+	Category     string // e.g., "removed", "parameter-type", "struct-layout"
+	Element      string // e.g., "method", "parameter", "enum value"
+	Name         string // qualified name, e.g., "renderer.draw.color"
+	Message      string // what changed, e.g., "type changed from int32 to int64"
+	ABI          bool   // a breaking change that breaks binaries built against the old C ABI, not only source
+	Experimental bool   // the element was experimental, so it may break without a major bump
+}
+
+func (c *Change) String() string {
+	level := c.Level.String()
+	if c.Level == LevelBreaking {
+		if c.ABI {
+			level += " (ABI)"
+		} else {
+			level += " (API)"
+		}
+	}
+	s := fmt.Sprintf("%s: %s %s: %s [%s]", level, c.Element, c.Name, c.Message, c.Category)
+	if c.Experimental {
+		s += " (experimental)"
+	}
+	return s
+}
+
+// Definition is an API definition with the FlatBuffers types it was
+// resolved against.
+type Definition struct {
+	API   *model.APIDefinition
+	Types resolver.ResolvedTypes
+}
+
+// Report is the result of comparing two definitions.
+type Report struct {
+	OldVersion string
+	NewVersion string
+	Changes    []Change
+	Required   Bump  // the smallest version bump the changes need
+	Actual     Bump  // the bump from OldVersion to NewVersion
+	VersionErr error // set if the versions do not parse or the version went down
+}
+
+// VersionOK reports whether the version bump is at least the one the changes
+// need.
+func (r *Report) VersionOK() bool {
+	return r.VersionErr == nil && r.Actual >= r.Required
+}
+
+// Count returns the number of changes at level.
+func (r *Report) Count(level Level) int {
+	n := 0
+	for _, c := range r.Changes {
+		if c.Level == level {
+			n++
+		}
+	}
+	return n
+}
+
+// differ accumulates the changes of one Compare.
+type differ struct {
+	old, new Definition
+	changes  []Change
+	// experimental is set while comparing the members of an element that was
+	// experimental in the old definition.
+	experimental bool
+}
+
+// Compare classifies every difference from old to new, then checks the
+// version bump.
+func Compare(old, new Definition) *Report {
+	d := &differ{old: old, new: new}
+	d.compareAPI()
+	d.compareTypes()
+
+	r := &Report{
+		OldVersion: old.API.API.Version,
+		NewVersion: new.API.API.Version,
+		Changes:    d.changes,
+	}
+	r.Actual, r.VersionErr = versionBump(r.OldVersion, r.NewVersion)
+	r.Required = requiredBump(r.OldVersion, r.Changes)
+	return r
+}
+
+func (d *differ) add(level Level, category, element, name string, abi bool, format string, args ...any) {
+	d.changes = append(d.changes, Change{
+		Level:        level,
+		Category:     category,
+		Element:      element,
+		Name:         name,
+		Message:      fmt.Sprintf(format, args...),
+		ABI:          level == LevelBreaking && abi,
+		Experimental: d.experimental,
+	})
+}
+
+func (d *differ) added(element, name string) {
+	d.add(LevelAddition, "added", element, name, false, "added")
+}
+
+func (d *differ) removed(element, name string) {
+	d.add(LevelBreaking, "removed", element, name, true, "removed")
+}
+
+func (d *differ) compareAPI() {
+	oldAPI, newAPI := d.old.API, d.new.API
+	if oldAPI.API.Name != newAPI.API.Name {
+		d.add(LevelBreaking, "renamed", "api", oldAPI.API.Name, true, "renamed to %s, which renames every C symbol", newAPI.API.Name)
+	}
+
+	newHandles := make(map[string]*model.HandleDef)
+	for i := range newAPI.Handles {
+		newHandles[newAPI.Handles[i].Name] = &newAPI.Handles[i]
+	}
+	for i := range oldAPI.Handles {
+		oh := &oldAPI.Handles[i]
+		d.experimental = oh.IsExperimental()
+		nh, ok := newHandles[oh.Name]
+		if !ok {
+			d.removed("handle", oh.Name)
+			continue
+		}
+		d.compareThreadAffinity("handle", oh.Name, oh.ThreadAffinity, nh.ThreadAffinity)
+		d.compareDeprecation("handle", oh.Name, &oh.Lifecycle, &nh.Lifecycle)
+	}
+	d.experimental = false
+	for _, nh := range newAPI.Handles {
+		if oldAPI.HandleByName(nh.Name) == nil {
+			d.added("handle", nh.Name)
+		}
+	}
+
+	for i := range oldAPI.Interfaces {
+		oi := &oldAPI.Interfaces[i]
+		d.experimental = oi.IsExperimental()
+		ni := newAPI.InterfaceByName(oi.Name)
+		if ni == nil {
+			d.removed("interface", oi.Name)
+			continue
+		}
+		d.compareInterface(oi, ni)
+	}
+	d.experimental = false
+	for _, ni := range newAPI.Interfaces {
+		if oldAPI.InterfaceByName(ni.Name) == nil {
+			d.added("interface", ni.Name)
+		}
+	}
+
+	d.compareEvents()
+}
+
+func (d *differ) compareInterface(oi, ni *model.InterfaceDef) {
+	if oi.Extends != ni.Extends {
+		d.add(LevelBreaking, "extends", "interface", oi.Name, false, "base interface changed from %s to %s", orNone(oi.Extends), orNone(ni.Extends))
+	}
+	d.compareThreadAffinity("interface", oi.Name, oi.ThreadAffinity, ni.ThreadAffinity)
+	d.compareDeprecation("interface", oi.Name, &oi.Lifecycle, &ni.Lifecycle)
+	d.compareMethods("constructor", oi.Name, oi.Constructors, ni.Constructors)
+	d.compareMethods("method", oi.Name, oi.Methods, ni.Methods)
+}
+
+// compareMethods compares the constructors or methods of an interface by
+// name. A method removed while another with the same signature was added is
+// reported as renamed.
+func (d *differ) compareMethods(element, iface string, oldMethods, newMethods []model.MethodDef) {
+	oldByName := make(map[string]bool)
+	for _, m := range oldMethods {
+		oldByName[m.Name] = true
+	}
+	newByName := make(map[string]*model.MethodDef)
+	var addedMethods []*model.MethodDef
+	for i := range newMethods {
+		m := &newMethods[i]
+		newByName[m.Name] = m
+		if !oldByName[m.Name] {
+			addedMethods = append(addedMethods, m)
+		}
+	}
+
+	outer := d.experimental
+	renamedTo := make(map[string]bool)
+	for i := range oldMethods {
+		om := &oldMethods[i]
+		d.experimental = outer || om.IsExperimental()
+		name := iface + "." + om.Name
+		nm, ok := newByName[om.Name]
+		if ok {
+			d.compareMethod(element, name, om, nm)
+			continue
+		}
+		if to := renameTarget(om, addedMethods, renamedTo); to != nil {
+			renamedTo[to.Name] = true
+			d.add(LevelBreaking, "renamed", element, name, true, "renamed to %s", to.Name)
+			continue
+		}
+		d.removed(element, name)
+	}
+	d.experimental = outer
+	for _, m := range addedMethods {
+		if !renamedTo[m.Name] {
+			d.added(element, iface+"."+m.Name)
+		}
+	}
+}
+
+// renameTarget returns the added method with the same signature as a removed
+// one, if exactly one has it and it is not taken.
+func renameTarget(removed *model.MethodDef, added []*model.MethodDef, taken map[string]bool) *model.MethodDef {
+	var match *model.MethodDef
+	sig := signature(removed)
+	for _, m := range added {
+		if taken[m.Name] || signature(m) != sig {
+			continue
+		}
+		if match != nil {
+			return nil
+		}
+		match = m
+	}
+	return match
+}
+
+// signature describes the C ABI of a method, leaving out its name.
+func signature(m *model.MethodDef) string {
+	var sb strings.Builder
+	for _, p := range m.Parameters {
+		fmt.Fprintf(&sb, "%s/%s/%t,", p.Type, transfer(&p), p.Optional)
+	}
+	if m.Returns != nil {
+		fmt.Fprintf(&sb, "->%s/%t/%t", m.Returns.Type, m.Returns.Optional, m.Returns.Borrowed)
+	}
+	fmt.Fprintf(&sb, "!%s async=%t", m.Error, m.Async)
+	return sb.String()
+}
+
+func (d *differ) compareMethod(element, name string, om, nm *model.MethodDef) {
+	if om.Async != nm.Async {
+		d.add(LevelBreaking, "signature", element, name, true, "async changed from %t to %t", om.Async, nm.Async)
+	}
+	if len(om.Parameters) != len(nm.Parameters) {
+		d.add(LevelBreaking, "signature", element, name, true, "parameters changed from (%s) to (%s)", paramList(om.Parameters), paramList(nm.Parameters))
+	} else {
+		for k := range om.Parameters {
+			d.compareParam(name, k, &om.Parameters[k], &nm.Parameters[k])
+		}
+	}
+	d.compareReturn(element, name, om.Returns, nm.Returns)
+	if om.Error != nm.Error {
+		d.add(LevelBreaking, "error-type", element, name, true, "error type changed from %s to %s", orNone(om.Error), orNone(nm.Error))
+	}
+	d.compareThreadAffinity(element, name, om.ThreadAffinity, nm.ThreadAffinity)
+	d.compareDeprecation(element, name, &om.Lifecycle, &nm.Lifecycle)
+}
+
+// compareParam compares the parameters at the same position, which is what
+// the C ABI binds by.
+func (d *differ) compareParam(method string, k int, op, np *model.ParameterDef) {
+	name := method + "." + op.Name
+	if op.Name != np.Name {
+		// Kotlin and Swift callers name their arguments.
+		d.add(LevelBreaking, "renamed", "parameter", name, false, "renamed to %s", np.Name)
+	}
+	if op.Type != np.Type {
+		d.add(LevelBreaking, "parameter-type", "parameter", name, true, "type changed from %s to %s", op.Type, np.Type)
+	}
+	if ot, nt := transfer(op), transfer(np); ot != nt {
+		d.add(LevelBreaking, "parameter-transfer", "parameter", name, true, "transfer changed from %s to %s", ot, nt)
+	}
+	if op.Optional != np.Optional {
+		d.add(LevelBreaking, "parameter-type", "parameter", name, true, "optional changed from %t to %t", op.Optional, np.Optional)
+	}
+}
+
+func (d *differ) compareReturn(element, name string, or, nr *model.ReturnDef) {
+	switch {
+	case or == nil && nr == nil:
+	case or == nil || nr == nil || or.Type != nr.Type:
+		d.add(LevelBreaking, "return-type", element, name, true, "return type changed from %s to %s", returnType(or), returnType(nr))
+	default:
+		if or.Optional != nr.Optional {
+			d.add(LevelBreaking, "return-type", element, name, true, "optional return changed from %t to %t", or.Optional, nr.Optional)
+		}
+		if or.Borrowed != nr.Borrowed {
+			// The owner of the returned handle changes.
+			d.add(LevelBreaking, "return-type", element, name, true, "borrowed return changed from %t to %t", or.Borrowed, nr.Borrowed)
+		}
+	}
+}
+
+// compareThreadAffinity reports a change that restricts the threads callers
+// may use as breaking, and one that lifts a restriction as compatible.
+func (d *differ) compareThreadAffinity(element, name, oldAffinity, newAffinity string) {
+	oldAffinity, newAffinity = orAny(oldAffinity), orAny(newAffinity)
+	if oldAffinity == newAffinity {
+		return
+	}
+	level := LevelBreaking
+	if newAffinity == model.ThreadAffinityAny {
+		level = LevelCompatible
+	}
+	d.add(level, "thread-affinity", element, name, false, "thread affinity changed from %s to %s", oldAffinity, newAffinity)
+}
+
+func (d *differ) compareDeprecation(element, name string, ol, nl *model.Lifecycle) {
+	if ol.Deprecated == nil && nl.Deprecated != nil {
+		d.add(LevelCompatible, "deprecated", element, name, false, "deprecated")
+	}
+}
+
+func (d *differ) compareEvents() {
+	newEvents := make(map[string]*model.EventDef)
+	for i := range d.new.API.Events {
+		newEvents[d.new.API.Events[i].Name] = &d.new.API.Events[i]
+	}
+	newKinds := make(map[string]int)
+	for i, ne := range d.new.API.Events {
+		newKinds[ne.Name] = gen.EventKindValue(i)
+	}
+	oldEvents := make(map[string]bool)
+	for i, oe := range d.old.API.Events {
+		oldEvents[oe.Name] = true
+		ne, ok := newEvents[oe.Name]
+		if !ok {
+			d.removed("event", oe.Name)
+			continue
+		}
+		if oe.Type != ne.Type {
+			d.add(LevelBreaking, "event-type", "event", oe.Name, true, "payload type changed from %s to %s", oe.Type, ne.Type)
+		}
+		// Kind values follow declaration order, so inserting or removing an
+		// event before this one renumbers it.
+		if oldKind, newKind := gen.EventKindValue(i), newKinds[oe.Name]; oldKind != newKind {
+			d.add(LevelBreaking, "event-kind", "event", oe.Name, true, "kind value changed from %d to %d", oldKind, newKind)
+		}
+	}
+	for _, ne := range d.new.API.Events {
+		if !oldEvents[ne.Name] {
+			d.added("event", ne.Name)
+		}
+	}
+}
+
+func paramList(params []model.ParameterDef) string {
+	var parts []string
+	for _, p := range params {
+		parts = append(parts, p.Name+": "+p.Type)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func returnType(r *model.ReturnDef) string {
+	if r == nil {
+		return "none"
+	}
+	return r.Type
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+// transfer returns the transfer of a parameter, filling in the default:
+// strings are passed by reference and everything else by value.
+func transfer(p *model.ParameterDef) string {
+	switch {
+	case p.Transfer != "":
+		return p.Transfer
+	case model.IsString(p.Type):
+		return "ref"
+	default:
+		return "value"
+	}
+}
+
+func orAny(affinity string) string {
+	if affinity == "" {
+		return model.ThreadAffinityAny
+	}
+	return affinity
+}
@@ -0,0 +1,290 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/benn-herrera/xplatter/model"
+	"github.com/benn-herrera/xplatter/resolver"
+)
+
+func baseDefinition() Definition {
+	return Definition{
+		API: &model.APIDefinition{
+			API: model.APIMetadata{
+				Name:     "test_api",
+				Version:  "1.0.0",
+				ImplLang: "c",
+			},
+			Handles: []model.HandleDef{
+				{Name: "Engine"},
+			},
+			Interfaces: []model.InterfaceDef{
+				{
+					Name: "lifecycle",
+					Methods: []model.MethodDef{
+						{
+							Name:    "create_engine",
+							Returns: &model.ReturnDef{Type: "handle:Engine"},
+							Error:   "Common.ErrorCode",
+						},
+						{
+							Name: "load",
+							Parameters: []model.ParameterDef{
+								{Name: "engine", Type: "handle:Engine"},
+								{Name: "path", Type: "string"},
+								{Name: "config", Type: "Common.Config"},
+							},
+							Error: "Common.ErrorCode",
+						},
+					},
+				},
+			},
+			Events: []model.EventDef{
+				{Name: "on_ready", Type: "Common.Config"},
+			},
+		},
+		Types: resolver.ResolvedTypes{
+			"Common.ErrorCode": &resolver.TypeInfo{
+				Kind:     resolver.TypeKindEnum,
+				BaseType: "int32",
+				EnumValues: []resolver.EnumValue{
+					{Name: "Ok", Value: 0},
+					{Name: "NotFound", Value: 1},
+				},
+			},
+			"Common.Config": &resolver.TypeInfo{
+				Kind: resolver.TypeKindTable,
+				Fields: []resolver.FieldDef{
+					{Name: "width", Type: "uint32"},
+					{Name: "height", Type: "uint32"},
+				},
+			},
+		},
+	}
+}
+
+// findChange returns the change to name in category, or nil.
+func findChange(r *Report, category, name string) *Change {
+	for i := range r.Changes {
+		if r.Changes[i].Category == category && r.Changes[i].Name == name {
+			return &r.Changes[i]
+		}
+	}
+	return nil
+}
+
+func expectChange(t *testing.T, r *Report, category, name string, level Level, abi bool) *Change {
+	t.Helper()
+	c := findChange(r, category, name)
+	if c == nil {
+		var got []string
+		for _, c := range r.Changes {
+			got = append(got, c.String())
+		}
+		t.Fatalf("expected %s change to %s, got:\n%s", category, name, strings.Join(got, "\n"))
+	}
+	if c.Level != level || c.ABI != abi {
+		t.Errorf("%s: expected %s (ABI %t), got %s (ABI %t)", name, level, abi, c.Level, c.ABI)
+	}
+	return c
+}
+
+func TestCompare_NoChanges(t *testing.T) {
+	r := Compare(baseDefinition(), baseDefinition())
+	if len(r.Changes) != 0 {
+		t.Errorf("expected no changes, got %v", r.Changes)
+	}
+	if r.Required != BumpNone || !r.VersionOK() {
+		t.Errorf("expected no bump needed, got %s", r.Required)
+	}
+}
+
+func TestCompare_MethodAdded(t *testing.T) {
+	newDef := baseDefinition()
+	newDef.API.Interfaces[0].Methods = append(newDef.API.Interfaces[0].Methods, model.MethodDef{
+		Name: "reset",
+		Parameters: []model.ParameterDef{
+			{Name: "engine", Type: "handle:Engine"},
+		},
+	})
+	r := Compare(baseDefinition(), newDef)
+	expectChange(t, r, "added", "lifecycle.reset", LevelAddition, false)
+	if r.Required != BumpMinor {
+		t.Errorf("expected minor bump, got %s", r.Required)
+	}
+}
+
+func TestCompare_MethodRemoved(t *testing.T) {
+	newDef := baseDefinition()
+	newDef.API.Interfaces[0].Methods = newDef.API.Interfaces[0].Methods[:1]
+	r := Compare(baseDefinition(), newDef)
+	expectChange(t, r, "removed", "lifecycle.load", LevelBreaking, true)
+	if r.Required != BumpMajor {
+		t.Errorf("expected major bump, got %s", r.Required)
+	}
+}
+
+func TestCompare_MethodRenamed(t *testing.T) {
+	newDef := baseDefinition()
+	newDef.API.Interfaces[0].Methods[1].Name = "load_file"
+	r := Compare(baseDefinition(), newDef)
+	c := expectChange(t, r, "renamed", "lifecycle.load", LevelBreaking, true)
+	if c.Message != "renamed to load_file" {
+		t.Errorf("unexpected message %q", c.Message)
+	}
+	if findChange(r, "added", "lifecycle.load_file") != nil {
+		t.Error("renamed method should not also be reported as added")
+	}
+}
+
+func TestCompare_MethodReplacedWithDifferentSignature(t *testing.T) {
+	newDef := baseDefinition()
+	m := &newDef.API.Interfaces[0].Methods[1]
+	m.Name = "load_file"
+	m.Parameters = m.Parameters[:2]
+	r := Compare(baseDefinition(), newDef)
+	expectChange(t, r, "removed", "lifecycle.load", LevelBreaking, true)
+	expectChange(t, r, "added", "lifecycle.load_file", LevelAddition, false)
+}
+
+func TestCompare_ParameterChanges(t *testing.T) {
+	newDef := baseDefinition()
+	params := newDef.API.Interfaces[0].Methods[1].Parameters
+	params[0].Name = "e"
+	params[1].Transfer = "value"
+	params[2].Type = "Common.Other"
+	r := Compare(baseDefinition(), newDef)
+	expectChange(t, r, "renamed", "lifecycle.load.engine", LevelBreaking, false)
+	c := expectChange(t, r, "parameter-transfer", "lifecycle.load.path", LevelBreaking, true)
+	if c.Message != "transfer changed from ref to value" {
+		t.Errorf("unexpected message %q", c.Message)
+	}
+	expectChange(t, r, "parameter-type", "lifecycle.load.config", LevelBreaking, true)
+}
+
+func TestCompare_DefaultTransferUnchanged(t *testing.T) {
+	newDef := baseDefinition()
+	newDef.API.Interfaces[0].Methods[1].Parameters[1].Transfer = "ref"
+	r := Compare(baseDefinition(), newDef)
+	if len(r.Changes) != 0 {
+		t.Errorf("spelling out the default transfer should not be a change, got %v", r.Changes)
+	}
+}
+
+func TestCompare_ParameterAdded(t *testing.T) {
+	newDef := baseDefinition()
+	m := &newDef.API.Interfaces[0].Methods[1]
+	m.Parameters = append(m.Parameters, model.ParameterDef{Name: "flags", Type: "uint32"})
+	r := Compare(baseDefinition(), newDef)
+	expectChange(t, r, "signature", "lifecycle.load", LevelBreaking, true)
+}
+
+func TestCompare_ReturnAndErrorChanges(t *testing.T) {
+	newDef := baseDefinition()
+	methods := newDef.API.Interfaces[0].Methods
+	methods[0].Returns.Borrowed = true
+	methods[1].Error = ""
+	r := Compare(baseDefinition(), newDef)
+	expectChange(t, r, "return-type", "lifecycle.create_engine", LevelBreaking, true)
+	expectChange(t, r, "error-type", "lifecycle.load", LevelBreaking, true)
+}
+
+func TestCompare_ThreadAffinity(t *testing.T) {
+	oldDef := baseDefinition()
+	oldDef.API.Handles[0].ThreadAffinity = model.ThreadAffinityMain
+	newDef := baseDefinition()
+	newDef.API.Interfaces[0].ThreadAffinity = model.ThreadAffinityMain
+	r := Compare(oldDef, newDef)
+	expectChange(t, r, "thread-affinity", "Engine", LevelCompatible, false)
+	expectChange(t, r, "thread-affinity", "lifecycle", LevelBreaking, false)
+}
+
+func TestCompare_Deprecated(t *testing.T) {
+	newDef := baseDefinition()
+	newDef.API.Interfaces[0].Methods[1].Deprecated = &model.Deprecation{Message: "use load_file"}
+	r := Compare(baseDefinition(), newDef)
+	expectChange(t, r, "deprecated", "lifecycle.load", LevelCompatible, false)
+	if r.Required != BumpMinor {
+		t.Errorf("expected minor bump for a deprecation, got %s", r.Required)
+	}
+}
+
+func TestCompare_Experimental(t *testing.T) {
+	oldDef := baseDefinition()
+	oldDef.API.Interfaces[0].Stability = model.StabilityExperimental
+	newDef := baseDefinition()
+	newDef.API.Interfaces[0].Methods = newDef.API.Interfaces[0].Methods[:1]
+	newDef.API.Handles = append(newDef.API.Handles, model.HandleDef{Name: "Texture"})
+	r := Compare(oldDef, newDef)
+	c := expectChange(t, r, "removed", "lifecycle.load", LevelBreaking, true)
+	if !c.Experimental {
+		t.Error("expected change to be marked experimental")
+	}
+	if c := expectChange(t, r, "added", "Texture", LevelAddition, false); c.Experimental {
+		t.Error("handle added outside the experimental interface was marked experimental")
+	}
+	if r.Required != BumpMinor {
+		t.Errorf("expected minor bump for an experimental break, got %s", r.Required)
+	}
+}
+
+func TestCompare_APIRenamed(t *testing.T) {
+	newDef := baseDefinition()
+	newDef.API.API.Name = "other_api"
+	r := Compare(baseDefinition(), newDef)
+	expectChange(t, r, "renamed", "test_api", LevelBreaking, true)
+}
+
+func TestCompare_Events(t *testing.T) {
+	newDef := baseDefinition()
+	newDef.API.Events[0].Type = "Common.ErrorCode"
+	newDef.API.Events = append(newDef.API.Events, model.EventDef{Name: "on_exit", Type: "Common.ErrorCode"})
+	r := Compare(baseDefinition(), newDef)
+	expectChange(t, r, "event-type", "on_ready", LevelBreaking, true)
+	expectChange(t, r, "added", "on_exit", LevelAddition, false)
+	if c := findChange(r, "event-kind", "on_ready"); c != nil {
+		t.Errorf("appending an event renumbered on_ready: %v", c)
+	}
+}
+
+func TestCompare_EventInsertedBefore(t *testing.T) {
+	// Old [a], new [b, a]: a's kind value moves from 1 to 2.
+	oldDef := baseDefinition()
+	newDef := baseDefinition()
+	newDef.API.Events = append([]model.EventDef{{Name: "on_start", Type: "Common.ErrorCode"}}, newDef.API.Events...)
+	r := Compare(oldDef, newDef)
+	expectChange(t, r, "added", "on_start", LevelAddition, false)
+	c := expectChange(t, r, "event-kind", "on_ready", LevelBreaking, true)
+	if c.Message != "kind value changed from 1 to 2" {
+		t.Errorf("message = %q", c.Message)
+	}
+	if r.Required != BumpMajor {
+		t.Errorf("expected a major bump, got %s", r.Required)
+	}
+}
+
+func TestChange_String(t *testing.T) {
+	tests := []struct {
+		change Change
+		want   string
+	}{
+		{
+			Change{Level: LevelBreaking, Category: "removed", Element: "method", Name: "r.draw", Message: "removed", ABI: true},
+			"breaking (ABI): method r.draw: removed [removed]",
+		},
+		{
+			Change{Level: LevelBreaking, Category: "renamed", Element: "parameter", Name: "r.draw.c", Message: "renamed to color", Experimental: true},
+			"breaking (API): parameter r.draw.c: renamed to color [renamed] (experimental)",
+		},
+		{
+			Change{Level: LevelAddition, Category: "added", Element: "event", Name: "on_exit", Message: "added"},
+			"addition: event on_exit: added [added]",
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.change.String(); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Formats lists the output formats WriteReport accepts.
+var Formats = []string{"text", "json"}
+
+// WriteReport writes a report to w in format.
+func WriteReport(w io.Writer, format string, r *Report) error {
+	switch format {
+	case "text":
+		return WriteText(w, r)
+	case "json":
+		return WriteJSON(w, r)
+	}
+	return fmt.Errorf("unknown format %q (want text or json)", format)
+}
+
+// VersionSummary describes the version bump and whether it covers the
+// changes.
+func (r *Report) VersionSummary() string {
+	if r.VersionErr != nil {
+		return r.VersionErr.Error()
+	}
+	bump := fmt.Sprintf("version %s -> %s is a %s bump", r.OldVersion, r.NewVersion, r.Actual)
+	if r.Actual == BumpNone {
+		bump = fmt.Sprintf("version %s is unchanged", r.OldVersion)
+	}
+	switch {
+	case r.Required == BumpNone:
+		return bump + "; the changes need none"
+	case r.VersionOK():
+		return fmt.Sprintf("%s; the changes need a %s bump", bump, r.Required)
+	}
+	return fmt.Sprintf("%s, but the changes need a %s bump: %s", bump, r.Required, nextVersion(r.OldVersion, r.Required))
+}
+
+// WriteText writes one line per change, then a summary and the version check.
+func WriteText(w io.Writer, r *Report) error {
+	for i := range r.Changes {
+		if _, err := fmt.Fprintln(w, r.Changes[i].String()); err != nil {
+			return err
+		}
+	}
+	if len(r.Changes) == 0 {
+		if _, err := fmt.Fprintln(w, "No changes."); err != nil {
+			return err
+		}
+	} else {
+		_, err := fmt.Fprintf(w, "%d breaking, %d addition(s), %d compatible\n",
+			r.Count(LevelBreaking), r.Count(LevelAddition), r.Count(LevelCompatible))
+		if err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, r.VersionSummary())
+	return err
+}
+
+// jsonChange is the JSON form of a Change.
+type jsonChange struct {
+	Level        string `json:"level"`
+	Category     string `json:"category"`
+	Element      string `json:"element"`
+	Name         string `json:"name"`
+	Message      string `json:"message"`
+	ABI          bool   `json:"abi"`
+	Experimental bool   `json:"experimental,omitempty"`
+}
+
+// jsonReport is the JSON form of a Report.
+type jsonReport struct {
+	OldVersion   string       `json:"old_version"`
+	NewVersion   string       `json:"new_version"`
+	Bump         string       `json:"bump"`
+	RequiredBump string       `json:"required_bump"`
+	VersionOK    bool         `json:"version_ok"`
+	Version      string       `json:"version"` // VersionSummary
+	Changes      []jsonChange `json:"changes"`
+}
+
+// WriteJSON writes a report as a JSON object.
+func WriteJSON(w io.Writer, r *Report) error {
+	out := jsonReport{
+		OldVersion:   r.OldVersion,
+		NewVersion:   r.NewVersion,
+		Bump:         r.Actual.String(),
+		RequiredBump: r.Required.String(),
+		VersionOK:    r.VersionOK(),
+		Version:      r.VersionSummary(),
+		Changes:      make([]jsonChange, 0, len(r.Changes)),
+	}
+	for _, c := range r.Changes {
+		out.Changes = append(out.Changes, jsonChange{
+			Level:        c.Level.String(),
+			Category:     c.Category,
+			Element:      c.Element,
+			Name:         c.Name,
+			Message:      c.Message,
+			ABI:          c.ABI,
+			Experimental: c.Experimental,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
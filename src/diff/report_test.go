@@ -0,0 +1,108 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testReport() *Report {
+	return &Report{
+		OldVersion: "1.2.0",
+		NewVersion: "1.3.0",
+		Changes: []Change{
+			{Level: LevelBreaking, Category: "removed", Element: "method", Name: "r.draw", Message: "removed", ABI: true},
+			{Level: LevelAddition, Category: "added", Element: "method", Name: "r.present", Message: "added"},
+		},
+		Required: BumpMajor,
+		Actual:   BumpMinor,
+	}
+}
+
+func TestVersionSummary(t *testing.T) {
+	r := testReport()
+	want := "version 1.2.0 -> 1.3.0 is a minor bump, but the changes need a major bump: 2.0.0"
+	if got := r.VersionSummary(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	r.Actual = BumpMajor
+	r.NewVersion = "2.0.0"
+	want = "version 1.2.0 -> 2.0.0 is a major bump; the changes need a major bump"
+	if got := r.VersionSummary(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	r = &Report{OldVersion: "1.2.0", NewVersion: "1.2.0"}
+	want = "version 1.2.0 is unchanged; the changes need none"
+	if got := r.VersionSummary(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, "text", testReport()); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"breaking (ABI): method r.draw: removed [removed]",
+		"addition: method r.present: added [added]",
+		"1 breaking, 1 addition(s), 0 compatible",
+		"version 1.2.0 -> 1.3.0 is a minor bump, but the changes need a major bump: 2.0.0",
+		"",
+	}, "\n")
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := WriteText(&buf, &Report{OldVersion: "1.0.0", NewVersion: "1.0.0"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "No changes.\n") {
+		t.Errorf("expected no changes, got %q", buf.String())
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, "json", testReport()); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Bump         string `json:"bump"`
+		RequiredBump string `json:"required_bump"`
+		VersionOK    bool   `json:"version_ok"`
+		Changes      []struct {
+			Level    string `json:"level"`
+			Category string `json:"category"`
+			Name     string `json:"name"`
+			ABI      bool   `json:"abi"`
+		} `json:"changes"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if got.Bump != "minor" || got.RequiredBump != "major" || got.VersionOK {
+		t.Errorf("unexpected version fields: %+v", got)
+	}
+	if len(got.Changes) != 2 || got.Changes[0].Level != "breaking" || !got.Changes[0].ABI || got.Changes[1].Category != "added" {
+		t.Errorf("unexpected changes: %+v", got.Changes)
+	}
+
+	buf.Reset()
+	if err := WriteJSON(&buf, &Report{OldVersion: "1.0.0", NewVersion: "1.0.0"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"changes": []`) {
+		t.Errorf("expected an empty changes array, got:\n%s", buf.String())
+	}
+}
+
+func TestWriteReport_UnknownFormat(t *testing.T) {
+	if err := WriteReport(&bytes.Buffer{}, "xml", testReport()); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
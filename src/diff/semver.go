@@ -0,0 +1,110 @@
+package diff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Bump is the part of a MAJOR.MINOR.PATCH version a release increments.
+type Bump int
+
+const (
+	BumpNone Bump = iota
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+func (b Bump) String() string {
+	switch b {
+	case BumpPatch:
+		return "patch"
+	case BumpMinor:
+		return "minor"
+	case BumpMajor:
+		return "major"
+	default:
+		return "none"
+	}
+}
+
+// parseVersion parses a MAJOR.MINOR.PATCH version, as the schema requires
+// api.version to be.
+func parseVersion(v string) ([3]int, error) {
+	var parsed [3]int
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return parsed, fmt.Errorf("version %q is not MAJOR.MINOR.PATCH", v)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return parsed, fmt.Errorf("version %q is not MAJOR.MINOR.PATCH", v)
+		}
+		parsed[i] = n
+	}
+	return parsed, nil
+}
+
+// versionBump returns the largest part incremented from old to new, or an
+// error if either does not parse or new is lower than old.
+func versionBump(oldVersion, newVersion string) (Bump, error) {
+	o, err := parseVersion(oldVersion)
+	if err != nil {
+		return BumpNone, err
+	}
+	n, err := parseVersion(newVersion)
+	if err != nil {
+		return BumpNone, err
+	}
+	for i, bump := range []Bump{BumpMajor, BumpMinor, BumpPatch} {
+		if n[i] > o[i] {
+			return bump, nil
+		}
+		if n[i] < o[i] {
+			return BumpNone, fmt.Errorf("version went down from %s to %s", oldVersion, newVersion)
+		}
+	}
+	return BumpNone, nil
+}
+
+// requiredBump returns the smallest bump from oldVersion that covers changes:
+// major for a breaking change, minor for an addition or a deprecation, and
+// patch for any other change. A breaking change to an experimental element
+// needs only a minor bump. Before 1.0.0 each requirement drops a level, as
+// Cargo does: a breaking change needs a minor bump and anything else a patch.
+func requiredBump(oldVersion string, changes []Change) Bump {
+	required := BumpNone
+	for _, c := range changes {
+		bump := BumpPatch
+		switch {
+		case c.Level == LevelBreaking && !c.Experimental:
+			bump = BumpMajor
+		case c.Level != LevelCompatible || c.Category == "deprecated":
+			bump = BumpMinor
+		}
+		required = max(required, bump)
+	}
+	if v, err := parseVersion(oldVersion); err == nil && v[0] == 0 && required > BumpPatch {
+		required--
+	}
+	return required
+}
+
+// nextVersion returns the lowest version that bumps v by b.
+func nextVersion(v string, b Bump) string {
+	parsed, err := parseVersion(v)
+	if err != nil {
+		return v
+	}
+	switch b {
+	case BumpMajor:
+		parsed = [3]int{parsed[0] + 1, 0, 0}
+	case BumpMinor:
+		parsed = [3]int{parsed[0], parsed[1] + 1, 0}
+	case BumpPatch:
+		parsed[2]++
+	}
+	return fmt.Sprintf("%d.%d.%d", parsed[0], parsed[1], parsed[2])
+}
@@ -0,0 +1,77 @@
+package diff
+
+import "testing"
+
+func TestVersionBump(t *testing.T) {
+	tests := []struct {
+		old, new string
+		want     Bump
+		wantErr  bool
+	}{
+		{"1.2.3", "1.2.3", BumpNone, false},
+		{"1.2.3", "1.2.4", BumpPatch, false},
+		{"1.2.3", "1.3.0", BumpMinor, false},
+		{"1.2.3", "2.0.0", BumpMajor, false},
+		{"1.2.3", "1.10.0", BumpMinor, false},
+		{"1.2.3", "1.2.2", BumpNone, true},
+		{"1.2.3", "0.9.0", BumpNone, true},
+		{"1.2", "1.3.0", BumpNone, true},
+		{"1.2.3", "1.x.0", BumpNone, true},
+	}
+	for _, tt := range tests {
+		got, err := versionBump(tt.old, tt.new)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s -> %s: unexpected error %v", tt.old, tt.new, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s -> %s: got %s, want %s", tt.old, tt.new, got, tt.want)
+		}
+	}
+}
+
+func TestRequiredBump(t *testing.T) {
+	breaking := Change{Level: LevelBreaking}
+	experimental := Change{Level: LevelBreaking, Experimental: true}
+	addition := Change{Level: LevelAddition}
+	deprecated := Change{Level: LevelCompatible, Category: "deprecated"}
+	compatible := Change{Level: LevelCompatible, Category: "thread-affinity"}
+
+	tests := []struct {
+		name    string
+		version string
+		changes []Change
+		want    Bump
+	}{
+		{"none", "1.0.0", nil, BumpNone},
+		{"compatible", "1.0.0", []Change{compatible}, BumpPatch},
+		{"deprecated", "1.0.0", []Change{deprecated}, BumpMinor},
+		{"addition", "1.0.0", []Change{compatible, addition}, BumpMinor},
+		{"breaking", "1.0.0", []Change{addition, breaking}, BumpMajor},
+		{"experimental breaking", "1.0.0", []Change{experimental}, BumpMinor},
+		{"0.x breaking", "0.3.0", []Change{breaking}, BumpMinor},
+		{"0.x addition", "0.3.0", []Change{addition}, BumpPatch},
+		{"0.x compatible", "0.3.0", []Change{compatible}, BumpPatch},
+	}
+	for _, tt := range tests {
+		if got := requiredBump(tt.version, tt.changes); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNextVersion(t *testing.T) {
+	tests := []struct {
+		bump Bump
+		want string
+	}{
+		{BumpNone, "1.2.3"},
+		{BumpPatch, "1.2.4"},
+		{BumpMinor, "1.3.0"},
+		{BumpMajor, "2.0.0"},
+	}
+	for _, tt := range tests {
+		if got := nextVersion("1.2.3", tt.bump); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.bump, got, tt.want)
+		}
+	}
+}
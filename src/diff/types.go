@@ -0,0 +1,225 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/benn-herrera/xplatter/resolver"
+)
+
+// compareTypes compares the FlatBuffers types each definition reaches from
+// its API surface. Types only one definition uses are added or dropped along
+// with the methods and events that use them, so only the added ones are
+// reported.
+func (d *differ) compareTypes() {
+	oldTypes := resolver.Reachable(d.old.Types, d.old.API.FlatBufferTypeRefs())
+	newTypes := resolver.Reachable(d.new.Types, d.new.API.FlatBufferTypeRefs())
+
+	for _, name := range sortedNames(oldTypes) {
+		ot := oldTypes[name]
+		nt, ok := newTypes[name]
+		if !ok {
+			continue
+		}
+		if ot.Kind != nt.Kind {
+			d.add(LevelBreaking, "type", ot.Kind.String(), name, true, "changed from %s to %s", ot.Kind, nt.Kind)
+			continue
+		}
+		switch ot.Kind {
+		case resolver.TypeKindEnum:
+			d.compareEnum(name, ot, nt)
+		case resolver.TypeKindUnion:
+			d.compareUnion(name, ot, nt)
+		case resolver.TypeKindStruct:
+			d.compareStruct(name, ot, nt)
+		case resolver.TypeKindTable:
+			d.compareTable(name, ot, nt)
+		}
+	}
+	for _, name := range sortedNames(newTypes) {
+		if _, ok := oldTypes[name]; !ok {
+			d.added(newTypes[name].Kind.String(), name)
+		}
+	}
+}
+
+// compareEnum compares enum values by name. A value removed while another
+// with its number was added is reported as renamed, which keeps the ABI.
+func (d *differ) compareEnum(name string, ot, nt *resolver.TypeInfo) {
+	if ot.BaseType != nt.BaseType {
+		d.add(LevelBreaking, "type", "enum", name, true, "underlying type changed from %s to %s", ot.BaseType, nt.BaseType)
+	}
+	if ot.BitFlags != nt.BitFlags {
+		d.add(LevelBreaking, "enum-value", "enum", name, true, "bit_flags changed from %t to %t", ot.BitFlags, nt.BitFlags)
+	}
+	oldValues := make(map[string]int64)
+	for _, v := range ot.EnumValues {
+		oldValues[v.Name] = v.Value
+	}
+	newValues := make(map[string]int64)
+	for _, v := range nt.EnumValues {
+		newValues[v.Name] = v.Value
+	}
+	renamedTo := make(map[string]bool)
+	for _, ov := range ot.EnumValues {
+		valueName := name + "." + ov.Name
+		nv, ok := newValues[ov.Name]
+		switch {
+		case ok && nv != ov.Value:
+			d.add(LevelBreaking, "enum-value", "enum value", valueName, true, "renumbered from %d to %d", ov.Value, nv)
+		case ok:
+		default:
+			if to, found := valueWithNumber(nt.EnumValues, ov.Value, oldValues); found && !renamedTo[to] {
+				renamedTo[to] = true
+				d.add(LevelBreaking, "renamed", "enum value", valueName, false, "renamed to %s", to)
+			} else {
+				d.removed("enum value", valueName)
+			}
+		}
+	}
+	for _, nv := range nt.EnumValues {
+		if _, ok := oldValues[nv.Name]; !ok && !renamedTo[nv.Name] {
+			d.added("enum value", name+"."+nv.Name)
+		}
+	}
+}
+
+// valueWithNumber returns the name of a value numbered n that the old enum
+// did not have.
+func valueWithNumber(values []resolver.EnumValue, n int64, old map[string]int64) (string, bool) {
+	for _, v := range values {
+		if _, existed := old[v.Name]; v.Value == n && !existed {
+			return v.Name, true
+		}
+	}
+	return "", false
+}
+
+// compareUnion compares union members by type tag.
+func (d *differ) compareUnion(name string, ot, nt *resolver.TypeInfo) {
+	newMembers := make(map[int64]resolver.UnionMember)
+	for _, m := range nt.Members {
+		newMembers[m.Value] = m
+	}
+	oldTags := make(map[int64]bool)
+	for _, om := range ot.Members {
+		oldTags[om.Value] = true
+		memberName := name + "." + om.Name
+		nm, ok := newMembers[om.Value]
+		switch {
+		case !ok:
+			d.add(LevelBreaking, "union-member", "union member", memberName, true, "removed")
+		case nm.Type != om.Type:
+			d.add(LevelBreaking, "union-member", "union member", memberName, true, "tag %d changed from %s to %s", om.Value, om.Type, nm.Type)
+		case nm.Name != om.Name:
+			d.add(LevelBreaking, "renamed", "union member", memberName, false, "renamed to %s", nm.Name)
+		}
+	}
+	for _, nm := range nt.Members {
+		if !oldTags[nm.Value] {
+			d.added("union member", name+"."+nm.Name)
+		}
+	}
+}
+
+// compareStruct compares struct fields by position: a struct is copied
+// across the C ABI as is, so any change to its fields moves its layout.
+func (d *differ) compareStruct(name string, ot, nt *resolver.TypeInfo) {
+	of, nf := ot.Fields, nt.Fields
+	if len(of) != len(nf) {
+		d.add(LevelBreaking, "struct-layout", "struct", name, true, "fields changed from {%s} to {%s}", fieldList(of), fieldList(nf))
+		return
+	}
+	for i := range of {
+		switch {
+		case of[i].Type != nf[i].Type:
+			d.add(LevelBreaking, "struct-layout", "struct", name, true, "field %d changed from %s: %s to %s: %s", i, of[i].Name, of[i].Type, nf[i].Name, nf[i].Type)
+		case of[i].Name != nf[i].Name:
+			d.add(LevelBreaking, "renamed", "field", name+"."+of[i].Name, false, "renamed to %s", nf[i].Name)
+		}
+	}
+}
+
+// compareTable compares table fields by their slot in the wire format. A
+// table crosses the C ABI as a FlatBuffer, so it may grow new fields but not
+// lose, retype or move existing ones.
+func (d *differ) compareTable(name string, ot, nt *resolver.TypeInfo) {
+	oldSlots, newSlots := tableSlots(ot, d.old.Types), tableSlots(nt, d.new.Types)
+	for _, slot := range sortedSlots(oldSlots) {
+		of := oldSlots[slot]
+		fieldName := name + "." + of.Name
+		nf, ok := newSlots[slot]
+		if !ok {
+			d.add(LevelBreaking, "table-field", "field", fieldName, true, "removed from slot %d; deprecate it instead", slot)
+			continue
+		}
+		if of.Type != nf.Type {
+			d.add(LevelBreaking, "table-field", "field", fieldName, true, "type changed from %s to %s", of.Type, nf.Type)
+		}
+		if of.Name != nf.Name {
+			d.add(LevelBreaking, "renamed", "field", fieldName, false, "renamed to %s", nf.Name)
+		}
+		if !of.Deprecated && nf.Deprecated {
+			// Deprecated fields are left out of the generated types.
+			d.add(LevelBreaking, "table-field", "field", fieldName, false, "deprecated, which removes it from the generated types")
+		}
+		if !of.Required && nf.Required {
+			d.add(LevelBreaking, "table-field", "field", fieldName, true, "became required, so tables built without it fail verification")
+		}
+		if of.Default != nf.Default {
+			d.add(LevelBreaking, "table-field", "field", fieldName, true, "default changed from %s to %s", orNone(of.Default), orNone(nf.Default))
+		}
+	}
+	for _, slot := range sortedSlots(newSlots) {
+		if _, ok := oldSlots[slot]; !ok {
+			d.added("field", name+"."+newSlots[slot].Name)
+		}
+	}
+}
+
+// tableSlots maps the fields of a table to their slots: the explicit id, else
+// the declaration order counting deprecated fields. A union field takes two
+// slots, its type tag and its value, and is keyed by the value's.
+func tableSlots(info *resolver.TypeInfo, types resolver.ResolvedTypes) map[int]resolver.FieldDef {
+	slots := make(map[int]resolver.FieldDef)
+	next := 0
+	for _, f := range info.Fields {
+		if f.HasID {
+			slots[f.ID] = f
+			continue
+		}
+		if ft, ok := types[f.Type]; ok && ft.Kind == resolver.TypeKindUnion {
+			next++
+		}
+		slots[next] = f
+		next++
+	}
+	return slots
+}
+
+func fieldList(fields []resolver.FieldDef) string {
+	var parts []string
+	for _, f := range fields {
+		parts = append(parts, fmt.Sprintf("%s: %s", f.Name, f.Type))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sortedNames(types resolver.ResolvedTypes) []string {
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedSlots(slots map[int]resolver.FieldDef) []int {
+	keys := make([]int, 0, len(slots))
+	for k := range slots {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -0,0 +1,170 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/benn-herrera/xplatter/resolver"
+)
+
+func TestCompareTypes_EnumValues(t *testing.T) {
+	newDef := baseDefinition()
+	newDef.Types["Common.ErrorCode"] = &resolver.TypeInfo{
+		Kind:     resolver.TypeKindEnum,
+		BaseType: "int32",
+		EnumValues: []resolver.EnumValue{
+			{Name: "Ok", Value: 1},
+			{Name: "Missing", Value: 1},
+			{Name: "Busy", Value: 2},
+		},
+	}
+	r := Compare(baseDefinition(), newDef)
+	expectChange(t, r, "enum-value", "Common.ErrorCode.Ok", LevelBreaking, true)
+	c := expectChange(t, r, "renamed", "Common.ErrorCode.NotFound", LevelBreaking, false)
+	if c.Message != "renamed to Missing" {
+		t.Errorf("unexpected message %q", c.Message)
+	}
+	expectChange(t, r, "added", "Common.ErrorCode.Busy", LevelAddition, false)
+	if findChange(r, "added", "Common.ErrorCode.Missing") != nil {
+		t.Error("renamed value should not also be reported as added")
+	}
+}
+
+func TestCompareTypes_EnumBaseType(t *testing.T) {
+	newDef := baseDefinition()
+	newDef.Types["Common.ErrorCode"].BaseType = "uint8"
+	r := Compare(baseDefinition(), newDef)
+	expectChange(t, r, "type", "Common.ErrorCode", LevelBreaking, true)
+}
+
+func TestCompareTypes_KindChanged(t *testing.T) {
+	newDef := baseDefinition()
+	newDef.Types["Common.Config"].Kind = resolver.TypeKindStruct
+	r := Compare(baseDefinition(), newDef)
+	c := expectChange(t, r, "type", "Common.Config", LevelBreaking, true)
+	if c.Message != "changed from table to struct" {
+		t.Errorf("unexpected message %q", c.Message)
+	}
+}
+
+func TestCompareTypes_StructLayout(t *testing.T) {
+	withStruct := func(fields ...resolver.FieldDef) Definition {
+		def := baseDefinition()
+		def.Types["Common.Config"] = &resolver.TypeInfo{Kind: resolver.TypeKindStruct, Fields: fields}
+		return def
+	}
+	x := resolver.FieldDef{Name: "x", Type: "float32"}
+	y := resolver.FieldDef{Name: "y", Type: "float32"}
+	z := resolver.FieldDef{Name: "z", Type: "float32"}
+
+	r := Compare(withStruct(x, y), withStruct(x, y, z))
+	expectChange(t, r, "struct-layout", "Common.Config", LevelBreaking, true)
+
+	r = Compare(withStruct(x, y), withStruct(x, resolver.FieldDef{Name: "y", Type: "float64"}))
+	expectChange(t, r, "struct-layout", "Common.Config", LevelBreaking, true)
+
+	r = Compare(withStruct(x, y), withStruct(x, resolver.FieldDef{Name: "w", Type: "float32"}))
+	expectChange(t, r, "renamed", "Common.Config.y", LevelBreaking, false)
+	if findChange(r, "struct-layout", "Common.Config") != nil {
+		t.Error("renaming a struct field should not change its layout")
+	}
+}
+
+func TestCompareTypes_TableFields(t *testing.T) {
+	newDef := baseDefinition()
+	newDef.Types["Common.Config"].Fields = []resolver.FieldDef{
+		{Name: "width", Type: "uint32", Deprecated: true},
+		{Name: "h", Type: "uint16", Default: "1"},
+		{Name: "depth", Type: "uint32"},
+	}
+	r := Compare(baseDefinition(), newDef)
+	expectChange(t, r, "table-field", "Common.Config.width", LevelBreaking, false)
+	expectChange(t, r, "renamed", "Common.Config.height", LevelBreaking, false)
+	c := findChange(r, "table-field", "Common.Config.height")
+	if c == nil || c.Message != "type changed from uint32 to uint16" {
+		t.Errorf("expected type change to height, got %v", c)
+	}
+	expectChange(t, r, "added", "Common.Config.depth", LevelAddition, false)
+}
+
+func TestCompareTypes_TableFieldRemoved(t *testing.T) {
+	newDef := baseDefinition()
+	newDef.Types["Common.Config"].Fields = newDef.Types["Common.Config"].Fields[1:]
+	r := Compare(baseDefinition(), newDef)
+	// height moves into width's slot.
+	expectChange(t, r, "renamed", "Common.Config.width", LevelBreaking, false)
+	expectChange(t, r, "table-field", "Common.Config.height", LevelBreaking, true)
+}
+
+func TestCompareTypes_ReachableOnly(t *testing.T) {
+	oldDef := baseDefinition()
+	oldDef.Types["Common.Unused"] = &resolver.TypeInfo{Kind: resolver.TypeKindTable}
+	newDef := baseDefinition()
+	newDef.Types["Common.Unused"] = &resolver.TypeInfo{Kind: resolver.TypeKindEnum}
+	newDef.Types["Common.Config"].Fields = append(newDef.Types["Common.Config"].Fields,
+		resolver.FieldDef{Name: "mode", Type: "Common.Mode"})
+	newDef.Types["Common.Mode"] = &resolver.TypeInfo{Kind: resolver.TypeKindEnum, BaseType: "int8"}
+	r := Compare(oldDef, newDef)
+	if findChange(r, "type", "Common.Unused") != nil {
+		t.Error("types the API does not reach should not be compared")
+	}
+	expectChange(t, r, "added", "Common.Mode", LevelAddition, false)
+}
+
+func TestCompareTypes_UnionMembers(t *testing.T) {
+	union := func(members ...resolver.UnionMember) Definition {
+		def := baseDefinition()
+		def.API.Events[0].Type = "Common.Shape"
+		def.Types["Common.Shape"] = &resolver.TypeInfo{Kind: resolver.TypeKindUnion, Members: members}
+		def.Types["Common.Circle"] = &resolver.TypeInfo{Kind: resolver.TypeKindTable}
+		def.Types["Common.Square"] = &resolver.TypeInfo{Kind: resolver.TypeKindTable}
+		return def
+	}
+	circle := resolver.UnionMember{Name: "Circle", Type: "Common.Circle", Value: 1}
+	square := resolver.UnionMember{Name: "Square", Type: "Common.Square", Value: 2}
+
+	r := Compare(union(circle, square), union(circle))
+	expectChange(t, r, "union-member", "Common.Shape.Square", LevelBreaking, true)
+
+	r = Compare(union(circle), union(resolver.UnionMember{Name: "Circle", Type: "Common.Square", Value: 1}))
+	expectChange(t, r, "union-member", "Common.Shape.Circle", LevelBreaking, true)
+
+	r = Compare(union(circle), union(circle, square))
+	expectChange(t, r, "added", "Common.Shape.Square", LevelAddition, false)
+}
+
+func TestTableSlots(t *testing.T) {
+	types := resolver.ResolvedTypes{
+		"Common.Shape": &resolver.TypeInfo{Kind: resolver.TypeKindUnion},
+	}
+	info := &resolver.TypeInfo{
+		Kind: resolver.TypeKindTable,
+		Fields: []resolver.FieldDef{
+			{Name: "a", Type: "int32"},
+			{Name: "shape", Type: "Common.Shape"},
+			{Name: "b", Type: "int32", Deprecated: true},
+			{Name: "c", Type: "int32"},
+		},
+	}
+	slots := tableSlots(info, types)
+	want := map[int]string{0: "a", 2: "shape", 3: "b", 4: "c"}
+	if len(slots) != len(want) {
+		t.Fatalf("expected %d slots, got %v", len(want), slots)
+	}
+	for slot, name := range want {
+		if slots[slot].Name != name {
+			t.Errorf("slot %d: expected %s, got %s", slot, name, slots[slot].Name)
+		}
+	}
+
+	explicit := &resolver.TypeInfo{
+		Kind: resolver.TypeKindTable,
+		Fields: []resolver.FieldDef{
+			{Name: "b", Type: "int32", ID: 1, HasID: true},
+			{Name: "a", Type: "int32", ID: 0, HasID: true},
+		},
+	}
+	slots = tableSlots(explicit, types)
+	if slots[0].Name != "a" || slots[1].Name != "b" {
+		t.Errorf("expected explicit ids to pick slots, got %v", slots)
+	}
+}
@@ -0,0 +1,60 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ABIInfoFunctionName returns the exported C function that reports the API
+// version and ABI hash the library was built from.
+// e.g., "hello_xplatter" → "hello_xplatter_abi_info"
+func ABIInfoFunctionName(apiName string) string {
+	return apiName + "_abi_info"
+}
+
+// ABIInfoSourceName returns the generated source that defines the ABI info
+// function for C implementations.
+func ABIInfoSourceName(apiName string) string {
+	return apiName + "_abi_info.c"
+}
+
+// ABIInfo returns the string the ABI info function returns: the API version,
+// a space, and the hash of the C ABI, e.g., "1.2.0 8f3a61c02e9b4d17". Bindings
+// compare only the hash, so a version bump that keeps the ABI still loads.
+func ABIInfo(ctx *Context) string {
+	return ctx.API.API.Version + " " + BuildABILock(ctx).Hash()
+}
+
+// writeABIInfoDeclaration emits the ABI handshake section of the public C header.
+func writeABIInfoDeclaration(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, `/* ABI handshake — returns "<version> <hash>": the API version and a hash of
+ * the C ABI this header declares. Bindings compare the hash with their own
+ * when they load the library. The string is static; do not free it. */
+%s const char* %s(void);
+
+`, ExportMacroName(apiName), ABIInfoFunctionName(apiName))
+}
+
+// generateCABIInfoSource produces <api>_abi_info.c, which defines the ABI
+// info function for C implementations. It is regenerated on every run and
+// compiled next to the implementation, like the C++ shim, so the function
+// always reports the ABI the header was generated with.
+func generateCABIInfoSource(apiName, abiInfo string) *OutputFile {
+	var b strings.Builder
+	fmt.Fprintf(&b, `#include "%s.h"
+
+/* The API version and ABI hash this implementation is built from. */
+%s const char* %s(void) {
+    return "%s";
+}
+`, apiName, ExportMacroName(apiName), ABIInfoFunctionName(apiName), abiInfo)
+	return &OutputFile{Path: ABIInfoSourceName(apiName), Content: []byte(b.String())}
+}
+
+// abiMismatchMessage returns the message bindings fail to load with when the
+// library reports a different ABI hash. library and bindings are the target
+// language's string interpolations of the two ABI info strings.
+func abiMismatchMessage(apiName, library, bindings string) string {
+	return fmt.Sprintf("%s ABI mismatch: the library reports %s but the bindings were generated for %s; regenerate and rebuild both from the same API definition",
+		apiName, library, bindings)
+}
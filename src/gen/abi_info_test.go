@@ -0,0 +1,223 @@
+package gen
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/benn-herrera/xplatter/model"
+)
+
+func TestABIInfoNames(t *testing.T) {
+	if got := ABIInfoFunctionName("test_api"); got != "test_api_abi_info" {
+		t.Errorf("ABIInfoFunctionName = %q", got)
+	}
+	if got := ABIInfoSourceName("test_api"); got != "test_api_abi_info.c" {
+		t.Errorf("ABIInfoSourceName = %q", got)
+	}
+}
+
+func TestABIInfo(t *testing.T) {
+	ctx := loadTestAPI(t, "minimal.yaml")
+	info := ABIInfo(ctx)
+	if !regexp.MustCompile(`^0\.1\.0 [0-9a-f]{16}$`).MatchString(info) {
+		t.Fatalf("ABIInfo = %q, want the version and 16 hex digits", info)
+	}
+	if again := ABIInfo(loadTestAPI(t, "minimal.yaml")); again != info {
+		t.Errorf("ABIInfo is not stable: %q then %q", info, again)
+	}
+
+	// The version is reported but is not part of the hash.
+	ctx.API.API.Version = "0.2.0"
+	if bumped := ABIInfo(ctx); bumped != "0.2.0"+strings.TrimPrefix(info, "0.1.0") {
+		t.Errorf("version bump changed the hash: %q then %q", info, bumped)
+	}
+
+	// Any change to a signature changes the hash.
+	method := &ctx.API.Interfaces[0].Constructors[0]
+	method.Name += "_v2"
+	if renamed := ABIInfo(ctx); strings.Fields(renamed)[1] == strings.Fields(info)[1] {
+		t.Errorf("renaming %s kept the hash %q", method.Name, renamed)
+	}
+}
+
+func TestABIInfo_StructLayoutChangesHash(t *testing.T) {
+	ctx := loadTestAPI(t, "arrays.yaml")
+	lock := BuildABILock(ctx)
+	hash := lock.Hash()
+	lock.Structs[0].Fields[0].Offset += 4
+	if lock.Hash() == hash {
+		t.Errorf("moving a field of %s kept the hash", lock.Structs[0].Name)
+	}
+}
+
+func TestGenerateCABIInfoSource(t *testing.T) {
+	content := string(generateCABIInfoSource("test_api", "1.0.0 0123456789abcdef").Content)
+	for _, want := range []string{
+		`#include "test_api.h"`,
+		"TEST_API_EXPORT const char* test_api_abi_info(void) {\n    return \"1.0.0 0123456789abcdef\";\n}",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("ABI info source missing %q", want)
+		}
+	}
+}
+
+// generateFile runs the named generator on ctx and returns the content of its
+// output file at path.
+func generateFile(t *testing.T, ctx *Context, generator, path string) string {
+	t.Helper()
+	g, ok := Get(generator)
+	if !ok {
+		t.Fatalf("generator %s not registered", generator)
+	}
+	files, err := g.Generate(ctx)
+	if err != nil {
+		t.Fatalf("%s generation failed: %v", generator, err)
+	}
+	return string(findOutputFile(t, files, path).Content)
+}
+
+func TestABIInfo_Header(t *testing.T) {
+	ctx := loadTestAPI(t, "minimal.yaml")
+	header := generateFile(t, ctx, "cheader", "test_api.h")
+	if !strings.Contains(header, "TEST_API_EXPORT const char* test_api_abi_info(void);") {
+		t.Error("header missing the ABI info declaration")
+	}
+	if !strings.Contains(ComputeWASMExportsCSV("test_api", ctx.API, ctx.ResolvedTypes), "_test_api_abi_info") {
+		t.Error("WASM exports missing the ABI info function")
+	}
+}
+
+func TestABIInfo_ImplShims(t *testing.T) {
+	ctx := loadTestAPI(t, "minimal.yaml")
+	info := ABIInfo(ctx)
+
+	g := &ImplCGenerator{}
+	files, err := g.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	impl := string(findOutputFile(t, files, "test_api_impl.c").Content)
+	if strings.Contains(impl, "abi_info") {
+		t.Error("C scaffold should leave the ABI info function to the generated source")
+	}
+	source := findOutputFile(t, files, "test_api_abi_info.c")
+	if source.Scaffold || source.ProjectFile {
+		t.Error("ABI info source should be a regenerated file in the output directory")
+	}
+	if !strings.Contains(string(source.Content), `"`+info+`"`) {
+		t.Errorf("ABI info source missing %q", info)
+	}
+	if cmake := string(findOutputFile(t, files, "CMakeLists.txt").Content); !strings.Contains(cmake, "generated/test_api_abi_info.c") {
+		t.Error("CMakeLists.txt should compile the ABI info source")
+	}
+	makefile := generateFile(t, ctx, "impl_makefile_c", "Makefile")
+	if !strings.Contains(makefile, "ABI_INFO_SOURCE := $(GEN_DIR)$(API_NAME)_abi_info.c") {
+		t.Error("Makefile should define ABI_INFO_SOURCE")
+	}
+	if got := strings.Count(makefile, "$(IMPL_SOURCES) $(ABI_INFO_SOURCE)"); got != 6 {
+		t.Errorf("expected the 5 desktop builds and the Android rule to use ABI_INFO_SOURCE, got %d", got)
+	}
+	for _, want := range []string{"obj/$(1)/abi_info.o: $(ABI_INFO_SOURCE)", "-c -o $(DIST_ANDROID_DIR)/obj/$(1)/abi_info.o $(ABI_INFO_SOURCE)"} {
+		if !strings.Contains(makefile, want) {
+			t.Errorf("Makefile missing %q", want)
+		}
+	}
+
+	for _, tt := range []struct {
+		generator, path, want string
+	}{
+		{"impl_cpp", "test_api_shim.cpp", "TEST_API_EXPORT const char* test_api_abi_info(void) {\n    return \"" + info + "\";\n}"},
+		{"impl_rust", "test_api_ffi.rs", "#[no_mangle]\npub extern \"C\" fn test_api_abi_info() -> *const c_char {\n    b\"" + info + "\\0\".as_ptr() as *const c_char\n}"},
+		{"impl_go", "test_api_cgo.go", "var _abiInfo = C.CString(\"" + info + "\")\n\n//export test_api_abi_info\nfunc test_api_abi_info() *C.char {\n\treturn _abiInfo\n}"},
+		{"impl_go_wasm", "test_api_wasm.go", "var _abiInfo = []byte(\"" + info + "\\x00\")\n\n//go:wasmexport test_api_abi_info\nfunc test_api_abi_info() uintptr {"},
+	} {
+		if content := generateFile(t, ctx, tt.generator, tt.path); !strings.Contains(content, tt.want) {
+			t.Errorf("%s missing %q", tt.path, tt.want)
+		}
+	}
+}
+
+func TestABIInfo_Bindings(t *testing.T) {
+	ctx := loadTestAPI(t, "minimal.yaml")
+	info := ABIInfo(ctx)
+
+	kotlin := generateFile(t, ctx, "kotlin", "TestApi.kt")
+	for _, want := range []string{
+		"    const val ABI_INFO = \"" + info + "\"\n",
+		"        System.loadLibrary(\"test_api\")\n        val abiInfo = nativeAbiInfo()\n        if (abiInfo.substringAfterLast(' ') != ABI_INFO.substringAfterLast(' ')) {\n            throw UnsatisfiedLinkError(\"test_api ABI mismatch: the library reports $abiInfo but the bindings were generated for $ABI_INFO;",
+		"    external fun nativeAbiInfo(): String\n",
+	} {
+		if !strings.Contains(kotlin, want) {
+			t.Errorf("Kotlin file missing %q", want)
+		}
+	}
+	jni := generateFile(t, ctx, "kotlin", "test_api_jni.c")
+	if !strings.Contains(jni, "_TestApi_nativeAbiInfo(JNIEnv *env, jobject thiz) {\n    return (*env)->NewStringUTF(env, test_api_abi_info());") {
+		t.Error("JNI bridge missing nativeAbiInfo")
+	}
+
+	swift := generateFile(t, ctx, "swift", "TestApi.swift")
+	for _, want := range []string{
+		"public enum TestApiABI {",
+		"    public static let expected = \"" + info + "\"\n",
+		"    public static var library: String { String(cString: test_api_abi_info()) }",
+		"public var description: String { \"test_api ABI mismatch: the library reports \\(library) but the bindings were generated for \\(expected);",
+		"    public static func verify() throws {",
+		"    static let checked: Void = {",
+		"    public static func createEngine() throws -> Engine {\n        _ = TestApiABI.checked\n",
+	} {
+		if !strings.Contains(swift, want) {
+			t.Errorf("Swift file missing %q", want)
+		}
+	}
+
+	js := generateFile(t, ctx, "jswasm", "test_api.js")
+	for _, want := range []string{
+		"const ABI_INFO = '" + info + "';",
+		"  const abiInfo = _decodeString(_wasm.exports.test_api_abi_info());\n  if (abiInfo.split(' ').pop() !== ABI_INFO.split(' ').pop()) {\n    throw new Error(`test_api ABI mismatch: the library reports ${abiInfo} but the bindings were generated for ${ABI_INFO};",
+	} {
+		if !strings.Contains(js, want) {
+			t.Errorf("JS module missing %q", want)
+		}
+	}
+}
+
+func TestABIInfo_SwiftStaticEntryPoints(t *testing.T) {
+	// Async factories, free functions and event polling reach the library
+	// without a handle, so each runs the check.
+	for _, tt := range []struct {
+		file string
+		edit func(*model.APIDefinition)
+		want []string
+	}{
+		{"async.yaml", nil, []string{
+			"    public static func forkEngine(engine: Engine) async throws -> Engine {\n        _ = AsyncApiABI.checked\n",
+		}},
+		{"minimal.yaml", func(api *model.APIDefinition) {
+			api.Interfaces[0].Methods = append(api.Interfaces[0].Methods, model.MethodDef{
+				Name:    "version",
+				Returns: &model.ReturnDef{Type: "int32"},
+			})
+		}, []string{
+			"public enum TestApi {\n",
+			"    public static func version() -> Int32 {\n        _ = TestApiABI.checked\n        return test_api_lifecycle_version()\n",
+		}},
+		{"events.yaml", nil, []string{
+			"    public static func poll() -> EventApiEvent? {\n        _ = EventApiABI.checked\n",
+			"-> DispatchSourceRead? {\n        _ = EventApiABI.checked\n",
+		}},
+	} {
+		ctx := loadTestAPI(t, tt.file)
+		if tt.edit != nil {
+			tt.edit(ctx.API)
+		}
+		content := generateFile(t, ctx, "swift", ToPascalCase(ctx.API.API.Name)+".swift")
+		for _, want := range tt.want {
+			if !strings.Contains(content, want) {
+				t.Errorf("%s: Swift file missing %q", tt.file, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,442 @@
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/benn-herrera/xplatter/model"
+	"github.com/benn-herrera/xplatter/resolver"
+	"gopkg.in/yaml.v3"
+)
+
+// ABILockFile is the lock file generate writes next to the API definition.
+const ABILockFile = "xplatter.lock"
+
+// pointerSize is the size and alignment of a C pointer in lock file layouts,
+// which are those of 64-bit targets.
+const pointerSize = 8
+
+// ABILock records the C ABI the generated header exports: every function
+// with its signature, the size, alignment and field offsets of every struct
+// that crosses it by value, and the constants of every enum. Functions are
+// sorted by name and structs and enums by C type name, so reordering
+// declarations is not a change.
+type ABILock struct {
+	API       string         `yaml:"api"`
+	Functions []LockFunction `yaml:"functions"`
+	Structs   []LockStruct   `yaml:"structs,omitempty"`
+	Enums     []LockEnum     `yaml:"enums,omitempty"`
+}
+
+// LockFunction is an exported C function.
+type LockFunction struct {
+	Name      string `yaml:"name"`
+	Signature string `yaml:"signature"` // e.g., "int32_t my_api_renderer_draw(renderer_handle renderer)"
+}
+
+// LockStruct is the layout of a C struct.
+type LockStruct struct {
+	Name   string      `yaml:"name"` // C type name, e.g., "Geometry_Vec3"
+	Size   int         `yaml:"size"`
+	Align  int         `yaml:"align"`
+	Fields []LockField `yaml:"fields"`
+}
+
+// LockField is a field of a C struct.
+type LockField struct {
+	Name   string `yaml:"name"`
+	Type   string `yaml:"type"` // C type, with the array length if any, e.g., "float[16]"
+	Offset int    `yaml:"offset"`
+}
+
+// LockEnum is a C enum: an FBS enum, including error enums, a union type
+// tag, the event kind or the async status.
+type LockEnum struct {
+	Name   string          `yaml:"name"` // C type name, e.g., "Common_ErrorCode"
+	Values []LockEnumValue `yaml:"values"`
+}
+
+// LockEnumValue is a constant of a C enum.
+type LockEnumValue struct {
+	Name  string `yaml:"name"` // C constant name, e.g., "Common_ErrorCode_Ok"
+	Value int64  `yaml:"value"`
+}
+
+// MarshalYAML writes a constant on one line.
+func (v LockEnumValue) MarshalYAML() (any, error) {
+	var node yaml.Node
+	type plain LockEnumValue // without this method
+	if err := node.Encode(plain(v)); err != nil {
+		return nil, err
+	}
+	node.Style = yaml.FlowStyle
+	return &node, nil
+}
+
+// MarshalYAML writes a field on one line, so a layout change diffs as the
+// lines of the fields that moved.
+func (f LockField) MarshalYAML() (any, error) {
+	var node yaml.Node
+	type plain LockField // without this method
+	if err := node.Encode(plain(f)); err != nil {
+		return nil, err
+	}
+	node.Style = yaml.FlowStyle
+	return &node, nil
+}
+
+// BuildABILock records the C ABI that the C header generated from ctx
+// exports.
+func BuildABILock(ctx *Context) *ABILock {
+	lock := &ABILock{API: ctx.API.API.Name}
+	for _, fn := range exportedCFunctions(ctx) {
+		lock.Functions = append(lock.Functions, LockFunction{Name: fn.name, Signature: fn.signature()})
+	}
+	sort.Slice(lock.Functions, func(i, j int) bool { return lock.Functions[i].Name < lock.Functions[j].Name })
+
+	layouts := newCLayouts(ctx.ResolvedTypes)
+	var names []string
+	for name, info := range ctx.ResolvedTypes {
+		if info.Kind == resolver.TypeKindStruct || info.Kind == resolver.TypeKindUnion {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return model.FlatBufferCType(names[i]) < model.FlatBufferCType(names[j]) })
+	for _, name := range names {
+		lock.Structs = append(lock.Structs, *layouts.of(name))
+	}
+
+	lock.Enums = cEnums(ctx)
+	sort.Slice(lock.Enums, func(i, j int) bool { return lock.Enums[i].Name < lock.Enums[j].Name })
+	return lock
+}
+
+// cEnums returns the enums the C header declares, as writeFBSTypedefs,
+// writeEventDeclarations and writeAsyncDeclarations declare them.
+func cEnums(ctx *Context) []LockEnum {
+	apiName := ctx.API.API.Name
+	var enums []LockEnum
+	for name, info := range ctx.ResolvedTypes {
+		cName := model.FlatBufferCType(name)
+		switch info.Kind {
+		case resolver.TypeKindEnum:
+			e := LockEnum{Name: cName}
+			for _, v := range info.EnumValues {
+				e.Values = append(e.Values, LockEnumValue{cName + "_" + v.Name, v.Value})
+			}
+			enums = append(enums, e)
+		case resolver.TypeKindUnion:
+			e := LockEnum{Name: unionTagCType(name), Values: []LockEnumValue{{cName + "_NONE", 0}}}
+			for _, m := range info.Members {
+				e.Values = append(e.Values, LockEnumValue{cName + "_" + m.Name, m.Value})
+			}
+			enums = append(enums, e)
+		}
+	}
+	if len(ctx.API.Events) > 0 {
+		e := LockEnum{Name: EventKindTypeName(apiName), Values: []LockEnumValue{{UpperSnakeCase(apiName) + "_EVENT_NONE", 0}}}
+		for i, ev := range ctx.API.Events {
+			e.Values = append(e.Values, LockEnumValue{EventKindConstName(apiName, ev.Name), int64(EventKindValue(i))})
+		}
+		enums = append(enums, e)
+	}
+	if hasAsyncMethods(ctx.API) {
+		enums = append(enums, LockEnum{Name: AsyncStatusTypeName(apiName), Values: []LockEnumValue{
+			{AsyncStatusConstName(apiName, "pending"), AsyncStatusPending},
+			{AsyncStatusConstName(apiName, "done"), AsyncStatusDone},
+			{AsyncStatusConstName(apiName, "cancelled"), AsyncStatusCancelled},
+		}})
+	}
+	return enums
+}
+
+// exportedCFunctions returns the functions the C header declares with the
+// export macro, in header order.
+func exportedCFunctions(ctx *Context) []cFunction {
+	api := ctx.API
+	apiName := api.API.Name
+	fns := []cFunction{{returnType: "const char*", name: ABIInfoFunctionName(apiName)}}
+	if hasStringReturns(api) {
+		fns = append(fns, cFunction{returnType: "void", name: StringFreeFunctionName(apiName), params: []string{"char* str"}})
+	}
+	if hasBufferReturns(api, ctx.ResolvedTypes) {
+		fns = append(fns, cFunction{returnType: "void", name: BufferFreeFunctionName(apiName), params: []string{"void* data"}})
+	}
+	if len(api.Events) > 0 {
+		fns = append(fns,
+			cFunction{returnType: "int32_t", name: EventPollFunctionName(apiName), params: []string{"uint32_t* out_kind", "uint8_t* buffer", "uint32_t buffer_size"}},
+			cFunction{returnType: "int32_t", name: EventSignalFDFunctionName(apiName)})
+	}
+	for i := range api.Interfaces {
+		iface := &api.Interfaces[i]
+		for j := range iface.Constructors {
+			fns = append(fns, methodCFunctions(apiName, iface.Name, &iface.Constructors[j])...)
+		}
+		if handleName, ok := iface.ConstructorHandleName(); ok {
+			destructor := threadAffinityDestructor(api, iface, handleName)
+			fns = append(fns, methodCFunctions(apiName, iface.Name, &destructor)...)
+		}
+		for j := range iface.Methods {
+			fns = append(fns, methodCFunctions(apiName, iface.Name, &iface.Methods[j])...)
+		}
+	}
+	return fns
+}
+
+// cLayouts computes the C layouts of the structs and unions in a set of
+// types, as writeFBSTypedefs declares them.
+type cLayouts struct {
+	types   resolver.ResolvedTypes
+	structs map[string]*LockStruct
+}
+
+func newCLayouts(types resolver.ResolvedTypes) *cLayouts {
+	return &cLayouts{types: types, structs: make(map[string]*LockStruct)}
+}
+
+// of returns the layout of the struct or union named name.
+func (l *cLayouts) of(name string) *LockStruct {
+	if s, ok := l.structs[name]; ok {
+		return s
+	}
+	s := &LockStruct{Name: model.FlatBufferCType(name), Align: 1}
+	info := l.types[name]
+	if info.Kind == resolver.TypeKindUnion {
+		l.unionFields(s, name, info)
+	} else {
+		for _, f := range info.ActiveFields() {
+			elem, n, isArray := resolver.ArrayField(f.Type)
+			if !isArray {
+				elem, n = f.Type, 1
+			}
+			cType, extraField := fbsFieldToCType(resolver.FieldDef{Name: f.Name, Type: elem})
+			size, align := l.sizeOf(elem)
+			if isArray {
+				cType = fmt.Sprintf("%s[%d]", cType, n)
+			}
+			s.addField(f.Name, cType, size*n, align)
+			if extraField != "" {
+				// A vector is a pointer followed by its count.
+				s.addField(f.Name+"_count", "uint32_t", 4, 4)
+			}
+		}
+	}
+	s.Size = alignUp(s.Size, s.Align)
+	l.structs[name] = s
+	return s
+}
+
+// unionFields lays out the tagged pair writeCUnionTypedef declares.
+func (l *cLayouts) unionFields(s *LockStruct, name string, info *resolver.TypeInfo) {
+	s.addField("type", unionTagCType(name), 4, 4)
+	if unionHasStructs(info, l.types) {
+		size, align := 0, 1
+		var members []string
+		for _, m := range info.Members {
+			if !isTableType(l.types, m.Type) {
+				member := l.of(m.Type)
+				size, align = max(size, member.Size), max(align, member.Align)
+				members = append(members, fmt.Sprintf("%s %s;", member.Name, m.Name))
+			}
+		}
+		s.addField("value", "union { "+strings.Join(members, " ")+" }", alignUp(size, align), align)
+	}
+	if unionHasTables(info, l.types) {
+		s.addField("table", "const uint8_t*", pointerSize, pointerSize)
+		s.addField("table_len", "uint32_t", 4, 4)
+	}
+}
+
+// sizeOf returns the C size and alignment of a struct field type.
+func (l *cLayouts) sizeOf(fbsType string) (size, align int) {
+	switch fbsType {
+	case "bool", "int8", "uint8":
+		return 1, 1
+	case "int16", "uint16":
+		return 2, 2
+	case "int32", "uint32", "float32":
+		return 4, 4
+	case "int64", "uint64", "float64":
+		return 8, 8
+	case "string":
+		return pointerSize, pointerSize
+	}
+	if strings.HasPrefix(fbsType, "[") {
+		return pointerSize, pointerSize
+	}
+	if info, ok := l.types[fbsType]; ok {
+		switch info.Kind {
+		case resolver.TypeKindStruct, resolver.TypeKindUnion:
+			s := l.of(fbsType)
+			return s.Size, s.Align
+		case resolver.TypeKindEnum:
+			// Declared as a C enum, which is int-sized whatever the FBS
+			// underlying type.
+			return 4, 4
+		}
+	}
+	return pointerSize, pointerSize
+}
+
+// addField appends a field at the next offset aligned for it.
+func (s *LockStruct) addField(name, cType string, size, align int) {
+	offset := alignUp(s.Size, align)
+	s.Fields = append(s.Fields, LockField{Name: name, Type: cType, Offset: offset})
+	s.Size = offset + size
+	s.Align = max(s.Align, align)
+}
+
+// Marshal returns the lock file contents.
+func (l *ABILock) Marshal() ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `# %s: the C ABI exported by %s.h. Written by xplatter generate; do not edit.
+# Struct layouts are those of 64-bit targets. generate --locked fails if the
+# ABI no longer matches this file; generate --update-lock rewrites it.
+`, ABILockFile, l.API)
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(l); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// ParseABILock parses a lock file.
+func ParseABILock(data []byte) (*ABILock, error) {
+	var lock ABILock
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&lock); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing %s: %w", ABILockFile, err)
+	}
+	return &lock, nil
+}
+
+// Hash returns a 64-bit FNV-1a hash of the ABI as 16 hex digits. It covers
+// the function signatures, struct layouts and enum values, so any change
+// Drift reports changes it.
+func (l *ABILock) Hash() string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "api %s\n", l.API)
+	for _, fn := range l.Functions {
+		fmt.Fprintf(h, "function %s\n", fn.Signature)
+	}
+	for i := range l.Structs {
+		fmt.Fprintf(h, "struct %s %s\n", l.Structs[i].Name, l.Structs[i].layout())
+	}
+	for i := range l.Enums {
+		fmt.Fprintf(h, "enum %s %s\n", l.Enums[i].Name, l.Enums[i].values())
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// LockDrift is a difference between a lock file and the current ABI.
+type LockDrift struct {
+	Subject string // e.g., "function my_api_renderer_draw", "struct Geometry_Vec3", "enum Common_ErrorCode"
+	Change  string // e.g., "removed", "signature changed from ... to ..."
+}
+
+func (d LockDrift) String() string { return d.Subject + ": " + d.Change }
+
+// Drift returns each difference from the locked ABI l to current, one per
+// function, struct or enum, or nil if they match.
+func (l *ABILock) Drift(current *ABILock) []LockDrift {
+	var drift []LockDrift
+	if l.API != current.API {
+		drift = append(drift, LockDrift{"api", fmt.Sprintf("renamed from %s to %s", l.API, current.API)})
+	}
+
+	currentFns := make(map[string]string)
+	for _, fn := range current.Functions {
+		currentFns[fn.Name] = fn.Signature
+	}
+	lockedFns := make(map[string]bool)
+	for _, fn := range l.Functions {
+		lockedFns[fn.Name] = true
+		sig, ok := currentFns[fn.Name]
+		switch {
+		case !ok:
+			drift = append(drift, LockDrift{"function " + fn.Name, "removed"})
+		case sig != fn.Signature:
+			drift = append(drift, LockDrift{"function " + fn.Name, fmt.Sprintf("signature changed from %s to %s", fn.Signature, sig)})
+		}
+	}
+	for _, fn := range current.Functions {
+		if !lockedFns[fn.Name] {
+			drift = append(drift, LockDrift{"function " + fn.Name, "added"})
+		}
+	}
+
+	currentStructs := make(map[string]*LockStruct)
+	for i := range current.Structs {
+		currentStructs[current.Structs[i].Name] = &current.Structs[i]
+	}
+	lockedStructs := make(map[string]bool)
+	for i := range l.Structs {
+		ls := &l.Structs[i]
+		lockedStructs[ls.Name] = true
+		cs, ok := currentStructs[ls.Name]
+		switch {
+		case !ok:
+			drift = append(drift, LockDrift{"struct " + ls.Name, "removed"})
+		case ls.layout() != cs.layout():
+			drift = append(drift, LockDrift{"struct " + ls.Name, fmt.Sprintf("layout changed from %s to %s", ls.layout(), cs.layout())})
+		}
+	}
+	for _, cs := range current.Structs {
+		if !lockedStructs[cs.Name] {
+			drift = append(drift, LockDrift{"struct " + cs.Name, "added"})
+		}
+	}
+
+	currentEnums := make(map[string]*LockEnum)
+	for i := range current.Enums {
+		currentEnums[current.Enums[i].Name] = &current.Enums[i]
+	}
+	lockedEnums := make(map[string]bool)
+	for i := range l.Enums {
+		le := &l.Enums[i]
+		lockedEnums[le.Name] = true
+		ce, ok := currentEnums[le.Name]
+		switch {
+		case !ok:
+			drift = append(drift, LockDrift{"enum " + le.Name, "removed"})
+		case le.values() != ce.values():
+			drift = append(drift, LockDrift{"enum " + le.Name, fmt.Sprintf("values changed from %s to %s", le.values(), ce.values())})
+		}
+	}
+	for _, ce := range current.Enums {
+		if !lockedEnums[ce.Name] {
+			drift = append(drift, LockDrift{"enum " + ce.Name, "added"})
+		}
+	}
+	return drift
+}
+
+// layout describes a struct's layout on one line, e.g.,
+// "{x: float @0, y: float @4} size 8 align 4".
+func (s *LockStruct) layout() string {
+	var fields []string
+	for _, f := range s.Fields {
+		fields = append(fields, fmt.Sprintf("%s: %s @%d", f.Name, f.Type, f.Offset))
+	}
+	return fmt.Sprintf("{%s} size %d align %d", strings.Join(fields, ", "), s.Size, s.Align)
+}
+
+// values describes an enum's constants on one line, e.g.,
+// "{Color_Red = 0, Color_Green = 1}".
+func (e *LockEnum) values() string {
+	var values []string
+	for _, v := range e.Values {
+		values = append(values, fmt.Sprintf("%s = %d", v.Name, v.Value))
+	}
+	return "{" + strings.Join(values, ", ") + "}"
+}
@@ -0,0 +1,201 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestBuildABILock_MatchesHeader(t *testing.T) {
+	for _, name := range []string{"full.yaml", "async.yaml", "events.yaml", "unions.yaml", "arrays.yaml", "strings.yaml", "buffers.yaml", "optional.yaml", "threading.yaml"} {
+		ctx := loadTestAPI(t, name)
+		files, err := (&CHeaderGenerator{}).Generate(ctx)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		// Join wrapped declarations back onto one line.
+		header := string(files[0].Content)
+		header = strings.ReplaceAll(header, "(\n    ", "(")
+		header = strings.ReplaceAll(header, ",\n    ", ", ")
+		exportMacro := ExportMacroName(ctx.API.API.Name)
+
+		lock := BuildABILock(ctx)
+		if got, want := len(lock.Functions), strings.Count(header, "\n"+exportMacro+" "); got != want {
+			t.Errorf("%s: lock has %d functions, header exports %d", name, got, want)
+		}
+		for i, fn := range lock.Functions {
+			if !strings.Contains(header, exportMacro+" "+fn.Signature+";") {
+				t.Errorf("%s: header does not declare %s", name, fn.Signature)
+			}
+			if i > 0 && lock.Functions[i-1].Name >= fn.Name {
+				t.Errorf("%s: functions not sorted at %s", name, fn.Name)
+			}
+		}
+		for _, e := range lock.Enums {
+			for _, v := range e.Values {
+				if !strings.Contains(header, fmt.Sprintf("%s = %d", v.Name, v.Value)) {
+					t.Errorf("%s: header does not declare %s = %d", name, v.Name, v.Value)
+				}
+			}
+		}
+	}
+}
+
+func TestBuildABILock_Signatures(t *testing.T) {
+	lock := BuildABILock(loadTestAPI(t, "async.yaml"))
+	want := map[string]string{
+		"async_api_assets_load_model_start":  "async_api_async_op async_api_assets_load_model_start(engine_handle engine, const char* path)",
+		"async_api_assets_load_model_poll":   "int32_t async_api_assets_load_model_poll(async_api_async_op op, int32_t* out_error, uint8_t** out_result, uint32_t* out_result_len)",
+		"async_api_lifecycle_destroy_engine": "void async_api_lifecycle_destroy_engine(engine_handle engine)",
+	}
+	for _, fn := range lock.Functions {
+		if sig, ok := want[fn.Name]; ok {
+			if fn.Signature != sig {
+				t.Errorf("%s: got %q, want %q", fn.Name, fn.Signature, sig)
+			}
+			delete(want, fn.Name)
+		}
+	}
+	for name := range want {
+		t.Errorf("missing function %s", name)
+	}
+}
+
+func TestBuildABILock_StructLayouts(t *testing.T) {
+	lock := BuildABILock(loadTestAPI(t, "arrays.yaml"))
+	if len(lock.Structs) != 2 {
+		t.Fatalf("expected 2 structs, got %d", len(lock.Structs))
+	}
+	bounds := lock.Structs[0]
+	if bounds.Name != "Geo_Bounds" || bounds.Size != 56 || bounds.Align != 8 {
+		t.Errorf("unexpected Geo_Bounds layout: %s", bounds.layout())
+	}
+	if got := bounds.layout(); got != "{layer: uint8_t @0, min: double[3] @8, max: double[3] @32} size 56 align 8" {
+		t.Errorf("unexpected Geo_Bounds layout: %s", got)
+	}
+
+	lock = BuildABILock(loadTestAPI(t, "unions.yaml"))
+	var shape *LockStruct
+	for i := range lock.Structs {
+		if lock.Structs[i].Name == "Scene_Shape" {
+			shape = &lock.Structs[i]
+		}
+	}
+	if shape == nil {
+		t.Fatal("expected a Scene_Shape layout")
+	}
+	want := "{type: Scene_Shape_Type @0, value: union { Scene_Rect Rect; } @4, table: const uint8_t* @16, table_len: uint32_t @24} size 32 align 8"
+	if got := shape.layout(); got != want {
+		t.Errorf("unexpected Scene_Shape layout:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestABILock_RoundTrip(t *testing.T) {
+	lock := BuildABILock(loadTestAPI(t, "unions.yaml"))
+	data, err := lock.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "\n      - {name: width, type: float, offset: 0}\n") {
+		t.Errorf("expected one line per struct field, got:\n%s", data)
+	}
+	parsed, err := ParseABILock(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if drift := parsed.Drift(lock); drift != nil {
+		t.Errorf("expected no drift after a round trip, got %v", drift)
+	}
+
+	if _, err := ParseABILock([]byte("api: x\nsymbols: []\n")); err == nil {
+		t.Error("expected error for unknown key")
+	}
+}
+
+func TestABILock_Drift(t *testing.T) {
+	locked := &ABILock{
+		API: "my_api",
+		Functions: []LockFunction{
+			{Name: "my_api_a", Signature: "void my_api_a(void)"},
+			{Name: "my_api_b", Signature: "void my_api_b(int32_t x)"},
+		},
+		Structs: []LockStruct{
+			{Name: "Geo_Vec2", Size: 8, Align: 4, Fields: []LockField{{"x", "float", 0}, {"y", "float", 4}}},
+		},
+	}
+	current := &ABILock{
+		API: "my_api",
+		Functions: []LockFunction{
+			{Name: "my_api_b", Signature: "void my_api_b(int64_t x)"},
+			{Name: "my_api_c", Signature: "void my_api_c(void)"},
+		},
+		Structs: []LockStruct{
+			{Name: "Geo_Vec2", Size: 16, Align: 8, Fields: []LockField{{"x", "double", 0}, {"y", "double", 8}}},
+		},
+	}
+	var got []string
+	for _, d := range locked.Drift(current) {
+		got = append(got, d.String())
+	}
+	want := []string{
+		"function my_api_a: removed",
+		"function my_api_b: signature changed from void my_api_b(int32_t x) to void my_api_b(int64_t x)",
+		"function my_api_c: added",
+		"struct Geo_Vec2: layout changed from {x: float @0, y: float @4} size 8 align 4 to {x: double @0, y: double @8} size 16 align 8",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestBuildABILock_Enums(t *testing.T) {
+	lock := BuildABILock(loadTestAPI(t, "async.yaml"))
+	got := make(map[string]string)
+	for i := range lock.Enums {
+		got[lock.Enums[i].Name] = lock.Enums[i].values()
+	}
+	want := map[string]string{
+		"Common_ErrorCode":       "{Common_ErrorCode_Ok = 0, Common_ErrorCode_InvalidArgument = 1, Common_ErrorCode_OutOfMemory = 2, Common_ErrorCode_NotFound = 3, Common_ErrorCode_InternalError = 4}",
+		"async_api_async_status": "{ASYNC_API_ASYNC_PENDING = 0, ASYNC_API_ASYNC_DONE = 1, ASYNC_API_ASYNC_CANCELLED = 2}",
+	}
+	for name, values := range want {
+		if got[name] != values {
+			t.Errorf("enum %s: got %s, want %s", name, got[name], values)
+		}
+	}
+
+	lock = BuildABILock(loadTestAPI(t, "events.yaml"))
+	for i := range lock.Enums {
+		if lock.Enums[i].Name == "event_api_event_kind" {
+			if got, want := lock.Enums[i].values(), "{EVENT_API_EVENT_NONE = 0, EVENT_API_EVENT_TOUCH = 1, EVENT_API_EVENT_ENTITY_SPAWNED = 2}"; got != want {
+				t.Errorf("event kind: got %s, want %s", got, want)
+			}
+			return
+		}
+	}
+	t.Error("expected an event_api_event_kind enum")
+}
+
+func TestABILock_EnumReorderDrift(t *testing.T) {
+	ctx := loadTestAPI(t, "events.yaml")
+	locked := BuildABILock(ctx)
+
+	// Swap the first two values of an FBS enum, as reordering implicitly
+	// numbered values in the schema does, and the two events.
+	values := ctx.ResolvedTypes["Common.ErrorCode"].EnumValues
+	values[0].Name, values[1].Name = values[1].Name, values[0].Name
+	events := ctx.API.Events
+	events[0], events[1] = events[1], events[0]
+	current := BuildABILock(ctx)
+
+	var subjects []string
+	for _, d := range locked.Drift(current) {
+		subjects = append(subjects, d.Subject)
+	}
+	if got, want := strings.Join(subjects, ", "), "enum Common_ErrorCode, enum event_api_event_kind"; got != want {
+		t.Errorf("got drift in %s, want %s", got, want)
+	}
+	if locked.Hash() == current.Hash() {
+		t.Error("expected a reordered enum to change the hash")
+	}
+}
@@ -0,0 +1,107 @@
+package gen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/benn-herrera/xplatter/model"
+	"github.com/benn-herrera/xplatter/resolver"
+)
+
+// arrayStructs returns the names of the structs with fixed-length array
+// fields, sorted.
+func arrayStructs(resolved resolver.ResolvedTypes) []string {
+	var names []string
+	for name, info := range resolved {
+		if info.Kind != resolver.TypeKindStruct {
+			continue
+		}
+		for _, f := range info.ActiveFields() {
+			if _, _, ok := resolver.ArrayField(f.Type); ok {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeSwiftArrayAccessors writes an extension giving each fixed-length array
+// field of an imported C struct an array view. Swift imports C arrays as
+// tuples, which cannot be indexed or iterated; the setter checks the count.
+func writeSwiftArrayAccessors(b *strings.Builder, typeName string, info *resolver.TypeInfo) {
+	cName := model.FlatBufferCType(typeName)
+	fmt.Fprintf(b, "extension %s {\n", cName)
+	first := true
+	for _, f := range info.ActiveFields() {
+		elem, n, ok := resolver.ArrayField(f.Type)
+		if !ok {
+			continue
+		}
+		elemType := model.FlatBufferCType(elem)
+		if resolver.IsScalarField(elem) {
+			elemType = swiftPrimitiveType(elem)
+		}
+		if !first {
+			b.WriteString("\n")
+		}
+		first = false
+		fmt.Fprintf(b, `    /// %[1]s as an array of %[2]d elements.
+    public var %[3]sArray: [%[4]s] {
+        get {
+            withUnsafeBytes(of: %[1]s) { Array($0.bindMemory(to: %[4]s.self)) }
+        }
+        set {
+            precondition(newValue.count == %[2]d, "%[5]s.%[1]s holds %[2]d elements, got \(newValue.count)")
+            withUnsafeMutableBytes(of: &%[1]s) { dst in
+                newValue.withUnsafeBytes { dst.copyMemory(from: $0) }
+            }
+        }
+    }
+`, f.Name, n, ToCamelCase(f.Name), elemType, cName)
+	}
+	b.WriteString("}\n\n")
+}
+
+// kotlinArrayFieldChecks returns the require() statements checking the sizes
+// of the fixed-length array fields of a data class, if it has any.
+func kotlinArrayFieldChecks(info *resolver.TypeInfo) []string {
+	var checks []string
+	for _, f := range info.ActiveFields() {
+		if _, n, ok := resolver.ArrayField(f.Type); ok {
+			name := ToCamelCase(f.Name)
+			checks = append(checks, fmt.Sprintf("require(%[1]s.size == %[2]d) { \"%[1]s must hold %[2]d elements, got ${%[1]s.size}\" }", name, n))
+		}
+	}
+	return checks
+}
+
+// writeJNIArrayField emits the copy of the fixed-length array field f of the
+// C struct src into a new Java array named j_<field>.
+func writeJNIArrayField(b *strings.Builder, indent string, f resolver.FieldDef, src string) {
+	elem, n, _ := resolver.ArrayField(f.Type)
+	kind := jniArrayKind(elem)
+	fmt.Fprintf(b, "%s%s j_%s = (*env)->New%sArray(env, %d);\n", indent, jniArrayCType(elem), f.Name, kind, n)
+	fmt.Fprintf(b, "%s(*env)->Set%sArrayRegion(env, j_%s, 0, %d, (const %s*)%s.%s);\n", indent, kind, f.Name, n, jniPrimitiveCType(elem), src, f.Name)
+}
+
+// writeJNIGetArrayField emits the copy of the fixed-length array field f of
+// the Kotlin data class obj, whose class is cls, into the C struct dst,
+// through the Java array j_<obj>_<field> the caller declares.
+func writeJNIGetArrayField(b *strings.Builder, indent string, f resolver.FieldDef, obj, cls, dst string) {
+	elem, n, _ := resolver.ArrayField(f.Type)
+	fmt.Fprintf(b, "%sj_%s_%s = (%s)(*env)->GetObjectField(env, %s, (*env)->GetFieldID(env, %s, \"%s\", \"%s\"));\n",
+		indent, obj, f.Name, jniArrayCType(elem), obj, cls, ToCamelCase(f.Name), jniFBSFieldDescriptor(f.Type))
+	fmt.Fprintf(b, "%s(*env)->Get%sArrayRegion(env, j_%s_%s, 0, %d, (%s*)%s.%s);\n", indent, jniArrayKind(elem), obj, f.Name, n, jniPrimitiveCType(elem), dst, f.Name)
+}
+
+// jsArrayFieldExpr returns a JS expression copying a fixed-length array
+// field at offset bytes past _outPtr into a typed array. The copy outlives
+// the out-parameter, which is freed after the call.
+func jsArrayFieldExpr(fieldType string, offset int) string {
+	elem, n, _ := resolver.ArrayField(fieldType)
+	size, _ := wasmFieldSize(elem)
+	return fmt.Sprintf("new %s(_memoryBuffer().slice(_outPtr + %d, _outPtr + %d))", jsTypedArrayName(elem), offset, offset+size*n)
+}
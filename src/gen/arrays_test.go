@@ -0,0 +1,38 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestArrayStructs(t *testing.T) {
+	ctx := loadTestAPI(t, "arrays.yaml")
+	if got := arrayStructs(ctx.ResolvedTypes); strings.Join(got, " ") != "Geo.Bounds Geo.Mat4" {
+		t.Errorf("unexpected array structs %v", got)
+	}
+}
+
+func TestWasmStructLayout_FixedArrays(t *testing.T) {
+	ctx := loadTestAPI(t, "arrays.yaml")
+	size, fields := wasmStructLayout("Geo.Bounds", ctx.ResolvedTypes)
+	// layer: 1 byte, then the double arrays aligned to 8.
+	if size != 56 {
+		t.Errorf("expected size 56, got %d", size)
+	}
+	wantOffsets := []int{0, 8, 32}
+	for i, f := range fields {
+		if f.Offset != wantOffsets[i] {
+			t.Errorf("field %s: expected offset %d, got %d", f.Name, wantOffsets[i], f.Offset)
+		}
+	}
+	if size, align := wasmFieldSize("[float32:16]"); size != 64 || align != 4 {
+		t.Errorf("expected [float32:16] to be 64 bytes aligned to 4, got %d and %d", size, align)
+	}
+}
+
+func TestJSArrayFieldExpr(t *testing.T) {
+	want := "new Float64Array(_memoryBuffer().slice(_outPtr + 8, _outPtr + 32))"
+	if got := jsArrayFieldExpr("[float64:3]", 8); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
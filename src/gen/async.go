@@ -0,0 +1,119 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/benn-herrera/xplatter/model"
+)
+
+// Async operation status values returned by every <method>_poll function.
+const (
+	AsyncStatusPending   = 0
+	AsyncStatusDone      = 1
+	AsyncStatusCancelled = 2
+)
+
+// Poll backoff used by the bindings while an async operation is pending:
+// the delay starts at AsyncPollMinDelayMS and doubles up to AsyncPollMaxDelayMS.
+const (
+	AsyncPollMinDelayMS = 1
+	AsyncPollMaxDelayMS = 16
+)
+
+// AsyncOpTypeName returns the C typedef for an opaque async operation handle.
+// e.g., "hello_xplatter" → "hello_xplatter_async_op"
+func AsyncOpTypeName(apiName string) string {
+	return apiName + "_async_op"
+}
+
+// AsyncStatusTypeName returns the C enum type name for async operation status.
+// e.g., "hello_xplatter" → "hello_xplatter_async_status"
+func AsyncStatusTypeName(apiName string) string {
+	return apiName + "_async_status"
+}
+
+// AsyncStatusConstName returns the C enum constant for an async status.
+// e.g., ("hello_xplatter", "done") → "HELLO_XPLATTER_ASYNC_DONE"
+func AsyncStatusConstName(apiName, status string) string {
+	return UpperSnakeCase(apiName) + "_ASYNC_" + UpperSnakeCase(status)
+}
+
+// AsyncStartFunctionName returns the exported C function that starts an async method.
+func AsyncStartFunctionName(apiName, ifaceName, methodName string) string {
+	return CABIFunctionName(apiName, ifaceName, methodName) + "_start"
+}
+
+// AsyncPollFunctionName returns the exported C function that polls an async operation.
+func AsyncPollFunctionName(apiName, ifaceName, methodName string) string {
+	return CABIFunctionName(apiName, ifaceName, methodName) + "_poll"
+}
+
+// AsyncCancelFunctionName returns the exported C function that cancels an async operation.
+func AsyncCancelFunctionName(apiName, ifaceName, methodName string) string {
+	return CABIFunctionName(apiName, ifaceName, methodName) + "_cancel"
+}
+
+// hasAsyncMethods reports whether any interface method is declared async.
+func hasAsyncMethods(api *model.APIDefinition) bool {
+	for _, iface := range api.Interfaces {
+		for _, method := range iface.Methods {
+			if method.Async {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// asyncPollOutParams returns the C out-parameters of a <method>_poll function:
+// the error code for fallible methods, then the result for methods that return.
+func asyncPollOutParams(method *model.MethodDef) []string {
+	var params []string
+	if method.Error != "" {
+		params = append(params, "int32_t* out_error")
+	}
+	if returnsTable(method) {
+		params = append(params, tableOutParams()...)
+	} else if method.Returns != nil {
+		params = append(params, COutParamType(method.Returns.Type)+" out_result")
+	}
+	return params
+}
+
+// writeAsyncDeclarations emits the shared async section of the public C header:
+// the opaque operation handle and the status enum returned by every poll.
+func writeAsyncDeclarations(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, `/* Async operations — <method>_start returns an operation (NULL if it could not
+ * start); <method>_poll returns its status and, once DONE, the outputs. A DONE or
+ * CANCELLED poll releases the operation. <method>_cancel requests cancellation
+ * and releases the operation; do not use it after a terminal poll. */
+typedef struct %[1]s_s* %[1]s;
+typedef enum {
+    %[2]s = %[5]d,
+    %[3]s = %[6]d,
+    %[4]s = %[7]d
+} %[8]s;
+
+`, AsyncOpTypeName(apiName),
+		AsyncStatusConstName(apiName, "pending"), AsyncStatusConstName(apiName, "done"), AsyncStatusConstName(apiName, "cancelled"),
+		AsyncStatusPending, AsyncStatusDone, AsyncStatusCancelled,
+		AsyncStatusTypeName(apiName))
+}
+
+// asyncCFunctions returns the start/poll/cancel functions that replace the
+// single C function of an async method.
+func asyncCFunctions(apiName, ifaceName string, method *model.MethodDef) []cFunction {
+	opType := AsyncOpTypeName(apiName)
+
+	var params []string
+	for _, p := range method.Parameters {
+		params = append(params, formatCParam(&p)...)
+	}
+	pollParams := append([]string{opType + " op"}, asyncPollOutParams(method)...)
+	return []cFunction{
+		{returnType: opType, name: AsyncStartFunctionName(apiName, ifaceName, method.Name), params: params},
+		{returnType: "int32_t", name: AsyncPollFunctionName(apiName, ifaceName, method.Name), params: pollParams},
+		{returnType: "void", name: AsyncCancelFunctionName(apiName, ifaceName, method.Name), params: []string{opType + " op"}},
+	}
+}
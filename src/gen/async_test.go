@@ -0,0 +1,70 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAsyncNames(t *testing.T) {
+	tests := []struct {
+		got, want string
+	}{
+		{AsyncOpTypeName("async_api"), "async_api_async_op"},
+		{AsyncStatusTypeName("async_api"), "async_api_async_status"},
+		{AsyncStatusConstName("async_api", "pending"), "ASYNC_API_ASYNC_PENDING"},
+		{AsyncStatusConstName("async_api", "cancelled"), "ASYNC_API_ASYNC_CANCELLED"},
+		{AsyncStartFunctionName("async_api", "assets", "load_model"), "async_api_assets_load_model_start"},
+		{AsyncPollFunctionName("async_api", "assets", "load_model"), "async_api_assets_load_model_poll"},
+		{AsyncCancelFunctionName("async_api", "assets", "load_model"), "async_api_assets_load_model_cancel"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+}
+
+func TestHasAsyncMethods(t *testing.T) {
+	if !hasAsyncMethods(loadTestAPI(t, "async.yaml").API) {
+		t.Error("async.yaml declares async methods")
+	}
+	if hasAsyncMethods(loadTestAPI(t, "full.yaml").API) {
+		t.Error("full.yaml declares no async methods")
+	}
+}
+
+func TestAsyncPollOutParams(t *testing.T) {
+	ctx := loadTestAPI(t, "async.yaml")
+	want := map[string]string{
+		"load_model":  "int32_t* out_error, uint8_t** out_result, uint32_t* out_result_len",
+		"decode":      "uint32_t* out_result",
+		"warm_up":     "int32_t* out_error",
+		"fork_engine": "engine_handle* out_result",
+	}
+	for _, iface := range ctx.API.Interfaces {
+		for i := range iface.Methods {
+			m := &iface.Methods[i]
+			if !m.Async {
+				continue
+			}
+			if got := strings.Join(asyncPollOutParams(m), ", "); got != want[m.Name] {
+				t.Errorf("%s: poll out-params = %q, want %q", m.Name, got, want[m.Name])
+			}
+		}
+	}
+}
+
+func TestWriteAsyncDeclarations(t *testing.T) {
+	var b strings.Builder
+	writeAsyncDeclarations(&b, "async_api")
+	content := b.String()
+
+	for _, want := range []string{
+		"typedef struct async_api_async_op_s* async_api_async_op;",
+		"    ASYNC_API_ASYNC_PENDING = 0,\n    ASYNC_API_ASYNC_DONE = 1,\n    ASYNC_API_ASYNC_CANCELLED = 2\n} async_api_async_status;",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("async declarations missing %q", want)
+		}
+	}
+}
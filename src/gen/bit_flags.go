@@ -0,0 +1,229 @@
+package gen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/benn-herrera/xplatter/model"
+	"github.com/benn-herrera/xplatter/resolver"
+)
+
+// bitFlagsEnums returns the names of the (bit_flags) enums, sorted.
+func bitFlagsEnums(resolved resolver.ResolvedTypes) []string {
+	var names []string
+	for name, info := range resolved {
+		if info.Kind == resolver.TypeKindEnum && info.BitFlags {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isEnumType reports whether t names a FlatBuffers enum, bit_flags or not.
+func isEnumType(resolved resolver.ResolvedTypes, t string) bool {
+	info, ok := resolved[t]
+	return ok && info.Kind == resolver.TypeKindEnum
+}
+
+// bitFlagsMask returns every flag of a bit_flags enum set.
+func bitFlagsMask(info *resolver.TypeInfo) int64 {
+	var mask int64
+	for _, v := range info.EnumValues {
+		mask |= v.Value
+	}
+	return mask
+}
+
+// flagConstName converts a flag name to UPPER_SNAKE_CASE, e.g. "HasAudio" →
+// "HAS_AUDIO". Runs of capitals stay together.
+func flagConstName(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && unicode.IsLower(runes[i-1]) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// writeCppBitFlagsOperators writes the bitwise operators that make the C enum
+// of each bit_flags enum usable as a flag set in C++. ~ keeps the result
+// within the declared flags so it stays in the enum's range.
+func writeCppBitFlagsOperators(b *strings.Builder, resolved resolver.ResolvedTypes) {
+	for _, name := range bitFlagsEnums(resolved) {
+		info := resolved[name]
+		cType := model.FlatBufferCType(name)
+		base := model.PrimitiveCType(info.BaseType)
+		fmt.Fprintf(b, "/* %s is a bit_flags enum: combine flags with |, test them with has_flags. */\n", name)
+		for _, op := range []string{"|", "&", "^"} {
+			fmt.Fprintf(b, "inline constexpr %[1]s operator%[3]s(%[1]s a, %[1]s b) { return static_cast<%[1]s>(static_cast<%[2]s>(a) %[3]s static_cast<%[2]s>(b)); }\n", cType, base, op)
+		}
+		fmt.Fprintf(b, "inline constexpr %[1]s operator~(%[1]s a) { return static_cast<%[1]s>(~static_cast<%[2]s>(a) & %[3]s); }\n", cType, base, cppFlagsLiteral(bitFlagsMask(info), info.BaseType))
+		for _, op := range []string{"|", "&", "^"} {
+			fmt.Fprintf(b, "inline %[1]s& operator%[2]s=(%[1]s& a, %[1]s b) { return a = a %[2]s b; }\n", cType, op)
+		}
+		fmt.Fprintf(b, "inline constexpr bool has_flags(%[1]s value, %[1]s flags) { return (value & flags) == flags; }\n\n", cType)
+	}
+}
+
+// cppFlagsLiteral returns a hex literal of a flag mask in the enum's
+// underlying type.
+func cppFlagsLiteral(v int64, baseType string) string {
+	switch baseType {
+	case "uint64", "int64":
+		return fmt.Sprintf("0x%xull", v)
+	case "uint32":
+		return fmt.Sprintf("0x%xu", v)
+	}
+	return fmt.Sprintf("0x%x", v)
+}
+
+// writeRustBitFlags writes a bitflags!-style newtype for a bit_flags enum,
+// which unlike a Rust enum may hold any combination of its flags.
+func writeRustBitFlags(b *strings.Builder, name string, info *resolver.TypeInfo) {
+	rustName := rustFlatBufferType(name)
+	base := rustPrimitiveType(info.BaseType)
+	fmt.Fprintf(b, "#[repr(transparent)]\n#[derive(Debug, Clone, Copy, PartialEq, Eq, Hash, Default)]\npub struct %s(pub %s);\n\n", rustName, base)
+	fmt.Fprintf(b, "impl %s {\n", rustName)
+	for _, val := range info.EnumValues {
+		fmt.Fprintf(b, "    pub const %s: Self = Self(%d);\n", flagConstName(val.Name), val.Value)
+	}
+	fmt.Fprintf(b, `
+    pub const fn empty() -> Self {
+        Self(0)
+    }
+
+    pub const fn all() -> Self {
+        Self(%[2]d)
+    }
+
+    pub const fn bits(self) -> %[1]s {
+        self.0
+    }
+
+    pub const fn is_empty(self) -> bool {
+        self.0 == 0
+    }
+
+    pub const fn contains(self, other: Self) -> bool {
+        self.0 & other.0 == other.0
+    }
+
+    pub const fn intersects(self, other: Self) -> bool {
+        self.0 & other.0 != 0
+    }
+}
+`, base, bitFlagsMask(info))
+	for _, op := range []struct{ trait, method, sym string }{
+		{"BitOr", "bitor", "|"}, {"BitAnd", "bitand", "&"}, {"BitXor", "bitxor", "^"},
+	} {
+		fmt.Fprintf(b, `
+impl std::ops::%[2]s for %[1]s {
+    type Output = Self;
+    fn %[3]s(self, rhs: Self) -> Self {
+        Self(self.0 %[4]s rhs.0)
+    }
+}
+
+impl std::ops::%[2]sAssign for %[1]s {
+    fn %[3]s_assign(&mut self, rhs: Self) {
+        self.0 %[4]s= rhs.0;
+    }
+}
+`, rustName, op.trait, op.method, op.sym)
+	}
+	fmt.Fprintf(b, `
+impl std::ops::Not for %[1]s {
+    type Output = Self;
+    fn not(self) -> Self {
+        Self(!self.0 & Self::all().0)
+    }
+}
+
+`, rustName)
+}
+
+// writeGoBitFlags writes a named type for a bit_flags enum with typed flag
+// constants and a Has method.
+func writeGoBitFlags(b *strings.Builder, name string, info *resolver.TypeInfo) {
+	goName := goEnumPrefix(name)
+	fmt.Fprintf(b, "// %s is a set of %s flags.\n", goName, name)
+	fmt.Fprintf(b, "type %s %s\n\n", goName, primitiveGoType(info.BaseType))
+	b.WriteString("const (\n")
+	for _, val := range info.EnumValues {
+		fmt.Fprintf(b, "\t%s%s %s = %d\n", goName, val.Name, goName, val.Value)
+	}
+	b.WriteString(")\n\n")
+	fmt.Fprintf(b, "// Has reports whether all of flags are set in f.\nfunc (f %s) Has(flags %s) bool {\n\treturn f&flags == flags\n}\n\n", goName, goName)
+}
+
+// writeSwiftBitFlags writes an OptionSet for a bit_flags enum.
+func writeSwiftBitFlags(b *strings.Builder, api *model.APIDefinition, name string, info *resolver.TypeInfo) {
+	swiftName := swiftTypeName(api, name)
+	fmt.Fprintf(b, "public struct %s: OptionSet, Hashable {\n", swiftName)
+	fmt.Fprintf(b, "    public let rawValue: %s\n\n", swiftPrimitiveType(info.BaseType))
+	fmt.Fprintf(b, "    public init(rawValue: %s) {\n        self.rawValue = rawValue\n    }\n\n", swiftPrimitiveType(info.BaseType))
+	for _, val := range info.EnumValues {
+		fmt.Fprintf(b, "    public static let %s = %s(rawValue: %d)\n", ToCamelCase(val.Name), swiftName, val.Value)
+	}
+	b.WriteString("}\n\n")
+}
+
+// writeKotlinBitFlags writes an inline value class for a bit_flags enum.
+// Flags are held in an Int, or a Long for 64-bit enums, as JNI passes them.
+func writeKotlinBitFlags(b *strings.Builder, name string, info *resolver.TypeInfo) {
+	ktName := strings.ReplaceAll(name, ".", "")
+	valueType := "Int"
+	if info.BaseType == "int64" || info.BaseType == "uint64" {
+		valueType = "Long"
+	}
+	fmt.Fprintf(b, `/** A set of %[1]s flags. */
+@JvmInline
+value class %[2]s(val value: %[3]s) {
+    infix fun or(other: %[2]s) = %[2]s(value or other.value)
+    infix fun and(other: %[2]s) = %[2]s(value and other.value)
+    infix fun xor(other: %[2]s) = %[2]s(value xor other.value)
+    operator fun contains(other: %[2]s) = (value and other.value) == other.value
+
+    companion object {
+        val NONE = %[2]s(0)
+`, name, ktName, valueType)
+	for _, val := range info.EnumValues {
+		v := val.Value
+		if valueType == "Int" {
+			v = int64(int32(v)) // bit 31 of a uint32 enum
+		}
+		fmt.Fprintf(b, "        val %s = %s(%d)\n", flagConstName(val.Name), ktName, v)
+	}
+	b.WriteString("    }\n}\n\n")
+}
+
+// writeJSBitFlags writes a frozen bitmask object for each bit_flags enum and
+// returns their names. 64-bit flags are BigInts. Only the enums keep selects
+// are written.
+func writeJSBitFlags(b *strings.Builder, resolved resolver.ResolvedTypes, keep func(string) bool) []string {
+	var names []string
+	for _, name := range bitFlagsEnums(resolved) {
+		if !keep(name) {
+			continue
+		}
+		info := resolved[name]
+		jsName := strings.ReplaceAll(name, ".", "")
+		suffix := ""
+		if info.BaseType == "int64" || info.BaseType == "uint64" {
+			suffix = "n"
+		}
+		fmt.Fprintf(b, "/** %s flags; combine with | and test with &. */\nconst %s = Object.freeze({\n", name, jsName)
+		for _, val := range info.EnumValues {
+			fmt.Fprintf(b, "  %s: %d%s,\n", flagConstName(val.Name), val.Value, suffix)
+		}
+		b.WriteString("});\n\n")
+		names = append(names, jsName)
+	}
+	return names
+}
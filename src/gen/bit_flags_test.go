@@ -0,0 +1,32 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFlagConstName(t *testing.T) {
+	tests := map[string]string{
+		"Read":      "READ",
+		"HasAudio":  "HAS_AUDIO",
+		"HDR":       "HDR",
+		"read_only": "READ_ONLY",
+		"Mode2D":    "MODE2D",
+	}
+	for in, want := range tests {
+		if got := flagConstName(in); got != want {
+			t.Errorf("flagConstName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestBitFlagsEnums(t *testing.T) {
+	ctx := loadTestAPI(t, "flags.yaml")
+	got := bitFlagsEnums(ctx.ResolvedTypes)
+	if strings.Join(got, " ") != "Media.Caps Media.Channels" {
+		t.Errorf("unexpected bit_flags enums %v", got)
+	}
+	if mask := bitFlagsMask(ctx.ResolvedTypes["Media.Caps"]); mask != 0x33 {
+		t.Errorf("expected Caps mask 0x33, got %#x", mask)
+	}
+}
@@ -0,0 +1,108 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/benn-herrera/xplatter/model"
+	"github.com/benn-herrera/xplatter/resolver"
+)
+
+// BufferFreeFunctionName returns the exported C function that releases a buffer
+// returned across the ABI.
+// e.g., "hello_xplatter" → "hello_xplatter_buffer_free"
+func BufferFreeFunctionName(apiName string) string {
+	return apiName + "_buffer_free"
+}
+
+// returnBufferElem returns the element type of a method's buffer<T> return.
+func returnBufferElem(method *model.MethodDef) (string, bool) {
+	if method.Returns == nil {
+		return "", false
+	}
+	return model.IsBuffer(method.Returns.Type)
+}
+
+// hasBufferReturns reports whether any method returns a buffer<T>, a table,
+// or a union with table members, making the buffer free function part of the
+// ABI.
+func hasBufferReturns(api *model.APIDefinition, resolved resolver.ResolvedTypes) bool {
+	for _, iface := range api.Interfaces {
+		for i := range iface.Methods {
+			method := &iface.Methods[i]
+			if _, ok := returnBufferElem(method); ok || returnsTable(method) {
+				return true
+			}
+			if method.Returns != nil && isUnionType(resolved, method.Returns.Type) && unionHasTables(resolved[method.Returns.Type], resolved) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tableOutParams returns the C out-parameters of a table result: the
+// finished FlatBuffer and its size in bytes.
+func tableOutParams() []string {
+	return []string{"uint8_t** out_result", "uint32_t* out_result_len"}
+}
+
+// cResultOutParams returns the C out-parameters that carry a method's result.
+// A buffer<T> result is always passed out as a data pointer plus element count,
+// a table as a FlatBuffer plus its size, and an optional by-value result as
+// the value plus a presence flag, so those methods never return their value
+// directly.
+func cResultOutParams(method *model.MethodDef) []string {
+	retType := method.Returns.Type
+	if method.Returns.Table {
+		return tableOutParams()
+	}
+	if elemType, ok := model.IsBuffer(retType); ok {
+		return []string{
+			model.PrimitiveCType(elemType) + "** out_result",
+			"uint32_t* out_result_len",
+		}
+	}
+	if returnHasPresenceFlag(method) {
+		return []string{COutParamType(retType) + " out_result", "bool* out_has_result"}
+	}
+	return []string{COutParamType(retType) + " out_result"}
+}
+
+// resultPassedOut reports whether an infallible method still passes its
+// result out through out-parameters rather than returning it.
+func resultPassedOut(method *model.MethodDef) bool {
+	if _, ok := returnBufferElem(method); ok || returnsTable(method) {
+		return true
+	}
+	return returnHasPresenceFlag(method)
+}
+
+// cMethodSignature returns the C return type and the trailing result
+// out-parameters for a synchronous method.
+func cMethodSignature(method *model.MethodDef) (returnType string, outParams []string) {
+	hasError := method.Error != ""
+	switch {
+	case method.Returns == nil && hasError:
+		return "int32_t", nil
+	case method.Returns == nil:
+		return "void", nil
+	case hasError:
+		return "int32_t", cResultOutParams(method)
+	}
+	if resultPassedOut(method) {
+		return "void", cResultOutParams(method)
+	}
+	return CReturnType(method.Returns.Type), nil
+}
+
+// writeBufferFreeDeclaration emits the buffer ownership section of the public C header.
+func writeBufferFreeDeclaration(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, `/* Returned buffers — buffer<T> results are passed out as a data pointer and
+ * an element count, and FlatBuffer tables as a finished buffer and its size in
+ * bytes. The data is allocated by the implementation and owned by the caller,
+ * who releases it with %[1]s. An empty buffer may be NULL. */
+%[2]s void %[1]s(void* data);
+
+`, BufferFreeFunctionName(apiName), ExportMacroName(apiName))
+}
@@ -0,0 +1,119 @@
+package gen
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/benn-herrera/xplatter/model"
+)
+
+func TestBufferFreeFunctionName(t *testing.T) {
+	if got := BufferFreeFunctionName("buffers_api"); got != "buffers_api_buffer_free" {
+		t.Errorf("BufferFreeFunctionName = %q", got)
+	}
+}
+
+func TestHasBufferReturns(t *testing.T) {
+	ctx := loadTestAPI(t, "buffers.yaml")
+	if !hasBufferReturns(ctx.API, ctx.ResolvedTypes) {
+		t.Error("buffers.yaml returns buffers")
+	}
+	ctx = loadTestAPI(t, "minimal.yaml")
+	if hasBufferReturns(ctx.API, ctx.ResolvedTypes) {
+		t.Error("minimal.yaml returns no buffers")
+	}
+	ctx = loadTestAPI(t, "async.yaml")
+	if !hasBufferReturns(ctx.API, ctx.ResolvedTypes) {
+		t.Error("async.yaml returns a table")
+	}
+	ctx = loadTestAPI(t, "unions.yaml")
+	if !hasBufferReturns(ctx.API, ctx.ResolvedTypes) {
+		t.Error("unions.yaml returns a union with table members")
+	}
+}
+
+func TestCMethodSignature(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     model.MethodDef
+		wantReturn string
+		wantOut    []string
+	}{
+		{
+			name:       "infallible void",
+			method:     model.MethodDef{},
+			wantReturn: "void",
+		},
+		{
+			name:       "fallible void",
+			method:     model.MethodDef{Error: "Common.ErrorCode"},
+			wantReturn: "int32_t",
+		},
+		{
+			name:       "infallible primitive",
+			method:     model.MethodDef{Returns: &model.ReturnDef{Type: "int32"}},
+			wantReturn: "int32_t",
+		},
+		{
+			name:       "fallible primitive",
+			method:     model.MethodDef{Returns: &model.ReturnDef{Type: "int32"}, Error: "Common.ErrorCode"},
+			wantReturn: "int32_t",
+			wantOut:    []string{"int32_t* out_result"},
+		},
+		{
+			name:       "infallible buffer",
+			method:     model.MethodDef{Returns: &model.ReturnDef{Type: "buffer<float32>"}},
+			wantReturn: "void",
+			wantOut:    []string{"float** out_result", "uint32_t* out_result_len"},
+		},
+		{
+			name:       "fallible buffer",
+			method:     model.MethodDef{Returns: &model.ReturnDef{Type: "buffer<uint8>"}, Error: "Common.ErrorCode"},
+			wantReturn: "int32_t",
+			wantOut:    []string{"uint8_t** out_result", "uint32_t* out_result_len"},
+		},
+		{
+			name:       "infallible optional primitive",
+			method:     model.MethodDef{Returns: &model.ReturnDef{Type: "uint32", Optional: true}},
+			wantReturn: "void",
+			wantOut:    []string{"uint32_t* out_result", "bool* out_has_result"},
+		},
+		{
+			name:       "infallible table",
+			method:     model.MethodDef{Returns: &model.ReturnDef{Type: "Common.EntityId", Table: true}},
+			wantReturn: "void",
+			wantOut:    []string{"uint8_t** out_result", "uint32_t* out_result_len"},
+		},
+		{
+			name:       "fallible optional table",
+			method:     model.MethodDef{Returns: &model.ReturnDef{Type: "Common.EntityId", Optional: true, Table: true}, Error: "Common.ErrorCode"},
+			wantReturn: "int32_t",
+			wantOut:    []string{"uint8_t** out_result", "uint32_t* out_result_len"},
+		},
+		{
+			name:       "infallible optional string",
+			method:     model.MethodDef{Returns: &model.ReturnDef{Type: "string", Optional: true}},
+			wantReturn: "char*",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotReturn, gotOut := cMethodSignature(&tt.method)
+			if gotReturn != tt.wantReturn {
+				t.Errorf("return type = %q, want %q", gotReturn, tt.wantReturn)
+			}
+			if !reflect.DeepEqual(gotOut, tt.wantOut) {
+				t.Errorf("out params = %q, want %q", gotOut, tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestWriteBufferFreeDeclaration(t *testing.T) {
+	var b strings.Builder
+	writeBufferFreeDeclaration(&b, "buffers_api")
+	if !strings.Contains(b.String(), "BUFFERS_API_EXPORT void buffers_api_buffer_free(void* data);") {
+		t.Errorf("missing buffer free declaration:\n%s", b.String())
+	}
+}
@@ -37,6 +37,9 @@ func (g *CHeaderGenerator) Generate(ctx *Context) ([]*OutputFile, error) {
 
 	// Symbol visibility export macro
 	writeExportMacro(&b, apiName)
+	if hasDeprecations(api) {
+		writeDeprecatedMacro(&b, apiName)
+	}
 
 	b.WriteString(`#ifdef __cplusplus
 extern "C" {
@@ -48,6 +51,15 @@ extern "C" {
 	if len(api.Handles) > 0 {
 		for _, h := range api.Handles {
 			snake := model.HandleToSnake(h.Name)
+			// Deprecating the typedef itself would warn on every include, since
+			// the header's own declarations use it.
+			writeCLifecycleComment(&b, "", &h.Lifecycle, h.Deprecated, HandleTypedefName)
+			if parent, ok := api.ParentHandleName(h.Name); ok {
+				// A derived handle may be cast to its parent handle type and passed to
+				// the base interface's functions.
+				fmt.Fprintf(&b, "typedef struct %s_s* %s_handle; /* extends %s */\n", snake, snake, HandleTypedefName(parent))
+				continue
+			}
 			fmt.Fprintf(&b, "typedef struct %s_s* %s_handle;\n", snake, snake)
 		}
 		b.WriteString("\n")
@@ -61,6 +73,29 @@ extern "C" {
 	// Platform services
 	writePlatformServices(&b, apiName)
 
+	// ABI handshake
+	writeABIInfoDeclaration(&b, apiName)
+
+	// Returned string ownership
+	if hasStringReturns(api) {
+		writeStringFreeDeclaration(&b, apiName)
+	}
+
+	// Returned buffer ownership
+	if hasBufferReturns(api, ctx.ResolvedTypes) {
+		writeBufferFreeDeclaration(&b, apiName)
+	}
+
+	// Async operations
+	if hasAsyncMethods(api) {
+		writeAsyncDeclarations(&b, apiName)
+	}
+
+	// Events
+	if len(api.Events) > 0 {
+		writeEventDeclarations(&b, apiName, api.Events)
+	}
+
 	// Interfaces
 	exportMacro := ExportMacroName(apiName)
 	for _, iface := range api.Interfaces {
@@ -71,7 +106,7 @@ extern "C" {
 		}
 		// Auto-generated destructor (when constructors are present)
 		if handleName, ok := iface.ConstructorHandleName(); ok {
-			destructor := SyntheticDestructor(handleName)
+			destructor := threadAffinityDestructor(api, &iface, handleName)
 			writeMethodSignature(&b, apiName, iface.Name, &destructor, exportMacro)
 		}
 		// Regular methods
@@ -114,6 +149,42 @@ func writeExportMacro(b *strings.Builder, apiName string) {
 `, exportMacro, buildMacro)
 }
 
+// writeDeprecatedMacro emits the macro that marks deprecated functions. It is
+// only defined if not already, so a binding that must call deprecated functions
+// (such as the JNI bridge) can define it empty before including the header.
+func writeDeprecatedMacro(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, `/* Deprecation warnings */
+#ifndef %[1]s
+  #if defined(_MSC_VER)
+    #define %[1]s(msg) __declspec(deprecated(msg))
+  #elif defined(__GNUC__) || defined(__clang__)
+    #define %[1]s(msg) __attribute__((deprecated(msg)))
+  #else
+    #define %[1]s(msg)
+  #endif
+#endif
+
+`, DeprecatedMacroName(apiName))
+}
+
+// writeCLifecycleComment writes a comment line noting an element's since and
+// stability annotations, and its deprecation when d is non-nil.
+func writeCLifecycleComment(b *strings.Builder, indent string, l *model.Lifecycle, d *model.Deprecation, name func(string) string) {
+	var notes []string
+	if l.Since != "" {
+		notes = append(notes, "Since "+l.Since+".")
+	}
+	if l.IsExperimental() {
+		notes = append(notes, experimentalNote)
+	}
+	if d != nil {
+		notes = append(notes, "Deprecated: "+deprecationText(d, name))
+	}
+	if len(notes) > 0 {
+		fmt.Fprintf(b, "%s/* %s */\n", indent, strings.Join(notes, " "))
+	}
+}
+
 func writePlatformServices(b *strings.Builder, apiName string) {
 	fmt.Fprintf(b, `/* Platform services — implement these per platform */
 void %[1]s_log_sink(int32_t level, const char* tag, const char* message);
@@ -127,9 +198,45 @@ int32_t  %[1]s_resource_read(const char* name, uint8_t* buffer, uint32_t buffer_
 }
 
 func writeMethodSignature(b *strings.Builder, apiName, ifaceName string, method *model.MethodDef, exportMacro string) {
+	// The deprecation itself is an attribute on the declaration that follows
+	// (the start function for async methods), so only since and stability
+	// need a comment.
+	writeCLifecycleComment(b, "", &method.Lifecycle, nil, nil)
+	writeCThreadNote(b, "", method)
+	writeCOwnershipNotes(b, "", method)
+	if method.Deprecated != nil {
+		replacement := func(name string) string { return CABIFunctionName(apiName, ifaceName, name) }
+		fmt.Fprintf(b, "%s(%s)\n", DeprecatedMacroName(apiName), quoteLiteral(deprecationText(method.Deprecated, replacement)))
+	}
+	for _, fn := range methodCFunctions(apiName, ifaceName, method) {
+		writeCSignature(b, exportMacro, fn.returnType, fn.name, fn.params)
+	}
+}
+
+// cFunction is the C declaration of an exported function.
+type cFunction struct {
+	returnType string
+	name       string
+	params     []string
+}
+
+// signature returns the declaration on one line, without export macro or
+// semicolon, e.g., "int32_t my_api_renderer_draw(renderer_handle renderer)".
+func (fn *cFunction) signature() string {
+	params := strings.Join(fn.params, ", ")
+	if params == "" {
+		params = "void"
+	}
+	return fmt.Sprintf("%s %s(%s)", fn.returnType, fn.name, params)
+}
+
+// methodCFunctions returns the C functions a method exports: its own, or the
+// start, poll and cancel functions of an async method.
+func methodCFunctions(apiName, ifaceName string, method *model.MethodDef) []cFunction {
+	if method.Async {
+		return asyncCFunctions(apiName, ifaceName, method)
+	}
 	funcName := CABIFunctionName(apiName, ifaceName, method.Name)
-	hasError := method.Error != ""
-	hasReturn := method.Returns != nil
 
 	// Build parameter list
 	var params []string
@@ -137,25 +244,16 @@ func writeMethodSignature(b *strings.Builder, apiName, ifaceName string, method
 		params = append(params, formatCParam(&p)...)
 	}
 
-	// Determine return type and out-parameter
-	var returnType string
-	switch {
-	case hasError && hasReturn:
-		// Fallible with return: returns error code, return value as out-param
-		returnType = "int32_t"
-		params = append(params, COutParamType(method.Returns.Type)+" out_result")
-	case hasError && !hasReturn:
-		// Fallible without return: returns error code
-		returnType = "int32_t"
-	case !hasError && hasReturn:
-		// Infallible with return: returns the value directly
-		returnType = CReturnType(method.Returns.Type)
-	default:
-		// Infallible without return: void
-		returnType = "void"
-	}
+	// Fallible methods return an error code and pass their result out;
+	// infallible methods return it directly (buffers are always passed out).
+	returnType, outParams := cMethodSignature(method)
+	params = append(params, outParams...)
+	return []cFunction{{returnType: returnType, name: funcName, params: params}}
+}
 
-	// Format the function signature
+// writeCSignature writes a C function declaration, wrapping one parameter per
+// line when the single-line form exceeds 80 columns.
+func writeCSignature(b *strings.Builder, exportMacro, returnType, funcName string, params []string) {
 	paramStr := strings.Join(params, ", ")
 	if paramStr == "" {
 		paramStr = "void"
@@ -179,10 +277,19 @@ func writeMethodSignature(b *strings.Builder, apiName, ifaceName string, method
 }
 
 // formatCParam formats a parameter as one or more C parameter strings.
-// buffer<T> expands to two parameters (data pointer + length).
+// buffer<T> expands to two parameters (data pointer + length), as does a table
+// (its FlatBuffer + size in bytes), and an optional primitive to a presence
+// flag followed by the value.
 func formatCParam(p *model.ParameterDef) []string {
+	name := cIdent(p.Name)
+	if p.Table {
+		return []string{"const uint8_t* " + name, "uint32_t " + p.Name + "_len"}
+	}
+	if hasPresenceFlag(p) {
+		return []string{"bool " + PresenceFlagName(p.Name), model.PrimitiveCType(p.Type) + " " + name}
+	}
 	if model.IsString(p.Type) {
-		return []string{"const char* " + p.Name}
+		return []string{"const char* " + name}
 	}
 
 	if elemType, ok := model.IsBuffer(p.Type); ok {
@@ -194,34 +301,39 @@ func formatCParam(p *model.ParameterDef) []string {
 			ptrType = "const " + cType + "*"
 		}
 		return []string{
-			ptrType + " " + p.Name,
+			ptrType + " " + name,
 			"uint32_t " + p.Name + "_len",
 		}
 	}
 
 	if handleName, ok := model.IsHandle(p.Type); ok {
-		return []string{HandleTypedefName(handleName) + " " + p.Name}
+		return []string{HandleTypedefName(handleName) + " " + name}
 	}
 
 	if model.IsPrimitive(p.Type) {
-		return []string{model.PrimitiveCType(p.Type) + " " + p.Name}
+		return []string{model.PrimitiveCType(p.Type) + " " + name}
 	}
 
 	// FlatBuffer type
 	cType := model.FlatBufferCType(p.Type)
 	transfer := p.Transfer
 	if transfer == "ref_mut" {
-		return []string{cType + "* " + p.Name}
+		return []string{cType + "* " + name}
 	}
 	if transfer == "ref" {
-		return []string{"const " + cType + "* " + p.Name}
+		return []string{"const " + cType + "* " + name}
 	}
-	return []string{cType + " " + p.Name}
+	return []string{cType + " " + name}
 }
 
 // writeCStructFields writes C struct field declarations for FBS fields.
 func writeCStructFields(b *strings.Builder, fields []resolver.FieldDef) {
 	for _, f := range fields {
+		if elem, n, ok := resolver.ArrayField(f.Type); ok {
+			cType, _ := fbsFieldToCType(resolver.FieldDef{Name: f.Name, Type: elem})
+			fmt.Fprintf(b, "    %s %s[%d];\n", cType, f.Name, n)
+			continue
+		}
 		cType, extraField := fbsFieldToCType(f)
 		fmt.Fprintf(b, "    %s %s;\n", cType, f.Name)
 		if extraField != "" {
@@ -232,6 +344,7 @@ func writeCStructFields(b *strings.Builder, fields []resolver.FieldDef) {
 
 // fbsFieldToCType maps an FBS field type to a C type.
 // Returns the C type and an optional extra field declaration (for vectors).
+// Fixed-length arrays are declared by writeCStructFields.
 func fbsFieldToCType(f resolver.FieldDef) (cType string, extraField string) {
 	t := f.Type
 	switch t {
@@ -49,6 +49,21 @@ func loadTestAPI(t *testing.T, name string) *Context {
 	return NewContext(def, types, "", path)
 }
 
+func TestNewContext_ReachableTypes(t *testing.T) {
+	ctx := loadTestAPI(t, "minimal.yaml")
+	if _, ok := ctx.ResolvedTypes["Common.ErrorCode"]; !ok {
+		t.Error("expected the error type to be reachable")
+	}
+	for _, name := range []string{"Common.LogLevel", "Geometry.Transform3D"} {
+		if _, ok := ctx.ResolvedTypes[name]; ok {
+			t.Errorf("expected unreferenced %s to be dropped", name)
+		}
+		if _, ok := ctx.SchemaTypes[name]; !ok {
+			t.Errorf("expected %s to stay in the schema types", name)
+		}
+	}
+}
+
 func TestCHeaderGenerator_Minimal(t *testing.T) {
 	ctx := loadTestAPI(t, "minimal.yaml")
 	gen := &CHeaderGenerator{}
@@ -220,3 +235,370 @@ func TestCHeaderGenerator_InfallibleVoid(t *testing.T) {
 		t.Error("missing or incorrect destroy_engine signature (void)")
 	}
 }
+
+func TestCHeaderGenerator_Events(t *testing.T) {
+	ctx := loadTestAPI(t, "events.yaml")
+	gen := &CHeaderGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"    EVENT_API_EVENT_NONE = 0,\n    EVENT_API_EVENT_TOUCH = 1,\n    EVENT_API_EVENT_ENTITY_SPAWNED = 2\n} event_api_event_kind;",
+		"EVENT_API_EXPORT int32_t event_api_event_poll(uint32_t* out_kind, uint8_t* buffer, uint32_t buffer_size);",
+		"EVENT_API_EXPORT int32_t event_api_event_signal_fd(void);",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("header missing %q", want)
+		}
+	}
+
+	// Push functions are implementation-side only
+	if strings.Contains(content, "event_api_event_push") {
+		t.Error("public header should not declare push functions")
+	}
+}
+
+func TestCHeaderGenerator_NoEvents(t *testing.T) {
+	ctx := loadTestAPI(t, "minimal.yaml")
+	gen := &CHeaderGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if strings.Contains(string(files[0].Content), "_event_poll") {
+		t.Error("event functions should only be declared when events are present")
+	}
+}
+
+func TestCHeaderGenerator_Async(t *testing.T) {
+	ctx := loadTestAPI(t, "async.yaml")
+	gen := &CHeaderGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"typedef struct async_api_async_op_s* async_api_async_op;",
+		"} async_api_async_status;",
+		"ASYNC_API_EXPORT async_api_async_op async_api_assets_load_model_start(\n    engine_handle engine,\n    const char* path);",
+		"ASYNC_API_EXPORT int32_t async_api_assets_load_model_poll(\n    async_api_async_op op,\n    int32_t* out_error,\n    uint8_t** out_result,\n    uint32_t* out_result_len);",
+		"ASYNC_API_EXPORT void async_api_assets_load_model_cancel(async_api_async_op op);",
+		"ASYNC_API_EXPORT int32_t async_api_assets_fork_engine_poll(\n    async_api_async_op op,\n    engine_handle* out_result);",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("header missing %q", want)
+		}
+	}
+
+	// An async method has no synchronous entry point
+	if strings.Contains(content, "async_api_assets_load_model(") {
+		t.Error("async method should not declare a synchronous function")
+	}
+
+	// The async section is only emitted when needed
+	ctx = loadTestAPI(t, "minimal.yaml")
+	files, err = gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if strings.Contains(string(files[0].Content), "_async_op") {
+		t.Error("async declarations should only be emitted with async methods")
+	}
+}
+
+func TestCHeaderGenerator_StringReturns(t *testing.T) {
+	ctx := loadTestAPI(t, "strings.yaml")
+	gen := &CHeaderGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"STRINGS_API_EXPORT void strings_api_string_free(char* str);",
+		"STRINGS_API_EXPORT char* strings_api_text_get_name(engine_handle engine);",
+		"    const char* key,\n    char** out_result);",
+		"    int32_t* out_error,\n    char** out_result);",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("header missing %q", want)
+		}
+	}
+
+	ctx = loadTestAPI(t, "minimal.yaml")
+	files, err = gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if strings.Contains(string(files[0].Content), "_string_free") {
+		t.Error("string free should only be declared when strings are returned")
+	}
+}
+
+func TestCHeaderGenerator_BufferReturns(t *testing.T) {
+	ctx := loadTestAPI(t, "buffers.yaml")
+	gen := &CHeaderGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"BUFFERS_API_EXPORT void buffers_api_buffer_free(void* data);",
+		"BUFFERS_API_EXPORT int32_t buffers_api_media_encode_frame(\n    engine_handle engine,\n    const uint8_t* pixels,\n    uint32_t pixels_len,\n    uint8_t** out_result,\n    uint32_t* out_result_len);",
+		"BUFFERS_API_EXPORT void buffers_api_media_read_tensor(\n    engine_handle engine,\n    float** out_result,\n    uint32_t* out_result_len);",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("header missing %q", want)
+		}
+	}
+
+	ctx = loadTestAPI(t, "minimal.yaml")
+	files, err = gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if strings.Contains(string(files[0].Content), "_buffer_free") {
+		t.Error("buffer free should only be declared when buffers are returned")
+	}
+}
+
+func TestCHeaderGenerator_Optionals(t *testing.T) {
+	ctx := loadTestAPI(t, "optional.yaml")
+	gen := &CHeaderGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"    engine_handle engine,\n    const char* label);",
+		"    bool has_limit,\n    uint32_t limit);",
+		"    engine_handle parent,\n    const uint8_t* config,\n    uint32_t config_len);",
+		"OPTIONAL_API_EXPORT char* optional_api_scene_get_label(engine_handle engine);",
+		"OPTIONAL_API_EXPORT void optional_api_scene_get_limit(\n    engine_handle engine,\n    uint32_t* out_result,\n    bool* out_has_result);",
+		"OPTIONAL_API_EXPORT int32_t optional_api_scene_get_config(\n    engine_handle engine,\n    uint8_t** out_result,\n    uint32_t* out_result_len);",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("header missing %q", want)
+		}
+	}
+}
+
+func TestCHeaderGenerator_Extends(t *testing.T) {
+	ctx := loadTestAPI(t, "extends.yaml")
+	gen := &CHeaderGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"typedef struct node_s* node_handle;\n",
+		"typedef struct texture_s* texture_handle; /* extends node_handle */",
+		"typedef struct mesh_s* mesh_handle; /* extends node_handle */",
+		"EXTENDS_API_EXPORT void extends_api_node_set_name(\n    node_handle node,",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("header missing %q", want)
+		}
+	}
+	if strings.Contains(content, "extends_api_texture_set_name") {
+		t.Error("inherited methods should not be duplicated per derived interface")
+	}
+}
+
+func TestCHeaderGenerator_Lifecycle(t *testing.T) {
+	ctx := loadTestAPI(t, "lifecycle.yaml")
+	gen := &CHeaderGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"#ifndef LIFECYCLE_API_DEPRECATED\n",
+		"#define LIFECYCLE_API_DEPRECATED(msg) __attribute__((deprecated(msg)))",
+		"/* Since 1.0.0. Deprecated: Sprites are drawn as textured quads now. Use quad_handle instead. */\ntypedef struct sprite_s* sprite_handle;",
+		"/* Experimental: May change or be removed without a deprecation period. */\ntypedef struct probe_s* probe_handle;",
+		"LIFECYCLE_API_DEPRECATED(\"Pass options explicitly. Use lifecycle_api_lifecycle_create_engine instead.\")\nLIFECYCLE_API_EXPORT int32_t lifecycle_api_lifecycle_create_engine_legacy(",
+		"/* Since 1.0.0. */\nLIFECYCLE_API_DEPRECATED(\"Quads replace sprites. Use lifecycle_api_renderer_draw_quad instead.\")\nLIFECYCLE_API_EXPORT void lifecycle_api_renderer_draw_sprite(",
+		"/* Since 1.2.0. */\nLIFECYCLE_API_EXPORT void lifecycle_api_renderer_draw_quad(",
+		// Interface annotations are inherited by its methods.
+		"/* Since 1.0.0. */\nLIFECYCLE_API_DEPRECATED(\"The legacy \\\"immediate\\\" path is retired\")\nLIFECYCLE_API_EXPORT void lifecycle_api_legacy_flush(",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("header missing %q", want)
+		}
+	}
+	if strings.Contains(content, "DEPRECATED(\"Sprites") {
+		t.Error("handle typedefs should not carry the deprecated attribute")
+	}
+}
+
+func TestCHeaderGenerator_ThreadAffinity(t *testing.T) {
+	ctx := loadTestAPI(t, "threading.yaml")
+	gen := &CHeaderGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"/* Must be called on the main thread. */\nTHREADING_API_EXPORT void threading_api_window_destroy_window(",
+		"/* May be called from any thread. */\nTHREADING_API_EXPORT float threading_api_window_content_scale(",
+		"/* Must be called on the thread that created the Session. */\nTHREADING_API_EXPORT threading_api_async_op threading_api_session_fetch_start(",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("header missing %q", want)
+		}
+	}
+}
+
+func TestCHeaderGenerator_NoDeprecatedMacroWithoutDeprecations(t *testing.T) {
+	ctx := loadTestAPI(t, "minimal.yaml")
+	files, err := (&CHeaderGenerator{}).Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if strings.Contains(string(files[0].Content), "_DEPRECATED") {
+		t.Error("deprecation macro should only be emitted when something is deprecated")
+	}
+}
+
+func TestCHeaderGenerator_Ownership(t *testing.T) {
+	ctx := loadTestAPI(t, "ownership.yaml")
+	gen := &CHeaderGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"/* Takes ownership of node, even when the call fails; do not use or destroy it afterwards. */\nOWNERSHIP_API_EXPORT int32_t ownership_api_scene_attach(",
+		"/* The returned Node is borrowed from its owner; do not destroy it or use it after the owner is destroyed. */\nOWNERSHIP_API_EXPORT node_handle ownership_api_scene_root_node(",
+		"/* Takes ownership of texture; do not use or destroy it afterwards. */\nOWNERSHIP_API_EXPORT void ownership_api_texture_consume(",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("header missing %q", want)
+		}
+	}
+}
+
+func TestCHeaderGenerator_Tables(t *testing.T) {
+	ctx := loadTestAPI(t, "fields.yaml")
+	gen := &CHeaderGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(findOutputFile(t, files, "fields_api.h").Content)
+
+	for _, want := range []string{
+		"FIELDS_API_EXPORT int32_t fields_api_stream_create_stream(\n    const uint8_t* config,\n    uint32_t config_len,\n    stream_handle* out_result);",
+		"    stream_handle stream,\n    const uint8_t* config,\n    uint32_t config_len);",
+		"FIELDS_API_EXPORT void fields_api_stream_info(\n    stream_handle stream,\n    uint8_t** out_result,\n    uint32_t* out_result_len);",
+		"FIELDS_API_EXPORT void fields_api_buffer_free(void* data);",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("header missing %q", want)
+		}
+	}
+	// Tables cross in wire format, so they have no C definition.
+	for _, table := range []string{"Fields_StreamConfig", "Fields_StreamInfo"} {
+		if strings.Contains(content, table) {
+			t.Errorf("table %s should not have a C typedef", table)
+		}
+	}
+}
+
+func TestCHeaderGenerator_BitFlags(t *testing.T) {
+	ctx := loadTestAPI(t, "flags.yaml")
+	gen := &CHeaderGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(findOutputFile(t, files, "flags_api.h").Content)
+
+	want := "typedef enum {\n    Media_Caps_Read = 1,\n    Media_Caps_Write = 2,\n    Media_Caps_HasAudio = 16,\n    Media_Caps_HDR = 32\n} Media_Caps;"
+	if !strings.Contains(content, want) {
+		t.Errorf("header missing %q", want)
+	}
+}
+
+func TestCHeaderGenerator_Unions(t *testing.T) {
+	ctx := loadTestAPI(t, "unions.yaml")
+	gen := &CHeaderGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"typedef enum {\n    Scene_Shape_NONE = 0,\n    Scene_Shape_Circle = 1,\n    Scene_Shape_Rect = 2,\n    Scene_Shape_Caption = 3\n} Scene_Shape_Type;\n",
+		"typedef struct Scene_Shape {\n    Scene_Shape_Type type;\n    union {\n        Scene_Rect Rect;\n    } value;\n    const uint8_t* table;\n    uint32_t table_len;\n} Scene_Shape;\n",
+		"    Scene_Shape shape);",
+		"Scene_Shape unions_api_canvas_last_shape(canvas_handle canvas);",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("header missing %q", want)
+		}
+	}
+	// Struct members must be defined before the union that holds them by value.
+	if strings.Index(content, "} Scene_Rect;") > strings.Index(content, "} Scene_Shape;") {
+		t.Error("expected member types before the union")
+	}
+	if strings.Contains(content, "Scene_Label") {
+		t.Error("table members should not have a C typedef")
+	}
+}
+
+func TestCHeaderGenerator_FixedArrays(t *testing.T) {
+	ctx := loadTestAPI(t, "arrays.yaml")
+	gen := &CHeaderGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"typedef struct Geo_Mat4 {\n    float m[16];\n} Geo_Mat4;\n",
+		"    uint8_t layer;\n    double min[3];\n    double max[3];\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("header missing %q", want)
+		}
+	}
+	if strings.Contains(content, "m_count") {
+		t.Error("fixed-length arrays should not have a count field")
+	}
+}
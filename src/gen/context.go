@@ -10,7 +10,8 @@ import (
 // Context holds everything a generator needs to produce output.
 type Context struct {
 	API           *model.APIDefinition
-	ResolvedTypes resolver.ResolvedTypes
+	ResolvedTypes resolver.ResolvedTypes // Types reachable from the API surface
+	SchemaTypes   resolver.ResolvedTypes // Every type in the schemas, for per-file flatc output
 	OutputDir     string
 	APIDefPath    string    // Path to the API definition YAML (for Makefile codegen step)
 	Version       string    // xplatter version (e.g. "v0.1.1-6-g27008c1")
@@ -21,10 +22,16 @@ type Context struct {
 
 // NewContext creates a new generation context. Timestamp is captured once so
 // all files produced in the same run share an identical header timestamp.
+// Methods inherit their interface's lifecycle annotations and their resolved
+// thread affinity in ctx.API, and table parameters and results are marked,
+// so generators only need to look at the method. ResolvedTypes keeps only the
+// types the API reaches, so shared schemas full of unrelated types do not
+// bloat the generated code.
 func NewContext(api *model.APIDefinition, resolvedTypes resolver.ResolvedTypes, outputDir string, apiDefPath string) *Context {
 	return &Context{
-		API:           api,
-		ResolvedTypes: resolvedTypes,
+		API:           markTables(inheritThreadAffinity(inheritLifecycle(api)), resolvedTypes),
+		ResolvedTypes: resolver.Reachable(resolvedTypes, api.FlatBufferTypeRefs()),
+		SchemaTypes:   resolvedTypes,
 		OutputDir:     outputDir,
 		APIDefPath:    apiDefPath,
 		Timestamp:     time.Now(),
@@ -9,7 +9,7 @@ import (
 	"github.com/benn-herrera/xplatter/resolver"
 )
 
-// WriteCTypedefs emits minimal C type definitions (handles, enums, structs, tables)
+// WriteCTypedefs emits minimal C type definitions (handles, enums, structs, unions)
 // suitable for embedding in a cgo preamble or other non-header contexts.
 // Unlike the full C header, this omits include guards, export macros, platform
 // services, and function declarations.
@@ -29,23 +29,25 @@ func WriteCTypedefs(b *strings.Builder, handles []model.HandleDef, resolved reso
 	}
 }
 
-// writeFBSTypedefs emits C type definitions for all FlatBuffer types.
-// Enums first, then structs, then tables.
+// writeFBSTypedefs emits C type definitions for the FlatBuffer types that
+// cross the C ABI as C values. Enums first, then structs, then unions, which
+// hold their member structs by value. Tables cross in wire format and have no
+// C definition.
 func writeFBSTypedefs(b *strings.Builder, resolved resolver.ResolvedTypes) {
-	var enumNames, structNames, tableNames []string
+	var enumNames, structNames, unionNames []string
 	for name, info := range resolved {
 		switch info.Kind {
 		case resolver.TypeKindEnum:
 			enumNames = append(enumNames, name)
 		case resolver.TypeKindStruct:
 			structNames = append(structNames, name)
-		case resolver.TypeKindTable:
-			tableNames = append(tableNames, name)
+		case resolver.TypeKindUnion:
+			unionNames = append(unionNames, name)
 		}
 	}
 	sort.Strings(enumNames)
 	sort.Strings(structNames)
-	sort.Strings(tableNames)
+	sort.Strings(unionNames)
 
 	for _, name := range enumNames {
 		info := resolved[name]
@@ -65,15 +67,48 @@ func writeFBSTypedefs(b *strings.Builder, resolved resolver.ResolvedTypes) {
 		info := resolved[name]
 		cName := model.FlatBufferCType(name)
 		fmt.Fprintf(b, "typedef struct %s {\n", cName)
-		writeCStructFields(b, info.Fields)
+		writeCStructFields(b, info.ActiveFields())
 		fmt.Fprintf(b, "} %s;\n\n", cName)
 	}
 
-	for _, name := range tableNames {
-		info := resolved[name]
-		cName := model.FlatBufferCType(name)
-		fmt.Fprintf(b, "typedef struct %s {\n", cName)
-		writeCStructFields(b, info.Fields)
-		fmt.Fprintf(b, "} %s;\n\n", cName)
+	for _, name := range unionNames {
+		writeCUnionTypedef(b, name, resolved[name], resolved)
+	}
+}
+
+// unionTagCType returns the C enum type of a union's type tag,
+// e.g., "Scene.Shape" → "Scene_Shape_Type".
+func unionTagCType(unionName string) string {
+	return model.FlatBufferCType(unionName) + "_Type"
+}
+
+// writeCUnionTypedef emits a union as a tagged pair: the type tag enum, with
+// NONE = 0 as in the FlatBuffers wire format, and a struct holding the tag
+// and the member value. Each struct member is a field of the inner C union
+// named after the member; a table member is held in wire format in the table
+// and table_len fields.
+func writeCUnionTypedef(b *strings.Builder, name string, info *resolver.TypeInfo, resolved resolver.ResolvedTypes) {
+	cName := model.FlatBufferCType(name)
+	tagName := unionTagCType(name)
+	fmt.Fprintf(b, "typedef enum {\n    %s_NONE = 0", cName)
+	for _, m := range info.Members {
+		fmt.Fprintf(b, ",\n    %s_%s = %d", cName, m.Name, m.Value)
+	}
+	fmt.Fprintf(b, "\n} %s;\n\n", tagName)
+
+	fmt.Fprintf(b, "typedef struct %s {\n", cName)
+	fmt.Fprintf(b, "    %s type;\n", tagName)
+	if unionHasStructs(info, resolved) {
+		b.WriteString("    union {\n")
+		for _, m := range info.Members {
+			if !isTableType(resolved, m.Type) {
+				fmt.Fprintf(b, "        %s %s;\n", model.FlatBufferCType(m.Type), m.Name)
+			}
+		}
+		b.WriteString("    } value;\n")
+	}
+	if unionHasTables(info, resolved) {
+		b.WriteString("    const uint8_t* table;\n    uint32_t table_len;\n")
 	}
+	fmt.Fprintf(b, "} %s;\n\n", cName)
 }
@@ -0,0 +1,400 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/benn-herrera/xplatter/model"
+)
+
+// EventRingCapacity is the default byte capacity of the implementation → binding
+// event ring buffer. Must be a power of two.
+const EventRingCapacity = 65536
+
+// EventKindTypeName returns the C enum type name for event kinds.
+// e.g., "hello_xplatter" → "hello_xplatter_event_kind"
+func EventKindTypeName(apiName string) string {
+	return apiName + "_event_kind"
+}
+
+// EventKindConstName returns the C enum constant for an event kind.
+// e.g., ("hello_xplatter", "touch") → "HELLO_XPLATTER_EVENT_TOUCH"
+func EventKindConstName(apiName, eventName string) string {
+	return UpperSnakeCase(apiName) + "_EVENT_" + UpperSnakeCase(eventName)
+}
+
+// EventPollFunctionName returns the exported C function that drains one event.
+func EventPollFunctionName(apiName string) string {
+	return apiName + "_event_poll"
+}
+
+// EventSignalFDFunctionName returns the exported C function that exposes the
+// signal file descriptor bindings wait on.
+func EventSignalFDFunctionName(apiName string) string {
+	return apiName + "_event_signal_fd"
+}
+
+// EventPushFunctionName returns the implementation-side push helper for an event.
+// An empty eventName yields the untyped push function.
+func EventPushFunctionName(apiName, eventName string) string {
+	if eventName == "" {
+		return apiName + "_event_push"
+	}
+	return apiName + "_event_push_" + eventName
+}
+
+// EventKindValue returns the wire value of the event at index i. Zero is
+// reserved for "no event", so kinds are numbered from 1 in declaration order.
+func EventKindValue(i int) int {
+	return i + 1
+}
+
+// writeEventDeclarations emits the binding-facing event section of the public C header:
+// the kind enum and the exported poll/signal functions.
+func writeEventDeclarations(b *strings.Builder, apiName string, events []model.EventDef) {
+	exportMacro := ExportMacroName(apiName)
+	fmt.Fprintf(b, "/* Events — implementation → binding ring buffer */\n")
+	b.WriteString("typedef enum {\n")
+	fmt.Fprintf(b, "    %s_EVENT_NONE = 0", UpperSnakeCase(apiName))
+	for i, ev := range events {
+		fmt.Fprintf(b, ",\n    %s = %d", EventKindConstName(apiName, ev.Name), EventKindValue(i))
+	}
+	fmt.Fprintf(b, "\n} %s;\n\n", EventKindTypeName(apiName))
+	fmt.Fprintf(b, `/* Pops one event. Returns the payload size and sets *out_kind, or 0 with
+ * *out_kind == %[1]s_EVENT_NONE when empty. Returns -(required size) and leaves
+ * the event queued when buffer_size is too small. */
+%[2]s int32_t %[3]s(uint32_t* out_kind, uint8_t* buffer, uint32_t buffer_size);
+/* Readable descriptor signaled on push, or -1 where the platform has none. */
+%[2]s int32_t %[4]s(void);
+
+`, UpperSnakeCase(apiName), exportMacro, EventPollFunctionName(apiName), EventSignalFDFunctionName(apiName))
+}
+
+// generateCEventsHeader produces <api>_events.h, the implementation-side event
+// ring for C and C++ implementations. The ring itself is compiled once, in the
+// translation unit that defines <API>_EVENTS_IMPLEMENTATION before including it.
+func generateCEventsHeader(api *model.APIDefinition, apiName string) *OutputFile {
+	upper := UpperSnakeCase(apiName)
+	var b strings.Builder
+
+	fmt.Fprintf(&b, `#ifndef %[1]s_EVENTS_H
+#define %[1]s_EVENTS_H
+
+#include "%[2]s.h"
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+/* Push helpers — payloads are FlatBuffer-serialized event tables.
+ * Single producer: call from one implementation thread at a time.
+ * Return 0 on success, -1 when the ring is full (the event is dropped). */
+int32_t %[3]s(uint32_t kind, const uint8_t* data, uint32_t size);
+`, upper, apiName, EventPushFunctionName(apiName, ""))
+	for _, ev := range api.Events {
+		fmt.Fprintf(&b, "int32_t %s(const uint8_t* data, uint32_t size); /* %s */\n",
+			EventPushFunctionName(apiName, ev.Name), ev.Type)
+	}
+
+	fmt.Fprintf(&b, `
+/* Signaling — define %[1]s_EVENT_CUSTOM_SIGNAL and implement these to replace
+ * the built-in eventfd (Linux/Android) or pipe (other POSIX) signaling. */
+int32_t %[2]s_event_signal_open(void);
+void    %[2]s_event_signal_notify(void);
+void    %[2]s_event_signal_clear(void);
+
+#ifdef __cplusplus
+}
+#endif
+
+#ifdef %[1]s_EVENTS_IMPLEMENTATION
+
+#include <string.h>
+
+#ifndef %[1]s_EVENT_RING_CAPACITY
+#define %[1]s_EVENT_RING_CAPACITY %[3]du /* bytes, power of two */
+#endif
+
+#ifdef __cplusplus
+#include <atomic>
+typedef std::atomic<uint32_t> %[2]s_event_index;
+#define %[1]s_EVENT_LOAD(a)     (a).load(std::memory_order_acquire)
+#define %[1]s_EVENT_STORE(a, v) (a).store((v), std::memory_order_release)
+#else
+#include <stdatomic.h>
+typedef _Atomic uint32_t %[2]s_event_index;
+#define %[1]s_EVENT_LOAD(a)     atomic_load_explicit(&(a), memory_order_acquire)
+#define %[1]s_EVENT_STORE(a, v) atomic_store_explicit(&(a), (v), memory_order_release)
+#endif
+
+/* Records are [kind:u32][size:u32][payload padded to 8 bytes]. head and tail
+ * are free-running byte counters; only the producer writes head and only the
+ * consumer writes tail. */
+static struct {
+    %[2]s_event_index head;
+    %[2]s_event_index tail;
+    uint8_t data[%[1]s_EVENT_RING_CAPACITY];
+} %[2]s_event_ring;
+
+static uint32_t %[2]s_event_record_size(uint32_t payload_size) {
+    return 8u + ((payload_size + 7u) & ~7u);
+}
+
+static void %[2]s_event_copy_in(uint32_t pos, const void* src, uint32_t n) {
+    uint32_t off = pos & (%[1]s_EVENT_RING_CAPACITY - 1u);
+    uint32_t first = %[1]s_EVENT_RING_CAPACITY - off;
+    if (first > n) first = n;
+    memcpy(%[2]s_event_ring.data + off, src, first);
+    memcpy(%[2]s_event_ring.data, (const uint8_t*)src + first, n - first);
+}
+
+static void %[2]s_event_copy_out(uint32_t pos, void* dst, uint32_t n) {
+    uint32_t off = pos & (%[1]s_EVENT_RING_CAPACITY - 1u);
+    uint32_t first = %[1]s_EVENT_RING_CAPACITY - off;
+    if (first > n) first = n;
+    memcpy(dst, %[2]s_event_ring.data + off, first);
+    memcpy((uint8_t*)dst + first, %[2]s_event_ring.data, n - first);
+}
+
+int32_t %[4]s(uint32_t kind, const uint8_t* data, uint32_t size) {
+    uint32_t head = %[1]s_EVENT_LOAD(%[2]s_event_ring.head);
+    uint32_t tail = %[1]s_EVENT_LOAD(%[2]s_event_ring.tail);
+    uint32_t need = %[2]s_event_record_size(size);
+    if (need > %[1]s_EVENT_RING_CAPACITY - (head - tail)) {
+        return -1;
+    }
+    uint32_t header[2] = { kind, size };
+    %[2]s_event_copy_in(head, header, sizeof(header));
+    if (size > 0) {
+        %[2]s_event_copy_in(head + 8u, data, size);
+    }
+    %[1]s_EVENT_STORE(%[2]s_event_ring.head, head + need);
+    %[2]s_event_signal_notify();
+    return 0;
+}
+
+`, upper, apiName, EventRingCapacity, EventPushFunctionName(apiName, ""))
+
+	for _, ev := range api.Events {
+		fmt.Fprintf(&b, `int32_t %s(const uint8_t* data, uint32_t size) {
+    return %s(%s, data, size);
+}
+
+`, EventPushFunctionName(apiName, ev.Name), EventPushFunctionName(apiName, ""), EventKindConstName(apiName, ev.Name))
+	}
+
+	fmt.Fprintf(&b, `%[3]s int32_t %[4]s(uint32_t* out_kind, uint8_t* buffer, uint32_t buffer_size) {
+    uint32_t tail = %[1]s_EVENT_LOAD(%[2]s_event_ring.tail);
+    uint32_t head = %[1]s_EVENT_LOAD(%[2]s_event_ring.head);
+    if (head == tail) {
+        /* Clear before the re-check so a concurrent push re-signals. */
+        %[2]s_event_signal_clear();
+        head = %[1]s_EVENT_LOAD(%[2]s_event_ring.head);
+        if (head == tail) {
+            *out_kind = %[1]s_EVENT_NONE;
+            return 0;
+        }
+    }
+    uint32_t header[2];
+    %[2]s_event_copy_out(tail, header, sizeof(header));
+    *out_kind = header[0];
+    if (header[1] > buffer_size) {
+        return -(int32_t)header[1];
+    }
+    if (header[1] > 0) {
+        %[2]s_event_copy_out(tail + 8u, buffer, header[1]);
+    }
+    %[1]s_EVENT_STORE(%[2]s_event_ring.tail, tail + %[2]s_event_record_size(header[1]));
+    return (int32_t)header[1];
+}
+
+%[3]s int32_t %[5]s(void) {
+    return %[2]s_event_signal_open();
+}
+
+#ifndef %[1]s_EVENT_CUSTOM_SIGNAL
+#if defined(__linux__) && !defined(__EMSCRIPTEN__)
+#include <pthread.h>
+#include <sys/eventfd.h>
+#include <unistd.h>
+
+static int %[2]s_event_fd = -1;
+static pthread_once_t %[2]s_event_fd_once = PTHREAD_ONCE_INIT;
+
+static void %[2]s_event_fd_init(void) {
+    %[2]s_event_fd = eventfd(0, EFD_NONBLOCK | EFD_CLOEXEC);
+}
+
+int32_t %[2]s_event_signal_open(void) {
+    pthread_once(&%[2]s_event_fd_once, %[2]s_event_fd_init);
+    return %[2]s_event_fd;
+}
+
+void %[2]s_event_signal_notify(void) {
+    uint64_t one = 1;
+    if (%[2]s_event_signal_open() >= 0) {
+        (void)!write(%[2]s_event_fd, &one, sizeof(one));
+    }
+}
+
+void %[2]s_event_signal_clear(void) {
+    uint64_t count;
+    if (%[2]s_event_signal_open() >= 0) {
+        (void)!read(%[2]s_event_fd, &count, sizeof(count));
+    }
+}
+#elif (defined(__unix__) || defined(__APPLE__)) && !defined(__EMSCRIPTEN__)
+#include <fcntl.h>
+#include <pthread.h>
+#include <unistd.h>
+
+static int %[2]s_event_pipe[2] = { -1, -1 };
+static pthread_once_t %[2]s_event_pipe_once = PTHREAD_ONCE_INIT;
+
+static void %[2]s_event_pipe_init(void) {
+    if (pipe(%[2]s_event_pipe) == 0) {
+        for (int i = 0; i < 2; i++) {
+            fcntl(%[2]s_event_pipe[i], F_SETFL, fcntl(%[2]s_event_pipe[i], F_GETFL) | O_NONBLOCK);
+            fcntl(%[2]s_event_pipe[i], F_SETFD, FD_CLOEXEC);
+        }
+    }
+}
+
+int32_t %[2]s_event_signal_open(void) {
+    pthread_once(&%[2]s_event_pipe_once, %[2]s_event_pipe_init);
+    return %[2]s_event_pipe[0];
+}
+
+void %[2]s_event_signal_notify(void) {
+    uint8_t one = 1;
+    if (%[2]s_event_signal_open() >= 0) {
+        (void)!write(%[2]s_event_pipe[1], &one, 1);
+    }
+}
+
+void %[2]s_event_signal_clear(void) {
+    uint8_t drain[64];
+    if (%[2]s_event_signal_open() >= 0) {
+        while (read(%[2]s_event_pipe[0], drain, sizeof(drain)) > 0) {
+        }
+    }
+}
+#else
+/* No descriptor-based signaling (Windows, WASM) — bindings poll instead. */
+int32_t %[2]s_event_signal_open(void) { return -1; }
+void %[2]s_event_signal_notify(void) { }
+void %[2]s_event_signal_clear(void) { }
+#endif
+#endif /* %[1]s_EVENT_CUSTOM_SIGNAL */
+
+#endif /* %[1]s_EVENTS_IMPLEMENTATION */
+
+#endif
+`, upper, apiName, ExportMacroName(apiName), EventPollFunctionName(apiName), EventSignalFDFunctionName(apiName))
+
+	return &OutputFile{Path: apiName + "_events.h", Content: []byte(b.String())}
+}
+
+// generateCEventsTest produces <api>_events_test.c, a standalone program that
+// exercises the ring and its signaling: ordering, wrap-around, full-ring and
+// short-buffer handling, and descriptor readiness where the platform has one.
+func generateCEventsTest(api *model.APIDefinition, apiName string) *OutputFile {
+	upper := UpperSnakeCase(apiName)
+	first := api.Events[0]
+	var b strings.Builder
+
+	fmt.Fprintf(&b, `#define %[1]s_EVENTS_IMPLEMENTATION
+#include "%[2]s_events.h"
+
+#include <stdio.h>
+#include <stdlib.h>
+#if defined(__unix__) || defined(__APPLE__)
+#include <poll.h>
+#endif
+
+static int failures = 0;
+
+#define EXPECT(cond) do { \
+    if (!(cond)) { \
+        fprintf(stderr, "%%s:%%d: expectation failed: %%s\n", __FILE__, __LINE__, #cond); \
+        failures++; \
+    } \
+} while (0)
+
+static int signal_ready(void) {
+#if defined(__unix__) || defined(__APPLE__)
+    int fd = %[3]s();
+    if (fd < 0) {
+        return -1;
+    }
+    struct pollfd p = { fd, POLLIN, 0 };
+    return poll(&p, 1, 0) == 1 && (p.revents & POLLIN) ? 1 : 0;
+#else
+    return -1;
+#endif
+}
+
+int main(void) {
+    uint8_t payload[256];
+    uint8_t out[256];
+    uint32_t kind = 0;
+
+    for (int i = 0; i < (int)sizeof(payload); i++) {
+        payload[i] = (uint8_t)i;
+    }
+
+    /* Empty ring */
+    EXPECT(%[4]s(&kind, out, sizeof(out)) == 0);
+    EXPECT(kind == %[1]s_EVENT_NONE);
+    EXPECT(signal_ready() != 1);
+
+    /* Round trip, FIFO order, and signaling */
+    EXPECT(%[5]s(payload, 3) == 0);
+    EXPECT(%[6]s(7, payload + 3, 5) == 0);
+    EXPECT(signal_ready() != 0);
+    EXPECT(%[4]s(&kind, out, sizeof(out)) == 3);
+    EXPECT(kind == %[7]s);
+    EXPECT(out[0] == 0 && out[2] == 2);
+    EXPECT(%[4]s(&kind, out, sizeof(out)) == 5);
+    EXPECT(kind == 7 && out[0] == 3);
+    EXPECT(%[4]s(&kind, out, sizeof(out)) == 0);
+    EXPECT(signal_ready() != 1);
+
+    /* Short buffer leaves the event queued */
+    EXPECT(%[5]s(payload, 100) == 0);
+    EXPECT(%[4]s(&kind, out, 10) == -100);
+    EXPECT(%[4]s(&kind, out, sizeof(out)) == 100);
+
+    /* Wrap-around: cycle well past capacity */
+    for (uint32_t i = 0; i < (%[1]s_EVENT_RING_CAPACITY / 64u) * 3u; i++) {
+        payload[0] = (uint8_t)i;
+        EXPECT(%[5]s(payload, 61) == 0);
+        EXPECT(%[4]s(&kind, out, sizeof(out)) == 61);
+        EXPECT(out[0] == (uint8_t)i && out[60] == 60);
+    }
+
+    /* Full ring rejects pushes without corrupting queued events */
+    uint32_t pushed = 0;
+    while (%[5]s(payload, 248) == 0) {
+        pushed++;
+    }
+    EXPECT(pushed == %[1]s_EVENT_RING_CAPACITY / 256u);
+    while (%[4]s(&kind, out, sizeof(out)) == 248) {
+        pushed--;
+    }
+    EXPECT(pushed == 0);
+    EXPECT(signal_ready() != 1);
+
+    if (failures > 0) {
+        fprintf(stderr, "%%d event ring expectation(s) failed\n", failures);
+        return EXIT_FAILURE;
+    }
+    printf("event ring: ok\n");
+    return EXIT_SUCCESS;
+}
+`, upper, apiName, EventSignalFDFunctionName(apiName), EventPollFunctionName(apiName),
+		EventPushFunctionName(apiName, first.Name), EventPushFunctionName(apiName, ""),
+		EventKindConstName(apiName, first.Name))
+
+	return &OutputFile{Path: apiName + "_events_test.c", Content: []byte(b.String())}
+}
@@ -0,0 +1,90 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+// findOutputFile returns the generated file with the given path, failing the test if absent.
+func findOutputFile(t *testing.T, files []*OutputFile, path string) *OutputFile {
+	t.Helper()
+	for _, f := range files {
+		if f.Path == path {
+			return f
+		}
+	}
+	t.Fatalf("missing output file %s", path)
+	return nil
+}
+
+func TestEventNames(t *testing.T) {
+	tests := []struct {
+		got, want string
+	}{
+		{EventKindTypeName("event_api"), "event_api_event_kind"},
+		{EventKindConstName("event_api", "entity_spawned"), "EVENT_API_EVENT_ENTITY_SPAWNED"},
+		{EventPollFunctionName("event_api"), "event_api_event_poll"},
+		{EventSignalFDFunctionName("event_api"), "event_api_event_signal_fd"},
+		{EventPushFunctionName("event_api", ""), "event_api_event_push"},
+		{EventPushFunctionName("event_api", "touch"), "event_api_event_push_touch"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+	if EventKindValue(0) != 1 {
+		t.Errorf("first event kind = %d, want 1 (0 is reserved)", EventKindValue(0))
+	}
+}
+
+func TestGenerateCEventsHeader(t *testing.T) {
+	ctx := loadTestAPI(t, "events.yaml")
+	content := string(generateCEventsHeader(ctx.API, "event_api").Content)
+
+	for _, want := range []string{
+		"#ifndef EVENT_API_EVENTS_H",
+		`#include "event_api.h"`,
+		"int32_t event_api_event_push(uint32_t kind, const uint8_t* data, uint32_t size);",
+		"int32_t event_api_event_push_touch(const uint8_t* data, uint32_t size); /* Input.TouchEvent */",
+		"int32_t event_api_event_push_entity_spawned(const uint8_t* data, uint32_t size);",
+		"#ifdef EVENT_API_EVENTS_IMPLEMENTATION",
+		"#define EVENT_API_EVENT_RING_CAPACITY 65536u",
+		"std::atomic<uint32_t>",
+		"_Atomic uint32_t",
+		"return event_api_event_push(EVENT_API_EVENT_TOUCH, data, size);",
+		"EVENT_API_EXPORT int32_t event_api_event_poll(uint32_t* out_kind, uint8_t* buffer, uint32_t buffer_size) {",
+		"EVENT_API_EXPORT int32_t event_api_event_signal_fd(void) {",
+		"#ifndef EVENT_API_EVENT_CUSTOM_SIGNAL",
+		"eventfd(0, EFD_NONBLOCK | EFD_CLOEXEC)",
+		"pipe(event_api_event_pipe)",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("events header missing %q", want)
+		}
+	}
+
+	// The signal must be cleared before re-checking for an empty ring.
+	if !strings.Contains(content, "event_api_event_signal_clear();\n        head =") {
+		t.Error("poll must clear the signal and re-check head when empty")
+	}
+}
+
+func TestGenerateCEventsTest(t *testing.T) {
+	ctx := loadTestAPI(t, "events.yaml")
+	content := string(generateCEventsTest(ctx.API, "event_api").Content)
+
+	for _, want := range []string{
+		"#define EVENT_API_EVENTS_IMPLEMENTATION",
+		`#include "event_api_events.h"`,
+		"int main(void) {",
+		"event_api_event_push_touch(payload, 3)",
+		"EXPECT(kind == EVENT_API_EVENT_TOUCH);",
+		"event_api_event_poll(&kind, out, 10) == -100",
+		"EXPECT(pushed == EVENT_API_EVENT_RING_CAPACITY / 256u);",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("events test missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package gen
+
+import "github.com/benn-herrera/xplatter/model"
+
+// isBaseHandle reports whether another handle extends handleName, i.e. it is
+// the receiver of an interface that some other interface extends.
+func isBaseHandle(api *model.APIDefinition, handleName string) bool {
+	for _, h := range api.Handles {
+		if parent, ok := api.ParentHandleName(h.Name); ok && parent == handleName {
+			return true
+		}
+	}
+	return false
+}
+
+// isBaseInterface reports whether another interface extends iface.
+func isBaseInterface(api *model.APIDefinition, iface *model.InterfaceDef) bool {
+	for _, other := range api.Interfaces {
+		if other.Extends == iface.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// handlesBaseFirst returns the API's handles ordered so that every handle
+// follows the handle it extends, for languages that need a base class
+// declared before its subclasses. Declaration order is otherwise kept.
+func handlesBaseFirst(api *model.APIDefinition) []model.HandleDef {
+	var ordered []model.HandleDef
+	placed := make(map[string]bool)
+	var place func(h model.HandleDef)
+	place = func(h model.HandleDef) {
+		if placed[h.Name] {
+			return
+		}
+		placed[h.Name] = true
+		if parent, ok := api.ParentHandleName(h.Name); ok {
+			if ph := api.HandleByName(parent); ph != nil {
+				place(*ph)
+			}
+		}
+		ordered = append(ordered, h)
+	}
+	for _, h := range api.Handles {
+		place(h)
+	}
+	return ordered
+}
+
+// interfacesBaseFirst returns the API's interfaces ordered so that every
+// interface follows the interface it extends.
+func interfacesBaseFirst(api *model.APIDefinition) []*model.InterfaceDef {
+	var ordered []*model.InterfaceDef
+	placed := make(map[string]bool)
+	var place func(iface *model.InterfaceDef)
+	place = func(iface *model.InterfaceDef) {
+		if placed[iface.Name] {
+			return
+		}
+		placed[iface.Name] = true
+		if base := api.BaseInterface(iface); base != nil {
+			place(base)
+		}
+		ordered = append(ordered, iface)
+	}
+	for i := range api.Interfaces {
+		place(&api.Interfaces[i])
+	}
+	return ordered
+}
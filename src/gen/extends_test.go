@@ -0,0 +1,60 @@
+package gen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/benn-herrera/xplatter/model"
+)
+
+func TestIsBaseHandle(t *testing.T) {
+	ctx := loadTestAPI(t, "extends.yaml")
+	for name, want := range map[string]bool{"Node": true, "Texture": false, "Mesh": false, "Engine": false} {
+		if got := isBaseHandle(ctx.API, name); got != want {
+			t.Errorf("isBaseHandle(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestIsBaseInterface(t *testing.T) {
+	ctx := loadTestAPI(t, "extends.yaml")
+	for i := range ctx.API.Interfaces {
+		iface := &ctx.API.Interfaces[i]
+		if got, want := isBaseInterface(ctx.API, iface), iface.Name == "node"; got != want {
+			t.Errorf("isBaseInterface(%q) = %v, want %v", iface.Name, got, want)
+		}
+	}
+}
+
+func TestHandlesBaseFirst(t *testing.T) {
+	api := &model.APIDefinition{
+		Handles: []model.HandleDef{{Name: "Texture"}, {Name: "Engine"}, {Name: "Node"}},
+		Interfaces: []model.InterfaceDef{
+			{
+				Name:    "texture",
+				Extends: "node",
+				Methods: []model.MethodDef{{Name: "get_width", Parameters: []model.ParameterDef{{Name: "texture", Type: "handle:Texture"}}}},
+			},
+			{
+				Name:    "node",
+				Methods: []model.MethodDef{{Name: "set_name", Parameters: []model.ParameterDef{{Name: "node", Type: "handle:Node"}}}},
+			},
+		},
+	}
+
+	var handles []string
+	for _, h := range handlesBaseFirst(api) {
+		handles = append(handles, h.Name)
+	}
+	if want := []string{"Node", "Texture", "Engine"}; !reflect.DeepEqual(handles, want) {
+		t.Errorf("handlesBaseFirst = %v, want %v", handles, want)
+	}
+
+	var ifaces []string
+	for _, iface := range interfacesBaseFirst(api) {
+		ifaces = append(ifaces, iface.Name)
+	}
+	if want := []string{"node", "texture"}; !reflect.DeepEqual(ifaces, want) {
+		t.Errorf("interfacesBaseFirst = %v, want %v", ifaces, want)
+	}
+}
@@ -0,0 +1,51 @@
+package gen
+
+import (
+	"strings"
+
+	"github.com/benn-herrera/xplatter/model"
+	"github.com/benn-herrera/xplatter/resolver"
+)
+
+// paramFlatBufferTypes returns the structs passed as parameters or returned
+// by the API's methods, in order of first use.
+func paramFlatBufferTypes(api *model.APIDefinition, resolved resolver.ResolvedTypes) []string {
+	seen := map[string]bool{}
+	var names []string
+	add := func(t string) {
+		info, ok := resolved[t]
+		if !ok || seen[t] || info.Kind != resolver.TypeKindStruct {
+			return
+		}
+		seen[t] = true
+		names = append(names, t)
+	}
+	for _, iface := range api.Interfaces {
+		for _, methods := range [][]model.MethodDef{iface.Constructors, iface.Methods} {
+			for _, method := range methods {
+				for _, p := range method.Parameters {
+					add(p.Type)
+				}
+				if method.Returns != nil {
+					add(method.Returns.Type)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// fieldEnumType returns the qualified name of the enum a field of typeName
+// refers to, if any. Field types are written relative to the namespace of
+// the type declaring them.
+func fieldEnumType(resolved resolver.ResolvedTypes, typeName string, f resolver.FieldDef) (string, bool) {
+	scope := ""
+	if i := strings.LastIndex(typeName, "."); i >= 0 {
+		scope = typeName[:i]
+	}
+	info, name, ok := resolver.LookupType(resolved, scope, f.Type)
+	if !ok || info.Kind != resolver.TypeKindEnum {
+		return "", false
+	}
+	return name, true
+}
@@ -0,0 +1,20 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParamFlatBufferTypes(t *testing.T) {
+	ctx := loadTestAPI(t, "arrays.yaml")
+	got := paramFlatBufferTypes(ctx.API, ctx.ResolvedTypes)
+	if strings.Join(got, " ") != "Geo.Mat4 Geo.Bounds" {
+		t.Errorf("unexpected types %v", got)
+	}
+
+	// Tables are built with flatc, not as plain objects
+	fields := loadTestAPI(t, "fields.yaml")
+	if got := paramFlatBufferTypes(fields.API, fields.ResolvedTypes); len(got) != 0 {
+		t.Errorf("expected no struct types, got %v", got)
+	}
+}
@@ -9,14 +9,16 @@ import (
 
 // FlatcConfig holds configuration for running the flatc compiler.
 type FlatcConfig struct {
-	FlatcPath string   // resolved flatc binary path
-	FBSFiles  []string // absolute paths to .fbs files
-	OutputDir string   // base output directory
-	Targets   []string // effective target list
-	ImplLang  string   // impl_lang value
-	DryRun    bool
-	Verbose   bool
-	Quiet     bool
+	FlatcPath   string   // resolved flatc binary path
+	FBSFiles    []string // absolute paths to .fbs files
+	IncludeDirs []string // directories flatc searches for included .fbs files
+	OutputDir   string   // base output directory
+	Targets     []string // effective target list
+	ImplLang    string   // impl_lang value
+	GoModule    string   // import path of the flatc Go output, for --go-module-name
+	DryRun      bool
+	Verbose     bool
+	Quiet       bool
 }
 
 // flatcLang pairs a flatc flag with its output subdirectory.
@@ -81,6 +83,19 @@ func collectFlatcLangs(targets []string, implLang string) []flatcLang {
 	return langs
 }
 
+// flatcArgs returns the flatc command line for one language.
+func flatcArgs(cfg *FlatcConfig, lang flatcLang, outDir string) []string {
+	args := []string{lang.Flag, "-o", outDir}
+	if lang.Flag == "--go" && cfg.GoModule != "" {
+		// Packages of other namespaces are imported by module path.
+		args = append(args, "--go-module-name", cfg.GoModule)
+	}
+	for _, dir := range cfg.IncludeDirs {
+		args = append(args, "-I", dir)
+	}
+	return append(args, cfg.FBSFiles...)
+}
+
 // RunFlatc invokes flatc once per required language, writing output into
 // <outputDir>/flatbuffers/<subdir>/. Returns the number of flatc invocations run.
 func RunFlatc(cfg *FlatcConfig) (int, error) {
@@ -92,8 +107,7 @@ func RunFlatc(cfg *FlatcConfig) (int, error) {
 	for _, lang := range langs {
 		outDir := filepath.Join(cfg.OutputDir, lang.Subdir)
 
-		args := []string{lang.Flag, "-o", outDir}
-		args = append(args, cfg.FBSFiles...)
+		args := flatcArgs(cfg, lang, outDir)
 
 		if cfg.DryRun {
 			fmt.Printf("  Would run: %s %s\n", cfg.FlatcPath, strings.Join(args, " "))
@@ -1,6 +1,7 @@
 package gen
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -164,3 +165,32 @@ func TestRunFlatc_NoFBSFiles(t *testing.T) {
 func (cfg *FlatcConfig) run() (int, error) {
 	return RunFlatc(cfg)
 }
+
+func TestFlatcArgs_IncludeDirs(t *testing.T) {
+	cfg := &FlatcConfig{
+		FBSFiles:    []string{"/schemas/base.fbs", "/schemas/app.fbs"},
+		IncludeDirs: []string{"/schemas", "/shared"},
+	}
+	got := strings.Join(flatcArgs(cfg, flatcLang{"--cpp", "flatbuffers/cpp"}, "/out/flatbuffers/cpp"), " ")
+	want := "--cpp -o /out/flatbuffers/cpp -I /schemas -I /shared /schemas/base.fbs /schemas/app.fbs"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestFlatcArgs_GoModule(t *testing.T) {
+	cfg := &FlatcConfig{
+		FBSFiles: []string{"/schemas/app.fbs"},
+		GoModule: GoFlatcModule("fields_api"),
+	}
+	got := strings.Join(flatcArgs(cfg, flatcLang{"--go", "flatbuffers/go"}, "/out/flatbuffers/go"), " ")
+	want := "--go -o /out/flatbuffers/go --go-module-name fields-api/generated/flatbuffers/go /schemas/app.fbs"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	got = strings.Join(flatcArgs(cfg, flatcLang{"--cpp", "flatbuffers/cpp"}, "/out/flatbuffers/cpp"), " ")
+	if strings.Contains(got, "--go-module-name") {
+		t.Errorf("--go-module-name passed to flatc --cpp: %q", got)
+	}
+}
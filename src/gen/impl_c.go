@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/benn-herrera/xplatter/model"
+	"github.com/benn-herrera/xplatter/resolver"
 )
 
 func init() {
@@ -14,6 +15,10 @@ func init() {
 // ImplCGenerator produces a C implementation scaffold:
 //   - A stub .c file that includes the generated header and provides
 //     TODO-marked function bodies for every exported API function.
+//   - A generated <api>_abi_info.c defining the ABI info function, which the
+//     build compiles next to the stub.
+//   - When events are declared, a generated <api>_events.h ring buffer and a
+//     standalone <api>_events_test.c exercising it.
 type ImplCGenerator struct{}
 
 func (g *ImplCGenerator) Name() string { return "impl_c" }
@@ -25,41 +30,88 @@ func (g *ImplCGenerator) Generate(ctx *Context) ([]*OutputFile, error) {
 	scaffoldHeader := GeneratedFileHeaderBlock(ctx, true)
 	scaffoldCMakeHeader := GeneratedFileHeader(ctx, "#", true)
 
-	implFile, err := g.generateImplSource(api, apiName)
+	implFile, err := g.generateImplSource(api, apiName, ctx.ResolvedTypes)
 	if err != nil {
 		return nil, fmt.Errorf("generating C impl stub: %w", err)
 	}
 	implFile.Content = prependHeader(scaffoldHeader, implFile.Content)
 
-	cmakeFile := g.generateCMakeLists(api, apiName)
+	cmakeFile := g.generateCMakeLists(api, apiName, ctx.ResolvedTypes)
 	cmakeFile.Content = prependHeader(scaffoldCMakeHeader, cmakeFile.Content)
 
-	return []*OutputFile{implFile, cmakeFile}, nil
+	files := []*OutputFile{implFile, cmakeFile}
+	generatedHeader := GeneratedFileHeaderBlock(ctx, false)
+	abiInfoFile := generateCABIInfoSource(apiName, ABIInfo(ctx))
+	abiInfoFile.Content = prependHeader(generatedHeader, abiInfoFile.Content)
+	files = append(files, abiInfoFile)
+	if len(api.Events) > 0 {
+		for _, f := range []*OutputFile{generateCEventsHeader(api, apiName), generateCEventsTest(api, apiName)} {
+			f.Content = prependHeader(generatedHeader, f.Content)
+			files = append(files, f)
+		}
+	}
+	if hasThreadChecks(api) {
+		f := generateCThreadingHeader(apiName)
+		f.Content = prependHeader(generatedHeader, f.Content)
+		files = append(files, f)
+	}
+
+	return files, nil
 }
 
 // generateImplSource produces the stub .c implementation file.
-func (g *ImplCGenerator) generateImplSource(api *model.APIDefinition, apiName string) (*OutputFile, error) {
+func (g *ImplCGenerator) generateImplSource(api *model.APIDefinition, apiName string, resolved resolver.ResolvedTypes) (*OutputFile, error) {
 	var b strings.Builder
 
 	// Includes
-	fmt.Fprintf(&b, "#include \"%s.h\"\n\n", apiName)
+	fmt.Fprintf(&b, "#include \"%s.h\"\n", apiName)
+	if len(api.Events) > 0 {
+		// This translation unit owns the event ring; push with the
+		// generated <api>_event_push_* helpers.
+		fmt.Fprintf(&b, "#define %s_EVENTS_IMPLEMENTATION\n", UpperSnakeCase(apiName))
+		fmt.Fprintf(&b, "#include \"%s_events.h\"\n", apiName)
+	}
+	if hasThreadChecks(api) {
+		// The stubs open with the generated thread-affinity checks; keep them
+		// when filling in the bodies.
+		writeCThreadingInclude(&b, apiName)
+	}
+	b.WriteString("\n")
 	b.WriteString("#include <stdlib.h>\n")
 	b.WriteString("#include <string.h>\n")
 	b.WriteString("\n")
 
+	if hasStringReturns(api) {
+		b.WriteString("// Returned strings must be allocated with malloc; callers release them\n")
+		b.WriteString("// through this function.\n")
+		fmt.Fprintf(&b, "%s void %s(char* str) {\n", ExportMacroName(apiName), StringFreeFunctionName(apiName))
+		b.WriteString("    free(str);\n")
+		b.WriteString("}\n\n")
+	}
+
+	if hasBufferReturns(api, resolved) {
+		b.WriteString("// Returned buffers and tables must be allocated with malloc; callers\n")
+		b.WriteString("// release them through this function.\n")
+		fmt.Fprintf(&b, "%s void %s(void* data) {\n", ExportMacroName(apiName), BufferFreeFunctionName(apiName))
+		b.WriteString("    free(data);\n")
+		b.WriteString("}\n\n")
+	}
+
 	// Per-interface function stubs: constructors, auto-destructor, then methods
 	for _, iface := range api.Interfaces {
+		handleName, hasConstructors := iface.ConstructorHandleName()
+		recordThread := hasConstructors && recordsCreator(api, handleName)
 		for i := range iface.Constructors {
-			g.writeMethodStub(&b, apiName, iface.Name, &iface.Constructors[i])
+			g.writeMethodStub(&b, apiName, iface.Name, &iface.Constructors[i], recordThread, resolved)
 			b.WriteString("\n")
 		}
-		if handleName, ok := iface.ConstructorHandleName(); ok {
-			destructor := SyntheticDestructor(handleName)
-			g.writeMethodStub(&b, apiName, iface.Name, &destructor)
+		if hasConstructors {
+			destructor := threadAffinityDestructor(api, &iface, handleName)
+			g.writeMethodStub(&b, apiName, iface.Name, &destructor, recordThread, resolved)
 			b.WriteString("\n")
 		}
 		for i := range iface.Methods {
-			g.writeMethodStub(&b, apiName, iface.Name, &iface.Methods[i])
+			g.writeMethodStub(&b, apiName, iface.Name, &iface.Methods[i], false, resolved)
 			b.WriteString("\n")
 		}
 	}
@@ -73,9 +125,9 @@ func (g *ImplCGenerator) generateImplSource(api *model.APIDefinition, apiName st
 }
 
 // generateCMakeLists produces a scaffold CMakeLists.txt for the C implementation (WASM only).
-func (g *ImplCGenerator) generateCMakeLists(api *model.APIDefinition, apiName string) *OutputFile {
+func (g *ImplCGenerator) generateCMakeLists(api *model.APIDefinition, apiName string, resolved resolver.ResolvedTypes) *OutputFile {
 	projectName := strings.ReplaceAll(apiName, "_", "-")
-	exports := ComputeWASMExportsCSV(apiName, api)
+	exports := ComputeWASMExportsCSV(apiName, api, resolved)
 	var b strings.Builder
 
 	fmt.Fprintf(&b, `cmake_minimum_required(VERSION 3.15)
@@ -87,6 +139,7 @@ set(CMAKE_C_STANDARD_REQUIRED ON)
 if(EMSCRIPTEN)
     add_executable(%[3]s
         %[3]s_impl.c
+        generated/%[3]s_abi_info.c
         platform_services/web.c
     )
     set_target_properties(%[3]s PROPERTIES SUFFIX ".wasm")
@@ -114,11 +167,16 @@ endif()
 	}
 }
 
-// writeMethodStub writes a single C function stub body.
-func (g *ImplCGenerator) writeMethodStub(b *strings.Builder, apiName, ifaceName string, method *model.MethodDef) {
+// writeMethodStub writes a single C function stub body. For constructors and
+// destructors of handles whose creating thread is checked, recordThread adds
+// recording it (left to the implementation, since only it knows the handle)
+// and forgetting it.
+func (g *ImplCGenerator) writeMethodStub(b *strings.Builder, apiName, ifaceName string, method *model.MethodDef, recordThread bool, resolved resolver.ResolvedTypes) {
+	if method.Async {
+		g.writeAsyncStubs(b, apiName, ifaceName, method)
+		return
+	}
 	funcName := CABIFunctionName(apiName, ifaceName, method.Name)
-	hasError := method.Error != ""
-	hasReturn := method.Returns != nil
 
 	// Build parameter list
 	var params []string
@@ -126,19 +184,8 @@ func (g *ImplCGenerator) writeMethodStub(b *strings.Builder, apiName, ifaceName
 		params = append(params, formatCParam(&p)...)
 	}
 
-	// Determine return type and out-parameter
-	var returnType string
-	switch {
-	case hasError && hasReturn:
-		returnType = "int32_t"
-		params = append(params, COutParamType(method.Returns.Type)+" out_result")
-	case hasError && !hasReturn:
-		returnType = "int32_t"
-	case !hasError && hasReturn:
-		returnType = CReturnType(method.Returns.Type)
-	default:
-		returnType = "void"
-	}
+	returnType, outParams := cMethodSignature(method)
+	params = append(params, outParams...)
 
 	paramStr := strings.Join(params, ", ")
 	if paramStr == "" {
@@ -147,14 +194,85 @@ func (g *ImplCGenerator) writeMethodStub(b *strings.Builder, apiName, ifaceName
 
 	exportMacro := ExportMacroName(apiName)
 	fmt.Fprintf(b, "%s %s %s(%s) {\n", exportMacro, returnType, funcName, paramStr)
+	writeCThreadCheck(b, apiName, method)
+	switch {
+	case recordThread && method.Returns != nil:
+		fmt.Fprintf(b, "    // TODO: once created, call %s_THREAD_RECORD(*out_result);\n", UpperSnakeCase(apiName))
+	case recordThread:
+		fmt.Fprintf(b, "    %s_THREAD_FORGET(%s);\n", UpperSnakeCase(apiName), cIdent(method.Parameters[0].Name))
+	}
+	writeCTableNotes(b, method.Parameters, method.Returns)
 	b.WriteString("    // TODO: implement\n")
 
 	switch {
-	case hasError:
-		b.WriteString("    return 0;\n")
-	case hasReturn:
+	case returnType == "void":
+	case returnsCValue(method, returnType, resolved):
+		// Structs and unions are returned by value and need a zero value
+		// of their own type.
+		fmt.Fprintf(b, "    %s result = {0};\n", returnType)
+		b.WriteString("    return result;\n")
+	default:
 		b.WriteString("    return 0;\n")
 	}
 
 	b.WriteString("}\n")
 }
+
+// returnsCValue reports whether a C function with the given return type
+// returns the method's struct or union result by value.
+func returnsCValue(method *model.MethodDef, returnType string, resolved resolver.ResolvedTypes) bool {
+	if method.Returns == nil || returnType != model.FlatBufferCType(method.Returns.Type) {
+		return false
+	}
+	info, ok := resolved[method.Returns.Type]
+	return ok && (info.Kind == resolver.TypeKindStruct || info.Kind == resolver.TypeKindUnion)
+}
+
+// writeAsyncStubs writes the start/poll/cancel stubs of an async method. The
+// operation representation is left to the implementation; the stubs refuse to
+// start so bindings report the call as failed until they are filled in.
+func (g *ImplCGenerator) writeAsyncStubs(b *strings.Builder, apiName, ifaceName string, method *model.MethodDef) {
+	exportMacro := ExportMacroName(apiName)
+	opType := AsyncOpTypeName(apiName)
+
+	var params []string
+	for _, p := range method.Parameters {
+		params = append(params, formatCParam(&p)...)
+	}
+	paramStr := strings.Join(params, ", ")
+	if paramStr == "" {
+		paramStr = "void"
+	}
+	pollParams := append([]string{opType + " op"}, asyncPollOutParams(method)...)
+
+	fmt.Fprintf(b, "%s %s %s(%s) {\n", exportMacro, opType, AsyncStartFunctionName(apiName, ifaceName, method.Name), paramStr)
+	writeCThreadCheck(b, apiName, method)
+	writeCTableNotes(b, method.Parameters, nil)
+	b.WriteString("    // TODO: allocate an operation and start the work\n")
+	b.WriteString("    return NULL;\n")
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(b, "%s int32_t %s(%s) {\n", exportMacro, AsyncPollFunctionName(apiName, ifaceName, method.Name), strings.Join(pollParams, ", "))
+	writeCTableNotes(b, nil, method.Returns)
+	b.WriteString("    // TODO: report progress; write outputs and free op once done\n")
+	fmt.Fprintf(b, "    return %s;\n", AsyncStatusConstName(apiName, "cancelled"))
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(b, "%s void %s(%s op) {\n", exportMacro, AsyncCancelFunctionName(apiName, ifaceName, method.Name), opType)
+	b.WriteString("    // TODO: signal cancellation and free op\n")
+	b.WriteString("}\n")
+}
+
+// writeCTableNotes notes the FlatBuffers tables a C stub receives and
+// returns. C has no flatc output, so the implementation reads and builds
+// them with a FlatBuffers C library such as flatcc.
+func writeCTableNotes(b *strings.Builder, params []model.ParameterDef, ret *model.ReturnDef) {
+	for _, p := range params {
+		if p.Table {
+			fmt.Fprintf(b, "    // %s is a %s FlatBuffer of %s_len bytes; verify it before reading.\n", cIdent(p.Name), p.Type, p.Name)
+		}
+	}
+	if ret != nil && ret.Table {
+		fmt.Fprintf(b, "    // Pass out a finished %s FlatBuffer allocated with malloc.\n", ret.Type)
+	}
+}
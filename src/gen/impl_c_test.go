@@ -1,6 +1,9 @@
 package gen
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -14,8 +17,8 @@ func TestImplCGenerator_Minimal(t *testing.T) {
 		t.Fatalf("generation failed: %v", err)
 	}
 
-	if len(files) != 2 {
-		t.Fatalf("expected 2 output files, got %d", len(files))
+	if len(files) != 3 {
+		t.Fatalf("expected 3 output files, got %d", len(files))
 	}
 
 	f := files[0]
@@ -103,8 +106,8 @@ func TestImplCGenerator_Full(t *testing.T) {
 		t.Fatalf("generation failed: %v", err)
 	}
 
-	if len(files) != 2 {
-		t.Fatalf("expected 2 output files, got %d", len(files))
+	if len(files) != 3 {
+		t.Fatalf("expected 3 output files, got %d", len(files))
 	}
 
 	if files[0].Path != "example_app_engine_impl.c" {
@@ -130,3 +133,268 @@ func TestImplCGenerator_Registration(t *testing.T) {
 		t.Errorf("expected name impl_c, got %q", gen.Name())
 	}
 }
+
+func TestImplCGenerator_Events(t *testing.T) {
+	ctx := loadTestAPI(t, "events.yaml")
+	gen := &ImplCGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	impl := string(findOutputFile(t, files, "event_api_impl.c").Content)
+	if !strings.Contains(impl, "#define EVENT_API_EVENTS_IMPLEMENTATION\n#include \"event_api_events.h\"") {
+		t.Error("impl scaffold should own the event ring implementation")
+	}
+
+	header := findOutputFile(t, files, "event_api_events.h")
+	if header.Scaffold || header.ProjectFile {
+		t.Error("events header should be a regenerated file in the output directory")
+	}
+	if !strings.Contains(string(header.Content), "DO NOT EDIT") {
+		t.Error("events header missing generated-file banner")
+	}
+	findOutputFile(t, files, "event_api_events_test.c")
+
+	// No events, no event files
+	ctx = loadTestAPI(t, "minimal.yaml")
+	files, err = gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	for _, f := range files {
+		if strings.Contains(f.Path, "events") {
+			t.Errorf("unexpected events file %s", f.Path)
+		}
+	}
+}
+
+func TestImplCGenerator_Async(t *testing.T) {
+	ctx := loadTestAPI(t, "async.yaml")
+	gen := &ImplCGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	impl := string(findOutputFile(t, files, "async_api_impl.c").Content)
+
+	for _, want := range []string{
+		"ASYNC_API_EXPORT async_api_async_op async_api_assets_load_model_start(engine_handle engine, const char* path) {\n    // TODO: allocate an operation and start the work\n    return NULL;\n}",
+		"ASYNC_API_EXPORT int32_t async_api_assets_load_model_poll(async_api_async_op op, int32_t* out_error, uint8_t** out_result, uint32_t* out_result_len) {\n    // Pass out a finished Common.EntityId FlatBuffer allocated with malloc.\n",
+		"return ASYNC_API_ASYNC_CANCELLED;",
+		"ASYNC_API_EXPORT void async_api_assets_load_model_cancel(async_api_async_op op) {",
+	} {
+		if !strings.Contains(impl, want) {
+			t.Errorf("impl scaffold missing %q", want)
+		}
+	}
+}
+
+func TestImplCGenerator_StringReturns(t *testing.T) {
+	ctx := loadTestAPI(t, "strings.yaml")
+	gen := &ImplCGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	impl := string(findOutputFile(t, files, "strings_api_impl.c").Content)
+
+	for _, want := range []string{
+		"STRINGS_API_EXPORT void strings_api_string_free(char* str) {\n    free(str);\n}",
+		"STRINGS_API_EXPORT char* strings_api_text_get_name(engine_handle engine) {",
+		"STRINGS_API_EXPORT int32_t strings_api_text_describe(engine_handle engine, const char* key, char** out_result) {",
+	} {
+		if !strings.Contains(impl, want) {
+			t.Errorf("impl scaffold missing %q", want)
+		}
+	}
+
+	cmake := string(findOutputFile(t, files, "CMakeLists.txt").Content)
+	if !strings.Contains(cmake, "_strings_api_string_free") {
+		t.Error("CMakeLists.txt should export the string free function to WASM")
+	}
+}
+
+func TestImplCGenerator_BufferReturns(t *testing.T) {
+	ctx := loadTestAPI(t, "buffers.yaml")
+	gen := &ImplCGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	impl := string(findOutputFile(t, files, "buffers_api_impl.c").Content)
+
+	for _, want := range []string{
+		"BUFFERS_API_EXPORT void buffers_api_buffer_free(void* data) {\n    free(data);\n}",
+		"BUFFERS_API_EXPORT void buffers_api_media_read_tensor(engine_handle engine, float** out_result, uint32_t* out_result_len) {\n    // TODO: implement\n}",
+		"BUFFERS_API_EXPORT int32_t buffers_api_media_sample_ids(engine_handle engine, uint32_t count, int64_t** out_result, uint32_t* out_result_len) {",
+	} {
+		if !strings.Contains(impl, want) {
+			t.Errorf("impl scaffold missing %q", want)
+		}
+	}
+
+	cmake := string(findOutputFile(t, files, "CMakeLists.txt").Content)
+	if !strings.Contains(cmake, "_buffers_api_buffer_free") {
+		t.Error("CMakeLists.txt should export the buffer free function to WASM")
+	}
+}
+
+func TestImplCGenerator_Optionals(t *testing.T) {
+	ctx := loadTestAPI(t, "optional.yaml")
+	gen := &ImplCGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	impl := string(findOutputFile(t, files, "optional_api_impl.c").Content)
+
+	for _, want := range []string{
+		"OPTIONAL_API_EXPORT int32_t optional_api_scene_set_limit(engine_handle engine, bool has_limit, uint32_t limit) {",
+		"OPTIONAL_API_EXPORT void optional_api_scene_get_limit(engine_handle engine, uint32_t* out_result, bool* out_has_result) {",
+		"OPTIONAL_API_EXPORT int32_t optional_api_scene_get_config(engine_handle engine, uint8_t** out_result, uint32_t* out_result_len) {",
+	} {
+		if !strings.Contains(impl, want) {
+			t.Errorf("impl scaffold missing %q", want)
+		}
+	}
+}
+
+func TestImplCGenerator_Tables(t *testing.T) {
+	ctx := loadTestAPI(t, "fields.yaml")
+	gen := &ImplCGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	impl := string(findOutputFile(t, files, "fields_api_impl.c").Content)
+
+	for _, want := range []string{
+		"FIELDS_API_EXPORT void fields_api_buffer_free(void* data) {\n    free(data);\n}",
+		"FIELDS_API_EXPORT int32_t fields_api_stream_reconfigure(stream_handle stream, const uint8_t* config, uint32_t config_len) {\n    // config is a Fields.StreamConfig FlatBuffer of config_len bytes; verify it before reading.\n",
+		"FIELDS_API_EXPORT void fields_api_stream_info(stream_handle stream, uint8_t** out_result, uint32_t* out_result_len) {\n    // Pass out a finished Fields.StreamInfo FlatBuffer allocated with malloc.\n",
+	} {
+		if !strings.Contains(impl, want) {
+			t.Errorf("impl scaffold missing %q", want)
+		}
+	}
+}
+
+func TestImplCGenerator_ThreadAffinity(t *testing.T) {
+	ctx := loadTestAPI(t, "threading.yaml")
+	gen := &ImplCGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	findOutputFile(t, files, "threading_api_threading.h")
+
+	impl := string(findOutputFile(t, files, "threading_api_impl.c").Content)
+	for _, want := range []string{
+		"#define THREADING_API_THREADING_IMPLEMENTATION\n#include \"threading_api_threading.h\"\n",
+		"threading_api_window_set_title(window_handle window, const char* title) {\n    THREADING_API_ASSERT_MAIN_THREAD();\n",
+		"threading_api_session_create_session(session_handle* out_result) {\n    // TODO: once created, call THREADING_API_THREAD_RECORD(*out_result);\n",
+		"threading_api_session_destroy_session(session_handle session) {\n    THREADING_API_ASSERT_CREATOR_THREAD(session);\n    THREADING_API_THREAD_FORGET(session);\n",
+		"threading_api_session_fetch_start(session_handle session) {\n    THREADING_API_ASSERT_CREATOR_THREAD(session);\n",
+		"threading_api_counter_increment(counter_handle counter) {\n    // TODO: implement\n",
+	} {
+		if !strings.Contains(impl, want) {
+			t.Errorf("impl scaffold missing %q", want)
+		}
+	}
+}
+
+func TestImplCGenerator_NoThreadAffinity(t *testing.T) {
+	ctx := loadTestAPI(t, "minimal.yaml")
+	gen := &ImplCGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	for _, f := range files {
+		if strings.HasSuffix(f.Path, "_threading.h") {
+			t.Errorf("unexpected %s without thread affinities", f.Path)
+		}
+		if strings.Contains(string(f.Content), "_THREADING_IMPLEMENTATION") {
+			t.Errorf("%s should not include the threading header", f.Path)
+		}
+	}
+}
+
+// buildCOutput compiles the C implementation generated from ctx, with the
+// header, as the generated Makefile lays them out: project files at the
+// root, the rest in generated/. It skips if no C compiler is found.
+func buildCOutput(t *testing.T, ctx *Context) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("compiles generated code")
+	}
+	cc := os.Getenv("CC")
+	if cc == "" {
+		cc = "cc"
+	}
+	if _, err := exec.LookPath(cc); err != nil {
+		t.Skip("C compiler not found")
+	}
+
+	var files []*OutputFile
+	for _, g := range []Generator{&CHeaderGenerator{}, &ImplCGenerator{}} {
+		out, err := g.Generate(ctx)
+		if err != nil {
+			t.Fatalf("%s generation failed: %v", g.Name(), err)
+		}
+		files = append(files, out...)
+	}
+	dir := t.TempDir()
+	var sources []string
+	for _, f := range files {
+		path := filepath.Join(dir, "generated", f.Path)
+		if f.ProjectFile {
+			path = filepath.Join(dir, f.Path)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, f.Content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Ext(path) == ".c" {
+			sources = append(sources, path)
+		}
+	}
+	for _, src := range sources {
+		cmd := exec.Command(cc, "-std=c17", "-Wall", "-I"+dir, "-I"+filepath.Join(dir, "generated"), "-c", "-o", src+".o", src)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("generated C does not compile: %v\n%s", err, out)
+		}
+	}
+}
+
+func TestImplCGenerator_ValueReturns(t *testing.T) {
+	impl := generateFile(t, loadTestAPI(t, "unions.yaml"), "impl_c", "unions_api_impl.c")
+	if !strings.Contains(impl, "Scene_Shape unions_api_canvas_last_shape(canvas_handle canvas) {\n    // TODO: implement\n    Scene_Shape result = {0};\n    return result;\n}") {
+		t.Errorf("union return stub should return a zero Scene_Shape:\n%s", impl)
+	}
+	impl = generateFile(t, loadTestAPI(t, "arrays.yaml"), "impl_c", "arrays_api_impl.c")
+	for _, want := range []string{"    Geo_Mat4 result = {0};\n    return result;\n", "    Geo_Bounds result = {0};\n    return result;\n"} {
+		if !strings.Contains(impl, want) {
+			t.Errorf("fixed-array struct return stub missing %q", want)
+		}
+	}
+}
+
+func TestImplCGenerator_Build(t *testing.T) {
+	for _, file := range []string{"minimal.yaml", "full.yaml", "async.yaml", "events.yaml", "unions.yaml", "arrays.yaml", "strings.yaml", "buffers.yaml", "optional.yaml", "threading.yaml", "flags.yaml", "namespaces.yaml", "fields.yaml", "fb_return.yaml", "ownership.yaml", "lifecycle.yaml"} {
+		t.Run(file, func(t *testing.T) {
+			buildCOutput(t, loadTestAPI(t, file))
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/benn-herrera/xplatter/model"
+	"github.com/benn-herrera/xplatter/resolver"
 )
 
 func init() {
@@ -15,6 +16,8 @@ func init() {
 //   - An abstract interface header with pure virtual methods
 //   - A C ABI shim that bridges extern "C" functions to the interface
 //   - A stub implementation header and source file
+//   - When events are declared, the generated <api>_events.h ring buffer
+//     (compiled into the shim) and its standalone test
 type ImplCppGenerator struct{}
 
 func (g *ImplCppGenerator) Name() string { return "impl_cpp" }
@@ -27,13 +30,13 @@ func (g *ImplCppGenerator) Generate(ctx *Context) ([]*OutputFile, error) {
 	scaffoldHeader := GeneratedFileHeaderBlock(ctx, true)
 	scaffoldCMakeHeader := GeneratedFileHeader(ctx, "#", true)
 
-	ifaceFile, err := g.generateInterface(api, apiName)
+	ifaceFile, err := g.generateInterface(api, apiName, ctx.ResolvedTypes)
 	if err != nil {
 		return nil, fmt.Errorf("generating interface: %w", err)
 	}
 	ifaceFile.Content = prependHeader(genHeader, ifaceFile.Content)
 
-	shimFile, err := g.generateShim(api, apiName)
+	shimFile, err := g.generateShim(api, apiName, ABIInfo(ctx), ctx.ResolvedTypes)
 	if err != nil {
 		return nil, fmt.Errorf("generating shim: %w", err)
 	}
@@ -51,14 +54,27 @@ func (g *ImplCppGenerator) Generate(ctx *Context) ([]*OutputFile, error) {
 	}
 	implSource.Content = prependHeader(scaffoldHeader, implSource.Content)
 
-	cmakeFile := g.generateCMakeLists(api, apiName)
+	cmakeFile := g.generateCMakeLists(api, apiName, ctx.ResolvedTypes)
 	cmakeFile.Content = prependHeader(scaffoldCMakeHeader, cmakeFile.Content)
 
-	return []*OutputFile{ifaceFile, shimFile, implHeader, implSource, cmakeFile}, nil
+	files := []*OutputFile{ifaceFile, shimFile, implHeader, implSource, cmakeFile}
+	if len(api.Events) > 0 {
+		for _, f := range []*OutputFile{generateCEventsHeader(api, apiName), generateCEventsTest(api, apiName)} {
+			f.Content = prependHeader(genHeader, f.Content)
+			files = append(files, f)
+		}
+	}
+	if hasThreadChecks(api) {
+		f := generateCThreadingHeader(apiName)
+		f.Content = prependHeader(genHeader, f.Content)
+		files = append(files, f)
+	}
+
+	return files, nil
 }
 
 // generateInterface produces the abstract C++ interface header.
-func (g *ImplCppGenerator) generateInterface(api *model.APIDefinition, apiName string) (*OutputFile, error) {
+func (g *ImplCppGenerator) generateInterface(api *model.APIDefinition, apiName string, resolved resolver.ResolvedTypes) (*OutputFile, error) {
 	className := ToPascalCase(apiName) + "Interface"
 	guardName := UpperSnakeCase(apiName) + "_INTERFACE_H"
 
@@ -70,24 +86,85 @@ func (g *ImplCppGenerator) generateInterface(api *model.APIDefinition, apiName s
 #include <stdint.h>
 #include <stdbool.h>
 #include <cstddef>
+#include <string>
 #include <string_view>
 #include <span>
 #include "%[2]s.h"
-
 `, guardName, apiName)
+	if len(api.Events) > 0 {
+		fmt.Fprintf(&b, "#include \"%s_events.h\"\n", apiName)
+	}
+	hasStrings := hasStringReturns(api)
+	if hasStrings {
+		b.WriteString("#include <cstdlib>\n#include <cstring>\n")
+	}
+	if hasBufferReturns(api, resolved) {
+		b.WriteString("#include <vector>\n")
+	}
+	if headers := cppTableHeaders(api, resolved); len(headers) > 0 {
+		// Tables reach the implementation as flatc accessors and leave it as
+		// finished FlatBuffers.
+		b.WriteString("#include \"flatbuffers/flatbuffers.h\"\n")
+		for _, h := range headers {
+			fmt.Fprintf(&b, "#include \"%s\"\n", h)
+		}
+	}
+	if hasOptionals(api) {
+		b.WriteString("#include <optional>\n")
+	}
+	hasAsync := hasAsyncMethods(api)
+	if hasAsync {
+		b.WriteString("#include <atomic>\n#include <memory>\n#include <mutex>\n")
+	}
+	b.WriteString("\n")
+
+	writeCppBitFlagsOperators(&b, resolved)
+	if hasStrings {
+		writeCppStringCopy(&b, apiName)
+	}
+	if hasAsync {
+		writeCppCompletion(&b, apiName)
+	}
+
+	// Base interfaces become abstract base classes of the interface class.
+	var bases []string
+	for _, iface := range interfacesBaseFirst(api) {
+		if !isBaseInterface(api, iface) {
+			continue
+		}
+		g.writeBaseInterfaceClass(&b, api, apiName, iface)
+		if !extendedByBaseInterface(api, iface) {
+			bases = append(bases, "public "+cppBaseInterfaceClassName(apiName, iface.Name))
+		}
+	}
 
 	// Abstract class
-	fmt.Fprintf(&b, "class %s {\n", className)
+	if len(bases) > 0 {
+		fmt.Fprintf(&b, "class %s : %s {\n", className, strings.Join(bases, ", "))
+	} else {
+		fmt.Fprintf(&b, "class %s {\n", className)
+	}
 	b.WriteString("public:\n")
 	fmt.Fprintf(&b, "    virtual ~%s() = default;\n\n", className)
 
+	if len(api.Events) > 0 {
+		b.WriteString("    /* Events — push FlatBuffer-serialized payloads to the binding */\n")
+		for _, ev := range api.Events {
+			fmt.Fprintf(&b, "    static int32_t push_%s_event(std::span<const uint8_t> payload) {\n", ev.Name)
+			fmt.Fprintf(&b, "        return %s(payload.data(), static_cast<uint32_t>(payload.size()));\n",
+				EventPushFunctionName(apiName, ev.Name))
+			b.WriteString("    }\n")
+		}
+		b.WriteString("\n")
+	}
+
 	for _, iface := range api.Interfaces {
-		if len(iface.Methods) == 0 {
+		if len(iface.Methods) == 0 || isBaseInterface(api, &iface) {
 			continue
 		}
 		fmt.Fprintf(&b, "    /* %s */\n", iface.Name)
 		for _, method := range iface.Methods {
-			g.writeInterfaceMethod(&b, &method)
+			g.writeInterfaceMethod(&b, apiName, &method)
 		}
 		b.WriteString("\n")
 	}
@@ -106,8 +183,55 @@ func (g *ImplCppGenerator) generateInterface(api *model.APIDefinition, apiName s
 	}, nil
 }
 
+// cppBaseInterfaceClassName returns the abstract class for a base interface.
+// e.g., ("hello_xplatter", "node") → "HelloXplatterNodeInterface"
+func cppBaseInterfaceClassName(apiName, ifaceName string) string {
+	return ToPascalCase(apiName) + ToPascalCase(ifaceName) + "Interface"
+}
+
+// extendedByBaseInterface reports whether iface is the base of another base
+// interface, in which case the interface class reaches it through that class.
+func extendedByBaseInterface(api *model.APIDefinition, iface *model.InterfaceDef) bool {
+	for i := range api.Interfaces {
+		other := &api.Interfaces[i]
+		if other.Extends == iface.Name && isBaseInterface(api, other) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeBaseInterfaceClass writes the abstract class holding a base interface's
+// methods, deriving from the class of the interface it extends in turn.
+func (g *ImplCppGenerator) writeBaseInterfaceClass(b *strings.Builder, api *model.APIDefinition, apiName string, iface *model.InterfaceDef) {
+	className := cppBaseInterfaceClassName(apiName, iface.Name)
+	fmt.Fprintf(b, "/* %s */\n", iface.Name)
+	if base := api.BaseInterface(iface); base != nil {
+		fmt.Fprintf(b, "class %s : public %s {\n", className, cppBaseInterfaceClassName(apiName, base.Name))
+	} else {
+		fmt.Fprintf(b, "class %s {\n", className)
+	}
+	b.WriteString("public:\n")
+	fmt.Fprintf(b, "    virtual ~%s() = default;\n\n", className)
+	for _, method := range iface.Methods {
+		g.writeInterfaceMethod(b, apiName, &method)
+	}
+	b.WriteString("};\n\n")
+}
+
 // writeInterfaceMethod writes a single pure virtual method declaration.
-func (g *ImplCppGenerator) writeInterfaceMethod(b *strings.Builder, method *model.MethodDef) {
+func (g *ImplCppGenerator) writeInterfaceMethod(b *strings.Builder, apiName string, method *model.MethodDef) {
+	writeCLifecycleComment(b, "    ", &method.Lifecycle, nil, nil)
+	writeCThreadNote(b, "    ", method)
+	writeCOwnershipNotes(b, "    ", method)
+	if method.Deprecated != nil {
+		fmt.Fprintf(b, "    [[deprecated(%s)]]\n", quoteLiteral(deprecationText(method.Deprecated, sameName)))
+	}
+	if method.Async {
+		fmt.Fprintf(b, "    virtual void %s(%s) = 0;\n", cIdent(method.Name), strings.Join(cppAsyncParams(apiName, method), ", "))
+		return
+	}
+
 	hasError := method.Error != ""
 	hasReturn := method.Returns != nil
 
@@ -117,7 +241,7 @@ func (g *ImplCppGenerator) writeInterfaceMethod(b *strings.Builder, method *mode
 	case hasError:
 		returnType = "int32_t"
 	case hasReturn:
-		returnType = cppReturnType(method.Returns.Type)
+		returnType = cppResultType(method.Returns)
 	default:
 		returnType = "void"
 	}
@@ -130,38 +254,93 @@ func (g *ImplCppGenerator) writeInterfaceMethod(b *strings.Builder, method *mode
 
 	// If fallible with return, add out-parameter
 	if hasError && hasReturn {
-		params = append(params, cppOutParamType(method.Returns.Type)+" out_result")
+		params = append(params, cppResultType(method.Returns)+"* out_result")
 	}
 
 	paramStr := strings.Join(params, ", ")
 
-	fmt.Fprintf(b, "    virtual %s %s(%s) = 0;\n", returnType, method.Name, paramStr)
+	fmt.Fprintf(b, "    virtual %s %s(%s) = 0;\n", returnType, cIdent(method.Name), paramStr)
 }
 
 // generateShim produces the C ABI shim source file.
-func (g *ImplCppGenerator) generateShim(api *model.APIDefinition, apiName string) (*OutputFile, error) {
+func (g *ImplCppGenerator) generateShim(api *model.APIDefinition, apiName, abiInfo string, resolved resolver.ResolvedTypes) (*OutputFile, error) {
 	className := ToPascalCase(apiName) + "Interface"
 
 	var b strings.Builder
 
-	// Includes
+	// Includes — the shim owns the event ring, so the events header must be
+	// included with the implementation macro before the interface pulls it in.
+	if len(api.Events) > 0 {
+		fmt.Fprintf(&b, "#define %s_EVENTS_IMPLEMENTATION\n", UpperSnakeCase(apiName))
+		fmt.Fprintf(&b, "#include \"%s_events.h\"\n", apiName)
+	}
 	fmt.Fprintf(&b, "#include \"%s_interface.h\"\n", apiName)
-	fmt.Fprintf(&b, "#include \"%s.h\"\n\n", apiName)
+	fmt.Fprintf(&b, "#include \"%s.h\"\n", apiName)
+	if hasThreadChecks(api) {
+		// The shim enforces thread affinity and owns the checks' state.
+		writeCThreadingInclude(&b, apiName)
+	}
+	hasTables := hasTableParams(api)
+	if hasTables {
+		b.WriteString("#include <cstdio>\n#include <cstdlib>\n")
+	}
+	b.WriteString("\n")
+
+	if hasDeprecations(api) {
+		// The shim forwards deprecated methods; their callers get the warning.
+		b.WriteString(`#if defined(__GNUC__) || defined(__clang__)
+#pragma GCC diagnostic ignored "-Wdeprecated-declarations"
+#elif defined(_MSC_VER)
+#pragma warning(disable: 4996)
+#endif
+
+`)
+	}
+
+	hasBuffers := hasBufferReturns(api, resolved)
+	if hasBuffers {
+		writeCppBufferCopy(&b, apiName)
+	}
+	if hasTableReturns(api) {
+		writeCppTableCopy(&b, apiName)
+	}
+	if hasTables {
+		writeCppTableVerify(&b, apiName)
+	}
 
 	b.WriteString("extern \"C\" {\n\n")
 
+	fmt.Fprintf(&b, "%s const char* %s(void) {\n", ExportMacroName(apiName), ABIInfoFunctionName(apiName))
+	fmt.Fprintf(&b, "    return \"%s\";\n", abiInfo)
+	b.WriteString("}\n\n")
+
+	if hasStringReturns(api) {
+		fmt.Fprintf(&b, "%s void %s(char* str) {\n", ExportMacroName(apiName), StringFreeFunctionName(apiName))
+		b.WriteString("    std::free(str);\n")
+		b.WriteString("}\n\n")
+	}
+
+	if hasBuffers {
+		fmt.Fprintf(&b, "%s void %s(void* data) {\n", ExportMacroName(apiName), BufferFreeFunctionName(apiName))
+		b.WriteString("    std::free(data);\n")
+		b.WriteString("}\n\n")
+	}
+
 	for _, iface := range api.Interfaces {
 		fmt.Fprintf(&b, "/* %s */\n", iface.Name)
+		handleName, hasConstructors := iface.ConstructorHandleName()
+		recordThread := hasConstructors && recordsCreator(api, handleName)
 		for _, ctor := range iface.Constructors {
-			g.writeShimCreate(&b, apiName, iface.Name, className, &ctor)
+			g.writeShimCreate(&b, apiName, iface.Name, className, &ctor, recordThread, resolved)
 			b.WriteString("\n")
 		}
-		if handleName, ok := iface.ConstructorHandleName(); ok {
-			g.writeShimDestroy(&b, apiName, iface.Name, className, handleName)
+		if hasConstructors {
+			destructor := threadAffinityDestructor(api, &iface, handleName)
+			g.writeShimDestroy(&b, apiName, iface.Name, className, &destructor, recordThread)
 			b.WriteString("\n")
 		}
 		for _, method := range iface.Methods {
-			g.writeShimFunction(&b, apiName, iface.Name, className, &method)
+			g.writeShimFunction(&b, apiName, iface.Name, className, &method, resolved)
 			b.WriteString("\n")
 		}
 	}
@@ -174,8 +353,9 @@ func (g *ImplCppGenerator) generateShim(api *model.APIDefinition, apiName string
 	}, nil
 }
 
-// writeShimCreate writes a constructor shim: instantiates the impl and returns it as a handle.
-func (g *ImplCppGenerator) writeShimCreate(b *strings.Builder, apiName, ifaceName, className string, ctor *model.MethodDef) {
+// writeShimCreate writes a constructor shim: instantiates the impl and returns it
+// as a handle, recording the creating thread when recordThread is set.
+func (g *ImplCppGenerator) writeShimCreate(b *strings.Builder, apiName, ifaceName, className string, ctor *model.MethodDef, recordThread bool, resolved resolver.ResolvedTypes) {
 	funcName := CABIFunctionName(apiName, ifaceName, ctor.Name)
 	handleName, _ := model.IsHandle(ctor.Returns.Type)
 
@@ -192,55 +372,55 @@ func (g *ImplCppGenerator) writeShimCreate(b *strings.Builder, apiName, ifaceNam
 
 	exportMacro := ExportMacroName(apiName)
 	fmt.Fprintf(b, "%s int32_t %s(%s) {\n", exportMacro, funcName, cParamStr)
+	writeCThreadCheck(b, apiName, ctor)
+	writeCppTableChecks(b, apiName, funcName, ctor, resolved, true)
 	fmt.Fprintf(b, `    %[1]s* instance = create_%[2]s_instance();
     if (!instance) {
         return -1;
     }
     *out_result = reinterpret_cast<%[3]s>(instance);
-    return 0;
 `, className, apiName, HandleTypedefName(handleName))
+	if recordThread {
+		fmt.Fprintf(b, "    %s_THREAD_RECORD(*out_result);\n", UpperSnakeCase(apiName))
+	}
+	b.WriteString("    return 0;\n")
 	b.WriteString("}\n")
 }
 
-// writeShimDestroy writes a destructor shim: casts handle back to interface and deletes.
-func (g *ImplCppGenerator) writeShimDestroy(b *strings.Builder, apiName, ifaceName, className, handleName string) {
-	destructor := SyntheticDestructor(handleName)
+// writeShimDestroy writes a destructor shim: casts handle back to interface and
+// deletes, forgetting the creating thread when forgetThread is set.
+func (g *ImplCppGenerator) writeShimDestroy(b *strings.Builder, apiName, ifaceName, className string, destructor *model.MethodDef, forgetThread bool) {
+	handleName, _ := model.IsHandle(destructor.Parameters[0].Type)
 	funcName := CABIFunctionName(apiName, ifaceName, destructor.Name)
-	paramName := destructor.Parameters[0].Name
+	paramName := cIdent(destructor.Parameters[0].Name)
 	handleType := HandleTypedefName(handleName)
 
 	exportMacro := ExportMacroName(apiName)
 	fmt.Fprintf(b, "%s void %s(%s %s) {\n", exportMacro, funcName, handleType, paramName)
+	writeCThreadCheck(b, apiName, destructor)
+	if forgetThread {
+		fmt.Fprintf(b, "    %s_THREAD_FORGET(%s);\n", UpperSnakeCase(apiName), paramName)
+	}
 	fmt.Fprintf(b, "    %s* instance = reinterpret_cast<%s*>(%s);\n", className, className, paramName)
 	fmt.Fprintf(b, "    delete instance;\n")
 	b.WriteString("}\n")
 }
 
 // writeShimFunction writes a regular extern "C" shim that delegates to the interface.
-func (g *ImplCppGenerator) writeShimFunction(b *strings.Builder, apiName, ifaceName, className string, method *model.MethodDef) {
+func (g *ImplCppGenerator) writeShimFunction(b *strings.Builder, apiName, ifaceName, className string, method *model.MethodDef, resolved resolver.ResolvedTypes) {
+	if method.Async {
+		g.writeShimAsync(b, apiName, ifaceName, className, method, resolved)
+		return
+	}
 	funcName := CABIFunctionName(apiName, ifaceName, method.Name)
-	hasError := method.Error != ""
-	hasReturn := method.Returns != nil
 
 	// Build C parameter list
 	var cParams []string
 	for _, p := range method.Parameters {
 		cParams = append(cParams, formatCParam(&p)...)
 	}
-
-	// Determine C return type and out-param
-	var returnType string
-	switch {
-	case hasError && hasReturn:
-		returnType = "int32_t"
-		cParams = append(cParams, COutParamType(method.Returns.Type)+" out_result")
-	case hasError && !hasReturn:
-		returnType = "int32_t"
-	case !hasError && hasReturn:
-		returnType = CReturnType(method.Returns.Type)
-	default:
-		returnType = "void"
-	}
+	returnType, outParams := cMethodSignature(method)
+	cParams = append(cParams, outParams...)
 
 	cParamStr := strings.Join(cParams, ", ")
 	if cParamStr == "" {
@@ -249,12 +429,41 @@ func (g *ImplCppGenerator) writeShimFunction(b *strings.Builder, apiName, ifaceN
 
 	exportMacro := ExportMacroName(apiName)
 	fmt.Fprintf(b, "%s %s %s(%s) {\n", exportMacro, returnType, funcName, cParamStr)
-	g.writeShimDelegation(b, className, method)
+	writeCThreadCheck(b, apiName, method)
+	writeCppTableChecks(b, apiName, funcName, method, resolved, true)
+	g.writeShimDelegation(b, apiName, className, method)
 	b.WriteString("}\n")
 }
 
+// writeCppTableChecks writes the verification of a shim's table parameters,
+// which also checks their required fields. A buffer that fails fails the call
+// with the method's InvalidArgument error when it has one and returnsError is
+// set, and aborts otherwise. An absent optional table is NULL.
+func writeCppTableChecks(b *strings.Builder, apiName, funcName string, method *model.MethodDef, resolved resolver.ResolvedTypes, returnsError bool) {
+	code, hasCode := invalidArgumentCode(method, resolved)
+	for i := range method.Parameters {
+		p := &method.Parameters[i]
+		if !p.Table {
+			continue
+		}
+		check := fmt.Sprintf("!%s<%s>(%s, %s_len)", cppTableVerifyName(apiName), cppTableType(p.Type), cIdent(p.Name), p.Name)
+		if p.Optional {
+			check = cIdent(p.Name) + " && " + check
+		}
+		fmt.Fprintf(b, "    if (%s) {\n", check)
+		if returnsError && hasCode {
+			fmt.Fprintf(b, "        return %d;\n", code)
+		} else {
+			fmt.Fprintf(b, "        std::fprintf(stderr, \"%%s\\n\", \"%s\");\n", tableCheckMessage(funcName, p))
+			b.WriteString("        std::abort();\n")
+		}
+		b.WriteString("    }\n")
+	}
+}
+
 // writeShimDelegation writes the body of a regular shim function that delegates to the interface.
-func (g *ImplCppGenerator) writeShimDelegation(b *strings.Builder, className string, method *model.MethodDef) {
+func (g *ImplCppGenerator) writeShimDelegation(b *strings.Builder, apiName, className string, method *model.MethodDef) {
+	name := cIdent(method.Name)
 	hasError := method.Error != ""
 	hasReturn := method.Returns != nil
 
@@ -278,37 +487,229 @@ func (g *ImplCppGenerator) writeShimDelegation(b *strings.Builder, className str
 	}
 
 	// Cast handle to interface pointer
-	fmt.Fprintf(b, "    %s* self = reinterpret_cast<%s*>(%s);\n", className, className, handleParam.Name)
+	fmt.Fprintf(b, "    %s* self = reinterpret_cast<%s*>(%s);\n", className, className, cIdent(handleParam.Name))
 
 	// Build the call arguments (handle param passes through as void*)
-	var callArgs []string
-	for _, p := range method.Parameters {
-		if model.IsString(p.Type) {
-			callArgs = append(callArgs, fmt.Sprintf("std::string_view(%s)", p.Name))
-		} else if _, ok := model.IsBuffer(p.Type); ok {
-			callArgs = append(callArgs, fmt.Sprintf("std::span(%s, %s_len)", p.Name, p.Name))
+	callArgs := cppShimCallArgs(method)
+
+	// String returns come back as std::string and are copied into a
+	// caller-owned C string; an absent optional string is NULL.
+	if hasReturn && model.IsString(method.Returns.Type) {
+		copyFunc := cppStringCopyName(apiName)
+		copyExpr := copyFunc + "(result)"
+		if method.Returns.Optional {
+			copyExpr = fmt.Sprintf("result ? %s(*result) : nullptr", copyFunc)
+		}
+		switch {
+		case hasError:
+			fmt.Fprintf(b, "    %s result;\n", cppResultType(method.Returns))
+			fmt.Fprintf(b, "    int32_t err = self->%s(%s);\n", name, strings.Join(append(callArgs, "&result"), ", "))
+			b.WriteString("    if (err == 0) {\n")
+			fmt.Fprintf(b, "        *out_result = %s;\n", copyExpr)
+			b.WriteString("    }\n")
+			b.WriteString("    return err;\n")
+		case method.Returns.Optional:
+			fmt.Fprintf(b, "    %s result = self->%s(%s);\n", cppResultType(method.Returns), name, strings.Join(callArgs, ", "))
+			fmt.Fprintf(b, "    return %s;\n", copyExpr)
+		default:
+			fmt.Fprintf(b, "    return %s(self->%s(%s));\n", copyFunc, name, strings.Join(callArgs, ", "))
+		}
+		return
+	}
+
+	// Optional by-value returns come back as std::optional and are split into
+	// the value and its presence flag.
+	if returnHasPresenceFlag(method) {
+		resultType := cppResultType(method.Returns)
+		indent := "    "
+		if hasError {
+			fmt.Fprintf(b, "    %s result;\n", resultType)
+			fmt.Fprintf(b, "    int32_t err = self->%s(%s);\n", name, strings.Join(append(callArgs, "&result"), ", "))
+			b.WriteString("    if (err == 0) {\n")
+			indent = "        "
 		} else {
-			callArgs = append(callArgs, p.Name)
+			fmt.Fprintf(b, "    %s result = self->%s(%s);\n", resultType, name, strings.Join(callArgs, ", "))
+		}
+		fmt.Fprintf(b, "%s*out_has_result = result.has_value();\n", indent)
+		fmt.Fprintf(b, "%sif (result) {\n", indent)
+		fmt.Fprintf(b, "%s    *out_result = *result;\n", indent)
+		fmt.Fprintf(b, "%s}\n", indent)
+		if hasError {
+			b.WriteString("    }\n")
+			b.WriteString("    return err;\n")
+		}
+		return
+	}
+
+	// Table returns come back as finished FlatBuffers and are copied into a
+	// caller-owned allocation; an absent optional table is NULL.
+	if returnsTable(method) {
+		copyExpr := fmt.Sprintf("%s(&result, out_result_len)", cppTableCopyName(apiName))
+		if method.Returns.Optional {
+			copyExpr = fmt.Sprintf("%s(result ? &*result : nullptr, out_result_len)", cppTableCopyName(apiName))
+		}
+		if hasError {
+			fmt.Fprintf(b, "    %s result;\n", cppResultType(method.Returns))
+			fmt.Fprintf(b, "    int32_t err = self->%s(%s);\n", name, strings.Join(append(callArgs, "&result"), ", "))
+			b.WriteString("    if (err == 0) {\n")
+			fmt.Fprintf(b, "        *out_result = %s;\n", copyExpr)
+			b.WriteString("    }\n")
+			b.WriteString("    return err;\n")
+		} else {
+			fmt.Fprintf(b, "    %s result = self->%s(%s);\n", cppResultType(method.Returns), name, strings.Join(callArgs, ", "))
+			fmt.Fprintf(b, "    *out_result = %s;\n", copyExpr)
+		}
+		return
+	}
+
+	// Buffer returns come back as std::vector and are copied into a
+	// caller-owned allocation.
+	if _, ok := returnBufferElem(method); ok {
+		copyFunc := cppBufferCopyName(apiName)
+		if hasError {
+			fmt.Fprintf(b, "    %s result;\n", cppReturnType(method.Returns.Type))
+			fmt.Fprintf(b, "    int32_t err = self->%s(%s);\n", name, strings.Join(append(callArgs, "&result"), ", "))
+			b.WriteString("    if (err == 0) {\n")
+			fmt.Fprintf(b, "        *out_result = %s(result, out_result_len);\n", copyFunc)
+			b.WriteString("    }\n")
+			b.WriteString("    return err;\n")
+		} else {
+			fmt.Fprintf(b, "    *out_result = %s(self->%s(%s), out_result_len);\n", copyFunc, name, strings.Join(callArgs, ", "))
+		}
+		return
+	}
+
+	// Handles are void* on the C++ side, so handle results need a cast to the
+	// typed C handle.
+	var handleType string
+	if hasReturn {
+		if handleName, ok := model.IsHandle(method.Returns.Type); ok {
+			handleType = HandleTypedefName(handleName)
 		}
 	}
 
 	// Add out_result if fallible with return
 	if hasError && hasReturn {
-		callArgs = append(callArgs, "out_result")
+		if handleType != "" {
+			callArgs = append(callArgs, "reinterpret_cast<void**>(out_result)")
+		} else {
+			callArgs = append(callArgs, "out_result")
+		}
 	}
 
 	argStr := strings.Join(callArgs, ", ")
 
 	switch {
 	case hasError:
-		fmt.Fprintf(b, "    return self->%s(%s);\n", method.Name, argStr)
+		fmt.Fprintf(b, "    return self->%s(%s);\n", name, argStr)
+	case hasReturn && handleType != "":
+		fmt.Fprintf(b, "    return static_cast<%s>(self->%s(%s));\n", handleType, name, argStr)
 	case hasReturn:
-		fmt.Fprintf(b, "    return self->%s(%s);\n", method.Name, argStr)
+		fmt.Fprintf(b, "    return self->%s(%s);\n", name, argStr)
 	default:
-		fmt.Fprintf(b, "    self->%s(%s);\n", method.Name, argStr)
+		fmt.Fprintf(b, "    self->%s(%s);\n", name, argStr)
 	}
 }
 
+// writeShimAsync writes the start/poll/cancel shims of an async method. The
+// operation handed across the C ABI is a heap-allocated shared_ptr to the
+// completion; the implementation holds the other references.
+func (g *ImplCppGenerator) writeShimAsync(b *strings.Builder, apiName, ifaceName, className string, method *model.MethodDef, resolved resolver.ResolvedTypes) {
+	exportMacro := ExportMacroName(apiName)
+	opType := AsyncOpTypeName(apiName)
+	completionType := cppCompletionType(apiName, method)
+	refType := "std::shared_ptr<" + completionType + ">"
+
+	var handleParam *model.ParameterDef
+	for i := range method.Parameters {
+		if _, ok := model.IsHandle(method.Parameters[i].Type); ok {
+			handleParam = &method.Parameters[i]
+			break
+		}
+	}
+
+	// start
+	var cParams []string
+	for _, p := range method.Parameters {
+		cParams = append(cParams, formatCParam(&p)...)
+	}
+	cParamStr := strings.Join(cParams, ", ")
+	if cParamStr == "" {
+		cParamStr = "void"
+	}
+	startName := AsyncStartFunctionName(apiName, ifaceName, method.Name)
+	fmt.Fprintf(b, "%s %s %s(%s) {\n", exportMacro, opType, startName, cParamStr)
+	writeCThreadCheck(b, apiName, method)
+	writeCppTableChecks(b, apiName, startName, method, resolved, false)
+	if handleParam == nil {
+		b.WriteString("    // TODO: no handle parameter found — implement manually\n")
+		b.WriteString("    return nullptr;\n")
+	} else {
+		fmt.Fprintf(b, "    %s* self = reinterpret_cast<%s*>(%s);\n", className, className, cIdent(handleParam.Name))
+		fmt.Fprintf(b, "    auto completion = std::make_shared<%s>();\n", completionType)
+		callArgs := append(cppShimCallArgs(method), "completion")
+		fmt.Fprintf(b, "    self->%s(%s);\n", cIdent(method.Name), strings.Join(callArgs, ", "))
+		fmt.Fprintf(b, "    return reinterpret_cast<%s>(new %s(std::move(completion)));\n", opType, refType)
+	}
+	b.WriteString("}\n\n")
+
+	// poll
+	pollParams := append([]string{opType + " op"}, asyncPollOutParams(method)...)
+	fmt.Fprintf(b, "%s int32_t %s(%s) {\n", exportMacro, AsyncPollFunctionName(apiName, ifaceName, method.Name), strings.Join(pollParams, ", "))
+	fmt.Fprintf(b, "    auto* ref = reinterpret_cast<%s*>(op);\n", refType)
+	b.WriteString("    // Sample ownership before the result: once the implementation has dropped\n")
+	b.WriteString("    // its references, anything it resolved is already visible.\n")
+	b.WriteString("    bool abandoned = ref->use_count() == 1;\n")
+	b.WriteString("    std::atomic_thread_fence(std::memory_order_acquire);\n")
+	errArg := "nullptr"
+	if method.Error != "" {
+		errArg = "out_error"
+	}
+	var returnHandle string
+	var returnsHandle bool
+	if method.Returns != nil {
+		returnHandle, returnsHandle = model.IsHandle(method.Returns.Type)
+	}
+	switch {
+	case method.Returns == nil:
+		fmt.Fprintf(b, "    int32_t status = (*ref)->take(abandoned, %s);\n", errArg)
+	case model.IsString(method.Returns.Type) || returnsTable(method):
+		copyExpr := cppStringCopyName(apiName) + "(result)"
+		if returnsTable(method) {
+			copyExpr = cppTableCopyName(apiName) + "(&result, out_result_len)"
+		}
+		fmt.Fprintf(b, "    %s result;\n", cppResultReturnType(method.Returns))
+		fmt.Fprintf(b, "    int32_t status = (*ref)->take(abandoned, %s, &result);\n", errArg)
+		if method.Error != "" {
+			fmt.Fprintf(b, "    if (status == %s && *out_error == 0) {\n", AsyncStatusConstName(apiName, "done"))
+		} else {
+			fmt.Fprintf(b, "    if (status == %s) {\n", AsyncStatusConstName(apiName, "done"))
+		}
+		fmt.Fprintf(b, "        *out_result = %s;\n", copyExpr)
+		b.WriteString("    }\n")
+	case returnsHandle:
+		b.WriteString("    void* result = nullptr;\n")
+		fmt.Fprintf(b, "    int32_t status = (*ref)->take(abandoned, %s, &result);\n", errArg)
+		fmt.Fprintf(b, "    if (status == %s) {\n", AsyncStatusConstName(apiName, "done"))
+		fmt.Fprintf(b, "        *out_result = reinterpret_cast<%s>(result);\n", HandleTypedefName(returnHandle))
+		b.WriteString("    }\n")
+	default:
+		fmt.Fprintf(b, "    int32_t status = (*ref)->take(abandoned, %s, out_result);\n", errArg)
+	}
+	fmt.Fprintf(b, "    if (status != %s) {\n", AsyncStatusConstName(apiName, "pending"))
+	b.WriteString("        delete ref;\n")
+	b.WriteString("    }\n")
+	b.WriteString("    return status;\n")
+	b.WriteString("}\n\n")
+
+	// cancel
+	fmt.Fprintf(b, "%s void %s(%s op) {\n", exportMacro, AsyncCancelFunctionName(apiName, ifaceName, method.Name), opType)
+	fmt.Fprintf(b, "    auto* ref = reinterpret_cast<%s*>(op);\n", refType)
+	b.WriteString("    (*ref)->cancel();\n")
+	b.WriteString("    delete ref;\n")
+	b.WriteString("}\n")
+}
+
 // generateImplHeader produces the stub implementation header.
 func (g *ImplCppGenerator) generateImplHeader(api *model.APIDefinition, apiName string) (*OutputFile, error) {
 	ifaceClassName := ToPascalCase(apiName) + "Interface"
@@ -335,7 +736,7 @@ func (g *ImplCppGenerator) generateImplHeader(api *model.APIDefinition, apiName
 		}
 		fmt.Fprintf(&b, "    /* %s */\n", iface.Name)
 		for _, method := range iface.Methods {
-			g.writeImplMethodDecl(&b, &method)
+			g.writeImplMethodDecl(&b, apiName, &method)
 		}
 		b.WriteString("\n")
 	}
@@ -352,7 +753,12 @@ func (g *ImplCppGenerator) generateImplHeader(api *model.APIDefinition, apiName
 }
 
 // writeImplMethodDecl writes a method declaration with override.
-func (g *ImplCppGenerator) writeImplMethodDecl(b *strings.Builder, method *model.MethodDef) {
+func (g *ImplCppGenerator) writeImplMethodDecl(b *strings.Builder, apiName string, method *model.MethodDef) {
+	if method.Async {
+		fmt.Fprintf(b, "    void %s(%s) override;\n", cIdent(method.Name), strings.Join(cppAsyncParams(apiName, method), ", "))
+		return
+	}
+
 	hasError := method.Error != ""
 	hasReturn := method.Returns != nil
 
@@ -361,7 +767,7 @@ func (g *ImplCppGenerator) writeImplMethodDecl(b *strings.Builder, method *model
 	case hasError:
 		returnType = "int32_t"
 	case hasReturn:
-		returnType = cppReturnType(method.Returns.Type)
+		returnType = cppResultType(method.Returns)
 	default:
 		returnType = "void"
 	}
@@ -372,12 +778,12 @@ func (g *ImplCppGenerator) writeImplMethodDecl(b *strings.Builder, method *model
 	}
 
 	if hasError && hasReturn {
-		params = append(params, cppOutParamType(method.Returns.Type)+" out_result")
+		params = append(params, cppResultType(method.Returns)+"* out_result")
 	}
 
 	paramStr := strings.Join(params, ", ")
 
-	fmt.Fprintf(b, "    %s %s(%s) override;\n", returnType, method.Name, paramStr)
+	fmt.Fprintf(b, "    %s %s(%s) override;\n", returnType, cIdent(method.Name), paramStr)
 }
 
 // generateImplSource produces the stub implementation source file.
@@ -402,7 +808,7 @@ func (g *ImplCppGenerator) generateImplSource(api *model.APIDefinition, apiName
 	// Method stubs
 	for _, iface := range api.Interfaces {
 		for _, method := range iface.Methods {
-			g.writeImplMethodStub(&b, implClassName, &method)
+			g.writeImplMethodStub(&b, apiName, implClassName, &method)
 			b.WriteString("\n")
 		}
 	}
@@ -422,7 +828,15 @@ func (g *ImplCppGenerator) generateImplSource(api *model.APIDefinition, apiName
 }
 
 // writeImplMethodStub writes a stub method body.
-func (g *ImplCppGenerator) writeImplMethodStub(b *strings.Builder, implClassName string, method *model.MethodDef) {
+func (g *ImplCppGenerator) writeImplMethodStub(b *strings.Builder, apiName, implClassName string, method *model.MethodDef) {
+	if method.Async {
+		fmt.Fprintf(b, "void %s::%s(%s) {\n", implClassName, cIdent(method.Name), strings.Join(cppAsyncParams(apiName, method), ", "))
+		b.WriteString("    // TODO: start the work and call completion->resolve() or reject() when done.\n")
+		b.WriteString("    // Dropping the completion unresolved reports the operation as cancelled.\n")
+		b.WriteString("}\n")
+		return
+	}
+
 	hasError := method.Error != ""
 	hasReturn := method.Returns != nil
 
@@ -431,7 +845,7 @@ func (g *ImplCppGenerator) writeImplMethodStub(b *strings.Builder, implClassName
 	case hasError:
 		returnType = "int32_t"
 	case hasReturn:
-		returnType = cppReturnType(method.Returns.Type)
+		returnType = cppResultType(method.Returns)
 	default:
 		returnType = "void"
 	}
@@ -442,12 +856,12 @@ func (g *ImplCppGenerator) writeImplMethodStub(b *strings.Builder, implClassName
 	}
 
 	if hasError && hasReturn {
-		params = append(params, cppOutParamType(method.Returns.Type)+" out_result")
+		params = append(params, cppResultType(method.Returns)+"* out_result")
 	}
 
 	paramStr := strings.Join(params, ", ")
 
-	fmt.Fprintf(b, "%s %s::%s(%s) {\n", returnType, implClassName, method.Name, paramStr)
+	fmt.Fprintf(b, "%s %s::%s(%s) {\n", returnType, implClassName, cIdent(method.Name), paramStr)
 	b.WriteString("    // TODO: implement\n")
 
 	switch {
@@ -461,9 +875,9 @@ func (g *ImplCppGenerator) writeImplMethodStub(b *strings.Builder, implClassName
 }
 
 // generateCMakeLists produces a scaffold CMakeLists.txt for the C++ implementation.
-func (g *ImplCppGenerator) generateCMakeLists(api *model.APIDefinition, apiName string) *OutputFile {
+func (g *ImplCppGenerator) generateCMakeLists(api *model.APIDefinition, apiName string, resolved resolver.ResolvedTypes) *OutputFile {
 	projectName := strings.ReplaceAll(apiName, "_", "-")
-	exports := ComputeWASMExportsCSV(apiName, api)
+	exports := ComputeWASMExportsCSV(apiName, api, resolved)
 	var b strings.Builder
 
 	fmt.Fprintf(&b, `cmake_minimum_required(VERSION 3.15)
@@ -498,6 +912,14 @@ endif()
 
 target_include_directories(%[3]s PRIVATE ${CMAKE_CURRENT_SOURCE_DIR} ${CMAKE_CURRENT_SOURCE_DIR}/generated)
 `, projectName, api.API.Version, apiName, exports)
+	if len(usedTables(api)) > 0 {
+		fmt.Fprintf(&b, `
+# Tables cross the C ABI as FlatBuffers, read and built with the flatc output.
+find_package(flatbuffers CONFIG REQUIRED)
+target_include_directories(%[1]s PRIVATE ${CMAKE_CURRENT_SOURCE_DIR}/generated/flatbuffers/cpp)
+target_link_libraries(%[1]s PRIVATE flatbuffers::flatbuffers)
+`, apiName)
+	}
 
 	return &OutputFile{
 		Path:        "CMakeLists.txt",
@@ -509,42 +931,320 @@ target_include_directories(%[3]s PRIVATE ${CMAKE_CURRENT_SOURCE_DIR} ${CMAKE_CUR
 
 // --- C++ type helpers ---
 
+// cppStringCopyName returns the interface-header helper that copies a string
+// into caller-owned memory.
+// e.g., "hello_xplatter" → "hello_xplatter_string_copy"
+func cppStringCopyName(apiName string) string {
+	return apiName + "_string_copy"
+}
+
+// cppBufferCopyName returns the shim helper that copies a returned vector into
+// caller-owned memory.
+// e.g., "hello_xplatter" → "hello_xplatter_buffer_copy"
+func cppBufferCopyName(apiName string) string {
+	return apiName + "_buffer_copy"
+}
+
+// writeCppBufferCopy emits the shim helper that copies a returned vector into
+// memory released by <api>_buffer_free. Empty vectors come back as NULL.
+func writeCppBufferCopy(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, `#include <cstdlib>
+#include <cstring>
+
+/* Returned buffers are released by %[1]s (std::free). */
+template <typename T>
+static T* %[2]s(const std::vector<T>& v, uint32_t* out_len) {
+    *out_len = 0;
+    if (v.empty()) {
+        return nullptr;
+    }
+    T* out = static_cast<T*>(std::malloc(v.size() * sizeof(T)));
+    if (out) {
+        std::memcpy(out, v.data(), v.size() * sizeof(T));
+        *out_len = static_cast<uint32_t>(v.size());
+    }
+    return out;
+}
+
+`, BufferFreeFunctionName(apiName), cppBufferCopyName(apiName))
+}
+
+// cppTableCopyName returns the shim helper that copies a returned table into
+// caller-owned memory.
+// e.g., "hello_xplatter" → "hello_xplatter_table_copy"
+func cppTableCopyName(apiName string) string {
+	return apiName + "_table_copy"
+}
+
+// cppTableVerifyName returns the shim helper that verifies a table parameter.
+// e.g., "hello_xplatter" → "hello_xplatter_table_verify"
+func cppTableVerifyName(apiName string) string {
+	return apiName + "_table_verify"
+}
+
+// writeCppTableCopy emits the shim helper that copies a finished FlatBuffer
+// into memory released by <api>_buffer_free. An absent table is NULL.
+func writeCppTableCopy(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, `/* Returned tables are released by %[1]s (std::free). */
+static uint8_t* %[2]s(const flatbuffers::DetachedBuffer* buf, uint32_t* out_len) {
+    *out_len = 0;
+    if (!buf || buf->size() == 0) {
+        return nullptr;
+    }
+    uint8_t* out = static_cast<uint8_t*>(std::malloc(buf->size()));
+    if (out) {
+        std::memcpy(out, buf->data(), buf->size());
+        *out_len = static_cast<uint32_t>(buf->size());
+    }
+    return out;
+}
+
+`, BufferFreeFunctionName(apiName), cppTableCopyName(apiName))
+}
+
+// writeCppTableVerify emits the shim helper that runs the flatc verifier over
+// a table parameter before the implementation reads it.
+func writeCppTableVerify(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, `/* Table parameters are verified, required fields included, before the
+ * implementation reads them. */
+template <typename T>
+static bool %s(const uint8_t* data, uint32_t len) {
+    flatbuffers::Verifier verifier(data, len);
+    return verifier.VerifyBuffer<T>(nullptr);
+}
+
+`, cppTableVerifyName(apiName))
+}
+
+// writeCppStringCopy emits the helper that copies a string into memory released
+// by <api>_string_free. The shim uses it for string returns; implementations
+// use it for the string fields of returned tables.
+func writeCppStringCopy(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, `/* Returned strings are released by %[1]s (std::free). String
+ * fields of returned tables must be allocated with this helper. */
+inline char* %[2]s(std::string_view s) {
+    char* out = static_cast<char*>(std::malloc(s.size() + 1));
+    if (out) {
+        std::memcpy(out, s.data(), s.size());
+        out[s.size()] = '\0';
+    }
+    return out;
+}
+
+`, StringFreeFunctionName(apiName), cppStringCopyName(apiName))
+}
+
+// cppCompletionClassName returns the async completion template name.
+// e.g., "hello_xplatter" → "HelloXplatterCompletion"
+func cppCompletionClassName(apiName string) string {
+	return ToPascalCase(apiName) + "Completion"
+}
+
+// cppCompletionType returns the completion instantiation for an async method.
+func cppCompletionType(apiName string, method *model.MethodDef) string {
+	valueType := "void"
+	if method.Returns != nil {
+		valueType = cppResultReturnType(method.Returns)
+	}
+	return fmt.Sprintf("%s<%s>", cppCompletionClassName(apiName), valueType)
+}
+
+// cppAsyncParams returns the C++ parameter list of an async method: the
+// regular parameters followed by the shared completion.
+func cppAsyncParams(apiName string, method *model.MethodDef) []string {
+	var params []string
+	for _, p := range method.Parameters {
+		params = append(params, formatCppParam(&p)...)
+	}
+	return append(params, fmt.Sprintf("std::shared_ptr<%s> completion", cppCompletionType(apiName, method)))
+}
+
+// cppShimCallArgs converts C ABI parameters to the C++ interface call arguments.
+// Handle parameters pass through as void*, and verified tables become their
+// flatc root accessors.
+func cppShimCallArgs(method *model.MethodDef) []string {
+	var callArgs []string
+	for _, p := range method.Parameters {
+		name := cIdent(p.Name)
+		if p.Optional && model.IsString(p.Type) {
+			callArgs = append(callArgs, fmt.Sprintf("%[1]s ? std::optional<std::string_view>(%[1]s) : std::nullopt", name))
+		} else if hasPresenceFlag(&p) {
+			callArgs = append(callArgs, fmt.Sprintf("%s ? std::optional<%s>(%s) : std::nullopt",
+				PresenceFlagName(p.Name), cppPrimitiveType(p.Type), name))
+		} else if model.IsString(p.Type) {
+			callArgs = append(callArgs, fmt.Sprintf("std::string_view(%s)", name))
+		} else if _, ok := model.IsBuffer(p.Type); ok {
+			callArgs = append(callArgs, fmt.Sprintf("std::span(%s, %s_len)", name, p.Name))
+		} else if p.Table && p.Optional {
+			callArgs = append(callArgs, fmt.Sprintf("%[1]s ? flatbuffers::GetRoot<%[2]s>(%[1]s) : nullptr", name, cppTableType(p.Type)))
+		} else if p.Table {
+			callArgs = append(callArgs, fmt.Sprintf("flatbuffers::GetRoot<%s>(%s)", cppTableType(p.Type), name))
+		} else {
+			callArgs = append(callArgs, name)
+		}
+	}
+	return callArgs
+}
+
+// writeCppCompletion emits the completion template async methods receive.
+// The shim keeps one reference for the binding; when the implementation drops
+// the others without resolving, the next poll reports the operation cancelled.
+func writeCppCompletion(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, `/* Async completion — async methods receive a shared completion. Call resolve()
+ * or reject() exactly once, from any thread. String views, spans and pointers
+ * passed alongside it are only valid during the call; copy what you keep.
+ * Dropping every reference without resolving reports the operation cancelled. */
+class %[1]sAsyncState {
+public:
+    virtual ~%[1]sAsyncState() = default;
+
+    /* Fails the operation with an error enum value. Rejecting an infallible
+     * method reports it cancelled. */
+    void reject(int32_t error) {
+        std::lock_guard<std::mutex> lock(mutex_);
+        if (status_ == %[2]s) {
+            error_ = error;
+            rejected_ = true;
+            status_ = %[3]s;
+        }
+    }
+
+    /* True once the caller has cancelled; stop early and drop the completion. */
+    bool cancelled() const { return cancelled_.load(std::memory_order_acquire); }
+
+    /* Shim side: flags cancellation for the implementation. */
+    void cancel() { cancelled_.store(true, std::memory_order_release); }
+
+protected:
+    /* Shim side, with mutex_ held: the status to report from poll. */
+    int32_t take_status(bool abandoned, int32_t* out_error) {
+        if (status_ == %[2]s) {
+            return abandoned ? %[4]s : %[2]s;
+        }
+        if (rejected_) {
+            if (!out_error) {
+                return %[4]s;
+            }
+            *out_error = error_;
+        } else if (out_error) {
+            *out_error = 0;
+        }
+        return %[3]s;
+    }
+
+    std::mutex mutex_;
+    int32_t status_ = %[2]s;
+    int32_t error_ = 0;
+    bool rejected_ = false;
+    std::atomic<bool> cancelled_{false};
+};
+
+template <typename T>
+class %[5]s : public %[1]sAsyncState {
+public:
+    void resolve(T value) {
+        std::lock_guard<std::mutex> lock(mutex_);
+        if (status_ == %[2]s) {
+            value_ = std::move(value);
+            status_ = %[3]s;
+        }
+    }
+
+    /* Shim side: polls the operation, writing outputs once done. */
+    int32_t take(bool abandoned, int32_t* out_error, T* out_result) {
+        std::lock_guard<std::mutex> lock(mutex_);
+        int32_t status = take_status(abandoned, out_error);
+        if (status == %[3]s && !rejected_) {
+            *out_result = std::move(value_);
+        }
+        return status;
+    }
+
+private:
+    T value_{};
+};
+
+template <>
+class %[5]s<void> : public %[1]sAsyncState {
+public:
+    void resolve() {
+        std::lock_guard<std::mutex> lock(mutex_);
+        if (status_ == %[2]s) {
+            status_ = %[3]s;
+        }
+    }
+
+    /* Shim side: polls the operation. */
+    int32_t take(bool abandoned, int32_t* out_error) {
+        std::lock_guard<std::mutex> lock(mutex_);
+        return take_status(abandoned, out_error);
+    }
+};
+
+`, ToPascalCase(apiName),
+		AsyncStatusConstName(apiName, "pending"), AsyncStatusConstName(apiName, "done"), AsyncStatusConstName(apiName, "cancelled"),
+		cppCompletionClassName(apiName))
+}
+
 // formatCppParam formats a parameter for C++ interface methods.
-// Strings become std::string_view, buffers become std::span<const T>.
+// Strings become std::string_view, buffers become std::span<const T>, and
+// tables their flatc accessor class. Optional strings and primitives are
+// wrapped in std::optional; optional handles, tables and FlatBuffer refs are
+// nullptr when absent.
 func formatCppParam(p *model.ParameterDef) []string {
+	name := cIdent(p.Name)
+	if p.Table {
+		return []string{"const " + cppTableType(p.Type) + "* " + name}
+	}
+	if p.Optional && (model.IsString(p.Type) || model.IsPrimitive(p.Type)) {
+		inner := "std::string_view"
+		if model.IsPrimitive(p.Type) {
+			inner = cppPrimitiveType(p.Type)
+		}
+		return []string{fmt.Sprintf("std::optional<%s> %s", inner, name)}
+	}
 	if model.IsString(p.Type) {
-		return []string{"std::string_view " + p.Name}
+		return []string{"std::string_view " + name}
 	}
 
 	if elemType, ok := model.IsBuffer(p.Type); ok {
 		cppType := cppPrimitiveType(elemType)
 		if p.Transfer == "ref_mut" {
-			return []string{fmt.Sprintf("std::span<%s> %s", cppType, p.Name)}
+			return []string{fmt.Sprintf("std::span<%s> %s", cppType, name)}
 		}
-		return []string{fmt.Sprintf("std::span<const %s> %s", cppType, p.Name)}
+		return []string{fmt.Sprintf("std::span<const %s> %s", cppType, name)}
 	}
 
 	if handleName, ok := model.IsHandle(p.Type); ok {
 		// Handles in the C++ interface stay as opaque pointers (void*)
 		// The shim layer handles the cast.
 		_ = handleName
-		return []string{"void* " + p.Name}
+		return []string{"void* " + name}
 	}
 
 	if model.IsPrimitive(p.Type) {
-		return []string{cppPrimitiveType(p.Type) + " " + p.Name}
+		return []string{cppPrimitiveType(p.Type) + " " + name}
 	}
 
-	// FlatBuffer type — pass as const pointer
+	// FlatBuffer type — by value, or by pointer as the C ABI passes it
 	cType := model.FlatBufferCType(p.Type)
-	if p.Transfer == "ref_mut" {
-		return []string{cType + "* " + p.Name}
+	switch p.Transfer {
+	case "ref_mut":
+		return []string{cType + "* " + name}
+	case "ref":
+		return []string{"const " + cType + "* " + name}
 	}
-	return []string{"const " + cType + "* " + p.Name}
+	return []string{cType + " " + name}
 }
 
 // cppReturnType returns the C++ type for a return value.
 func cppReturnType(retType string) string {
+	if model.IsString(retType) {
+		return "std::string"
+	}
+	if elemType, ok := model.IsBuffer(retType); ok {
+		return "std::vector<" + cppPrimitiveType(elemType) + ">"
+	}
 	if handleName, ok := model.IsHandle(retType); ok {
 		_ = handleName
 		return "void*"
@@ -555,9 +1255,24 @@ func cppReturnType(retType string) string {
 	return model.FlatBufferCType(retType)
 }
 
-// cppOutParamType returns the C++ out-parameter type (pointer to the return type).
-func cppOutParamType(retType string) string {
-	return cppReturnType(retType) + "*"
+// cppResultReturnType is cppReturnType for a method's result: a table is
+// returned as a finished FlatBuffer, built with the flatc builders.
+func cppResultReturnType(ret *model.ReturnDef) string {
+	if ret.Table {
+		return "flatbuffers::DetachedBuffer"
+	}
+	return cppReturnType(ret.Type)
+}
+
+// cppResultType returns the C++ type an interface method produces for a
+// result. Optional results other than handles are wrapped in std::optional;
+// an absent handle is nullptr.
+func cppResultType(ret *model.ReturnDef) string {
+	t := cppResultReturnType(ret)
+	if _, ok := model.IsHandle(ret.Type); ok || !ret.Optional {
+		return t
+	}
+	return "std::optional<" + t + ">"
 }
 
 // cppPrimitiveType maps xplatter primitive types to C++ fixed-width types.
@@ -344,3 +344,443 @@ func TestImplCppGenerator_Registration(t *testing.T) {
 		t.Errorf("expected name impl_cpp, got %q", gen.Name())
 	}
 }
+
+func TestImplCppGenerator_Events(t *testing.T) {
+	ctx := loadTestAPI(t, "events.yaml")
+	gen := &ImplCppGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	iface := string(findOutputFile(t, files, "event_api_interface.h").Content)
+	if !strings.Contains(iface, `#include "event_api_events.h"`) {
+		t.Error("interface header should include the events header")
+	}
+	if !strings.Contains(iface, "static int32_t push_touch_event(std::span<const uint8_t> payload) {") {
+		t.Error("missing push_touch_event helper")
+	}
+	if !strings.Contains(iface, "return event_api_event_push_entity_spawned(payload.data(), static_cast<uint32_t>(payload.size()));") {
+		t.Error("push helper should forward to the C push function")
+	}
+
+	// The shim owns the ring: the implementation macro must precede any include of the events header.
+	shim := string(findOutputFile(t, files, "event_api_shim.cpp").Content)
+	define := strings.Index(shim, "#define EVENT_API_EVENTS_IMPLEMENTATION")
+	ifaceInclude := strings.Index(shim, `#include "event_api_interface.h"`)
+	if define < 0 || ifaceInclude < 0 || define > ifaceInclude {
+		t.Error("shim must define EVENT_API_EVENTS_IMPLEMENTATION before including the interface header")
+	}
+
+	findOutputFile(t, files, "event_api_events.h")
+	findOutputFile(t, files, "event_api_events_test.c")
+}
+
+func TestImplCppGenerator_Async(t *testing.T) {
+	ctx := loadTestAPI(t, "async.yaml")
+	gen := &ImplCppGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	iface := string(findOutputFile(t, files, "async_api_interface.h").Content)
+	for _, want := range []string{
+		"#include <atomic>",
+		"#include <mutex>",
+		"class AsyncApiAsyncState {",
+		"class AsyncApiCompletion : public AsyncApiAsyncState {",
+		"class AsyncApiCompletion<void> : public AsyncApiAsyncState {",
+		"virtual void load_model(void* engine, std::string_view path, std::shared_ptr<AsyncApiCompletion<flatbuffers::DetachedBuffer>> completion) = 0;",
+		"virtual void warm_up(void* engine, std::shared_ptr<AsyncApiCompletion<void>> completion) = 0;",
+		"virtual void fork_engine(void* engine, std::shared_ptr<AsyncApiCompletion<void*>> completion) = 0;",
+	} {
+		if !strings.Contains(iface, want) {
+			t.Errorf("interface header missing %q", want)
+		}
+	}
+
+	shim := string(findOutputFile(t, files, "async_api_shim.cpp").Content)
+	for _, want := range []string{
+		"ASYNC_API_EXPORT async_api_async_op async_api_assets_load_model_start(engine_handle engine, const char* path) {",
+		"auto completion = std::make_shared<AsyncApiCompletion<flatbuffers::DetachedBuffer>>();",
+		"self->load_model(engine, std::string_view(path), completion);",
+		"return reinterpret_cast<async_api_async_op>(new std::shared_ptr<AsyncApiCompletion<flatbuffers::DetachedBuffer>>(std::move(completion)));",
+		"ASYNC_API_EXPORT int32_t async_api_assets_load_model_poll(async_api_async_op op, int32_t* out_error, uint8_t** out_result, uint32_t* out_result_len) {",
+		"    flatbuffers::DetachedBuffer result;\n    int32_t status = (*ref)->take(abandoned, out_error, &result);\n    if (status == ASYNC_API_ASYNC_DONE && *out_error == 0) {\n        *out_result = async_api_table_copy(&result, out_result_len);\n    }\n",
+		"ASYNC_API_EXPORT void async_api_assets_load_model_cancel(async_api_async_op op) {",
+	} {
+		if !strings.Contains(shim, want) {
+			t.Errorf("shim missing %q", want)
+		}
+	}
+
+	implH := string(findOutputFile(t, files, "async_api_impl.h").Content)
+	if !strings.Contains(implH, "void decode(void* engine, std::span<const uint8_t> data, std::shared_ptr<AsyncApiCompletion<uint32_t>> completion) override;") {
+		t.Error("impl header should declare async methods with a completion")
+	}
+
+	// No async methods, no completion types
+	ctx = loadTestAPI(t, "minimal.yaml")
+	files, err = gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if strings.Contains(string(findOutputFile(t, files, "test_api_interface.h").Content), "Completion") {
+		t.Error("completion types should only be emitted with async methods")
+	}
+}
+
+func TestImplCppGenerator_StringReturns(t *testing.T) {
+	ctx := loadTestAPI(t, "strings.yaml")
+	gen := &ImplCppGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	iface := string(findOutputFile(t, files, "strings_api_interface.h").Content)
+	for _, want := range []string{
+		"#include <cstdlib>",
+		"#include <cstring>",
+		"inline char* strings_api_string_copy(std::string_view s) {",
+		"virtual std::string get_name(void* engine) = 0;",
+		"virtual int32_t describe(void* engine, std::string_view key, std::string* out_result) = 0;",
+		"virtual void fetch_label(void* engine, std::shared_ptr<StringsApiCompletion<std::string>> completion) = 0;",
+	} {
+		if !strings.Contains(iface, want) {
+			t.Errorf("interface header missing %q", want)
+		}
+	}
+
+	shim := string(findOutputFile(t, files, "strings_api_shim.cpp").Content)
+	for _, want := range []string{
+		"STRINGS_API_EXPORT void strings_api_string_free(char* str) {\n    std::free(str);\n}",
+		"return strings_api_string_copy(self->get_name(engine));",
+		"std::string result;\n    int32_t err = self->describe(engine, std::string_view(key), &result);\n    if (err == 0) {\n        *out_result = strings_api_string_copy(result);\n    }\n    return err;",
+		"if (status == STRINGS_API_ASYNC_DONE && *out_error == 0) {\n        *out_result = strings_api_string_copy(result);\n    }",
+	} {
+		if !strings.Contains(shim, want) {
+			t.Errorf("shim missing %q", want)
+		}
+	}
+
+	ctx = loadTestAPI(t, "minimal.yaml")
+	files, err = gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if strings.Contains(string(findOutputFile(t, files, "test_api_interface.h").Content), "_string_copy") {
+		t.Error("string copy helper should only be emitted when strings are returned")
+	}
+}
+
+func TestImplCppGenerator_BufferReturns(t *testing.T) {
+	ctx := loadTestAPI(t, "buffers.yaml")
+	gen := &ImplCppGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	iface := string(findOutputFile(t, files, "buffers_api_interface.h").Content)
+	for _, want := range []string{
+		"#include <vector>",
+		"virtual int32_t encode_frame(void* engine, std::span<const uint8_t> pixels, std::vector<uint8_t>* out_result) = 0;",
+		"virtual std::vector<float> read_tensor(void* engine) = 0;",
+	} {
+		if !strings.Contains(iface, want) {
+			t.Errorf("interface header missing %q", want)
+		}
+	}
+
+	shim := string(findOutputFile(t, files, "buffers_api_shim.cpp").Content)
+	for _, want := range []string{
+		"static T* buffers_api_buffer_copy(const std::vector<T>& v, uint32_t* out_len) {",
+		"BUFFERS_API_EXPORT void buffers_api_buffer_free(void* data) {\n    std::free(data);\n}",
+		"*out_result = buffers_api_buffer_copy(self->read_tensor(engine), out_result_len);",
+		"std::vector<int64_t> result;\n    int32_t err = self->sample_ids(engine, count, &result);\n    if (err == 0) {\n        *out_result = buffers_api_buffer_copy(result, out_result_len);\n    }\n    return err;",
+	} {
+		if !strings.Contains(shim, want) {
+			t.Errorf("shim missing %q", want)
+		}
+	}
+
+	ctx = loadTestAPI(t, "minimal.yaml")
+	files, err = gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if strings.Contains(string(findOutputFile(t, files, "test_api_shim.cpp").Content), "_buffer_copy") {
+		t.Error("buffer copy helper should only be emitted when buffers are returned")
+	}
+}
+
+func TestImplCppGenerator_Optionals(t *testing.T) {
+	ctx := loadTestAPI(t, "optional.yaml")
+	gen := &ImplCppGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	iface := string(findOutputFile(t, files, "optional_api_interface.h").Content)
+	for _, want := range []string{
+		"#include <optional>",
+		"virtual void set_label(void* engine, std::optional<std::string_view> label) = 0;",
+		"virtual int32_t set_limit(void* engine, std::optional<uint32_t> limit) = 0;",
+		"virtual std::optional<std::string> get_label(void* engine) = 0;",
+		"virtual std::optional<uint32_t> get_limit(void* engine) = 0;",
+		"virtual void attach(void* engine, void* parent, const Rendering::RendererConfig* config) = 0;",
+		"virtual int32_t get_config(void* engine, std::optional<flatbuffers::DetachedBuffer>* out_result) = 0;",
+	} {
+		if !strings.Contains(iface, want) {
+			t.Errorf("interface header missing %q", want)
+		}
+	}
+
+	shim := string(findOutputFile(t, files, "optional_api_shim.cpp").Content)
+	for _, want := range []string{
+		"self->set_label(engine, label ? std::optional<std::string_view>(label) : std::nullopt);",
+		"self->set_limit(engine, has_limit ? std::optional<uint32_t>(limit) : std::nullopt);",
+		"*out_has_result = result.has_value();",
+	} {
+		if !strings.Contains(shim, want) {
+			t.Errorf("shim missing %q", want)
+		}
+	}
+
+	ctx = loadTestAPI(t, "minimal.yaml")
+	files, err = gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if strings.Contains(string(findOutputFile(t, files, "test_api_interface.h").Content), "#include <optional>") {
+		t.Error("<optional> should only be included when the API declares optionals")
+	}
+}
+
+func TestImplCppGenerator_Extends(t *testing.T) {
+	ctx := loadTestAPI(t, "extends.yaml")
+	gen := &ImplCppGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	iface := string(findOutputFile(t, files, "extends_api_interface.h").Content)
+	for _, want := range []string{
+		"/* node */\nclass ExtendsApiNodeInterface {\npublic:\n    virtual ~ExtendsApiNodeInterface() = default;\n",
+		"    virtual void set_name(void* node, std::string_view name) = 0;",
+		"class ExtendsApiInterface : public ExtendsApiNodeInterface {",
+		"    /* texture */\n    virtual uint32_t get_width(void* texture) = 0;",
+	} {
+		if !strings.Contains(iface, want) {
+			t.Errorf("interface header missing %q", want)
+		}
+	}
+	if strings.Count(iface, "virtual void set_name(") != 1 {
+		t.Error("base interface methods should be declared once, on the base class")
+	}
+
+	shim := string(findOutputFile(t, files, "extends_api_shim.cpp").Content)
+	if !strings.Contains(shim, "self->set_name(") {
+		t.Error("shim should call inherited methods through the interface class")
+	}
+}
+
+func TestCppBaseInterfaceClassName(t *testing.T) {
+	if got := cppBaseInterfaceClassName("hello_xplatter", "scene_node"); got != "HelloXplatterSceneNodeInterface" {
+		t.Errorf("cppBaseInterfaceClassName = %q", got)
+	}
+}
+
+func TestImplCppGenerator_Lifecycle(t *testing.T) {
+	ctx := loadTestAPI(t, "lifecycle.yaml")
+	gen := &ImplCppGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	iface := string(findOutputFile(t, files, "lifecycle_api_interface.h").Content)
+	for _, want := range []string{
+		"    /* Since 1.2.0. */\n    virtual void draw_quad(",
+		"    /* Since 1.0.0. */\n    [[deprecated(\"Quads replace sprites. Use draw_quad instead.\")]]\n    virtual void draw_sprite(",
+		"    /* Experimental: May change or be removed without a deprecation period. */\n    virtual int32_t set_vsync(",
+		"    [[deprecated(\"The legacy \\\"immediate\\\" path is retired\")]]\n    virtual void flush(",
+	} {
+		if !strings.Contains(iface, want) {
+			t.Errorf("interface header missing %q", want)
+		}
+	}
+
+	shim := string(findOutputFile(t, files, "lifecycle_api_shim.cpp").Content)
+	if !strings.Contains(shim, "#pragma GCC diagnostic ignored \"-Wdeprecated-declarations\"") {
+		t.Error("shim should silence warnings for the deprecated methods it forwards")
+	}
+}
+
+func TestImplCppGenerator_ThreadAffinity(t *testing.T) {
+	ctx := loadTestAPI(t, "threading.yaml")
+	gen := &ImplCppGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	findOutputFile(t, files, "threading_api_threading.h")
+
+	shim := string(findOutputFile(t, files, "threading_api_shim.cpp").Content)
+	for _, want := range []string{
+		"#define THREADING_API_THREADING_IMPLEMENTATION\n#include \"threading_api_threading.h\"\n",
+		"threading_api_window_create_window(window_handle* out_result) {\n    THREADING_API_ASSERT_MAIN_THREAD();\n",
+		"threading_api_window_destroy_window(window_handle window) {\n    THREADING_API_ASSERT_MAIN_THREAD();\n",
+		"    *out_result = reinterpret_cast<session_handle>(instance);\n    THREADING_API_THREAD_RECORD(*out_result);\n    return 0;\n",
+		"    THREADING_API_ASSERT_CREATOR_THREAD(session);\n    THREADING_API_THREAD_FORGET(session);\n    ThreadingApiInterface* instance",
+		"threading_api_session_send(session_handle session, int32_t value) {\n    THREADING_API_ASSERT_CREATOR_THREAD(session);\n",
+		"threading_api_session_fetch_start(session_handle session) {\n    THREADING_API_ASSERT_CREATOR_THREAD(session);\n",
+		"threading_api_display_show_count(counter_handle counter) {\n    THREADING_API_ASSERT_MAIN_THREAD();\n",
+	} {
+		if !strings.Contains(shim, want) {
+			t.Errorf("shim missing %q", want)
+		}
+	}
+	if strings.Contains(shim, "threading_api_window_content_scale(window_handle window) {\n    THREADING_API") {
+		t.Error("content_scale overrides the Window affinity with any and should not be checked")
+	}
+	if strings.Count(shim, "THREADING_API_THREAD_RECORD(") != 1 {
+		t.Error("only the Session constructor should record its creating thread")
+	}
+
+	iface := string(findOutputFile(t, files, "threading_api_interface.h").Content)
+	for _, want := range []string{
+		"    /* Must be called on the main thread. */\n    virtual void set_title(",
+		"    /* Must be called on the thread that created the Session. */\n    virtual int32_t send(",
+	} {
+		if !strings.Contains(iface, want) {
+			t.Errorf("interface header missing %q", want)
+		}
+	}
+}
+
+func TestImplCppGenerator_Ownership(t *testing.T) {
+	ctx := loadTestAPI(t, "ownership.yaml")
+	gen := &ImplCppGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(findOutputFile(t, files, "ownership_api_interface.h").Content)
+
+	for _, want := range []string{
+		"    /* Takes ownership of material; do not use or destroy it afterwards. */\n    virtual void adopt_material(",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("C++ interface missing %q", want)
+		}
+	}
+}
+
+func TestImplCppGenerator_Tables(t *testing.T) {
+	ctx := loadTestAPI(t, "fields.yaml")
+	gen := &ImplCppGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	iface := string(findOutputFile(t, files, "fields_api_interface.h").Content)
+	for _, want := range []string{
+		"#include \"flatbuffers/flatbuffers.h\"\n#include \"fields_generated.h\"\n",
+		"virtual int32_t reconfigure(void* stream, const Fields::StreamConfig* config) = 0;",
+		"virtual flatbuffers::DetachedBuffer info(void* stream) = 0;",
+	} {
+		if !strings.Contains(iface, want) {
+			t.Errorf("interface header missing %q", want)
+		}
+	}
+
+	shim := string(findOutputFile(t, files, "fields_api_shim.cpp").Content)
+	for _, want := range []string{
+		"#include <cstdio>\n#include <cstdlib>\n",
+		"static bool fields_api_table_verify(const uint8_t* data, uint32_t len) {\n    flatbuffers::Verifier verifier(data, len);\n    return verifier.VerifyBuffer<T>(nullptr);\n}",
+		"fields_api_stream_create_stream(const uint8_t* config, uint32_t config_len, stream_handle* out_result) {\n    if (!fields_api_table_verify<Fields::StreamConfig>(config, config_len)) {\n        return 1;\n    }\n",
+		"    return self->reconfigure(stream, flatbuffers::GetRoot<Fields::StreamConfig>(config));\n",
+		"    if (!fields_api_table_verify<Fields::StreamConfig>(config, config_len)) {\n        std::fprintf(stderr, \"%s\\n\", \"fields_api_stream_restart: config is not a valid Fields.StreamConfig FlatBuffer\");\n        std::abort();\n    }\n",
+		"\"fields_api_stream_prepare_start: config is not a valid Fields.StreamConfig FlatBuffer\");\n        std::abort();\n",
+		"static uint8_t* fields_api_table_copy(const flatbuffers::DetachedBuffer* buf, uint32_t* out_len) {",
+		"    flatbuffers::DetachedBuffer result = self->info(stream);\n    *out_result = fields_api_table_copy(&result, out_result_len);\n",
+	} {
+		if !strings.Contains(shim, want) {
+			t.Errorf("shim missing %q", want)
+		}
+	}
+
+	cmake := string(findOutputFile(t, files, "CMakeLists.txt").Content)
+	for _, want := range []string{
+		"find_package(flatbuffers CONFIG REQUIRED)",
+		"target_include_directories(fields_api PRIVATE ${CMAKE_CURRENT_SOURCE_DIR}/generated/flatbuffers/cpp)",
+		"target_link_libraries(fields_api PRIVATE flatbuffers::flatbuffers)",
+	} {
+		if !strings.Contains(cmake, want) {
+			t.Errorf("CMakeLists.txt missing %q", want)
+		}
+	}
+}
+
+func TestImplCppGenerator_OptionalTables(t *testing.T) {
+	ctx := loadTestAPI(t, "optional.yaml")
+	gen := &ImplCppGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	shim := string(findOutputFile(t, files, "optional_api_shim.cpp").Content)
+
+	for _, want := range []string{
+		"    if (config && !optional_api_table_verify<Rendering::RendererConfig>(config, config_len)) {\n",
+		"config ? flatbuffers::GetRoot<Rendering::RendererConfig>(config) : nullptr",
+		"        *out_result = optional_api_table_copy(result ? &*result : nullptr, out_result_len);\n",
+	} {
+		if !strings.Contains(shim, want) {
+			t.Errorf("shim missing %q", want)
+		}
+	}
+}
+
+func TestImplCppGenerator_BitFlags(t *testing.T) {
+	ctx := loadTestAPI(t, "flags.yaml")
+	gen := &ImplCppGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	iface := string(findOutputFile(t, files, "flags_api_interface.h").Content)
+
+	for _, want := range []string{
+		"inline constexpr Media_Caps operator|(Media_Caps a, Media_Caps b) { return static_cast<Media_Caps>(static_cast<uint32_t>(a) | static_cast<uint32_t>(b)); }\n",
+		"inline constexpr Media_Caps operator~(Media_Caps a) { return static_cast<Media_Caps>(~static_cast<uint32_t>(a) & 0x33u); }\n",
+		"inline Media_Caps& operator&=(Media_Caps& a, Media_Caps b) { return a = a & b; }\n",
+		"inline constexpr bool has_flags(Media_Caps value, Media_Caps flags) { return (value & flags) == flags; }\n",
+		"static_cast<Media_Channels>(~static_cast<uint64_t>(a) & 0x10000000003ull)",
+	} {
+		if !strings.Contains(iface, want) {
+			t.Errorf("interface header missing %q", want)
+		}
+	}
+	if strings.Contains(iface, "operator|(Media_ErrorCode") {
+		t.Error("only bit_flags enums should get operators")
+	}
+}
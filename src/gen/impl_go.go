@@ -15,7 +15,8 @@ func init() {
 
 // GoImplGenerator produces Go implementation scaffolding:
 // an interface file, a cgo shim file, a stub implementation file,
-// type definitions, and a go.mod.
+// type definitions, a go.mod, and — when events are declared — the event
+// ring with its signaling and tests.
 //
 // The generated interface excludes lifecycle methods (create/destroy) and
 // removes handle parameters — the shim manages handle↔impl mapping so
@@ -52,16 +53,36 @@ func (g *GoImplGenerator) Generate(ctx *Context) ([]*OutputFile, error) {
 	files := []*OutputFile{ifaceFile, cgoFile, implFile}
 
 	if len(ctx.ResolvedTypes) > 0 {
-		typesFile := g.generateTypes(ctx.ResolvedTypes, apiName, api)
-		typesFile.Content = prependHeader(genHeader, typesFile.Content)
-		files = append(files, typesFile)
+		typesFiles, err := g.generateTypes(ctx.ResolvedTypes, apiName, api)
+		if err != nil {
+			return nil, fmt.Errorf("generating types: %w", err)
+		}
+		for _, f := range typesFiles {
+			f.Content = prependHeader(genHeader, f.Content)
+		}
+		files = append(files, typesFiles...)
+	}
+
+	if len(api.Events) > 0 {
+		files = append(files, g.generateEvents(ctx, api, apiName)...)
+	}
+
+	if hasThreadChecks(api) {
+		files = append(files, generateGoThreading(apiName, genHeader)...)
+	}
+
+	hasAsync := hasAsyncMethods(api)
+	if hasAsync {
+		asyncFile := g.generateAsync(apiName)
+		asyncFile.Content = prependHeader(genHeader, asyncFile.Content)
+		files = append(files, asyncFile)
 	}
 
 	goModFile := g.generateGoMod(api)
 	goModFile.Content = prependHeader(scaffoldHeader, goModFile.Content)
 	files = append(files, goModFile)
 
-	gitignoreFile := g.generateGitignore(apiName)
+	gitignoreFile := g.generateGitignore(apiName, len(api.Events) > 0, hasAsync, hasThreadChecks(api))
 	files = append(files, gitignoreFile)
 
 	return files, nil
@@ -76,6 +97,12 @@ func goReturnStructName(t string) string {
 // goReturnStructType returns the Go type name for a method return type,
 // using generated Go structs for FlatBuffer types.
 func goReturnStructType(t string) string {
+	if model.IsString(t) {
+		return "string"
+	}
+	if elemType, ok := model.IsBuffer(t); ok {
+		return "[]" + primitiveGoType(elemType)
+	}
 	if _, ok := model.IsHandle(t); ok {
 		return "uintptr"
 	}
@@ -87,7 +114,15 @@ func goReturnStructType(t string) string {
 }
 
 // goReturnStructZeroValue returns the zero value for a Go return struct type.
-func goReturnStructZeroValue(t string) string {
+// A union's zero value is nil, the unset union; enums and bit_flags are named
+// integer types, so 0.
+func goReturnStructZeroValue(t string, resolved resolver.ResolvedTypes) string {
+	if model.IsString(t) {
+		return `""`
+	}
+	if _, ok := model.IsBuffer(t); ok {
+		return "nil"
+	}
 	if _, ok := model.IsHandle(t); ok {
 		return "0"
 	}
@@ -99,6 +134,12 @@ func goReturnStructZeroValue(t string) string {
 			return "0"
 		}
 	}
+	if isUnionType(resolved, t) || isTableType(resolved, t) {
+		return "nil"
+	}
+	if isEnumType(resolved, t) {
+		return "0"
+	}
 	// FlatBuffer struct — zero value is the struct name with empty fields
 	return goReturnStructName(t) + "{}"
 }
@@ -114,6 +155,7 @@ func (g *GoImplGenerator) generateInterface(api *model.APIDefinition, apiName st
 
 	fmt.Fprintf(&b, "package %s\n", pkgName)
 	b.WriteString("\n")
+	writeGoImports(&b, goTableImports(api))
 
 	for _, iface := range api.Interfaces {
 		// Interface only includes regular methods (constructors/destructor are shim-handled).
@@ -122,10 +164,17 @@ func (g *GoImplGenerator) generateInterface(api *model.APIDefinition, apiName st
 		}
 
 		ifaceName := ToPascalCase(iface.Name)
+		var doc []string
 		if iface.Description != "" {
-			fmt.Fprintf(&b, "// %s %s\n", ifaceName, iface.Description)
+			doc = append(doc, ifaceName+" "+iface.Description)
 		}
+		writeGoDocComment(&b, "", append(doc, goLifecycleParagraphs(ifaceName, &iface.Lifecycle)...))
 		fmt.Fprintf(&b, "type %s interface {\n", ifaceName)
+		if iface.Extends != "" {
+			// Embedding the base interface makes every derived handle's impl
+			// satisfy the base interface's shim functions too.
+			fmt.Fprintf(&b, "\t%s\n", ToPascalCase(iface.Extends))
+		}
 		for _, method := range iface.Methods {
 			writeGoInterfaceMethod(&b, &method, resolved)
 		}
@@ -140,8 +189,11 @@ func (g *GoImplGenerator) generateInterface(api *model.APIDefinition, apiName st
 // Handle parameters are excluded (the shim resolves handles to impl instances).
 func writeGoInterfaceMethod(b *strings.Builder, method *model.MethodDef, resolved resolver.ResolvedTypes) {
 	methodName := ToPascalCase(method.Name)
-	hasError := method.Error != ""
-	hasReturn := method.Returns != nil
+	paras := goLifecycleParagraphs(methodName, &method.Lifecycle)
+	if note := borrowedReturnNote(method); note != "" {
+		paras = append(paras, note)
+	}
+	writeGoDocComment(b, "\t", paras)
 
 	// Build parameter list, excluding handle parameters
 	var params []string
@@ -151,20 +203,15 @@ func writeGoInterfaceMethod(b *strings.Builder, method *model.MethodDef, resolve
 		}
 		params = append(params, goInterfaceParamSignature(&p, resolved))
 	}
+	if method.Async {
+		// Async methods report through the completion instead of returning.
+		params = append(params, "completion *"+goCompletionType(method))
+		fmt.Fprintf(b, "\t%s(%s)\n", methodName, strings.Join(params, ", "))
+		return
+	}
 	paramStr := strings.Join(params, ", ")
 
-	// Determine return signature
-	var retSig string
-	switch {
-	case hasError && hasReturn:
-		retSig = fmt.Sprintf("(%s, error)", goReturnStructType(method.Returns.Type))
-	case hasError && !hasReturn:
-		retSig = "error"
-	case !hasError && hasReturn:
-		retSig = goReturnStructType(method.Returns.Type)
-	default:
-		retSig = ""
-	}
+	retSig := goReturnSignature(method)
 
 	if retSig != "" {
 		fmt.Fprintf(b, "\t%s(%s) %s\n", methodName, paramStr, retSig)
@@ -173,13 +220,120 @@ func writeGoInterfaceMethod(b *strings.Builder, method *model.MethodDef, resolve
 	}
 }
 
+// goLifecycleParagraphs returns the doc comment paragraphs for a declaration's
+// lifecycle annotations, ending with the standard "Deprecated:" paragraph.
+func goLifecycleParagraphs(name string, l *model.Lifecycle) []string {
+	var paras []string
+	if l.Since != "" {
+		paras = append(paras, fmt.Sprintf("%s is available since %s.", name, l.Since))
+	}
+	if l.IsExperimental() {
+		paras = append(paras, experimentalNote)
+	}
+	if l.Deprecated != nil {
+		paras = append(paras, "Deprecated: "+deprecationText(l.Deprecated, ToPascalCase))
+	}
+	return paras
+}
+
+// writeGoDocComment writes paragraphs as a Go doc comment.
+func writeGoDocComment(b *strings.Builder, indent string, paras []string) {
+	for i, para := range paras {
+		if i > 0 {
+			fmt.Fprintf(b, "%s//\n", indent)
+		}
+		fmt.Fprintf(b, "%s// %s\n", indent, para)
+	}
+}
+
+// writeGoImports writes an import declaration of the non-empty groups of
+// imports, separated by blank lines, if there are any. An import is a path,
+// optionally preceded by a package name and a space.
+func writeGoImports(b *strings.Builder, groups ...[]string) {
+	var lines []string
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		for _, path := range group {
+			if name, p, ok := strings.Cut(path, " "); ok {
+				lines = append(lines, fmt.Sprintf("\t%s %q", name, p))
+			} else {
+				lines = append(lines, fmt.Sprintf("\t%q", path))
+			}
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "import (\n%s\n)\n\n", strings.Join(lines, "\n"))
+}
+
+// goResultType returns the Go type of a method result. A table is returned
+// as its finished FlatBuffer, e.g. from flatbuffers.Builder.FinishedBytes.
+func goResultType(ret *model.ReturnDef) string {
+	if ret.Table {
+		return "[]byte"
+	}
+	return goReturnStructType(ret.Type)
+}
+
+// goReturnSignature returns the Go result list of a synchronous interface
+// method, or empty string for void. Optional results other than handles add
+// an ok flag after the value.
+func goReturnSignature(method *model.MethodDef) string {
+	hasError := method.Error != ""
+	if method.Returns == nil {
+		if hasError {
+			return "error"
+		}
+		return ""
+	}
+	results := []string{goResultType(method.Returns)}
+	if goReturnsOkPair(method) {
+		results = append(results, "bool")
+	}
+	if hasError {
+		results = append(results, "error")
+	}
+	if len(results) == 1 {
+		return results[0]
+	}
+	return "(" + strings.Join(results, ", ") + ")"
+}
+
+// goReturnsOkPair reports whether a method returns its optional result as a
+// (value, ok) pair. Absent handles are 0 instead.
+func goReturnsOkPair(method *model.MethodDef) bool {
+	if !isOptionalReturn(method) {
+		return false
+	}
+	_, isHandle := model.IsHandle(method.Returns.Type)
+	return !isHandle
+}
+
 // goInterfaceParamSignature returns a Go parameter as "name type" for an interface method.
+// Optional strings and primitives are pointers that are nil when absent, and a
+// ref_mut struct is a pointer whose changes are copied back to the caller.
 func goInterfaceParamSignature(p *model.ParameterDef, resolved resolver.ResolvedTypes) string {
-	name := ToCamelCase(p.Name)
+	name := goIdent(ToCamelCase(p.Name))
 	goType := goInterfaceParamType(p.Type, resolved)
+	if p.Optional && (model.IsString(p.Type) || model.IsPrimitive(p.Type)) || isMutableStructParam(p, resolved) {
+		goType = "*" + goType
+	}
 	return name + " " + goType
 }
 
+// isMutableStructParam reports whether p is a FlatBuffers struct the callee
+// may modify.
+func isMutableStructParam(p *model.ParameterDef, resolved resolver.ResolvedTypes) bool {
+	info, ok := resolved[p.Type]
+	return ok && info.Kind == resolver.TypeKindStruct && p.Transfer == "ref_mut"
+}
+
 // goInterfaceParamType returns the Go type for an interface parameter.
 func goInterfaceParamType(t string, resolved resolver.ResolvedTypes) string {
 	if model.IsString(t) {
@@ -194,6 +348,10 @@ func goInterfaceParamType(t string, resolved resolver.ResolvedTypes) string {
 	if model.IsPrimitive(t) {
 		return primitiveGoType(t)
 	}
+	if isTableType(resolved, t) {
+		// Read through the flatc accessors; nil when an optional table is absent.
+		return goTableType(t)
+	}
 	// FlatBuffer type — use generated Go struct
 	return goReturnStructName(t)
 }
@@ -214,31 +372,46 @@ func (g *GoImplGenerator) generateCgo(api *model.APIDefinition, apiName string,
 	// cgo preamble — local C typedefs instead of #include (avoids prototype conflicts)
 	b.WriteString("/*\n#include <stdint.h>\n#include <stdbool.h>\n#include <stdlib.h>\n\n")
 	WriteCTypedefs(&b, api.Handles, ctx.ResolvedTypes)
-	b.WriteString(`*/
-import "C"
-
-import (
-	"sync"
-	"sync/atomic"
-	"unsafe"
-)
-
-`)
+	if hasAsyncMethods(api) {
+		opType := AsyncOpTypeName(apiName)
+		fmt.Fprintf(&b, "typedef struct %s_s* %s;\n\n", opType, opType)
+	}
+	b.WriteString("*/\nimport \"C\"\n\n")
+	hasTables := hasTableParams(api)
+	imports := []string{"sync", "sync/atomic", "unsafe"}
+	if hasTables {
+		imports = append([]string{"encoding/binary"}, imports...)
+	}
+	writeGoImports(&b, imports, goTableImports(api))
 
 	// Handle management
 	writeCgoHandleHelpers(&b)
+	writeCgoABIInfo(&b, apiName, ABIInfo(ctx))
+
+	if hasStringReturns(api) {
+		writeCgoStringFree(&b, apiName)
+	}
+	if hasBufferReturns(api, ctx.ResolvedTypes) {
+		writeCgoBufferHelpers(&b, apiName)
+	}
+	if hasTables {
+		writeCgoTableHelpers(&b)
+	}
 
 	// Export functions for each interface.
 	for _, iface := range api.Interfaces {
 		fmt.Fprintf(&b, "/* %s */\n\n", iface.Name)
+		handleName, hasConstructors := iface.ConstructorHandleName()
+		recordThread := hasConstructors && recordsCreator(api, handleName)
 		// Constructors
 		for _, ctor := range iface.Constructors {
-			writeCgoConstructorFunc(&b, apiName, iface.Name, &ctor)
+			writeCgoConstructorFunc(&b, apiName, iface.Name, &ctor, recordThread, ctx.ResolvedTypes)
 			b.WriteString("\n")
 		}
 		// Auto-destructor
-		if handleName, ok := iface.ConstructorHandleName(); ok {
-			writeCgoDestructorFunc(&b, apiName, iface.Name, handleName)
+		if hasConstructors {
+			destructor := threadAffinityDestructor(api, &iface, handleName)
+			writeCgoDestructorFunc(&b, apiName, iface.Name, &destructor, recordThread)
 			b.WriteString("\n")
 		}
 		// Regular methods
@@ -248,6 +421,10 @@ import (
 		}
 	}
 
+	if len(api.Events) > 0 {
+		writeCgoEventExports(&b, apiName)
+	}
+
 	filename := apiName + "_cgo.go"
 	return &OutputFile{Path: filename, Content: []byte(b.String())}, nil
 }
@@ -258,7 +435,6 @@ func writeCgoHandleHelpers(b *strings.Builder) {
 var (
 	_handles    sync.Map
 	_nextHandle atomic.Uintptr
-	_cstrCache  sync.Map // handle key → []*C.char for borrowing-only lifetime
 )
 
 func _allocHandle(impl interface{}) uintptr {
@@ -269,36 +445,86 @@ func _allocHandle(impl interface{}) uintptr {
 
 func _freeHandle(key uintptr) {
 	_handles.Delete(key)
-	// Free any cached C strings for this handle
-	if val, ok := _cstrCache.LoadAndDelete(key); ok {
-		for _, cs := range val.([]*C.char) {
-			C.free(unsafe.Pointer(cs))
-		}
+}
+
+`)
+}
+
+// writeCgoABIInfo writes the export that returns the ABI info string, copied
+// into C memory once and never released.
+func writeCgoABIInfo(b *strings.Builder, apiName, abiInfo string) {
+	funcName := ABIInfoFunctionName(apiName)
+	fmt.Fprintf(b, "var _abiInfo = C.CString(%q)\n\n", abiInfo)
+	fmt.Fprintf(b, "//export %s\n", funcName)
+	fmt.Fprintf(b, "func %s() *C.char {\n", funcName)
+	b.WriteString("\treturn _abiInfo\n")
+	b.WriteString("}\n\n")
+}
+
+// writeCgoStringFree writes the export that releases returned strings. Every
+// returned string is a C.CString copy owned by the caller.
+func writeCgoStringFree(b *strings.Builder, apiName string) {
+	funcName := StringFreeFunctionName(apiName)
+	fmt.Fprintf(b, "//export %s\n", funcName)
+	fmt.Fprintf(b, "func %s(str *C.char) {\n", funcName)
+	b.WriteString("\tC.free(unsafe.Pointer(str))\n")
+	b.WriteString("}\n\n")
+}
+
+// writeCgoBufferHelpers writes the helper that copies returned slices into C
+// memory and the export that releases them.
+func writeCgoBufferHelpers(b *strings.Builder, apiName string) {
+	funcName := BufferFreeFunctionName(apiName)
+	fmt.Fprintf(b, `// _cBuffer copies a returned slice into C memory owned by the caller and
+// released with %[1]s. An empty slice comes back as nil.
+func _cBuffer[T any](s []T) (unsafe.Pointer, C.uint32_t) {
+	if len(s) == 0 {
+		return nil, 0
 	}
+	p := C.malloc(C.size_t(len(s)) * C.size_t(unsafe.Sizeof(s[0])))
+	copy(unsafe.Slice((*T)(p), len(s)), s)
+	return p, C.uint32_t(len(s))
 }
 
-// _cacheStrings allocates C strings and caches them for the handle's lifetime.
-// Previous cache for this handle is freed first (borrowing-only semantics).
-func _cacheStrings(handleKey uintptr, ss ...string) []*C.char {
-	// Free previous cache
-	if val, ok := _cstrCache.LoadAndDelete(handleKey); ok {
-		for _, cs := range val.([]*C.char) {
-			C.free(unsafe.Pointer(cs))
-		}
+//export %[1]s
+func %[1]s(data unsafe.Pointer) {
+	C.free(data)
+}
+
+`, funcName)
+}
+
+// writeCgoTableHelpers writes the helpers that check and read table
+// parameters. The Go FlatBuffers runtime has no verifier, so _tableValid only
+// checks that the root table and its vtable lie within the buffer.
+func writeCgoTableHelpers(b *strings.Builder) {
+	b.WriteString(`// _tableBytes aliases a table parameter's FlatBuffer, which is only valid
+// during the call.
+func _tableBytes(data *C.uint8_t, n C.uint32_t) []byte {
+	return unsafe.Slice((*byte)(unsafe.Pointer(data)), n)
+}
+
+// _tableValid reports whether a table parameter's root table and its vtable
+// lie within the buffer.
+func _tableValid(data *C.uint8_t, n C.uint32_t) bool {
+	if data == nil || n < 8 {
+		return false
 	}
-	result := make([]*C.char, len(ss))
-	for i, s := range ss {
-		result[i] = C.CString(s)
+	buf := _tableBytes(data, n)
+	root := int64(binary.LittleEndian.Uint32(buf))
+	if root+4 > int64(n) {
+		return false
 	}
-	_cstrCache.Store(handleKey, result)
-	return result
+	vtable := root - int64(int32(binary.LittleEndian.Uint32(buf[root:])))
+	return vtable >= 0 && vtable+4 <= int64(n)
 }
 
 `)
 }
 
-// writeCgoConstructorFunc writes an //export annotated cgo constructor that allocates a handle.
-func writeCgoConstructorFunc(b *strings.Builder, apiName, ifaceName string, ctor *model.MethodDef) {
+// writeCgoConstructorFunc writes an //export annotated cgo constructor that
+// allocates a handle, recording the creating thread when recordThread is set.
+func writeCgoConstructorFunc(b *strings.Builder, apiName, ifaceName string, ctor *model.MethodDef, recordThread bool, resolved resolver.ResolvedTypes) {
 	funcName := CABIFunctionName(apiName, ifaceName, ctor.Name)
 	handleName, _ := model.IsHandle(ctor.Returns.Type)
 	handleTypedef := HandleTypedefName(handleName)
@@ -313,28 +539,42 @@ func writeCgoConstructorFunc(b *strings.Builder, apiName, ifaceName string, ctor
 	fmt.Fprintf(b, "//export %s\n", funcName)
 	paramStr := strings.Join(cParams, ", ")
 	fmt.Fprintf(b, "func %s(%s) C.int32_t {\n", funcName, paramStr)
+	writeGoThreadCheck(b, apiName, ifaceName, ctor)
+	writeGoTableChecks(b, funcName, ctor, resolved, true)
 	fmt.Fprintf(b, "\timpl := &%s{}\n", implStruct)
 	b.WriteString("\tkey := _allocHandle(impl)\n")
+	if recordThread {
+		b.WriteString("\t_recordCreator(key)\n")
+	}
 	fmt.Fprintf(b, "\t*out_result = (C.%s)(unsafe.Pointer(key))\n", handleTypedef)
 	b.WriteString("\treturn 0\n")
 	b.WriteString("}\n")
 }
 
-// writeCgoDestructorFunc writes an //export annotated cgo destructor that frees a handle.
-func writeCgoDestructorFunc(b *strings.Builder, apiName, ifaceName, handleName string) {
-	destructor := SyntheticDestructor(handleName)
+// writeCgoDestructorFunc writes an //export annotated cgo destructor that frees
+// a handle, forgetting the creating thread when forgetThread is set.
+func writeCgoDestructorFunc(b *strings.Builder, apiName, ifaceName string, destructor *model.MethodDef, forgetThread bool) {
+	handleName, _ := model.IsHandle(destructor.Parameters[0].Type)
 	funcName := CABIFunctionName(apiName, ifaceName, destructor.Name)
 	handleTypedef := HandleTypedefName(handleName)
-	paramName := destructor.Parameters[0].Name
+	paramName := goIdent(destructor.Parameters[0].Name)
 
 	fmt.Fprintf(b, "//export %s\n", funcName)
 	fmt.Fprintf(b, "func %s(%s C.%s) {\n", funcName, paramName, handleTypedef)
+	writeGoThreadCheck(b, apiName, ifaceName, destructor)
+	if forgetThread {
+		fmt.Fprintf(b, "\t_forgetCreator(uintptr(unsafe.Pointer(%s)))\n", paramName)
+	}
 	fmt.Fprintf(b, "\t_freeHandle(uintptr(unsafe.Pointer(%s)))\n", paramName)
 	b.WriteString("}\n")
 }
 
 // writeCgoExportFunc writes an //export annotated cgo function that delegates to the Go interface.
 func writeCgoExportFunc(b *strings.Builder, apiName, ifaceName string, method *model.MethodDef, resolved resolver.ResolvedTypes) {
+	if method.Async {
+		writeCgoAsyncExports(b, apiName, ifaceName, method, resolved)
+		return
+	}
 	funcName := CABIFunctionName(apiName, ifaceName, method.Name)
 	hasError := method.Error != ""
 	hasReturn := method.Returns != nil
@@ -347,14 +587,27 @@ func writeCgoExportFunc(b *strings.Builder, apiName, ifaceName string, method *m
 
 	// Determine C return type and out-parameter
 	var cReturnType string
+	_, bufferReturn := returnBufferElem(method)
 	switch {
+	case bufferReturn || returnsTable(method):
+		// Buffers and tables are always passed out as pointer + length.
+		if hasError {
+			cReturnType = "C.int32_t"
+		}
+		cParams = append(cParams, cgoBufferOutParams(method.Returns)...)
+	case returnHasPresenceFlag(method):
+		// Optional by-value results are always passed out with a presence flag.
+		if hasError {
+			cReturnType = "C.int32_t"
+		}
+		cParams = append(cParams, "out_result *"+cgoReturnType(method.Returns.Type), "out_has_result *C.bool")
 	case hasError && hasReturn:
 		cReturnType = "C.int32_t"
-		cParams = append(cParams, "out_result *C."+cgoType(method.Returns.Type))
+		cParams = append(cParams, "out_result *"+cgoReturnType(method.Returns.Type))
 	case hasError && !hasReturn:
 		cReturnType = "C.int32_t"
 	case !hasError && hasReturn:
-		cReturnType = "C." + cgoType(method.Returns.Type)
+		cReturnType = cgoReturnType(method.Returns.Type)
 	default:
 		cReturnType = ""
 	}
@@ -370,11 +623,36 @@ func writeCgoExportFunc(b *strings.Builder, apiName, ifaceName string, method *m
 		fmt.Fprintf(b, "func %s(%s) {\n", funcName, paramStr)
 	}
 
+	writeGoThreadCheck(b, apiName, ifaceName, method)
+	writeGoTableChecks(b, funcName, method, resolved, hasError)
 	writeCgoRegularBody(b, ifaceName, method, resolved)
 
 	b.WriteString("}\n")
 }
 
+// writeGoTableChecks writes the checks of table parameters. A shim
+// returning an error code returns InvalidArgument when the error enum has
+// one; others panic. An absent optional table is not checked.
+func writeGoTableChecks(b *strings.Builder, funcName string, method *model.MethodDef, resolved resolver.ResolvedTypes, returnsError bool) {
+	code, hasCode := invalidArgumentCode(method, resolved)
+	for _, p := range method.Parameters {
+		if !p.Table {
+			continue
+		}
+		if p.Optional {
+			fmt.Fprintf(b, "\tif %[1]s != nil && !_tableValid(%[1]s, %[2]s_len) {\n", goIdent(p.Name), p.Name)
+		} else {
+			fmt.Fprintf(b, "\tif !_tableValid(%[1]s, %[2]s_len) {\n", goIdent(p.Name), p.Name)
+		}
+		if returnsError && hasCode {
+			fmt.Fprintf(b, "\t\treturn C.int32_t(%d)\n", code)
+		} else {
+			fmt.Fprintf(b, "\t\tpanic(%q)\n", tableCheckMessage(funcName, &p))
+		}
+		b.WriteString("\t}\n")
+	}
+}
+
 // writeCgoRegularBody writes the body of a regular method in the cgo shim,
 // delegating to the Go interface.
 // writeCgoRegularBody generates the body for a regular (non-create/destroy) CGo export function.
@@ -413,62 +691,286 @@ func writeCgoRegularBody(b *strings.Builder, ifaceName string, method *model.Met
 	}
 
 	// Look up impl from handle map
-	fmt.Fprintf(b, "\thandle := uintptr(unsafe.Pointer(%s))\n", handleParam.Name)
+	fmt.Fprintf(b, "\thandle := uintptr(unsafe.Pointer(%s))\n", goIdent(handleParam.Name))
 	b.WriteString("\tval, ok := _handles.Load(handle)\n")
 	b.WriteString("\tif !ok {\n")
-	if hasError {
+	_, bufferReturn := returnBufferElem(method)
+	bufferReturn = bufferReturn || returnsTable(method)
+	presenceFlag := returnHasPresenceFlag(method)
+	switch {
+	case hasError:
 		b.WriteString("\t\treturn -1\n")
-	} else {
+	case bufferReturn:
+		b.WriteString("\t\t*out_result, *out_result_len = nil, 0\n")
+		b.WriteString("\t\treturn\n")
+	case presenceFlag:
+		b.WriteString("\t\t*out_has_result = false\n")
+		b.WriteString("\t\treturn\n")
+	case hasReturn:
+		fmt.Fprintf(b, "\t\treturn %s\n", cgoZeroValue(method.Returns.Type, resolved))
+	default:
 		b.WriteString("\t\treturn\n")
 	}
 	b.WriteString("\t}\n")
 	fmt.Fprintf(b, "\timpl := val.(%s)\n", goIfaceName)
 
 	// Convert non-handle parameters
+	callArgs, copyBack := writeCgoParamConversions(b, method, resolved)
+
+	// Call interface method; ref_mut structs are copied back before
+	// anything returns.
+	argStr := strings.Join(callArgs, ", ")
+
+	switch {
+	case hasError && goReturnsOkPair(method):
+		fmt.Fprintf(b, "\tresult, present, err := impl.%s(%s)\n", methodName, argStr)
+		b.WriteString(copyBack)
+		b.WriteString("\tif err != nil {\n\t\treturn -1\n\t}\n")
+		writeCgoAbsentResult(b, method, "0")
+		writeCgoReturnMarshal(b, method.Returns.Type, resolved)
+		b.WriteString("\treturn 0\n")
+	case goReturnsOkPair(method):
+		fmt.Fprintf(b, "\tresult, present := impl.%s(%s)\n", methodName, argStr)
+		b.WriteString(copyBack)
+		if presenceFlag || bufferReturn {
+			writeCgoAbsentResult(b, method, "")
+			writeCgoReturnMarshal(b, method.Returns.Type, resolved)
+		} else {
+			writeCgoAbsentResult(b, method, "nil")
+			writeCgoReturnMarshalDirect(b, method.Returns.Type, resolved)
+		}
+	case hasError && hasReturn:
+		fmt.Fprintf(b, "\tresult, err := impl.%s(%s)\n", methodName, argStr)
+		b.WriteString(copyBack)
+		b.WriteString("\tif err != nil {\n\t\treturn -1\n\t}\n")
+		writeCgoReturnMarshal(b, method.Returns.Type, resolved)
+		b.WriteString("\treturn 0\n")
+	case hasError && !hasReturn:
+		fmt.Fprintf(b, "\terr := impl.%s(%s)\n", methodName, argStr)
+		b.WriteString(copyBack)
+		b.WriteString("\tif err != nil {\n\t\treturn -1\n\t}\n\treturn 0\n")
+	case bufferReturn:
+		fmt.Fprintf(b, "\tresult := impl.%s(%s)\n", methodName, argStr)
+		b.WriteString(copyBack)
+		writeCgoReturnMarshal(b, method.Returns.Type, resolved)
+	case !hasError && hasReturn:
+		fmt.Fprintf(b, "\tresult := impl.%s(%s)\n", methodName, argStr)
+		b.WriteString(copyBack)
+		writeCgoReturnMarshalDirect(b, method.Returns.Type, resolved)
+	default:
+		fmt.Fprintf(b, "\timpl.%s(%s)\n", methodName, argStr)
+		b.WriteString(copyBack)
+	}
+}
+
+// writeCgoAbsentResult writes the early return for an absent optional result:
+// the presence flag is cleared, or a string or table result is set to NULL.
+func writeCgoAbsentResult(b *strings.Builder, method *model.MethodDef, retVal string) {
+	if returnHasPresenceFlag(method) {
+		b.WriteString("\t*out_has_result = C.bool(present)\n")
+	}
+	b.WriteString("\tif !present {\n")
+	switch {
+	case returnsTable(method):
+		b.WriteString("\t\t*out_result, *out_result_len = nil, 0\n")
+	case !returnHasPresenceFlag(method) && method.Error != "":
+		b.WriteString("\t\t*out_result = nil\n")
+	}
+	if retVal != "" {
+		fmt.Fprintf(b, "\t\treturn %s\n", retVal)
+	} else {
+		b.WriteString("\t\treturn\n")
+	}
+	b.WriteString("\t}\n")
+}
+
+// writeCgoParamConversions converts the non-handle cgo parameters of a method
+// to Go values. It returns the interface call arguments and the code that
+// copies ref_mut structs back to the caller after the call.
+func writeCgoParamConversions(b *strings.Builder, method *model.MethodDef, resolved resolver.ResolvedTypes) ([]string, string) {
 	var callArgs []string
+	var copyBack strings.Builder
 	for _, p := range method.Parameters {
 		if _, ok := model.IsHandle(p.Type); ok {
-			continue // handle is resolved to impl above
+			continue // handle is resolved to impl by the caller
+		}
+		name := goIdent(p.Name)
+		if p.Table {
+			// Checked by writeGoTableChecks before the call.
+			goVar := ToCamelCase(p.Name) + "Val"
+			root := goTableGetRoot(p.Type, fmt.Sprintf("_tableBytes(%[1]s, %[2]s_len)", name, p.Name))
+			if p.Optional {
+				fmt.Fprintf(b, "\tvar %s %s\n", goVar, goTableType(p.Type))
+				fmt.Fprintf(b, "\tif %s != nil {\n\t\t%s = %s\n\t}\n", name, goVar, root)
+			} else {
+				fmt.Fprintf(b, "\t%s := %s\n", goVar, root)
+			}
+			callArgs = append(callArgs, goVar)
+			continue
 		}
-		if model.IsString(p.Type) {
+		if p.Optional && model.IsString(p.Type) {
 			goVar := ToCamelCase(p.Name) + "Go"
-			fmt.Fprintf(b, "\t%s := C.GoString(%s)\n", goVar, p.Name)
+			fmt.Fprintf(b, "\tvar %s *string\n", goVar)
+			fmt.Fprintf(b, "\tif %s != nil {\n", name)
+			fmt.Fprintf(b, "\t\ts := C.GoString(%s)\n", name)
+			fmt.Fprintf(b, "\t\t%s = &s\n", goVar)
+			b.WriteString("\t}\n")
+			callArgs = append(callArgs, goVar)
+		} else if hasPresenceFlag(&p) {
+			goVar := ToCamelCase(p.Name) + "Val"
+			fmt.Fprintf(b, "\tvar %s *%s\n", goVar, primitiveGoType(p.Type))
+			fmt.Fprintf(b, "\tif %s {\n", PresenceFlagName(p.Name))
+			fmt.Fprintf(b, "\t\tv := %s(%s)\n", primitiveGoType(p.Type), name)
+			fmt.Fprintf(b, "\t\t%s = &v\n", goVar)
+			b.WriteString("\t}\n")
+			callArgs = append(callArgs, goVar)
+		} else if model.IsString(p.Type) {
+			goVar := ToCamelCase(p.Name) + "Go"
+			fmt.Fprintf(b, "\t%s := C.GoString(%s)\n", goVar, name)
 			callArgs = append(callArgs, goVar)
 		} else if elemType, ok := model.IsBuffer(p.Type); ok {
 			goVar := ToCamelCase(p.Name) + "Slice"
 			goElemType := primitiveGoType(elemType)
 			fmt.Fprintf(b, "\t%s := unsafe.Slice((*%s)(unsafe.Pointer(%s)), %s_len)\n",
-				goVar, goElemType, p.Name, p.Name)
+				goVar, goElemType, name, p.Name)
 			callArgs = append(callArgs, goVar)
 		} else if model.IsPrimitive(p.Type) {
 			goVar := ToCamelCase(p.Name) + "Val"
 			goType := primitiveGoType(p.Type)
-			fmt.Fprintf(b, "\t%s := %s(%s)\n", goVar, goType, p.Name)
+			fmt.Fprintf(b, "\t%s := %s(%s)\n", goVar, goType, name)
+			callArgs = append(callArgs, goVar)
+		} else if isEnumType(resolved, p.Type) {
+			goVar := ToCamelCase(p.Name) + "Val"
+			fmt.Fprintf(b, "\t%s := %s(%s)\n", goVar, goReturnStructName(p.Type), name)
+			callArgs = append(callArgs, goVar)
+		} else if isUnionType(resolved, p.Type) {
+			// A ref_mut union is passed in like a ref one; the callee
+			// receives a copy.
+			goVar := ToCamelCase(p.Name) + "Val"
+			writeCgoUnionUnmarshal(b, &p, resolved[p.Type], goVar, resolved)
 			callArgs = append(callArgs, goVar)
 		} else {
-			// FlatBuffer type — pass as pointer (TODO: proper marshalling)
-			callArgs = append(callArgs, p.Name)
+			goVar := ToCamelCase(p.Name) + "Val"
+			writeCgoStructParam(b, &copyBack, &p, resolved[p.Type], goVar)
+			callArgs = append(callArgs, goVar)
 		}
 	}
+	return callArgs, copyBack.String()
+}
 
-	// Call interface method
-	argStr := strings.Join(callArgs, ", ")
+// writeCgoStructParam writes the conversion of the C struct parameter p into
+// the Go struct goVar, field by field. A NULL ref is the zero struct. A
+// ref_mut struct is a Go pointer, nil for NULL, whose fields copyBack writes
+// back to the caller's struct.
+func writeCgoStructParam(b, copyBack *strings.Builder, p *model.ParameterDef, info *resolver.TypeInfo, goVar string) {
+	name := goIdent(p.Name)
+	goType := goReturnStructName(p.Type)
+	switch p.Transfer {
+	case "ref_mut":
+		fmt.Fprintf(b, "\tvar %s *%s\n", goVar, goType)
+		fmt.Fprintf(b, "\tif %s != nil {\n\t\t%s = &%s{}\n", name, goVar, goType)
+		writeCgoStructUnmarshal(b, "\t\t", info, goVar, name)
+		b.WriteString("\t}\n")
+		fmt.Fprintf(copyBack, "\tif %s != nil {\n", name)
+		writeCgoStructMarshal(copyBack, "\t\t", info, name, goVar)
+		copyBack.WriteString("\t}\n")
+	case "ref":
+		fmt.Fprintf(b, "\tvar %s %s\n", goVar, goType)
+		fmt.Fprintf(b, "\tif %s != nil {\n", name)
+		writeCgoStructUnmarshal(b, "\t\t", info, goVar, name)
+		b.WriteString("\t}\n")
+	default:
+		fmt.Fprintf(b, "\tvar %s %s\n", goVar, goType)
+		writeCgoStructUnmarshal(b, "\t", info, goVar, name)
+	}
+}
 
-	switch {
-	case hasError && hasReturn:
-		fmt.Fprintf(b, "\tresult, err := impl.%s(%s)\n", methodName, argStr)
-		b.WriteString("\tif err != nil {\n\t\treturn -1\n\t}\n")
+// writeCgoAsyncExports writes the //export start/poll/cancel functions of an
+// async method. Operations live in the handle map next to the impls they
+// were started on.
+func writeCgoAsyncExports(b *strings.Builder, apiName, ifaceName string, method *model.MethodDef, resolved resolver.ResolvedTypes) {
+	opType := "C." + AsyncOpTypeName(apiName)
+	completionType := goCompletionType(method)
+	startName := AsyncStartFunctionName(apiName, ifaceName, method.Name)
+	pollName := AsyncPollFunctionName(apiName, ifaceName, method.Name)
+	cancelName := AsyncCancelFunctionName(apiName, ifaceName, method.Name)
+
+	// start
+	var cParams []string
+	for _, p := range method.Parameters {
+		cParams = append(cParams, goCgoParam(&p)...)
+	}
+	fmt.Fprintf(b, "//export %s\n", startName)
+	fmt.Fprintf(b, "func %s(%s) %s {\n", startName, strings.Join(cParams, ", "), opType)
+	writeGoThreadCheck(b, apiName, ifaceName, method)
+	writeGoTableChecks(b, startName, method, resolved, false)
+	var handleParam *model.ParameterDef
+	for i := range method.Parameters {
+		if _, ok := model.IsHandle(method.Parameters[i].Type); ok {
+			handleParam = &method.Parameters[i]
+			break
+		}
+	}
+	if handleParam == nil {
+		b.WriteString("\t// TODO: no handle parameter found — implement manually\n")
+		b.WriteString("\treturn nil\n")
+	} else {
+		fmt.Fprintf(b, "\thandle := uintptr(unsafe.Pointer(%s))\n", goIdent(handleParam.Name))
+		b.WriteString("\tval, ok := _handles.Load(handle)\n")
+		b.WriteString("\tif !ok {\n\t\treturn nil\n\t}\n")
+		fmt.Fprintf(b, "\timpl := val.(%s)\n", ToPascalCase(ifaceName))
+		callArgs, _ := writeCgoParamConversions(b, method, resolved) // async methods take no ref_mut parameters
+		fmt.Fprintf(b, "\tcompletion := &%s{}\n", completionType)
+		b.WriteString("\tkey := _allocHandle(&_asyncOp{completion: completion})\n")
+		fmt.Fprintf(b, "\timpl.%s(%s)\n", ToPascalCase(method.Name), strings.Join(append(callArgs, "completion"), ", "))
+		fmt.Fprintf(b, "\treturn (%s)(unsafe.Pointer(key))\n", opType)
+	}
+	b.WriteString("}\n\n")
+
+	// poll
+	pollParams := []string{"op " + opType}
+	if method.Error != "" {
+		pollParams = append(pollParams, "out_error *C.int32_t")
+	}
+	if returnsTable(method) {
+		pollParams = append(pollParams, cgoBufferOutParams(method.Returns)...)
+	} else if method.Returns != nil {
+		pollParams = append(pollParams, "out_result *"+cgoReturnType(method.Returns.Type))
+	}
+	fmt.Fprintf(b, "//export %s\n", pollName)
+	fmt.Fprintf(b, "func %s(%s) C.int32_t {\n", pollName, strings.Join(pollParams, ", "))
+	b.WriteString("\tkey := uintptr(unsafe.Pointer(op))\n")
+	b.WriteString("\tval, ok := _handles.Load(key)\n")
+	b.WriteString("\tif !ok {\n\t\treturn _asyncCancelled\n\t}\n")
+	b.WriteString("\trec := val.(*_asyncOp)\n")
+	resultVar := "_"
+	if method.Returns != nil {
+		resultVar = "result"
+	}
+	fmt.Fprintf(b, "\tstatus, %s, err := rec.completion.(*%s)._poll()\n", resultVar, completionType)
+	b.WriteString("\tif status != _asyncPending {\n\t\t_freeHandle(key)\n\t}\n")
+	b.WriteString("\tif status != _asyncDone {\n\t\treturn C.int32_t(status)\n\t}\n")
+	if method.Error != "" {
+		b.WriteString("\tif err != nil {\n\t\t*out_error = -1\n\t\treturn C.int32_t(status)\n\t}\n")
+		b.WriteString("\t*out_error = 0\n")
+	} else {
+		b.WriteString("\tif err != nil {\n\t\treturn _asyncCancelled\n\t}\n")
+	}
+	if method.Returns != nil {
 		writeCgoReturnMarshal(b, method.Returns.Type, resolved)
-		b.WriteString("\treturn 0\n")
-	case hasError && !hasReturn:
-		fmt.Fprintf(b, "\terr := impl.%s(%s)\n", methodName, argStr)
-		b.WriteString("\tif err != nil {\n\t\treturn -1\n\t}\n\treturn 0\n")
-	case !hasError && hasReturn:
-		fmt.Fprintf(b, "\tresult := impl.%s(%s)\n", methodName, argStr)
-		writeCgoReturnMarshalDirect(b, method.Returns.Type)
-	default:
-		fmt.Fprintf(b, "\timpl.%s(%s)\n", methodName, argStr)
 	}
+	b.WriteString("\treturn C.int32_t(status)\n")
+	b.WriteString("}\n\n")
+
+	// cancel
+	fmt.Fprintf(b, "//export %s\n", cancelName)
+	fmt.Fprintf(b, "func %s(op %s) {\n", cancelName, opType)
+	b.WriteString("\tkey := uintptr(unsafe.Pointer(op))\n")
+	b.WriteString("\tif val, ok := _handles.Load(key); ok {\n")
+	b.WriteString("\t\tval.(*_asyncOp).completion._cancel()\n")
+	b.WriteString("\t\t_freeHandle(key)\n")
+	b.WriteString("\t}\n")
+	b.WriteString("}\n")
 }
 
 // writeCgoReturnMarshal writes code to marshal a Go return value into a C out_result pointer.
@@ -484,46 +986,80 @@ func writeCgoReturnMarshal(b *strings.Builder, retType string, resolved resolver
 		fmt.Fprintf(b, "\t*out_result = C.%s(result)\n", cType)
 		return
 	}
+	if model.IsString(retType) {
+		b.WriteString("\t*out_result = C.CString(result)\n")
+		return
+	}
+	if _, ok := model.IsBuffer(retType); ok {
+		b.WriteString("\tdata, n := _cBuffer(result)\n")
+		fmt.Fprintf(b, "\t*out_result, *out_result_len = (%s)(data), n\n", cgoReturnType(retType))
+		return
+	}
+	if isEnumType(resolved, retType) {
+		fmt.Fprintf(b, "\t*out_result = %s(result)\n", cgoReturnType(retType))
+		return
+	}
+	if isTableType(resolved, retType) {
+		// The finished FlatBuffer is copied out like a buffer<uint8>.
+		b.WriteString("\tdata, n := _cBuffer(result)\n")
+		b.WriteString("\t*out_result, *out_result_len = (*C.uint8_t)(data), n\n")
+		return
+	}
 
-	// FlatBuffer struct — marshal fields from Go struct to C struct
+	// FlatBuffer struct — marshal fields from Go struct to C struct.
+	// String fields are caller-owned copies.
 	info, ok := resolved[retType]
 	if !ok {
 		b.WriteString("\t_ = result // TODO: marshal FlatBuffer return type\n")
 		return
 	}
+	if info.Kind == resolver.TypeKindUnion {
+		writeCgoUnionMarshal(b, retType, info, resolved)
+		return
+	}
+	writeCgoStructMarshal(b, "\t", info, "out_result", "result")
+}
 
-	// Collect string fields and non-string fields
-	var stringGoExprs []string
-	for _, f := range info.Fields {
+// writeCgoStructMarshal writes the fields of the Go struct src into the C
+// struct dest.
+func writeCgoStructMarshal(b *strings.Builder, indent string, info *resolver.TypeInfo, dest, src string) {
+	for _, f := range info.ActiveFields() {
+		goFieldName := ToPascalCase(f.Name)
 		if f.Type == "string" {
-			stringGoExprs = append(stringGoExprs, "result."+ToPascalCase(f.Name))
+			fmt.Fprintf(b, "%s%s.%s = C.CString(%s.%s)\n", indent, dest, f.Name, src, goFieldName)
+			continue
+		}
+		cType := fbsFieldToCgoType(f.Type)
+		if _, _, ok := resolver.ArrayField(f.Type); ok {
+			// The Go and C arrays have the same layout; copy the whole array.
+			fmt.Fprintf(b, "%s%s.%s = *(*%s)(unsafe.Pointer(&%s.%s))\n", indent, dest, f.Name, cType, src, goFieldName)
+			continue
 		}
+		fmt.Fprintf(b, "%s%s.%s = %s(%s.%s)\n", indent, dest, f.Name, cType, src, goFieldName)
 	}
+}
 
-	if len(stringGoExprs) > 0 {
-		fmt.Fprintf(b, "\tcStrs := _cacheStrings(handle, %s)\n", strings.Join(stringGoExprs, ", "))
-		idx := 0
-		for _, f := range info.Fields {
-			if f.Type == "string" {
-				fmt.Fprintf(b, "\tout_result.%s = cStrs[%d]\n", f.Name, idx)
-				idx++
-			} else {
-				goFieldName := ToPascalCase(f.Name)
-				cType := fbsFieldToCgoType(f.Type)
-				fmt.Fprintf(b, "\tout_result.%s = %s(result.%s)\n", f.Name, cType, goFieldName)
-			}
+// writeCgoStructUnmarshal writes the fields of the C struct src into the Go
+// struct dest.
+func writeCgoStructUnmarshal(b *strings.Builder, indent string, info *resolver.TypeInfo, dest, src string) {
+	for _, f := range info.ActiveFields() {
+		goFieldName := ToPascalCase(f.Name)
+		goType := fbsFieldToGoType(f.Type)
+		if f.Type == "string" {
+			fmt.Fprintf(b, "%s%s.%s = C.GoString(%s.%s)\n", indent, dest, goFieldName, src, f.Name)
+			continue
 		}
-	} else {
-		for _, f := range info.Fields {
-			goFieldName := ToPascalCase(f.Name)
-			cType := fbsFieldToCgoType(f.Type)
-			fmt.Fprintf(b, "\tout_result.%s = %s(result.%s)\n", f.Name, cType, goFieldName)
+		if _, _, ok := resolver.ArrayField(f.Type); ok {
+			// The Go and C arrays have the same layout; copy the whole array.
+			fmt.Fprintf(b, "%s%s.%s = *(*%s)(unsafe.Pointer(&%s.%s))\n", indent, dest, goFieldName, goType, src, f.Name)
+			continue
 		}
+		fmt.Fprintf(b, "%s%s.%s = %s(%s.%s)\n", indent, dest, goFieldName, goType, src, f.Name)
 	}
 }
 
 // writeCgoReturnMarshalDirect writes code for infallible non-void methods that return directly.
-func writeCgoReturnMarshalDirect(b *strings.Builder, retType string) {
+func writeCgoReturnMarshalDirect(b *strings.Builder, retType string, resolved resolver.ResolvedTypes) {
 	if _, ok := model.IsHandle(retType); ok {
 		handleName, _ := model.IsHandle(retType)
 		handleTypedef := HandleTypedefName(handleName)
@@ -535,6 +1071,20 @@ func writeCgoReturnMarshalDirect(b *strings.Builder, retType string) {
 		fmt.Fprintf(b, "\treturn C.%s(result)\n", cType)
 		return
 	}
+	if model.IsString(retType) {
+		b.WriteString("\treturn C.CString(result)\n")
+		return
+	}
+	if isEnumType(resolved, retType) {
+		fmt.Fprintf(b, "\treturn %s(result)\n", cgoReturnType(retType))
+		return
+	}
+	if _, ok := resolved[retType]; ok {
+		fmt.Fprintf(b, "\tout_result := &%s{}\n", cgoReturnType(retType))
+		writeCgoReturnMarshal(b, retType, resolved)
+		b.WriteString("\treturn *out_result\n")
+		return
+	}
 	b.WriteString("\t_ = result // TODO: marshal FlatBuffer direct return\n")
 	b.WriteString("\treturn 0\n")
 }
@@ -565,6 +1115,9 @@ func fbsFieldToCgoType(t string) string {
 	case "float64":
 		return "C.double"
 	}
+	if elem, n, ok := resolver.ArrayField(t); ok {
+		return fmt.Sprintf("[%d]%s", n, fbsFieldToCgoType(elem))
+	}
 	return "C." + model.FlatBufferCType(t)
 }
 
@@ -578,6 +1131,7 @@ func (g *GoImplGenerator) generateImpl(api *model.APIDefinition, apiName string,
 
 	fmt.Fprintf(&b, "package %s\n", pkgName)
 	b.WriteString("\n")
+	writeGoImports(&b, goTableImports(api))
 
 	for _, iface := range api.Interfaces {
 		// Impl stub only includes regular methods; constructors/destructor are shim-handled.
@@ -589,7 +1143,11 @@ func (g *GoImplGenerator) generateImpl(api *model.APIDefinition, apiName string,
 		structName := ifaceName + "Impl"
 
 		fmt.Fprintf(&b, "// %s is a stub implementation of %s.\n", structName, ifaceName)
-		fmt.Fprintf(&b, "type %s struct{}\n\n", structName)
+		if iface.Extends != "" {
+			fmt.Fprintf(&b, "type %s struct {\n\t%sImpl\n}\n\n", structName, ToPascalCase(iface.Extends))
+		} else {
+			fmt.Fprintf(&b, "type %s struct{}\n\n", structName)
+		}
 
 		// Verify interface satisfaction.
 		fmt.Fprintf(&b, "var _ %s = (*%s)(nil)\n\n", ifaceName, structName)
@@ -618,20 +1176,17 @@ func writeGoStubMethod(b *strings.Builder, structName string, method *model.Meth
 		}
 		params = append(params, goInterfaceParamSignature(&p, resolved))
 	}
+	if method.Async {
+		params = append(params, "completion *"+goCompletionType(method))
+		fmt.Fprintf(b, "func (s *%s) %s(%s) {\n", structName, methodName, strings.Join(params, ", "))
+		b.WriteString("\t// TODO: implement; call completion.Resolve or completion.Reject when done\n")
+		b.WriteString("\tcompletion.Abandon()\n")
+		b.WriteString("}\n")
+		return
+	}
 	paramStr := strings.Join(params, ", ")
 
-	// Determine return signature
-	var retSig string
-	switch {
-	case hasError && hasReturn:
-		retSig = fmt.Sprintf("(%s, error)", goReturnStructType(method.Returns.Type))
-	case hasError && !hasReturn:
-		retSig = "error"
-	case !hasError && hasReturn:
-		retSig = goReturnStructType(method.Returns.Type)
-	default:
-		retSig = ""
-	}
+	retSig := goReturnSignature(method)
 
 	if retSig != "" {
 		fmt.Fprintf(b, "func (s *%s) %s(%s) %s {\n", structName, methodName, paramStr, retSig)
@@ -642,14 +1197,19 @@ func writeGoStubMethod(b *strings.Builder, structName string, method *model.Meth
 	b.WriteString("\t// TODO: implement\n")
 
 	// Return zero values
+	var zeroVal string
+	if hasReturn {
+		zeroVal = goReturnStructZeroValue(method.Returns.Type, resolved)
+		if goReturnsOkPair(method) {
+			zeroVal += ", false"
+		}
+	}
 	switch {
 	case hasError && hasReturn:
-		zeroVal := goReturnStructZeroValue(method.Returns.Type)
 		fmt.Fprintf(b, "\treturn %s, nil\n", zeroVal)
 	case hasError && !hasReturn:
 		b.WriteString("\treturn nil\n")
 	case !hasError && hasReturn:
-		zeroVal := goReturnStructZeroValue(method.Returns.Type)
 		fmt.Fprintf(b, "\treturn %s\n", zeroVal)
 	default:
 		// void — no return
@@ -658,20 +1218,149 @@ func writeGoStubMethod(b *strings.Builder, structName string, method *model.Meth
 	b.WriteString("}\n")
 }
 
-// --- Types generation ---
+// goCompletionType returns the completion an async interface method receives.
+func goCompletionType(method *model.MethodDef) string {
+	valueType := "struct{}"
+	if method.Returns != nil {
+		valueType = goResultType(method.Returns)
+	}
+	return "AsyncCompletion[" + valueType + "]"
+}
 
-// generateTypes produces the Go type definitions file from FBS schemas.
-// Emits enum constants and Go structs for FlatBuffer types used as return values.
-func (g *GoImplGenerator) generateTypes(resolved resolver.ResolvedTypes, apiName string, api *model.APIDefinition) *OutputFile {
+// generateAsync produces the completion type async interface methods receive
+// and the operation record the cgo and wasm shims keep in their handle maps.
+func (g *GoImplGenerator) generateAsync(apiName string) *OutputFile {
 	pkgName := goPackageName(apiName)
 	var b strings.Builder
 
-	fmt.Fprintf(&b, "package %s\n\n", pkgName)
+	fmt.Fprintf(&b, `package %s
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Status values returned by every <method>_poll function.
+const (
+	_asyncPending   = %d
+	_asyncDone      = %d
+	_asyncCancelled = %d
+)
+`, pkgName, AsyncStatusPending, AsyncStatusDone, AsyncStatusCancelled)
+
+	b.WriteString(`
+// AsyncCompletion is handed to async interface methods. Call Resolve, Reject
+// or Abandon exactly once, from any goroutine. Slices passed alongside it
+// alias caller memory that is only valid during the call; copy what you keep.
+type AsyncCompletion[T any] struct {
+	mu        sync.Mutex
+	status    int32
+	value     T
+	err       error
+	cancelled atomic.Bool
+}
+
+// Resolve finishes the operation with its result.
+func (c *AsyncCompletion[T]) Resolve(value T) {
+	c.finish(_asyncDone, value, nil)
+}
+
+// Reject fails the operation. Rejecting an infallible method reports it cancelled.
+func (c *AsyncCompletion[T]) Reject(err error) {
+	var zero T
+	c.finish(_asyncDone, zero, err)
+}
+
+// Abandon reports the operation cancelled without a result.
+func (c *AsyncCompletion[T]) Abandon() {
+	var zero T
+	c.finish(_asyncCancelled, zero, nil)
+}
+
+// Cancelled reports whether the caller has cancelled; stop early and Abandon.
+func (c *AsyncCompletion[T]) Cancelled() bool {
+	return c.cancelled.Load()
+}
+
+func (c *AsyncCompletion[T]) finish(status int32, value T, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.status == _asyncPending {
+		c.status, c.value, c.err = status, value, err
+	}
+}
+
+// _poll returns the status and, once done, the outcome.
+func (c *AsyncCompletion[T]) _poll() (int32, T, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.status, c.value, c.err
+}
+
+func (c *AsyncCompletion[T]) _cancel() {
+	c.cancelled.Store(true)
+}
+
+// _asyncOp is what an operation handle refers to.
+type _asyncOp struct {
+	completion interface{ _cancel() }
+}
+`)
+
+	return &OutputFile{Path: apiName + "_async.go", Content: []byte(b.String())}
+}
+
+// --- Types generation ---
+
+// generateTypes produces the Go type definitions file from FBS schemas. The
+// types of a namespace mapped to a Go package are declared in that package,
+// under the generated directory, and aliased in the main package.
+func (g *GoImplGenerator) generateTypes(resolved resolver.ResolvedTypes, apiName string, api *model.APIDefinition) ([]*OutputFile, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "package %s\n\n", goPackageName(apiName))
+
+	var files []*OutputFile
+	var aliases strings.Builder
+	var imports []string
+	for _, ns := range mappedNamespaces(api, goTarget) {
+		var types strings.Builder
+		writeGoTypes(&types, resolved, api, inTarget(api, goTarget, ns.Go))
+		if types.Len() == 0 {
+			continue
+		}
+		var pkg strings.Builder
+		fmt.Fprintf(&pkg, "package %s\n\n", goSubPackageName(ns.Go))
+		var pkgImports []string
+		for _, dep := range goNamespaceDeps(api, resolved, ns.Go) {
+			pkgImports = append(pkgImports, ". "+goSubPackagePath(apiName, dep))
+		}
+		writeGoImports(&pkg, pkgImports)
+		pkg.WriteString(types.String())
+
+		if err := writeGoAliases(&aliases, goSubPackageName(ns.Go), pkg.String()); err != nil {
+			return nil, fmt.Errorf("aliasing Go package %s: %w", ns.Go, err)
+		}
+		imports = append(imports, goSubPackagePath(apiName, ns.Go))
+		files = append(files, &OutputFile{
+			Path:    ns.Go + "/" + goSubPackageName(ns.Go) + ".go",
+			Content: []byte(pkg.String()),
+		})
+	}
+	writeGoImports(&b, imports)
+	b.WriteString(aliases.String())
+	writeGoTypes(&b, resolved, api, inTarget(api, goTarget, ""))
 
-	// Collect and sort enum names
+	filename := apiName + "_types.go"
+	return append([]*OutputFile{{Path: filename, Content: []byte(b.String())}}, files...), nil
+}
+
+// writeGoTypes writes the Go definitions of the FlatBuffer types that keep
+// selects.
+func writeGoTypes(b *strings.Builder, resolved resolver.ResolvedTypes, api *model.APIDefinition, keep func(string) bool) {
+	// Collect and sort enum names; bit_flags enums get a type of their own
 	var enumNames []string
 	for name, info := range resolved {
-		if info.Kind == resolver.TypeKindEnum {
+		if info.Kind == resolver.TypeKindEnum && !info.BitFlags && keep(name) {
 			enumNames = append(enumNames, name)
 		}
 	}
@@ -684,24 +1373,40 @@ func (g *GoImplGenerator) generateTypes(resolved resolver.ResolvedTypes, apiName
 			info := resolved[name]
 			goPrefix := goEnumPrefix(name)
 			for _, val := range info.EnumValues {
-				fmt.Fprintf(&b, "\t%s%s = %d\n", goPrefix, val.Name, val.Value)
+				fmt.Fprintf(b, "\t%s%s = %d\n", goPrefix, val.Name, val.Value)
 			}
 			b.WriteString("\n")
 		}
 		b.WriteString(")\n\n")
 	}
+	for _, name := range bitFlagsEnums(resolved) {
+		if keep(name) {
+			writeGoBitFlags(b, name, resolved[name])
+		}
+	}
 
 	// Collect FlatBuffer types used as return values in the API
 	returnTypes := collectReturnTypes(api)
 
-	// Emit Go structs for return types (tables and structs only, not enums)
+	// Union types, whose struct members need Go structs too
+	for _, name := range unionTypes(resolved) {
+		if keep(name) {
+			writeGoUnion(b, name, resolved[name], resolved)
+		}
+		for _, m := range resolved[name].Members {
+			returnTypes[m.Type] = true
+		}
+	}
+
+	// Emit Go structs for returned structs. Tables are returned as finished
+	// FlatBuffers and read through the flatc accessors.
 	var structNames []string
 	for name := range returnTypes {
 		info, ok := resolved[name]
-		if !ok {
+		if !ok || !keep(name) {
 			continue
 		}
-		if info.Kind == resolver.TypeKindStruct || info.Kind == resolver.TypeKindTable {
+		if info.Kind == resolver.TypeKindStruct {
 			structNames = append(structNames, name)
 		}
 	}
@@ -710,18 +1415,15 @@ func (g *GoImplGenerator) generateTypes(resolved resolver.ResolvedTypes, apiName
 	for _, name := range structNames {
 		info := resolved[name]
 		goName := goReturnStructName(name)
-		fmt.Fprintf(&b, "// %s is the Go representation of the %s FlatBuffer type.\n", goName, name)
-		fmt.Fprintf(&b, "type %s struct {\n", goName)
-		for _, f := range info.Fields {
+		fmt.Fprintf(b, "// %s is the Go representation of the %s FlatBuffer type.\n", goName, name)
+		fmt.Fprintf(b, "type %s struct {\n", goName)
+		for _, f := range info.ActiveFields() {
 			goFieldName := ToPascalCase(f.Name)
 			goFieldType := fbsFieldToGoType(f.Type)
-			fmt.Fprintf(&b, "\t%s %s\n", goFieldName, goFieldType)
+			fmt.Fprintf(b, "\t%s %s\n", goFieldName, goFieldType)
 		}
 		b.WriteString("}\n\n")
 	}
-
-	filename := apiName + "_types.go"
-	return &OutputFile{Path: filename, Content: []byte(b.String())}
 }
 
 // collectReturnTypes collects all FlatBuffer types used as method or constructor return values.
@@ -770,35 +1472,423 @@ func fbsFieldToGoType(t string) string {
 	case "float64":
 		return "float64"
 	}
+	if elem, n, ok := resolver.ArrayField(t); ok {
+		return fmt.Sprintf("[%d]%s", n, fbsFieldToGoType(elem))
+	}
 	// FlatBuffer type reference
 	return goReturnStructName(t)
 }
 
+// --- Event ring generation ---
+
+// generateEvents produces the pure-Go event ring, its per-platform signaling
+// files, and a test file. The cgo and wasm shims export poll/signal_fd on top
+// of _pollEvent and _getEventSignal.
+func (g *GoImplGenerator) generateEvents(ctx *Context, api *model.APIDefinition, apiName string) []*OutputFile {
+	pkgName := goPackageName(apiName)
+	genHeader := GeneratedFileHeader(ctx, "//", false)
+	var b strings.Builder
+
+	fmt.Fprintf(&b, `package %s
+
+import (
+	"encoding/binary"
+	"sync"
+	"sync/atomic"
+)
+
+// EventKind identifies an event payload type. Values match the C header;
+// zero is reserved for "no event".
+type EventKind uint32
+
+const (
+`, pkgName)
+	for i, ev := range api.Events {
+		fmt.Fprintf(&b, "\tEventKind%s EventKind = %d // %s\n", ToPascalCase(ev.Name), EventKindValue(i), ev.Type)
+	}
+	fmt.Fprintf(&b, `)
+
+// _eventRingCapacity is the byte capacity of the event ring. Must be a power of two.
+const _eventRingCapacity = %d
+
+// _eventRing is a single-producer, single-consumer byte ring. Records are
+// [kind:u32][size:u32][payload padded to 8 bytes]; head and tail are
+// free-running byte counters.
+type _eventRing struct {
+	head atomic.Uint32
+	tail atomic.Uint32
+	data [_eventRingCapacity]byte
+}
+
+func _eventRecordSize(size uint32) uint32 {
+	return 8 + (size+7)&^7
+}
+
+func (r *_eventRing) copyIn(pos uint32, src []byte) {
+	n := copy(r.data[pos&(_eventRingCapacity-1):], src)
+	copy(r.data[:], src[n:])
+}
+
+func (r *_eventRing) copyOut(pos uint32, dst []byte) {
+	n := copy(dst, r.data[pos&(_eventRingCapacity-1):])
+	copy(dst[n:], r.data[:])
+}
+
+func (r *_eventRing) empty() bool {
+	return r.head.Load() == r.tail.Load()
+}
+
+// push appends one record. Returns false when the ring is full.
+func (r *_eventRing) push(kind uint32, payload []byte) bool {
+	if len(payload) > _eventRingCapacity-8 {
+		return false
+	}
+	head, tail := r.head.Load(), r.tail.Load()
+	need := _eventRecordSize(uint32(len(payload)))
+	if need > _eventRingCapacity-(head-tail) {
+		return false
+	}
+	var header [8]byte
+	binary.LittleEndian.PutUint32(header[0:], kind)
+	binary.LittleEndian.PutUint32(header[4:], uint32(len(payload)))
+	r.copyIn(head, header[:])
+	r.copyIn(head+8, payload)
+	r.head.Store(head + need)
+	return true
+}
+
+// poll pops one record into buf using the C poll protocol: returns the payload
+// size, 0 with kind 0 when empty, or -(required size) when buf is too small
+// (the record stays queued).
+func (r *_eventRing) poll(kind *uint32, buf []byte) int32 {
+	tail, head := r.tail.Load(), r.head.Load()
+	if head == tail {
+		*kind = 0
+		return 0
+	}
+	var header [8]byte
+	r.copyOut(tail, header[:])
+	*kind = binary.LittleEndian.Uint32(header[0:])
+	size := binary.LittleEndian.Uint32(header[4:])
+	if int(size) > len(buf) {
+		return -int32(size)
+	}
+	r.copyOut(tail+8, buf[:size])
+	r.tail.Store(tail + _eventRecordSize(size))
+	return int32(size)
+}
+
+// EventSignal wakes the binding when events are pushed. The default uses
+// eventfd on Linux/Android, a pipe on other Unix platforms, and no
+// descriptor elsewhere.
+type EventSignal interface {
+	// FD returns the readable descriptor the binding waits on, or -1.
+	FD() int32
+	Notify()
+	Clear()
+}
+
+var (
+	_events          _eventRing
+	_eventSignal     EventSignal
+	_eventSignalOnce sync.Once
+)
+
+// SetEventSignal installs custom signaling. Must be called before the first
+// push or poll; returns false if signaling is already in use.
+func SetEventSignal(s EventSignal) bool {
+	installed := false
+	_eventSignalOnce.Do(func() {
+		_eventSignal = s
+		installed = true
+	})
+	return installed
+}
+
+func _getEventSignal() EventSignal {
+	_eventSignalOnce.Do(func() {
+		_eventSignal = _openEventSignal()
+	})
+	return _eventSignal
+}
+
+// PushEvent pushes a FlatBuffer-serialized payload and signals the binding.
+// Returns false when the ring is full (the event is dropped). Must not be
+// called from more than one goroutine at a time.
+func PushEvent(kind EventKind, payload []byte) bool {
+	if !_events.push(uint32(kind), payload) {
+		return false
+	}
+	_getEventSignal().Notify()
+	return true
+}
+`, EventRingCapacity)
+	for _, ev := range api.Events {
+		pascal := ToPascalCase(ev.Name)
+		fmt.Fprintf(&b, `
+// Push%[1]sEvent pushes a serialized %[2]s.
+func Push%[1]sEvent(payload []byte) bool {
+	return PushEvent(EventKind%[1]s, payload)
+}
+`, pascal, ev.Type)
+	}
+	b.WriteString(`
+// _pollEvent backs the exported poll function for both cgo and wasm builds.
+func _pollEvent(kind *uint32, buf []byte) int32 {
+	if _events.empty() {
+		// Clear before polling so a concurrent push re-signals.
+		_getEventSignal().Clear()
+	}
+	return _events.poll(kind, buf)
+}
+`)
+	eventsFile := &OutputFile{Path: apiName + "_events.go", Content: prependHeader(genHeader, []byte(b.String()))}
+
+	linuxFile := &OutputFile{Path: apiName + "_events_signal_linux.go", Content: prependHeader(genHeader, []byte(fmt.Sprintf(`package %s
+
+import "syscall"
+
+// _eventFDSignal signals through a non-blocking eventfd (Linux and Android).
+type _eventFDSignal struct{ fd int }
+
+func _openEventSignal() EventSignal {
+	fd, _, errno := syscall.RawSyscall(syscall.SYS_EVENTFD2, 0, syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0)
+	if errno != 0 {
+		return _eventFDSignal{fd: -1}
+	}
+	return _eventFDSignal{fd: int(fd)}
+}
+
+func (s _eventFDSignal) FD() int32 { return int32(s.fd) }
+
+func (s _eventFDSignal) Notify() {
+	if s.fd >= 0 {
+		syscall.Write(s.fd, []byte{1, 0, 0, 0, 0, 0, 0, 0})
+	}
+}
+
+func (s _eventFDSignal) Clear() {
+	var counter [8]byte
+	if s.fd >= 0 {
+		syscall.Read(s.fd, counter[:])
+	}
+}
+`, pkgName)))}
+
+	unixFile := &OutputFile{Path: apiName + "_events_signal_unix.go", Content: []byte("//go:build unix && !linux\n\n" + genHeader + fmt.Sprintf(`
+package %s
+
+import "syscall"
+
+// _pipeEventSignal signals through a non-blocking pipe (Darwin, BSDs).
+type _pipeEventSignal struct{ r, w int }
+
+func _openEventSignal() EventSignal {
+	var fds [2]int
+	if err := syscall.Pipe(fds[:]); err != nil {
+		return _pipeEventSignal{r: -1, w: -1}
+	}
+	for _, fd := range fds {
+		syscall.SetNonblock(fd, true)
+		syscall.CloseOnExec(fd)
+	}
+	return _pipeEventSignal{r: fds[0], w: fds[1]}
+}
+
+func (s _pipeEventSignal) FD() int32 { return int32(s.r) }
+
+func (s _pipeEventSignal) Notify() {
+	if s.w >= 0 {
+		syscall.Write(s.w, []byte{1})
+	}
+}
+
+func (s _pipeEventSignal) Clear() {
+	var drain [64]byte
+	if s.r >= 0 {
+		for {
+			if n, _ := syscall.Read(s.r, drain[:]); n <= 0 {
+				return
+			}
+		}
+	}
+}
+`, pkgName))}
+
+	otherFile := &OutputFile{Path: apiName + "_events_signal_other.go", Content: []byte("//go:build !unix\n\n" + genHeader + fmt.Sprintf(`
+package %s
+
+// _noEventSignal is used where no readable descriptor exists (Windows, WASM);
+// bindings poll instead.
+type _noEventSignal struct{}
+
+func _openEventSignal() EventSignal { return _noEventSignal{} }
+
+func (_noEventSignal) FD() int32 { return -1 }
+func (_noEventSignal) Notify()   {}
+func (_noEventSignal) Clear()    {}
+`, pkgName))}
+
+	first := api.Events[0]
+	testFile := &OutputFile{Path: apiName + "_events_test.go", Content: prependHeader(genHeader, []byte(fmt.Sprintf(`package %[1]s
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEventRing_RoundTrip(t *testing.T) {
+	r := new(_eventRing)
+	var kind uint32
+	out := make([]byte, 64)
+	if n := r.poll(&kind, out); n != 0 || kind != 0 {
+		t.Fatalf("empty poll = %%d kind %%d, want 0 kind 0", n, kind)
+	}
+	r.push(1, []byte{1, 2, 3})
+	r.push(2, []byte{4, 5, 6, 7, 8})
+	if n := r.poll(&kind, out); n != 3 || kind != 1 || !bytes.Equal(out[:n], []byte{1, 2, 3}) {
+		t.Fatalf("first poll = %%d kind %%d %%v", n, kind, out[:3])
+	}
+	if n := r.poll(&kind, out); n != 5 || kind != 2 || !bytes.Equal(out[:n], []byte{4, 5, 6, 7, 8}) {
+		t.Fatalf("second poll = %%d kind %%d %%v", n, kind, out[:5])
+	}
+	if !r.empty() {
+		t.Fatal("ring not empty after draining")
+	}
+}
+
+func TestEventRing_ShortBuffer(t *testing.T) {
+	r := new(_eventRing)
+	var kind uint32
+	r.push(1, make([]byte, 100))
+	if n := r.poll(&kind, make([]byte, 10)); n != -100 {
+		t.Fatalf("short poll = %%d, want -100", n)
+	}
+	if n := r.poll(&kind, make([]byte, 128)); n != 100 {
+		t.Fatalf("retry poll = %%d, want 100", n)
+	}
+}
+
+func TestEventRing_WrapAround(t *testing.T) {
+	r := new(_eventRing)
+	var kind uint32
+	out := make([]byte, 64)
+	for i := 0; i < (_eventRingCapacity/64)*3; i++ {
+		payload := bytes.Repeat([]byte{byte(i)}, 61)
+		if !r.push(1, payload) {
+			t.Fatalf("push %%d failed", i)
+		}
+		if n := r.poll(&kind, out); n != 61 || !bytes.Equal(out[:n], payload) {
+			t.Fatalf("poll %%d = %%d", i, n)
+		}
+	}
+}
+
+func TestEventRing_Full(t *testing.T) {
+	r := new(_eventRing)
+	var kind uint32
+	pushed := 0
+	for r.push(1, make([]byte, 248)) {
+		pushed++
+	}
+	if pushed != _eventRingCapacity/256 {
+		t.Fatalf("pushed %%d records, want %%d", pushed, _eventRingCapacity/256)
+	}
+	for r.poll(&kind, make([]byte, 256)) == 248 {
+		pushed--
+	}
+	if pushed != 0 {
+		t.Fatalf("%%d records lost", pushed)
+	}
+}
+
+func TestPushEvent_Poll(t *testing.T) {
+	if !Push%[2]sEvent([]byte{9, 9}) {
+		t.Fatal("push failed")
+	}
+	var kind uint32
+	if n := _pollEvent(&kind, make([]byte, 16)); n != 2 || EventKind(kind) != EventKind%[2]s {
+		t.Fatalf("poll = %%d kind %%d", n, kind)
+	}
+	if n := _pollEvent(&kind, make([]byte, 16)); n != 0 {
+		t.Fatalf("drained poll = %%d, want 0", n)
+	}
+}
+`, pkgName, ToPascalCase(first.Name))))}
+
+	return []*OutputFile{eventsFile, linuxFile, unixFile, otherFile, testFile}
+}
+
+// writeCgoEventExports writes the //export poll and signal_fd functions.
+func writeCgoEventExports(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, `/* events */
+
+//export %[1]s
+func %[1]s(out_kind *C.uint32_t, buffer *C.uint8_t, buffer_size C.uint32_t) C.int32_t {
+	var kind uint32
+	var buf []byte
+	if buffer != nil {
+		buf = unsafe.Slice((*byte)(unsafe.Pointer(buffer)), int(buffer_size))
+	}
+	n := _pollEvent(&kind, buf)
+	*out_kind = C.uint32_t(kind)
+	return C.int32_t(n)
+}
+
+//export %[2]s
+func %[2]s() C.int32_t {
+	return C.int32_t(_getEventSignal().FD())
+}
+
+`, EventPollFunctionName(apiName), EventSignalFDFunctionName(apiName))
+}
+
 // --- Go module generation ---
 
 // generateGoMod produces a scaffold go.mod for the implementation package.
+// Reading tables through the flatc output needs the FlatBuffers Go runtime.
 func (g *GoImplGenerator) generateGoMod(api *model.APIDefinition) *OutputFile {
-	moduleName := strings.ReplaceAll(api.API.Name, "_", "-")
 	var b strings.Builder
-	fmt.Fprintf(&b, "module %s\n\n", moduleName)
+	fmt.Fprintf(&b, "module %s\n\n", goModulePath(api.API.Name))
 	b.WriteString("go 1.24\n")
+	if len(goTableImports(api)) > 0 {
+		b.WriteString("\nrequire github.com/google/flatbuffers v24.3.25+incompatible\n")
+	}
 	return &OutputFile{Path: "go.mod", Content: []byte(b.String()), Scaffold: true, ProjectFile: true}
 }
 
 // generateGitignore produces a .gitignore that lists the generated Go source files
 // copied from generated/ into the package root by the Makefile.
-func (g *GoImplGenerator) generateGitignore(apiName string) *OutputFile {
+func (g *GoImplGenerator) generateGitignore(apiName string, hasEvents, hasAsync, hasThreading bool) *OutputFile {
 	content := fmt.Sprintf(`# Generated Go sources — copied from generated/ by Makefile; do not edit.
 %[1]s_interface.go
 %[1]s_cgo.go
 %[1]s_types.go
 %[1]s_wasm.go
 `, apiName)
+	if hasEvents {
+		content += fmt.Sprintf(`%[1]s_events.go
+%[1]s_events_signal_*.go
+%[1]s_events_test.go
+`, apiName)
+	}
+	if hasAsync {
+		content += apiName + "_async.go\n"
+	}
+	if hasThreading {
+		content += apiName + "_threading.go\n" + apiName + "_threading_release.go\n"
+	}
 	return &OutputFile{Path: ".gitignore", Content: []byte(content), Scaffold: true, ProjectFile: true}
 }
 
 // --- Package-level utilities ---
 
+// goModulePath returns the module path of the Go implementation,
+// e.g. "fields_api" → "fields-api".
+func goModulePath(apiName string) string {
+	return strings.ReplaceAll(apiName, "_", "-")
+}
+
 // goPackageName returns "main" — Go impl packages are always built as
 // c-shared or wasip1 binaries, both of which require package main.
 func goPackageName(apiName string) string {
@@ -843,31 +1933,76 @@ func primitiveGoType(t string) string {
 
 // goCgoParam returns one or more cgo parameter strings for a C ABI parameter.
 func goCgoParam(p *model.ParameterDef) []string {
+	name := goIdent(p.Name)
 	if model.IsString(p.Type) {
-		return []string{p.Name + " *C.char"}
+		return []string{name + " *C.char"}
 	}
 	if elemType, ok := model.IsBuffer(p.Type); ok {
 		cType := model.PrimitiveCType(elemType)
 		return []string{
-			p.Name + " *C." + cType,
+			name + " *C." + cType,
 			p.Name + "_len C.uint32_t",
 		}
 	}
 	if handleName, ok := model.IsHandle(p.Type); ok {
-		return []string{p.Name + " C." + HandleTypedefName(handleName)}
+		return []string{name + " C." + HandleTypedefName(handleName)}
+	}
+	if p.Table {
+		return []string{name + " *C.uint8_t", p.Name + "_len C.uint32_t"}
+	}
+	if hasPresenceFlag(p) {
+		return []string{
+			PresenceFlagName(p.Name) + " C.bool",
+			name + " C." + model.PrimitiveCType(p.Type),
+		}
 	}
 	if model.IsPrimitive(p.Type) {
-		return []string{p.Name + " C." + model.PrimitiveCType(p.Type)}
+		return []string{name + " C." + model.PrimitiveCType(p.Type)}
 	}
 	// FlatBuffer type
 	cType := model.FlatBufferCType(p.Type)
 	if p.Transfer == "ref_mut" {
-		return []string{p.Name + " *C." + cType}
+		return []string{name + " *C." + cType}
 	}
 	if p.Transfer == "ref" {
-		return []string{p.Name + " *C." + cType}
+		return []string{name + " *C." + cType}
+	}
+	return []string{name + " C." + cType}
+}
+
+// cgoBufferOutParams returns the out-parameters of a buffer or table
+// result: the data pointer and its length, in elements or bytes.
+func cgoBufferOutParams(ret *model.ReturnDef) []string {
+	outType := "*" + cgoReturnType(ret.Type)
+	if ret.Table {
+		outType = "**C.uint8_t"
+	}
+	return []string{"out_result " + outType, "out_result_len *C.uint32_t"}
+}
+
+// cgoZeroValue returns the zero value of a cgo return type.
+func cgoZeroValue(t string, resolved resolver.ResolvedTypes) string {
+	if _, ok := model.IsHandle(t); ok || model.IsString(t) {
+		return "nil"
+	}
+	if t == "bool" {
+		return "false"
+	}
+	if model.IsPrimitive(t) || isEnumType(resolved, t) {
+		return cgoReturnType(t) + "(0)"
+	}
+	return cgoReturnType(t) + "{}"
+}
+
+// cgoReturnType returns the cgo type of a return value.
+func cgoReturnType(t string) string {
+	if model.IsString(t) {
+		return "*C.char"
+	}
+	if elemType, ok := model.IsBuffer(t); ok {
+		return "*C." + model.PrimitiveCType(elemType)
 	}
-	return []string{p.Name + " C." + cType}
+	return "C." + cgoType(t)
 }
 
 // cgoType returns the cgo type name for a return type.
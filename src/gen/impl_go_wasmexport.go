@@ -29,27 +29,34 @@ func (g *GoWASMImplGenerator) Generate(ctx *Context) ([]*OutputFile, error) {
 
 	b.WriteString("//go:build wasip1\n\n")
 	b.WriteString(GeneratedFileHeader(ctx, "//", false))
-	fmt.Fprintf(&b, `
-package %s
-
-import (
-	"sync"
-	"sync/atomic"
-	"unsafe"
-)
-
-`, pkgName)
+	fmt.Fprintf(&b, "\npackage %s\n\n", pkgName)
+	hasTables := hasTableParams(api)
+	var imports []string
+	if hasTables {
+		imports = append(imports, "encoding/binary")
+	}
+	imports = append(imports, "sync", "sync/atomic", "unsafe")
+	writeGoImports(&b, imports, goTableImports(api))
 
 	writeWasmMemoryAllocator(&b)
 	writeWasmHandleManagement(&b)
 	writeWasmCStringHelper(&b)
-	writeWasmStringCacheHelpers(&b)
+	writeWasmABIInfo(&b, apiName, ABIInfo(ctx))
+	if hasTables {
+		writeWasmTableHelpers(&b)
+	}
+	if hasStringReturns(api) {
+		writeWasmStringReturnHelpers(&b, apiName)
+	}
+	if hasBufferReturns(api, ctx.ResolvedTypes) {
+		writeWasmBufferReturnHelpers(&b, apiName)
+	}
 	writeWasmPlatformImports(&b, apiName)
 
 	for _, iface := range api.Interfaces {
 		fmt.Fprintf(&b, "/* %s */\n\n", iface.Name)
 		for _, ctor := range iface.Constructors {
-			writeWasmConstructorFunc(&b, apiName, iface.Name, &ctor)
+			writeWasmConstructorFunc(&b, apiName, iface.Name, &ctor, ctx.ResolvedTypes)
 			b.WriteString("\n")
 		}
 		if handleName, ok := iface.ConstructorHandleName(); ok {
@@ -62,6 +69,10 @@ import (
 		}
 	}
 
+	if len(api.Events) > 0 {
+		writeWasmEventExports(&b, apiName)
+	}
+
 	filename := apiName + "_wasm.go"
 	return []*OutputFile{{Path: filename, Content: []byte(b.String())}}, nil
 }
@@ -104,12 +115,6 @@ func _allocHandle(impl interface{}) uintptr {
 
 func _freeHandle(key uintptr) {
 	_wasmHandles.Delete(key)
-	// Free any cached WASM strings for this handle
-	if val, ok := _wasmStrCache.LoadAndDelete(key); ok {
-		for _, ptr := range val.([]uintptr) {
-			_wasmFree(ptr)
-		}
-	}
 }
 
 `)
@@ -129,36 +134,112 @@ func _cstring(ptr uintptr) string {
 `)
 }
 
-// writeWasmStringCacheHelpers writes helpers for caching WASM string allocations per-handle.
-func writeWasmStringCacheHelpers(b *strings.Builder) {
-	b.WriteString(`// WASM string cache — maps handle key → []uintptr of allocated strings.
-var _wasmStrCache sync.Map
+// writeWasmABIInfo writes the export that returns the ABI info string, a
+// null-terminated package-level copy that stays put in linear memory.
+func writeWasmABIInfo(b *strings.Builder, apiName, abiInfo string) {
+	fmt.Fprintf(b, `var _abiInfo = []byte(%[2]q)
 
-// _wasmCacheStrings allocates null-terminated strings in WASM linear memory
-// and caches them for the handle's lifetime (borrowing-only semantics).
-func _wasmCacheStrings(handleKey uintptr, ss ...string) []uintptr {
-	// Free previous cache
-	if val, ok := _wasmStrCache.LoadAndDelete(handleKey); ok {
-		for _, ptr := range val.([]uintptr) {
-			_wasmFree(ptr)
-		}
+//go:wasmexport %[1]s
+func %[1]s() uintptr {
+	return uintptr(unsafe.Pointer(&_abiInfo[0]))
+}
+
+`, ABIInfoFunctionName(apiName), abiInfo+"\x00")
+}
+
+// writeWasmStringReturnHelpers writes the allocator for returned strings and
+// the export that releases them. Every returned string is a caller-owned copy.
+func writeWasmStringReturnHelpers(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, `// _wasmString copies s into a null-terminated string in WASM linear memory,
+// owned by the caller and released with %[1]s.
+func _wasmString(s string) uintptr {
+	ptr := _wasmMalloc(uint32(len(s) + 1))
+	buf := unsafe.Slice((*byte)(unsafe.Pointer(ptr)), len(s)+1)
+	copy(buf, s)
+	buf[len(s)] = 0
+	return ptr
+}
+
+//go:wasmexport %[1]s
+func %[1]s(str uintptr) {
+	_wasmFree(str)
+}
+
+`, StringFreeFunctionName(apiName))
+}
+
+// writeWasmBufferReturnHelpers writes the allocator for returned buffers and
+// the export that releases them.
+func writeWasmBufferReturnHelpers(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, `// _wasmBuffer copies s into WASM linear memory, owned by the caller and
+// released with %[1]s. An empty slice comes back as 0.
+func _wasmBuffer[T any](s []T) (uintptr, uint32) {
+	if len(s) == 0 {
+		return 0, 0
 	}
-	result := make([]uintptr, len(ss))
-	for i, s := range ss {
-		data := []byte(s)
-		ptr := _wasmMalloc(uint32(len(data) + 1))
-		buf := unsafe.Slice((*byte)(unsafe.Pointer(ptr)), len(data)+1)
-		copy(buf, data)
-		buf[len(data)] = 0
-		result[i] = ptr
+	ptr := _wasmMalloc(uint32(len(s)) * uint32(unsafe.Sizeof(s[0])))
+	copy(unsafe.Slice((*T)(unsafe.Pointer(ptr)), len(s)), s)
+	return ptr, uint32(len(s))
+}
+
+//go:wasmexport %[1]s
+func %[1]s(data uintptr) {
+	_wasmFree(data)
+}
+
+`, BufferFreeFunctionName(apiName))
+}
+
+// writeWasmTableHelpers writes the helpers that check and read table
+// parameters, which arrive as FlatBuffers in linear memory. Parallel to
+// writeCgoTableHelpers.
+func writeWasmTableHelpers(b *strings.Builder) {
+	b.WriteString(`// _wasmTableBytes aliases a table parameter's FlatBuffer, which is only
+// valid during the call.
+func _wasmTableBytes(data uintptr, n uint32) []byte {
+	return unsafe.Slice((*byte)(unsafe.Pointer(data)), n)
+}
+
+// _wasmTableValid reports whether a table parameter's root table and its
+// vtable lie within the buffer.
+func _wasmTableValid(data uintptr, n uint32) bool {
+	if data == 0 || n < 8 {
+		return false
+	}
+	buf := _wasmTableBytes(data, n)
+	root := int64(binary.LittleEndian.Uint32(buf))
+	if root+4 > int64(n) {
+		return false
 	}
-	_wasmStrCache.Store(handleKey, result)
-	return result
+	vtable := root - int64(int32(binary.LittleEndian.Uint32(buf[root:])))
+	return vtable >= 0 && vtable+4 <= int64(n)
 }
 
 `)
 }
 
+// writeWasmTableChecks writes the checks of table parameters. Parallel to
+// writeGoTableChecks.
+func writeWasmTableChecks(b *strings.Builder, funcName string, method *model.MethodDef, resolved resolver.ResolvedTypes, returnsError bool) {
+	code, hasCode := invalidArgumentCode(method, resolved)
+	for _, p := range method.Parameters {
+		if !p.Table {
+			continue
+		}
+		if p.Optional {
+			fmt.Fprintf(b, "\tif %[1]s != 0 && !_wasmTableValid(%[1]s, %[2]s_len) {\n", goIdent(p.Name), p.Name)
+		} else {
+			fmt.Fprintf(b, "\tif !_wasmTableValid(%[1]s, %[2]s_len) {\n", goIdent(p.Name), p.Name)
+		}
+		if returnsError && hasCode {
+			fmt.Fprintf(b, "\t\treturn %d\n", code)
+		} else {
+			fmt.Fprintf(b, "\t\tpanic(%q)\n", tableCheckMessage(funcName, &p))
+		}
+		b.WriteString("\t}\n")
+	}
+}
+
 // writeWasmPlatformImports writes //go:wasmimport declarations for the 6 platform services.
 func writeWasmPlatformImports(b *strings.Builder, apiName string) {
 	fmt.Fprintf(b, `// Platform service imports — provided by the JS binding as WASM imports.
@@ -183,8 +264,32 @@ func _platform_resource_read(name uintptr, buffer uintptr, bufferSize uint32) in
 `, apiName)
 }
 
+// writeWasmEventExports writes the //go:wasmexport poll and signal_fd functions.
+func writeWasmEventExports(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, `/* events */
+
+//go:wasmexport %[1]s
+func %[1]s(out_kind uintptr, buffer uintptr, buffer_size uint32) int32 {
+	var kind uint32
+	var buf []byte
+	if buffer != 0 {
+		buf = unsafe.Slice((*byte)(unsafe.Pointer(buffer)), int(buffer_size))
+	}
+	n := _pollEvent(&kind, buf)
+	*(*uint32)(unsafe.Pointer(out_kind)) = kind
+	return n
+}
+
+//go:wasmexport %[2]s
+func %[2]s() int32 {
+	return _getEventSignal().FD()
+}
+
+`, EventPollFunctionName(apiName), EventSignalFDFunctionName(apiName))
+}
+
 // writeWasmConstructorFunc writes a //go:wasmexport constructor that allocates a handle.
-func writeWasmConstructorFunc(b *strings.Builder, apiName, ifaceName string, ctor *model.MethodDef) {
+func writeWasmConstructorFunc(b *strings.Builder, apiName, ifaceName string, ctor *model.MethodDef, resolved resolver.ResolvedTypes) {
 	funcName := CABIFunctionName(apiName, ifaceName, ctor.Name)
 	handleName, _ := model.IsHandle(ctor.Returns.Type)
 	implStruct := ToPascalCase(handleName) + "Impl"
@@ -198,6 +303,7 @@ func writeWasmConstructorFunc(b *strings.Builder, apiName, ifaceName string, cto
 	fmt.Fprintf(b, "//go:wasmexport %s\n", funcName)
 	paramStr := strings.Join(wasmParams, ", ")
 	fmt.Fprintf(b, "func %s(%s) int32 {\n", funcName, paramStr)
+	writeWasmTableChecks(b, funcName, ctor, resolved, true)
 	fmt.Fprintf(b, `	impl := &%s{}
 	key := _allocHandle(impl)
 	*(*uint32)(unsafe.Pointer(out_result)) = uint32(key)
@@ -210,7 +316,7 @@ func writeWasmConstructorFunc(b *strings.Builder, apiName, ifaceName string, cto
 func writeWasmDestructorFunc(b *strings.Builder, apiName, ifaceName, handleName string) {
 	destructor := SyntheticDestructor(handleName)
 	funcName := CABIFunctionName(apiName, ifaceName, destructor.Name)
-	paramName := destructor.Parameters[0].Name
+	paramName := goIdent(destructor.Parameters[0].Name)
 
 	fmt.Fprintf(b, "//go:wasmexport %s\n", funcName)
 	fmt.Fprintf(b, "func %s(%s uint32) {\n", funcName, paramName)
@@ -221,6 +327,10 @@ func writeWasmDestructorFunc(b *strings.Builder, apiName, ifaceName, handleName
 // writeWasmExportFunc writes a single //go:wasmexport annotated function that
 // delegates to the Go interface.
 func writeWasmExportFunc(b *strings.Builder, apiName, ifaceName string, method *model.MethodDef, resolved resolver.ResolvedTypes) {
+	if method.Async {
+		writeWasmAsyncExports(b, apiName, ifaceName, method, resolved)
+		return
+	}
 	funcName := CABIFunctionName(apiName, ifaceName, method.Name)
 	hasError := method.Error != ""
 	hasReturn := method.Returns != nil
@@ -233,7 +343,18 @@ func writeWasmExportFunc(b *strings.Builder, apiName, ifaceName string, method *
 
 	// Determine return type and out-parameter
 	var wasmReturnType string
+	_, bufferReturn := returnBufferElem(method)
 	switch {
+	case bufferReturn || returnsTable(method):
+		if hasError {
+			wasmReturnType = "int32"
+		}
+		wasmParams = append(wasmParams, "out_result uintptr", "out_result_len uintptr")
+	case returnHasPresenceFlag(method):
+		if hasError {
+			wasmReturnType = "int32"
+		}
+		wasmParams = append(wasmParams, "out_result uintptr", "out_has_result uintptr")
 	case hasError && hasReturn:
 		wasmReturnType = "int32"
 		wasmParams = append(wasmParams, "out_result uintptr")
@@ -252,6 +373,7 @@ func writeWasmExportFunc(b *strings.Builder, apiName, ifaceName string, method *
 		fmt.Fprintf(b, "func %s(%s) {\n", funcName, paramStr)
 	}
 
+	writeWasmTableChecks(b, funcName, method, resolved, hasError)
 	writeWasmRegularBody(b, ifaceName, method, resolved)
 
 	b.WriteString("}\n")
@@ -290,64 +412,313 @@ func writeWasmRegularBody(b *strings.Builder, ifaceName string, method *model.Me
 	}
 
 	// Look up impl from handle map
-	fmt.Fprintf(b, "\tval, ok := _wasmHandles.Load(%s)\n", handleParam.Name)
-	if hasError {
+	fmt.Fprintf(b, "\tval, ok := _wasmHandles.Load(%s)\n", goIdent(handleParam.Name))
+	_, bufferReturn := returnBufferElem(method)
+	bufferReturn = bufferReturn || returnsTable(method)
+	presenceFlag := returnHasPresenceFlag(method)
+	switch {
+	case hasError:
 		b.WriteString("\tif !ok {\n\t\treturn -1\n\t}\n")
-	} else {
+	case bufferReturn:
+		b.WriteString("\tif !ok {\n")
+		b.WriteString("\t\t*(*uint32)(unsafe.Pointer(out_result)) = 0\n")
+		b.WriteString("\t\t*(*uint32)(unsafe.Pointer(out_result_len)) = 0\n")
+		b.WriteString("\t\treturn\n\t}\n")
+	case presenceFlag:
+		b.WriteString("\tif !ok {\n")
+		b.WriteString("\t\t*(*bool)(unsafe.Pointer(out_has_result)) = false\n")
+		b.WriteString("\t\treturn\n\t}\n")
+	case hasReturn:
+		fmt.Fprintf(b, "\tif !ok {\n\t\treturn %s\n\t}\n", goWasmZeroValue(method.Returns.Type))
+	default:
 		b.WriteString("\tif !ok {\n\t\treturn\n\t}\n")
 	}
 	fmt.Fprintf(b, "\timpl := val.(%s)\n", goIfaceName)
 
 	// Convert non-handle parameters
+	callArgs, copyBack := writeWasmParamConversions(b, method, resolved)
+
+	// Call interface method; ref_mut structs are copied back before
+	// anything returns.
+	argStr := strings.Join(callArgs, ", ")
+
+	switch {
+	case hasError && goReturnsOkPair(method):
+		fmt.Fprintf(b, "\tresult, present, err := impl.%s(%s)\n", methodName, argStr)
+		b.WriteString(copyBack)
+		b.WriteString("\tif err != nil {\n\t\treturn -1\n\t}\n")
+		writeWasmAbsentResult(b, method, "0")
+		writeWasmReturnMarshal(b, method.Returns.Type, resolved)
+		b.WriteString("\treturn 0\n")
+	case goReturnsOkPair(method):
+		fmt.Fprintf(b, "\tresult, present := impl.%s(%s)\n", methodName, argStr)
+		b.WriteString(copyBack)
+		if presenceFlag || bufferReturn {
+			writeWasmAbsentResult(b, method, "")
+			writeWasmReturnMarshal(b, method.Returns.Type, resolved)
+		} else {
+			writeWasmAbsentResult(b, method, "0")
+			b.WriteString("\treturn _wasmString(result)\n")
+		}
+	case hasError && hasReturn:
+		fmt.Fprintf(b, "\tresult, err := impl.%s(%s)\n", methodName, argStr)
+		b.WriteString(copyBack)
+		b.WriteString("\tif err != nil {\n\t\treturn -1\n\t}\n")
+		writeWasmReturnMarshal(b, method.Returns.Type, resolved)
+		b.WriteString("\treturn 0\n")
+	case hasError && !hasReturn:
+		fmt.Fprintf(b, "\terr := impl.%s(%s)\n", methodName, argStr)
+		b.WriteString(copyBack)
+		b.WriteString("\tif err != nil {\n\t\treturn -1\n\t}\n")
+		b.WriteString("\treturn 0\n")
+	case bufferReturn:
+		fmt.Fprintf(b, "\tresult := impl.%s(%s)\n", methodName, argStr)
+		b.WriteString(copyBack)
+		writeWasmReturnMarshal(b, method.Returns.Type, resolved)
+	case !hasError && hasReturn:
+		fmt.Fprintf(b, "\tresult := impl.%s(%s)\n", methodName, argStr)
+		b.WriteString(copyBack)
+		if model.IsString(method.Returns.Type) {
+			b.WriteString("\treturn _wasmString(result)\n")
+		} else {
+			b.WriteString("\t_ = result // TODO: marshal WASM direct return\n\treturn 0\n")
+		}
+	default:
+		fmt.Fprintf(b, "\timpl.%s(%s)\n", methodName, argStr)
+		b.WriteString(copyBack)
+	}
+}
+
+// writeWasmAbsentResult writes the early return for an absent optional result:
+// the presence flag is cleared, or a string or table result is set to NULL.
+// Parallel to writeCgoAbsentResult.
+func writeWasmAbsentResult(b *strings.Builder, method *model.MethodDef, retVal string) {
+	if returnHasPresenceFlag(method) {
+		b.WriteString("\t*(*bool)(unsafe.Pointer(out_has_result)) = present\n")
+	}
+	b.WriteString("\tif !present {\n")
+	switch {
+	case returnsTable(method):
+		b.WriteString("\t\t*(*uint32)(unsafe.Pointer(out_result)) = 0\n")
+		b.WriteString("\t\t*(*uint32)(unsafe.Pointer(out_result_len)) = 0\n")
+	case !returnHasPresenceFlag(method) && method.Error != "":
+		b.WriteString("\t\t*(*uint32)(unsafe.Pointer(out_result)) = 0\n")
+	}
+	if retVal != "" {
+		fmt.Fprintf(b, "\t\treturn %s\n", retVal)
+	} else {
+		b.WriteString("\t\treturn\n")
+	}
+	b.WriteString("\t}\n")
+}
+
+// writeWasmParamConversions converts the non-handle WASM parameters of a method
+// to Go values. It returns the interface call arguments and the code that
+// copies ref_mut structs back to the caller after the call.
+func writeWasmParamConversions(b *strings.Builder, method *model.MethodDef, resolved resolver.ResolvedTypes) ([]string, string) {
 	var callArgs []string
+	var copyBack strings.Builder
 	for _, p := range method.Parameters {
 		if _, ok := model.IsHandle(p.Type); ok {
-			continue // handle resolved to impl above
+			continue // handle resolved to impl by the caller
+		}
+		name := goIdent(p.Name)
+		if p.Table {
+			// Checked by writeWasmTableChecks before the call.
+			goVar := ToCamelCase(p.Name) + "Val"
+			root := goTableGetRoot(p.Type, fmt.Sprintf("_wasmTableBytes(%[1]s, %[2]s_len)", name, p.Name))
+			if p.Optional {
+				fmt.Fprintf(b, "\tvar %s %s\n", goVar, goTableType(p.Type))
+				fmt.Fprintf(b, "\tif %s != 0 {\n\t\t%s = %s\n\t}\n", name, goVar, root)
+			} else {
+				fmt.Fprintf(b, "\t%s := %s\n", goVar, root)
+			}
+			callArgs = append(callArgs, goVar)
+			continue
 		}
-		if model.IsString(p.Type) {
+		if p.Optional && model.IsString(p.Type) {
 			goVar := ToCamelCase(p.Name) + "Go"
-			fmt.Fprintf(b, "\t%s := _cstring(%s)\n", goVar, p.Name)
+			fmt.Fprintf(b, "\tvar %s *string\n", goVar)
+			fmt.Fprintf(b, "\tif %s != 0 {\n", name)
+			fmt.Fprintf(b, "\t\ts := _cstring(%s)\n", name)
+			fmt.Fprintf(b, "\t\t%s = &s\n", goVar)
+			b.WriteString("\t}\n")
+			callArgs = append(callArgs, goVar)
+		} else if hasPresenceFlag(&p) {
+			goVar := ToCamelCase(p.Name) + "Val"
+			fmt.Fprintf(b, "\tvar %s *%s\n", goVar, primitiveGoType(p.Type))
+			fmt.Fprintf(b, "\tif %s {\n", PresenceFlagName(p.Name))
+			fmt.Fprintf(b, "\t\t%s = &%s\n", goVar, name)
+			b.WriteString("\t}\n")
+			callArgs = append(callArgs, goVar)
+		} else if model.IsString(p.Type) {
+			goVar := ToCamelCase(p.Name) + "Go"
+			fmt.Fprintf(b, "\t%s := _cstring(%s)\n", goVar, name)
 			callArgs = append(callArgs, goVar)
 		} else if elemType, ok := model.IsBuffer(p.Type); ok {
 			goVar := ToCamelCase(p.Name) + "Slice"
 			goElemType := primitiveGoType(elemType)
 			fmt.Fprintf(b, "\t%s := unsafe.Slice((*%s)(unsafe.Pointer(%s)), %s_len)\n",
-				goVar, goElemType, p.Name, p.Name)
+				goVar, goElemType, name, p.Name)
 			callArgs = append(callArgs, goVar)
 		} else if model.IsPrimitive(p.Type) {
-			callArgs = append(callArgs, p.Name)
+			callArgs = append(callArgs, name)
+		} else if isEnumType(resolved, p.Type) {
+			goVar := ToCamelCase(p.Name) + "Val"
+			fmt.Fprintf(b, "\t%s := %s(%s)\n", goVar, goReturnStructName(p.Type), name)
+			callArgs = append(callArgs, goVar)
+		} else if info, ok := resolved[p.Type]; ok && info.Kind == resolver.TypeKindStruct {
+			goVar := ToCamelCase(p.Name) + "Val"
+			writeWasmStructParam(b, &copyBack, &p, goVar, resolved)
+			callArgs = append(callArgs, goVar)
 		} else {
-			callArgs = append(callArgs, p.Name)
+			callArgs = append(callArgs, name)
 		}
 	}
+	return callArgs, copyBack.String()
+}
 
-	// Call interface method
-	argStr := strings.Join(callArgs, ", ")
+// writeWasmStructParam writes the conversion of the struct parameter p, a
+// pointer into linear memory, into the Go struct goVar. Parallel to
+// writeCgoStructParam: a NULL pointer is the zero struct, or nil for a ref_mut
+// struct, whose fields copyBack writes back to linear memory.
+func writeWasmStructParam(b, copyBack *strings.Builder, p *model.ParameterDef, goVar string, resolved resolver.ResolvedTypes) {
+	name := goIdent(p.Name)
+	goType := goReturnStructName(p.Type)
+	_, layoutFields := wasmStructLayout(p.Type, resolved)
+	dest := goVar
+	if p.Transfer == "ref_mut" {
+		fmt.Fprintf(b, "\tvar %s *%s\n", goVar, goType)
+		fmt.Fprintf(b, "\tif %s != 0 {\n\t\t%s = &%s{}\n", name, goVar, goType)
+	} else {
+		fmt.Fprintf(b, "\tvar %s %s\n", goVar, goType)
+		fmt.Fprintf(b, "\tif %s != 0 {\n", name)
+	}
+	for _, f := range layoutFields {
+		field := fmt.Sprintf("unsafe.Pointer(%s + %d)", name, f.Offset)
+		var expr string
+		switch {
+		case f.Type == "string":
+			expr = fmt.Sprintf("_cstring(uintptr(*(*uint32)(%s)))", field)
+		case f.Type == "bool":
+			expr = fmt.Sprintf("*(*byte)(%s) != 0", field)
+		default:
+			if _, _, ok := resolver.ArrayField(f.Type); ok {
+				expr = fmt.Sprintf("*(*%s)(%s)", fbsFieldToGoType(f.Type), field)
+			} else {
+				expr = fmt.Sprintf("%s(*(*%s)(%s))", fbsFieldToGoType(f.Type), goWasmFieldGoType(f.Type), field)
+			}
+		}
+		fmt.Fprintf(b, "\t\t%s.%s = %s\n", dest, ToPascalCase(f.Name), expr)
+	}
+	b.WriteString("\t}\n")
+	if p.Transfer == "ref_mut" {
+		fmt.Fprintf(copyBack, "\tif %s != 0 {\n", name)
+		writeWasmStructMarshal(copyBack, "\t\t", layoutFields, name, goVar)
+		copyBack.WriteString("\t}\n")
+	}
+}
 
-	switch {
-	case hasError && hasReturn:
-		fmt.Fprintf(b, "\tresult, err := impl.%s(%s)\n", methodName, argStr)
-		b.WriteString("\tif err != nil {\n\t\treturn -1\n\t}\n")
-		writeWasmReturnMarshal(b, method.Returns.Type, handleParam.Name, resolved)
-		b.WriteString("\treturn 0\n")
-	case hasError && !hasReturn:
-		fmt.Fprintf(b, "\terr := impl.%s(%s)\n", methodName, argStr)
-		b.WriteString("\tif err != nil {\n\t\treturn -1\n\t}\n")
+// writeWasmAsyncExports writes the //go:wasmexport start/poll/cancel functions
+// of an async method. Parallel to writeCgoAsyncExports; operation handles are
+// integer keys in _wasmHandles and outputs are written into linear memory.
+func writeWasmAsyncExports(b *strings.Builder, apiName, ifaceName string, method *model.MethodDef, resolved resolver.ResolvedTypes) {
+	completionType := goCompletionType(method)
+	startName := AsyncStartFunctionName(apiName, ifaceName, method.Name)
+	pollName := AsyncPollFunctionName(apiName, ifaceName, method.Name)
+	cancelName := AsyncCancelFunctionName(apiName, ifaceName, method.Name)
+
+	// start
+	var wasmParams []string
+	for _, p := range method.Parameters {
+		wasmParams = append(wasmParams, goWasmExportParams(&p)...)
+	}
+	fmt.Fprintf(b, "//go:wasmexport %s\n", startName)
+	fmt.Fprintf(b, "func %s(%s) uintptr {\n", startName, strings.Join(wasmParams, ", "))
+	writeWasmTableChecks(b, startName, method, resolved, false)
+	var handleParam *model.ParameterDef
+	for i := range method.Parameters {
+		if _, ok := model.IsHandle(method.Parameters[i].Type); ok {
+			handleParam = &method.Parameters[i]
+			break
+		}
+	}
+	if handleParam == nil {
+		b.WriteString("\t// TODO: no handle parameter found — implement manually\n")
 		b.WriteString("\treturn 0\n")
-	case !hasError && hasReturn:
-		fmt.Fprintf(b, "\tresult := impl.%s(%s)\n", methodName, argStr)
-		b.WriteString("\t_ = result // TODO: marshal WASM direct return\n\treturn 0\n")
-	default:
-		fmt.Fprintf(b, "\timpl.%s(%s)\n", methodName, argStr)
+	} else {
+		fmt.Fprintf(b, "\tval, ok := _wasmHandles.Load(%s)\n", goIdent(handleParam.Name))
+		b.WriteString("\tif !ok {\n\t\treturn 0\n\t}\n")
+		fmt.Fprintf(b, "\timpl := val.(%s)\n", ToPascalCase(ifaceName))
+		callArgs, _ := writeWasmParamConversions(b, method, resolved) // async methods take no ref_mut parameters
+		fmt.Fprintf(b, "\tcompletion := &%s{}\n", completionType)
+		b.WriteString("\tkey := _allocHandle(&_asyncOp{completion: completion})\n")
+		fmt.Fprintf(b, "\timpl.%s(%s)\n", ToPascalCase(method.Name), strings.Join(append(callArgs, "completion"), ", "))
+		b.WriteString("\treturn key\n")
+	}
+	b.WriteString("}\n\n")
+
+	// poll
+	pollParams := []string{"op uintptr"}
+	if method.Error != "" {
+		pollParams = append(pollParams, "out_error uintptr")
+	}
+	if returnsTable(method) {
+		pollParams = append(pollParams, "out_result uintptr", "out_result_len uintptr")
+	} else if method.Returns != nil {
+		pollParams = append(pollParams, "out_result uintptr")
+	}
+	fmt.Fprintf(b, "//go:wasmexport %s\n", pollName)
+	fmt.Fprintf(b, "func %s(%s) int32 {\n", pollName, strings.Join(pollParams, ", "))
+	b.WriteString("\tval, ok := _wasmHandles.Load(op)\n")
+	b.WriteString("\tif !ok {\n\t\treturn _asyncCancelled\n\t}\n")
+	b.WriteString("\trec := val.(*_asyncOp)\n")
+	resultVar := "_"
+	if method.Returns != nil {
+		resultVar = "result"
+	}
+	fmt.Fprintf(b, "\tstatus, %s, err := rec.completion.(*%s)._poll()\n", resultVar, completionType)
+	b.WriteString("\tif status != _asyncPending {\n\t\t_freeHandle(op)\n\t}\n")
+	b.WriteString("\tif status != _asyncDone {\n\t\treturn status\n\t}\n")
+	if method.Error != "" {
+		b.WriteString("\tif err != nil {\n\t\t*(*int32)(unsafe.Pointer(out_error)) = -1\n\t\treturn status\n\t}\n")
+		b.WriteString("\t*(*int32)(unsafe.Pointer(out_error)) = 0\n")
+	} else {
+		b.WriteString("\tif err != nil {\n\t\treturn _asyncCancelled\n\t}\n")
 	}
+	if method.Returns != nil {
+		writeWasmReturnMarshal(b, method.Returns.Type, resolved)
+	}
+	b.WriteString("\treturn status\n")
+	b.WriteString("}\n\n")
+
+	// cancel
+	fmt.Fprintf(b, "//go:wasmexport %s\n", cancelName)
+	fmt.Fprintf(b, "func %s(op uintptr) {\n", cancelName)
+	b.WriteString("\tif val, ok := _wasmHandles.Load(op); ok {\n")
+	b.WriteString("\t\tval.(*_asyncOp).completion._cancel()\n")
+	b.WriteString("\t\t_freeHandle(op)\n")
+	b.WriteString("\t}\n")
+	b.WriteString("}\n")
 }
 
 // writeWasmReturnMarshal writes code to marshal a Go return value into WASM linear memory.
-func writeWasmReturnMarshal(b *strings.Builder, retType string, handleParamName string, resolved resolver.ResolvedTypes) {
+func writeWasmReturnMarshal(b *strings.Builder, retType string, resolved resolver.ResolvedTypes) {
 	if _, ok := model.IsHandle(retType); ok {
 		b.WriteString("\t*(*uint32)(unsafe.Pointer(out_result)) = uint32(result)\n")
 		return
 	}
+	if model.IsString(retType) {
+		b.WriteString("\t*(*uint32)(unsafe.Pointer(out_result)) = uint32(_wasmString(result))\n")
+		return
+	}
+	if _, ok := model.IsBuffer(retType); ok || isTableType(resolved, retType) {
+		// A finished table FlatBuffer is copied out like a buffer<uint8>.
+		b.WriteString("\tdata, n := _wasmBuffer(result)\n")
+		b.WriteString("\t*(*uint32)(unsafe.Pointer(out_result)) = uint32(data)\n")
+		b.WriteString("\t*(*uint32)(unsafe.Pointer(out_result_len)) = n\n")
+		return
+	}
 	if model.IsPrimitive(retType) {
 		fmt.Fprintf(b, "\t*(*%s)(unsafe.Pointer(out_result)) = result\n", primitiveGoType(retType))
 		return
@@ -361,29 +732,26 @@ func writeWasmReturnMarshal(b *strings.Builder, retType string, handleParamName
 		return
 	}
 
-	// Collect string fields for caching
-	var stringGoExprs []string
-	for _, f := range layoutFields {
-		if f.Type == "string" {
-			stringGoExprs = append(stringGoExprs, "result."+ToPascalCase(f.Name))
-		}
-	}
-
-	if len(stringGoExprs) > 0 {
-		fmt.Fprintf(b, "\tstrPtrs := _wasmCacheStrings(%s, %s)\n",
-			handleParamName, strings.Join(stringGoExprs, ", "))
-	}
+	writeWasmStructMarshal(b, "\t", layoutFields, "out_result", "result")
+}
 
-	strIdx := 0
+// writeWasmStructMarshal writes the fields of the Go struct src into linear
+// memory at dest, using the layout of layoutFields.
+func writeWasmStructMarshal(b *strings.Builder, indent string, layoutFields []wasmFieldInfo, dest, src string) {
 	for _, f := range layoutFields {
 		if f.Type == "string" {
-			fmt.Fprintf(b, "\t*(*uint32)(unsafe.Pointer(out_result + %d)) = uint32(strPtrs[%d])\n", f.Offset, strIdx)
-			strIdx++
+			// String fields are caller-owned copies.
+			fmt.Fprintf(b, "%s*(*uint32)(unsafe.Pointer(%s + %d)) = uint32(_wasmString(%s.%s))\n",
+				indent, dest, f.Offset, src, ToPascalCase(f.Name))
+		} else if _, _, ok := resolver.ArrayField(f.Type); ok {
+			// Go arrays of scalars have the C layout.
+			fmt.Fprintf(b, "%s*(*%s)(unsafe.Pointer(%s + %d)) = %s.%s\n",
+				indent, fbsFieldToGoType(f.Type), dest, f.Offset, src, ToPascalCase(f.Name))
 		} else {
 			goFieldName := ToPascalCase(f.Name)
 			wasmGoType := goWasmFieldGoType(f.Type)
-			fmt.Fprintf(b, "\t*(*%s)(unsafe.Pointer(out_result + %d)) = %s(result.%s)\n",
-				wasmGoType, f.Offset, wasmGoType, goFieldName)
+			fmt.Fprintf(b, "%s*(*%s)(unsafe.Pointer(%s + %d)) = %s(%s.%s)\n",
+				indent, wasmGoType, dest, f.Offset, wasmGoType, src, goFieldName)
 		}
 	}
 }
@@ -401,20 +769,24 @@ func goWasmFieldGoType(t string) string {
 
 // goWasmExportParams returns WASM-typed parameter strings for an export function.
 func goWasmExportParams(p *model.ParameterDef) []string {
+	name := goIdent(p.Name)
 	if model.IsString(p.Type) {
-		return []string{p.Name + " uintptr"}
+		return []string{name + " uintptr"}
 	}
-	if _, ok := model.IsBuffer(p.Type); ok {
-		return []string{p.Name + " uintptr", p.Name + "_len uint32"}
+	if _, ok := model.IsBuffer(p.Type); ok || p.Table {
+		return []string{name + " uintptr", p.Name + "_len uint32"}
 	}
 	if _, ok := model.IsHandle(p.Type); ok {
-		return []string{p.Name + " uintptr"}
+		return []string{name + " uintptr"}
+	}
+	if hasPresenceFlag(p) {
+		return []string{PresenceFlagName(p.Name) + " bool", name + " " + primitiveGoType(p.Type)}
 	}
 	if model.IsPrimitive(p.Type) {
-		return []string{p.Name + " " + primitiveGoType(p.Type)}
+		return []string{name + " " + primitiveGoType(p.Type)}
 	}
 	// FlatBuffer type — pointer into WASM linear memory
-	return []string{p.Name + " uintptr"}
+	return []string{name + " uintptr"}
 }
 
 // goWasmReturnType returns the WASM-compatible Go return type for an infallible method.
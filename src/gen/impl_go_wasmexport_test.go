@@ -237,7 +237,7 @@ func TestGoWASMImplGenerator_ReturnMarshalAlignment(t *testing.T) {
 		},
 	}
 	var b strings.Builder
-	writeWasmReturnMarshal(&b, "Test.Mixed", "handle", resolved)
+	writeWasmReturnMarshal(&b, "Test.Mixed", resolved)
 	output := b.String()
 
 	// flag is at offset 0 (no padding needed before first field)
@@ -326,7 +326,45 @@ func TestGoWASMImplGenerator_FallibleVoidStub(t *testing.T) {
 	}
 }
 
-func TestGoWASMImplGenerator_StringCacheHelpers(t *testing.T) {
+func TestGoWASMImplGenerator_StringReturns(t *testing.T) {
+	ctx := loadTestAPI(t, "strings.yaml")
+	gen := &GoWASMImplGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	content := string(files[0].Content)
+
+	if !strings.Contains(content, "func _wasmString(s string) uintptr {") {
+		t.Error("missing _wasmString helper")
+	}
+	if !strings.Contains(content, "//go:wasmexport strings_api_string_free\nfunc strings_api_string_free(str uintptr) {") {
+		t.Error("missing string free export")
+	}
+	if strings.Contains(content, "_wasmStrCache") || strings.Contains(content, "_wasmCacheStrings") {
+		t.Error("returned strings should not be cached against handles")
+	}
+
+	// Infallible string return is the pointer itself
+	if !strings.Contains(content, "func strings_api_text_get_name(engine uintptr) uintptr {") {
+		t.Error("infallible string return should return a pointer")
+	}
+	if !strings.Contains(content, "\treturn _wasmString(result)\n") {
+		t.Error("infallible string return not copied into linear memory")
+	}
+	// Fallible and async string returns write the pointer to out_result
+	if !strings.Contains(content, "*(*uint32)(unsafe.Pointer(out_result)) = uint32(_wasmString(result))") {
+		t.Error("string out_result not copied into linear memory")
+	}
+	// Tables are returned as finished FlatBuffers
+	if !strings.Contains(content, "\tdata, n := _wasmBuffer(result)\n") {
+		t.Error("table result not copied into linear memory")
+	}
+}
+
+func TestGoWASMImplGenerator_NoStringHelpersWithoutStringReturns(t *testing.T) {
 	ctx := loadTestAPI(t, "minimal.yaml")
 	gen := &GoWASMImplGenerator{}
 
@@ -337,10 +375,133 @@ func TestGoWASMImplGenerator_StringCacheHelpers(t *testing.T) {
 
 	content := string(files[0].Content)
 
-	if !strings.Contains(content, "_wasmStrCache sync.Map") {
-		t.Error("missing _wasmStrCache sync.Map")
+	if strings.Contains(content, "_wasmString(") || strings.Contains(content, "_string_free") {
+		t.Error("string return helpers emitted for an API without string returns")
+	}
+}
+
+func TestGoWASMImplGenerator_Events(t *testing.T) {
+	ctx := loadTestAPI(t, "events.yaml")
+	gen := &GoWASMImplGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	if !strings.Contains(content, "//go:wasmexport event_api_event_poll\nfunc event_api_event_poll(out_kind uintptr, buffer uintptr, buffer_size uint32) int32 {") {
+		t.Error("missing event poll wasm export")
+	}
+	if !strings.Contains(content, "//go:wasmexport event_api_event_signal_fd\n") {
+		t.Error("missing signal fd wasm export")
+	}
+}
+
+func TestGoWASMImplGenerator_Async(t *testing.T) {
+	ctx := loadTestAPI(t, "async.yaml")
+	gen := &GoWASMImplGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"//go:wasmexport async_api_assets_load_model_start\nfunc async_api_assets_load_model_start(engine uintptr, path uintptr) uintptr {",
+		"//go:wasmexport async_api_assets_load_model_poll\nfunc async_api_assets_load_model_poll(op uintptr, out_error uintptr, out_result uintptr, out_result_len uintptr) int32 {",
+		"//go:wasmexport async_api_assets_load_model_cancel\nfunc async_api_assets_load_model_cancel(op uintptr) {",
+		"//go:wasmexport async_api_assets_decode_poll\nfunc async_api_assets_decode_poll(op uintptr, out_result uintptr) int32 {",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("wasm shim missing %q", want)
+		}
 	}
-	if !strings.Contains(content, "func _wasmCacheStrings(") {
-		t.Error("missing _wasmCacheStrings function")
+	if strings.Contains(content, "//go:wasmexport async_api_assets_load_model\n") {
+		t.Error("async method should not export a synchronous function")
+	}
+}
+
+func TestGoWASMImplGenerator_BufferReturns(t *testing.T) {
+	ctx := loadTestAPI(t, "buffers.yaml")
+	gen := &GoWASMImplGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"func _wasmBuffer[T any](s []T) (uintptr, uint32) {",
+		"//go:wasmexport buffers_api_buffer_free\nfunc buffers_api_buffer_free(data uintptr) {\n\t_wasmFree(data)\n}",
+		"func buffers_api_media_read_tensor(engine uintptr, out_result uintptr, out_result_len uintptr) {",
+		"func buffers_api_media_sample_ids(engine uintptr, count uint32, out_result uintptr, out_result_len uintptr) int32 {",
+		"\tdata, n := _wasmBuffer(result)\n\t*(*uint32)(unsafe.Pointer(out_result)) = uint32(data)\n\t*(*uint32)(unsafe.Pointer(out_result_len)) = n\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("WASM file missing %q", want)
+		}
+	}
+
+	ctx = loadTestAPI(t, "minimal.yaml")
+	files, err = gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if strings.Contains(string(files[0].Content), "_wasmBuffer") {
+		t.Error("buffer helpers should only be emitted when buffers are returned")
+	}
+}
+
+func TestGoWASMImplGenerator_Optionals(t *testing.T) {
+	ctx := loadTestAPI(t, "optional.yaml")
+	gen := &GoWASMImplGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"func optional_api_scene_set_limit(engine uintptr, has_limit bool, limit uint32) int32 {",
+		"var labelGo *string",
+		"func optional_api_scene_get_limit(engine uintptr, out_result uintptr, out_has_result uintptr) {",
+		"*(*bool)(unsafe.Pointer(out_has_result)) = present",
+		"result, present, err := impl.GetConfig()",
+		"\tif config != 0 && !_wasmTableValid(config, config_len) {\n",
+		"\tvar configVal *renderingfb.RendererConfig\n\tif config != 0 {\n\t\tconfigVal = renderingfb.GetRootAsRendererConfig(_wasmTableBytes(config, config_len), 0)\n\t}\n",
+		"\t\t*(*uint32)(unsafe.Pointer(out_result)) = 0\n\t\t*(*uint32)(unsafe.Pointer(out_result_len)) = 0\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("WASM file missing %q", want)
+		}
+	}
+}
+
+func TestGoWASMImplGenerator_Tables(t *testing.T) {
+	ctx := loadTestAPI(t, "fields.yaml")
+	gen := &GoWASMImplGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"\tfieldsfb \"fields-api/generated/flatbuffers/go/Fields\"\n",
+		"func _wasmTableValid(data uintptr, n uint32) bool {",
+		"func fields_api_stream_create_stream(config uintptr, config_len uint32, out_result uintptr) int32 {\n\tif !_wasmTableValid(config, config_len) {\n\t\treturn 1\n\t}\n",
+		"\t\tpanic(\"fields_api_stream_restart: config is not a valid Fields.StreamConfig FlatBuffer\")\n",
+		"\tconfigVal := fieldsfb.GetRootAsStreamConfig(_wasmTableBytes(config, config_len), 0)\n",
+		"\tdata, n := _wasmBuffer(result)\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("WASM file missing %q", want)
+		}
 	}
 }
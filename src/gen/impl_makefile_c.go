@@ -24,7 +24,7 @@ func (g *CMakefileGenerator) Generate(ctx *Context) ([]*OutputFile, error) {
 	MakefileMSVCDiscovery(&b)
 	MakefileEmscriptenConfig(&b)
 	MakefileBindingVars(&b, apiName, "generated/")
-	MakefileWASMExports(&b, apiName, ctx.API)
+	MakefileWASMExports(&b, apiName, ctx.API, ctx.ResolvedTypes)
 
 	b.WriteString(`# ── C build configuration ─────────────────────────────────────────────────────
 
@@ -47,8 +47,11 @@ CROSS_CFLAGS         := -Wall -Wextra -std=c17 -I. -I$(GEN_DIR)
 CROSS_VISIBILITY     := -fvisibility=hidden -D$(BUILD_MACRO)
 CROSS_LIB_C_FLAGS    := -std=c17 -Wall -Wextra $(CROSS_VISIBILITY)
 
+# Defines <api>_abi_info; regenerated with the header, so it always matches it
+ABI_INFO_SOURCE := $(GEN_DIR)$(API_NAME)_abi_info.c
+
 # Ensure codegen runs before any target needs generated files
-$(GEN_HEADER) $(GEN_SWIFT_BINDING) $(GEN_KOTLIN_BINDING) $(GEN_JS_BINDING) $(GEN_JNI_SOURCE): $(STAMP)
+$(GEN_HEADER) $(GEN_SWIFT_BINDING) $(GEN_KOTLIN_BINDING) $(GEN_JS_BINDING) $(GEN_JNI_SOURCE) $(ABI_INFO_SOURCE): $(STAMP)
 
 `)
 
@@ -68,10 +71,10 @@ else
 	@mkdir -p $(BUILD_DIR)
 ifneq (,$(EXE))
 	$(CC) /nologo $(CFLAGS) /Fe:$(BUILD_DIR)/$(API_NAME).exe \
-		$(IMPL_SOURCES) $(PLATFORM_SERVICES)/desktop.c main.c
+		$(IMPL_SOURCES) $(ABI_INFO_SOURCE) $(PLATFORM_SERVICES)/desktop.c main.c
 else
 	$(CC) $(CFLAGS) -o $(BUILD_DIR)/$(API_NAME) \
-		$(IMPL_SOURCES) $(PLATFORM_SERVICES)/desktop.c main.c
+		$(IMPL_SOURCES) $(ABI_INFO_SOURCE) $(PLATFORM_SERVICES)/desktop.c main.c
 endif
 	./$(BUILD_DIR)/$(API_NAME)$(EXE)
 endif
@@ -86,14 +89,14 @@ else
 ifeq ($(HOST_OS),Darwin)
 	$(CC) $(CFLAGS) $(LIB_VISIBILITY_FLAGS) -shared -fPIC \
 		-Wl,-install_name,@rpath/$(DESKTOP_LIB_NAME).$(DYLIB_EXT) \
-		-o $@ $(IMPL_SOURCES) $(PLATFORM_SERVICES)/desktop.c
+		-o $@ $(IMPL_SOURCES) $(ABI_INFO_SOURCE) $(PLATFORM_SERVICES)/desktop.c
 else ifneq (,$(EXE))
 	$(CC) /nologo /LD $(LIB_VISIBILITY_FLAGS) $(CFLAGS) \
-		$(IMPL_SOURCES) $(PLATFORM_SERVICES)/desktop.c \
+		$(IMPL_SOURCES) $(ABI_INFO_SOURCE) $(PLATFORM_SERVICES)/desktop.c \
 		/Fe:$@ /link /IMPLIB:$(BUILD_DIR)/$(DESKTOP_LIB_NAME).lib
 else
 	$(CC) $(CFLAGS) $(LIB_VISIBILITY_FLAGS) -shared -fPIC \
-		-o $@ $(IMPL_SOURCES) $(PLATFORM_SERVICES)/desktop.c
+		-o $@ $(IMPL_SOURCES) $(ABI_INFO_SOURCE) $(PLATFORM_SERVICES)/desktop.c
 endif
 endif
 
@@ -102,6 +105,8 @@ clean:
 
 `)
 
+	MakefileEventsTest(&b, ctx.API, "$(CC)", "-Wall -Wextra -std=c17 -I$(GEN_DIR)")
+
 	// iOS packaging
 	MakefilePackageIOS(&b, func(b *strings.Builder) {
 		g.writeIOSArchRules(b)
@@ -135,12 +140,17 @@ $(DIST_IOS_DIR)/obj/$(1)/impl.o: $(IMPL_SOURCES) $(GEN_HEADER)
 	xcrun --sdk $(3) clang $(CFLAGS) $(LIB_VISIBILITY_FLAGS) \
 		-target $(2) -c -o $$@ $$<
 
+$(DIST_IOS_DIR)/obj/$(1)/abi_info.o: $(ABI_INFO_SOURCE) $(GEN_HEADER)
+	@mkdir -p $$(dir $$@)
+	xcrun --sdk $(3) clang $(LIB_C_FLAGS) \
+		-target $(2) -c -o $$@ $$<
+
 $(DIST_IOS_DIR)/obj/$(1)/platform.o: $(PLATFORM_SERVICES)/ios.c $(GEN_HEADER)
 	@mkdir -p $$(dir $$@)
 	xcrun --sdk $(3) clang $(LIB_C_FLAGS) \
 		-target $(2) -c -o $$@ $$<
 
-$(DIST_IOS_DIR)/obj/$(1)/$(LIB_NAME).a: $(DIST_IOS_DIR)/obj/$(1)/impl.o $(DIST_IOS_DIR)/obj/$(1)/platform.o
+$(DIST_IOS_DIR)/obj/$(1)/$(LIB_NAME).a: $(DIST_IOS_DIR)/obj/$(1)/impl.o $(DIST_IOS_DIR)/obj/$(1)/abi_info.o $(DIST_IOS_DIR)/obj/$(1)/platform.o
 	ar rcs $$@ $$^
 
 endef
@@ -156,16 +166,19 @@ func (g *CMakefileGenerator) writeAndroidABIRules(b *strings.Builder) {
 	b.WriteString(`# $(1) = ABI name, $(2) = NDK target triple
 define BUILD_ANDROID_ABI
 
-$(DIST_ANDROID_DIR)/src/main/jniLibs/$(1)/$(LIB_NAME).so: $(IMPL_SOURCES) $(GEN_JNI_SOURCE) $(PLATFORM_SERVICES)/android.c $(GEN_HEADER)
+$(DIST_ANDROID_DIR)/src/main/jniLibs/$(1)/$(LIB_NAME).so: $(IMPL_SOURCES) $(ABI_INFO_SOURCE) $(GEN_JNI_SOURCE) $(PLATFORM_SERVICES)/android.c $(GEN_HEADER)
 	@mkdir -p $(DIST_ANDROID_DIR)/obj/$(1) $$(dir $$@)
 	"$(NDK_BIN)/$(2)-clang" $(CROSS_CFLAGS) -fPIC $(CROSS_VISIBILITY) \
 		-c -o $(DIST_ANDROID_DIR)/obj/$(1)/impl.o $(IMPL_SOURCES)
+	"$(NDK_BIN)/$(2)-clang" $(CROSS_LIB_C_FLAGS) -fPIC \
+		-c -o $(DIST_ANDROID_DIR)/obj/$(1)/abi_info.o $(ABI_INFO_SOURCE)
 	"$(NDK_BIN)/$(2)-clang" $(CROSS_LIB_C_FLAGS) -fPIC \
 		-c -o $(DIST_ANDROID_DIR)/obj/$(1)/jni.o $(GEN_JNI_SOURCE)
 	"$(NDK_BIN)/$(2)-clang" $(CROSS_LIB_C_FLAGS) -fPIC \
 		-c -o $(DIST_ANDROID_DIR)/obj/$(1)/platform.o $(PLATFORM_SERVICES)/android.c
 	"$(NDK_BIN)/$(2)-clang" -shared -llog \
 		$(DIST_ANDROID_DIR)/obj/$(1)/impl.o \
+		$(DIST_ANDROID_DIR)/obj/$(1)/abi_info.o \
 		$(DIST_ANDROID_DIR)/obj/$(1)/jni.o \
 		$(DIST_ANDROID_DIR)/obj/$(1)/platform.o \
 		-o $$@
@@ -23,7 +23,7 @@ func (g *CppMakefileGenerator) Generate(ctx *Context) ([]*OutputFile, error) {
 	MakefileMSVCDiscovery(&b)
 	MakefileEmscriptenConfig(&b)
 	MakefileBindingVars(&b, apiName, "generated/")
-	MakefileWASMExports(&b, apiName, ctx.API)
+	MakefileWASMExports(&b, apiName, ctx.API, ctx.ResolvedTypes)
 
 	b.WriteString(`# ── C++ build configuration ───────────────────────────────────────────────────
 
@@ -106,6 +106,8 @@ clean:
 
 `)
 
+	MakefileEventsTest(&b, ctx.API, "$(CXX)", "-Wall -Wextra -std=c++20 -x c++ -I$(GEN_DIR)")
+
 	// iOS packaging
 	MakefilePackageIOS(&b, func(b *strings.Builder) {
 		g.writeIOSArchRules(b)
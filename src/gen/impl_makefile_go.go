@@ -31,8 +31,13 @@ func (g *GoMakefileGenerator) Generate(ctx *Context) ([]*OutputFile, error) {
 	b.WriteString("  CGO_CC := zig cc\n")
 	b.WriteString("endif\n\n")
 
+	// Packaged libraries are release builds, as cargo build --release is for
+	// Rust: the release tag compiles the thread-affinity checks out.
+	b.WriteString("# Build flags of packaged libraries; the release tag compiles out debug checks.\n")
+	b.WriteString("GO_RELEASE_FLAGS := -tags release\n\n")
+
 	MakefileBindingVars(&b, apiName, "generated/")
-	MakefileWASMExports(&b, apiName, ctx.API)
+	MakefileWASMExports(&b, apiName, ctx.API, ctx.ResolvedTypes)
 
 	b.WriteString(`# Ensure codegen runs before any target needs generated files
 $(GEN_HEADER) $(GEN_SWIFT_BINDING) $(GEN_KOTLIN_BINDING) $(GEN_JS_BINDING) $(GEN_JNI_SOURCE): $(STAMP)
@@ -64,9 +69,9 @@ desktop-shared-lib: $(DESKTOP_SHARED_LIB)
 
 $(DESKTOP_SHARED_LIB): $(STAMP)
 ifeq ($(HOST_OS),Darwin)
-	CC="$(CGO_CC)" go build -buildmode=c-shared -ldflags='-extldflags "-Wl,-install_name,@rpath/$(LIB_NAME).$(DYLIB_EXT)"' -o $(DESKTOP_SHARED_LIB) .
+	CC="$(CGO_CC)" go build $(GO_RELEASE_FLAGS) -buildmode=c-shared -ldflags='-extldflags "-Wl,-install_name,@rpath/$(LIB_NAME).$(DYLIB_EXT)"' -o $(DESKTOP_SHARED_LIB) .
 else
-	CC="$(CGO_CC)" go build -buildmode=c-shared -o $(DESKTOP_SHARED_LIB) .
+	CC="$(CGO_CC)" go build $(GO_RELEASE_FLAGS) -buildmode=c-shared -o $(DESKTOP_SHARED_LIB) .
 ifneq (,$(EXE))
 	awk 'BEGIN { print "EXPORTS"; } /^extern "C"/ { next; } /^extern / { gsub(/\(/, " "); print $$3; }' \
 	    $(BUILD_DIR)/$(API_NAME).h > $(BUILD_DIR)/$(DESKTOP_LIB_NAME).def
@@ -80,6 +85,16 @@ clean:
 
 `)
 
+	if len(ctx.API.Events) > 0 {
+		b.WriteString(`# Event ring self-test
+.PHONY: test-events
+
+test-events: $(STAMP)
+	CC="$(CGO_CC)" go test -run 'EventRing|PushEvent' .
+
+`)
+	}
+
 	// iOS packaging
 	MakefilePackageIOS(&b, func(b *strings.Builder) {
 		g.writeIOSArchRules(b)
@@ -122,7 +137,7 @@ $(DIST_IOS_DIR)/obj/$(1)/$(LIB_NAME).a: $(STAMP)
 		CC="$(5)" \
 		CGO_CFLAGS="-arch $(3) -target $(4) -isysroot $(6)" \
 		CGO_LDFLAGS="-arch $(3) -target $(4) -isysroot $(6)" \
-		go build -buildmode=c-archive -o $$@ .
+		go build $(GO_RELEASE_FLAGS) -buildmode=c-archive -o $$@ .
 
 endef
 
@@ -150,7 +165,7 @@ $(DIST_ANDROID_DIR)/src/main/jniLibs/$(1)/$(LIB_NAME).so: $(STAMP)
 	CGO_ENABLED=1 GOOS=android GOARCH=$(2) $(4) \
 		CC=$(NDK_BIN)/$(3)-clang \
 		CGO_CFLAGS="-I generated" \
-		go build -buildmode=c-shared -o $$@ . || (rm -f $(GEN_JNI_SOURCE_LOCAL); exit 1)
+		go build $(GO_RELEASE_FLAGS) -buildmode=c-shared -o $$@ . || (rm -f $(GEN_JNI_SOURCE_LOCAL); exit 1)
 	rm -f $(GEN_JNI_SOURCE_LOCAL)
 
 endef
@@ -166,7 +181,7 @@ $(eval $(call BUILD_ANDROID_ABI,x86,386,i686-linux-android$(ANDROID_MIN_API),))
 func (g *GoMakefileGenerator) writeWASMBuildRule(b *strings.Builder) {
 	b.WriteString(`$(DIST_WEB_DIR)/$(API_NAME).wasm: $(STAMP)
 	@mkdir -p $(dir $@)
-	GOOS=wasip1 GOARCH=wasm go build -o $@ .
+	GOOS=wasip1 GOARCH=wasm go build $(GO_RELEASE_FLAGS) -o $@ .
 
 `)
 }
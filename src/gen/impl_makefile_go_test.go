@@ -53,9 +53,16 @@ func TestGoMakefileGenerator_Content(t *testing.T) {
 	if !strings.Contains(content, `CC="$(CGO_CC)" go build -o $(BUILD_DIR)/$(API_NAME)$(EXE) .`) {
 		t.Error("missing CC=$(CGO_CC) go build in run target")
 	}
-	if !strings.Contains(content, `CC="$(CGO_CC)" go build -buildmode=c-shared`) {
+	if !strings.Contains(content, `CC="$(CGO_CC)" go build $(GO_RELEASE_FLAGS) -buildmode=c-shared`) {
 		t.Error("missing CC=$(CGO_CC) go build -buildmode=c-shared")
 	}
+	// Packaged libraries compile the debug thread checks out
+	if !strings.Contains(content, "GO_RELEASE_FLAGS := -tags release\n") {
+		t.Error("missing GO_RELEASE_FLAGS release tag")
+	}
+	if n := strings.Count(content, "go build $(GO_RELEASE_FLAGS)"); n != 5 {
+		t.Errorf("expected 5 release builds (desktop x2, iOS, Android, WASM), got %d", n)
+	}
 	// CGO_CC variable
 	if !strings.Contains(content, "CGO_CC := clang") {
 		t.Error("missing CGO_CC := clang default")
@@ -184,3 +191,23 @@ func TestGoMakefileGenerator_Packaging(t *testing.T) {
 		t.Error("missing Package.swift in iOS packaging")
 	}
 }
+
+func TestGoMakefileGenerator_EventsTest(t *testing.T) {
+	gen := &GoMakefileGenerator{}
+
+	files, err := gen.Generate(loadTestAPI(t, "events.yaml"))
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if !strings.Contains(string(files[0].Content), `CC="$(CGO_CC)" go test -run 'EventRing|PushEvent' .`) {
+		t.Error("missing test-events target")
+	}
+
+	files, err = gen.Generate(loadTestAPI(t, "minimal.yaml"))
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if strings.Contains(string(files[0].Content), "test-events") {
+		t.Error("test-events should only be emitted when events are declared")
+	}
+}
@@ -33,7 +33,7 @@ endif
 `)
 
 	MakefileBindingVars(&b, apiName, "generated/")
-	MakefileWASMExports(&b, apiName, ctx.API)
+	MakefileWASMExports(&b, apiName, ctx.API, ctx.ResolvedTypes)
 
 	b.WriteString(`# Ensure codegen runs before any target needs generated files
 $(GEN_HEADER) $(GEN_SWIFT_BINDING) $(GEN_KOTLIN_BINDING) $(GEN_JS_BINDING) $(GEN_JNI_SOURCE): $(STAMP)
@@ -24,24 +24,25 @@ func (g *RustImplGenerator) Generate(ctx *Context) ([]*OutputFile, error) {
 	api := ctx.API
 	apiName := api.API.Name
 	hasTypes := len(ctx.ResolvedTypes) > 0
+	typeModules := rustTypeModules(api, ctx.ResolvedTypes)
 
 	genHeader := GeneratedFileHeader(ctx, "//", false)
 	scaffoldHeader := GeneratedFileHeader(ctx, "//", true)
 	scaffoldTomlHeader := GeneratedFileHeader(ctx, "#", true)
 
-	traitFile, err := g.generateTrait(api, apiName, hasTypes)
+	traitFile, err := g.generateTrait(api, apiName, hasTypes, typeModules)
 	if err != nil {
 		return nil, fmt.Errorf("generating trait file: %w", err)
 	}
 	traitFile.Content = prependHeader(genHeader, traitFile.Content)
 
-	ffiFile, err := g.generateFFI(api, apiName, hasTypes)
+	ffiFile, err := g.generateFFI(api, apiName, ABIInfo(ctx), ctx.ResolvedTypes, typeModules)
 	if err != nil {
 		return nil, fmt.Errorf("generating FFI file: %w", err)
 	}
 	ffiFile.Content = prependHeader(genHeader, ffiFile.Content)
 
-	implFile, err := g.generateImpl(api, apiName, hasTypes)
+	implFile, err := g.generateImpl(api, apiName, hasTypes, typeModules)
 	if err != nil {
 		return nil, fmt.Errorf("generating impl file: %w", err)
 	}
@@ -50,25 +51,46 @@ func (g *RustImplGenerator) Generate(ctx *Context) ([]*OutputFile, error) {
 	files := []*OutputFile{traitFile, ffiFile, implFile}
 
 	if hasTypes {
-		typesFile := g.generateTypes(ctx.ResolvedTypes, apiName)
+		typesFile := g.generateTypes(api, ctx.ResolvedTypes, apiName, typeModules)
 		typesFile.Content = prependHeader(genHeader, typesFile.Content)
 		files = append(files, typesFile)
 	}
 
+	// Tables are read and built with the flatc Rust output.
+	var schemaModules []string
+	if len(tableTypes(ctx.ResolvedTypes)) > 0 {
+		schemaModules = flatcSchemaBases(ctx.SchemaTypes)
+	}
+
 	// Package metadata (scaffold — preserved across regeneration)
-	cargoToml := g.generateCargoToml(api, apiName)
+	cargoToml := g.generateCargoToml(api, apiName, len(schemaModules) > 0)
 	cargoToml.Content = prependHeader(scaffoldTomlHeader, cargoToml.Content)
 	files = append(files, cargoToml)
 
-	libRs := g.generateLibRs(apiName, hasTypes)
+	hasEvents := len(api.Events) > 0
+	if hasEvents {
+		eventsFile := g.generateEvents(api, apiName)
+		eventsFile.Content = prependHeader(genHeader, eventsFile.Content)
+		files = append(files, eventsFile)
+	}
+
+	hasAsync := hasAsyncMethods(api)
+	if hasAsync {
+		asyncFile := g.generateAsync(apiName)
+		asyncFile.Content = prependHeader(genHeader, asyncFile.Content)
+		files = append(files, asyncFile)
+	}
+
+	libRs := g.generateLibRs(apiName, schemaModules, hasTypes, hasEvents, hasAsync)
 	libRs.Content = prependHeader(scaffoldHeader, libRs.Content)
 	files = append(files, libRs)
 
 	return files, nil
 }
 
-// generateCargoToml produces the Cargo.toml package manifest.
-func (g *RustImplGenerator) generateCargoToml(api *model.APIDefinition, apiName string) *OutputFile {
+// generateCargoToml produces the Cargo.toml package manifest. APIs with
+// FlatBuffers tables depend on the flatbuffers crate the flatc output uses.
+func (g *RustImplGenerator) generateCargoToml(api *model.APIDefinition, apiName string, hasTables bool) *OutputFile {
 	content := fmt.Sprintf(`[package]
 name = %q
 version = %q
@@ -77,6 +99,9 @@ edition = "2021"
 [lib]
 crate-type = ["cdylib", "staticlib", "rlib"]
 `, apiName, api.API.Version)
+	if hasTables {
+		content += "\n[dependencies]\nflatbuffers = \"24\"\n"
+	}
 
 	return &OutputFile{
 		Path:        "Cargo.toml",
@@ -87,12 +112,22 @@ crate-type = ["cdylib", "staticlib", "rlib"]
 }
 
 // generateLibRs produces the src/lib.rs entry point with module declarations.
-// Generated (non-scaffold) modules use #[path] to reference files in ../generated/.
-func (g *RustImplGenerator) generateLibRs(apiName string, hasTypes bool) *OutputFile {
+// Generated (non-scaffold) modules use #[path] to reference files in ../generated/,
+// including the flatc output of each schema in schemaModules.
+func (g *RustImplGenerator) generateLibRs(apiName string, schemaModules []string, hasTypes, hasEvents, hasAsync bool) *OutputFile {
 	var b strings.Builder
+	for _, base := range schemaModules {
+		fmt.Fprintf(&b, "#[path = \"../generated/flatbuffers/rust/%[1]s_generated.rs\"]\npub mod %[1]s_generated;\n", base)
+	}
 	if hasTypes {
 		fmt.Fprintf(&b, "#[path = \"../generated/%[1]s_types.rs\"]\npub mod %[1]s_types;\n", apiName)
 	}
+	if hasEvents {
+		fmt.Fprintf(&b, "#[path = \"../generated/%[1]s_events.rs\"]\npub mod %[1]s_events;\n", apiName)
+	}
+	if hasAsync {
+		fmt.Fprintf(&b, "#[path = \"../generated/%[1]s_async.rs\"]\npub mod %[1]s_async;\n", apiName)
+	}
 	fmt.Fprintf(&b, `#[path = "../generated/%[1]s_trait.rs"]
 pub mod %[1]s_trait;
 #[path = "../generated/%[1]s_ffi.rs"]
@@ -109,12 +144,15 @@ pub mod %[1]s_impl;
 }
 
 // generateTrait produces the trait definition file.
-func (g *RustImplGenerator) generateTrait(api *model.APIDefinition, apiName string, hasTypes bool) (*OutputFile, error) {
+func (g *RustImplGenerator) generateTrait(api *model.APIDefinition, apiName string, hasTypes bool, typeModules []string) (*OutputFile, error) {
 	var b strings.Builder
 
 	fmt.Fprintf(&b, "use std::ffi::c_void;\n")
 	if hasTypes {
-		fmt.Fprintf(&b, "use crate::%s_types::*;\n", apiName)
+		writeRustTypesUse(&b, apiName, typeModules)
+	}
+	if hasAsyncMethods(api) {
+		fmt.Fprintf(&b, "use crate::%s_async::Completion;\n", apiName)
 	}
 	b.WriteString("\n")
 
@@ -127,7 +165,12 @@ func (g *RustImplGenerator) generateTrait(api *model.APIDefinition, apiName stri
 
 		traitName := ToPascalCase(iface.Name)
 		fmt.Fprintf(&b, "/// %s interface methods.\n", traitName)
-		fmt.Fprintf(&b, "pub trait %s {\n", traitName)
+		if iface.Extends != "" {
+			// Inherited methods live on the base trait, a supertrait here.
+			fmt.Fprintf(&b, "pub trait %s: %s {\n", traitName, ToPascalCase(iface.Extends))
+		} else {
+			fmt.Fprintf(&b, "pub trait %s {\n", traitName)
+		}
 
 		for _, method := range iface.Methods {
 			writeTraitMethod(&b, &method)
@@ -143,33 +186,66 @@ func (g *RustImplGenerator) generateTrait(api *model.APIDefinition, apiName stri
 }
 
 // generateFFI produces the C ABI shim file.
-func (g *RustImplGenerator) generateFFI(api *model.APIDefinition, apiName string, hasTypes bool) (*OutputFile, error) {
+func (g *RustImplGenerator) generateFFI(api *model.APIDefinition, apiName, abiInfo string, resolved resolver.ResolvedTypes, typeModules []string) (*OutputFile, error) {
 	var b strings.Builder
 
-	b.WriteString("use std::ffi::{c_void, CStr};\n")
+	if hasDeprecations(api) {
+		// The shim forwards deprecated trait methods; their callers get the warning.
+		b.WriteString("#![allow(deprecated)]\n\n")
+	}
+	hasBuffers := hasBufferReturns(api, resolved)
+	if hasBuffers {
+		b.WriteString("use std::alloc::{alloc, dealloc, Layout};\n")
+	}
+	hasStrings := hasStringReturns(api)
+	if hasStrings {
+		b.WriteString("use std::ffi::{c_void, CStr, CString};\n")
+	} else {
+		b.WriteString("use std::ffi::{c_void, CStr};\n")
+	}
 	b.WriteString("use std::os::raw::c_char;\n")
-	if hasTypes {
-		fmt.Fprintf(&b, "use crate::%s_types::*;\n", apiName)
+	if len(resolved) > 0 {
+		writeRustTypesUse(&b, apiName, typeModules)
+	}
+	if hasAsyncMethods(api) {
+		fmt.Fprintf(&b, "use crate::%s_async::*;\n", apiName)
 	}
 	fmt.Fprintf(&b, "use crate::%s_trait::*;\n", apiName)
 	fmt.Fprintf(&b, "use crate::%s_impl::*;\n\n", apiName)
 
+	writeFFIABIInfo(&b, apiName, abiInfo)
+	if hasStrings {
+		writeFFIStringHelpers(&b, apiName)
+	}
+	if hasBuffers {
+		writeFFIBufferHelpers(&b, apiName)
+	}
+	if hasTableParams(api) {
+		writeFFITableHelpers(&b)
+	}
+	if hasThreadChecks(api) {
+		writeRustThreadCheckModule(&b, apiName)
+	}
+
 	for _, iface := range api.Interfaces {
 		fmt.Fprintf(&b, "// %s\n", iface.Name)
 
+		handleName, hasConstructors := iface.ConstructorHandleName()
+		recordThread := hasConstructors && recordsCreator(api, handleName)
 		// Constructor shims
 		for _, ctor := range iface.Constructors {
-			writeFFIConstructor(&b, apiName, iface.Name, &ctor)
+			writeFFIConstructor(&b, apiName, iface.Name, &ctor, recordThread, resolved)
 			b.WriteString("\n")
 		}
 		// Auto-destructor shim
-		if handleName, ok := iface.ConstructorHandleName(); ok {
-			writeFFIDestructor(&b, apiName, iface.Name, handleName)
+		if hasConstructors {
+			destructor := threadAffinityDestructor(api, &iface, handleName)
+			writeFFIDestructor(&b, apiName, iface.Name, &destructor, recordThread)
 			b.WriteString("\n")
 		}
 		// Regular method shims
 		for _, method := range iface.Methods {
-			writeFFIFunction(&b, apiName, iface.Name, &method)
+			writeFFIFunction(&b, apiName, iface.Name, &method, resolved)
 			b.WriteString("\n")
 		}
 	}
@@ -181,12 +257,15 @@ func (g *RustImplGenerator) generateFFI(api *model.APIDefinition, apiName string
 }
 
 // generateImpl produces the stub implementation file (scaffold — not overwritten).
-func (g *RustImplGenerator) generateImpl(api *model.APIDefinition, apiName string, hasTypes bool) (*OutputFile, error) {
+func (g *RustImplGenerator) generateImpl(api *model.APIDefinition, apiName string, hasTypes bool, typeModules []string) (*OutputFile, error) {
 	var b strings.Builder
 
 	b.WriteString("use std::ffi::c_void;\n")
 	if hasTypes {
-		fmt.Fprintf(&b, "use crate::%s_types::*;\n", apiName)
+		writeRustTypesUse(&b, apiName, typeModules)
+	}
+	if hasAsyncMethods(api) {
+		fmt.Fprintf(&b, "use crate::%s_async::Completion;\n", apiName)
 	}
 	fmt.Fprintf(&b, "use crate::%s_trait::*;\n\n", apiName)
 
@@ -220,35 +299,526 @@ func (g *RustImplGenerator) generateImpl(api *model.APIDefinition, apiName strin
 	}, nil
 }
 
+// generateEvents produces the event ring module: the single-producer ring the
+// implementation pushes into, pluggable descriptor signaling, and the exported
+// poll/signal functions declared in the C header.
+func (g *RustImplGenerator) generateEvents(api *model.APIDefinition, apiName string) *OutputFile {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, `use std::cell::UnsafeCell;
+use std::sync::atomic::{AtomicU32, Ordering};
+use std::sync::OnceLock;
+
+/// Byte capacity of the event ring. Must be a power of two.
+pub const EVENT_RING_CAPACITY: u32 = %d;
+
+/// Event kinds, numbered as in the C header. Zero is reserved for "no event".
+#[repr(u32)]
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub enum EventKind {
+`, EventRingCapacity)
+	for i, ev := range api.Events {
+		fmt.Fprintf(&b, "    /// %s\n", ev.Type)
+		fmt.Fprintf(&b, "    %s = %d,\n", ToPascalCase(ev.Name), EventKindValue(i))
+	}
+	b.WriteString(`}
+
+/// Single-producer, single-consumer byte ring. Records are
+/// [kind:u32][size:u32][payload padded to 8 bytes]; head and tail are
+/// free-running byte counters.
+pub struct Ring {
+    head: AtomicU32,
+    tail: AtomicU32,
+    data: UnsafeCell<[u8; EVENT_RING_CAPACITY as usize]>,
+}
+
+// Only the producer writes head and the bytes between tail and capacity;
+// only the consumer writes tail.
+unsafe impl Sync for Ring {}
+
+impl Ring {
+    pub const fn new() -> Self {
+        Ring {
+            head: AtomicU32::new(0),
+            tail: AtomicU32::new(0),
+            data: UnsafeCell::new([0; EVENT_RING_CAPACITY as usize]),
+        }
+    }
+
+    fn record_size(size: u32) -> u32 {
+        8 + ((size + 7) & !7)
+    }
+
+    unsafe fn copy_in(&self, pos: u32, src: &[u8]) {
+        let data = &mut *self.data.get();
+        let off = (pos & (EVENT_RING_CAPACITY - 1)) as usize;
+        let first = src.len().min(data.len() - off);
+        data[off..off + first].copy_from_slice(&src[..first]);
+        data[..src.len() - first].copy_from_slice(&src[first..]);
+    }
+
+    unsafe fn copy_out(&self, pos: u32, dst: &mut [u8]) {
+        let data = &*self.data.get();
+        let off = (pos & (EVENT_RING_CAPACITY - 1)) as usize;
+        let first = dst.len().min(data.len() - off);
+        dst[..first].copy_from_slice(&data[off..off + first]);
+        let rest = dst.len() - first;
+        dst[first..].copy_from_slice(&data[..rest]);
+    }
+
+    pub fn is_empty(&self) -> bool {
+        self.head.load(Ordering::Acquire) == self.tail.load(Ordering::Acquire)
+    }
+
+    /// Appends one record. Returns false when the ring is full.
+    /// Must not be called from more than one thread at a time.
+    pub fn push(&self, kind: u32, payload: &[u8]) -> bool {
+        if payload.len() > (EVENT_RING_CAPACITY - 8) as usize {
+            return false;
+        }
+        let head = self.head.load(Ordering::Relaxed);
+        let tail = self.tail.load(Ordering::Acquire);
+        let need = Self::record_size(payload.len() as u32);
+        if need > EVENT_RING_CAPACITY - head.wrapping_sub(tail) {
+            return false;
+        }
+        unsafe {
+            self.copy_in(head, &kind.to_ne_bytes());
+            self.copy_in(head.wrapping_add(4), &(payload.len() as u32).to_ne_bytes());
+            self.copy_in(head.wrapping_add(8), payload);
+        }
+        self.head.store(head.wrapping_add(need), Ordering::Release);
+        true
+    }
+
+    /// Pops one record into buf using the C poll protocol: returns the payload
+    /// size, 0 with kind 0 when empty, or -(required size) when buf is too
+    /// small (the record stays queued).
+    pub fn poll(&self, out_kind: &mut u32, buf: &mut [u8]) -> i32 {
+        let tail = self.tail.load(Ordering::Relaxed);
+        let head = self.head.load(Ordering::Acquire);
+        if head == tail {
+            *out_kind = 0;
+            return 0;
+        }
+        let mut header = [0u8; 8];
+        unsafe { self.copy_out(tail, &mut header) };
+        let kind = u32::from_ne_bytes([header[0], header[1], header[2], header[3]]);
+        let size = u32::from_ne_bytes([header[4], header[5], header[6], header[7]]);
+        *out_kind = kind;
+        if size as usize > buf.len() {
+            return -(size as i32);
+        }
+        unsafe { self.copy_out(tail.wrapping_add(8), &mut buf[..size as usize]) };
+        self.tail.store(tail.wrapping_add(Self::record_size(size)), Ordering::Release);
+        size as i32
+    }
+}
+
+/// Wakes the binding when events are pushed. Replace the default
+/// eventfd/pipe signaling with set_event_signal.
+pub trait EventSignal: Send + Sync {
+    /// Readable descriptor the binding waits on, or -1 if none.
+    fn fd(&self) -> i32;
+    fn notify(&self);
+    fn clear(&self);
+}
+
+static EVENT_RING: Ring = Ring::new();
+static EVENT_SIGNAL: OnceLock<Box<dyn EventSignal>> = OnceLock::new();
+
+/// Installs custom signaling. Must be called before the first push or poll;
+/// returns false if a signal is already installed.
+pub fn set_event_signal(signal: Box<dyn EventSignal>) -> bool {
+    EVENT_SIGNAL.set(signal).is_ok()
+}
+
+fn event_signal() -> &'static dyn EventSignal {
+    EVENT_SIGNAL.get_or_init(|| Box::new(DefaultEventSignal::open())).as_ref()
+}
+
+/// Pushes a FlatBuffer-serialized event payload and signals the binding.
+/// Returns false when the ring is full (the event is dropped).
+/// Must not be called from more than one thread at a time.
+pub fn push_event(kind: EventKind, payload: &[u8]) -> bool {
+    if !EVENT_RING.push(kind as u32, payload) {
+        return false;
+    }
+    event_signal().notify();
+    true
+}
+`)
+
+	for _, ev := range api.Events {
+		fmt.Fprintf(&b, `
+/// Pushes a serialized %s.
+pub fn push_%s(payload: &[u8]) -> bool {
+    push_event(EventKind::%s, payload)
+}
+`, ev.Type, ev.Name, ToPascalCase(ev.Name))
+	}
+
+	fmt.Fprintf(&b, `
+#[no_mangle]
+pub unsafe extern "C" fn %[1]s(out_kind: *mut u32, buffer: *mut u8, buffer_size: u32) -> i32 {
+    if EVENT_RING.is_empty() {
+        // Clear before polling so a concurrent push re-signals.
+        event_signal().clear();
+    }
+    let buf: &mut [u8] = if buffer.is_null() {
+        &mut []
+    } else {
+        std::slice::from_raw_parts_mut(buffer, buffer_size as usize)
+    };
+    EVENT_RING.poll(&mut *out_kind, buf)
+}
+
+#[no_mangle]
+pub extern "C" fn %[2]s() -> i32 {
+    event_signal().fd()
+}
+`, EventPollFunctionName(apiName), EventSignalFDFunctionName(apiName))
+
+	b.WriteString(`
+/// Default signaling: eventfd on Linux/Android, a non-blocking pipe on other
+/// Unix platforms, and no descriptor elsewhere.
+struct DefaultEventSignal {
+    read_fd: i32,
+    write_fd: i32,
+}
+
+#[cfg(any(target_os = "linux", target_os = "android"))]
+impl DefaultEventSignal {
+    fn open() -> Self {
+        extern "C" {
+            fn eventfd(initval: u32, flags: i32) -> i32;
+        }
+        const EFD_NONBLOCK: i32 = 0o4000;
+        const EFD_CLOEXEC: i32 = 0o2000000;
+        let fd = unsafe { eventfd(0, EFD_NONBLOCK | EFD_CLOEXEC) };
+        DefaultEventSignal { read_fd: fd, write_fd: fd }
+    }
+}
+
+#[cfg(all(unix, not(any(target_os = "linux", target_os = "android"))))]
+impl DefaultEventSignal {
+    fn open() -> Self {
+        extern "C" {
+            fn pipe(fds: *mut i32) -> i32;
+            fn fcntl(fd: i32, cmd: i32, ...) -> i32;
+        }
+        const F_SETFD: i32 = 2;
+        const F_GETFL: i32 = 3;
+        const F_SETFL: i32 = 4;
+        const FD_CLOEXEC: i32 = 1;
+        const O_NONBLOCK: i32 = 0x4;
+        let mut fds = [-1i32; 2];
+        unsafe {
+            if pipe(fds.as_mut_ptr()) != 0 {
+                return DefaultEventSignal { read_fd: -1, write_fd: -1 };
+            }
+            for &fd in &fds {
+                fcntl(fd, F_SETFL, fcntl(fd, F_GETFL) | O_NONBLOCK);
+                fcntl(fd, F_SETFD, FD_CLOEXEC);
+            }
+        }
+        DefaultEventSignal { read_fd: fds[0], write_fd: fds[1] }
+    }
+}
+
+#[cfg(not(unix))]
+impl DefaultEventSignal {
+    fn open() -> Self {
+        DefaultEventSignal { read_fd: -1, write_fd: -1 }
+    }
+}
+
+#[cfg(unix)]
+extern "C" {
+    fn read(fd: i32, buf: *mut u8, count: usize) -> isize;
+    fn write(fd: i32, buf: *const u8, count: usize) -> isize;
+}
+
+impl EventSignal for DefaultEventSignal {
+    fn fd(&self) -> i32 {
+        self.read_fd
+    }
+
+    fn notify(&self) {
+        #[cfg(unix)]
+        if self.write_fd >= 0 {
+            // eventfd requires an 8-byte counter; a pipe accepts any byte count.
+            let one = 1u64.to_ne_bytes();
+            unsafe { write(self.write_fd, one.as_ptr(), one.len()) };
+        }
+    }
+
+    fn clear(&self) {
+        #[cfg(unix)]
+        if self.read_fd >= 0 {
+            let mut drain = [0u8; 64];
+            while unsafe { read(self.read_fd, drain.as_mut_ptr(), drain.len()) } > 0 {
+                if self.read_fd == self.write_fd {
+                    break;
+                }
+            }
+        }
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn round_trip_in_order() {
+        let ring = Box::new(Ring::new());
+        let mut kind = 0;
+        let mut out = [0u8; 64];
+        assert_eq!(ring.poll(&mut kind, &mut out), 0);
+        assert_eq!(kind, 0);
+        assert!(ring.push(1, &[1, 2, 3]));
+        assert!(ring.push(2, &[4, 5, 6, 7, 8]));
+        assert_eq!(ring.poll(&mut kind, &mut out), 3);
+        assert_eq!((kind, &out[..3]), (1, &[1u8, 2, 3][..]));
+        assert_eq!(ring.poll(&mut kind, &mut out), 5);
+        assert_eq!((kind, &out[..5]), (2, &[4u8, 5, 6, 7, 8][..]));
+        assert!(ring.is_empty());
+    }
+
+    #[test]
+    fn short_buffer_keeps_event() {
+        let ring = Box::new(Ring::new());
+        let mut kind = 0;
+        let mut out = [0u8; 128];
+        assert!(ring.push(1, &[7u8; 100]));
+        assert_eq!(ring.poll(&mut kind, &mut out[..10]), -100);
+        assert_eq!(ring.poll(&mut kind, &mut out), 100);
+        assert_eq!(out[99], 7);
+    }
+
+    #[test]
+    fn wraps_around() {
+        let ring = Box::new(Ring::new());
+        let mut kind = 0;
+        let mut out = [0u8; 64];
+        for i in 0..(EVENT_RING_CAPACITY / 64) * 3 {
+            let payload = [i as u8; 61];
+            assert!(ring.push(1, &payload));
+            assert_eq!(ring.poll(&mut kind, &mut out), 61);
+            assert_eq!(&out[..61], &payload[..]);
+        }
+    }
+
+    #[test]
+    fn full_ring_rejects_push() {
+        let ring = Box::new(Ring::new());
+        let mut kind = 0;
+        let mut out = [0u8; 256];
+        let mut pushed = 0;
+        while ring.push(1, &[0u8; 248]) {
+            pushed += 1;
+        }
+        assert_eq!(pushed, EVENT_RING_CAPACITY / 256);
+        while ring.poll(&mut kind, &mut out) == 248 {
+            pushed -= 1;
+        }
+        assert_eq!(pushed, 0);
+    }
+
+    #[test]
+    fn exported_poll_drains_pushed_events() {
+`)
+	first := api.Events[0]
+	fmt.Fprintf(&b, `        assert!(push_%[1]s(&[9, 9]));
+        let mut kind = 0;
+        let mut out = [0u8; 16];
+        unsafe {
+            assert_eq!(%[2]s(&mut kind, out.as_mut_ptr(), out.len() as u32), 2);
+            assert_eq!(kind, EventKind::%[3]s as u32);
+            assert_eq!(%[2]s(&mut kind, out.as_mut_ptr(), out.len() as u32), 0);
+        }
+        #[cfg(unix)]
+        assert!(%[4]s() >= 0);
+    }
+}
+`, first.Name, EventPollFunctionName(apiName), ToPascalCase(first.Name), EventSignalFDFunctionName(apiName))
+
+	return &OutputFile{
+		Path:    apiName + "_events.rs",
+		Content: []byte(b.String()),
+	}
+}
+
+// generateAsync produces the async operation module: the Completion handed to
+// async trait methods and the Operation the FFI shim keeps behind the opaque
+// operation handle.
+func (g *RustImplGenerator) generateAsync(apiName string) *OutputFile {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, `use std::sync::atomic::{fence, AtomicBool, Ordering};
+use std::sync::{Arc, Mutex};
+
+/// Status values returned by every `+"`<method>_poll`"+` function.
+pub const ASYNC_PENDING: i32 = %d;
+pub const ASYNC_DONE: i32 = %d;
+pub const ASYNC_CANCELLED: i32 = %d;
+`, AsyncStatusPending, AsyncStatusDone, AsyncStatusCancelled)
+
+	b.WriteString(`
+struct State<T> {
+    outcome: Mutex<Option<T>>,
+    cancelled: AtomicBool,
+}
+
+/// Handed to async trait methods. Call ` + "`complete`" + ` once, from any thread;
+/// dropping it without completing reports the operation cancelled.
+pub struct Completion<T> {
+    state: Arc<State<T>>,
+}
+
+impl<T> Completion<T> {
+    /// Finishes the operation with its outcome.
+    pub fn complete(self, outcome: T) {
+        *self.state.outcome.lock().unwrap() = Some(outcome);
+    }
+
+    /// True once the caller has cancelled; stop early and drop the completion.
+    pub fn is_cancelled(&self) -> bool {
+        self.state.cancelled.load(Ordering::Acquire)
+    }
+}
+
+/// Caller side of an async operation, owned by the FFI shim.
+pub struct Operation<T> {
+    state: Arc<State<T>>,
+}
+
+impl<T> Operation<T> {
+    /// Returns the status and, once done, the outcome.
+    pub fn poll(&self) -> (i32, Option<T>) {
+        // Sample ownership before the outcome: once the completion is gone,
+        // anything it stored is already visible.
+        let abandoned = Arc::strong_count(&self.state) == 1;
+        fence(Ordering::Acquire);
+        if let Some(outcome) = self.state.outcome.lock().unwrap().take() {
+            return (ASYNC_DONE, Some(outcome));
+        }
+        if abandoned {
+            (ASYNC_CANCELLED, None)
+        } else {
+            (ASYNC_PENDING, None)
+        }
+    }
+
+    /// Flags cancellation for the implementation.
+    pub fn cancel(&self) {
+        self.state.cancelled.store(true, Ordering::Release);
+    }
+}
+
+/// Creates the two halves of a new operation.
+pub fn operation<T>() -> (Operation<T>, Completion<T>) {
+    let state = Arc::new(State {
+        outcome: Mutex::new(None),
+        cancelled: AtomicBool::new(false),
+    });
+    (
+        Operation {
+            state: Arc::clone(&state),
+        },
+        Completion { state },
+    )
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn completes_across_threads() {
+        let (op, completion) = operation::<u32>();
+        assert_eq!(op.poll(), (ASYNC_PENDING, None));
+        std::thread::spawn(move || completion.complete(7)).join().unwrap();
+        assert_eq!(op.poll(), (ASYNC_DONE, Some(7)));
+    }
+
+    #[test]
+    fn dropped_completion_is_cancelled() {
+        let (op, completion) = operation::<()>();
+        op.cancel();
+        assert!(completion.is_cancelled());
+        drop(completion);
+        assert_eq!(op.poll(), (ASYNC_CANCELLED, None));
+    }
+}
+`)
+
+	return &OutputFile{
+		Path:    apiName + "_async.rs",
+		Content: []byte(b.String()),
+	}
+}
+
 // generateTypes produces the Rust type definitions file from FBS schemas.
-func (g *RustImplGenerator) generateTypes(resolved resolver.ResolvedTypes, apiName string) *OutputFile {
+// The types of a namespace mapped to a module are declared in that module.
+func (g *RustImplGenerator) generateTypes(api *model.APIDefinition, resolved resolver.ResolvedTypes, apiName string, typeModules []string) *OutputFile {
 	var b strings.Builder
 
 	b.WriteString("use std::os::raw::c_char;\n\n")
+	writeRustTypes(&b, resolved, inTarget(api, rustTarget, ""))
+
+	for _, mod := range typeModules {
+		var types strings.Builder
+		writeRustTypes(&types, resolved, inTarget(api, rustTarget, mod))
+		fmt.Fprintf(&b, "pub mod %s {\n    #![allow(unused_imports)]\n    use super::*;\n", mod)
+		for _, other := range typeModules {
+			if other != mod {
+				fmt.Fprintf(&b, "    use super::%s::*;\n", other)
+			}
+		}
+		b.WriteString("\n")
+		b.WriteString(indentLines(strings.TrimSuffix(types.String(), "\n"), "    "))
+		b.WriteString("}\n\n")
+	}
+
+	return &OutputFile{
+		Path:    apiName + "_types.rs",
+		Content: []byte(b.String()),
+	}
+}
 
+// writeRustTypes writes the Rust definitions of the FlatBuffer types that
+// keep selects.
+func writeRustTypes(b *strings.Builder, resolved resolver.ResolvedTypes, keep func(string) bool) {
 	// Collect and sort type names
-	var enumNames, structNames, tableNames []string
+	var enumNames, structNames []string
 	for name, info := range resolved {
+		if !keep(name) {
+			continue
+		}
 		switch info.Kind {
 		case resolver.TypeKindEnum:
 			enumNames = append(enumNames, name)
 		case resolver.TypeKindStruct:
 			structNames = append(structNames, name)
-		case resolver.TypeKindTable:
-			tableNames = append(tableNames, name)
 		}
 	}
+
 	sort.Strings(enumNames)
 	sort.Strings(structNames)
-	sort.Strings(tableNames)
 
 	for _, name := range enumNames {
 		info := resolved[name]
+		if info.BitFlags {
+			writeRustBitFlags(b, name, info)
+			continue
+		}
 		rustName := rustFlatBufferType(name)
 		baseType := rustPrimitiveType(info.BaseType)
-		fmt.Fprintf(&b, "#[repr(%s)]\n#[derive(Debug, Clone, Copy, PartialEq, Eq)]\npub enum %s {\n", baseType, rustName)
+		fmt.Fprintf(b, "#[repr(%s)]\n#[derive(Debug, Clone, Copy, PartialEq, Eq)]\npub enum %s {\n", baseType, rustName)
 		for _, val := range info.EnumValues {
-			fmt.Fprintf(&b, "    %s = %d,\n", val.Name, val.Value)
+			fmt.Fprintf(b, "    %s = %d,\n", val.Name, val.Value)
 		}
 		b.WriteString("}\n\n")
 	}
@@ -256,26 +826,34 @@ func (g *RustImplGenerator) generateTypes(resolved resolver.ResolvedTypes, apiNa
 	for _, name := range structNames {
 		info := resolved[name]
 		rustName := rustFlatBufferType(name)
-		fmt.Fprintf(&b, "#[repr(C)]\n#[derive(Debug, Clone, Copy)]\npub struct %s {\n", rustName)
-		for _, f := range info.Fields {
-			fmt.Fprintf(&b, "    pub %s: %s,\n", f.Name, fbsFieldToRustType(f.Type))
+		fmt.Fprintf(b, "#[repr(C)]\n#[derive(Debug, Clone, Copy)]\npub struct %s {\n", rustName)
+		for _, f := range info.ActiveFields() {
+			fmt.Fprintf(b, "    pub %s: %s,\n", f.Name, fbsFieldToRustType(f.Type))
 		}
 		b.WriteString("}\n\n")
 	}
 
-	for _, name := range tableNames {
-		info := resolved[name]
-		rustName := rustFlatBufferType(name)
-		fmt.Fprintf(&b, "#[repr(C)]\n#[derive(Debug)]\npub struct %s {\n", rustName)
-		for _, f := range info.Fields {
-			fmt.Fprintf(&b, "    pub %s: %s,\n", f.Name, fbsFieldToRustType(f.Type))
+	// Tables cross the ABI in wire format and are read through flatc's
+	// accessors; the aliases give them the same names as the other types.
+	for _, name := range tableTypes(resolved) {
+		if keep(name) {
+			fmt.Fprintf(b, "/// The %s FlatBuffer table.\npub type %s<'a> = %s<'a>;\n\n", name, rustFlatBufferType(name), rustTablePath(resolved, name))
 		}
-		b.WriteString("}\n\n")
 	}
 
-	return &OutputFile{
-		Path:    apiName + "_types.rs",
-		Content: []byte(b.String()),
+	for _, name := range unionTypes(resolved) {
+		if keep(name) {
+			writeRustUnion(b, name, resolved[name], resolved)
+		}
+	}
+}
+
+// writeRustTypesUse writes the use declarations that bring the generated
+// types into scope, including those of the modules namespaces map to.
+func writeRustTypesUse(b *strings.Builder, apiName string, typeModules []string) {
+	fmt.Fprintf(b, "use crate::%s_types::*;\n", apiName)
+	for _, mod := range typeModules {
+		fmt.Fprintf(b, "use crate::%s_types::%s::*;\n", apiName, mod)
 	}
 }
 
@@ -307,6 +885,10 @@ func fbsFieldToRustType(t string) string {
 	case "float64":
 		return "f64"
 	}
+	// Fixed-length array: [T:N]
+	if elem, n, ok := resolver.ArrayField(t); ok {
+		return fmt.Sprintf("[%s; %d]", fbsFieldToRustType(elem), n)
+	}
 	// Vector type: [T]
 	if strings.HasPrefix(t, "[") && strings.HasSuffix(t, "]") {
 		// Vectors are not directly representable in repr(C); use pointer + count
@@ -323,14 +905,31 @@ func writeTraitMethod(b *strings.Builder, method *model.MethodDef) {
 	if method.Description != "" {
 		fmt.Fprintf(b, "    /// %s\n", method.Description)
 	}
+	if method.Since != "" {
+		fmt.Fprintf(b, "    /// Available since %s.\n", method.Since)
+	}
+	if method.IsExperimental() {
+		fmt.Fprintf(b, "    /// **Experimental:** %s\n", experimentalDetail)
+	}
+	for _, note := range ownershipNotes(method) {
+		fmt.Fprintf(b, "    /// %s\n", note)
+	}
+	if method.Deprecated != nil {
+		fmt.Fprintf(b, "    #[deprecated(note = %s)]\n", quoteLiteral(deprecationText(method.Deprecated, sameName)))
+	}
 
 	params := rustTraitParams(method.Parameters)
 	retType := rustTraitReturnType(method)
+	if method.Async {
+		// Async methods report through the completion instead of returning.
+		params = append(params, "completion: "+rustCompletionType(method))
+		retType = ""
+	}
 
 	if len(params) > 0 {
-		fmt.Fprintf(b, "    fn %s(&self, %s)%s;\n", method.Name, strings.Join(params, ", "), retType)
+		fmt.Fprintf(b, "    fn %s(&self, %s)%s;\n", rustIdent(method.Name), strings.Join(params, ", "), retType)
 	} else {
-		fmt.Fprintf(b, "    fn %s(&self)%s;\n", method.Name, retType)
+		fmt.Fprintf(b, "    fn %s(&self)%s;\n", rustIdent(method.Name), retType)
 	}
 }
 
@@ -344,8 +943,18 @@ func rustTraitParams(parameters []model.ParameterDef) []string {
 }
 
 // rustTraitParam maps a single parameter to a Rust trait parameter string.
+// Optional parameters become Option<T>, except handles, which stay raw
+// pointers that are null when absent.
 func rustTraitParam(p *model.ParameterDef) string {
-	return fmt.Sprintf("%s: %s", p.Name, rustTraitParamType(p.Type, p.Transfer))
+	t := rustTraitParamType(p.Type, p.Transfer)
+	if p.Table {
+		// flatc tables are Copy views of the verified buffer.
+		t = rustFlatBufferType(p.Type) + "<'_>"
+	}
+	if _, ok := model.IsHandle(p.Type); p.Optional && !ok {
+		t = "Option<" + t + ">"
+	}
+	return fmt.Sprintf("%s: %s", rustIdent(p.Name), t)
 }
 
 // rustTraitParamType returns the Rust type for a trait parameter.
@@ -381,29 +990,43 @@ func rustTraitParamType(paramType string, transfer string) string {
 // rustTraitReturnType returns the full " -> T" suffix for a trait method,
 // or empty string for void.
 func rustTraitReturnType(method *model.MethodDef) string {
+	outcome := rustOutcomeType(method)
+	if outcome == "()" {
+		return ""
+	}
+	return " -> " + outcome
+}
+
+// rustOutcomeType returns the Rust type a method produces: Result<T, E> for
+// fallible methods, T for infallible ones, () for void.
+func rustOutcomeType(method *model.MethodDef) string {
 	hasError := method.Error != ""
 	hasReturn := method.Returns != nil
 
 	switch {
 	case hasError && hasReturn:
-		inner := rustReturnValueType(method.Returns.Type)
+		inner := rustResultValueType(method.Returns)
 		errType := rustFlatBufferType(method.Error)
-		return fmt.Sprintf(" -> Result<%s, %s>", inner, errType)
+		return fmt.Sprintf("Result<%s, %s>", inner, errType)
 	case hasError && !hasReturn:
 		errType := rustFlatBufferType(method.Error)
-		return fmt.Sprintf(" -> Result<(), %s>", errType)
+		return fmt.Sprintf("Result<(), %s>", errType)
 	case !hasError && hasReturn:
-		inner := rustReturnValueType(method.Returns.Type)
-		return fmt.Sprintf(" -> %s", inner)
+		return rustResultValueType(method.Returns)
 	default:
-		return ""
+		return "()"
 	}
 }
 
+// rustCompletionType returns the completion an async trait method receives.
+func rustCompletionType(method *model.MethodDef) string {
+	return "Completion<" + rustOutcomeType(method) + ">"
+}
+
 // --- FFI helpers ---
 
 // writeFFIConstructor writes an extern "C" shim that creates a new Impl and returns it as a handle.
-func writeFFIConstructor(b *strings.Builder, apiName, ifaceName string, ctor *model.MethodDef) {
+func writeFFIConstructor(b *strings.Builder, apiName, ifaceName string, ctor *model.MethodDef, recordThread bool, resolved resolver.ResolvedTypes) {
 	funcName := CABIFunctionName(apiName, ifaceName, ctor.Name)
 	handleName, _ := model.IsHandle(ctor.Returns.Type)
 
@@ -415,21 +1038,30 @@ func writeFFIConstructor(b *strings.Builder, apiName, ifaceName string, ctor *mo
 
 	fmt.Fprintf(b, "#[no_mangle]\n")
 	fmt.Fprintf(b, "pub unsafe extern \"C\" fn %s(%s) -> i32 {\n", funcName, strings.Join(params, ", "))
+	writeRustThreadCheck(b, apiName, ifaceName, ctor)
+	writeRustTableChecks(b, funcName, ctor, resolved, true)
 	fmt.Fprintf(b, "    let impl_box = Box::new(Impl::new());\n")
 	fmt.Fprintf(b, "    *out_result = Box::into_raw(impl_box) as *mut c_void;\n")
+	if recordThread {
+		b.WriteString("    thread_check::record(*out_result as usize);\n")
+	}
 	_ = handleName
 	b.WriteString("    0\n")
 	b.WriteString("}\n")
 }
 
-// writeFFIDestructor writes an extern "C" shim that deallocates a handle.
-func writeFFIDestructor(b *strings.Builder, apiName, ifaceName, handleName string) {
-	destructor := SyntheticDestructor(handleName)
+// writeFFIDestructor writes an extern "C" shim that deallocates a handle,
+// forgetting the creating thread when forgetThread is set.
+func writeFFIDestructor(b *strings.Builder, apiName, ifaceName string, destructor *model.MethodDef, forgetThread bool) {
 	funcName := CABIFunctionName(apiName, ifaceName, destructor.Name)
-	paramName := destructor.Parameters[0].Name
+	paramName := rustIdent(destructor.Parameters[0].Name)
 
 	fmt.Fprintf(b, "#[no_mangle]\n")
 	fmt.Fprintf(b, "pub unsafe extern \"C\" fn %s(%s: *mut c_void) {\n", funcName, paramName)
+	writeRustThreadCheck(b, apiName, ifaceName, destructor)
+	if forgetThread {
+		fmt.Fprintf(b, "    thread_check::forget(%s as usize);\n", paramName)
+	}
 	fmt.Fprintf(b, "    if !%s.is_null() {\n", paramName)
 	fmt.Fprintf(b, "        drop(Box::from_raw(%s as *mut Impl));\n", paramName)
 	fmt.Fprintf(b, "    }\n")
@@ -437,7 +1069,11 @@ func writeFFIDestructor(b *strings.Builder, apiName, ifaceName, handleName strin
 }
 
 // writeFFIFunction writes a single #[no_mangle] extern "C" shim function.
-func writeFFIFunction(b *strings.Builder, apiName, ifaceName string, method *model.MethodDef) {
+func writeFFIFunction(b *strings.Builder, apiName, ifaceName string, method *model.MethodDef, resolved resolver.ResolvedTypes) {
+	if method.Async {
+		writeFFIAsync(b, apiName, ifaceName, method, resolved)
+		return
+	}
 	funcName := CABIFunctionName(apiName, ifaceName, method.Name)
 	hasError := method.Error != ""
 	hasReturn := method.Returns != nil
@@ -450,7 +1086,20 @@ func writeFFIFunction(b *strings.Builder, apiName, ifaceName string, method *mod
 
 	// Determine return type and out parameter
 	var cReturnType string
+	_, bufferReturn := returnBufferElem(method)
 	switch {
+	case bufferReturn || returnsTable(method):
+		// Buffers and tables are always passed out as pointer + length.
+		if hasError {
+			cReturnType = "i32"
+		}
+		params = append(params, ffiBufferOutParams(method.Returns)...)
+	case returnHasPresenceFlag(method):
+		// Optional by-value results are always passed out with a presence flag.
+		if hasError {
+			cReturnType = "i32"
+		}
+		params = append(params, fmt.Sprintf("out_result: %s", ffiOutParamType(method.Returns.Type)), "out_has_result: *mut bool")
 	case hasError && hasReturn:
 		cReturnType = "i32"
 		params = append(params, fmt.Sprintf("out_result: %s", ffiOutParamType(method.Returns.Type)))
@@ -469,6 +1118,8 @@ func writeFFIFunction(b *strings.Builder, apiName, ifaceName string, method *mod
 
 	fmt.Fprintf(b, "#[no_mangle]\n")
 	fmt.Fprintf(b, "pub unsafe extern \"C\" fn %s(%s)%s {\n", funcName, strings.Join(params, ", "), retSuffix)
+	writeRustThreadCheck(b, apiName, ifaceName, method)
+	writeRustTableChecks(b, funcName, method, resolved, hasError)
 
 	// Body: convert parameters and delegate to trait method
 	writeFFIBody(b, method, ifaceName)
@@ -476,10 +1127,130 @@ func writeFFIFunction(b *strings.Builder, apiName, ifaceName string, method *mod
 	b.WriteString("}\n")
 }
 
+// writeRustTableChecks verifies each table parameter and shadows it with
+// the root of the verified buffer. A shim returning an error code returns
+// InvalidArgument when the error enum has one; others abort. An absent
+// optional table is None.
+func writeRustTableChecks(b *strings.Builder, funcName string, method *model.MethodDef, resolved resolver.ResolvedTypes, returnsError bool) {
+	code, hasCode := invalidArgumentCode(method, resolved)
+	for _, p := range method.Parameters {
+		if !p.Table {
+			continue
+		}
+		name := rustIdent(p.Name)
+		fmt.Fprintf(b, "    let %[1]s = match table_root::<%[2]s>(%[1]s, %[3]s_len) {\n", name, rustFlatBufferType(p.Type), p.Name)
+		if p.Optional {
+			fmt.Fprintf(b, "        Some(root) => Some(root),\n        None if %s.is_null() => None,\n", name)
+		} else {
+			b.WriteString("        Some(root) => root,\n")
+		}
+		if returnsError && hasCode {
+			fmt.Fprintf(b, "        None => return %d,\n", code)
+		} else {
+			fmt.Fprintf(b, "        None => {\n            eprintln!(\"{}\", %q);\n            std::process::abort();\n        }\n", tableCheckMessage(funcName, &p))
+		}
+		b.WriteString("    };\n")
+	}
+}
+
+// writeFFIAsync writes the start/poll/cancel shims of an async method. The
+// operation handle is a boxed Operation; the trait method holds the Completion.
+func writeFFIAsync(b *strings.Builder, apiName, ifaceName string, method *model.MethodDef, resolved resolver.ResolvedTypes) {
+	opType := "Operation<" + rustOutcomeType(method) + ">"
+
+	// start
+	var params []string
+	for _, p := range method.Parameters {
+		params = append(params, ffiParams(&p)...)
+	}
+	startName := AsyncStartFunctionName(apiName, ifaceName, method.Name)
+	fmt.Fprintf(b, "#[no_mangle]\n")
+	fmt.Fprintf(b, "pub unsafe extern \"C\" fn %s(%s) -> *mut c_void {\n", startName, strings.Join(params, ", "))
+	writeRustThreadCheck(b, apiName, ifaceName, method)
+	writeRustTableChecks(b, startName, method, resolved, false)
+	var callArgs []string
+	for _, p := range method.Parameters {
+		writeParamConversion(b, &p)
+		callArgs = append(callArgs, rustConvertedArgName(&p))
+	}
+	for _, p := range method.Parameters {
+		if _, ok := model.IsHandle(p.Type); ok {
+			fmt.Fprintf(b, "    let _self = &*(%s as *mut Impl);\n", rustIdent(p.Name))
+			break
+		}
+	}
+	b.WriteString("    let (op, completion) = operation();\n")
+	callArgs = append(callArgs, "completion")
+	fmt.Fprintf(b, "    %s::%s(_self, %s);\n", ToPascalCase(ifaceName), rustIdent(method.Name), strings.Join(callArgs, ", "))
+	b.WriteString("    Box::into_raw(Box::new(op)) as *mut c_void\n")
+	b.WriteString("}\n\n")
+
+	// poll
+	pollParams := []string{"op: *mut c_void"}
+	if method.Error != "" {
+		pollParams = append(pollParams, "out_error: *mut i32")
+	}
+	if returnsTable(method) {
+		pollParams = append(pollParams, ffiBufferOutParams(method.Returns)...)
+	} else if method.Returns != nil {
+		pollParams = append(pollParams, "out_result: "+ffiOutParamType(method.Returns.Type))
+	}
+	fmt.Fprintf(b, "#[no_mangle]\n")
+	fmt.Fprintf(b, "pub unsafe extern \"C\" fn %s(%s) -> i32 {\n",
+		AsyncPollFunctionName(apiName, ifaceName, method.Name), strings.Join(pollParams, ", "))
+	fmt.Fprintf(b, "    let operation = op as *mut %s;\n", opType)
+	hasError := method.Error != ""
+	hasReturn := method.Returns != nil
+	switch {
+	case hasError && hasReturn:
+		fmt.Fprintf(b, `    let (status, outcome) = (*operation).poll();
+    match outcome {
+        Some(Ok(val)) => {
+            *out_error = 0;
+            %s;
+        }
+        Some(Err(e)) => *out_error = e as i32,
+        None => {}
+    }
+`, ffiPollResultStmt(method.Returns))
+	case hasError && !hasReturn:
+		b.WriteString(`    let (status, outcome) = (*operation).poll();
+    match outcome {
+        Some(Ok(())) => *out_error = 0,
+        Some(Err(e)) => *out_error = e as i32,
+        None => {}
+    }
+`)
+	case !hasError && hasReturn:
+		fmt.Fprintf(b, `    let (status, outcome) = (*operation).poll();
+    if let Some(val) = outcome {
+        %s;
+    }
+`, ffiPollResultStmt(method.Returns))
+	default:
+		b.WriteString("    let (status, _) = (*operation).poll();\n")
+	}
+	b.WriteString(`    if status != ASYNC_PENDING {
+        drop(Box::from_raw(operation));
+    }
+    status
+}
+
+`)
+
+	// cancel
+	fmt.Fprintf(b, "#[no_mangle]\n")
+	fmt.Fprintf(b, "pub unsafe extern \"C\" fn %s(op: *mut c_void) {\n", AsyncCancelFunctionName(apiName, ifaceName, method.Name))
+	fmt.Fprintf(b, "    let operation = Box::from_raw(op as *mut %s);\n", opType)
+	b.WriteString("    operation.cancel();\n")
+	b.WriteString("}\n")
+}
+
 // ffiParams returns the FFI parameter strings for a single API parameter.
 func ffiParams(p *model.ParameterDef) []string {
+	name := rustIdent(p.Name)
 	if model.IsString(p.Type) {
-		return []string{fmt.Sprintf("%s: *const c_char", p.Name)}
+		return []string{fmt.Sprintf("%s: *const c_char", name)}
 	}
 
 	if elemType, ok := model.IsBuffer(p.Type); ok {
@@ -491,29 +1262,135 @@ func ffiParams(p *model.ParameterDef) []string {
 			ptrType = "*const " + rustElem
 		}
 		return []string{
-			fmt.Sprintf("%s: %s", p.Name, ptrType),
+			fmt.Sprintf("%s: %s", name, ptrType),
 			fmt.Sprintf("%s_len: u32", p.Name),
 		}
 	}
 
 	if _, ok := model.IsHandle(p.Type); ok {
-		return []string{fmt.Sprintf("%s: *mut c_void", p.Name)}
+		return []string{fmt.Sprintf("%s: *mut c_void", name)}
+	}
+
+	if p.Table {
+		return []string{
+			fmt.Sprintf("%s: *const u8", name),
+			fmt.Sprintf("%s_len: u32", p.Name),
+		}
+	}
+
+	if hasPresenceFlag(p) {
+		return []string{
+			fmt.Sprintf("%s: bool", PresenceFlagName(p.Name)),
+			fmt.Sprintf("%s: %s", name, rustPrimitiveType(p.Type)),
+		}
 	}
 
 	if model.IsPrimitive(p.Type) {
-		return []string{fmt.Sprintf("%s: %s", p.Name, rustPrimitiveType(p.Type))}
+		return []string{fmt.Sprintf("%s: %s", name, rustPrimitiveType(p.Type))}
 	}
 
 	// FlatBuffer type
 	rustType := rustFlatBufferType(p.Type)
 	if p.Transfer == "ref_mut" {
-		return []string{fmt.Sprintf("%s: *mut %s", p.Name, rustType)}
+		return []string{fmt.Sprintf("%s: *mut %s", name, rustType)}
 	}
-	return []string{fmt.Sprintf("%s: *const %s", p.Name, rustType)}
+	return []string{fmt.Sprintf("%s: *const %s", name, rustType)}
+}
+
+// writeFFIABIInfo writes the exported ABI info function, which returns a
+// static string.
+func writeFFIABIInfo(b *strings.Builder, apiName, abiInfo string) {
+	fmt.Fprintf(b, `#[no_mangle]
+pub extern "C" fn %s() -> *const c_char {
+    b"%s\0".as_ptr() as *const c_char
+}
+
+`, ABIInfoFunctionName(apiName), abiInfo)
+}
+
+// writeFFIStringHelpers writes the string ownership helpers: into_c_string
+// and the exported <api>_string_free.
+func writeFFIStringHelpers(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, `/// Converts a string into a caller-owned C string, released by %[1]s.
+/// Interior NUL bytes truncate the string.
+pub fn into_c_string(s: impl Into<Vec<u8>>) -> *mut c_char {
+    let c = CString::new(s).unwrap_or_else(|e| {
+        let end = e.nul_position();
+        let mut bytes = e.into_vec();
+        bytes.truncate(end);
+        CString::new(bytes).unwrap_or_default()
+    });
+    c.into_raw()
+}
+
+#[no_mangle]
+pub unsafe extern "C" fn %[1]s(s: *mut c_char) {
+    if !s.is_null() {
+        drop(CString::from_raw(s));
+    }
+}
+
+`, StringFreeFunctionName(apiName))
+}
+
+// writeFFIBufferHelpers writes the buffer ownership helpers: into_c_buffer and
+// the exported <api>_buffer_free. The allocation size is kept in a header in
+// front of the data so the caller can release it with the data pointer alone.
+// Implementations also use into_c_buffer for the table of a returned union.
+func writeFFIBufferHelpers(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, `const BUFFER_HEADER: usize = 16;
+
+/// Copies a vector into a caller-owned buffer, released by %[1]s, and
+/// stores its element count in *len. An empty vector comes back as null.
+pub unsafe fn into_c_buffer<T: Copy>(v: Vec<T>, len: *mut u32) -> *mut T {
+    *len = 0;
+    if v.is_empty() {
+        return std::ptr::null_mut();
+    }
+    let bytes = v.len() * std::mem::size_of::<T>();
+    let layout = match Layout::from_size_align(BUFFER_HEADER + bytes, BUFFER_HEADER) {
+        Ok(layout) => layout,
+        Err(_) => return std::ptr::null_mut(),
+    };
+    let base = alloc(layout);
+    if base.is_null() {
+        return std::ptr::null_mut();
+    }
+    (base as *mut usize).write(layout.size());
+    let data = base.add(BUFFER_HEADER);
+    std::ptr::copy_nonoverlapping(v.as_ptr() as *const u8, data, bytes);
+    *len = v.len() as u32;
+    data as *mut T
+}
+
+#[no_mangle]
+pub unsafe extern "C" fn %[1]s(data: *mut c_void) {
+    if !data.is_null() {
+        let base = (data as *mut u8).sub(BUFFER_HEADER);
+        let size = (base as *const usize).read();
+        dealloc(base, Layout::from_size_align_unchecked(size, BUFFER_HEADER));
+    }
+}
+
+`, BufferFreeFunctionName(apiName))
+}
+
+// ffiReturnExpr converts a trait return value expression to its FFI value.
+func ffiReturnExpr(retType, expr string) string {
+	if model.IsString(retType) {
+		return "into_c_string(" + expr + ")"
+	}
+	return expr
 }
 
 // ffiReturnType returns the FFI return type for a value type.
 func ffiReturnType(retType string) string {
+	if model.IsString(retType) {
+		return "*mut c_char"
+	}
+	if elemType, ok := model.IsBuffer(retType); ok {
+		return "*mut " + rustPrimitiveType(elemType)
+	}
 	if _, ok := model.IsHandle(retType); ok {
 		return "*mut c_void"
 	}
@@ -523,11 +1400,65 @@ func ffiReturnType(retType string) string {
 	return rustFlatBufferType(retType)
 }
 
+// ffiResultExpr converts a synchronous trait result expression to its FFI
+// value. An absent optional string becomes null.
+func ffiResultExpr(ret *model.ReturnDef, expr string) string {
+	if ret.Optional && model.IsString(ret.Type) {
+		return expr + ".map_or(std::ptr::null_mut(), into_c_string)"
+	}
+	return ffiReturnExpr(ret.Type, expr)
+}
+
 // ffiOutParamType returns the FFI out-parameter pointer type.
 func ffiOutParamType(retType string) string {
 	return "*mut " + ffiReturnType(retType)
 }
 
+// ffiBufferOutParams returns the out-parameters of a buffer or table result:
+// the data pointer and its length, in elements or bytes.
+func ffiBufferOutParams(ret *model.ReturnDef) []string {
+	outType := ffiOutParamType(ret.Type)
+	if ret.Table {
+		outType = "*mut *mut u8"
+	}
+	return []string{"out_result: " + outType, "out_result_len: *mut u32"}
+}
+
+// ffiBufferArg returns the vector a buffer or table result expression passes
+// to into_c_buffer. An absent optional result is empty, so it comes back null.
+func ffiBufferArg(ret *model.ReturnDef, expr string) string {
+	if ret.Optional {
+		return expr + ".unwrap_or_default()"
+	}
+	return expr
+}
+
+// ffiPollResultStmt returns the statement an async poll shim uses to pass
+// out the result val.
+func ffiPollResultStmt(ret *model.ReturnDef) string {
+	if ret.Table {
+		return "*out_result = into_c_buffer(" + ffiBufferArg(ret, "val") + ", out_result_len)"
+	}
+	return "*out_result = " + ffiReturnExpr(ret.Type, "val")
+}
+
+// writeFFITableHelpers writes table_root, which verifies a table parameter
+// and returns its root, or None if the buffer is null or not a valid
+// FlatBuffer of that table.
+func writeFFITableHelpers(b *strings.Builder) {
+	b.WriteString(`unsafe fn table_root<'a, T>(data: *const u8, len: u32) -> Option<T::Inner>
+where
+    T: 'a + flatbuffers::Follow<'a> + flatbuffers::Verifiable,
+{
+    if data.is_null() {
+        return None;
+    }
+    flatbuffers::root::<T>(std::slice::from_raw_parts(data, len as usize)).ok()
+}
+
+`)
+}
+
 // writeFFIBody writes the function body of an FFI shim.
 func writeFFIBody(b *strings.Builder, method *model.MethodDef, ifaceName string) {
 	hasError := method.Error != ""
@@ -543,7 +1474,7 @@ func writeFFIBody(b *strings.Builder, method *model.MethodDef, ifaceName string)
 	selfExpr := "&Impl"
 	for _, p := range method.Parameters {
 		if _, ok := model.IsHandle(p.Type); ok {
-			fmt.Fprintf(b, "    let _self = &*(%s as *mut Impl);\n", p.Name)
+			fmt.Fprintf(b, "    let _self = &*(%s as *mut Impl);\n", rustIdent(p.Name))
 			selfExpr = "_self"
 			break
 		}
@@ -555,21 +1486,53 @@ func writeFFIBody(b *strings.Builder, method *model.MethodDef, ifaceName string)
 	for _, p := range method.Parameters {
 		callArgs = append(callArgs, rustConvertedArgName(&p))
 	}
-	call := fmt.Sprintf("%s::%s(%s, %s)", traitName, method.Name, selfExpr, strings.Join(callArgs, ", "))
+	call := fmt.Sprintf("%s::%s(%s, %s)", traitName, rustIdent(method.Name), selfExpr, strings.Join(callArgs, ", "))
 	if len(method.Parameters) == 0 {
-		call = fmt.Sprintf("%s::%s(%s)", traitName, method.Name, selfExpr)
+		call = fmt.Sprintf("%s::%s(%s)", traitName, rustIdent(method.Name), selfExpr)
 	}
 
+	_, bufferReturn := returnBufferElem(method)
+	bufferReturn = bufferReturn || returnsTable(method)
 	switch {
-	case hasError && hasReturn:
+	case bufferReturn && hasError:
 		fmt.Fprintf(b, `    match %s {
         Ok(val) => {
-            *out_result = val;
+            *out_result = into_c_buffer(%s, out_result_len);
             0
         }
         Err(e) => e as i32,
     }
+`, call, ffiBufferArg(method.Returns, "val"))
+	case bufferReturn:
+		fmt.Fprintf(b, "    *out_result = into_c_buffer(%s, out_result_len);\n", ffiBufferArg(method.Returns, call))
+	case returnHasPresenceFlag(method) && hasError:
+		fmt.Fprintf(b, `    match %s {
+        Ok(val) => {
+            *out_has_result = val.is_some();
+            if let Some(val) = val {
+                *out_result = val;
+            }
+            0
+        }
+        Err(e) => e as i32,
+    }
+`, call)
+	case returnHasPresenceFlag(method):
+		fmt.Fprintf(b, `    let val = %s;
+    *out_has_result = val.is_some();
+    if let Some(val) = val {
+        *out_result = val;
+    }
 `, call)
+	case hasError && hasReturn:
+		fmt.Fprintf(b, `    match %s {
+        Ok(val) => {
+            *out_result = %s;
+            0
+        }
+        Err(e) => e as i32,
+    }
+`, call, ffiResultExpr(method.Returns, "val"))
 	case hasError && !hasReturn:
 		fmt.Fprintf(b, `    match %s {
         Ok(()) => 0,
@@ -577,7 +1540,7 @@ func writeFFIBody(b *strings.Builder, method *model.MethodDef, ifaceName string)
     }
 `, call)
 	case !hasError && hasReturn:
-		fmt.Fprintf(b, "    %s\n", call)
+		fmt.Fprintf(b, "    %s\n", ffiResultExpr(method.Returns, call))
 	default:
 		fmt.Fprintf(b, "    %s;\n", call)
 	}
@@ -585,16 +1548,22 @@ func writeFFIBody(b *strings.Builder, method *model.MethodDef, ifaceName string)
 
 // writeParamConversion writes the unsafe conversion of a single C parameter to its Rust equivalent.
 func writeParamConversion(b *strings.Builder, p *model.ParameterDef) {
+	if p.Optional {
+		writeOptionalParamConversion(b, p)
+		return
+	}
+
+	name := rustIdent(p.Name)
 	if model.IsString(p.Type) {
-		fmt.Fprintf(b, "    let %s = CStr::from_ptr(%s).to_str().expect(\"invalid UTF-8\");\n", p.Name, p.Name)
+		fmt.Fprintf(b, "    let %s = CStr::from_ptr(%s).to_str().expect(\"invalid UTF-8\");\n", name, name)
 		return
 	}
 
 	if _, ok := model.IsBuffer(p.Type); ok {
 		if p.Transfer == "ref_mut" {
-			fmt.Fprintf(b, "    let %s = std::slice::from_raw_parts_mut(%s, %s_len as usize);\n", p.Name, p.Name, p.Name)
+			fmt.Fprintf(b, "    let %s = std::slice::from_raw_parts_mut(%s, %s_len as usize);\n", name, name, p.Name)
 		} else {
-			fmt.Fprintf(b, "    let %s = std::slice::from_raw_parts(%s, %s_len as usize);\n", p.Name, p.Name, p.Name)
+			fmt.Fprintf(b, "    let %s = std::slice::from_raw_parts(%s, %s_len as usize);\n", name, name, p.Name)
 		}
 		return
 	}
@@ -604,6 +1573,11 @@ func writeParamConversion(b *strings.Builder, p *model.ParameterDef) {
 		return
 	}
 
+	if p.Table {
+		// Converted when verified by writeRustTableChecks.
+		return
+	}
+
 	if model.IsPrimitive(p.Type) {
 		// Primitive passed directly — no conversion needed.
 		return
@@ -611,16 +1585,36 @@ func writeParamConversion(b *strings.Builder, p *model.ParameterDef) {
 
 	// FlatBuffer type — dereference the pointer to a reference.
 	if p.Transfer == "ref_mut" {
-		fmt.Fprintf(b, "    let %s = &mut *%s;\n", p.Name, p.Name)
+		fmt.Fprintf(b, "    let %s = &mut *%s;\n", name, name)
 	} else {
-		fmt.Fprintf(b, "    let %s = &*%s;\n", p.Name, p.Name)
+		fmt.Fprintf(b, "    let %s = &*%s;\n", name, name)
+	}
+}
+
+// writeOptionalParamConversion writes the conversion of an optional C
+// parameter to an Option. Absent strings and FlatBuffer refs are null and
+// absent primitives have their presence flag cleared.
+func writeOptionalParamConversion(b *strings.Builder, p *model.ParameterDef) {
+	name := rustIdent(p.Name)
+	switch {
+	case p.Table:
+		// Converted when verified by writeRustTableChecks.
+	case model.IsString(p.Type):
+		fmt.Fprintf(b, "    let %[1]s = if %[1]s.is_null() { None } else { Some(CStr::from_ptr(%[1]s).to_str().expect(\"invalid UTF-8\")) };\n", name)
+	case hasPresenceFlag(p):
+		fmt.Fprintf(b, "    let %[1]s = if %[2]s { Some(%[1]s) } else { None };\n", name, PresenceFlagName(p.Name))
+	case model.IsFlatBufferType(p.Type) && p.Transfer == "ref_mut":
+		fmt.Fprintf(b, "    let %[1]s = %[1]s.as_mut();\n", name)
+	case model.IsFlatBufferType(p.Type):
+		fmt.Fprintf(b, "    let %[1]s = %[1]s.as_ref();\n", name)
 	}
+	// Handles pass through as raw pointers that are null when absent.
 }
 
 // rustConvertedArgName returns the name to use for a converted parameter in the call.
 func rustConvertedArgName(p *model.ParameterDef) string {
 	// All conversions shadow the original name, so just return the name.
-	return p.Name
+	return rustIdent(p.Name)
 }
 
 // --- Impl helpers ---
@@ -629,11 +1623,15 @@ func rustConvertedArgName(p *model.ParameterDef) string {
 func writeImplMethod(b *strings.Builder, method *model.MethodDef) {
 	params := rustTraitParams(method.Parameters)
 	retType := rustTraitReturnType(method)
+	if method.Async {
+		params = append(params, "completion: "+rustCompletionType(method))
+		retType = ""
+	}
 
 	if len(params) > 0 {
-		fmt.Fprintf(b, "    fn %s(&self, %s)%s {\n", method.Name, strings.Join(params, ", "), retType)
+		fmt.Fprintf(b, "    fn %s(&self, %s)%s {\n", rustIdent(method.Name), strings.Join(params, ", "), retType)
 	} else {
-		fmt.Fprintf(b, "    fn %s(&self)%s {\n", method.Name, retType)
+		fmt.Fprintf(b, "    fn %s(&self)%s {\n", rustIdent(method.Name), retType)
 	}
 	fmt.Fprintf(b, "        // TODO: implement %s\n", method.Name)
 	b.WriteString("        todo!()\n")
@@ -674,6 +1672,12 @@ func rustPrimitiveType(t string) string {
 
 // rustReturnValueType returns the Rust type for a return value.
 func rustReturnValueType(retType string) string {
+	if model.IsString(retType) {
+		return "String"
+	}
+	if elemType, ok := model.IsBuffer(retType); ok {
+		return "Vec<" + rustPrimitiveType(elemType) + ">"
+	}
 	if _, ok := model.IsHandle(retType); ok {
 		return "*mut c_void"
 	}
@@ -683,6 +1687,20 @@ func rustReturnValueType(retType string) string {
 	return rustFlatBufferType(retType)
 }
 
+// rustResultValueType returns the Rust type for a method result. Optional
+// results become Option<T>, except handles, which are null when absent.
+func rustResultValueType(ret *model.ReturnDef) string {
+	t := rustReturnValueType(ret.Type)
+	if ret.Table {
+		// The finished FlatBuffer, e.g. builder.finished_data().to_vec().
+		t = "Vec<u8>"
+	}
+	if _, ok := model.IsHandle(ret.Type); ok || !ret.Optional {
+		return t
+	}
+	return "Option<" + t + ">"
+}
+
 // rustFlatBufferType converts a FlatBuffers type to a Rust type name.
 // e.g., "Common.ErrorCode" -> "CommonErrorCode"
 func rustFlatBufferType(t string) string {
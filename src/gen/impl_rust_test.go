@@ -327,7 +327,7 @@ func TestRustImplGenerator_FallibleWithoutReturn(t *testing.T) {
 	}
 }
 
-func TestRustImplGenerator_MutableRefParam(t *testing.T) {
+func TestRustImplGenerator_TableReturn(t *testing.T) {
 	ctx := loadTestAPI(t, "full.yaml")
 	gen := &RustImplGenerator{}
 
@@ -339,17 +339,15 @@ func TestRustImplGenerator_MutableRefParam(t *testing.T) {
 	trait := string(files[0].Content)
 	ffi := string(files[1].Content)
 
-	// poll_events has ref_mut FlatBuffer parameter (Common.EventQueue)
-	if !strings.Contains(trait, "events: &mut CommonEventQueue") {
-		t.Error("missing &mut CommonEventQueue trait parameter for ref_mut FlatBuffer")
+	// poll_events returns a Common.EventQueue table as its finished FlatBuffer
+	if !strings.Contains(trait, "fn poll_events(&self, engine: *mut c_void) -> Result<Vec<u8>, CommonErrorCode>;") {
+		t.Error("missing Vec<u8> trait result for table return")
 	}
-
-	// FFI should accept *mut pointer and dereference to &mut
-	if !strings.Contains(ffi, "events: *mut CommonEventQueue") {
-		t.Error("missing *mut CommonEventQueue FFI parameter for ref_mut FlatBuffer")
+	if !strings.Contains(ffi, "example_app_engine_events_poll_events(engine: *mut c_void, out_result: *mut *mut u8, out_result_len: *mut u32) -> i32 {") {
+		t.Error("missing byte buffer out-parameters for table return")
 	}
-	if !strings.Contains(ffi, "let events = &mut *events") {
-		t.Error("missing &mut * dereference for ref_mut FlatBuffer parameter")
+	if !strings.Contains(ffi, "            *out_result = into_c_buffer(val, out_result_len);\n") {
+		t.Error("missing into_c_buffer copy of table return")
 	}
 }
 
@@ -362,3 +360,448 @@ func TestRustImplGenerator_Registry(t *testing.T) {
 		t.Errorf("expected name %q, got %q", "impl_rust", gen.Name())
 	}
 }
+
+func TestRustImplGenerator_Events(t *testing.T) {
+	ctx := loadTestAPI(t, "events.yaml")
+	gen := &RustImplGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	events := findOutputFile(t, files, "event_api_events.rs")
+	if events.Scaffold {
+		t.Error("events module should be regenerated, not a scaffold")
+	}
+	content := string(events.Content)
+	for _, want := range []string{
+		"pub const EVENT_RING_CAPACITY: u32 = 65536;",
+		"pub enum EventKind {",
+		"    Touch = 1,",
+		"    EntitySpawned = 2,",
+		"pub struct Ring {",
+		"pub trait EventSignal: Send + Sync {",
+		"pub fn set_event_signal(signal: Box<dyn EventSignal>) -> bool {",
+		"pub fn push_event(kind: EventKind, payload: &[u8]) -> bool {",
+		"pub fn push_touch(payload: &[u8]) -> bool {",
+		"pub fn push_entity_spawned(payload: &[u8]) -> bool {",
+		"pub unsafe extern \"C\" fn event_api_event_poll(out_kind: *mut u32, buffer: *mut u8, buffer_size: u32) -> i32 {",
+		"pub extern \"C\" fn event_api_event_signal_fd() -> i32 {",
+		"#[cfg(any(target_os = \"linux\", target_os = \"android\"))]",
+		"#[cfg(test)]",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("events module missing %q", want)
+		}
+	}
+
+	libRs := string(findOutputFile(t, files, "src/lib.rs").Content)
+	if !strings.Contains(libRs, "#[path = \"../generated/event_api_events.rs\"]\npub mod event_api_events;") {
+		t.Error("lib.rs should declare the events module")
+	}
+}
+
+func TestRustImplGenerator_Async(t *testing.T) {
+	ctx := loadTestAPI(t, "async.yaml")
+	gen := &RustImplGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	async := findOutputFile(t, files, "async_api_async.rs")
+	if async.Scaffold {
+		t.Error("async module should be regenerated, not a scaffold")
+	}
+	content := string(async.Content)
+	for _, want := range []string{
+		"pub struct Completion<T> {",
+		"pub fn complete(self, outcome: T) {",
+		"pub fn is_cancelled(&self) -> bool {",
+		"pub struct Operation<T> {",
+		"pub fn poll(&self) -> (i32, Option<T>) {",
+		"#[cfg(test)]",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("async module missing %q", want)
+		}
+	}
+
+	trait := string(findOutputFile(t, files, "async_api_trait.rs").Content)
+	for _, want := range []string{
+		"use crate::async_api_async::Completion;",
+		"fn load_model(&self, engine: *mut c_void, path: &str, completion: Completion<Result<Vec<u8>, CommonErrorCode>>);",
+		"fn decode(&self, engine: *mut c_void, data: &[u8], completion: Completion<u32>);",
+		"fn warm_up(&self, engine: *mut c_void, completion: Completion<Result<(), CommonErrorCode>>);",
+	} {
+		if !strings.Contains(trait, want) {
+			t.Errorf("trait missing %q", want)
+		}
+	}
+
+	ffi := string(findOutputFile(t, files, "async_api_ffi.rs").Content)
+	for _, want := range []string{
+		"pub unsafe extern \"C\" fn async_api_assets_load_model_start(engine: *mut c_void, path: *const c_char) -> *mut c_void {",
+		"let (op, completion) = operation();",
+		"pub unsafe extern \"C\" fn async_api_assets_load_model_poll(op: *mut c_void, out_error: *mut i32, out_result: *mut *mut u8, out_result_len: *mut u32) -> i32 {",
+		"            *out_result = into_c_buffer(val, out_result_len);\n",
+		"pub unsafe extern \"C\" fn async_api_assets_load_model_cancel(op: *mut c_void) {",
+	} {
+		if !strings.Contains(ffi, want) {
+			t.Errorf("FFI missing %q", want)
+		}
+	}
+
+	libRs := string(findOutputFile(t, files, "src/lib.rs").Content)
+	if !strings.Contains(libRs, "#[path = \"../generated/async_api_async.rs\"]\npub mod async_api_async;") {
+		t.Error("lib.rs should declare the async module")
+	}
+}
+
+func TestRustImplGenerator_StringReturns(t *testing.T) {
+	ctx := loadTestAPI(t, "strings.yaml")
+	gen := &RustImplGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	ffi := string(findOutputFile(t, files, "strings_api_ffi.rs").Content)
+	for _, want := range []string{
+		"use std::ffi::{c_void, CStr, CString};",
+		"pub fn into_c_string(s: impl Into<Vec<u8>>) -> *mut c_char {",
+		"pub unsafe extern \"C\" fn strings_api_string_free(s: *mut c_char) {",
+		"drop(CString::from_raw(s));",
+		"pub unsafe extern \"C\" fn strings_api_text_get_name(engine: *mut c_void) -> *mut c_char {",
+		"into_c_string(Text::get_name(_self, engine))",
+		"out_result: *mut *mut c_char) -> i32 {",
+		"*out_result = into_c_string(val);",
+	} {
+		if !strings.Contains(ffi, want) {
+			t.Errorf("FFI file missing %q", want)
+		}
+	}
+
+	trait := string(findOutputFile(t, files, "strings_api_trait.rs").Content)
+	if !strings.Contains(trait, "-> String;") {
+		t.Error("trait should return String for string returns")
+	}
+
+	ctx = loadTestAPI(t, "minimal.yaml")
+	files, err = gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if strings.Contains(string(findOutputFile(t, files, "test_api_ffi.rs").Content), "into_c_string") {
+		t.Error("string helpers should only be emitted when strings are returned")
+	}
+}
+
+func TestRustImplGenerator_BufferReturns(t *testing.T) {
+	ctx := loadTestAPI(t, "buffers.yaml")
+	gen := &RustImplGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	ffi := string(findOutputFile(t, files, "buffers_api_ffi.rs").Content)
+	for _, want := range []string{
+		"use std::alloc::{alloc, dealloc, Layout};",
+		"unsafe fn into_c_buffer<T: Copy>(v: Vec<T>, len: *mut u32) -> *mut T {",
+		"pub unsafe extern \"C\" fn buffers_api_buffer_free(data: *mut c_void) {",
+		"pub unsafe extern \"C\" fn buffers_api_media_read_tensor(engine: *mut c_void, out_result: *mut *mut f32, out_result_len: *mut u32) {",
+		"*out_result = into_c_buffer(Media::read_tensor(_self, engine), out_result_len);",
+		"out_result: *mut *mut i64, out_result_len: *mut u32) -> i32 {",
+		"*out_result = into_c_buffer(val, out_result_len);",
+	} {
+		if !strings.Contains(ffi, want) {
+			t.Errorf("FFI file missing %q", want)
+		}
+	}
+
+	trait := string(findOutputFile(t, files, "buffers_api_trait.rs").Content)
+	for _, want := range []string{
+		"-> Result<Vec<u8>, CommonErrorCode>;",
+		"fn read_tensor(&self, engine: *mut c_void) -> Vec<f32>;",
+	} {
+		if !strings.Contains(trait, want) {
+			t.Errorf("trait missing %q", want)
+		}
+	}
+
+	ctx = loadTestAPI(t, "minimal.yaml")
+	files, err = gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if strings.Contains(string(findOutputFile(t, files, "test_api_ffi.rs").Content), "into_c_buffer") {
+		t.Error("buffer helpers should only be emitted when buffers are returned")
+	}
+}
+
+func TestRustImplGenerator_Optionals(t *testing.T) {
+	ctx := loadTestAPI(t, "optional.yaml")
+	gen := &RustImplGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	trait := string(findOutputFile(t, files, "optional_api_trait.rs").Content)
+	for _, want := range []string{
+		"fn set_label(&self, engine: *mut c_void, label: Option<&str>);",
+		"fn set_limit(&self, engine: *mut c_void, limit: Option<u32>) -> Result<(), CommonErrorCode>;",
+		"config: Option<RenderingRendererConfig<'_>>);",
+		"fn get_label(&self, engine: *mut c_void) -> Option<String>;",
+		"fn get_limit(&self, engine: *mut c_void) -> Option<u32>;",
+		"fn get_config(&self, engine: *mut c_void) -> Result<Option<Vec<u8>>, CommonErrorCode>;",
+	} {
+		if !strings.Contains(trait, want) {
+			t.Errorf("trait file missing %q", want)
+		}
+	}
+
+	ffi := string(findOutputFile(t, files, "optional_api_ffi.rs").Content)
+	for _, want := range []string{
+		"let label = if label.is_null() { None } else { Some(",
+		"fn optional_api_scene_set_limit(engine: *mut c_void, has_limit: bool, limit: u32) -> i32 {",
+		"let limit = if has_limit { Some(limit) } else { None };",
+		"out_result: *mut u32, out_has_result: *mut bool) {",
+		"*out_has_result = val.is_some();",
+		"if let Some(val) = val {",
+		"        Some(root) => Some(root),\n        None if config.is_null() => None,\n",
+		"            *out_result = into_c_buffer(val.unwrap_or_default(), out_result_len);\n",
+	} {
+		if !strings.Contains(ffi, want) {
+			t.Errorf("FFI file missing %q", want)
+		}
+	}
+}
+
+func TestRustImplGenerator_Extends(t *testing.T) {
+	ctx := loadTestAPI(t, "extends.yaml")
+	gen := &RustImplGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	trait := string(findOutputFile(t, files, "extends_api_trait.rs").Content)
+	for _, want := range []string{
+		"pub trait Node {",
+		"pub trait Texture: Node {",
+		"pub trait Mesh: Node {",
+		"pub trait Scene {",
+	} {
+		if !strings.Contains(trait, want) {
+			t.Errorf("trait file missing %q", want)
+		}
+	}
+}
+
+func TestRustImplGenerator_Lifecycle(t *testing.T) {
+	ctx := loadTestAPI(t, "lifecycle.yaml")
+	gen := &RustImplGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	trait := string(findOutputFile(t, files, "lifecycle_api_trait.rs").Content)
+	for _, want := range []string{
+		"    /// Available since 1.2.0.\n    fn draw_quad(",
+		"    /// Available since 1.0.0.\n    #[deprecated(note = \"Quads replace sprites. Use draw_quad instead.\")]\n    fn draw_sprite(",
+		"    /// **Experimental:** May change or be removed without a deprecation period.\n    fn set_vsync(",
+		"    #[deprecated(note = \"The legacy \\\"immediate\\\" path is retired\")]\n    fn flush(",
+	} {
+		if !strings.Contains(trait, want) {
+			t.Errorf("trait file missing %q", want)
+		}
+	}
+
+	ffi := string(findOutputFile(t, files, "lifecycle_api_ffi.rs").Content)
+	if !strings.Contains(ffi, "\n#![allow(deprecated)]\n") {
+		t.Error("FFI shim should allow calls to deprecated trait methods")
+	}
+}
+
+func TestRustImplGenerator_ThreadAffinity(t *testing.T) {
+	ctx := loadTestAPI(t, "threading.yaml")
+	gen := &RustImplGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	ffi := string(findOutputFile(t, files, "threading_api_ffi.rs").Content)
+	for _, want := range []string{
+		"mod thread_check {",
+		"if cfg!(debug_assertions) && !is_main_thread() {",
+		"threading_api_window_set_title(window: *mut c_void, title: *const c_char) {\n    thread_check::assert_main(\"threading_api_window_set_title\");\n",
+		"    *out_result = Box::into_raw(impl_box) as *mut c_void;\n    thread_check::record(*out_result as usize);\n",
+		"    thread_check::assert_creator(session as usize, \"threading_api_session_destroy_session\");\n    thread_check::forget(session as usize);\n",
+		"    thread_check::assert_creator(session as usize, \"threading_api_session_fetch_start\");\n",
+	} {
+		if !strings.Contains(ffi, want) {
+			t.Errorf("FFI file missing %q", want)
+		}
+	}
+	if strings.Count(ffi, "thread_check::record(") != 1 {
+		t.Error("only the Session constructor should record its creating thread")
+	}
+}
+
+func TestRustImplGenerator_Ownership(t *testing.T) {
+	ctx := loadTestAPI(t, "ownership.yaml")
+	gen := &RustImplGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(findOutputFile(t, files, "ownership_api_trait.rs").Content)
+
+	for _, want := range []string{
+		"    /// Takes ownership of node, even when the call fails; do not use or destroy it afterwards.\n    fn attach(",
+		"    /// The returned Node is borrowed from its owner; do not destroy it or use it after the owner is destroyed.\n    fn root_node(",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Rust trait missing %q", want)
+		}
+	}
+}
+
+func TestRustImplGenerator_Tables(t *testing.T) {
+	ctx := loadTestAPI(t, "fields.yaml")
+	gen := &RustImplGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	types := string(findOutputFile(t, files, "fields_api_types.rs").Content)
+	if !strings.Contains(types, "/// The Fields.StreamConfig FlatBuffer table.\npub type FieldsStreamConfig<'a> = crate::fields_generated::fields::StreamConfig<'a>;\n") {
+		t.Error("types missing alias of the flatc StreamConfig table")
+	}
+	if strings.Contains(types, "pub struct FieldsStreamConfig") {
+		t.Error("tables should not be declared as repr(C) structs")
+	}
+
+	trait := string(findOutputFile(t, files, "fields_api_trait.rs").Content)
+	for _, want := range []string{
+		"fn reconfigure(&self, stream: *mut c_void, config: FieldsStreamConfig<'_>) -> Result<(), FieldsErrorCode>;",
+		"fn info(&self, stream: *mut c_void) -> Vec<u8>;",
+	} {
+		if !strings.Contains(trait, want) {
+			t.Errorf("trait missing %q", want)
+		}
+	}
+
+	ffi := string(findOutputFile(t, files, "fields_api_ffi.rs").Content)
+	for _, want := range []string{
+		"unsafe fn table_root<'a, T>(data: *const u8, len: u32) -> Option<T::Inner>",
+		"fields_api_stream_create_stream(config: *const u8, config_len: u32, out_result: *mut *mut c_void) -> i32 {\n    let config = match table_root::<FieldsStreamConfig>(config, config_len) {\n        Some(root) => root,\n        None => return 1,\n    };\n",
+		"        None => {\n            eprintln!(\"{}\", \"fields_api_stream_restart: config is not a valid Fields.StreamConfig FlatBuffer\");\n            std::process::abort();\n        }\n",
+		"\"fields_api_stream_prepare_start: config is not a valid Fields.StreamConfig FlatBuffer\");\n            std::process::abort();\n",
+		"fields_api_stream_info(stream: *mut c_void, out_result: *mut *mut u8, out_result_len: *mut u32) {\n",
+		"    *out_result = into_c_buffer(Stream::info(_self, stream), out_result_len);\n",
+	} {
+		if !strings.Contains(ffi, want) {
+			t.Errorf("ffi missing %q", want)
+		}
+	}
+
+	lib := string(findOutputFile(t, files, "src/lib.rs").Content)
+	if !strings.Contains(lib, "#[path = \"../generated/flatbuffers/rust/fields_generated.rs\"]\npub mod fields_generated;\n") {
+		t.Error("lib.rs missing the flatc module of fields.fbs")
+	}
+	cargo := string(findOutputFile(t, files, "Cargo.toml").Content)
+	if !strings.Contains(cargo, "[dependencies]\nflatbuffers = ") {
+		t.Error("Cargo.toml missing the flatbuffers dependency")
+	}
+}
+
+func TestRustImplGenerator_BitFlags(t *testing.T) {
+	ctx := loadTestAPI(t, "flags.yaml")
+	gen := &RustImplGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	types := string(findOutputFile(t, files, "flags_api_types.rs").Content)
+
+	for _, want := range []string{
+		"#[repr(transparent)]\n#[derive(Debug, Clone, Copy, PartialEq, Eq, Hash, Default)]\npub struct MediaCaps(pub u32);\n",
+		"    pub const READ: Self = Self(1);\n    pub const WRITE: Self = Self(2);\n    pub const HAS_AUDIO: Self = Self(16);\n    pub const HDR: Self = Self(32);\n",
+		"    pub const fn all() -> Self {\n        Self(51)\n    }\n",
+		"impl std::ops::BitOr for MediaCaps {\n    type Output = Self;\n    fn bitor(self, rhs: Self) -> Self {\n        Self(self.0 | rhs.0)\n    }\n}\n",
+		"impl std::ops::Not for MediaCaps {\n    type Output = Self;\n    fn not(self) -> Self {\n        Self(!self.0 & Self::all().0)\n    }\n}\n",
+		"pub struct MediaChannels(pub u64);",
+		"#[repr(i32)]\n#[derive(Debug, Clone, Copy, PartialEq, Eq)]\npub enum MediaErrorCode {",
+	} {
+		if !strings.Contains(types, want) {
+			t.Errorf("types missing %q", want)
+		}
+	}
+	if strings.Contains(types, "pub enum MediaCaps") {
+		t.Error("bit_flags enum should not be a Rust enum")
+	}
+}
+
+func TestRustImplGenerator_Unions(t *testing.T) {
+	ctx := loadTestAPI(t, "unions.yaml")
+	gen := &RustImplGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	types := string(findOutputFile(t, files, "unions_api_types.rs").Content)
+
+	for _, want := range []string{
+		"#[repr(C)]\npub union SceneShapeMembers {\n    pub rect: std::mem::ManuallyDrop<SceneRect>,\n}\n",
+		"#[repr(C)]\npub struct SceneShape {\n    pub tag: i32,\n    pub value: SceneShapeMembers,\n    pub table: *const u8,\n    pub table_len: u32,\n}\n",
+		"pub enum SceneShapeRef<'a> {\n    Circle(&'a [u8]),\n    Rect(&'a SceneRect),\n    Caption(&'a [u8]),\n}\n",
+		"    pub const NONE: i32 = 0;\n    pub const CIRCLE: i32 = 1;\n",
+		"    pub fn caption(table: &[u8]) -> Self {\n        Self {\n            tag: Self::CAPTION,\n            table: table.as_ptr(),\n            table_len: table.len() as u32,\n            ..Self::none()\n        }\n    }\n",
+		"            value: SceneShapeMembers { rect: std::mem::ManuallyDrop::new(value) },\n            ..Self::none()\n",
+		"                Self::RECT => Some(SceneShapeRef::Rect(&self.value.rect)),\n",
+		"                Self::CAPTION => Some(SceneShapeRef::Caption(std::slice::from_raw_parts(self.table, self.table_len as usize))),\n",
+		"impl std::fmt::Debug for SceneShape {",
+	} {
+		if !strings.Contains(types, want) {
+			t.Errorf("types missing %q", want)
+		}
+	}
+}
+
+func TestRustImplGenerator_FixedArrays(t *testing.T) {
+	ctx := loadTestAPI(t, "arrays.yaml")
+	gen := &RustImplGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	types := string(findOutputFile(t, files, "arrays_api_types.rs").Content)
+
+	for _, want := range []string{
+		"pub struct GeoMat4 {\n    pub m: [f32; 16],\n}\n",
+		"    pub min: [f64; 3],\n    pub max: [f64; 3],\n",
+	} {
+		if !strings.Contains(types, want) {
+			t.Errorf("types missing %q", want)
+		}
+	}
+}
@@ -28,18 +28,70 @@ func (g *JSWASMGenerator) Generate(ctx *Context) ([]*OutputFile, error) {
 	writeModuleHeader(&b, ctx, api)
 	writeMemoryHelpers(&b)
 	writeStringMarshalling(&b)
+	if hasStringReturns(api) {
+		writeStringReturnHelper(&b, apiName)
+	}
 	writeBufferMarshalling(&b)
+	if hasBufferReturns(api, ctx.ResolvedTypes) {
+		writeBufferReturnHelper(&b, apiName)
+	}
 	writeHandleClasses(&b, api)
 	writeWASIPolyfill(&b)
 	writePlatformServiceImports(&b, apiName)
-	writeWASMLoader(&b, apiName, api)
+	writeWASMLoader(&b, apiName, api, ABIInfo(ctx))
+	if hasAsyncMethods(api) {
+		writeAsyncHelpers(&b)
+	}
 	writeInterfaceWrappers(&b, apiName, api, ctx.ResolvedTypes)
-	writeModuleExports(&b, apiName, api)
+	if len(api.Events) > 0 {
+		writeEventHelpers(&b, apiName, api)
+	}
+	exports := writeJSTypes(&b, api, ctx.ResolvedTypes, inTarget(api, jsTarget, ""))
+
+	// Types of a namespace mapped to a sub-module go to a module of their
+	// own, which this one re-exports as a namespace object.
+	var files []*OutputFile
+	var subModules []string
+	for _, ns := range mappedNamespaces(api, jsTarget) {
+		var types strings.Builder
+		names := writeJSTypes(&types, api, ctx.ResolvedTypes, inTarget(api, jsTarget, ns.JS))
+		if types.Len() == 0 {
+			continue
+		}
+		for i, name := range names {
+			if i == 0 {
+				types.WriteString("// Exports\n")
+			}
+			fmt.Fprintf(&types, "export { %s };\n", name)
+		}
+		files = append(files, &OutputFile{
+			Path:    jsSubModuleFileName(apiName, ns.JS),
+			Content: []byte(GeneratedFileHeader(ctx, "//", false) + "\n" + types.String()),
+		})
+		subModules = append(subModules, ns.JS)
+	}
+	writeModuleExports(&b, apiName, api, exports, subModules)
 
 	filename := apiName + ".js"
-	return []*OutputFile{
+	return append([]*OutputFile{
 		{Path: filename, Content: []byte(b.String())},
-	}, nil
+	}, files...), nil
+}
+
+// writeJSTypes writes the flag objects, union typedefs and object factories
+// generated for the FlatBuffer types that keep selects and returns the names
+// to export.
+func writeJSTypes(b *strings.Builder, api *model.APIDefinition, resolved resolver.ResolvedTypes, keep func(string) bool) []string {
+	flags := writeJSBitFlags(b, resolved, keep)
+	writeJSUnionTypedefs(b, resolved, keep)
+	factories := writeFieldDefaultFactories(b, api, resolved, keep)
+	return append(flags, factories...)
+}
+
+// jsSubModuleFileName returns the file of the sub-module a namespace is
+// mapped to, e.g. "engine_api_scene.js".
+func jsSubModuleFileName(apiName, subModule string) string {
+	return apiName + "_" + subModule + ".js"
 }
 
 // writeModuleHeader writes the top-of-file comment and shared state.
@@ -94,6 +146,22 @@ function _decodeString(ptr) {
 `)
 }
 
+// writeStringReturnHelper writes the helper that decodes a string returned by
+// the library and hands the WASM copy back through the string free export.
+func writeStringReturnHelper(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, `// Decodes a returned string and releases it with %[1]s. NULL reads as ''.
+function _takeString(ptr) {
+  if (ptr === 0) return '';
+  try {
+    return _decodeString(ptr);
+  } finally {
+    _wasm.exports.%[1]s(ptr);
+  }
+}
+
+`, StringFreeFunctionName(apiName))
+}
+
 // writeBufferMarshalling writes TypedArray → WASM linear memory helpers.
 func writeBufferMarshalling(b *strings.Builder) {
 	b.WriteString(`// Buffer marshalling
@@ -116,6 +184,24 @@ function _readBufferFromWasm(ptr, length, TypedArrayCtor) {
 `)
 }
 
+// writeBufferReturnHelper writes the helper that copies a buffer returned by
+// the library into a typed array and hands the WASM copy back through the
+// buffer free export.
+func writeBufferReturnHelper(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, `// Copies a returned buffer into a new typed array and releases it with
+// %[1]s. NULL reads as an empty array.
+function _takeBuffer(ptr, length, TypedArrayCtor) {
+  if (ptr === 0) return new TypedArrayCtor(0);
+  try {
+    return _readBufferFromWasm(ptr, length, TypedArrayCtor);
+  } finally {
+    _wasm.exports.%[1]s(ptr);
+  }
+}
+
+`, BufferFreeFunctionName(apiName))
+}
+
 // writeHandleClasses writes wrapper classes for each handle type.
 func writeHandleClasses(b *strings.Builder, api *model.APIDefinition) {
 	if len(api.Handles) == 0 {
@@ -133,15 +219,26 @@ func writeHandleClasses(b *strings.Builder, api *model.APIDefinition) {
 	}
 
 	b.WriteString("// Handle wrapper classes\n")
-	for _, h := range api.Handles {
+	for _, h := range handlesBaseFirst(api) {
 		destroyFunc, hasDestructor := handleDestructor[h.Name]
-		writeHandleClass(b, h.Name, destroyFunc, hasDestructor)
+		writeJSDocLifecycle(b, "", &h.Lifecycle, sameName)
+		tracked := tracksOwnership(api, h.Name)
+		if parent, ok := api.ParentHandleName(h.Name); ok {
+			writeDerivedHandleClass(b, h.Name, parent, destroyFunc, hasDestructor, tracked)
+			continue
+		}
+		if tracked {
+			writeOwningHandleClass(b, h.Name, destroyFunc, hasDestructor, isBaseHandle(api, h.Name))
+			continue
+		}
+		writeHandleClass(b, h.Name, destroyFunc, hasDestructor, isBaseHandle(api, h.Name))
 	}
 }
 
 // writeHandleClass writes a single handle wrapper class.
 // If hasDestructor is true, dispose() calls the WASM destructor before zeroing the pointer.
-func writeHandleClass(b *strings.Builder, className, destroyFunc string, hasDestructor bool) {
+// A base class also exposes _disposed so subclasses can guard their own destructor.
+func writeHandleClass(b *strings.Builder, className, destroyFunc string, hasDestructor, isBase bool) {
 	fmt.Fprintf(b, `class %[1]s {
   #ptr;
 
@@ -160,6 +257,15 @@ func writeHandleClass(b *strings.Builder, className, destroyFunc string, hasDest
 
 `, className)
 
+	if isBase {
+		b.WriteString(`  /** @internal */
+  get _disposed() {
+    return this.#ptr === 0;
+  }
+
+`)
+	}
+
 	if hasDestructor {
 		fmt.Fprintf(b, `  dispose() {
     if (this.#ptr !== 0) {
@@ -189,6 +295,122 @@ func writeHandleClass(b *strings.Builder, className, destroyFunc string, hasDest
 `)
 }
 
+// writeOwningHandleClass writes a handle wrapper class that tracks whether
+// it still owns its pointer: _take() gives the pointer to a call that takes
+// ownership of it, and a borrowed wrapper's dispose() never destroys it. A
+// base class also exposes _disposed and _owned so subclasses can guard their
+// own destructor.
+func writeOwningHandleClass(b *strings.Builder, className, destroyFunc string, hasDestructor, isBase bool) {
+	fmt.Fprintf(b, `class %[1]s {
+  #ptr;
+  #owned;
+
+  /** @internal */
+  constructor(ptr, owned = true) {
+    this.#ptr = ptr;
+    this.#owned = owned;
+  }
+
+  /** @internal */
+  get _ptr() {
+    if (this.#ptr === 0) {
+      throw new Error('%[1]s has been disposed or moved');
+    }
+    return this.#ptr;
+  }
+
+  /** @internal */
+  _take() {
+    if (!this.#owned) {
+      throw new Error('%[1]s is borrowed and cannot be moved');
+    }
+    const ptr = this._ptr;
+    this.#ptr = 0;
+    return ptr;
+  }
+
+`, className)
+
+	if isBase {
+		b.WriteString(`  /** @internal */
+  get _disposed() {
+    return this.#ptr === 0;
+  }
+
+  /** @internal */
+  get _owned() {
+    return this.#owned;
+  }
+
+`)
+	}
+
+	if hasDestructor {
+		fmt.Fprintf(b, `  dispose() {
+    if (this.#ptr !== 0) {
+      if (this.#owned) {
+        _wasm.exports.%s(this.#ptr);
+      }
+      this.#ptr = 0;
+    }
+  }
+
+`, destroyFunc)
+	} else {
+		b.WriteString(`  dispose() {
+    this.#ptr = 0;
+  }
+
+`)
+	}
+
+	b.WriteString(`  close() {
+    this.dispose();
+  }
+
+  [Symbol.dispose]() {
+    this.dispose();
+  }
+}
+
+`)
+}
+
+// writeDerivedHandleClass writes a handle wrapper class that extends its
+// parent handle's class, inheriting the pointer and the _disposed guard, and
+// the _owned flag when the family tracks ownership.
+func writeDerivedHandleClass(b *strings.Builder, className, parent, destroyFunc string, hasDestructor, tracked bool) {
+	if !hasDestructor {
+		fmt.Fprintf(b, "class %s extends %s {}\n\n", className, parent)
+		return
+	}
+	if tracked {
+		fmt.Fprintf(b, `class %s extends %s {
+  dispose() {
+    if (!this._disposed) {
+      if (this._owned) {
+        _wasm.exports.%s(this._ptr);
+      }
+      super.dispose();
+    }
+  }
+}
+
+`, className, parent, destroyFunc)
+		return
+	}
+	fmt.Fprintf(b, `class %s extends %s {
+  dispose() {
+    if (!this._disposed) {
+      _wasm.exports.%s(this._ptr);
+      super.dispose();
+    }
+  }
+}
+
+`, className, parent, destroyFunc)
+}
+
 // writeWASIPolyfill emits _buildWasiImports(), a minimal WASI snapshot_preview1
 // implementation required by GOOS=wasip1 binaries (e.g. Go WASM).
 // Providing these imports is harmless for non-WASI WASM modules (they are never called).
@@ -338,10 +560,13 @@ function _buildPlatformImports(services) {
 }
 
 // writeWASMLoader writes the async loader function that instantiates the WASM module.
-func writeWASMLoader(b *strings.Builder, apiName string, api *model.APIDefinition) {
+func writeWASMLoader(b *strings.Builder, apiName string, api *model.APIDefinition, abiInfo string) {
 	loaderName := ToCamelCase("load_" + apiName)
-	fmt.Fprintf(b, `// WASM module loader
-async function %s(wasmSource, platformServices) {
+	fmt.Fprintf(b, `// The API version and ABI hash these bindings were generated for.
+const ABI_INFO = '%[2]s';
+
+// WASM module loader
+async function %[1]s(wasmSource, platformServices) {
   const imports = _buildPlatformImports(platformServices);
   let result;
   if (wasmSource instanceof WebAssembly.Module) {
@@ -371,13 +596,23 @@ async function %s(wasmSource, platformServices) {
       if (!e || e.wasiExitCode !== 0) throw e;
     }
   }
+  // Refuse a module built from a different ABI.
+  const abiInfo = _decodeString(_wasm.exports.%[3]s());
+  if (abiInfo.split(' ').pop() !== ABI_INFO.split(' ').pop()) {
+    throw new Error(`+"`%[4]s`"+`);
+  }
   return {
-`, loaderName)
+`, loaderName, abiInfo, ABIInfoFunctionName(apiName), abiMismatchMessage(apiName, "${abiInfo}", "${ABI_INFO}"))
 
 	for _, iface := range api.Interfaces {
 		jsName := ToCamelCase(iface.Name)
 		fmt.Fprintf(b, "    %s: _create%s(),\n", jsName, ToPascalCase(iface.Name))
 	}
+	if len(api.Events) > 0 {
+		b.WriteString("    pollEvent: _pollEvent,\n")
+		b.WriteString("    drainEvents: _drainEvents,\n")
+		b.WriteString("    subscribeEvents: _subscribeEvents,\n")
+	}
 
 	b.WriteString(`  };
 }
@@ -391,6 +626,10 @@ func writeInterfaceWrappers(b *strings.Builder, apiName string, api *model.APIDe
 	for _, iface := range api.Interfaces {
 		factoryName := "_create" + ToPascalCase(iface.Name)
 		fmt.Fprintf(b, "// %s interface\nfunction %s() {\n  return {\n", iface.Name, factoryName)
+		if iface.Extends != "" {
+			// Inherited methods call the base interface's exports.
+			fmt.Fprintf(b, "    ..._create%s(),\n", ToPascalCase(iface.Extends))
+		}
 
 		// Constructors
 		totalMethods := len(iface.Constructors)
@@ -410,7 +649,7 @@ func writeInterfaceWrappers(b *strings.Builder, apiName string, api *model.APIDe
 		}
 		// Auto-destructor
 		if handleName, ok := iface.ConstructorHandleName(); ok {
-			destructor := SyntheticDestructor(handleName)
+			destructor := threadAffinityDestructor(api, &iface, handleName)
 			writeMethodWrapper(b, apiName, iface.Name, &destructor, resolved)
 			if idx < totalMethods-1 {
 				b.WriteString("\n")
@@ -429,18 +668,56 @@ func writeInterfaceWrappers(b *strings.Builder, apiName string, api *model.APIDe
 	}
 }
 
+// writeJSDocLifecycle writes a JSDoc block with any notes followed by @since,
+// @experimental and @deprecated tags, if the element has any. name spells a
+// replacement in JS.
+func writeJSDocLifecycle(b *strings.Builder, indent string, l *model.Lifecycle, name func(string) string, notes ...string) {
+	tags := notes
+	if l.Since != "" {
+		tags = append(tags, "@since "+l.Since)
+	}
+	if l.IsExperimental() {
+		tags = append(tags, "@experimental "+experimentalDetail)
+	}
+	if l.Deprecated != nil {
+		tags = append(tags, "@deprecated "+deprecationText(l.Deprecated, name))
+	}
+	if len(tags) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "%s/**\n", indent)
+	for _, tag := range tags {
+		fmt.Fprintf(b, "%s * %s\n", indent, strings.ReplaceAll(tag, "*/", "*\\/"))
+	}
+	fmt.Fprintf(b, "%s */\n", indent)
+}
+
 // writeMethodWrapper writes a single method wrapper inside an interface object.
 func writeMethodWrapper(b *strings.Builder, apiName, ifaceName string, method *model.MethodDef, resolved resolver.ResolvedTypes) {
+	var notes []string
+	if note := threadAffinityNote(method); note != "" {
+		notes = append(notes, note)
+	}
+	notes = append(notes, bindingOwnershipNotes(method, ToCamelCase, false)...)
+	writeJSDocLifecycle(b, "    ", &method.Lifecycle, ToCamelCase, notes...)
+	if method.Async {
+		writeAsyncMethodWrapper(b, apiName, ifaceName, method, resolved)
+		return
+	}
 	funcName := CABIFunctionName(apiName, ifaceName, method.Name)
 	jsMethodName := ToCamelCase(method.Name)
 	hasError := method.Error != ""
 	hasReturn := method.Returns != nil
-	isFBReturn := hasReturn && model.IsFlatBufferType(method.Returns.Type)
+	isFBReturn := hasReturn && !method.Returns.Table && model.IsFlatBufferType(method.Returns.Type)
+	_, isBufReturn := returnBufferElem(method)
+	isBufReturn = isBufReturn || returnsTable(method)
+	presenceFlag := returnHasPresenceFlag(method)
+	isSret := !hasError && isFBReturn && !presenceFlag
 
 	// Build JS parameter list
 	var jsParams []string
 	for _, p := range method.Parameters {
-		jsParams = append(jsParams, ToCamelCase(p.Name))
+		jsParams = append(jsParams, jsIdent(ToCamelCase(p.Name)))
 	}
 
 	paramStr := strings.Join(jsParams, ", ")
@@ -462,15 +739,21 @@ func writeMethodWrapper(b *strings.Builder, apiName, ifaceName string, method *m
 		}
 	}
 
-	// For fallible + return, allocate out-parameter space
-	if hasError && hasReturn {
+	// For fallible + return, allocate out-parameter space. Buffer and table
+	// returns always come back through a data pointer + length out-parameter
+	// pair, and optional by-value returns through a value + presence flag pair.
+	if (hasError && hasReturn) || isBufReturn || presenceFlag {
 		outSize := wasmOutParamSize(method.Returns.Type, resolved)
 		fmt.Fprintf(b, "      const _outPtr = _malloc(%d);\n", outSize)
 		cleanupPtrs = append(cleanupPtrs, "_outPtr")
 	}
+	if presenceFlag {
+		b.WriteString("      const _hasPtr = _malloc(1);\n")
+		cleanupPtrs = append(cleanupPtrs, "_hasPtr")
+	}
 
 	// For infallible + FlatBuffer return (sret convention on WASM32)
-	if !hasError && isFBReturn {
+	if isSret {
 		outSize := wasmOutParamSize(method.Returns.Type, resolved)
 		fmt.Fprintf(b, "      const _outPtr = _malloc(%d);\n", outSize)
 		cleanupPtrs = append(cleanupPtrs, "_outPtr")
@@ -479,14 +762,19 @@ func writeMethodWrapper(b *strings.Builder, apiName, ifaceName string, method *m
 	// Build WASM call arguments
 	var wasmArgs []string
 	// Sret: prepend _outPtr as first argument
-	if !hasError && isFBReturn {
+	if isSret {
 		wasmArgs = append(wasmArgs, "_outPtr")
 	}
 	for _, mp := range marshalledParams {
 		wasmArgs = append(wasmArgs, mp.wasmArgs...)
 	}
 	// Fallible out-parameter: append _outPtr as last argument
-	if hasError && hasReturn {
+	switch {
+	case isBufReturn:
+		wasmArgs = append(wasmArgs, "_outPtr", "_outPtr + 4")
+	case presenceFlag:
+		wasmArgs = append(wasmArgs, "_outPtr", "_hasPtr")
+	case hasError && hasReturn:
 		wasmArgs = append(wasmArgs, "_outPtr")
 	}
 
@@ -510,7 +798,11 @@ func writeMethodWrapper(b *strings.Builder, apiName, ifaceName string, method *m
 		fmt.Fprintf(b, "%sif (_rc !== 0) {\n", indent)
 		fmt.Fprintf(b, "%s  throw new Error(`%s failed with error code ${_rc}`);\n", indent, jsMethodName)
 		fmt.Fprintf(b, "%s}\n", indent)
-		writeReturnRead(b, indent, method.Returns.Type, resolved)
+		if method.Returns.Optional {
+			writeOptionalReturnRead(b, indent, method, resolved)
+		} else {
+			writeReturnRead(b, indent, method.Returns, resolved)
+		}
 
 	case hasError && !hasReturn:
 		fmt.Fprintf(b, "%sconst _rc = _wasm.exports.%s(%s);\n", indent, funcName, wasmArgStr)
@@ -518,6 +810,18 @@ func writeMethodWrapper(b *strings.Builder, apiName, ifaceName string, method *m
 		fmt.Fprintf(b, "%s  throw new Error(`%s failed with error code ${_rc}`);\n", indent, jsMethodName)
 		fmt.Fprintf(b, "%s}\n", indent)
 
+	case !hasError && isBufReturn:
+		fmt.Fprintf(b, "%s_wasm.exports.%s(%s);\n", indent, funcName, wasmArgStr)
+		if method.Returns.Optional {
+			writeOptionalReturnRead(b, indent, method, resolved)
+		} else {
+			writeReturnRead(b, indent, method.Returns, resolved)
+		}
+
+	case !hasError && presenceFlag:
+		fmt.Fprintf(b, "%s_wasm.exports.%s(%s);\n", indent, funcName, wasmArgStr)
+		writeOptionalReturnRead(b, indent, method, resolved)
+
 	case !hasError && hasReturn:
 		if isFBReturn {
 			// Sret: call as void, read struct fields from _outPtr
@@ -525,7 +829,13 @@ func writeMethodWrapper(b *strings.Builder, apiName, ifaceName string, method *m
 			writeJSFBSObjectReturn(b, indent, method.Returns.Type, resolved)
 		} else {
 			fmt.Fprintf(b, "%sconst _result = _wasm.exports.%s(%s);\n", indent, funcName, wasmArgStr)
-			writeDirectReturn(b, indent, method.Returns.Type)
+			if method.Returns.Optional {
+				// Optional strings and handles are absent when NULL.
+				fmt.Fprintf(b, "%sif (_result === 0) {\n", indent)
+				fmt.Fprintf(b, "%s  return undefined;\n", indent)
+				fmt.Fprintf(b, "%s}\n", indent)
+			}
+			writeDirectReturn(b, indent, method.Returns)
 		}
 
 	default:
@@ -543,6 +853,149 @@ func writeMethodWrapper(b *strings.Builder, apiName, ifaceName string, method *m
 	b.WriteString("    },\n")
 }
 
+// writeAsyncHelpers writes the polling helper that turns an async operation
+// into a Promise.
+func writeAsyncHelpers(b *strings.Builder) {
+	fmt.Fprintf(b, `// Async operations
+// Polls op until it finishes, backing off from %[1]d ms to %[2]d ms, and resolves
+// with read() once it is done. Aborting options.signal cancels the operation
+// and rejects with the signal's reason.
+function _awaitOperation(op, options, poll, cancel, read) {
+  const signal = options ? options.signal : undefined;
+  return new Promise((resolve, reject) => {
+    if (!op) {
+      reject(new Error('async operation could not start'));
+      return;
+    }
+    let delay = %[1]d;
+    let timer = null;
+    const onAbort = () => {
+      clearTimeout(timer);
+      cancel(op);
+      reject(signal.reason ?? new DOMException('The operation was aborted.', 'AbortError'));
+    };
+    const settle = () => {
+      if (signal) signal.removeEventListener('abort', onAbort);
+    };
+    const tick = () => {
+      try {
+        const status = poll(op);
+        if (status === %[3]d) {
+          timer = setTimeout(tick, delay);
+          delay = Math.min(delay * 2, %[2]d);
+          return;
+        }
+        settle();
+        if (status === %[4]d) {
+          resolve(read());
+        } else {
+          reject(new Error('async operation was cancelled'));
+        }
+      } catch (e) {
+        settle();
+        reject(e);
+      }
+    };
+    if (signal) {
+      if (signal.aborted) {
+        onAbort();
+        return;
+      }
+      signal.addEventListener('abort', onAbort, { once: true });
+    }
+    tick();
+  });
+}
+
+`, AsyncPollMinDelayMS, AsyncPollMaxDelayMS, AsyncStatusPending, AsyncStatusDone)
+}
+
+// writeAsyncMethodWrapper writes a method that starts an async operation and
+// returns a Promise for its result. An optional trailing { signal } argument
+// cancels the operation when aborted. Out-parameters live in WASM memory until
+// the Promise settles.
+func writeAsyncMethodWrapper(b *strings.Builder, apiName, ifaceName string, method *model.MethodDef, resolved resolver.ResolvedTypes) {
+	jsMethodName := ToCamelCase(method.Name)
+	hasError := method.Error != ""
+	hasReturn := method.Returns != nil
+
+	var jsParams []string
+	for _, p := range method.Parameters {
+		jsParams = append(jsParams, jsIdent(ToCamelCase(p.Name)))
+	}
+	jsParams = append(jsParams, "options")
+	fmt.Fprintf(b, "    %s(%s) {\n", jsMethodName, strings.Join(jsParams, ", "))
+
+	var pollArgs, outPtrs []string
+	pollArgs = append(pollArgs, "_op")
+	if hasError {
+		b.WriteString("      const _errPtr = _malloc(4);\n")
+		pollArgs = append(pollArgs, "_errPtr")
+		outPtrs = append(outPtrs, "_errPtr")
+	}
+	if hasReturn {
+		fmt.Fprintf(b, "      const _outPtr = _malloc(%d);\n", wasmOutParamSize(method.Returns.Type, resolved))
+		pollArgs = append(pollArgs, "_outPtr")
+		if method.Returns.Table {
+			pollArgs = append(pollArgs, "_outPtr + 4")
+		}
+		outPtrs = append(outPtrs, "_outPtr")
+	}
+
+	// Start — string and buffer copies only need to live until start returns.
+	var wasmArgs, cleanupPtrs []string
+	var marshalLines []string
+	for _, p := range method.Parameters {
+		mp := marshalParam(p)
+		marshalLines = append(marshalLines, mp.marshalLines...)
+		wasmArgs = append(wasmArgs, mp.wasmArgs...)
+		cleanupPtrs = append(cleanupPtrs, mp.cleanupPtrs...)
+	}
+	startCall := fmt.Sprintf("_wasm.exports.%s(%s)", AsyncStartFunctionName(apiName, ifaceName, method.Name), strings.Join(wasmArgs, ", "))
+	if len(cleanupPtrs) > 0 {
+		for _, line := range marshalLines {
+			fmt.Fprintf(b, "      %s\n", line)
+		}
+		b.WriteString("      let _op;\n")
+		b.WriteString("      try {\n")
+		fmt.Fprintf(b, "        _op = %s;\n", startCall)
+		b.WriteString("      } finally {\n")
+		for _, ptr := range cleanupPtrs {
+			fmt.Fprintf(b, "        _free(%s);\n", ptr)
+		}
+		b.WriteString("      }\n")
+	} else {
+		fmt.Fprintf(b, "      const _op = %s;\n", startCall)
+	}
+
+	fmt.Fprintf(b, "      const _promise = _awaitOperation(_op, options,\n")
+	fmt.Fprintf(b, "        (_op) => _wasm.exports.%s(%s),\n", AsyncPollFunctionName(apiName, ifaceName, method.Name), strings.Join(pollArgs, ", "))
+	fmt.Fprintf(b, "        (_op) => _wasm.exports.%s(_op),\n", AsyncCancelFunctionName(apiName, ifaceName, method.Name))
+	b.WriteString("        () => {\n")
+	indent := "          "
+	if hasError {
+		fmt.Fprintf(b, "%sconst _rc = new DataView(_memoryBuffer()).getInt32(_errPtr, true);\n", indent)
+		fmt.Fprintf(b, "%sif (_rc !== 0) {\n", indent)
+		fmt.Fprintf(b, "%s  throw new Error(`%s failed with error code ${_rc}`);\n", indent, jsMethodName)
+		fmt.Fprintf(b, "%s}\n", indent)
+	}
+	if hasReturn {
+		writeReturnRead(b, indent, method.Returns, resolved)
+	}
+	b.WriteString("        });\n")
+
+	if len(outPtrs) == 0 {
+		b.WriteString("      return _promise;\n")
+	} else {
+		b.WriteString("      return _promise.finally(() => {\n")
+		for _, ptr := range outPtrs {
+			fmt.Fprintf(b, "        _free(%s);\n", ptr)
+		}
+		b.WriteString("      });\n")
+	}
+	b.WriteString("    },\n")
+}
+
 // marshalledParam tracks how a parameter is marshalled from JS to WASM.
 type marshalledParam struct {
 	needsMarshal bool
@@ -551,12 +1004,47 @@ type marshalledParam struct {
 	cleanupPtrs  []string // Pointers that need _free in finally block
 }
 
-// marshalParam determines how to pass a JS parameter to WASM.
+// marshalParam determines how to pass a JS parameter to WASM. A table is
+// passed as its finished FlatBuffer, e.g. the flatc Builder's asUint8Array().
+// Optional parameters accept null or undefined: absent strings, tables and
+// handles pass NULL and absent primitives pass a cleared presence flag.
 func marshalParam(p model.ParameterDef) marshalledParam {
-	jsName := ToCamelCase(p.Name)
+	raw := ToCamelCase(p.Name)
+	jsName := jsIdent(raw)
+
+	if p.Optional && model.IsString(p.Type) {
+		ptrVar := "_" + raw + "Ptr"
+		return marshalledParam{
+			needsMarshal: true,
+			marshalLines: []string{
+				fmt.Sprintf("const %s = %s == null ? 0 : _encodeString(%s);", ptrVar, jsName, jsName),
+			},
+			wasmArgs:    []string{ptrVar},
+			cleanupPtrs: []string{ptrVar},
+		}
+	}
+
+	if _, ok := model.IsHandle(p.Type); ok && p.Optional {
+		return marshalledParam{
+			wasmArgs: []string{fmt.Sprintf("%[1]s == null ? 0 : %[1]s.%[2]s", jsName, jsHandleMember(&p))},
+		}
+	}
+
+	if hasPresenceFlag(&p) {
+		zero := "0"
+		if p.Type == "int64" || p.Type == "uint64" {
+			zero = "0n"
+		}
+		return marshalledParam{
+			wasmArgs: []string{
+				fmt.Sprintf("%s == null ? 0 : 1", jsName),
+				fmt.Sprintf("%s ?? %s", jsName, zero),
+			},
+		}
+	}
 
 	if model.IsString(p.Type) {
-		ptrVar := "_" + jsName + "Ptr"
+		ptrVar := "_" + raw + "Ptr"
 		return marshalledParam{
 			needsMarshal: true,
 			marshalLines: []string{
@@ -567,9 +1055,20 @@ func marshalParam(p model.ParameterDef) marshalledParam {
 		}
 	}
 
-	if _, ok := model.IsBuffer(p.Type); ok {
-		ptrVar := "_" + jsName + "Ptr"
-		lenVar := "_" + jsName + "Len"
+	if _, ok := model.IsBuffer(p.Type); ok || p.Table {
+		ptrVar := "_" + raw + "Ptr"
+		lenVar := "_" + raw + "Len"
+		if p.Optional {
+			// An absent optional table passes NULL
+			return marshalledParam{
+				needsMarshal: true,
+				marshalLines: []string{
+					fmt.Sprintf("const [%s, %s] = %s == null ? [0, 0] : _copyBufferToWasm(%s);", ptrVar, lenVar, jsName, jsName),
+				},
+				wasmArgs:    []string{ptrVar, lenVar},
+				cleanupPtrs: []string{ptrVar},
+			}
+		}
 		return marshalledParam{
 			needsMarshal: true,
 			marshalLines: []string{
@@ -582,7 +1081,7 @@ func marshalParam(p model.ParameterDef) marshalledParam {
 
 	if _, ok := model.IsHandle(p.Type); ok {
 		return marshalledParam{
-			wasmArgs: []string{jsName + "._ptr"},
+			wasmArgs: []string{jsName + "." + jsHandleMember(&p)},
 		}
 	}
 
@@ -592,12 +1091,52 @@ func marshalParam(p model.ParameterDef) marshalledParam {
 	}
 }
 
+// jsHandleMember returns the wrapper member passing a handle parameter's
+// pointer, giving it up when the callee takes ownership.
+func jsHandleMember(p *model.ParameterDef) string {
+	if isMoveParam(p) {
+		return "_take()"
+	}
+	return "_ptr"
+}
+
+// jsNewHandle returns the expression wrapping a returned pointer in its
+// handle class. A borrowed handle's wrapper does not own it.
+func jsNewHandle(ret *model.ReturnDef, ptr string) string {
+	handleName, _ := model.IsHandle(ret.Type)
+	if ret.Borrowed {
+		return "new " + handleName + "(" + ptr + ", false)"
+	}
+	return "new " + handleName + "(" + ptr + ")"
+}
+
 // writeReturnRead writes code to read the out-parameter result after a successful fallible call.
-func writeReturnRead(b *strings.Builder, indent string, retType string, resolved resolver.ResolvedTypes) {
-	if handleName, ok := model.IsHandle(retType); ok {
+func writeReturnRead(b *strings.Builder, indent string, ret *model.ReturnDef, resolved resolver.ResolvedTypes) {
+	retType := ret.Type
+	if _, ok := model.IsHandle(retType); ok {
 		fmt.Fprintf(b, "%sconst _view = new DataView(_memoryBuffer());\n", indent)
 		fmt.Fprintf(b, "%sconst _handleVal = _view.getUint32(_outPtr, true);\n", indent)
-		fmt.Fprintf(b, "%sreturn new %s(_handleVal);\n", indent, handleName)
+		fmt.Fprintf(b, "%sreturn %s;\n", indent, jsNewHandle(ret, "_handleVal"))
+		return
+	}
+
+	if model.IsString(retType) {
+		fmt.Fprintf(b, "%sreturn _takeString(new DataView(_memoryBuffer()).getUint32(_outPtr, true));\n", indent)
+		return
+	}
+
+	if ret.Table {
+		// A table result is a copy of its FlatBuffer, read with the flatc
+		// accessors, e.g. T.getRootAsT(new flatbuffers.ByteBuffer(bytes))
+		fmt.Fprintf(b, "%sconst _view = new DataView(_memoryBuffer());\n", indent)
+		fmt.Fprintf(b, "%sreturn _takeBuffer(_view.getUint32(_outPtr, true), _view.getUint32(_outPtr + 4, true), Uint8Array);\n", indent)
+		return
+	}
+
+	if elemType, ok := model.IsBuffer(retType); ok {
+		fmt.Fprintf(b, "%sconst _view = new DataView(_memoryBuffer());\n", indent)
+		fmt.Fprintf(b, "%sreturn _takeBuffer(_view.getUint32(_outPtr, true), _view.getUint32(_outPtr + 4, true), %s);\n",
+			indent, jsTypedArrayName(elemType))
 		return
 	}
 
@@ -612,10 +1151,41 @@ func writeReturnRead(b *strings.Builder, indent string, retType string, resolved
 	writeJSFBSObjectReturn(b, indent, retType, resolved)
 }
 
+// writeOptionalReturnRead writes code to read an optional out-parameter result,
+// returning undefined when it is absent: a cleared presence flag for by-value
+// results, NULL for strings, tables and handles.
+func writeOptionalReturnRead(b *strings.Builder, indent string, method *model.MethodDef, resolved resolver.ResolvedTypes) {
+	retType := method.Returns.Type
+	if returnHasPresenceFlag(method) {
+		fmt.Fprintf(b, "%sif (new DataView(_memoryBuffer()).getUint8(_hasPtr) === 0) {\n", indent)
+		fmt.Fprintf(b, "%s  return undefined;\n", indent)
+		fmt.Fprintf(b, "%s}\n", indent)
+		writeReturnRead(b, indent, method.Returns, resolved)
+		return
+	}
+	fmt.Fprintf(b, "%sconst _resultPtr = new DataView(_memoryBuffer()).getUint32(_outPtr, true);\n", indent)
+	fmt.Fprintf(b, "%sif (_resultPtr === 0) {\n", indent)
+	fmt.Fprintf(b, "%s  return undefined;\n", indent)
+	fmt.Fprintf(b, "%s}\n", indent)
+	if _, ok := model.IsHandle(retType); ok {
+		fmt.Fprintf(b, "%sreturn %s;\n", indent, jsNewHandle(method.Returns, "_resultPtr"))
+	} else if method.Returns.Table {
+		fmt.Fprintf(b, "%sreturn _takeBuffer(_resultPtr, new DataView(_memoryBuffer()).getUint32(_outPtr + 4, true), Uint8Array);\n", indent)
+	} else {
+		fmt.Fprintf(b, "%sreturn _takeString(_resultPtr);\n", indent)
+	}
+}
+
 // writeDirectReturn writes code to return a direct (non-out-param) return value.
-func writeDirectReturn(b *strings.Builder, indent string, retType string) {
-	if handleName, ok := model.IsHandle(retType); ok {
-		fmt.Fprintf(b, "%sreturn new %s(_result);\n", indent, handleName)
+func writeDirectReturn(b *strings.Builder, indent string, ret *model.ReturnDef) {
+	retType := ret.Type
+	if _, ok := model.IsHandle(retType); ok {
+		fmt.Fprintf(b, "%sreturn %s;\n", indent, jsNewHandle(ret, "_result"))
+		return
+	}
+
+	if model.IsString(retType) {
+		fmt.Fprintf(b, "%sreturn _takeString(_result);\n", indent)
 		return
 	}
 
@@ -623,8 +1193,70 @@ func writeDirectReturn(b *strings.Builder, indent string, retType string) {
 	fmt.Fprintf(b, "%sreturn _result;\n", indent)
 }
 
-// writeModuleExports writes the default export and named exports.
-func writeModuleExports(b *strings.Builder, apiName string, api *model.APIDefinition) {
+// writeEventHelpers writes the event kind table and the poll/drain/subscribe
+// helpers exposed on the loaded module. WASM has no signal descriptor, so
+// subscriptions drain once per frame.
+func writeEventHelpers(b *strings.Builder, apiName string, api *model.APIDefinition) {
+	b.WriteString("// Events\nconst EventKind = Object.freeze({\n")
+	for i, ev := range api.Events {
+		fmt.Fprintf(b, "  %s: %d,\n", UpperSnakeCase(ev.Name), EventKindValue(i))
+	}
+	fmt.Fprintf(b, `});
+
+// Pops the next pending event as { kind, payload }, or null when none are pending.
+function _pollEvent() {
+  let size = 256;
+  for (;;) {
+    const kindPtr = _malloc(4);
+    const bufPtr = _malloc(size);
+    try {
+      const n = _wasm.exports.%s(kindPtr, bufPtr, size);
+      if (n < 0) {
+        size = -n;
+        continue;
+      }
+      const kind = new DataView(_memoryBuffer()).getUint32(kindPtr, true);
+      if (kind === 0) return null;
+      return { kind, payload: new Uint8Array(_memoryBuffer(), bufPtr, n).slice() };
+    } finally {
+      _free(bufPtr);
+      _free(kindPtr);
+    }
+  }
+}
+
+// Delivers every pending event to handler and returns how many were delivered.
+function _drainEvents(handler) {
+  let count = 0;
+  for (let event = _pollEvent(); event !== null; event = _pollEvent()) {
+    handler(event);
+    count++;
+  }
+  return count;
+}
+
+// Drains events once per animation frame (every 16 ms outside the browser).
+// Returns an unsubscribe function.
+function _subscribeEvents(handler) {
+  let active = true;
+  const schedule = typeof requestAnimationFrame === 'function'
+    ? (fn) => requestAnimationFrame(fn)
+    : (fn) => setTimeout(fn, 16);
+  const tick = () => {
+    if (!active) return;
+    _drainEvents(handler);
+    schedule(tick);
+  };
+  schedule(tick);
+  return () => { active = false; };
+}
+
+`, EventPollFunctionName(apiName))
+}
+
+// writeModuleExports writes the default export and named exports, including
+// the extra top-level names given.
+func writeModuleExports(b *strings.Builder, apiName string, api *model.APIDefinition, extra, subModules []string) {
 	loaderName := ToCamelCase("load_" + apiName)
 
 	// Export handle classes
@@ -633,6 +1265,92 @@ func writeModuleExports(b *strings.Builder, apiName string, api *model.APIDefini
 	for _, h := range api.Handles {
 		fmt.Fprintf(b, "export { %s };\n", h.Name)
 	}
+	if len(api.Events) > 0 {
+		b.WriteString("export { EventKind };\n")
+	}
+	for _, name := range extra {
+		fmt.Fprintf(b, "export { %s };\n", name)
+	}
+	for _, m := range subModules {
+		fmt.Fprintf(b, "export * as %s from './%s';\n", m, jsSubModuleFileName(apiName, m))
+	}
+}
+
+// writeFieldDefaultFactories writes a factory for each FlatBuffer struct the
+// API passes or returns, building the plain object form the wrappers return
+// with every field set to its zero value. Tables are built with flatc
+// instead. It returns the factory names.
+func writeFieldDefaultFactories(b *strings.Builder, api *model.APIDefinition, resolved resolver.ResolvedTypes, keep func(string) bool) []string {
+	var names []string
+	for _, t := range paramFlatBufferTypes(api, resolved) {
+		if !keep(t) {
+			continue
+		}
+		name := jsFieldDefaultFactoryName(t)
+		var fields []string
+		for _, f := range resolved[t].ActiveFields() {
+			fields = append(fields, fmt.Sprintf("%s: %s", ToCamelCase(f.Name), jsFieldDefault(resolved, t, f)))
+		}
+		fields = append(fields, "...fields")
+		if len(names) == 0 {
+			b.WriteString("// FlatBuffer object factories\n")
+		}
+		fmt.Fprintf(b, "/** Returns a %s with schema defaults, overridden by fields. */\n", t)
+		fmt.Fprintf(b, "function %s(fields = {}) {\n  return { %s };\n}\n\n", name, strings.Join(fields, ", "))
+		names = append(names, name)
+	}
+	return names
+}
+
+// jsFieldDefaultFactoryName returns the name of the object factory of a
+// FlatBuffer type, e.g. "makeCommonTouchEvent" for "Common.TouchEvent".
+func jsFieldDefaultFactoryName(typeName string) string {
+	return "make" + strings.ReplaceAll(typeName, ".", "")
+}
+
+// jsFieldDefault returns the JS literal of a field's schema default, or of
+// its zero value when it has none. 64-bit integers are BigInts, as the
+// wrappers read them.
+func jsFieldDefault(resolved resolver.ResolvedTypes, typeName string, f resolver.FieldDef) string {
+	if _, ok := fieldEnumType(resolved, typeName, f); ok {
+		if f.Default == "" {
+			return "0"
+		}
+		return f.Default
+	}
+	switch f.Type {
+	case "string":
+		return "''"
+	case "bool":
+		if f.Default == "" {
+			return "false"
+		}
+		return f.Default
+	case "int64", "uint64":
+		if f.Default == "" {
+			return "0n"
+		}
+		return f.Default + "n"
+	case "float32", "float64":
+		switch f.Default {
+		case "nan":
+			return "NaN"
+		case "inf":
+			return "Infinity"
+		case "-inf":
+			return "-Infinity"
+		}
+	}
+	if resolver.IsScalarField(f.Type) {
+		if f.Default == "" {
+			return "0"
+		}
+		return f.Default
+	}
+	if elem, n, ok := resolver.ArrayField(f.Type); ok {
+		return fmt.Sprintf("new %s(%d)", jsTypedArrayName(elem), n)
+	}
+	return "null"
 }
 
 // wasmOutParamSize returns the byte size needed for an out-parameter of the given type.
@@ -640,6 +1358,12 @@ func wasmOutParamSize(retType string, resolved resolver.ResolvedTypes) int {
 	if _, ok := model.IsHandle(retType); ok {
 		return 4 // handle is a 32-bit pointer
 	}
+	if model.IsString(retType) {
+		return 4 // char* on wasm32
+	}
+	if _, ok := model.IsBuffer(retType); ok || isTableType(resolved, retType) {
+		return 8 // data pointer + uint32 element count or size on wasm32
+	}
 	switch retType {
 	case "int8", "uint8", "bool":
 		return 1
@@ -656,6 +1380,32 @@ func wasmOutParamSize(retType string, resolved resolver.ResolvedTypes) int {
 	}
 }
 
+// jsTypedArrayName returns the typed array constructor for a buffer element type.
+func jsTypedArrayName(elemType string) string {
+	switch elemType {
+	case "int8":
+		return "Int8Array"
+	case "int16":
+		return "Int16Array"
+	case "uint16":
+		return "Uint16Array"
+	case "int32":
+		return "Int32Array"
+	case "uint32":
+		return "Uint32Array"
+	case "int64":
+		return "BigInt64Array"
+	case "uint64":
+		return "BigUint64Array"
+	case "float32":
+		return "Float32Array"
+	case "float64":
+		return "Float64Array"
+	default:
+		return "Uint8Array"
+	}
+}
+
 // wasmDataViewGetter returns the DataView getter method for a primitive type.
 func wasmDataViewGetter(t string) string {
 	switch t {
@@ -696,6 +1446,10 @@ type wasmFieldInfo struct {
 
 // wasmFieldSize returns the byte size and alignment for a field type on WASM32.
 func wasmFieldSize(fieldType string) (size, align int) {
+	if elem, n, ok := resolver.ArrayField(fieldType); ok {
+		size, align = wasmFieldSize(elem)
+		return size * n, align
+	}
 	switch fieldType {
 	case "string":
 		return 4, 4 // const char* on wasm32
@@ -720,10 +1474,14 @@ func wasmStructLayout(typeName string, resolved resolver.ResolvedTypes) (totalSi
 	if !ok {
 		return 4, nil // unresolved — fallback to pointer size
 	}
+	if typeInfo.Kind == resolver.TypeKindUnion {
+		totalSize, _, _ = wasmUnionLayout(typeInfo, resolved)
+		return totalSize, nil
+	}
 
 	offset := 0
 	maxAlign := 1
-	for _, f := range typeInfo.Fields {
+	for _, f := range typeInfo.ActiveFields() {
 		size, align := wasmFieldSize(f.Type)
 		if align > maxAlign {
 			maxAlign = align
@@ -754,6 +1512,10 @@ func wasmStructLayout(typeName string, resolved resolver.ResolvedTypes) (totalSi
 // writeJSFBSObjectReturn emits JS code to read struct fields from _outPtr
 // and return a plain JS object.
 func writeJSFBSObjectReturn(b *strings.Builder, indent, retType string, resolved resolver.ResolvedTypes) {
+	if isUnionType(resolved, retType) {
+		writeJSUnionReturn(b, indent, retType, resolved)
+		return
+	}
 	_, fields := wasmStructLayout(retType, resolved)
 	if len(fields) == 0 {
 		// Unresolved type — fallback to raw pointer
@@ -763,25 +1525,35 @@ func writeJSFBSObjectReturn(b *strings.Builder, indent, retType string, resolved
 	}
 
 	fmt.Fprintf(b, "%sconst _view = new DataView(_memoryBuffer());\n", indent)
+	fmt.Fprintf(b, "%sreturn %s;\n", indent, jsStructObjectExpr(fields, 0))
+}
+
+// jsStructObjectExpr returns a JS object literal reading fields of a struct
+// that starts base bytes past _outPtr from _view.
+func jsStructObjectExpr(fields []wasmFieldInfo, base int) string {
 	var fieldExprs []string
 	for _, f := range fields {
 		jsFieldName := ToCamelCase(f.Name)
+		offset := base + f.Offset
 		var expr string
 		switch f.Type {
 		case "string":
-			expr = fmt.Sprintf("_decodeString(_view.getUint32(_outPtr + %d, true))", f.Offset)
+			expr = fmt.Sprintf("_takeString(_view.getUint32(_outPtr + %d, true))", offset)
 		case "bool":
-			expr = fmt.Sprintf("_view.getUint8(_outPtr + %d) !== 0", f.Offset)
+			expr = fmt.Sprintf("_view.getUint8(_outPtr + %d) !== 0", offset)
 		case "int64":
-			expr = fmt.Sprintf("_view.getBigInt64(_outPtr + %d, true)", f.Offset)
+			expr = fmt.Sprintf("_view.getBigInt64(_outPtr + %d, true)", offset)
 		case "uint64":
-			expr = fmt.Sprintf("_view.getBigUint64(_outPtr + %d, true)", f.Offset)
+			expr = fmt.Sprintf("_view.getBigUint64(_outPtr + %d, true)", offset)
 		default:
+			if _, _, ok := resolver.ArrayField(f.Type); ok {
+				expr = jsArrayFieldExpr(f.Type, offset)
+				break
+			}
 			getter := wasmDataViewGetter(f.Type)
-			expr = fmt.Sprintf("_view.%s(_outPtr + %d, true)", getter, f.Offset)
+			expr = fmt.Sprintf("_view.%s(_outPtr + %d, true)", getter, offset)
 		}
 		fieldExprs = append(fieldExprs, fmt.Sprintf("%s: %s", jsFieldName, expr))
 	}
-
-	fmt.Fprintf(b, "%sreturn { %s };\n", indent, strings.Join(fieldExprs, ", "))
+	return "{ " + strings.Join(fieldExprs, ", ") + " }"
 }
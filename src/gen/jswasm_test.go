@@ -435,3 +435,376 @@ func TestJSWASMGenerator_GeneratedHeader(t *testing.T) {
 		t.Error("missing do-not-edit warning")
 	}
 }
+
+func TestJSWASMGenerator_Events(t *testing.T) {
+	ctx := loadTestAPI(t, "events.yaml")
+	gen := &JSWASMGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"const EventKind = Object.freeze({\n  TOUCH: 1,\n  ENTITY_SPAWNED: 2,\n});",
+		"const n = _wasm.exports.event_api_event_poll(kindPtr, bufPtr, size);",
+		"function _drainEvents(handler) {",
+		"function _subscribeEvents(handler) {",
+		"    pollEvent: _pollEvent,\n    drainEvents: _drainEvents,\n    subscribeEvents: _subscribeEvents,\n",
+		"export { EventKind };",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("JS module missing %q", want)
+		}
+	}
+}
+
+func TestJSWASMGenerator_Async(t *testing.T) {
+	ctx := loadTestAPI(t, "async.yaml")
+	gen := &JSWASMGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"function _awaitOperation(op, options, poll, cancel, read) {",
+		"signal.addEventListener('abort', onAbort, { once: true });",
+		"loadModel(engine, path, options) {",
+		"_op = _wasm.exports.async_api_assets_load_model_start(engine._ptr, _pathPtr);",
+		"(_op) => _wasm.exports.async_api_assets_load_model_poll(_op, _errPtr, _outPtr, _outPtr + 4),",
+		"(_op) => _wasm.exports.async_api_assets_load_model_cancel(_op),",
+		"throw new Error(`loadModel failed with error code ${_rc}`);",
+		"return _promise.finally(() => {",
+		"return new Engine(_handleVal);",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("JS module missing %q", want)
+		}
+	}
+
+	ctx = loadTestAPI(t, "minimal.yaml")
+	files, err = gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if strings.Contains(string(files[0].Content), "_awaitOperation") {
+		t.Error("async helper should only be emitted with async methods")
+	}
+}
+
+func TestJSWASMGenerator_StringReturns(t *testing.T) {
+	ctx := loadTestAPI(t, "strings.yaml")
+	gen := &JSWASMGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"function _takeString(ptr) {",
+		"_wasm.exports.strings_api_string_free(ptr);",
+		"const _result = _wasm.exports.strings_api_text_get_name(engine._ptr);\n      return _takeString(_result);",
+		"return _takeString(new DataView(_memoryBuffer()).getUint32(_outPtr, true));",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("JS module missing %q", want)
+		}
+	}
+
+	ctx = loadTestAPI(t, "minimal.yaml")
+	files, err = gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if strings.Contains(string(files[0].Content), "_takeString") {
+		t.Error("string helper should only be emitted when strings are returned")
+	}
+}
+
+func TestJSWASMGenerator_BufferReturns(t *testing.T) {
+	ctx := loadTestAPI(t, "buffers.yaml")
+	gen := &JSWASMGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"function _takeBuffer(ptr, length, TypedArrayCtor) {",
+		"_wasm.exports.buffers_api_buffer_free(ptr);",
+		"_wasm.exports.buffers_api_media_read_tensor(engine._ptr, _outPtr, _outPtr + 4);",
+		"return _takeBuffer(_view.getUint32(_outPtr, true), _view.getUint32(_outPtr + 4, true), Float32Array);",
+		"return _takeBuffer(_view.getUint32(_outPtr, true), _view.getUint32(_outPtr + 4, true), BigInt64Array);",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("JS module missing %q", want)
+		}
+	}
+
+	ctx = loadTestAPI(t, "minimal.yaml")
+	files, err = gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if strings.Contains(string(files[0].Content), "_takeBuffer") {
+		t.Error("buffer helper should only be emitted when buffers are returned")
+	}
+}
+
+func TestJSTypedArrayName(t *testing.T) {
+	tests := map[string]string{
+		"uint8":   "Uint8Array",
+		"int8":    "Int8Array",
+		"uint16":  "Uint16Array",
+		"int32":   "Int32Array",
+		"uint64":  "BigUint64Array",
+		"float64": "Float64Array",
+	}
+	for elem, want := range tests {
+		if got := jsTypedArrayName(elem); got != want {
+			t.Errorf("jsTypedArrayName(%q) = %q, want %q", elem, got, want)
+		}
+	}
+}
+
+func TestJSWASMGenerator_Optionals(t *testing.T) {
+	ctx := loadTestAPI(t, "optional.yaml")
+	gen := &JSWASMGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"const _labelPtr = label == null ? 0 : _encodeString(label);",
+		"optional_api_scene_set_limit(engine._ptr, limit == null ? 0 : 1, limit ?? 0);",
+		"parent == null ? 0 : parent._ptr",
+		"if (_result === 0) {\n        return undefined;\n      }\n      return _takeString(_result);",
+		"if (_resultPtr === 0) {\n          return undefined;\n        }\n        return new Engine(_resultPtr);",
+		"const _hasPtr = _malloc(1);",
+		"optional_api_scene_get_limit(engine._ptr, _outPtr, _hasPtr);",
+		"if (new DataView(_memoryBuffer()).getUint8(_hasPtr) === 0) {",
+		"_free(_hasPtr);",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("JS module missing %q", want)
+		}
+	}
+}
+
+func TestJSWASMGenerator_Extends(t *testing.T) {
+	ctx := loadTestAPI(t, "extends.yaml")
+	gen := &JSWASMGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"  get _disposed() {\n    return this.#ptr === 0;\n  }",
+		"class Texture extends Node {\n  dispose() {\n    if (!this._disposed) {\n      _wasm.exports.extends_api_texture_destroy_texture(this._ptr);\n      super.dispose();",
+		"class Mesh extends Node {}",
+		"function _createTexture() {\n  return {\n    ..._createNode(),\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("JS module missing %q", want)
+		}
+	}
+	if strings.Index(content, "class Node {") > strings.Index(content, "class Texture extends Node") {
+		t.Error("base handle class must be declared before its subclasses")
+	}
+}
+
+func TestJSWASMGenerator_Lifecycle(t *testing.T) {
+	ctx := loadTestAPI(t, "lifecycle.yaml")
+	gen := &JSWASMGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"/**\n * @since 1.0.0\n * @deprecated Sprites are drawn as textured quads now. Use Quad instead.\n */\nclass Sprite {",
+		"/**\n * @experimental May change or be removed without a deprecation period.\n */\nclass Probe {",
+		"    /**\n     * @since 1.0.0\n     * @deprecated Quads replace sprites. Use drawQuad instead.\n     */\n    drawSprite(engine, x) {",
+		"    /**\n     * @deprecated Pass options explicitly. Use createEngine instead.\n     */\n    createEngineLegacy() {",
+		"     * @deprecated The legacy \"immediate\" path is retired\n     */\n    flush(engine) {",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("JS file missing %q", want)
+		}
+	}
+	if strings.Contains(content, "/**\n */") || strings.Contains(content, "/**\n     */") {
+		t.Error("elements without lifecycle annotations should not get an empty JSDoc block")
+	}
+}
+
+func TestJSWASMGenerator_ThreadAffinity(t *testing.T) {
+	ctx := loadTestAPI(t, "threading.yaml")
+	gen := &JSWASMGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"    /**\n     * Must be called on the main thread.\n     */\n    setTitle(window, title) {",
+		"    /**\n     * May be called from any thread.\n     */\n    contentScale(window) {",
+		"    /**\n     * Must be called on the thread that created the Session.\n     */\n    destroySession(session) {",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("JS file missing %q", want)
+		}
+	}
+}
+
+func TestJSWASMGenerator_Ownership(t *testing.T) {
+	ctx := loadTestAPI(t, "ownership.yaml")
+	gen := &JSWASMGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"  constructor(ptr, owned = true) {\n    this.#ptr = ptr;\n    this.#owned = owned;\n  }",
+		"  _take() {\n    if (!this.#owned) {\n      throw new Error('Material is borrowed and cannot be moved');\n    }",
+		"  get _owned() {\n    return this.#owned;\n  }",
+		"    if (!this._disposed) {\n      if (this._owned) {\n        _wasm.exports.ownership_api_texture_destroy_texture(this._ptr);\n      }\n      super.dispose();",
+		"_wasm.exports.ownership_api_scene_attach(scene._ptr, node._take());",
+		"      return new Node(_result, false);",
+		"return new Texture(_resultPtr, false);",
+		"_wasm.exports.ownership_api_scene_preload_start(scene._ptr, texture._take());",
+		"material == null ? 0 : material._take()",
+		"     * Takes ownership of texture, which cannot be used afterwards.\n     */\n    consume(texture, scene) {",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("JS file missing %q", want)
+		}
+	}
+}
+
+func TestJSWASMGenerator_Tables(t *testing.T) {
+	ctx := loadTestAPI(t, "fields.yaml")
+	gen := &JSWASMGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"      const [_configPtr, _configLen] = _copyBufferToWasm(config);\n",
+		"_wasm.exports.fields_api_stream_reconfigure(stream._ptr, _configPtr, _configLen);",
+		"_wasm.exports.fields_api_stream_info(stream._ptr, _outPtr, _outPtr + 4);\n        const _view = new DataView(_memoryBuffer());\n        return _takeBuffer(_view.getUint32(_outPtr, true), _view.getUint32(_outPtr + 4, true), Uint8Array);\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("JS output missing %q", want)
+		}
+	}
+	if strings.Contains(content, "makeFieldsStream") {
+		t.Error("tables are built with flatc, not with object factories")
+	}
+
+	ctx = loadTestAPI(t, "optional.yaml")
+	files, err = gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content = string(files[0].Content)
+	for _, want := range []string{
+		"const [_configPtr, _configLen] = config == null ? [0, 0] : _copyBufferToWasm(config);",
+		"if (_resultPtr === 0) {\n          return undefined;\n        }\n        return _takeBuffer(_resultPtr, new DataView(_memoryBuffer()).getUint32(_outPtr + 4, true), Uint8Array);\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("JS output missing %q", want)
+		}
+	}
+}
+
+func TestJSWASMGenerator_BitFlags(t *testing.T) {
+	ctx := loadTestAPI(t, "flags.yaml")
+	gen := &JSWASMGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"const MediaCaps = Object.freeze({\n  READ: 1,\n  WRITE: 2,\n  HAS_AUDIO: 16,\n  HDR: 32,\n});\n",
+		"const MediaChannels = Object.freeze({\n  LEFT: 1n,\n  RIGHT: 2n,\n  SURROUND: 1099511627776n,\n});\n",
+		"export { MediaCaps };\nexport { MediaChannels };\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("JS output missing %q", want)
+		}
+	}
+}
+
+func TestJSWASMGenerator_Unions(t *testing.T) {
+	ctx := loadTestAPI(t, "unions.yaml")
+	gen := &JSWASMGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"const _outPtr = _malloc(20);",
+		"switch (_view.getInt32(_outPtr, true)) {\n",
+		"case 1:\n            return { type: 'Circle', value: _takeBuffer(_view.getUint32(_outPtr + 12, true), _view.getUint32(_outPtr + 16, true), Uint8Array) };\n",
+		"return { type: 'Rect', value: { width: _view.getFloat32(_outPtr + 4, true), height: _view.getFloat32(_outPtr + 8, true) } };",
+		"default:\n            return undefined;\n",
+		" * @typedef {{type: 'Circle', value: Uint8Array} | {type: 'Rect', value: Object} | {type: 'Caption', value: Uint8Array}} SceneShape\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("JS output missing %q", want)
+		}
+	}
+}
+
+func TestJSWASMGenerator_FixedArrays(t *testing.T) {
+	ctx := loadTestAPI(t, "arrays.yaml")
+	gen := &JSWASMGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"const _outPtr = _malloc(64);",
+		"return { m: new Float32Array(_memoryBuffer().slice(_outPtr + 0, _outPtr + 64)) };",
+		"const _outPtr = _malloc(56);",
+		"min: new Float64Array(_memoryBuffer().slice(_outPtr + 8, _outPtr + 32)), max: new Float64Array(_memoryBuffer().slice(_outPtr + 32, _outPtr + 56))",
+		"return { m: new Float32Array(16), ...fields };",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("JS output missing %q", want)
+		}
+	}
+}
@@ -21,43 +21,110 @@ func (g *KotlinGenerator) Generate(ctx *Context) ([]*OutputFile, error) {
 	api := ctx.API
 	apiName := api.API.Name
 	pascalName := ToPascalCase(apiName)
-	packageName := strings.ReplaceAll(apiName, "_", ".")
+	pkgs := kotlinPackages{binding: strings.ReplaceAll(apiName, "_", "."), api: api}
 
 	ktHeader := GeneratedFileHeader(ctx, "//", false)
 	jniHeader := GeneratedFileHeaderBlock(ctx, false)
 
-	ktContent, err := generateKotlinFile(api, ctx.ResolvedTypes, pascalName, packageName)
+	// Types of a namespace mapped to its own package go to a file of their
+	// own, which the binding file imports.
+	var nsFiles []*OutputFile
+	var nsPackages []string
+	for _, ns := range mappedNamespaces(api, kotlinTarget) {
+		var types strings.Builder
+		writeKotlinTypes(&types, api, ctx.ResolvedTypes, inTarget(api, kotlinTarget, ns.Kotlin))
+		if types.Len() == 0 {
+			continue
+		}
+		nsFiles = append(nsFiles, &OutputFile{
+			Path:    strings.ReplaceAll(ns.Kotlin, ".", "/") + "/" + namespaceFileName(ns.Name) + ".kt",
+			Content: []byte(types.String()),
+		})
+		nsPackages = append(nsPackages, ns.Kotlin)
+	}
+	for i, f := range nsFiles {
+		var b strings.Builder
+		fmt.Fprintf(&b, "package %s\n\n", nsPackages[i])
+		fmt.Fprintf(&b, "import %s.*\n", pkgs.binding)
+		for _, pkg := range nsPackages {
+			if pkg != nsPackages[i] {
+				fmt.Fprintf(&b, "import %s.*\n", pkg)
+			}
+		}
+		f.Content = []byte(ktHeader + "\n" + b.String() + "\n" + string(f.Content))
+	}
+
+	ktContent, err := generateKotlinFile(api, ctx.ResolvedTypes, pascalName, pkgs.binding, nsPackages, ABIInfo(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("generating Kotlin file: %w", err)
 	}
 
-	jniContent, err := generateJNIFile(api, ctx.ResolvedTypes, pascalName, packageName)
+	jniContent, err := generateJNIFile(api, ctx.ResolvedTypes, pascalName, pkgs)
 	if err != nil {
 		return nil, fmt.Errorf("generating JNI C bridge: %w", err)
 	}
 
-	return []*OutputFile{
+	return append([]*OutputFile{
 		{Path: pascalName + ".kt", Content: []byte(ktHeader + "\n" + ktContent)},
 		{Path: apiName + "_jni.c", Content: []byte(jniHeader + "\n" + jniContent)},
-	}, nil
+	}, nsFiles...), nil
 }
 
 // ---------- Kotlin file generation ----------
 
-func generateKotlinFile(api *model.APIDefinition, resolved resolver.ResolvedTypes, pascalName, packageName string) (string, error) {
+func generateKotlinFile(api *model.APIDefinition, resolved resolver.ResolvedTypes, pascalName, packageName string, nsPackages []string, abiInfo string) (string, error) {
 	var b strings.Builder
 
+	// The wrappers themselves use deprecated and experimental declarations
+	// (e.g. a factory returning an experimental handle); consumers still see
+	// the warnings and opt-in requirements.
+	var fileAnnotations []string
+	if hasDeprecations(api) || hasDeprecatedHandles(api) {
+		fileAnnotations = append(fileAnnotations, "@file:Suppress(\"DEPRECATION\")")
+	}
+	if hasExperimental(api) {
+		fileAnnotations = append(fileAnnotations, fmt.Sprintf("@file:OptIn(%s::class)", kotlinExperimentalAnnotationName(pascalName)))
+	}
+	if len(fileAnnotations) > 0 {
+		fmt.Fprintf(&b, "%s\n\n", strings.Join(fileAnnotations, "\n"))
+	}
+
 	// Package and imports
 	fmt.Fprintf(&b, "package %s\n\n", packageName)
+	var imports []string
+	if len(api.Events) > 0 {
+		imports = append(imports, "android.os.Looper", "android.os.MessageQueue", "android.os.ParcelFileDescriptor")
+	}
+	if usesThreadAffinity(api, model.ThreadAffinityAny) {
+		imports = append(imports, "androidx.annotation.AnyThread")
+	}
+	if usesThreadAffinity(api, model.ThreadAffinityMain) {
+		imports = append(imports, "androidx.annotation.MainThread")
+	}
+	if hasAsyncMethods(api) {
+		imports = append(imports, "kotlinx.coroutines.CancellationException", "kotlinx.coroutines.delay")
+	}
+	for _, pkg := range nsPackages {
+		imports = append(imports, pkg+".*")
+	}
+	if len(imports) > 0 {
+		for _, imp := range imports {
+			fmt.Fprintf(&b, "import %s\n", imp)
+		}
+		b.WriteString("\n")
+	}
 
-	// Error exception class — collect all unique error types
-	errorTypes := CollectErrorTypes(api)
-	for _, errType := range errorTypes {
-		writeKotlinException(&b, errType)
+	// Opt-in marker for experimental API
+	if hasExperimental(api) {
+		writeKotlinExperimentalAnnotation(&b, pascalName)
 	}
 
-	// Data classes for FlatBuffer return types
-	writeKotlinFBSDataClasses(&b, resolved, api)
+	writeKotlinTypes(&b, api, resolved, inTarget(api, kotlinTarget, ""))
+
+	// Event kinds and value class
+	if len(api.Events) > 0 {
+		writeKotlinEventTypes(&b, api, pascalName)
+	}
 
 	// Handle wrapper classes
 	for _, h := range api.Handles {
@@ -65,11 +132,32 @@ func generateKotlinFile(api *model.APIDefinition, resolved resolver.ResolvedType
 	}
 
 	// Singleton object for the native library and methods without handles
-	writeKotlinNativeObject(&b, api, pascalName)
+	writeKotlinNativeObject(&b, api, pascalName, abiInfo)
 
 	return b.String(), nil
 }
 
+// writeKotlinTypes writes the exception classes, flag sets and data classes
+// generated for the FlatBuffer types that keep selects.
+func writeKotlinTypes(b *strings.Builder, api *model.APIDefinition, resolved resolver.ResolvedTypes, keep func(string) bool) {
+	// Error exception class — collect all unique error types
+	for _, errType := range CollectErrorTypes(api) {
+		if keep(errType) {
+			writeKotlinException(b, errType)
+		}
+	}
+
+	// Flag sets for bit_flags enums
+	for _, name := range bitFlagsEnums(resolved) {
+		if keep(name) {
+			writeKotlinBitFlags(b, name, resolved[name])
+		}
+	}
+
+	// Data classes for FlatBuffer return types
+	writeKotlinFBSDataClasses(b, resolved, api, keep)
+}
+
 // writeKotlinException writes a Kotlin exception class for a FlatBuffer error enum.
 func writeKotlinException(b *strings.Builder, errType string) {
 	className := kotlinErrorExceptionName(errType)
@@ -86,10 +174,35 @@ func kotlinErrorExceptionName(errType string) string {
 func writeKotlinHandleClass(b *strings.Builder, h model.HandleDef, api *model.APIDefinition, pascalName string) {
 	className := h.Name
 
-	if h.Description != "" {
-		fmt.Fprintf(b, "/**\n * %s\n */\n", h.Description)
+	if h.Description != "" || h.Since != "" {
+		b.WriteString("/**\n")
+		if h.Description != "" {
+			fmt.Fprintf(b, " * %s\n", h.Description)
+		}
+		if h.Since != "" {
+			fmt.Fprintf(b, " * @since %s\n", h.Since)
+		}
+		b.WriteString(" */\n")
+	}
+	writeKotlinLifecycleAnnotations(b, "", &h.Lifecycle, pascalName, sameName)
+	modifier := ""
+	if isBaseHandle(api, h.Name) {
+		modifier = "open "
+	}
+	tracked := tracksOwnership(api, h.Name)
+	parent, hasParent := api.ParentHandleName(h.Name)
+	switch {
+	case hasParent && tracked:
+		fmt.Fprintf(b, "%sclass %s internal constructor(handle: Long, owned: Boolean = true) : %s(handle, owned) {\n", modifier, className, parent)
+	case hasParent:
+		// Base interface methods are inherited from the parent handle's class.
+		fmt.Fprintf(b, "%sclass %s internal constructor(handle: Long) : %s(handle) {\n", modifier, className, parent)
+	case tracked:
+		fmt.Fprintf(b, "%sclass %s internal constructor(handle: Long, private val owned: Boolean = true) : AutoCloseable {\n", modifier, className)
+		writeKotlinOwnershipMembers(b)
+	default:
+		fmt.Fprintf(b, "%sclass %s internal constructor(internal val handle: Long) : AutoCloseable {\n", modifier, className)
 	}
-	fmt.Fprintf(b, "class %s internal constructor(internal val handle: Long) : AutoCloseable {\n", className)
 
 	// Find methods that take this handle as the first parameter (instance methods)
 	for _, iface := range api.Interfaces {
@@ -102,16 +215,31 @@ func writeKotlinHandleClass(b *strings.Builder, h model.HandleDef, api *model.AP
 
 	// AutoCloseable close method — find the interface that constructs this handle
 	destructorIfaceName := ""
+	var destructor model.MethodDef
 	for i := range api.Interfaces {
 		if ifaceHandleName, ok := api.Interfaces[i].ConstructorHandleName(); ok && ifaceHandleName == h.Name {
 			destructorIfaceName = api.Interfaces[i].Name
+			destructor = threadAffinityDestructor(api, &api.Interfaces[i], h.Name)
 			break
 		}
 	}
 	if destructorIfaceName != "" {
 		destroyMethodName := DestructorMethodName(h.Name)
+		if destructor.ThreadAffinity == model.ThreadAffinityCreator {
+			writeKotlinKDoc(b, "    ", []string{threadAffinityNote(&destructor)})
+		}
+		writeKotlinThreadAnnotation(b, &destructor)
 		fmt.Fprintf(b, "    override fun close() {\n")
-		fmt.Fprintf(b, "        %s.%s(handle)\n", pascalName, jniNativeMethodName(destructorIfaceName, destroyMethodName))
+		if tracked {
+			b.WriteString("        val released = release()\n")
+			fmt.Fprintf(b, "        if (released != 0L) %s.%s(released)\n", pascalName, jniNativeMethodName(destructorIfaceName, destroyMethodName))
+		} else {
+			fmt.Fprintf(b, "        %s.%s(handle)\n", pascalName, jniNativeMethodName(destructorIfaceName, destroyMethodName))
+		}
+		fmt.Fprintf(b, "    }\n")
+	} else if tracked {
+		fmt.Fprintf(b, "    override fun close() {\n")
+		fmt.Fprintf(b, "        release()\n")
 		fmt.Fprintf(b, "    }\n")
 	} else {
 		fmt.Fprintf(b, "    override fun close() { }\n")
@@ -120,6 +248,31 @@ func writeKotlinHandleClass(b *strings.Builder, h model.HandleDef, api *model.AP
 	fmt.Fprintf(b, "}\n\n")
 }
 
+// writeKotlinOwnershipMembers writes the members of a handle family's root
+// class that track whether the wrapper still owns its handle. A moved or
+// closed wrapper has handle 0, and a borrowed one never destroys it.
+func writeKotlinOwnershipMembers(b *strings.Builder) {
+	b.WriteString(`    private var nativeHandle = handle
+
+    internal val handle: Long
+        get() = nativeHandle.also { check(it != 0L) { "${javaClass.simpleName} has been moved or closed" } }
+
+    /** Gives the handle to a call that takes ownership of it. */
+    internal fun take(): Long {
+        check(owned) { "${javaClass.simpleName} is borrowed and cannot be moved" }
+        return handle.also { nativeHandle = 0L }
+    }
+
+    /** Invalidates the wrapper, returning the handle to destroy or 0 if it owns none. */
+    internal fun release(): Long {
+        val released = nativeHandle
+        nativeHandle = 0L
+        return if (owned) released else 0L
+    }
+
+`)
+}
+
 // isInstanceMethod returns true if the method's first parameter is a handle of the given type.
 func isInstanceMethod(method model.MethodDef, handleName string) bool {
 	if len(method.Parameters) == 0 {
@@ -132,25 +285,29 @@ func isInstanceMethod(method model.MethodDef, handleName string) bool {
 
 // writeKotlinInstanceMethod writes a Kotlin method on a handle wrapper class.
 func writeKotlinInstanceMethod(b *strings.Builder, ifaceName string, method *model.MethodDef, pascalName string) {
-	methodName := ToCamelCase(method.Name)
+	writeKotlinMethodLifecycle(b, method, pascalName, true)
+	if method.Async {
+		writeKotlinAsyncMethod(b, ifaceName, method, pascalName+".", method.Parameters[1:], []string{kotlinReceiverArg(method)})
+		return
+	}
+	methodName := kotlinName(method.Name)
 	nativeName := jniNativeMethodName(ifaceName, method.Name)
 	hasReturn := method.Returns != nil
 
 	// Build Kotlin parameters (skip the first handle param — it's 'this')
 	var ktParams []string
 	var nativeCallArgs []string
-	nativeCallArgs = append(nativeCallArgs, "handle")
+	nativeCallArgs = append(nativeCallArgs, kotlinReceiverArg(method))
 
 	for _, p := range method.Parameters[1:] {
-		ktType := kotlinParamType(p.Type)
-		ktParams = append(ktParams, ToCamelCase(p.Name)+": "+ktType)
+		ktParams = append(ktParams, kotlinParamDecl(p))
 		nativeCallArgs = append(nativeCallArgs, kotlinParamToNativeArg(p))
 	}
 
 	// Determine Kotlin return type
 	ktReturnType := ""
 	if hasReturn {
-		ktReturnType = kotlinReturnType(method.Returns.Type)
+		ktReturnType = kotlinResultType(method.Returns)
 	}
 
 	// Method signature
@@ -167,18 +324,38 @@ func writeKotlinInstanceMethod(b *strings.Builder, ifaceName string, method *mod
 	fmt.Fprintf(b, "    }\n\n")
 }
 
+// kotlinReceiverArg returns the native argument for an instance method's
+// receiver, giving up the handle when the method takes ownership of it.
+func kotlinReceiverArg(method *model.MethodDef) string {
+	if isMoveParam(&method.Parameters[0]) {
+		return "take()"
+	}
+	return "handle"
+}
+
 // writeKotlinNativeObject writes the companion/singleton object containing native methods
 // and factory functions (constructors and non-instance methods).
-func writeKotlinNativeObject(b *strings.Builder, api *model.APIDefinition, pascalName string) {
+func writeKotlinNativeObject(b *strings.Builder, api *model.APIDefinition, pascalName, abiInfo string) {
 	fmt.Fprintf(b, "object %s {\n", pascalName)
-	fmt.Fprintf(b, "    init {\n")
-	fmt.Fprintf(b, "        System.loadLibrary(\"%s\")\n", api.API.Name)
-	fmt.Fprintf(b, "    }\n\n")
+	// The library is checked as it loads: a mismatch throws from the
+	// object's initializer, on first use of the bindings.
+	fmt.Fprintf(b, `    /** The API version and ABI hash these bindings were generated for. */
+    const val ABI_INFO = "%[2]s"
+
+    init {
+        System.loadLibrary("%[1]s")
+        val abiInfo = nativeAbiInfo()
+        if (abiInfo.substringAfterLast(' ') != ABI_INFO.substringAfterLast(' ')) {
+            throw UnsatisfiedLinkError("%[3]s")
+        }
+    }
+
+`, api.API.Name, abiInfo, abiMismatchMessage(api.API.Name, "$abiInfo", "$ABI_INFO"))
 
 	// Factory methods: explicit constructors from each interface
 	for _, iface := range api.Interfaces {
 		for i := range iface.Constructors {
-			writeKotlinFactoryMethod(b, iface.Name, &iface.Constructors[i])
+			writeKotlinFactoryMethod(b, iface.Name, &iface.Constructors[i], pascalName)
 		}
 	}
 
@@ -186,11 +363,21 @@ func writeKotlinNativeObject(b *strings.Builder, api *model.APIDefinition, pasca
 	for _, iface := range api.Interfaces {
 		for i := range iface.Methods {
 			if !isAnyInstanceMethod(iface.Methods[i], api) {
-				writeKotlinFactoryMethod(b, iface.Name, &iface.Methods[i])
+				writeKotlinFactoryMethod(b, iface.Name, &iface.Methods[i], pascalName)
 			}
 		}
 	}
 
+	// Event polling and subscription
+	if len(api.Events) > 0 {
+		writeKotlinEventMethods(b, pascalName)
+	}
+
+	// Async operation polling
+	if hasAsyncMethods(api) {
+		writeKotlinAwaitOperation(b)
+	}
+
 	// JNI native method declarations: constructors, auto-destructor, then regular methods
 	for _, iface := range api.Interfaces {
 		for i := range iface.Constructors {
@@ -201,9 +388,18 @@ func writeKotlinNativeObject(b *strings.Builder, api *model.APIDefinition, pasca
 			writeKotlinNativeDecl(b, iface.Name, &destructor)
 		}
 		for i := range iface.Methods {
+			if iface.Methods[i].Async {
+				writeKotlinAsyncNativeDecls(b, iface.Name, &iface.Methods[i])
+				continue
+			}
 			writeKotlinNativeDecl(b, iface.Name, &iface.Methods[i])
 		}
 	}
+	b.WriteString("    external fun nativeAbiInfo(): String\n")
+	if len(api.Events) > 0 {
+		b.WriteString("    external fun nativeEventPoll(kind: IntArray, buffer: ByteArray): Int\n")
+		b.WriteString("    external fun nativeEventSignalFd(): Int\n")
+	}
 
 	fmt.Fprintf(b, "}\n")
 }
@@ -229,22 +425,26 @@ func isAnyInstanceMethod(method model.MethodDef, api *model.APIDefinition) bool
 }
 
 // writeKotlinFactoryMethod writes a top-level factory method (e.g., createEngine).
-func writeKotlinFactoryMethod(b *strings.Builder, ifaceName string, method *model.MethodDef) {
-	methodName := ToCamelCase(method.Name)
+func writeKotlinFactoryMethod(b *strings.Builder, ifaceName string, method *model.MethodDef, pascalName string) {
+	writeKotlinMethodLifecycle(b, method, pascalName, false)
+	if method.Async {
+		writeKotlinAsyncMethod(b, ifaceName, method, "", method.Parameters, nil)
+		return
+	}
+	methodName := kotlinName(method.Name)
 	nativeName := jniNativeMethodName(ifaceName, method.Name)
 	hasReturn := method.Returns != nil
 
 	var ktParams []string
 	var nativeCallArgs []string
 	for _, p := range method.Parameters {
-		ktType := kotlinParamType(p.Type)
-		ktParams = append(ktParams, ToCamelCase(p.Name)+": "+ktType)
+		ktParams = append(ktParams, kotlinParamDecl(p))
 		nativeCallArgs = append(nativeCallArgs, kotlinParamToNativeArg(p))
 	}
 
 	ktReturnType := ""
 	if hasReturn {
-		ktReturnType = kotlinReturnType(method.Returns.Type)
+		ktReturnType = kotlinResultType(method.Returns)
 	}
 
 	paramStr := strings.Join(ktParams, ", ")
@@ -260,6 +460,85 @@ func writeKotlinFactoryMethod(b *strings.Builder, ifaceName string, method *mode
 	fmt.Fprintf(b, "    }\n\n")
 }
 
+// kotlinExperimentalAnnotationName returns the opt-in marker for experimental API.
+// e.g., "HelloXplatter" → "ExperimentalHelloXplatterApi", "MediaApi" → "ExperimentalMediaApi"
+func kotlinExperimentalAnnotationName(pascalName string) string {
+	if strings.HasSuffix(pascalName, "Api") {
+		return "Experimental" + pascalName
+	}
+	return "Experimental" + pascalName + "Api"
+}
+
+// writeKotlinExperimentalAnnotation declares the opt-in marker that callers of
+// experimental API must acknowledge with @OptIn.
+func writeKotlinExperimentalAnnotation(b *strings.Builder, pascalName string) {
+	b.WriteString("@RequiresOptIn(message = \"This API is experimental and may change or be removed without a deprecation period.\")\n")
+	b.WriteString("@Retention(AnnotationRetention.BINARY)\n")
+	b.WriteString("@Target(AnnotationTarget.CLASS, AnnotationTarget.FUNCTION)\n")
+	fmt.Fprintf(b, "annotation class %s\n\n", kotlinExperimentalAnnotationName(pascalName))
+}
+
+// writeKotlinMethodLifecycle writes the KDoc and annotations for a wrapper
+// method, which is an instance method of its first parameter's class when
+// instance is set.
+func writeKotlinMethodLifecycle(b *strings.Builder, method *model.MethodDef, pascalName string, instance bool) {
+	var kdoc []string
+	if method.ThreadAffinity == model.ThreadAffinityCreator {
+		kdoc = append(kdoc, threadAffinityNote(method))
+	}
+	kdoc = append(kdoc, bindingOwnershipNotes(method, ToCamelCase, instance)...)
+	if method.Since != "" {
+		kdoc = append(kdoc, "@since "+method.Since)
+	}
+	writeKotlinKDoc(b, "    ", kdoc)
+	writeKotlinLifecycleAnnotations(b, "    ", &method.Lifecycle, pascalName, ToCamelCase)
+	writeKotlinThreadAnnotation(b, method)
+}
+
+// writeKotlinKDoc writes a KDoc comment of the given lines, on a single line
+// when there is only one.
+func writeKotlinKDoc(b *strings.Builder, indent string, lines []string) {
+	switch len(lines) {
+	case 0:
+	case 1:
+		fmt.Fprintf(b, "%s/** %s */\n", indent, lines[0])
+	default:
+		fmt.Fprintf(b, "%s/**\n", indent)
+		for _, line := range lines {
+			fmt.Fprintf(b, "%s * %s\n", indent, line)
+		}
+		fmt.Fprintf(b, "%s */\n", indent)
+	}
+}
+
+// writeKotlinThreadAnnotation writes the androidx thread annotation for a
+// wrapper method. Creator affinity has no annotation (@WorkerThread would
+// reject a handle created on the main thread); its KDoc says so instead.
+func writeKotlinThreadAnnotation(b *strings.Builder, method *model.MethodDef) {
+	switch method.ThreadAffinity {
+	case model.ThreadAffinityMain:
+		b.WriteString("    @MainThread\n")
+	case model.ThreadAffinityAny:
+		b.WriteString("    @AnyThread\n")
+	}
+}
+
+// writeKotlinLifecycleAnnotations writes @Deprecated and the experimental
+// opt-in marker for a declaration. name spells a replacement in Kotlin.
+func writeKotlinLifecycleAnnotations(b *strings.Builder, indent string, l *model.Lifecycle, pascalName string, name func(string) string) {
+	if l.Deprecated != nil {
+		fmt.Fprintf(b, "%s@Deprecated(%s)\n", indent, kotlinStringLiteral(deprecationText(l.Deprecated, name)))
+	}
+	if l.IsExperimental() {
+		fmt.Fprintf(b, "%s@%s\n", indent, kotlinExperimentalAnnotationName(pascalName))
+	}
+}
+
+// kotlinStringLiteral quotes s for Kotlin, escaping string templates.
+func kotlinStringLiteral(s string) string {
+	return strings.ReplaceAll(quoteLiteral(s), "$", "\\$")
+}
+
 // writeKotlinMethodBody emits the body of a Kotlin wrapper method given a pre-built
 // native call expression. The four-case dispatch (hasError × hasReturn) is identical
 // for instance methods and factory methods — only the call expression differs.
@@ -267,19 +546,32 @@ func writeKotlinMethodBody(b *strings.Builder, callExpr string, method *model.Me
 	hasError := method.Error != ""
 	hasReturn := method.Returns != nil
 
+	// A table result arrives as its FlatBuffer, with errors thrown from the
+	// JNI bridge
+	if returnsTable(method) {
+		if method.Returns.Optional {
+			fmt.Fprintf(b, "        return %s?.let { %s }\n", callExpr, kotlinTableRoot(method.Returns.Type, "it"))
+		} else {
+			fmt.Fprintf(b, "        return %s\n", kotlinTableRoot(method.Returns.Type, callExpr))
+		}
+		return
+	}
+
 	if hasError {
 		if hasReturn {
 			retType := method.Returns.Type
-			if model.IsFlatBufferType(retType) {
-				// FlatBuffer return: JNI throws exception, native returns data class directly
+			if kotlinResultIsObject(method) {
+				// FlatBuffer, string or optional return: JNI throws exception, native returns the object directly
 				fmt.Fprintf(b, "        return %s\n", callExpr)
 			} else {
 				// Handle or primitive: LongArray pattern [errorCode, result]
 				fmt.Fprintf(b, "        val result = %s\n", callExpr)
 				fmt.Fprintf(b, "        if (result[0] != 0L) throw %s(result[0].toInt())\n",
 					kotlinErrorExceptionName(method.Error))
-				if _, ok := model.IsHandle(retType); ok {
-					fmt.Fprintf(b, "        return %s(result[1])\n", kotlinHandleReturnType(retType))
+				if _, ok := model.IsHandle(retType); ok && method.Returns.Optional {
+					fmt.Fprintf(b, "        return if (result[1] == 0L) null else %s\n", kotlinHandleWrap(method.Returns, "result[1]"))
+				} else if ok {
+					fmt.Fprintf(b, "        return %s\n", kotlinHandleWrap(method.Returns, "result[1]"))
 				} else {
 					fmt.Fprintf(b, "        return result[1]\n")
 				}
@@ -293,8 +585,11 @@ func writeKotlinMethodBody(b *strings.Builder, callExpr string, method *model.Me
 	} else {
 		if hasReturn {
 			retType := method.Returns.Type
-			if _, ok := model.IsHandle(retType); ok {
-				fmt.Fprintf(b, "        return %s(%s)\n", kotlinHandleReturnType(retType), callExpr)
+			if _, ok := model.IsHandle(retType); ok && method.Returns.Optional {
+				fmt.Fprintf(b, "        val result = %s\n", callExpr)
+				fmt.Fprintf(b, "        return if (result == 0L) null else %s\n", kotlinHandleWrap(method.Returns, "result"))
+			} else if ok {
+				fmt.Fprintf(b, "        return %s\n", kotlinHandleWrap(method.Returns, callExpr))
 			} else {
 				fmt.Fprintf(b, "        return %s\n", callExpr)
 			}
@@ -318,15 +613,15 @@ func writeKotlinNativeDecl(b *strings.Builder, ifaceName string, method *model.M
 	var returnType string
 	switch {
 	case hasError && hasReturn:
-		if model.IsFlatBufferType(method.Returns.Type) {
-			returnType = kotlinFBSDataClassName(method.Returns.Type)
+		if kotlinResultIsObject(method) {
+			returnType = kotlinNativeResultType(method)
 		} else {
 			returnType = "LongArray"
 		}
 	case hasError && !hasReturn:
 		returnType = "Int"
 	case !hasError && hasReturn:
-		returnType = kotlinNativeReturnType(method.Returns.Type)
+		returnType = kotlinNativeResultType(method)
 	default:
 		returnType = "Unit"
 	}
@@ -335,17 +630,372 @@ func writeKotlinNativeDecl(b *strings.Builder, ifaceName string, method *model.M
 	fmt.Fprintf(b, "    external fun %s(%s): %s\n", nativeName, paramStr, returnType)
 }
 
+// ---------- Events ----------
+
+// kotlinEventKindName returns the Kotlin enum class name for event kinds.
+func kotlinEventKindName(pascalName string) string {
+	return pascalName + "EventKind"
+}
+
+// writeKotlinEventTypes writes the event kind enum and event value class.
+func writeKotlinEventTypes(b *strings.Builder, api *model.APIDefinition, pascalName string) {
+	kindName := kotlinEventKindName(pascalName)
+	fmt.Fprintf(b, "enum class %s(val value: Int) {\n", kindName)
+	for i, ev := range api.Events {
+		sep := ","
+		if i == len(api.Events)-1 {
+			sep = ";"
+		}
+		fmt.Fprintf(b, "    %s(%d)%s\n", UpperSnakeCase(ev.Name), EventKindValue(i), sep)
+	}
+	fmt.Fprintf(b, `
+    companion object {
+        fun fromValue(value: Int): %[1]s? = values().firstOrNull { it.value == value }
+    }
+}
+
+/**
+ * An event pushed by the implementation. The payload is the serialized
+ * FlatBuffer table for the kind.
+ */
+class %[2]sEvent(val kind: %[1]s, val payload: ByteArray)
+
+`, kindName, pascalName)
+}
+
+// writeKotlinEventMethods writes pollEvent/drainEvents/subscribeEvents into the native object.
+func writeKotlinEventMethods(b *strings.Builder, pascalName string) {
+	kindName := kotlinEventKindName(pascalName)
+	fmt.Fprintf(b, `    private var eventBuffer = ByteArray(256)
+
+    /** Pops the next pending event, or returns null when none are pending. */
+    @Synchronized
+    fun pollEvent(): %[1]sEvent? {
+        val kind = IntArray(1)
+        while (true) {
+            val size = nativeEventPoll(kind, eventBuffer)
+            if (size < 0) {
+                eventBuffer = ByteArray(-size)
+                continue
+            }
+            if (kind[0] == 0) return null
+            val eventKind = %[2]s.fromValue(kind[0]) ?: continue
+            return %[1]sEvent(eventKind, eventBuffer.copyOf(size))
+        }
+    }
+
+    /** Delivers every pending event to handler and returns how many were delivered. */
+    fun drainEvents(handler: (%[1]sEvent) -> Unit): Int {
+        var count = 0
+        while (true) {
+            val event = pollEvent() ?: return count
+            handler(event)
+            count++
+        }
+    }
+
+    /**
+     * Drains events on the looper's thread whenever the implementation signals.
+     * Close the returned subscription to stop. Returns null when the platform
+     * has no signal descriptor; call drainEvents periodically instead.
+     */
+    fun subscribeEvents(looper: Looper = Looper.getMainLooper(), handler: (%[1]sEvent) -> Unit): AutoCloseable? {
+        val fd = nativeEventSignalFd()
+        if (fd < 0) return null
+        val pfd = ParcelFileDescriptor.fromFd(fd)
+        val queue = looper.queue
+        queue.addOnFileDescriptorEventListener(
+            pfd.fileDescriptor,
+            MessageQueue.OnFileDescriptorEventListener.EVENT_INPUT,
+        ) { _, _ ->
+            drainEvents(handler)
+            MessageQueue.OnFileDescriptorEventListener.EVENT_INPUT
+        }
+        drainEvents(handler)
+        return AutoCloseable {
+            queue.removeOnFileDescriptorEventListener(pfd.fileDescriptor)
+            pfd.close()
+        }
+    }
+
+`, pascalName, kindName)
+}
+
+// writeJNIABIInfo writes the JNI bridge the native object's initializer
+// checks the library's ABI through.
+func writeJNIABIInfo(b *strings.Builder, apiName, jniClassPath string) {
+	fmt.Fprintf(b, `/* ABI handshake */
+JNIEXPORT jstring JNICALL
+Java_%s_nativeAbiInfo(JNIEnv *env, jobject thiz) {
+    return (*env)->NewStringUTF(env, %s());
+}
+
+`, jniClassPath, ABIInfoFunctionName(apiName))
+}
+
+// writeJNIEventFunctions writes the JNI bridges for event polling and the signal descriptor.
+func writeJNIEventFunctions(b *strings.Builder, apiName, jniClassPath string) {
+	fmt.Fprintf(b, `/* events */
+JNIEXPORT jint JNICALL
+Java_%[1]s_nativeEventPoll(JNIEnv *env, jobject thiz, jintArray kind, jbyteArray buffer) {
+    jsize size = (*env)->GetArrayLength(env, buffer);
+    jbyte* data = (*env)->GetByteArrayElements(env, buffer, NULL);
+    uint32_t out_kind = 0;
+    int32_t result = %[2]s(&out_kind, (uint8_t*)data, (uint32_t)size);
+    (*env)->ReleaseByteArrayElements(env, buffer, data, result > 0 ? 0 : JNI_ABORT);
+    jint k = (jint)out_kind;
+    (*env)->SetIntArrayRegion(env, kind, 0, 1, &k);
+    return (jint)result;
+}
+
+JNIEXPORT jint JNICALL
+Java_%[1]s_nativeEventSignalFd(JNIEnv *env, jobject thiz) {
+    return (jint)%[3]s();
+}
+
+`, jniClassPath, EventPollFunctionName(apiName), EventSignalFDFunctionName(apiName))
+}
+
+// ---------- Async ----------
+
+// writeKotlinAwaitOperation writes the suspend helper every async wrapper
+// method polls its operation through.
+func writeKotlinAwaitOperation(b *strings.Builder) {
+	fmt.Fprintf(b, `    /**
+     * Polls an async operation until it finishes, backing off from %[1]d ms to %[2]d ms.
+     * Returns the final poll result with status[1] holding the error code. Throws
+     * CancellationException when the implementation cancels, and cancels the
+     * operation when the calling coroutine is cancelled first.
+     */
+    internal suspend fun <T> awaitOperation(
+        op: Long,
+        status: IntArray,
+        poll: (Long, IntArray) -> T,
+        cancel: (Long) -> Unit,
+    ): T {
+        if (op == 0L) throw IllegalStateException("async operation could not start")
+        var finished = false
+        try {
+            var delayMs = %[1]dL
+            while (true) {
+                val result = poll(op, status)
+                when (status[0]) {
+                    %[3]d -> {
+                        delay(delayMs)
+                        delayMs = minOf(delayMs * 2, %[2]dL)
+                    }
+                    %[4]d -> {
+                        finished = true
+                        return result
+                    }
+                    else -> {
+                        finished = true
+                        throw CancellationException("async operation was cancelled")
+                    }
+                }
+            }
+        } finally {
+            if (!finished) cancel(op)
+        }
+    }
+
+`, AsyncPollMinDelayMS, AsyncPollMaxDelayMS, AsyncStatusPending, AsyncStatusDone)
+}
+
+// writeKotlinAsyncMethod writes a suspend wrapper for an async method. prefix
+// qualifies the native object ("" inside it); leadingArgs are native arguments
+// supplied by the receiver rather than by Kotlin parameters.
+func writeKotlinAsyncMethod(b *strings.Builder, ifaceName string, method *model.MethodDef, prefix string, params []model.ParameterDef, leadingArgs []string) {
+	methodName := kotlinName(method.Name)
+	nativeName := jniNativeMethodName(ifaceName, method.Name)
+
+	var ktParams []string
+	nativeCallArgs := append([]string{}, leadingArgs...)
+	for _, p := range params {
+		ktParams = append(ktParams, kotlinParamDecl(p))
+		nativeCallArgs = append(nativeCallArgs, kotlinParamToNativeArg(p))
+	}
+
+	paramStr := strings.Join(ktParams, ", ")
+	if method.Returns != nil {
+		fmt.Fprintf(b, "    suspend fun %s(%s): %s {\n", methodName, paramStr, kotlinResultType(method.Returns))
+	} else {
+		fmt.Fprintf(b, "    suspend fun %s(%s) {\n", methodName, paramStr)
+	}
+
+	b.WriteString("        val status = IntArray(2)\n")
+	call := fmt.Sprintf("%[1]sawaitOperation(%[1]s%[2]sStart(%[3]s), status, %[4]s::%[2]sPoll, %[4]s::%[2]sCancel)",
+		prefix, nativeName, strings.Join(nativeCallArgs, ", "), strings.TrimSuffix(prefix, "."))
+	if prefix == "" {
+		call = fmt.Sprintf("awaitOperation(%[1]sStart(%[2]s), status, ::%[1]sPoll, ::%[1]sCancel)",
+			nativeName, strings.Join(nativeCallArgs, ", "))
+	}
+	if method.Returns != nil {
+		fmt.Fprintf(b, "        val result = %s\n", call)
+	} else {
+		fmt.Fprintf(b, "        %s\n", call)
+	}
+	if method.Error != "" {
+		fmt.Fprintf(b, "        if (status[1] != 0) throw %s(status[1])\n", kotlinErrorExceptionName(method.Error))
+	}
+	if method.Returns != nil {
+		retType := method.Returns.Type
+		switch {
+		case method.Returns.Table:
+			fmt.Fprintf(b, "        return %s\n", kotlinTableRoot(retType, "result!!"))
+		case kotlinReturnsObject(retType):
+			b.WriteString("        return result!!\n")
+		case strings.HasPrefix(retType, "handle:"):
+			fmt.Fprintf(b, "        return %s(result)\n", kotlinHandleReturnType(retType))
+		default:
+			b.WriteString("        return result\n")
+		}
+	}
+	fmt.Fprintf(b, "    }\n\n")
+}
+
+// writeKotlinAsyncNativeDecls writes the start/poll/cancel native declarations
+// of an async method. Poll fills status with [status, error] and returns the
+// result, which is only meaningful once the status is done without error.
+func writeKotlinAsyncNativeDecls(b *strings.Builder, ifaceName string, method *model.MethodDef) {
+	nativeName := jniNativeMethodName(ifaceName, method.Name)
+
+	var params []string
+	for _, p := range method.Parameters {
+		params = append(params, kotlinNativeDeclParam(p))
+	}
+	fmt.Fprintf(b, "    external fun %sStart(%s): Long\n", nativeName, strings.Join(params, ", "))
+
+	pollReturn := "Unit"
+	if method.Returns != nil {
+		pollReturn = kotlinNativeReturnType(method.Returns.Type)
+		if method.Returns.Table {
+			pollReturn = "ByteArray"
+		}
+		if kotlinReturnsObject(method.Returns.Type) {
+			pollReturn += "?"
+		}
+	}
+	fmt.Fprintf(b, "    external fun %sPoll(op: Long, status: IntArray): %s\n", nativeName, pollReturn)
+	fmt.Fprintf(b, "    external fun %sCancel(op: Long)\n", nativeName)
+}
+
+// writeJNIAsyncFunctions writes the JNI bridges for an async method's
+// start/poll/cancel functions.
+func writeJNIAsyncFunctions(b *strings.Builder, apiName, ifaceName string, method *model.MethodDef, jniClassPath string, resolved resolver.ResolvedTypes, pkgs kotlinPackages) {
+	jniMethodName := jniNativeMethodName(ifaceName, method.Name)
+	opType := AsyncOpTypeName(apiName)
+
+	// start
+	jniParams := []string{"JNIEnv *env", "jobject thiz"}
+	for _, p := range method.Parameters {
+		jniParams = append(jniParams, jniParamDecl(&p)...)
+	}
+	fmt.Fprintf(b, "JNIEXPORT jlong JNICALL\n")
+	fmt.Fprintf(b, "Java_%s_%sStart(%s) {\n", jniClassPath, jniMethodName, strings.Join(jniParams, ", "))
+	var stringParams, tableParams []model.ParameterDef
+	for _, p := range method.Parameters {
+		if model.IsString(p.Type) {
+			stringParams = append(stringParams, p)
+			writeJNIGetString(b, &p)
+		}
+		if p.Table {
+			tableParams = append(tableParams, p)
+			writeJNIGetTable(b, &p)
+		}
+		if p.Struct {
+			writeJNIGetStruct(b, &p, resolved)
+		}
+	}
+	var callArgs []string
+	for _, p := range method.Parameters {
+		callArgs = append(callArgs, jniToCArg(&p)...)
+	}
+	fmt.Fprintf(b, "    %s op = %s(%s);\n", opType, AsyncStartFunctionName(apiName, ifaceName, method.Name), strings.Join(callArgs, ", "))
+	for _, sp := range stringParams {
+		writeJNIReleaseString(b, &sp)
+	}
+	for _, tp := range tableParams {
+		writeJNIReleaseTable(b, &tp)
+	}
+	b.WriteString("    return (jlong)op;\n")
+	b.WriteString("}\n\n")
+
+	// poll
+	fbReturn := isFlatBufferReturn(method)
+	strReturn := method.Returns != nil && model.IsString(method.Returns.Type)
+	tableReturn := returnsTable(method)
+	jniRetType := "void"
+	if method.Returns != nil {
+		switch {
+		case tableReturn:
+			jniRetType = "jbyteArray"
+		case fbReturn:
+			jniRetType = "jobject"
+		case strReturn:
+			jniRetType = "jstring"
+		default:
+			jniRetType = jniCReturnType(method.Returns.Type)
+		}
+	}
+	fmt.Fprintf(b, "JNIEXPORT %s JNICALL\n", jniRetType)
+	fmt.Fprintf(b, "Java_%s_%sPoll(JNIEnv *env, jobject thiz, jlong op, jintArray status) {\n", jniClassPath, jniMethodName)
+	pollArgs := []string{"(" + opType + ")op"}
+	b.WriteString("    int32_t out_error = 0;\n")
+	if method.Error != "" {
+		pollArgs = append(pollArgs, "&out_error")
+	}
+	if tableReturn {
+		b.WriteString("    uint8_t* out_result = NULL;\n")
+		b.WriteString("    uint32_t out_result_len = 0;\n")
+		pollArgs = append(pollArgs, "&out_result", "&out_result_len")
+	} else if method.Returns != nil {
+		fmt.Fprintf(b, "    %s out_result = {0};\n", CReturnType(method.Returns.Type))
+		pollArgs = append(pollArgs, "&out_result")
+	}
+	fmt.Fprintf(b, "    int32_t st = %s(%s);\n", AsyncPollFunctionName(apiName, ifaceName, method.Name), strings.Join(pollArgs, ", "))
+	b.WriteString("    jint values[2] = { (jint)st, (jint)out_error };\n")
+	b.WriteString("    (*env)->SetIntArrayRegion(env, status, 0, 2, values);\n")
+	if fbReturn || strReturn || tableReturn {
+		fmt.Fprintf(b, "    if (st != %s || out_error != 0) {\n", AsyncStatusConstName(apiName, "done"))
+		b.WriteString("        return NULL;\n")
+		b.WriteString("    }\n")
+		switch {
+		case strReturn:
+			b.WriteString("    return take_string(env, out_result);\n")
+		case tableReturn:
+			writeJNIBufferReturn(b, apiName, "uint8")
+		default:
+			writeJNIFBSObjectReturn(b, apiName, method.Returns.Type, resolved, pkgs)
+		}
+	} else if method.Returns != nil {
+		fmt.Fprintf(b, "    return (%s)out_result;\n", jniRetType)
+	}
+	b.WriteString("}\n\n")
+
+	// cancel
+	b.WriteString("JNIEXPORT void JNICALL\n")
+	fmt.Fprintf(b, "Java_%s_%sCancel(JNIEnv *env, jobject thiz, jlong op) {\n", jniClassPath, jniMethodName)
+	fmt.Fprintf(b, "    %s((%s)op);\n", AsyncCancelFunctionName(apiName, ifaceName, method.Name), opType)
+	b.WriteString("}\n\n")
+}
+
 // ---------- JNI C bridge file generation ----------
 
-func generateJNIFile(api *model.APIDefinition, resolved resolver.ResolvedTypes, pascalName, packageName string) (string, error) {
+func generateJNIFile(api *model.APIDefinition, resolved resolver.ResolvedTypes, pascalName string, pkgs kotlinPackages) (string, error) {
 	var b strings.Builder
 
 	apiName := api.API.Name
-	jniClassPath := strings.ReplaceAll(packageName, ".", "_") + "_" + pascalName
+	jniClassPath := strings.ReplaceAll(pkgs.binding, ".", "_") + "_" + pascalName
 
 	// Header
 	b.WriteString("#include <jni.h>\n")
 	b.WriteString("#include <string.h>\n")
+	if hasDeprecations(api) {
+		// The bridge forwards deprecated functions too; the Kotlin wrappers carry
+		// the warning instead.
+		fmt.Fprintf(&b, "#define %s(msg)\n", DeprecatedMacroName(apiName))
+	}
 	fmt.Fprintf(&b, "#include \"%s.h\"\n\n", apiName)
 
 	// Helper: throw exception
@@ -356,47 +1006,84 @@ func generateJNIFile(api *model.APIDefinition, resolved resolver.ResolvedTypes,
 	fmt.Fprintf(&b, "    }\n")
 	fmt.Fprintf(&b, "}\n\n")
 
+	// Helper: convert and release a returned string
+	if hasStringReturns(api) {
+		writeJNITakeString(&b, apiName)
+	}
+	if hasOptionalStringReturns(api) {
+		writeJNITakeOptionalString(&b)
+	}
+
+	writeJNIABIInfo(&b, apiName, jniClassPath)
+
 	// Generate JNI functions: constructors, auto-destructor, then regular methods
 	for _, iface := range api.Interfaces {
 		fmt.Fprintf(&b, "/* %s */\n", iface.Name)
 		for i := range iface.Constructors {
-			writeJNIFunction(&b, apiName, iface.Name, &iface.Constructors[i], jniClassPath, resolved, packageName)
+			writeJNIFunction(&b, apiName, iface.Name, &iface.Constructors[i], jniClassPath, resolved, pkgs)
 		}
 		if handleName, ok := iface.ConstructorHandleName(); ok {
 			destructor := SyntheticDestructor(handleName)
-			writeJNIFunction(&b, apiName, iface.Name, &destructor, jniClassPath, resolved, packageName)
+			writeJNIFunction(&b, apiName, iface.Name, &destructor, jniClassPath, resolved, pkgs)
 		}
 		for i := range iface.Methods {
-			writeJNIFunction(&b, apiName, iface.Name, &iface.Methods[i], jniClassPath, resolved, packageName)
+			if iface.Methods[i].Async {
+				writeJNIAsyncFunctions(&b, apiName, iface.Name, &iface.Methods[i], jniClassPath, resolved, pkgs)
+				continue
+			}
+			writeJNIFunction(&b, apiName, iface.Name, &iface.Methods[i], jniClassPath, resolved, pkgs)
 		}
 		b.WriteString("\n")
 	}
 
+	if len(api.Events) > 0 {
+		writeJNIEventFunctions(&b, apiName, jniClassPath)
+	}
+
 	return b.String(), nil
 }
 
-func writeJNIFunction(b *strings.Builder, apiName, ifaceName string, method *model.MethodDef, jniClassPath string, resolved resolver.ResolvedTypes, packageName string) {
+func writeJNIFunction(b *strings.Builder, apiName, ifaceName string, method *model.MethodDef, jniClassPath string, resolved resolver.ResolvedTypes, pkgs kotlinPackages) {
 	cabiFunc := CABIFunctionName(apiName, ifaceName, method.Name)
 	jniMethodName := jniNativeMethodName(ifaceName, method.Name)
 	hasError := method.Error != ""
 	hasReturn := method.Returns != nil
 	fbReturn := isFlatBufferReturn(method)
+	strReturn := hasReturn && model.IsString(method.Returns.Type)
+	bufElem, bufReturn := returnBufferElem(method)
+	presenceFlag := returnHasPresenceFlag(method)
+	if returnsTable(method) {
+		// A table result is copied out of its FlatBuffer like a byte buffer
+		bufElem, bufReturn = "uint8", true
+	}
 
 	// JNI return type
 	var jniRetType string
 	switch {
+	case presenceFlag:
+		jniRetType = "jobject"
 	case hasError && hasReturn:
-		if fbReturn {
+		switch {
+		case fbReturn:
 			jniRetType = "jobject"
-		} else {
+		case strReturn:
+			jniRetType = "jstring"
+		case bufReturn:
+			jniRetType = jniArrayCType(bufElem)
+		default:
 			jniRetType = "jlongArray"
 		}
 	case hasError && !hasReturn:
 		jniRetType = "jint"
 	case !hasError && hasReturn:
-		if fbReturn {
+		switch {
+		case fbReturn:
 			jniRetType = "jobject"
-		} else {
+		case strReturn:
+			jniRetType = "jstring"
+		case bufReturn:
+			jniRetType = jniArrayCType(bufElem)
+		default:
 			jniRetType = jniCReturnType(method.Returns.Type)
 		}
 	default:
@@ -418,14 +1105,22 @@ func writeJNIFunction(b *strings.Builder, apiName, ifaceName string, method *mod
 	fmt.Fprintf(b, "JNIEXPORT %s JNICALL\n", jniRetType)
 	fmt.Fprintf(b, "%s(%s) {\n", jniFuncName, paramStr)
 
-	// String marshalling setup
-	var stringParams []model.ParameterDef
+	// String, table and struct marshalling setup
+	var stringParams, tableParams, mutStructParams []model.ParameterDef
 	for _, p := range method.Parameters {
 		if model.IsString(p.Type) {
 			stringParams = append(stringParams, p)
-			jniVarName := ToCamelCase(p.Name)
-			fmt.Fprintf(b, "    const char *c_%s = (*env)->GetStringUTFChars(env, %s, NULL);\n",
-				p.Name, jniVarName)
+			writeJNIGetString(b, &p)
+		}
+		if p.Table {
+			tableParams = append(tableParams, p)
+			writeJNIGetTable(b, &p)
+		}
+		if p.Struct {
+			writeJNIGetStruct(b, &p, resolved)
+			if p.Transfer == "ref_mut" {
+				mutStructParams = append(mutStructParams, p)
+			}
 		}
 	}
 
@@ -435,17 +1130,51 @@ func writeJNIFunction(b *strings.Builder, apiName, ifaceName string, method *mod
 		callArgs = append(callArgs, jniToCArg(&p)...)
 	}
 
-	// Helper to release string params
+	// Helper to release string and table params and copy back ref_mut structs
 	releaseStrings := func() {
 		for _, sp := range stringParams {
-			jniVarName := ToCamelCase(sp.Name)
-			fmt.Fprintf(b, "    (*env)->ReleaseStringUTFChars(env, %s, c_%s);\n",
-				jniVarName, sp.Name)
+			writeJNIReleaseString(b, &sp)
+		}
+		for _, tp := range tableParams {
+			writeJNIReleaseTable(b, &tp)
+		}
+		for _, mp := range mutStructParams {
+			writeJNIPutStructArrays(b, &mp, resolved)
 		}
 	}
 
+	// Take the returned string, keeping an absent optional string null
+	takeString := "take_string"
+	if strReturn && method.Returns.Optional {
+		takeString = "take_optional_string"
+	}
+
 	// Call the C ABI function
 	switch {
+	case presenceFlag:
+		// Optional by-value return: null when absent, otherwise a boxed
+		// primitive or a data class
+		fmt.Fprintf(b, "    %s out_result;\n", CReturnType(method.Returns.Type))
+		fmt.Fprintf(b, "    bool out_has_result = false;\n")
+		callArgs = append(callArgs, "&out_result", "&out_has_result")
+		if hasError {
+			fmt.Fprintf(b, "    int32_t rc = %s(%s);\n", cabiFunc, strings.Join(callArgs, ", "))
+		} else {
+			fmt.Fprintf(b, "    %s(%s);\n", cabiFunc, strings.Join(callArgs, ", "))
+		}
+		releaseStrings()
+		if hasError {
+			writeJNIExceptionThrow(b, method.Error, pkgs)
+		}
+		fmt.Fprintf(b, "    if (!out_has_result) {\n")
+		fmt.Fprintf(b, "        return NULL;\n")
+		fmt.Fprintf(b, "    }\n")
+		if fbReturn {
+			writeJNIFBSObjectReturn(b, apiName, method.Returns.Type, resolved, pkgs)
+		} else {
+			writeJNIBoxedReturn(b, method.Returns.Type)
+		}
+
 	case hasError && hasReturn && fbReturn:
 		// Fallible with FlatBuffer return: throw JNI exception on error, return data class
 		retCType := CReturnType(method.Returns.Type)
@@ -453,8 +1182,38 @@ func writeJNIFunction(b *strings.Builder, apiName, ifaceName string, method *mod
 		callArgs = append(callArgs, "&out_result")
 		fmt.Fprintf(b, "    int32_t rc = %s(%s);\n", cabiFunc, strings.Join(callArgs, ", "))
 		releaseStrings()
-		writeJNIExceptionThrow(b, method.Error, packageName)
-		writeJNIFBSObjectReturn(b, method.Returns.Type, resolved, packageName)
+		writeJNIExceptionThrow(b, method.Error, pkgs)
+		writeJNIFBSObjectReturn(b, apiName, method.Returns.Type, resolved, pkgs)
+
+	case hasError && hasReturn && strReturn:
+		// Fallible with string return: throw JNI exception on error, return the string
+		fmt.Fprintf(b, "    char* out_result = NULL;\n")
+		callArgs = append(callArgs, "&out_result")
+		fmt.Fprintf(b, "    int32_t rc = %s(%s);\n", cabiFunc, strings.Join(callArgs, ", "))
+		releaseStrings()
+		writeJNIExceptionThrow(b, method.Error, pkgs)
+		fmt.Fprintf(b, "    return %s(env, out_result);\n", takeString)
+
+	case bufReturn:
+		// Buffer return: copy into a Java array and release the C buffer
+		fmt.Fprintf(b, "    %s* out_result = NULL;\n", model.PrimitiveCType(bufElem))
+		fmt.Fprintf(b, "    uint32_t out_result_len = 0;\n")
+		callArgs = append(callArgs, "&out_result", "&out_result_len")
+		if hasError {
+			fmt.Fprintf(b, "    int32_t rc = %s(%s);\n", cabiFunc, strings.Join(callArgs, ", "))
+		} else {
+			fmt.Fprintf(b, "    %s(%s);\n", cabiFunc, strings.Join(callArgs, ", "))
+		}
+		releaseStrings()
+		if hasError {
+			writeJNIExceptionThrow(b, method.Error, pkgs)
+		}
+		if returnsTable(method) && method.Returns.Optional {
+			fmt.Fprintf(b, "    if (out_result == NULL) {\n")
+			fmt.Fprintf(b, "        return NULL;\n")
+			fmt.Fprintf(b, "    }\n")
+		}
+		writeJNIBufferReturn(b, apiName, bufElem)
 
 	case hasError && hasReturn:
 		// Fallible with handle/primitive return: LongArray pattern
@@ -479,7 +1238,13 @@ func writeJNIFunction(b *strings.Builder, apiName, ifaceName string, method *mod
 		retCType := CReturnType(method.Returns.Type)
 		fmt.Fprintf(b, "    %s out_result = %s(%s);\n", retCType, cabiFunc, strings.Join(callArgs, ", "))
 		releaseStrings()
-		writeJNIFBSObjectReturn(b, method.Returns.Type, resolved, packageName)
+		writeJNIFBSObjectReturn(b, apiName, method.Returns.Type, resolved, pkgs)
+
+	case !hasError && hasReturn && strReturn:
+		// Infallible with string return
+		fmt.Fprintf(b, "    char* result = %s(%s);\n", cabiFunc, strings.Join(callArgs, ", "))
+		releaseStrings()
+		fmt.Fprintf(b, "    return %s(env, result);\n", takeString)
 
 	case !hasError && hasReturn:
 		// Infallible with handle/primitive return
@@ -513,12 +1278,64 @@ func kotlinParamType(t string) string {
 	if model.IsPrimitive(t) {
 		return kotlinPrimitiveType(t)
 	}
-	// FlatBuffer type — passed as ByteArray (serialized)
+	// A table is passed as its finished FlatBuffer, e.g. FlatBufferBuilder.sizedByteArray()
 	return "ByteArray"
 }
 
+// kotlinParamDecl returns the Kotlin parameter declaration for a wrapper
+// method. Optional parameters are nullable.
+func kotlinParamDecl(p model.ParameterDef) string {
+	ktType := kotlinParamType(p.Type)
+	if p.Struct {
+		ktType = kotlinFBSDataClassName(p.Type)
+	}
+	if p.Optional {
+		ktType += "?"
+	}
+	return kotlinName(p.Name) + ": " + ktType
+}
+
+// kotlinResultType returns the Kotlin type a wrapper method returns.
+// Optional results are nullable.
+func kotlinResultType(ret *model.ReturnDef) string {
+	ktType := kotlinReturnType(ret.Type)
+	if ret.Table {
+		ktType = ret.Type
+	}
+	if ret.Optional {
+		ktType += "?"
+	}
+	return ktType
+}
+
+// kotlinResultIsObject reports whether a method's result crosses JNI as an
+// object. Optional primitives are boxed so that an absent value is null.
+func kotlinResultIsObject(method *model.MethodDef) bool {
+	return kotlinReturnsObject(method.Returns.Type) || returnHasPresenceFlag(method)
+}
+
+// kotlinNativeResultType returns the JNI native method return type for a
+// method's result. Optional objects and boxed primitives are nullable;
+// absent handles are 0.
+func kotlinNativeResultType(method *model.MethodDef) string {
+	t := kotlinNativeReturnType(method.Returns.Type)
+	if returnsTable(method) {
+		t = "ByteArray"
+	}
+	if _, ok := model.IsHandle(method.Returns.Type); !ok && method.Returns.Optional {
+		t += "?"
+	}
+	return t
+}
+
 // kotlinReturnType maps an API return type to its Kotlin type.
 func kotlinReturnType(t string) string {
+	if model.IsString(t) {
+		return "String"
+	}
+	if elemType, ok := model.IsBuffer(t); ok {
+		return kotlinArrayType(elemType)
+	}
 	if handleName, ok := model.IsHandle(t); ok {
 		return handleName
 	}
@@ -531,6 +1348,24 @@ func kotlinReturnType(t string) string {
 	return "Long"
 }
 
+// kotlinReturnsObject reports whether a return type crosses JNI as an object
+// (a data class, a String or an array), with errors thrown from the JNI bridge.
+func kotlinReturnsObject(t string) bool {
+	if _, ok := model.IsBuffer(t); ok {
+		return true
+	}
+	return model.IsString(t) || model.IsFlatBufferType(t)
+}
+
+// kotlinHandleWrap returns the expression wrapping a returned native handle in
+// its class. A borrowed handle's wrapper does not own it.
+func kotlinHandleWrap(ret *model.ReturnDef, expr string) string {
+	if ret.Borrowed {
+		return kotlinHandleReturnType(ret.Type) + "(" + expr + ", owned = false)"
+	}
+	return kotlinHandleReturnType(ret.Type) + "(" + expr + ")"
+}
+
 // kotlinHandleReturnType returns the Kotlin class name for a handle return type.
 func kotlinHandleReturnType(t string) string {
 	if handleName, ok := model.IsHandle(t); ok {
@@ -541,6 +1376,12 @@ func kotlinHandleReturnType(t string) string {
 
 // kotlinNativeReturnType maps an API return type to the JNI native method return type in Kotlin.
 func kotlinNativeReturnType(t string) string {
+	if model.IsString(t) {
+		return "String"
+	}
+	if elemType, ok := model.IsBuffer(t); ok {
+		return kotlinArrayType(elemType)
+	}
 	if _, ok := model.IsHandle(t); ok {
 		return "Long"
 	}
@@ -604,30 +1445,72 @@ func kotlinArrayType(elemType string) string {
 }
 
 // kotlinParamToNativeArg returns the Kotlin expression to pass a parameter to a native method.
+// Absent handles pass 0 and absent primitives pass a cleared presence flag.
 func kotlinParamToNativeArg(p model.ParameterDef) string {
+	name := kotlinName(p.Name)
 	if _, ok := model.IsHandle(p.Type); ok {
-		return ToCamelCase(p.Name) + ".handle"
+		member := "handle"
+		if isMoveParam(&p) {
+			member = "take()"
+		}
+		if p.Optional {
+			return name + "?." + member + " ?: 0L"
+		}
+		return name + "." + member
+	}
+	if hasPresenceFlag(&p) {
+		return fmt.Sprintf("%[1]s != null, %[1]s ?: %[2]s", name, kotlinZeroValue(p.Type))
+	}
+	return name
+}
+
+// kotlinZeroValue returns the Kotlin zero literal of a primitive type.
+func kotlinZeroValue(t string) string {
+	switch kotlinPrimitiveType(t) {
+	case "Byte":
+		return "0.toByte()"
+	case "Short":
+		return "0.toShort()"
+	case "Long":
+		return "0L"
+	case "Float":
+		return "0f"
+	case "Double":
+		return "0.0"
+	case "Boolean":
+		return "false"
+	default:
+		return "0"
 	}
-	return ToCamelCase(p.Name)
 }
 
 // kotlinNativeDeclParam returns the Kotlin parameter declaration for a native method.
 func kotlinNativeDeclParam(p model.ParameterDef) string {
-	name := ToCamelCase(p.Name)
+	name := kotlinName(p.Name)
 	if _, ok := model.IsHandle(p.Type); ok {
 		return name + ": Long"
 	}
+	nullable := ""
+	if p.Optional {
+		nullable = "?"
+	}
 	if model.IsString(p.Type) {
-		return name + ": String"
+		return name + ": String" + nullable
 	}
 	if elemType, ok := model.IsBuffer(p.Type); ok {
-		return name + ": " + kotlinArrayType(elemType) + ", " + name + "Len: Int"
+		return name + ": " + kotlinArrayType(elemType) + ", " + ToCamelCase(p.Name) + "Len: Int"
+	}
+	if hasPresenceFlag(&p) {
+		return jniPresenceFlagName(p.Name) + ": Boolean, " + name + ": " + kotlinPrimitiveType(p.Type)
 	}
 	if model.IsPrimitive(p.Type) {
 		return name + ": " + kotlinPrimitiveType(p.Type)
 	}
+	if p.Struct {
+		return name + ": " + kotlinFBSDataClassName(p.Type) + nullable
+	}
 	// FlatBuffer type — passed as ByteArray
-	return name + ": ByteArray"
+	return name + ": ByteArray" + nullable
 }
 
 // jniNativeMethodName builds the Kotlin/JNI native method name: nativeIfaceMethod
@@ -639,41 +1522,184 @@ func jniNativeMethodName(ifaceName, methodName string) string {
 
 // jniParamDecl returns JNI C parameter declarations for a given API parameter.
 func jniParamDecl(p *model.ParameterDef) []string {
-	name := ToCamelCase(p.Name)
+	name := jniName(p.Name)
 	if model.IsString(p.Type) {
 		return []string{"jstring " + name}
 	}
 	if elemType, ok := model.IsBuffer(p.Type); ok {
 		jniArrayType := jniArrayCType(elemType)
-		return []string{jniArrayType + " " + name, "jint " + name + "Len"}
+		return []string{jniArrayType + " " + name, "jint " + ToCamelCase(p.Name) + "Len"}
 	}
 	if _, ok := model.IsHandle(p.Type); ok {
 		return []string{"jlong " + name}
 	}
+	if hasPresenceFlag(p) {
+		return []string{"jboolean " + jniPresenceFlagName(p.Name), jniPrimitiveCType(p.Type) + " " + name}
+	}
 	if model.IsPrimitive(p.Type) {
 		return []string{jniPrimitiveCType(p.Type) + " " + name}
 	}
-	// FlatBuffer type — ByteArray
+	if p.Struct {
+		// The data class, read into a C struct by writeJNIGetStruct
+		return []string{"jobject " + name}
+	}
+	// FlatBuffer type — ByteArray; a table's is pinned by writeJNIGetTable
 	return []string{"jbyteArray " + name}
 }
 
+// kotlinName returns the Kotlin name of a snake_case parameter or method
+// name, escaped if it is a keyword.
+func kotlinName(name string) string {
+	return kotlinIdent(ToCamelCase(name))
+}
+
+// jniName returns the JNI C name of a parameter, its Kotlin name escaped
+// for C.
+func jniName(name string) string {
+	return cIdent(ToCamelCase(name))
+}
+
+// jniPresenceFlagName returns the JNI/Kotlin name of an optional primitive's
+// presence flag, e.g., "limit" → "hasLimit".
+func jniPresenceFlagName(name string) string {
+	return ToCamelCase(PresenceFlagName(name))
+}
+
+// writeJNIGetString emits the conversion of a jstring parameter to a C
+// string. An absent optional string stays NULL.
+func writeJNIGetString(b *strings.Builder, p *model.ParameterDef) {
+	name := jniName(p.Name)
+	if p.Optional {
+		fmt.Fprintf(b, "    const char *c_%s = %s ? (*env)->GetStringUTFChars(env, %s, NULL) : NULL;\n",
+			p.Name, name, name)
+		return
+	}
+	fmt.Fprintf(b, "    const char *c_%s = (*env)->GetStringUTFChars(env, %s, NULL);\n", p.Name, name)
+}
+
+// writeJNIReleaseString emits the release of a string converted by writeJNIGetString.
+func writeJNIReleaseString(b *strings.Builder, p *model.ParameterDef) {
+	name := jniName(p.Name)
+	if p.Optional {
+		fmt.Fprintf(b, "    if (c_%s) (*env)->ReleaseStringUTFChars(env, %s, c_%s);\n", p.Name, name, p.Name)
+		return
+	}
+	fmt.Fprintf(b, "    (*env)->ReleaseStringUTFChars(env, %s, c_%s);\n", name, p.Name)
+}
+
+// writeJNIGetTable emits the pinning of a table parameter's FlatBuffer and
+// its size. An absent optional table stays NULL.
+func writeJNIGetTable(b *strings.Builder, p *model.ParameterDef) {
+	name := jniName(p.Name)
+	if p.Optional {
+		fmt.Fprintf(b, "    jbyte *c_%s = %s ? (*env)->GetByteArrayElements(env, %s, NULL) : NULL;\n", p.Name, name, name)
+		fmt.Fprintf(b, "    jsize c_%s_len = %s ? (*env)->GetArrayLength(env, %s) : 0;\n", p.Name, name, name)
+		return
+	}
+	fmt.Fprintf(b, "    jbyte *c_%s = (*env)->GetByteArrayElements(env, %s, NULL);\n", p.Name, name)
+	fmt.Fprintf(b, "    jsize c_%s_len = (*env)->GetArrayLength(env, %s);\n", p.Name, name)
+}
+
+// writeJNIReleaseTable emits the release of a table pinned by
+// writeJNIGetTable. The buffer is only read, so nothing is copied back.
+func writeJNIReleaseTable(b *strings.Builder, p *model.ParameterDef) {
+	name := jniName(p.Name)
+	if p.Optional {
+		fmt.Fprintf(b, "    if (c_%s) (*env)->ReleaseByteArrayElements(env, %s, c_%s, JNI_ABORT);\n", p.Name, name, p.Name)
+		return
+	}
+	fmt.Fprintf(b, "    (*env)->ReleaseByteArrayElements(env, %s, c_%s, JNI_ABORT);\n", name, p.Name)
+}
+
+// writeJNIGetStruct emits the copy of a struct parameter's data class into a
+// local C struct, c_<name>, which is passed by value or by address. An
+// absent optional struct leaves it zeroed and passes NULL.
+func writeJNIGetStruct(b *strings.Builder, p *model.ParameterDef, resolved resolver.ResolvedTypes) {
+	name := jniName(p.Name)
+	fmt.Fprintf(b, "    %s c_%s = {0};\n", model.FlatBufferCType(p.Type), p.Name)
+	// Declared here so that writeJNIPutStructArrays can copy them back
+	for _, f := range resolved[p.Type].ActiveFields() {
+		if elem, _, ok := resolver.ArrayField(f.Type); ok {
+			fmt.Fprintf(b, "    %s j_%s_%s = NULL;\n", jniArrayCType(elem), name, f.Name)
+		}
+	}
+	indent := "    "
+	if p.Optional {
+		fmt.Fprintf(b, "    if (%s != NULL) {\n", name)
+		indent = "        "
+	}
+	cls := "cls_" + p.Name
+	fmt.Fprintf(b, "%sjclass %s = (*env)->GetObjectClass(env, %s);\n", indent, cls, name)
+	for _, f := range resolved[p.Type].ActiveFields() {
+		if _, _, ok := resolver.ArrayField(f.Type); ok {
+			writeJNIGetArrayField(b, indent, f, name, cls, "c_"+p.Name)
+			continue
+		}
+		kind, cType := "Int", model.FlatBufferCType(f.Type)
+		if resolver.IsScalarField(f.Type) {
+			kind, cType = kotlinPrimitiveType(f.Type), model.PrimitiveCType(f.Type)
+		}
+		fmt.Fprintf(b, "%sc_%s.%s = (%s)(*env)->Get%sField(env, %s, (*env)->GetFieldID(env, %s, \"%s\", \"%s\"));\n",
+			indent, p.Name, f.Name, cType, kind, name, cls, ToCamelCase(f.Name), jniFBSFieldDescriptor(f.Type))
+	}
+	if p.Optional {
+		b.WriteString("    }\n")
+	}
+}
+
+// writeJNIPutStructArrays emits the copy of a ref_mut struct parameter's
+// array fields back into the data class's arrays after the call. Its other
+// fields are immutable vals and are not copied back.
+func writeJNIPutStructArrays(b *strings.Builder, p *model.ParameterDef, resolved resolver.ResolvedTypes) {
+	name := jniName(p.Name)
+	indent := "    "
+	if p.Optional {
+		fmt.Fprintf(b, "    if (%s != NULL) {\n", name)
+		indent = "        "
+	}
+	for _, f := range resolved[p.Type].ActiveFields() {
+		if elem, n, ok := resolver.ArrayField(f.Type); ok {
+			fmt.Fprintf(b, "%s(*env)->Set%sArrayRegion(env, j_%s_%s, 0, %d, (const %s*)c_%s.%s);\n",
+				indent, jniArrayKind(elem), name, f.Name, n, jniPrimitiveCType(elem), p.Name, f.Name)
+		}
+	}
+	if p.Optional {
+		b.WriteString("    }\n")
+	}
+}
+
 // jniToCArg returns the C expression(s) to pass a JNI parameter to the C ABI function.
 func jniToCArg(p *model.ParameterDef) []string {
-	name := ToCamelCase(p.Name)
+	name := jniName(p.Name)
 	if model.IsString(p.Type) {
 		return []string{"c_" + p.Name}
 	}
 	if _, ok := model.IsBuffer(p.Type); ok {
 		// For buffers, we need to get the array elements pointer
 		// This is simplified — in production you'd use GetByteArrayElements etc.
-		return []string{"(" + CParamType(p.Type, p.Transfer) + ")" + name, "(uint32_t)" + name + "Len"}
+		return []string{"(" + CParamType(p.Type, p.Transfer) + ")" + name, "(uint32_t)" + ToCamelCase(p.Name) + "Len"}
 	}
 	if handleName, ok := model.IsHandle(p.Type); ok {
 		return []string{"(" + HandleTypedefName(handleName) + ")" + name}
 	}
+	if hasPresenceFlag(p) {
+		return []string{"(bool)" + jniPresenceFlagName(p.Name), "(" + model.PrimitiveCType(p.Type) + ")" + name}
+	}
 	if model.IsPrimitive(p.Type) {
 		return []string{"(" + model.PrimitiveCType(p.Type) + ")" + name}
 	}
+	if p.Table {
+		return []string{"(const uint8_t*)c_" + p.Name, "(uint32_t)c_" + p.Name + "_len"}
+	}
+	if p.Struct {
+		switch {
+		case p.Transfer != "ref" && p.Transfer != "ref_mut":
+			return []string{"c_" + p.Name}
+		case p.Optional:
+			return []string{fmt.Sprintf("(%s ? &c_%s : NULL)", name, p.Name)}
+		}
+		return []string{"&c_" + p.Name}
+	}
 	// FlatBuffer type
 	return []string{"(" + CParamType(p.Type, p.Transfer) + ")" + name}
 }
@@ -731,11 +1757,27 @@ func jniArrayCType(elemType string) string {
 	}
 }
 
+// jniArrayKind returns the element kind used in JNI array function names
+// (New<Kind>Array, Set<Kind>ArrayRegion) for a buffer element type.
+func jniArrayKind(elemType string) string {
+	return strings.TrimSuffix(kotlinArrayType(elemType), "Array")
+}
+
 // ---------- FlatBuffer return type helpers ----------
 
-// isFlatBufferReturn returns true if the method returns a FlatBuffer type.
+// isFlatBufferReturn returns true if the method returns a FlatBuffer struct
+// or union, which crosses JNI as a data class. Tables cross as their FlatBuffer.
 func isFlatBufferReturn(method *model.MethodDef) bool {
-	return method.Returns != nil && model.IsFlatBufferType(method.Returns.Type)
+	return method.Returns != nil && !method.Returns.Table && model.IsFlatBufferType(method.Returns.Type)
+}
+
+// kotlinTableRoot returns the flatc Kotlin call reading the root of a table
+// from the ByteArray expression data, e.g. "Fields.StreamInfo" →
+// "Fields.StreamInfo.getRootAsStreamInfo(java.nio.ByteBuffer.wrap(data))".
+// flatc declares each table in a package named after its namespace.
+func kotlinTableRoot(t, data string) string {
+	name := t[strings.LastIndex(t, ".")+1:]
+	return fmt.Sprintf("%s.getRootAs%s(java.nio.ByteBuffer.wrap(%s))", t, name, data)
 }
 
 // kotlinFBSDataClassName converts a FBS type name to a Kotlin data class name.
@@ -746,6 +1788,9 @@ func kotlinFBSDataClassName(typeName string) string {
 
 // kotlinFBSFieldType maps a FBS field type to a Kotlin type.
 func kotlinFBSFieldType(fieldType string) string {
+	if elem, _, ok := resolver.ArrayField(fieldType); ok {
+		return kotlinArrayType(elem)
+	}
 	switch fieldType {
 	case "string":
 		return "String?"
@@ -770,6 +1815,9 @@ func kotlinFBSFieldType(fieldType string) string {
 
 // jniFBSFieldDescriptor maps a FBS field type to a JNI type descriptor for constructor signatures.
 func jniFBSFieldDescriptor(fieldType string) string {
+	if elem, _, ok := resolver.ArrayField(fieldType); ok {
+		return "[" + jniFBSFieldDescriptor(elem)
+	}
 	switch fieldType {
 	case "string":
 		return "Ljava/lang/String;"
@@ -792,9 +1840,10 @@ func jniFBSFieldDescriptor(fieldType string) string {
 	}
 }
 
-// writeKotlinFBSDataClasses generates Kotlin data classes for all FlatBuffer types
-// used as method return values.
-func writeKotlinFBSDataClasses(b *strings.Builder, resolved resolver.ResolvedTypes, api *model.APIDefinition) {
+// writeKotlinFBSDataClasses generates Kotlin data classes for all FlatBuffer
+// structs and unions used as method return values, and structs used as
+// parameters, that keep selects.
+func writeKotlinFBSDataClasses(b *strings.Builder, resolved resolver.ResolvedTypes, api *model.APIDefinition, keep func(string) bool) {
 	seen := map[string]bool{}
 	var fbTypes []string
 	collectFBReturn := func(method *model.MethodDef) {
@@ -805,6 +1854,12 @@ func writeKotlinFBSDataClasses(b *strings.Builder, resolved resolver.ResolvedTyp
 				fbTypes = append(fbTypes, t)
 			}
 		}
+		for _, p := range method.Parameters {
+			if p.Struct && !seen[p.Type] {
+				seen[p.Type] = true
+				fbTypes = append(fbTypes, p.Type)
+			}
+		}
 	}
 	for _, iface := range api.Interfaces {
 		for i := range iface.Constructors {
@@ -815,24 +1870,113 @@ func writeKotlinFBSDataClasses(b *strings.Builder, resolved resolver.ResolvedTyp
 		}
 	}
 
+	// A returned union needs the data classes of its struct members
+	for _, t := range fbTypes {
+		if info, ok := resolved[t]; ok && info.Kind == resolver.TypeKindUnion {
+			for _, m := range info.Members {
+				if !seen[m.Type] && !isTableType(resolved, m.Type) {
+					seen[m.Type] = true
+					fbTypes = append(fbTypes, m.Type)
+				}
+			}
+		}
+	}
+
 	for _, t := range fbTypes {
 		className := kotlinFBSDataClassName(t)
 		typeInfo, ok := resolved[t]
-		if !ok {
+		if !ok || !keep(t) {
+			continue
+		}
+		if typeInfo.Kind == resolver.TypeKindUnion {
+			writeKotlinUnion(b, t, typeInfo, resolved)
 			continue
 		}
 		var fields []string
-		for _, f := range typeInfo.Fields {
+		for _, f := range typeInfo.ActiveFields() {
 			fields = append(fields, fmt.Sprintf("val %s: %s", ToCamelCase(f.Name), kotlinFBSFieldType(f.Type)))
 		}
-		fmt.Fprintf(b, "data class %s(%s)\n\n", className, strings.Join(fields, ", "))
+		checks := kotlinArrayFieldChecks(typeInfo)
+		if len(checks) == 0 {
+			fmt.Fprintf(b, "data class %s(%s)\n\n", className, strings.Join(fields, ", "))
+			continue
+		}
+		fmt.Fprintf(b, "data class %s(%s) {\n    init {\n", className, strings.Join(fields, ", "))
+		for _, check := range checks {
+			fmt.Fprintf(b, "        %s\n", check)
+		}
+		b.WriteString("    }\n}\n\n")
 	}
 }
 
+// writeJNITakeString emits the JNI helper that converts a returned string to a
+// jstring and releases it; NULL converts to the empty string.
+func writeJNITakeString(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, `static jstring take_string(JNIEnv *env, char *str) {
+    jstring result = (*env)->NewStringUTF(env, str ? str : "");
+    %s(str);
+    return result;
+}
+
+`, StringFreeFunctionName(apiName))
+}
+
+// writeJNITakeOptionalString emits the JNI helper for optional string
+// returns, which keeps NULL as a null jstring.
+func writeJNITakeOptionalString(b *strings.Builder) {
+	b.WriteString(`static jstring take_optional_string(JNIEnv *env, char *str) {
+    return str ? take_string(env, str) : NULL;
+}
+
+`)
+}
+
+// hasOptionalStringReturns reports whether any method returns an optional string.
+func hasOptionalStringReturns(api *model.APIDefinition) bool {
+	for _, iface := range api.Interfaces {
+		for _, method := range iface.Methods {
+			if isOptionalReturn(&method) && model.IsString(method.Returns.Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeJNIBoxedReturn emits JNI code that boxes the primitive out_result
+// (e.g., java.lang.Integer) so an optional primitive can be returned as an object.
+func writeJNIBoxedReturn(b *strings.Builder, retType string) {
+	desc := jniFBSFieldDescriptor(retType)
+	boxClass := "java/lang/" + jniBoxedClassName(retType)
+	fmt.Fprintf(b, "    jclass box_cls = (*env)->FindClass(env, \"%s\");\n", boxClass)
+	fmt.Fprintf(b, "    jmethodID box_of = (*env)->GetStaticMethodID(env, box_cls, \"valueOf\", \"(%s)L%s;\");\n", desc, boxClass)
+	fmt.Fprintf(b, "    return (*env)->CallStaticObjectMethod(env, box_cls, box_of, (%s)out_result);\n", jniPrimitiveCType(retType))
+}
+
+// jniBoxedClassName returns the java.lang box class of a primitive type.
+func jniBoxedClassName(t string) string {
+	if ktType := kotlinPrimitiveType(t); ktType != "Int" {
+		return ktType
+	}
+	return "Integer"
+}
+
+// writeJNIBufferReturn emits JNI code that copies the returned C buffer
+// (out_result, out_result_len) into a new Java array and releases it.
+func writeJNIBufferReturn(b *strings.Builder, apiName, elemType string) {
+	kind := jniArrayKind(elemType)
+	fmt.Fprintf(b, "    %s arr = (*env)->New%sArray(env, (jsize)out_result_len);\n", jniArrayCType(elemType), kind)
+	fmt.Fprintf(b, "    if (arr != NULL && out_result_len > 0) {\n")
+	fmt.Fprintf(b, "        (*env)->Set%sArrayRegion(env, arr, 0, (jsize)out_result_len, (const %s*)out_result);\n",
+		kind, jniPrimitiveCType(elemType))
+	fmt.Fprintf(b, "    }\n")
+	fmt.Fprintf(b, "    %s(out_result);\n", BufferFreeFunctionName(apiName))
+	fmt.Fprintf(b, "    return arr;\n")
+}
+
 // writeJNIExceptionThrow emits JNI code to throw a Kotlin exception when rc != 0.
-func writeJNIExceptionThrow(b *strings.Builder, errorType, packageName string) {
-	exClassName := kotlinErrorExceptionName(errorType)
-	jniClassPath := strings.ReplaceAll(packageName, ".", "/") + "/" + exClassName
+func writeJNIExceptionThrow(b *strings.Builder, errorType string, pkgs kotlinPackages) {
+	jniClassPath := pkgs.classPath(errorType, kotlinErrorExceptionName(errorType))
 	fmt.Fprintf(b, "    if (rc != 0) {\n")
 	fmt.Fprintf(b, "        jclass ex_cls = (*env)->FindClass(env, \"%s\");\n", jniClassPath)
 	fmt.Fprintf(b, "        jmethodID ex_ctor = (*env)->GetMethodID(env, ex_cls, \"<init>\", \"(I)V\");\n")
@@ -842,37 +1986,48 @@ func writeJNIExceptionThrow(b *strings.Builder, errorType, packageName string) {
 }
 
 // writeJNIFBSObjectReturn emits JNI code to construct a Kotlin data class from the C struct out_result.
-func writeJNIFBSObjectReturn(b *strings.Builder, retType string, resolved resolver.ResolvedTypes, packageName string) {
-	className := kotlinFBSDataClassName(retType)
-	jniClassPath := strings.ReplaceAll(packageName, ".", "/") + "/" + className
+func writeJNIFBSObjectReturn(b *strings.Builder, apiName, retType string, resolved resolver.ResolvedTypes, pkgs kotlinPackages) {
+	jniClassPath := pkgs.classPath(retType, kotlinFBSDataClassName(retType))
 
 	typeInfo, ok := resolved[retType]
 	if !ok {
 		fmt.Fprintf(b, "    return NULL; /* unresolved type %s */\n", retType)
 		return
 	}
+	if typeInfo.Kind == resolver.TypeKindUnion {
+		writeJNIUnionReturn(b, apiName, retType, typeInfo, resolved, pkgs)
+		return
+	}
 
-	// Convert string fields to jstring first
-	for _, f := range typeInfo.Fields {
+	sig, args := writeJNIDataClassArgs(b, "    ", typeInfo, "out_result")
+	fmt.Fprintf(b, "    jclass cls = (*env)->FindClass(env, \"%s\");\n", jniClassPath)
+	fmt.Fprintf(b, "    jmethodID ctor = (*env)->GetMethodID(env, cls, \"<init>\", \"%s\");\n", sig)
+	fmt.Fprintf(b, "    return (*env)->NewObject(env, cls, ctor, %s);\n", strings.Join(args, ", "))
+}
+
+// writeJNIDataClassArgs emits the jstring conversions of the string fields of
+// the C struct src and returns the data class constructor signature and
+// arguments.
+func writeJNIDataClassArgs(b *strings.Builder, indent string, typeInfo *resolver.TypeInfo, src string) (string, []string) {
+	// Convert string and array fields to Java objects first
+	for _, f := range typeInfo.ActiveFields() {
 		if f.Type == "string" {
-			fmt.Fprintf(b, "    jstring j_%s = (*env)->NewStringUTF(env, out_result.%s);\n", f.Name, f.Name)
+			fmt.Fprintf(b, "%sjstring j_%s = take_string(env, (char*)%s.%s);\n", indent, f.Name, src, f.Name)
+		} else if _, _, ok := resolver.ArrayField(f.Type); ok {
+			writeJNIArrayField(b, indent, f, src)
 		}
 	}
 
 	// Build constructor signature and arguments
 	var sigParts []string
 	var args []string
-	for _, f := range typeInfo.Fields {
+	for _, f := range typeInfo.ActiveFields() {
 		sigParts = append(sigParts, jniFBSFieldDescriptor(f.Type))
-		if f.Type == "string" {
+		if _, _, isArray := resolver.ArrayField(f.Type); isArray || f.Type == "string" {
 			args = append(args, "j_"+f.Name)
 		} else {
-			args = append(args, fmt.Sprintf("(%s)out_result.%s", jniPrimitiveCType(f.Type), f.Name))
+			args = append(args, fmt.Sprintf("(%s)%s.%s", jniPrimitiveCType(f.Type), src, f.Name))
 		}
 	}
-
-	sig := "(" + strings.Join(sigParts, "") + ")V"
-	fmt.Fprintf(b, "    jclass cls = (*env)->FindClass(env, \"%s\");\n", jniClassPath)
-	fmt.Fprintf(b, "    jmethodID ctor = (*env)->GetMethodID(env, cls, \"<init>\", \"%s\");\n", sig)
-	fmt.Fprintf(b, "    return (*env)->NewObject(env, cls, ctor, %s);\n", strings.Join(args, ", "))
+	return "(" + strings.Join(sigParts, "") + ")V", args
 }
@@ -3,6 +3,8 @@ package gen
 import (
 	"strings"
 	"testing"
+
+	"github.com/benn-herrera/xplatter/model"
 )
 
 func TestKotlinGenerator_Minimal(t *testing.T) {
@@ -374,3 +376,527 @@ func TestKotlinGenerator_FlatBufferReturn(t *testing.T) {
 		t.Error("handle-returning JNI functions should still use jlongArray")
 	}
 }
+
+func TestKotlinGenerator_Events(t *testing.T) {
+	ctx := loadTestAPI(t, "events.yaml")
+	gen := &KotlinGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	kt := string(files[0].Content)
+	for _, want := range []string{
+		"import android.os.Looper\n",
+		"import android.os.MessageQueue\n",
+		"enum class EventApiEventKind(val value: Int) {\n    TOUCH(1),\n    ENTITY_SPAWNED(2);\n",
+		"class EventApiEvent(val kind: EventApiEventKind, val payload: ByteArray)",
+		"fun pollEvent(): EventApiEvent? {",
+		"fun drainEvents(handler: (EventApiEvent) -> Unit): Int {",
+		"fun subscribeEvents(looper: Looper = Looper.getMainLooper(), handler: (EventApiEvent) -> Unit): AutoCloseable? {",
+		"MessageQueue.OnFileDescriptorEventListener.EVENT_INPUT",
+		"external fun nativeEventPoll(kind: IntArray, buffer: ByteArray): Int",
+		"external fun nativeEventSignalFd(): Int",
+	} {
+		if !strings.Contains(kt, want) {
+			t.Errorf("Kotlin file missing %q", want)
+		}
+	}
+
+	jni := string(files[1].Content)
+	if !strings.Contains(jni, "Java_event_api_EventApi_nativeEventPoll(JNIEnv *env, jobject thiz, jintArray kind, jbyteArray buffer) {") {
+		t.Error("missing JNI event poll bridge")
+	}
+	if !strings.Contains(jni, "int32_t result = event_api_event_poll(&out_kind, (uint8_t*)data, (uint32_t)size);") {
+		t.Error("JNI poll bridge should call the C poll function")
+	}
+	if !strings.Contains(jni, "return (jint)event_api_event_signal_fd();") {
+		t.Error("missing JNI signal fd bridge")
+	}
+
+	// Android imports only when events are declared
+	ctx = loadTestAPI(t, "minimal.yaml")
+	files, err = gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if strings.Contains(string(files[0].Content), "import android.os") {
+		t.Error("android imports should only be emitted with events")
+	}
+}
+
+func TestKotlinGenerator_Async(t *testing.T) {
+	ctx := loadTestAPI(t, "async.yaml")
+	gen := &KotlinGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	kt := string(files[0].Content)
+	for _, want := range []string{
+		"import kotlinx.coroutines.CancellationException\nimport kotlinx.coroutines.delay\n",
+		"suspend fun loadModel(path: String): Common.EntityId {",
+		"val result = AsyncApi.awaitOperation(AsyncApi.nativeAssetsLoadModelStart(handle, path), status, AsyncApi::nativeAssetsLoadModelPoll, AsyncApi::nativeAssetsLoadModelCancel)",
+		"if (status[1] != 0) throw CommonErrorCodeException(status[1])",
+		"suspend fun warmUp() {",
+		"suspend fun forkEngine(): Engine {",
+		"return Engine(result)",
+		"return Common.EntityId.getRootAsEntityId(java.nio.ByteBuffer.wrap(result!!))",
+		"internal suspend fun <T> awaitOperation(",
+		"if (!finished) cancel(op)",
+		"external fun nativeAssetsLoadModelStart(engine: Long, path: String): Long",
+		"external fun nativeAssetsLoadModelPoll(op: Long, status: IntArray): ByteArray?",
+		"external fun nativeAssetsLoadModelCancel(op: Long)",
+		"external fun nativeAssetsWarmUpPoll(op: Long, status: IntArray): Unit",
+	} {
+		if !strings.Contains(kt, want) {
+			t.Errorf("Kotlin file missing %q", want)
+		}
+	}
+
+	jni := string(files[1].Content)
+	for _, want := range []string{
+		"Java_async_api_AsyncApi_nativeAssetsLoadModelStart(JNIEnv *env, jobject thiz, jlong engine, jstring path) {",
+		"async_api_async_op op = async_api_assets_load_model_start((engine_handle)engine, c_path);",
+		"Java_async_api_AsyncApi_nativeAssetsLoadModelPoll(JNIEnv *env, jobject thiz, jlong op, jintArray status) {",
+		"int32_t st = async_api_assets_load_model_poll((async_api_async_op)op, &out_error, &out_result, &out_result_len);",
+		"(*env)->SetIntArrayRegion(env, status, 0, 2, values);",
+		"if (st != ASYNC_API_ASYNC_DONE || out_error != 0) {",
+		"async_api_assets_load_model_cancel((async_api_async_op)op);",
+	} {
+		if !strings.Contains(jni, want) {
+			t.Errorf("JNI file missing %q", want)
+		}
+	}
+
+	// Coroutine imports only when async methods are declared
+	ctx = loadTestAPI(t, "minimal.yaml")
+	files, err = gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if strings.Contains(string(files[0].Content), "kotlinx.coroutines") {
+		t.Error("coroutine imports should only be emitted with async methods")
+	}
+}
+
+func TestKotlinGenerator_StringReturns(t *testing.T) {
+	ctx := loadTestAPI(t, "strings.yaml")
+	gen := &KotlinGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	kt := string(files[0].Content)
+	for _, want := range []string{
+		"fun getName(): String {\n        return StringsApi.nativeTextGetName(handle)\n    }",
+		"fun describe(key: String): String {\n        return StringsApi.nativeTextDescribe(handle, key)\n    }",
+		"suspend fun fetchLabel(): String {",
+		"return result!!",
+		"external fun nativeTextGetName(engine: Long): String",
+		"external fun nativeTextDescribe(engine: Long, key: String): String",
+		"external fun nativeTextFetchLabelPoll(op: Long, status: IntArray): String?",
+	} {
+		if !strings.Contains(kt, want) {
+			t.Errorf("Kotlin file missing %q", want)
+		}
+	}
+
+	jni := string(files[1].Content)
+	for _, want := range []string{
+		"static jstring take_string(JNIEnv *env, char *str) {",
+		"strings_api_string_free(str);",
+		"char* result = strings_api_text_get_name((engine_handle)engine);\n    return take_string(env, result);",
+		"int32_t rc = strings_api_text_describe((engine_handle)engine, c_key, &out_result);",
+	} {
+		if !strings.Contains(jni, want) {
+			t.Errorf("JNI file missing %q", want)
+		}
+	}
+
+	ctx = loadTestAPI(t, "minimal.yaml")
+	files, err = gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if strings.Contains(string(files[1].Content), "take_string") {
+		t.Error("take_string should only be emitted when strings are returned")
+	}
+}
+
+func TestKotlinGenerator_BufferReturns(t *testing.T) {
+	ctx := loadTestAPI(t, "buffers.yaml")
+	gen := &KotlinGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	kt := string(files[0].Content)
+	for _, want := range []string{
+		"fun readTensor(): FloatArray {\n        return BuffersApi.nativeMediaReadTensor(handle)\n    }",
+		"fun sampleIds(count: Int): LongArray {\n        return BuffersApi.nativeMediaSampleIds(handle, count)\n    }",
+		"external fun nativeMediaReadTensor(engine: Long): FloatArray",
+		"external fun nativeMediaSampleIds(engine: Long, count: Int): LongArray",
+	} {
+		if !strings.Contains(kt, want) {
+			t.Errorf("Kotlin file missing %q", want)
+		}
+	}
+
+	jni := string(files[1].Content)
+	for _, want := range []string{
+		"JNIEXPORT jfloatArray JNICALL",
+		"float* out_result = NULL;\n    uint32_t out_result_len = 0;\n    buffers_api_media_read_tensor((engine_handle)engine, &out_result, &out_result_len);",
+		"jlongArray arr = (*env)->NewLongArray(env, (jsize)out_result_len);",
+		"(*env)->SetByteArrayRegion(env, arr, 0, (jsize)out_result_len, (const jbyte*)out_result);",
+		"buffers_api_buffer_free(out_result);\n    return arr;",
+	} {
+		if !strings.Contains(jni, want) {
+			t.Errorf("JNI file missing %q", want)
+		}
+	}
+}
+
+func TestKotlinGenerator_Optionals(t *testing.T) {
+	ctx := loadTestAPI(t, "optional.yaml")
+	gen := &KotlinGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	kt := string(files[0].Content)
+	for _, want := range []string{
+		"fun setLabel(label: String?) {",
+		"fun setLimit(limit: Int?) {",
+		"fun attach(parent: Engine?, config: ByteArray?) {",
+		"fun getLabel(): String? {",
+		"fun findChild(name: String): Engine? {",
+		"fun getLimit(): Int? {",
+		"fun getConfig(): Rendering.RendererConfig? {\n        return OptionalApi.nativeSceneGetConfig(handle)?.let { Rendering.RendererConfig.getRootAsRendererConfig(java.nio.ByteBuffer.wrap(it)) }\n",
+		"external fun nativeSceneSetLimit(engine: Long, hasLimit: Boolean, limit: Int): Int",
+		"external fun nativeSceneGetLimit(engine: Long): Int?",
+	} {
+		if !strings.Contains(kt, want) {
+			t.Errorf("Kotlin file missing %q", want)
+		}
+	}
+
+	jni := string(files[1].Content)
+	for _, want := range []string{
+		"static jstring take_optional_string(JNIEnv *env, char *str) {",
+		"const char *c_label = label ? (*env)->GetStringUTFChars(env, label, NULL) : NULL;",
+		"jboolean hasLimit, jint limit) {",
+		"(bool)hasLimit, (uint32_t)limit);",
+		"(*env)->FindClass(env, \"java/lang/Integer\");",
+		"    jbyte *c_config = config ? (*env)->GetByteArrayElements(env, config, NULL) : NULL;\n",
+		"    if (out_result == NULL) {\n        return NULL;\n    }\n    jbyteArray arr = (*env)->NewByteArray(env, (jsize)out_result_len);\n",
+	} {
+		if !strings.Contains(jni, want) {
+			t.Errorf("JNI file missing %q", want)
+		}
+	}
+}
+
+func TestKotlinGenerator_Extends(t *testing.T) {
+	ctx := loadTestAPI(t, "extends.yaml")
+	gen := &KotlinGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	kt := string(files[0].Content)
+	for _, want := range []string{
+		"open class Node internal constructor(internal val handle: Long) : AutoCloseable {",
+		"    fun setName(name: String) {\n        ExtendsApi.nativeNodeSetName(handle, name)",
+		"class Texture internal constructor(handle: Long) : Node(handle) {",
+		"class Mesh internal constructor(handle: Long) : Node(handle) {",
+		"class Engine internal constructor(internal val handle: Long) : AutoCloseable {",
+	} {
+		if !strings.Contains(kt, want) {
+			t.Errorf("Kotlin file missing %q", want)
+		}
+	}
+	if strings.Count(kt, "fun setName(") != 1 {
+		t.Error("inherited methods should only be declared on the base class")
+	}
+}
+
+func TestKotlinGenerator_Lifecycle(t *testing.T) {
+	ctx := loadTestAPI(t, "lifecycle.yaml")
+	gen := &KotlinGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+
+	kt := string(findOutputFile(t, files, "LifecycleApi.kt").Content)
+	for _, want := range []string{
+		"@file:Suppress(\"DEPRECATION\")\n@file:OptIn(ExperimentalLifecycleApi::class)\n\npackage lifecycle.api",
+		"@RequiresOptIn(message = ",
+		"annotation class ExperimentalLifecycleApi\n",
+		" * @since 1.0.0\n */\n@Deprecated(\"Sprites are drawn as textured quads now. Use Quad instead.\")\nclass Sprite internal constructor",
+		"@ExperimentalLifecycleApi\nclass Probe internal constructor",
+		"    /** @since 1.0.0 */\n    @Deprecated(\"Quads replace sprites. Use drawQuad instead.\")\n    fun drawSprite(x: Float) {",
+		"    @ExperimentalLifecycleApi\n    fun setVsync(enabled: Boolean) {",
+		"    @Deprecated(\"Pass options explicitly. Use createEngine instead.\")\n    fun createEngineLegacy(): Engine {",
+		"    @Deprecated(\"The legacy \\\"immediate\\\" path is retired\")\n    fun flush() {",
+	} {
+		if !strings.Contains(kt, want) {
+			t.Errorf("Kotlin file missing %q", want)
+		}
+	}
+
+	jni := string(findOutputFile(t, files, "lifecycle_api_jni.c").Content)
+	if !strings.Contains(jni, "#define LIFECYCLE_API_DEPRECATED(msg)\n#include \"lifecycle_api.h\"") {
+		t.Error("JNI bridge should silence deprecation warnings before including the header")
+	}
+}
+
+func TestKotlinGenerator_ThreadAffinity(t *testing.T) {
+	ctx := loadTestAPI(t, "threading.yaml")
+	gen := &KotlinGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	kt := string(findOutputFile(t, files, "ThreadingApi.kt").Content)
+
+	for _, want := range []string{
+		"import androidx.annotation.AnyThread\nimport androidx.annotation.MainThread\n",
+		"    @MainThread\n    fun setTitle(title: String) {",
+		"    @AnyThread\n    fun contentScale(): Float {",
+		"    @MainThread\n    override fun close() {\n        ThreadingApi.nativeWindowDestroyWindow(handle)",
+		"    /** Must be called on the thread that created the Session. */\n    fun send(value: Int) {",
+		"    /** Must be called on the thread that created the Session. */\n    override fun close() {",
+		"    /** The returned Session must only be used on the calling thread. */\n    fun createSession(): Session {",
+		"    @MainThread\n    fun showCount() {",
+	} {
+		if !strings.Contains(kt, want) {
+			t.Errorf("Kotlin file missing %q", want)
+		}
+	}
+	if strings.Contains(kt, "@WorkerThread") {
+		t.Error("creator affinity should not map to @WorkerThread")
+	}
+}
+
+func TestKotlinExperimentalAnnotationName(t *testing.T) {
+	if got := kotlinExperimentalAnnotationName("HelloXplatter"); got != "ExperimentalHelloXplatterApi" {
+		t.Errorf("got %q", got)
+	}
+	if got := kotlinExperimentalAnnotationName("MediaApi"); got != "ExperimentalMediaApi" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestKotlinStringLiteral(t *testing.T) {
+	if got := kotlinStringLiteral(`costs $5 "now"`); got != `"costs \$5 \"now\""` {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestKotlinGenerator_Ownership(t *testing.T) {
+	ctx := loadTestAPI(t, "ownership.yaml")
+	gen := &KotlinGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(findOutputFile(t, files, "OwnershipApi.kt").Content)
+
+	for _, want := range []string{
+		"open class Node internal constructor(handle: Long, private val owned: Boolean = true) : AutoCloseable {\n    private var nativeHandle = handle\n",
+		"class Texture internal constructor(handle: Long, owned: Boolean = true) : Node(handle, owned) {",
+		"    internal fun take(): Long {\n        check(owned) {",
+		"        val rc = OwnershipApi.nativeSceneAttach(handle, node.take())",
+		"        return Node(OwnershipApi.nativeSceneRootNode(handle), owned = false)",
+		"        return if (result[1] == 0L) null else Texture(result[1], owned = false)",
+		"OwnershipApi.nativeScenePreloadStart(handle, texture.take())",
+		"        OwnershipApi.nativeTextureAdoptMaterial(handle, material?.take() ?: 0L)",
+		"    /** Takes ownership of this Texture, which cannot be used afterwards. */\n    fun consume(scene: Scene) {\n        OwnershipApi.nativeTextureConsume(take(), scene.handle)",
+		"        val released = release()\n        if (released != 0L) OwnershipApi.nativeTextureDestroyTexture(released)",
+		"class Scene internal constructor(internal val handle: Long) : AutoCloseable {",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Kotlin file missing %q", want)
+		}
+	}
+}
+
+func TestKotlinGenerator_Tables(t *testing.T) {
+	ctx := loadTestAPI(t, "fields.yaml")
+	gen := &KotlinGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	kt := string(findOutputFile(t, files, "FieldsApi.kt").Content)
+	jni := string(findOutputFile(t, files, "fields_api_jni.c").Content)
+
+	for _, want := range []string{
+		"    fun reconfigure(config: ByteArray) {\n",
+		"    fun info(): Fields.StreamInfo {\n        return Fields.StreamInfo.getRootAsStreamInfo(java.nio.ByteBuffer.wrap(FieldsApi.nativeStreamInfo(handle)))\n",
+		"external fun nativeStreamInfo(stream: Long): ByteArray\n",
+	} {
+		if !strings.Contains(kt, want) {
+			t.Errorf("Kotlin output missing %q", want)
+		}
+	}
+	if strings.Contains(kt, "data class FieldsStream") {
+		t.Error("tables are read with flatc, not as data classes")
+	}
+	for _, want := range []string{
+		"    jbyte *c_config = (*env)->GetByteArrayElements(env, config, NULL);\n    jsize c_config_len = (*env)->GetArrayLength(env, config);\n",
+		"fields_api_stream_reconfigure((stream_handle)stream, (const uint8_t*)c_config, (uint32_t)c_config_len);\n    (*env)->ReleaseByteArrayElements(env, config, c_config, JNI_ABORT);\n",
+		"JNIEXPORT jbyteArray JNICALL\nJava_fields_api_FieldsApi_nativeStreamInfo(JNIEnv *env, jobject thiz, jlong stream) {\n    uint8_t* out_result = NULL;\n    uint32_t out_result_len = 0;\n",
+		"    fields_api_buffer_free(out_result);\n    return arr;\n",
+	} {
+		if !strings.Contains(jni, want) {
+			t.Errorf("JNI output missing %q", want)
+		}
+	}
+}
+
+func TestKotlinGenerator_BitFlags(t *testing.T) {
+	ctx := loadTestAPI(t, "flags.yaml")
+	gen := &KotlinGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	kt := string(findOutputFile(t, files, "FlagsApi.kt").Content)
+
+	for _, want := range []string{
+		"@JvmInline\nvalue class MediaCaps(val value: Int) {\n    infix fun or(other: MediaCaps) = MediaCaps(value or other.value)\n",
+		"    operator fun contains(other: MediaCaps) = (value and other.value) == other.value\n",
+		"        val NONE = MediaCaps(0)\n        val READ = MediaCaps(1)\n        val WRITE = MediaCaps(2)\n        val HAS_AUDIO = MediaCaps(16)\n        val HDR = MediaCaps(32)\n",
+		"value class MediaChannels(val value: Long) {",
+	} {
+		if !strings.Contains(kt, want) {
+			t.Errorf("Kotlin output missing %q", want)
+		}
+	}
+}
+
+func TestKotlinGenerator_Unions(t *testing.T) {
+	ctx := loadTestAPI(t, "unions.yaml")
+	gen := &KotlinGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	kt := string(findOutputFile(t, files, "UnionsApi.kt").Content)
+	jni := string(findOutputFile(t, files, "unions_api_jni.c").Content)
+
+	for _, want := range []string{
+		"sealed class SceneShape {\n    data class Circle(val value: ByteArray) : SceneShape()\n    data class Rect(val value: SceneRect) : SceneShape()\n    data class Caption(val value: ByteArray) : SceneShape()\n    object None : SceneShape()\n}\n",
+		"data class SceneRect(val width: Float, val height: Float)\n",
+		"    fun lastShape(): SceneShape {\n",
+	} {
+		if !strings.Contains(kt, want) {
+			t.Errorf("Kotlin output missing %q", want)
+		}
+	}
+	for _, want := range []string{
+		"    switch (out_result.type) {\n    case Scene_Shape_Circle: {\n",
+		"        jobject member = (*env)->NewObject(env, member_cls, member_ctor, (jfloat)out_result.value.Rect.width, (jfloat)out_result.value.Rect.height);\n",
+		"    case Scene_Shape_Caption: {\n        jbyteArray member = (*env)->NewByteArray(env, (jsize)out_result.table_len);\n",
+		"        unions_api_buffer_free((void*)out_result.table);\n        jclass cls = (*env)->FindClass(env, \"unions/api/SceneShape$Caption\");\n        jmethodID ctor = (*env)->GetMethodID(env, cls, \"<init>\", \"([B)V\");\n",
+		"        jclass cls = (*env)->FindClass(env, \"unions/api/SceneShape$None\");\n",
+	} {
+		if !strings.Contains(jni, want) {
+			t.Errorf("JNI output missing %q", want)
+		}
+	}
+}
+
+func TestKotlinGenerator_FixedArrays(t *testing.T) {
+	ctx := loadTestAPI(t, "arrays.yaml")
+	gen := &KotlinGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	kt := string(findOutputFile(t, files, "ArraysApi.kt").Content)
+	jni := string(findOutputFile(t, files, "arrays_api_jni.c").Content)
+
+	for _, want := range []string{
+		"data class GeoMat4(val m: FloatArray) {\n    init {\n        require(m.size == 16) { \"m must hold 16 elements, got ${m.size}\" }\n    }\n}\n",
+		"data class GeoBounds(val layer: Byte, val min: DoubleArray, val max: DoubleArray) {\n",
+	} {
+		if !strings.Contains(kt, want) {
+			t.Errorf("Kotlin output missing %q", want)
+		}
+	}
+	for _, want := range []string{
+		"    jfloatArray j_m = (*env)->NewFloatArray(env, 16);\n    (*env)->SetFloatArrayRegion(env, j_m, 0, 16, (const jfloat*)out_result.m);\n",
+		"\"<init>\", \"([F)V\");\n    return (*env)->NewObject(env, cls, ctor, j_m);\n",
+		"\"(B[D[D)V\");\n    return (*env)->NewObject(env, cls, ctor, (jbyte)out_result.layer, j_min, j_max);\n",
+	} {
+		if !strings.Contains(jni, want) {
+			t.Errorf("JNI output missing %q", want)
+		}
+	}
+}
+
+func TestKotlinGenerator_StructParams(t *testing.T) {
+	// Struct parameters cross JNI as data classes, read into a C struct field
+	// by field; a ref_mut struct has its arrays copied back after the call.
+	ctx := loadTestAPI(t, "arrays.yaml")
+	iface := &ctx.API.Interfaces[0]
+	iface.Methods = append(iface.Methods, model.MethodDef{
+		Name: "grow",
+		Parameters: []model.ParameterDef{
+			{Name: "node", Type: "handle:Node"},
+			{Name: "bounds", Type: "Geo.Bounds", Transfer: "ref_mut", Struct: true},
+		},
+	})
+	files, err := (&KotlinGenerator{}).Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	kt := string(findOutputFile(t, files, "ArraysApi.kt").Content)
+	jni := string(findOutputFile(t, files, "arrays_api_jni.c").Content)
+
+	for _, want := range []string{
+		"    fun setTransform(transform: GeoMat4) {\n",
+		"    external fun nativeNodeSetTransform(node: Long, transform: GeoMat4): Int\n",
+		"    fun grow(bounds: GeoBounds) {\n",
+	} {
+		if !strings.Contains(kt, want) {
+			t.Errorf("Kotlin output missing %q", want)
+		}
+	}
+	for _, want := range []string{
+		"nativeNodeSetTransform(JNIEnv *env, jobject thiz, jlong node, jobject transform) {\n    Geo_Mat4 c_transform = {0};\n    jfloatArray j_transform_m = NULL;\n",
+		"    j_transform_m = (jfloatArray)(*env)->GetObjectField(env, transform, (*env)->GetFieldID(env, cls_transform, \"m\", \"[F\"));\n    (*env)->GetFloatArrayRegion(env, j_transform_m, 0, 16, (jfloat*)c_transform.m);\n",
+		"arrays_api_node_set_transform((node_handle)node, &c_transform);\n",
+		"    c_bounds.layer = (uint8_t)(*env)->GetByteField(env, bounds, (*env)->GetFieldID(env, cls_bounds, \"layer\", \"B\"));\n",
+		"    arrays_api_node_grow((node_handle)node, &c_bounds);\n    (*env)->SetDoubleArrayRegion(env, j_bounds_min, 0, 3, (const jdouble*)c_bounds.min);\n",
+	} {
+		if !strings.Contains(jni, want) {
+			t.Errorf("JNI output missing %q", want)
+		}
+	}
+	if strings.Contains(jni, "jbyteArray transform") {
+		t.Error("struct parameter should not cross JNI as a byte array")
+	}
+}
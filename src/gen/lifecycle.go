@@ -0,0 +1,130 @@
+package gen
+
+import (
+	"strings"
+
+	"github.com/benn-herrera/xplatter/model"
+)
+
+// inheritLifecycle returns a copy of api in which every constructor and method
+// carries the lifecycle annotations of its interface wherever it declares none
+// of its own. Bindings have no interface types, so this is how a deprecated or
+// experimental interface reaches its consumers. An interface's replacement
+// names another interface and is not inherited.
+func inheritLifecycle(api *model.APIDefinition) *model.APIDefinition {
+	if api == nil {
+		return nil
+	}
+	out := *api
+	out.Interfaces = make([]model.InterfaceDef, len(api.Interfaces))
+	for i, iface := range api.Interfaces {
+		iface.Constructors = inheritMethodLifecycle(iface.Lifecycle, iface.Constructors)
+		iface.Methods = inheritMethodLifecycle(iface.Lifecycle, iface.Methods)
+		out.Interfaces[i] = iface
+	}
+	return &out
+}
+
+func inheritMethodLifecycle(from model.Lifecycle, methods []model.MethodDef) []model.MethodDef {
+	if methods == nil {
+		return nil
+	}
+	out := make([]model.MethodDef, len(methods))
+	for i, method := range methods {
+		if method.Since == "" {
+			method.Since = from.Since
+		}
+		if method.Deprecated == nil && from.Deprecated != nil {
+			method.Deprecated = &model.Deprecation{Message: from.Deprecated.Message}
+		}
+		if method.Stability == "" {
+			method.Stability = from.Stability
+		}
+		out[i] = method
+	}
+	return out
+}
+
+// hasDeprecations reports whether any constructor or method is deprecated.
+func hasDeprecations(api *model.APIDefinition) bool {
+	for _, iface := range api.Interfaces {
+		if iface.Deprecated != nil {
+			return true
+		}
+		for _, ctor := range iface.Constructors {
+			if ctor.Deprecated != nil {
+				return true
+			}
+		}
+		for _, method := range iface.Methods {
+			if method.Deprecated != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasDeprecatedHandles reports whether any handle is deprecated.
+func hasDeprecatedHandles(api *model.APIDefinition) bool {
+	for _, h := range api.Handles {
+		if h.Deprecated != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasExperimental reports whether any handle, constructor or method is
+// experimental.
+func hasExperimental(api *model.APIDefinition) bool {
+	for _, h := range api.Handles {
+		if h.IsExperimental() {
+			return true
+		}
+	}
+	for _, iface := range api.Interfaces {
+		for _, ctor := range iface.Constructors {
+			if ctor.IsExperimental() {
+				return true
+			}
+		}
+		for _, method := range iface.Methods {
+			if method.IsExperimental() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// deprecationText returns a deprecation's message as a single line, followed by
+// a pointer to its replacement spelled by name in the target language.
+func deprecationText(d *model.Deprecation, name func(string) string) string {
+	text := strings.Join(strings.Fields(d.Message), " ")
+	if d.Replacement != "" {
+		if text != "" && !strings.HasSuffix(text, ".") {
+			text += "."
+		}
+		text += " Use " + name(d.Replacement) + " instead."
+	}
+	return strings.TrimSpace(text)
+}
+
+// sameName spells a replacement exactly as declared, for handle names.
+func sameName(name string) string { return name }
+
+// experimentalDetail explains what experimental means in generated docs, and
+// experimentalNote is the doc text for experimental API elements.
+const (
+	experimentalDetail = "May change or be removed without a deprecation period."
+	experimentalNote   = "Experimental: " + experimentalDetail
+)
+
+// quoteLiteral returns s as a double-quoted string literal for C-family
+// languages, escaping backslashes and quotes.
+func quoteLiteral(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,66 @@
+package gen
+
+import (
+	"testing"
+
+	"github.com/benn-herrera/xplatter/model"
+)
+
+func TestInheritLifecycle(t *testing.T) {
+	api := &model.APIDefinition{
+		Interfaces: []model.InterfaceDef{{
+			Name: "legacy",
+			Lifecycle: model.Lifecycle{
+				Since:      "1.0.0",
+				Deprecated: &model.Deprecation{Message: "Retired", Replacement: "modern"},
+				Stability:  model.StabilityExperimental,
+			},
+			Methods: []model.MethodDef{
+				{Name: "flush"},
+				{Name: "reset", Lifecycle: model.Lifecycle{
+					Since:      "1.1.0",
+					Deprecated: &model.Deprecation{Message: "Use flush", Replacement: "flush"},
+					Stability:  "stable",
+				}},
+			},
+		}},
+	}
+
+	got := inheritLifecycle(api)
+	flush := got.Interfaces[0].Methods[0]
+	if flush.Since != "1.0.0" || !flush.IsExperimental() {
+		t.Errorf("flush should inherit since and stability, got %+v", flush.Lifecycle)
+	}
+	if flush.Deprecated == nil || flush.Deprecated.Message != "Retired" || flush.Deprecated.Replacement != "" {
+		t.Errorf("flush should inherit the deprecation message but not the interface replacement, got %+v", flush.Deprecated)
+	}
+	reset := got.Interfaces[0].Methods[1]
+	if reset.Since != "1.1.0" || reset.IsExperimental() || reset.Deprecated.Replacement != "flush" {
+		t.Errorf("reset should keep its own annotations, got %+v", reset.Lifecycle)
+	}
+	if api.Interfaces[0].Methods[0].Deprecated != nil {
+		t.Error("inheritLifecycle must not modify its input")
+	}
+}
+
+func TestDeprecationText(t *testing.T) {
+	tests := []struct {
+		d    model.Deprecation
+		want string
+	}{
+		{model.Deprecation{Message: "Gone"}, "Gone"},
+		{model.Deprecation{Message: "Gone", Replacement: "draw_quad"}, "Gone. Use drawQuad instead."},
+		{model.Deprecation{Message: "Gone.\n  Really", Replacement: "draw_quad"}, "Gone. Really. Use drawQuad instead."},
+	}
+	for _, tt := range tests {
+		if got := deprecationText(&tt.d, ToCamelCase); got != tt.want {
+			t.Errorf("deprecationText(%+v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteLiteral(t *testing.T) {
+	if got := quoteLiteral(`say "hi" \o/`); got != `"say \"hi\" \\o/"` {
+		t.Errorf("got %s", got)
+	}
+}
@@ -6,10 +6,11 @@ import (
 	"strings"
 
 	"github.com/benn-herrera/xplatter/model"
+	"github.com/benn-herrera/xplatter/resolver"
 )
 
 // computeWASMExportNames returns the raw WASM export function names (without JSON quoting).
-func computeWASMExportNames(apiName string, api *model.APIDefinition) []string {
+func computeWASMExportNames(apiName string, api *model.APIDefinition, resolved resolver.ResolvedTypes) []string {
 	names := []string{"_malloc", "_free"}
 	for _, iface := range api.Interfaces {
 		for _, ctor := range iface.Constructors {
@@ -19,16 +20,33 @@ func computeWASMExportNames(apiName string, api *model.APIDefinition) []string {
 			names = append(names, "_"+CABIFunctionName(apiName, iface.Name, DestructorMethodName(handleName)))
 		}
 		for _, method := range iface.Methods {
+			if method.Async {
+				names = append(names,
+					"_"+AsyncStartFunctionName(apiName, iface.Name, method.Name),
+					"_"+AsyncPollFunctionName(apiName, iface.Name, method.Name),
+					"_"+AsyncCancelFunctionName(apiName, iface.Name, method.Name))
+				continue
+			}
 			names = append(names, "_"+CABIFunctionName(apiName, iface.Name, method.Name))
 		}
 	}
+	names = append(names, "_"+ABIInfoFunctionName(apiName))
+	if hasStringReturns(api) {
+		names = append(names, "_"+StringFreeFunctionName(apiName))
+	}
+	if hasBufferReturns(api, resolved) {
+		names = append(names, "_"+BufferFreeFunctionName(apiName))
+	}
+	if len(api.Events) > 0 {
+		names = append(names, "_"+EventPollFunctionName(apiName), "_"+EventSignalFDFunctionName(apiName))
+	}
 	return names
 }
 
 // ComputeWASMExports returns the WASM export function names as a JSON array string
 // for Emscripten's -s EXPORTED_FUNCTIONS. Includes _malloc, _free, and all C ABI function names.
-func ComputeWASMExports(apiName string, api *model.APIDefinition) string {
-	names := computeWASMExportNames(apiName, api)
+func ComputeWASMExports(apiName string, api *model.APIDefinition, resolved resolver.ResolvedTypes) string {
+	names := computeWASMExportNames(apiName, api, resolved)
 	quoted := make([]string, len(names))
 	for i, n := range names {
 		quoted[i] = `"` + n + `"`
@@ -38,8 +56,8 @@ func ComputeWASMExports(apiName string, api *model.APIDefinition) string {
 
 // ComputeWASMExportsCSV returns WASM export function names as a comma-separated string
 // for embedding in CMakeLists.txt EXPORTED_FUNCTIONS without JSON quoting issues.
-func ComputeWASMExportsCSV(apiName string, api *model.APIDefinition) string {
-	return strings.Join(computeWASMExportNames(apiName, api), ",")
+func ComputeWASMExportsCSV(apiName string, api *model.APIDefinition, resolved resolver.ResolvedTypes) string {
+	return strings.Join(computeWASMExportNames(apiName, api, resolved), ",")
 }
 
 // APIDefRelPath computes the relative path from the project root to the API definition file.
@@ -141,7 +159,8 @@ ANDROID_MIN_API := 28
 
 IOS_MIN := 15.0
 
-`)}
+`)
+}
 
 // MakefileMSVCDiscovery emits the MSVC toolchain discovery block.
 // Only needed for C and C++ impls which compile with cl.exe.
@@ -215,10 +234,27 @@ func MakefileBindingVars(b *strings.Builder, apiName, genPrefix string) {
 	fmt.Fprintf(b, "GEN_JNI_SOURCE     := $(GEN_DIR)$(API_NAME)_jni.c\n\n")
 }
 
+// MakefileEventsTest emits the test-events target, which builds and runs the
+// generated standalone event ring test. Emits nothing when no events are declared.
+func MakefileEventsTest(b *strings.Builder, api *model.APIDefinition, compiler, flags string) {
+	if len(api.Events) == 0 {
+		return
+	}
+	fmt.Fprintf(b, `# Event ring self-test (POSIX hosts)
+.PHONY: test-events
+
+test-events: $(STAMP)
+	@mkdir -p $(BUILD_DIR)
+	%[1]s %[2]s -o $(BUILD_DIR)/$(API_NAME)_events_test $(GEN_DIR)$(API_NAME)_events_test.c -lpthread
+	./$(BUILD_DIR)/$(API_NAME)_events_test
+
+`, compiler, flags)
+}
+
 // MakefileWASMExports emits the WASM_EXPORTS variable.
-func MakefileWASMExports(b *strings.Builder, apiName string, api *model.APIDefinition) {
+func MakefileWASMExports(b *strings.Builder, apiName string, api *model.APIDefinition, resolved resolver.ResolvedTypes) {
 	b.WriteString("# ── WASM exports (computed from API definition) ──────────────────────────────\n\n")
-	fmt.Fprintf(b, "WASM_EXPORTS := %s\n\n", ComputeWASMExports(apiName, api))
+	fmt.Fprintf(b, "WASM_EXPORTS := %s\n\n", ComputeWASMExports(apiName, api, resolved))
 }
 
 // MakefileCodegenStamp emits the STAMP rule that reruns xplatter generate.
@@ -22,7 +22,7 @@ func checkWASMRules(t *testing.T, content string) {
 
 func TestComputeWASMExports_Minimal(t *testing.T) {
 	ctx := loadTestAPI(t, "minimal.yaml")
-	exports := ComputeWASMExports(ctx.API.API.Name, ctx.API)
+	exports := ComputeWASMExports(ctx.API.API.Name, ctx.API, ctx.ResolvedTypes)
 
 	if !strings.HasPrefix(exports, "[") || !strings.HasSuffix(exports, "]") {
 		t.Errorf("expected JSON array, got %q", exports)
@@ -43,7 +43,7 @@ func TestComputeWASMExports_Minimal(t *testing.T) {
 
 func TestComputeWASMExports_Full(t *testing.T) {
 	ctx := loadTestAPI(t, "full.yaml")
-	exports := ComputeWASMExports(ctx.API.API.Name, ctx.API)
+	exports := ComputeWASMExports(ctx.API.API.Name, ctx.API, ctx.ResolvedTypes)
 
 	expected := []string{
 		`"_malloc"`,
@@ -324,3 +324,77 @@ func TestMakefileAggregateTargets(t *testing.T) {
 		t.Error("missing build alias")
 	}
 }
+
+func TestComputeWASMExports_Events(t *testing.T) {
+	ctx := loadTestAPI(t, "events.yaml")
+	result := ComputeWASMExports(ctx.API.API.Name, ctx.API, ctx.ResolvedTypes)
+
+	for _, want := range []string{`"_event_api_event_poll"`, `"_event_api_event_signal_fd"`} {
+		if !strings.Contains(result, want) {
+			t.Errorf("WASM exports missing %s: %s", want, result)
+		}
+	}
+}
+
+func TestMakefileEventsTest(t *testing.T) {
+	var b strings.Builder
+	MakefileEventsTest(&b, loadTestAPI(t, "minimal.yaml").API, "$(CC)", "-std=c17")
+	if b.Len() != 0 {
+		t.Error("test-events target should only be emitted when events are declared")
+	}
+
+	MakefileEventsTest(&b, loadTestAPI(t, "events.yaml").API, "$(CC)", "-std=c17")
+	content := b.String()
+	if !strings.Contains(content, "test-events: $(STAMP)") {
+		t.Error("missing test-events target")
+	}
+	if !strings.Contains(content, "$(CC) -std=c17 -o $(BUILD_DIR)/$(API_NAME)_events_test $(GEN_DIR)$(API_NAME)_events_test.c") {
+		t.Error("test-events should compile the generated events test")
+	}
+}
+
+func TestComputeWASMExports_Async(t *testing.T) {
+	ctx := loadTestAPI(t, "async.yaml")
+	result := ComputeWASMExports(ctx.API.API.Name, ctx.API, ctx.ResolvedTypes)
+
+	for _, want := range []string{
+		`"_async_api_assets_load_model_start"`,
+		`"_async_api_assets_load_model_poll"`,
+		`"_async_api_assets_load_model_cancel"`,
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("WASM exports missing %s: %s", want, result)
+		}
+	}
+	if strings.Contains(result, `"_async_api_assets_load_model"`) {
+		t.Error("async methods have no synchronous export")
+	}
+}
+
+func TestComputeWASMExports_StringReturns(t *testing.T) {
+	ctx := loadTestAPI(t, "strings.yaml")
+	result := ComputeWASMExports(ctx.API.API.Name, ctx.API, ctx.ResolvedTypes)
+	if !strings.Contains(result, `"_strings_api_string_free"`) {
+		t.Errorf("WASM exports missing string free: %s", result)
+	}
+
+	ctx = loadTestAPI(t, "minimal.yaml")
+	result = ComputeWASMExports(ctx.API.API.Name, ctx.API, ctx.ResolvedTypes)
+	if strings.Contains(result, "_string_free") {
+		t.Error("string free should only be exported when strings are returned")
+	}
+}
+
+func TestComputeWASMExports_BufferReturns(t *testing.T) {
+	ctx := loadTestAPI(t, "buffers.yaml")
+	result := ComputeWASMExports(ctx.API.API.Name, ctx.API, ctx.ResolvedTypes)
+	if !strings.Contains(result, `"_buffers_api_buffer_free"`) {
+		t.Errorf("WASM exports missing buffer free: %s", result)
+	}
+
+	ctx = loadTestAPI(t, "minimal.yaml")
+	result = ComputeWASMExports(ctx.API.API.Name, ctx.API, ctx.ResolvedTypes)
+	if strings.Contains(result, "_buffer_free") {
+		t.Error("buffer free should only be exported when buffers are returned")
+	}
+}
@@ -0,0 +1,211 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/benn-herrera/xplatter/model"
+	"github.com/benn-herrera/xplatter/resolver"
+)
+
+// namespaceTarget picks one language's field of a namespace mapping.
+type namespaceTarget func(ns *model.NamespaceDef) string
+
+func kotlinTarget(ns *model.NamespaceDef) string { return ns.Kotlin }
+func swiftTarget(ns *model.NamespaceDef) string  { return ns.Swift }
+func jsTarget(ns *model.NamespaceDef) string     { return ns.JS }
+func rustTarget(ns *model.NamespaceDef) string   { return ns.Rust }
+func goTarget(ns *model.NamespaceDef) string     { return ns.Go }
+
+// mappedNamespaces returns the namespace mappings that set target, in
+// declaration order.
+func mappedNamespaces(api *model.APIDefinition, target namespaceTarget) []model.NamespaceDef {
+	var mapped []model.NamespaceDef
+	for i := range api.Namespaces {
+		if target(&api.Namespaces[i]) != "" {
+			mapped = append(mapped, api.Namespaces[i])
+		}
+	}
+	return mapped
+}
+
+// typeTarget returns where a language puts the code generated for the
+// FlatBuffer type t: the value its namespace is mapped to, or "" when the
+// binding's own file holds it.
+func typeTarget(api *model.APIDefinition, target namespaceTarget, t string) string {
+	if ns := api.NamespaceByName(model.FlatBufferNamespace(t)); ns != nil {
+		return target(ns)
+	}
+	return ""
+}
+
+// inTarget returns a filter selecting the FlatBuffer types a language puts
+// at value; "" selects the types of every unmapped namespace.
+func inTarget(api *model.APIDefinition, target namespaceTarget, value string) func(t string) bool {
+	return func(t string) bool {
+		return typeTarget(api, target, t) == value
+	}
+}
+
+// namespaceFileName returns the PascalCase file stem of a namespace,
+// e.g. "Acme.Net" → "AcmeNet".
+func namespaceFileName(ns string) string {
+	return strings.ReplaceAll(ns, ".", "")
+}
+
+// kotlinPackages resolves the Kotlin package of each class generated for a
+// FlatBuffer type: the package its namespace is mapped to, or the binding's.
+type kotlinPackages struct {
+	binding string
+	api     *model.APIDefinition
+}
+
+// of returns the package of the class generated for t.
+func (p kotlinPackages) of(t string) string {
+	if pkg := typeTarget(p.api, kotlinTarget, t); pkg != "" {
+		return pkg
+	}
+	return p.binding
+}
+
+// classPath returns the JNI path of a class generated for t,
+// e.g. "com/acme/scene/SceneShape$Circle".
+func (p kotlinPackages) classPath(t, className string) string {
+	return strings.ReplaceAll(p.of(t), ".", "/") + "/" + className
+}
+
+// swiftTypeName returns the Swift name of the type generated for the
+// FlatBuffer type t: its namespace's prefix and its own name when the
+// namespace is mapped, e.g. "Scene.Shape" → "ScnShape", or else its
+// namespace and name run together, "SceneShape".
+func swiftTypeName(api *model.APIDefinition, t string) string {
+	if prefix := typeTarget(api, swiftTarget, t); prefix != "" {
+		return prefix + t[strings.LastIndex(t, ".")+1:]
+	}
+	return strings.ReplaceAll(t, ".", "")
+}
+
+// rustTypeModules returns the modules namespaces map to that declare at
+// least one of the resolved types, in declaration order.
+func rustTypeModules(api *model.APIDefinition, resolved resolver.ResolvedTypes) []string {
+	var modules []string
+	for _, ns := range mappedNamespaces(api, rustTarget) {
+		for name := range resolved {
+			if typeTarget(api, rustTarget, name) == ns.Rust {
+				modules = append(modules, ns.Rust)
+				break
+			}
+		}
+	}
+	return modules
+}
+
+// indentLines prefixes each non-empty line of s with indent, ending the
+// result with a newline.
+func indentLines(s, indent string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(s, "\n") {
+		if line != "" {
+			b.WriteString(indent)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// goSubPackagePath returns the import path of the Go package a namespace is
+// mapped to, which lives under the generated directory of the module.
+func goSubPackagePath(apiName, pkgPath string) string {
+	return goModulePath(apiName) + "/generated/" + pkgPath
+}
+
+// goSubPackageName returns the name of the Go package at pkgPath, its last
+// element.
+func goSubPackageName(pkgPath string) string {
+	return path.Base(pkgPath)
+}
+
+// goNamespaceDeps returns the other Go packages whose types the types mapped
+// to pkgPath refer to, sorted.
+func goNamespaceDeps(api *model.APIDefinition, resolved resolver.ResolvedTypes, pkgPath string) []string {
+	deps := map[string]bool{}
+	for name, info := range resolved {
+		if typeTarget(api, goTarget, name) != pkgPath {
+			continue
+		}
+		var refs []string
+		for _, f := range info.Fields {
+			refs = append(refs, resolver.FieldTypeName(f.Type))
+		}
+		for _, m := range info.Members {
+			refs = append(refs, m.Type)
+		}
+		for _, ref := range refs {
+			refInfo, ok := resolved[ref]
+			if !ok || refInfo.Kind == resolver.TypeKindEnum {
+				continue
+			}
+			if dep := typeTarget(api, goTarget, ref); dep != "" && dep != pkgPath {
+				deps[dep] = true
+			}
+		}
+	}
+	var sorted []string
+	for dep := range deps {
+		sorted = append(sorted, dep)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// writeGoAliases writes an alias in the main package for each exported
+// type, constant and variable the Go source src declares, so the main
+// package uses the types of a mapped namespace under their own names.
+func writeGoAliases(b *strings.Builder, pkgName, src string) error {
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return err
+	}
+	var types, consts []string
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				if spec.Name.IsExported() {
+					types = append(types, spec.Name.Name)
+				}
+			case *ast.ValueSpec:
+				for _, name := range spec.Names {
+					if name.IsExported() {
+						consts = append(consts, name.Name)
+					}
+				}
+			}
+		}
+	}
+	if len(types) > 0 {
+		b.WriteString("type (\n")
+		for _, name := range types {
+			fmt.Fprintf(b, "\t%s = %s.%s\n", name, pkgName, name)
+		}
+		b.WriteString(")\n\n")
+	}
+	if len(consts) > 0 {
+		b.WriteString("const (\n")
+		for _, name := range consts {
+			fmt.Fprintf(b, "\t%s = %s.%s\n", name, pkgName, name)
+		}
+		b.WriteString(")\n\n")
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/benn-herrera/xplatter/model"
+)
+
+func TestSwiftTypeName(t *testing.T) {
+	api := &model.APIDefinition{Namespaces: []model.NamespaceDef{{Name: "Acme.Scene", Swift: "Scn"}}}
+	tests := []struct{ in, want string }{
+		{"Acme.Scene.Shape", "ScnShape"},
+		{"Acme.Net.Packet", "AcmeNetPacket"},
+		{"Scene.Shape", "SceneShape"},
+	}
+	for _, tt := range tests {
+		if got := swiftTypeName(api, tt.in); got != tt.want {
+			t.Errorf("swiftTypeName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIndentLines(t *testing.T) {
+	if got, want := indentLines("a\n\nb", "  "), "  a\n\n  b\n"; got != want {
+		t.Errorf("indentLines = %q, want %q", got, want)
+	}
+}
+
+func TestNamespaces_Kotlin(t *testing.T) {
+	ctx := loadTestAPI(t, "namespaces.yaml")
+	files, err := (&KotlinGenerator{}).Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	kt := string(findOutputFile(t, files, "NamespacesApi.kt").Content)
+	scene := string(findOutputFile(t, files, "com/acme/scene/Scene.kt").Content)
+	media := string(findOutputFile(t, files, "com/acme/media/Media.kt").Content)
+	jni := string(findOutputFile(t, files, "namespaces_api_jni.c").Content)
+
+	if want := "package namespaces.api\n\nimport com.acme.scene.*\nimport com.acme.media.*\n"; !strings.Contains(kt, want) {
+		t.Errorf("Kotlin binding missing %q", want)
+	}
+	for _, moved := range []string{"class SceneErrorCodeException", "sealed class SceneShape", "value class MediaCaps"} {
+		if strings.Contains(kt, moved) {
+			t.Errorf("Kotlin binding should not declare %q", moved)
+		}
+	}
+	for _, want := range []string{
+		"package com.acme.scene\n\nimport namespaces.api.*\nimport com.acme.media.*\n",
+		"class SceneErrorCodeException(val errorCode: Int)",
+		"sealed class SceneShape {",
+		"data class SceneRect(val width: Float, val height: Float)",
+	} {
+		if !strings.Contains(scene, want) {
+			t.Errorf("Scene.kt missing %q", want)
+		}
+	}
+	for _, want := range []string{"package com.acme.media\n", "class MediaErrorCodeException", "value class MediaCaps(val value: Int)"} {
+		if !strings.Contains(media, want) {
+			t.Errorf("Media.kt missing %q", want)
+		}
+	}
+	for _, want := range []string{
+		"Java_namespaces_api_NamespacesApi_nativeCanvasLastShape(",
+		"(*env)->FindClass(env, \"com/acme/scene/SceneShape$Rect\");",
+		"(*env)->FindClass(env, \"com/acme/scene/SceneRect\");",
+		"\"(Lcom/acme/scene/SceneRect;)V\"",
+		"(*env)->FindClass(env, \"com/acme/scene/SceneErrorCodeException\");",
+	} {
+		if !strings.Contains(jni, want) {
+			t.Errorf("JNI bridge missing %q", want)
+		}
+	}
+}
+
+func TestNamespaces_Swift(t *testing.T) {
+	ctx := loadTestAPI(t, "namespaces.yaml")
+	files, err := (&SwiftGenerator{}).Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	swift := string(findOutputFile(t, files, "NamespacesApi.swift").Content)
+	scn := string(findOutputFile(t, files, "NamespacesApi+Scn.swift").Content)
+
+	for _, want := range []string{"public enum ScnErrorCode: Int32, Error {", "public enum ScnShape {"} {
+		if !strings.Contains(scn, want) {
+			t.Errorf("NamespacesApi+Scn.swift missing %q", want)
+		}
+		if strings.Contains(swift, want) {
+			t.Errorf("Swift binding should not declare %q", want)
+		}
+	}
+	for _, want := range []string{
+		"public enum MediaErrorCode: Int32, Error {",
+		"public struct MediaCaps: OptionSet, Hashable {",
+		"throw ScnErrorCode(rawValue: code) ?? ScnErrorCode.internalError",
+		"public func draw(shape: ScnShape) throws {",
+		"public func lastShape() -> ScnShape? {",
+	} {
+		if !strings.Contains(swift, want) {
+			t.Errorf("Swift binding missing %q", want)
+		}
+	}
+}
+
+func TestNamespaces_JS(t *testing.T) {
+	ctx := loadTestAPI(t, "namespaces.yaml")
+	files, err := (&JSWASMGenerator{}).Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	js := string(findOutputFile(t, files, "namespaces_api.js").Content)
+	scene := string(findOutputFile(t, files, "namespaces_api_scene.js").Content)
+
+	if !strings.Contains(scene, "@typedef {{type: 'Circle', value: Uint8Array}") {
+		t.Error("scene sub-module missing the SceneShape typedef")
+	}
+	if strings.Contains(js, "@typedef {{type: 'Circle'") {
+		t.Error("main module should not document SceneShape")
+	}
+	for _, want := range []string{"export { MediaCaps };\n", "export * as scene from './namespaces_api_scene.js';\n"} {
+		if !strings.Contains(js, want) {
+			t.Errorf("main module missing %q", want)
+		}
+	}
+}
+
+func TestNamespaces_Rust(t *testing.T) {
+	ctx := loadTestAPI(t, "namespaces.yaml")
+	files, err := (&RustImplGenerator{}).Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	types := string(findOutputFile(t, files, "namespaces_api_types.rs").Content)
+	trait := string(findOutputFile(t, files, "namespaces_api_trait.rs").Content)
+
+	for _, want := range []string{
+		"pub mod scene {\n    #![allow(unused_imports)]\n    use super::*;\n    use super::media::*;\n\n",
+		"    pub enum SceneErrorCode {\n        Ok = 0,\n",
+		"    pub struct SceneShape {\n",
+		"pub mod media {\n    #![allow(unused_imports)]\n    use super::*;\n    use super::scene::*;\n",
+		"    pub struct MediaCaps(pub u32);\n",
+	} {
+		if !strings.Contains(types, want) {
+			t.Errorf("types file missing %q", want)
+		}
+	}
+	if strings.Contains(types, "\npub enum SceneErrorCode") {
+		t.Error("SceneErrorCode should be declared in the scene module")
+	}
+	want := "use crate::namespaces_api_types::*;\nuse crate::namespaces_api_types::scene::*;\nuse crate::namespaces_api_types::media::*;\n"
+	if !strings.Contains(trait, want) {
+		t.Errorf("trait file missing %q", want)
+	}
+}
+
+func TestNamespaces_Go(t *testing.T) {
+	ctx := loadTestAPI(t, "namespaces.yaml")
+	files, err := (&GoImplGenerator{}).Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	types := string(findOutputFile(t, files, "namespaces_api_types.go").Content)
+	scene := string(findOutputFile(t, files, "scene/scene.go").Content)
+
+	for _, want := range []string{
+		"package scene\n",
+		"\tSceneErrorCodeOk = 0\n",
+		"type SceneShape interface {\n\tisSceneShape()\n}\n",
+		"type SceneRect struct {\n",
+	} {
+		if !strings.Contains(scene, want) {
+			t.Errorf("scene package missing %q", want)
+		}
+	}
+	for _, want := range []string{
+		"import (\n\t\"namespaces-api/generated/scene\"\n)\n",
+		"\tSceneShape = scene.SceneShape\n",
+		"\tSceneShapeRect = scene.SceneShapeRect\n",
+		"\tSceneRect = scene.SceneRect\n",
+		"\tSceneErrorCodeOk = scene.SceneErrorCodeOk\n",
+		"\tMediaErrorCodeOk = 0\n",
+		"type MediaCaps uint32\n",
+	} {
+		if !strings.Contains(types, want) {
+			t.Errorf("main types file missing %q", want)
+		}
+	}
+	if strings.Contains(types, "type SceneRect struct") {
+		t.Error("SceneRect should be declared in the scene package")
+	}
+}
@@ -0,0 +1,51 @@
+package gen
+
+import "github.com/benn-herrera/xplatter/model"
+
+// PresenceFlagName returns the C parameter that flags whether an optional
+// by-value parameter is present.
+// e.g., "limit" → "has_limit"
+func PresenceFlagName(paramName string) string {
+	return "has_" + paramName
+}
+
+// hasPresenceFlag reports whether p is an optional primitive. Primitives cannot
+// be NULL, so they cross the C ABI as a bool presence flag followed by the value.
+// Optional strings, handles, tables, and FlatBuffer refs are NULL when absent.
+func hasPresenceFlag(p *model.ParameterDef) bool {
+	return p.Optional && model.IsPrimitive(p.Type)
+}
+
+// returnHasPresenceFlag reports whether a method's optional result is passed
+// out by value alongside a bool* out_has_result flag. Optional string, handle
+// and table results are NULL when absent instead.
+func returnHasPresenceFlag(method *model.MethodDef) bool {
+	r := method.Returns
+	if r == nil || !r.Optional || r.Table {
+		return false
+	}
+	return model.IsPrimitive(r.Type) || model.IsFlatBufferType(r.Type)
+}
+
+// isOptionalReturn reports whether a method declares an optional result.
+func isOptionalReturn(method *model.MethodDef) bool {
+	return method.Returns != nil && method.Returns.Optional
+}
+
+// hasOptionals reports whether any method takes an optional parameter or
+// declares an optional result.
+func hasOptionals(api *model.APIDefinition) bool {
+	for _, iface := range api.Interfaces {
+		for _, method := range iface.Methods {
+			if isOptionalReturn(&method) {
+				return true
+			}
+			for _, p := range method.Parameters {
+				if p.Optional {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package gen
+
+import (
+	"testing"
+
+	"github.com/benn-herrera/xplatter/model"
+)
+
+func TestPresenceFlagName(t *testing.T) {
+	if got := PresenceFlagName("limit"); got != "has_limit" {
+		t.Errorf("PresenceFlagName = %q", got)
+	}
+}
+
+func TestHasPresenceFlag(t *testing.T) {
+	tests := []struct {
+		param model.ParameterDef
+		want  bool
+	}{
+		{model.ParameterDef{Type: "uint32", Optional: true}, true},
+		{model.ParameterDef{Type: "bool", Optional: true}, true},
+		{model.ParameterDef{Type: "uint32"}, false},
+		{model.ParameterDef{Type: "string", Optional: true}, false},
+		{model.ParameterDef{Type: "handle:Engine", Optional: true}, false},
+		{model.ParameterDef{Type: "Rendering.RendererConfig", Transfer: "ref", Optional: true}, false},
+	}
+	for _, tt := range tests {
+		if got := hasPresenceFlag(&tt.param); got != tt.want {
+			t.Errorf("hasPresenceFlag(%+v) = %v, want %v", tt.param, got, tt.want)
+		}
+	}
+}
+
+func TestReturnHasPresenceFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		returns *model.ReturnDef
+		want    bool
+	}{
+		{"no return", nil, false},
+		{"required primitive", &model.ReturnDef{Type: "uint32"}, false},
+		{"optional primitive", &model.ReturnDef{Type: "uint32", Optional: true}, true},
+		{"optional flatbuffer", &model.ReturnDef{Type: "Rendering.RendererConfig", Optional: true}, true},
+		{"optional string", &model.ReturnDef{Type: "string", Optional: true}, false},
+		{"optional handle", &model.ReturnDef{Type: "handle:Engine", Optional: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := model.MethodDef{Returns: tt.returns}
+			if got := returnHasPresenceFlag(&method); got != tt.want {
+				t.Errorf("returnHasPresenceFlag = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasOptionals(t *testing.T) {
+	ctx := loadTestAPI(t, "optional.yaml")
+	if !hasOptionals(ctx.API) {
+		t.Error("optional.yaml declares optional parameters and returns")
+	}
+	ctx = loadTestAPI(t, "minimal.yaml")
+	if hasOptionals(ctx.API) {
+		t.Error("minimal.yaml declares no optionals")
+	}
+}
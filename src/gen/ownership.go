@@ -0,0 +1,120 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/benn-herrera/xplatter/model"
+)
+
+// isMoveParam reports whether a parameter passes ownership of its handle to
+// the callee.
+func isMoveParam(p *model.ParameterDef) bool {
+	_, ok := model.IsHandle(p.Type)
+	return ok && p.Transfer == "move"
+}
+
+// isBorrowedReturn reports whether a method returns a handle that stays owned
+// by the implementation.
+func isBorrowedReturn(method *model.MethodDef) bool {
+	return method.Returns != nil && method.Returns.Borrowed
+}
+
+// rootHandleName returns the handle at the top of handleName's extends chain.
+func rootHandleName(api *model.APIDefinition, handleName string) string {
+	for {
+		parent, ok := api.ParentHandleName(handleName)
+		if !ok {
+			return handleName
+		}
+		handleName = parent
+	}
+}
+
+// tracksOwnership reports whether the binding wrappers of handleName must
+// track whether they still own their handle: some method takes a handle of
+// its family by move or returns one borrowed. The whole family tracks it so
+// a derived wrapper can be passed where its base is moved.
+func tracksOwnership(api *model.APIDefinition, handleName string) bool {
+	root := rootHandleName(api, handleName)
+	family := func(t string) bool {
+		name, ok := model.IsHandle(t)
+		return ok && rootHandleName(api, name) == root
+	}
+	for _, iface := range api.Interfaces {
+		for _, methods := range [][]model.MethodDef{iface.Constructors, iface.Methods} {
+			for _, method := range methods {
+				for _, p := range method.Parameters {
+					if isMoveParam(&p) && family(p.Type) {
+						return true
+					}
+				}
+				if isBorrowedReturn(&method) && family(method.Returns.Type) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// ownershipNotes returns the C ABI doc text for a function that takes
+// ownership of handle parameters or returns a borrowed handle.
+func ownershipNotes(method *model.MethodDef) []string {
+	var notes []string
+	for _, p := range method.Parameters {
+		switch {
+		case !isMoveParam(&p):
+		case method.Error != "":
+			notes = append(notes, fmt.Sprintf("Takes ownership of %s, even when the call fails; do not use or destroy it afterwards.", p.Name))
+		default:
+			notes = append(notes, fmt.Sprintf("Takes ownership of %s; do not use or destroy it afterwards.", p.Name))
+		}
+	}
+	if note := borrowedReturnNote(method); note != "" {
+		notes = append(notes, note)
+	}
+	return notes
+}
+
+// borrowedReturnNote is the C ABI doc text for a borrowed handle return, or
+// "" if the method does not return one.
+func borrowedReturnNote(method *model.MethodDef) string {
+	if !isBorrowedReturn(method) {
+		return ""
+	}
+	handleName, _ := model.IsHandle(method.Returns.Type)
+	return fmt.Sprintf("The returned %s is borrowed from its owner; do not destroy it or use it after the owner is destroyed.", handleName)
+}
+
+// bindingOwnershipNotes is ownershipNotes for the binding wrappers, which
+// invalidate a moved wrapper and never destroy a borrowed one. name spells
+// parameter names in the binding's language, and receiver is set when the
+// first parameter is the wrapper the method is called on.
+func bindingOwnershipNotes(method *model.MethodDef, name func(string) string, receiver bool) []string {
+	var notes []string
+	for i, p := range method.Parameters {
+		if !isMoveParam(&p) {
+			continue
+		}
+		if i == 0 && receiver {
+			handleName, _ := model.IsHandle(p.Type)
+			notes = append(notes, fmt.Sprintf("Takes ownership of this %s, which cannot be used afterwards.", handleName))
+		} else {
+			notes = append(notes, fmt.Sprintf("Takes ownership of %s, which cannot be used afterwards.", name(p.Name)))
+		}
+	}
+	if isBorrowedReturn(method) {
+		handleName, _ := model.IsHandle(method.Returns.Type)
+		notes = append(notes, fmt.Sprintf("The returned %s is borrowed from its owner: releasing it does not destroy it, and it must not be used after the owner is released.", handleName))
+	}
+	return notes
+}
+
+// writeCOwnershipNotes writes a comment line for each ownership note of a
+// C ABI function or implementation method.
+func writeCOwnershipNotes(b *strings.Builder, indent string, method *model.MethodDef) {
+	for _, note := range ownershipNotes(method) {
+		fmt.Fprintf(b, "%s/* %s */\n", indent, note)
+	}
+}
@@ -0,0 +1,69 @@
+package gen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/benn-herrera/xplatter/model"
+)
+
+func TestTracksOwnership(t *testing.T) {
+	api := loadTestAPI(t, "ownership.yaml").API
+	// Texture is never borrowed or moved as itself, but its base Node is.
+	for handle, want := range map[string]bool{"Scene": false, "Node": true, "Texture": true, "Material": true} {
+		if got := tracksOwnership(api, handle); got != want {
+			t.Errorf("tracksOwnership(%s) = %v, want %v", handle, got, want)
+		}
+	}
+	if tracksOwnership(loadTestAPI(t, "extends.yaml").API, "Texture") {
+		t.Error("extends.yaml neither moves nor borrows handles")
+	}
+}
+
+func TestOwnershipNotes(t *testing.T) {
+	texture := model.ParameterDef{Name: "texture", Type: "handle:Texture", Transfer: "move"}
+	tests := []struct {
+		method model.MethodDef
+		want   []string
+	}{
+		{
+			model.MethodDef{Parameters: []model.ParameterDef{texture}},
+			[]string{"Takes ownership of texture; do not use or destroy it afterwards."},
+		},
+		{
+			model.MethodDef{Parameters: []model.ParameterDef{texture}, Error: "Common.ErrorCode"},
+			[]string{"Takes ownership of texture, even when the call fails; do not use or destroy it afterwards."},
+		},
+		{
+			model.MethodDef{Returns: &model.ReturnDef{Type: "handle:Node", Borrowed: true}},
+			[]string{"The returned Node is borrowed from its owner; do not destroy it or use it after the owner is destroyed."},
+		},
+		{
+			model.MethodDef{Parameters: []model.ParameterDef{{Name: "texture", Type: "handle:Texture"}}, Returns: &model.ReturnDef{Type: "handle:Node"}},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		if got := ownershipNotes(&tt.method); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ownershipNotes(%+v) = %q, want %q", tt.method, got, tt.want)
+		}
+	}
+}
+
+func TestBindingOwnershipNotes(t *testing.T) {
+	method := model.MethodDef{Parameters: []model.ParameterDef{
+		{Name: "texture", Type: "handle:Texture", Transfer: "move"},
+		{Name: "new_material", Type: "handle:Material", Transfer: "move"},
+	}}
+	want := []string{
+		"Takes ownership of this Texture, which cannot be used afterwards.",
+		"Takes ownership of newMaterial, which cannot be used afterwards.",
+	}
+	if got := bindingOwnershipNotes(&method, ToCamelCase, true); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	want[0] = "Takes ownership of texture, which cannot be used afterwards."
+	if got := bindingOwnershipNotes(&method, ToCamelCase, false); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,165 @@
+package gen
+
+import (
+	"sort"
+	"strings"
+)
+
+// reservedWords is the set of words a language does not accept as a
+// parameter or method name. API names are snake_case, so only lowercase
+// words, and the camelCase of single words, can collide.
+type reservedWords map[string]bool
+
+func words(s string) reservedWords {
+	set := make(reservedWords)
+	for _, w := range strings.Fields(s) {
+		set[w] = true
+	}
+	return set
+}
+
+// cReservedWords are the C11 and C23 keywords.
+var cReservedWords = words(`
+	alignas alignof auto bool break case char const constexpr continue default
+	do double else enum extern false float for goto if inline int long nullptr
+	register restrict return short signed sizeof static static_assert struct
+	switch thread_local true typedef typeof typeof_unqual union unsigned void
+	volatile while`)
+
+// cppReservedWords are the C++20 keywords and alternative operator names
+// beyond C's. C++ compiles the C header too.
+var cppReservedWords = words(`
+	and and_eq asm bitand bitor catch char8_t char16_t char32_t class co_await
+	co_return co_yield compl concept const_cast consteval constinit decltype
+	delete dynamic_cast explicit export friend mutable namespace new noexcept
+	not not_eq operator or or_eq private protected public reinterpret_cast
+	requires static_cast template this throw try typeid typename using virtual
+	wchar_t xor xor_eq`)
+
+// kotlinReservedWords are Kotlin's hard keywords.
+var kotlinReservedWords = words(`
+	as break class continue do else false for fun if in interface is null
+	object package return super this throw true try typealias typeof val var
+	when while`)
+
+// swiftReservedWords are the Swift keywords that need backticks as a
+// parameter name or in a declaration.
+var swiftReservedWords = words(`
+	Any Self as associatedtype await break case catch class continue default
+	defer deinit do else enum extension fallthrough false fileprivate for func
+	guard if import in init inout internal is let nil open operator
+	precedencegroup private protocol public repeat rethrows return self static
+	struct subscript super switch throw throws true try typealias var where
+	while`)
+
+// jsReservedWords are the JavaScript reserved words, including those of
+// strict mode and modules, and the names strict mode forbids binding.
+var jsReservedWords = words(`
+	arguments await break case catch class const continue debugger default
+	delete do else enum eval export extends false finally for function if
+	implements import in instanceof interface let new null package private
+	protected public return static super switch this throw true try typeof var
+	void while with yield`)
+
+// rustReservedWords are Rust's strict and reserved keywords.
+var rustReservedWords = words(`
+	Self abstract as async await become box break const continue crate do dyn
+	else enum extern false final fn for gen if impl in let loop macro match mod
+	move mut override priv pub ref return self static struct super trait true
+	try type typeof unsafe unsized use virtual where while yield`)
+
+// rustNonRawWords are the Rust keywords that cannot be raw identifiers.
+var rustNonRawWords = words(`Self crate self super`)
+
+// goReservedWords are Go's keywords, plus the predeclared identifiers and
+// package names generated Go code uses, which a parameter would shadow.
+var goReservedWords = words(`
+	break case chan const continue default defer else fallthrough for func go
+	goto if import interface map package range return select struct switch
+	type var
+	any append bool byte cap copy error false float32 float64 int int8 int16
+	int32 int64 len make new nil panic rune string true uint uint8 uint16
+	uint32 uint64 uintptr
+	atomic cgo runtime sync unsafe`)
+
+// reservedWordTables names the table of each generated language.
+var reservedWordTables = []struct {
+	lang  string
+	words reservedWords
+}{
+	{"C", cReservedWords},
+	{"C++", cppReservedWords},
+	{"Kotlin", kotlinReservedWords},
+	{"Swift", swiftReservedWords},
+	{"JavaScript", jsReservedWords},
+	{"Rust", rustReservedWords},
+	{"Go", goReservedWords},
+}
+
+// ReservedIn returns the languages, sorted, in which the snake_case API name
+// name, or the camelCase the Kotlin, Swift and JavaScript bindings use, is a
+// reserved word.
+func ReservedIn(name string) []string {
+	camel := ToCamelCase(name)
+	var langs []string
+	for _, t := range reservedWordTables {
+		if t.words[name] || t.words[camel] {
+			langs = append(langs, t.lang)
+		}
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// cIdent escapes a name for C code with a trailing underscore. Names that
+// are C++ keywords are escaped too, since C++ includes the C header.
+func cIdent(name string) string {
+	if cReservedWords[name] || cppReservedWords[name] {
+		return name + "_"
+	}
+	return name
+}
+
+// kotlinIdent escapes a Kotlin name with backticks.
+func kotlinIdent(name string) string {
+	if kotlinReservedWords[name] {
+		return "`" + name + "`"
+	}
+	return name
+}
+
+// swiftIdent escapes a Swift name with backticks.
+func swiftIdent(name string) string {
+	if swiftReservedWords[name] {
+		return "`" + name + "`"
+	}
+	return name
+}
+
+// jsIdent escapes a JavaScript name with a trailing underscore.
+func jsIdent(name string) string {
+	if jsReservedWords[name] {
+		return name + "_"
+	}
+	return name
+}
+
+// rustIdent escapes a Rust name as a raw identifier, or with a trailing
+// underscore for the keywords that cannot be raw.
+func rustIdent(name string) string {
+	if rustNonRawWords[name] {
+		return name + "_"
+	}
+	if rustReservedWords[name] {
+		return "r#" + name
+	}
+	return name
+}
+
+// goIdent escapes a Go name with a trailing underscore.
+func goIdent(name string) string {
+	if goReservedWords[name] {
+		return name + "_"
+	}
+	return name
+}
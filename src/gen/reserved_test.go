@@ -0,0 +1,145 @@
+package gen
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReservedIn(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"delete", []string{"C++", "JavaScript"}},
+		{"type", []string{"Go", "Rust"}},
+		{"in", []string{"JavaScript", "Kotlin", "Rust", "Swift"}},
+		{"default", []string{"C", "Go", "JavaScript", "Swift"}},
+		{"static_assert", []string{"C"}},
+		{"color", nil},
+	}
+	for _, tt := range tests {
+		if got := ReservedIn(tt.name); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ReservedIn(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIdentEscapes(t *testing.T) {
+	tests := []struct {
+		fn       func(string) string
+		in, want string
+	}{
+		{cIdent, "default", "default_"},
+		{cIdent, "new", "new_"},
+		{cIdent, "type", "type"},
+		{kotlinIdent, "fun", "`fun`"},
+		{kotlinIdent, "default", "default"},
+		{swiftIdent, "repeat", "`repeat`"},
+		{jsIdent, "var", "var_"},
+		{rustIdent, "match", "r#match"},
+		{rustIdent, "self", "self_"},
+		{rustIdent, "new", "new"},
+		{goIdent, "range", "range_"},
+		{goIdent, "len", "len_"},
+	}
+	for _, tt := range tests {
+		if got := tt.fn(tt.in); got != tt.want {
+			t.Errorf("escape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestReserved_Bindings(t *testing.T) {
+	ctx := loadTestAPI(t, "reserved.yaml")
+	checks := []struct {
+		gen  Generator
+		path string
+		want []string
+	}{
+		{&CHeaderGenerator{}, "reserved_api.h", []string{
+			"    int32_t default_,",
+			"    const uint8_t* fun,\n    uint32_t fun_len,",
+		}},
+		{&KotlinGenerator{}, "ReservedApi.kt", []string{
+			"fun delete(type: String, `fun`: ByteArray, `object`: Int?)",
+			"external fun nativeDocRepeat(doc: Long, protocol: String?, `var`: FloatArray, varLen: Int): String",
+		}},
+		{&KotlinGenerator{}, "reserved_api_jni.c", []string{
+			"jint default_) {",
+			"reserved_api_doc_create((int32_t)default_, &out_result)",
+		}},
+		{&SwiftGenerator{}, "ReservedApi.swift", []string{
+			"public static func create(`default`: Int32) throws -> Doc {",
+			"public func `repeat`(`protocol`: String?, ",
+			"UInt32(`var`.count)",
+		}},
+		{&JSWASMGenerator{}, "reserved_api.js", []string{
+			"create(default_) {",
+			"repeat(doc, protocol, var_) {",
+			"_varPtr, _varLen",
+		}},
+	}
+	for _, c := range checks {
+		files, err := c.gen.Generate(ctx)
+		if err != nil {
+			t.Fatalf("%s: generation failed: %v", c.gen.Name(), err)
+		}
+		content := string(findOutputFile(t, files, c.path).Content)
+		for _, want := range c.want {
+			if !strings.Contains(content, want) {
+				t.Errorf("%s missing %q", c.path, want)
+			}
+		}
+	}
+}
+
+func TestReserved_Impls(t *testing.T) {
+	checks := []struct {
+		lang string
+		gen  Generator
+		path string
+		want []string
+	}{
+		{"cpp", &ImplCppGenerator{}, "reserved_api_interface.h", []string{
+			"virtual int32_t delete_(void* doc, std::string_view type,",
+			"const Rendering::RendererConfig* new_) = 0;",
+		}},
+		{"cpp", &ImplCppGenerator{}, "reserved_api_shim.cpp", []string{
+			"return self->delete_(doc, std::string_view(type), std::span(fun, fun_len),",
+			"if (new_ && !reserved_api_table_verify<Rendering::RendererConfig>(new_, new_len)) {",
+		}},
+		{"rust", &RustImplGenerator{}, "reserved_api_trait.rs", []string{
+			"fn delete(&self, doc: *mut c_void, r#type: &str,",
+			"fn r#match(&self, doc: *mut c_void, r#in: RenderingRendererConfig<'_>,",
+		}},
+		{"rust", &RustImplGenerator{}, "reserved_api_ffi.rs", []string{
+			"let r#type = CStr::from_ptr(r#type).to_str()",
+			"let r#in = match table_root::<RenderingRendererConfig>(r#in, in_len) {",
+			"Doc::r#match(_self, doc, r#in, func, range)",
+		}},
+		{"go", &GoImplGenerator{}, "reserved_api_interface.go", []string{
+			"Delete(type_ string, fun []uint8, object *uint32) error",
+			"Repeat(protocol *string, var_ []float32) string",
+		}},
+		{"go", &GoImplGenerator{}, "reserved_api_cgo.go", []string{
+			"func reserved_api_doc_create(default_ C.int32_t, out_result *C.doc_handle) C.int32_t {",
+			"typeGo := C.GoString(type_)",
+			"unsafe.Slice((*float32)(unsafe.Pointer(var_)), var_len)",
+		}},
+	}
+	for _, c := range checks {
+		ctx := loadTestAPI(t, "reserved.yaml")
+		ctx.API.API.ImplLang = c.lang
+		files, err := c.gen.Generate(ctx)
+		if err != nil {
+			t.Fatalf("%s: generation failed: %v", c.gen.Name(), err)
+		}
+		content := string(findOutputFile(t, files, c.path).Content)
+		for _, want := range c.want {
+			if !strings.Contains(content, want) {
+				t.Errorf("%s missing %q", c.path, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/benn-herrera/xplatter/model"
+)
+
+// StringFreeFunctionName returns the exported C function that releases a string
+// returned across the ABI.
+// e.g., "hello_xplatter" → "hello_xplatter_string_free"
+func StringFreeFunctionName(apiName string) string {
+	return apiName + "_string_free"
+}
+
+// hasStringReturns reports whether any method returns a string, making the
+// string free function part of the ABI. The strings of a returned table are
+// part of its FlatBuffer.
+func hasStringReturns(api *model.APIDefinition) bool {
+	for _, iface := range api.Interfaces {
+		for _, method := range iface.Methods {
+			if method.Returns != nil && model.IsString(method.Returns.Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeStringFreeDeclaration emits the string ownership section of the public C header.
+func writeStringFreeDeclaration(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, `/* Returned strings — string return values are allocated by the
+ * implementation and owned by the caller, who releases each one with %[1]s.
+ * NULL is a valid empty string. */
+%[2]s void %[1]s(char* str);
+
+`, StringFreeFunctionName(apiName), ExportMacroName(apiName))
+}
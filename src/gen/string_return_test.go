@@ -0,0 +1,36 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStringFreeFunctionName(t *testing.T) {
+	if got := StringFreeFunctionName("strings_api"); got != "strings_api_string_free" {
+		t.Errorf("StringFreeFunctionName = %q", got)
+	}
+}
+
+func TestHasStringReturns(t *testing.T) {
+	ctx := loadTestAPI(t, "strings.yaml")
+	if !hasStringReturns(ctx.API) {
+		t.Error("strings.yaml returns strings")
+	}
+	ctx = loadTestAPI(t, "minimal.yaml")
+	if hasStringReturns(ctx.API) {
+		t.Error("minimal.yaml returns no strings")
+	}
+	// A returned table's strings are part of its FlatBuffer.
+	ctx = loadTestAPI(t, "unions.yaml")
+	if hasStringReturns(ctx.API) {
+		t.Error("unions.yaml returns no strings")
+	}
+}
+
+func TestWriteStringFreeDeclaration(t *testing.T) {
+	var b strings.Builder
+	writeStringFreeDeclaration(&b, "strings_api")
+	if !strings.Contains(b.String(), "STRINGS_API_EXPORT void strings_api_string_free(char* str);") {
+		t.Errorf("missing string free declaration:\n%s", b.String())
+	}
+}
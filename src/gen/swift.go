@@ -25,23 +25,69 @@ func (g *SwiftGenerator) Generate(ctx *Context) ([]*OutputFile, error) {
 	var b strings.Builder
 
 	b.WriteString(GeneratedFileHeader(ctx, "//", false))
-	b.WriteString("\nimport Foundation\n\n")
+	b.WriteString("\nimport Foundation\n")
+	if len(usedTables(api)) > 0 {
+		b.WriteString("import FlatBuffers\n")
+	}
+	b.WriteString("\n")
+
+	writeSwiftTypes(&b, api, ctx.ResolvedTypes, inTarget(api, swiftTarget, ""))
+
+	// Library ABI check
+	writeSwiftABI(&b, apiName, ABIInfo(ctx))
 
-	// Collect all error types used across the API
-	errorTypes := CollectErrorTypes(api)
+	// Array views of fixed-length array fields
+	for _, name := range arrayStructs(ctx.ResolvedTypes) {
+		writeSwiftArrayAccessors(&b, name, ctx.ResolvedTypes[name])
+	}
+
+	// Returned strings
+	if hasStringReturns(api) {
+		writeSwiftStringSupport(&b, apiName, hasOptionalStringReturns(api))
+	}
 
-	// Error enum
-	if len(errorTypes) > 0 {
-		for _, errType := range errorTypes {
-			writeSwiftErrorEnum(&b, errType, ctx.ResolvedTypes)
+	// Optional string arguments
+	if hasOptionalStringParams(api) {
+		writeSwiftOptionalCString(&b)
+	}
+
+	// Optional table arguments
+	if hasOptionalTableParams(api) {
+		writeSwiftOptionalBytes(&b)
+	}
+
+	// Returned buffers
+	if hasBufferReturns(api, ctx.ResolvedTypes) {
+		writeSwiftBufferSupport(&b, apiName)
+	}
+
+	// Async operation polling
+	if hasAsyncMethods(api) {
+		writeSwiftAsyncSupport(&b, pascalAPI)
+	}
+
+	// Protocols carrying the methods of handles that other handles extend
+	for _, h := range handlesBaseFirst(api) {
+		if isBaseHandle(api, h.Name) {
+			writeSwiftHandleProtocol(&b, h, api, ctx.ResolvedTypes)
 		}
 	}
 
+	// Moving a handle through a base handle's protocol
+	if swiftMovesBaseHandle(api) {
+		writeSwiftMovableProtocol(&b, api)
+	}
+
 	// Handle wrapper classes
 	for _, h := range api.Handles {
 		writeSwiftHandleClass(&b, h, api, ctx.ResolvedTypes)
 	}
 
+	// Events
+	if len(api.Events) > 0 {
+		writeSwiftEvents(&b, api)
+	}
+
 	// Free functions (methods on interfaces that don't take a handle as first param
 	// and don't return a handle — these are rare but possible)
 	// We group methods by the handle they operate on. Methods that create a handle
@@ -50,14 +96,171 @@ func (g *SwiftGenerator) Generate(ctx *Context) ([]*OutputFile, error) {
 	writeSwiftFreeFunctions(&b, api, ctx.ResolvedTypes)
 
 	filename := pascalAPI + ".swift"
-	return []*OutputFile{
+	files := []*OutputFile{
 		{Path: filename, Content: []byte(b.String())},
-	}, nil
+	}
+
+	// Types of a namespace mapped to a prefix go to a file of their own
+	for _, ns := range mappedNamespaces(api, swiftTarget) {
+		var types strings.Builder
+		writeSwiftTypes(&types, api, ctx.ResolvedTypes, inTarget(api, swiftTarget, ns.Swift))
+		if types.Len() == 0 {
+			continue
+		}
+		files = append(files, &OutputFile{
+			Path:    pascalAPI + "+" + ns.Swift + ".swift",
+			Content: []byte(GeneratedFileHeader(ctx, "//", false) + "\nimport Foundation\n\n" + types.String()),
+		})
+	}
+	return files, nil
+}
+
+// writeSwiftTypes writes the error enums, option sets and union enums
+// generated for the FlatBuffer types that keep selects.
+func writeSwiftTypes(b *strings.Builder, api *model.APIDefinition, resolved resolver.ResolvedTypes, keep func(string) bool) {
+	// Error enums for all error types used across the API
+	for _, errType := range CollectErrorTypes(api) {
+		if keep(errType) {
+			writeSwiftErrorEnum(b, api, errType, resolved)
+		}
+	}
+
+	// Option sets for bit_flags enums
+	for _, name := range bitFlagsEnums(resolved) {
+		if keep(name) {
+			writeSwiftBitFlags(b, api, name, resolved[name])
+		}
+	}
+
+	// Enums with associated values for unions
+	for _, name := range unionTypes(resolved) {
+		if keep(name) {
+			writeSwiftUnion(b, api, name, resolved[name], resolved)
+		}
+	}
+}
+
+// writeSwiftEvents writes the event kind enum, event value type, and the
+// poll/drain/subscribe namespace over the C event functions.
+func writeSwiftEvents(b *strings.Builder, api *model.APIDefinition) {
+	apiName := api.API.Name
+	pascalAPI := ToPascalCase(apiName)
+	kindName := pascalAPI + "EventKind"
+
+	fmt.Fprintf(b, "public enum %s: UInt32 {\n", kindName)
+	for i, ev := range api.Events {
+		fmt.Fprintf(b, "    case %s = %d\n", ToCamelCase(ev.Name), EventKindValue(i))
+	}
+	fmt.Fprintf(b, `}
+
+/// An event pushed by the implementation. The payload is the serialized
+/// FlatBuffer table for the kind.
+public struct %[1]sEvent {
+    public let kind: %[2]s
+    public let payload: [UInt8]
+}
+
+public enum %[1]sEvents {
+    /// Pops the next pending event, or returns nil when none are pending.
+    public static func poll() -> %[1]sEvent? {
+        _ = %[1]sABI.checked
+        var buffer = [UInt8](repeating: 0, count: 256)
+        var kind: UInt32 = 0
+        while true {
+            let size = buffer.withUnsafeMutableBufferPointer { buf in
+                %[3]s(&kind, buf.baseAddress, UInt32(buf.count))
+            }
+            if size < 0 {
+                buffer = [UInt8](repeating: 0, count: Int(-size))
+                continue
+            }
+            if kind == 0 { return nil }
+            guard let eventKind = %[2]s(rawValue: kind) else { continue }
+            return %[1]sEvent(kind: eventKind, payload: Array(buffer[0..<Int(size)]))
+        }
+    }
+
+    /// Delivers every pending event to handler and returns how many were delivered.
+    @discardableResult
+    public static func drain(_ handler: (%[1]sEvent) -> Void) -> Int {
+        var count = 0
+        while let event = poll() {
+            handler(event)
+            count += 1
+        }
+        return count
+    }
+
+    /// Drains events on queue whenever the implementation signals. Cancel the
+    /// returned source to stop. Returns nil when the platform has no signal
+    /// descriptor; call drain periodically instead.
+    public static func subscribe(queue: DispatchQueue = .main, _ handler: @escaping (%[1]sEvent) -> Void) -> DispatchSourceRead? {
+        _ = %[1]sABI.checked
+        let fd = %[4]s()
+        if fd < 0 { return nil }
+        let source = DispatchSource.makeReadSource(fileDescriptor: fd, queue: queue)
+        source.setEventHandler { drain(handler) }
+        source.resume()
+        queue.async { drain(handler) }
+        return source
+    }
+}
+
+`, pascalAPI, kindName, EventPollFunctionName(apiName), EventSignalFDFunctionName(apiName))
+}
+
+// writeSwiftABI writes the check of the linked library's ABI. Swift has no
+// load-time hook, so the static entry points into the library, the factory
+// methods and free functions, run it on the first call.
+func writeSwiftABI(b *strings.Builder, apiName, abiInfo string) {
+	pascalAPI := ToPascalCase(apiName)
+	fmt.Fprintf(b, `/// The ABI of the linked library, checked against the one these bindings were
+/// generated for before the first call into it.
+public enum %[1]sABI {
+    /// The API version and ABI hash these bindings were generated for.
+    public static let expected = "%[3]s"
+
+    /// The API version and ABI hash the linked library reports.
+    public static var library: String { String(cString: %[2]s()) }
+
+    /// Thrown when the linked library was built from a different ABI.
+    public struct MismatchError: Error, CustomStringConvertible {
+        public let library: String
+        public let expected: String
+        public var description: String { "%[4]s" }
+    }
+
+    /// Throws MismatchError unless the library's ABI hash matches the bindings'.
+    public static func verify() throws {
+        let library = self.library
+        if library.split(separator: " ").last != expected.split(separator: " ").last {
+            throw MismatchError(library: library, expected: expected)
+        }
+    }
+
+    /// Traps with the mismatch on first access; verify() reports it as an
+    /// error instead.
+    static let checked: Void = {
+        do {
+            try verify()
+        } catch {
+            fatalError("\(error)")
+        }
+    }()
+}
+
+`, pascalAPI, ABIInfoFunctionName(apiName), abiInfo, abiMismatchMessage(apiName, `\(library)`, `\(expected)`))
+}
+
+// writeSwiftABICheck writes the statement that opens a static entry point
+// with the library ABI check.
+func writeSwiftABICheck(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, "        _ = %sABI.checked\n", ToPascalCase(apiName))
 }
 
 // writeSwiftErrorEnum writes a Swift enum conforming to Error for a FlatBuffer error code type.
-func writeSwiftErrorEnum(b *strings.Builder, errType string, resolved resolver.ResolvedTypes) {
-	swiftName := swiftErrorEnumName(errType)
+func writeSwiftErrorEnum(b *strings.Builder, api *model.APIDefinition, errType string, resolved resolver.ResolvedTypes) {
+	swiftName := swiftErrorEnumName(api, errType)
 	fmt.Fprintf(b, "public enum %s: Int32, Error {\n", swiftName)
 	if info, ok := resolved[errType]; ok && info.Kind == resolver.TypeKindEnum {
 		for _, val := range info.EnumValues {
@@ -68,8 +271,8 @@ func writeSwiftErrorEnum(b *strings.Builder, errType string, resolved resolver.R
 }
 
 // swiftErrorEnumName converts a FlatBuffer error type like "Common.ErrorCode" to a Swift name.
-func swiftErrorEnumName(errType string) string {
-	return strings.ReplaceAll(errType, ".", "")
+func swiftErrorEnumName(api *model.APIDefinition, errType string) string {
+	return swiftTypeName(api, errType)
 }
 
 // writeSwiftHandleClass writes a Swift wrapper class for an opaque handle.
@@ -79,16 +282,35 @@ func writeSwiftHandleClass(b *strings.Builder, handle model.HandleDef, api *mode
 	handleSnake := model.HandleToSnake(handle.Name)
 	handleCType := HandleTypedefName(handle.Name) // e.g. "engine_handle"
 
-	if handle.Description != "" {
-		fmt.Fprintf(b, "/// %s\n", handle.Description)
+	var notes []string
+	if note := handleReleaseNote(api, handle.Name); note != "" {
+		notes = append(notes, note)
 	}
-	fmt.Fprintf(b, "public final class %s {\n", className)
-	fmt.Fprintf(b, "    let handle: OpaquePointer\n\n")
+	writeSwiftDocComment(b, "", handle.Description, &handle.Lifecycle, sameName, notes...)
+	conformance := swiftHandleConformance(api, handle.Name)
+	tracked := tracksOwnership(api, handle.Name)
+	if tracked && swiftMovesBaseHandle(api) {
+		conformance = strings.TrimPrefix(conformance+", "+swiftMovableProtocolName(api), ", ")
+	}
+	access := ""
+	if swiftHandleConformance(api, handle.Name) != "" {
+		access = "public "
+	}
+	if conformance != "" {
+		fmt.Fprintf(b, "public final class %s: %s {\n", className, conformance)
+	} else {
+		fmt.Fprintf(b, "public final class %s {\n", className)
+	}
+	if tracked {
+		writeSwiftOwnershipMembers(b, className, access)
+	} else {
+		fmt.Fprintf(b, "    %slet handle: OpaquePointer\n\n", access)
 
-	// Internal init from raw handle
-	fmt.Fprintf(b, "    init(handle: OpaquePointer) {\n")
-	fmt.Fprintf(b, "        self.handle = handle\n")
-	fmt.Fprintf(b, "    }\n\n")
+		// Internal init from raw handle
+		fmt.Fprintf(b, "    init(handle: OpaquePointer) {\n")
+		fmt.Fprintf(b, "        self.handle = handle\n")
+		fmt.Fprintf(b, "    }\n\n")
+	}
 
 	// Find the interface that owns this handle's lifecycle and emit deinit.
 	// Primary: interface has constructors → auto-destructor name derived from handle.
@@ -100,20 +322,14 @@ func writeSwiftHandleClass(b *strings.Builder, handle model.HandleDef, api *mode
 			break
 		}
 		if ifaceHandleName, ok := iface.ConstructorHandleName(); ok && ifaceHandleName == handle.Name {
-			destroyFunc := CABIFunctionName(apiName, iface.Name, expectedDestroyName)
-			fmt.Fprintf(b, "    deinit {\n")
-			fmt.Fprintf(b, "        %s(handle)\n", destroyFunc)
-			fmt.Fprintf(b, "    }\n\n")
+			writeSwiftDeinit(b, CABIFunctionName(apiName, iface.Name, expectedDestroyName), tracked)
 			deinitEmitted = true
 			break
 		}
 		// Fallback: look for an explicit destroy_<handle> in methods
 		for _, method := range iface.Methods {
 			if method.Name == expectedDestroyName {
-				destroyFunc := CABIFunctionName(apiName, iface.Name, method.Name)
-				fmt.Fprintf(b, "    deinit {\n")
-				fmt.Fprintf(b, "        %s(handle)\n", destroyFunc)
-				fmt.Fprintf(b, "    }\n\n")
+				writeSwiftDeinit(b, CABIFunctionName(apiName, iface.Name, method.Name), tracked)
 				deinitEmitted = true
 				break
 			}
@@ -125,7 +341,7 @@ func writeSwiftHandleClass(b *strings.Builder, handle model.HandleDef, api *mode
 		for _, ctor := range iface.Constructors {
 			if ctor.Returns != nil {
 				if hName, ok := model.IsHandle(ctor.Returns.Type); ok && hName == handle.Name {
-					writeSwiftFactoryMethod(b, apiName, iface.Name, &ctor, className, resolved)
+					writeSwiftFactoryMethod(b, api, iface.Name, &ctor, className, resolved)
 				}
 			}
 		}
@@ -135,19 +351,24 @@ func writeSwiftHandleClass(b *strings.Builder, handle model.HandleDef, api *mode
 			m := &iface.Methods[i]
 			if m.Returns != nil {
 				if hName, ok := model.IsHandle(m.Returns.Type); ok && hName == handle.Name {
-					writeSwiftFactoryMethod(b, apiName, iface.Name, m, className, resolved)
+					writeSwiftFactoryMethod(b, api, iface.Name, m, className, resolved)
 				}
 			}
 		}
 	}
 
-	// Find instance methods (first param is this handle)
+	// Find instance methods (first param is this handle). A base handle's
+	// methods live in the extension of its protocol instead.
+	if isBaseHandle(api, handle.Name) {
+		b.WriteString("}\n\n")
+		return
+	}
 	for _, iface := range api.Interfaces {
 		for _, method := range iface.Methods {
 			// Check if first param is this handle
 			if len(method.Parameters) > 0 {
 				if hName, ok := model.IsHandle(method.Parameters[0].Type); ok && hName == handle.Name {
-					writeSwiftInstanceMethod(b, apiName, iface.Name, &method, handleCType, resolved)
+					writeSwiftInstanceMethod(b, api, iface.Name, &method, handleCType, resolved)
 				}
 			}
 		}
@@ -159,10 +380,195 @@ func writeSwiftHandleClass(b *strings.Builder, handle model.HandleDef, api *mode
 	_ = handleCType
 }
 
+// writeSwiftOwnershipMembers writes the members of a handle class that track
+// whether it still owns its handle. A moved wrapper has no handle, and a
+// borrowed one never destroys it.
+func writeSwiftOwnershipMembers(b *strings.Builder, className, access string) {
+	fmt.Fprintf(b, `    private var nativeHandle: OpaquePointer?
+    private let owned: Bool
+
+    %[2]svar handle: OpaquePointer {
+        guard let handle = nativeHandle else {
+            preconditionFailure("%[1]s has been moved")
+        }
+        return handle
+    }
+
+    init(handle: OpaquePointer, owned: Bool = true) {
+        self.nativeHandle = handle
+        self.owned = owned
+    }
+
+    /// Gives the handle to a call that takes ownership of it.
+    func take() -> OpaquePointer {
+        precondition(owned, "%[1]s is borrowed and cannot be moved")
+        defer { nativeHandle = nil }
+        return handle
+    }
+
+`, className, access)
+}
+
+// writeSwiftDeinit writes a handle class's deinit, which destroys the handle
+// unless an ownership-tracking wrapper no longer owns it.
+func writeSwiftDeinit(b *strings.Builder, destroyFunc string, tracked bool) {
+	b.WriteString("    deinit {\n")
+	if tracked {
+		b.WriteString("        if owned, let handle = nativeHandle {\n")
+		fmt.Fprintf(b, "            %s(handle)\n", destroyFunc)
+		b.WriteString("        }\n")
+	} else {
+		fmt.Fprintf(b, "        %s(handle)\n", destroyFunc)
+	}
+	b.WriteString("    }\n\n")
+}
+
+// swiftMovesBaseHandle reports whether a method takes ownership of a handle
+// through its base handle's protocol, which cannot declare the internal
+// take(), so ownership-tracking classes also conform to a movable protocol.
+func swiftMovesBaseHandle(api *model.APIDefinition) bool {
+	for _, iface := range api.Interfaces {
+		for _, method := range iface.Methods {
+			for _, p := range method.Parameters {
+				if handleName, ok := model.IsHandle(p.Type); ok && isMoveParam(&p) && isBaseHandle(api, handleName) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// swiftMovableProtocolName returns the internal protocol of handle classes
+// that can give up their handle, e.g. "OwnershipApiMovableHandle".
+func swiftMovableProtocolName(api *model.APIDefinition) string {
+	return ToPascalCase(api.API.Name) + "MovableHandle"
+}
+
+// writeSwiftMovableProtocol writes the internal protocol that lets a moved
+// base-protocol value give up its handle.
+func writeSwiftMovableProtocol(b *strings.Builder, api *model.APIDefinition) {
+	b.WriteString("/// Handle classes that can give their handle to a call that takes ownership of it.\n")
+	fmt.Fprintf(b, "protocol %s: AnyObject {\n", swiftMovableProtocolName(api))
+	b.WriteString("    func take() -> OpaquePointer\n")
+	b.WriteString("}\n\n")
+}
+
+// swiftTakeExpr returns the expression giving up the handle of value, which
+// is typed as a base handle's protocol when viaProtocol is set.
+func swiftTakeExpr(api *model.APIDefinition, value string, viaProtocol bool) string {
+	if viaProtocol {
+		return "(" + value + " as! " + swiftMovableProtocolName(api) + ").take()"
+	}
+	return value + ".take()"
+}
+
+// swiftReceiverArg returns the C argument for an instance method's receiver,
+// giving up the handle when the method takes ownership of it. A base handle's
+// methods are declared on its protocol, so they reach take() through a cast.
+func swiftReceiverArg(api *model.APIDefinition, method *model.MethodDef) string {
+	if !isMoveParam(&method.Parameters[0]) {
+		return "handle"
+	}
+	handleName, _ := model.IsHandle(method.Parameters[0].Type)
+	if isBaseHandle(api, handleName) {
+		return swiftTakeExpr(api, "self", true)
+	}
+	return "take()"
+}
+
+// writeSwiftDocComment writes a declaration's doc comment, noting when it was
+// introduced, whether it is experimental and any further notes, followed by an
+// @available attribute if it is deprecated. name spells a replacement in Swift.
+func writeSwiftDocComment(b *strings.Builder, indent, description string, l *model.Lifecycle, name func(string) string, notes ...string) {
+	if description != "" {
+		fmt.Fprintf(b, "%s/// %s\n", indent, description)
+	}
+	if l.Since != "" {
+		fmt.Fprintf(b, "%s/// - Since: %s\n", indent, l.Since)
+	}
+	if l.IsExperimental() {
+		fmt.Fprintf(b, "%s/// - Important: %s\n", indent, experimentalNote)
+	}
+	for _, note := range notes {
+		fmt.Fprintf(b, "%s/// - Note: %s\n", indent, note)
+	}
+	if l.Deprecated != nil {
+		fmt.Fprintf(b, "%s@available(*, deprecated, message: %s)\n", indent, quoteLiteral(deprecationText(l.Deprecated, name)))
+	}
+}
+
+// writeSwiftMethodDocComment writes the doc comment and attributes of a
+// wrapper method, an instance method of its first parameter's class when
+// instance is set. Main-thread methods are isolated to the main actor;
+// creator affinity has no attribute, so it gets a note.
+func writeSwiftMethodDocComment(b *strings.Builder, method *model.MethodDef, instance bool) {
+	var notes []string
+	if method.ThreadAffinity == model.ThreadAffinityCreator {
+		notes = append(notes, threadAffinityNote(method))
+	}
+	notes = append(notes, bindingOwnershipNotes(method, ToCamelCase, instance)...)
+	writeSwiftDocComment(b, "    ", method.Description, &method.Lifecycle, ToCamelCase, notes...)
+	if method.ThreadAffinity == model.ThreadAffinityMain {
+		b.WriteString("    @MainActor\n")
+	}
+}
+
+// swiftHandleProtocolName returns the protocol for a base handle's methods.
+// e.g., "Node" → "NodeProtocol"
+func swiftHandleProtocolName(handleName string) string {
+	return handleName + "Protocol"
+}
+
+// swiftHandleConformance returns the protocol a handle class conforms to: its
+// own protocol if other handles extend it, else its parent handle's, else "".
+func swiftHandleConformance(api *model.APIDefinition, handleName string) string {
+	if isBaseHandle(api, handleName) {
+		return swiftHandleProtocolName(handleName)
+	}
+	if parent, ok := api.ParentHandleName(handleName); ok {
+		return swiftHandleProtocolName(parent)
+	}
+	return ""
+}
+
+// writeSwiftHandleProtocol writes the protocol for a handle that other handles
+// extend, with an extension implementing its instance methods once for every
+// conforming class.
+func writeSwiftHandleProtocol(b *strings.Builder, handle model.HandleDef, api *model.APIDefinition, resolved resolver.ResolvedTypes) {
+	protocolName := swiftHandleProtocolName(handle.Name)
+	if handle.Description != "" {
+		fmt.Fprintf(b, "/// %s\n", handle.Description)
+	}
+	if parent, ok := api.ParentHandleName(handle.Name); ok {
+		fmt.Fprintf(b, "public protocol %s: %s {}\n\n", protocolName, swiftHandleProtocolName(parent))
+	} else {
+		fmt.Fprintf(b, "public protocol %s: AnyObject {\n", protocolName)
+		b.WriteString("    var handle: OpaquePointer { get }\n")
+		b.WriteString("}\n\n")
+	}
+
+	fmt.Fprintf(b, "extension %s {\n", protocolName)
+	handleCType := HandleTypedefName(handle.Name)
+	for _, iface := range api.Interfaces {
+		for i := range iface.Methods {
+			if isInstanceMethod(iface.Methods[i], handle.Name) {
+				writeSwiftInstanceMethod(b, api, iface.Name, &iface.Methods[i], handleCType, resolved)
+			}
+		}
+	}
+	b.WriteString("}\n\n")
+}
+
 // writeSwiftFactoryMethod writes a static factory method that creates a handle.
-func writeSwiftFactoryMethod(b *strings.Builder, apiName, ifaceName string, method *model.MethodDef, className string, resolved resolver.ResolvedTypes) {
+func writeSwiftFactoryMethod(b *strings.Builder, api *model.APIDefinition, ifaceName string, method *model.MethodDef, className string, resolved resolver.ResolvedTypes) {
+	apiName := api.API.Name
+	if method.Async {
+		writeSwiftAsyncMethod(b, api, ifaceName, method, false, resolved)
+		return
+	}
 	funcName := CABIFunctionName(apiName, ifaceName, method.Name)
-	swiftMethodName := ToCamelCase(method.Name)
+	swiftMethodName := swiftName(method.Name)
 	hasError := method.Error != ""
 
 	// Build Swift parameter list (factory methods are static, no self handle)
@@ -170,42 +576,47 @@ func writeSwiftFactoryMethod(b *strings.Builder, apiName, ifaceName string, meth
 	var callArgs []string
 
 	for _, p := range method.Parameters {
-		sp, ca := swiftParamAndCallArg(&p, resolved)
+		sp, ca := swiftParamAndCallArg(api, &p, resolved)
 		swiftParams = append(swiftParams, sp...)
 		callArgs = append(callArgs, ca...)
 	}
 
 	paramStr := strings.Join(swiftParams, ", ")
+	resultType := className
+	if isOptionalReturn(method) {
+		resultType += "?"
+	}
 
 	if hasError {
-		errEnumName := swiftErrorEnumName(method.Error)
-		if method.Description != "" {
-			fmt.Fprintf(b, "    /// %s\n", method.Description)
-		}
-		fmt.Fprintf(b, "    public static func %s(%s) throws -> %s {\n", swiftMethodName, paramStr, className)
+		errEnumName := swiftErrorEnumName(api, method.Error)
+		writeSwiftMethodDocComment(b, method, false)
+		fmt.Fprintf(b, "    public static func %s(%s) throws -> %s {\n", swiftMethodName, paramStr, resultType)
+		writeSwiftABICheck(b, apiName)
 		fmt.Fprintf(b, "        var result: OpaquePointer?\n")
 
 		// Build the C call with withCString wrappers
-		writeSwiftCCall(b, funcName, callArgs, method.Parameters, "result", true, errEnumName, className)
+		writeSwiftCCall(b, funcName, callArgs, method.Parameters, resolved, "result", true, errEnumName, method.Returns)
 
 		fmt.Fprintf(b, "    }\n\n")
 	} else {
-		if method.Description != "" {
-			fmt.Fprintf(b, "    /// %s\n", method.Description)
-		}
-		fmt.Fprintf(b, "    public static func %s(%s) -> %s {\n", swiftMethodName, paramStr, className)
-		fmt.Fprintf(b, "        var result: OpaquePointer?\n")
-
-		writeSwiftCCall(b, funcName, callArgs, method.Parameters, "result", false, "", className)
+		writeSwiftMethodDocComment(b, method, false)
+		fmt.Fprintf(b, "    public static func %s(%s) -> %s {\n", swiftMethodName, paramStr, resultType)
+		writeSwiftABICheck(b, apiName)
+		writeSwiftCCall(b, funcName, callArgs, method.Parameters, resolved, "result", false, "", method.Returns)
 
 		fmt.Fprintf(b, "    }\n\n")
 	}
 }
 
 // writeSwiftInstanceMethod writes an instance method on a handle class.
-func writeSwiftInstanceMethod(b *strings.Builder, apiName, ifaceName string, method *model.MethodDef, handleCType string, resolved resolver.ResolvedTypes) {
+func writeSwiftInstanceMethod(b *strings.Builder, api *model.APIDefinition, ifaceName string, method *model.MethodDef, handleCType string, resolved resolver.ResolvedTypes) {
+	apiName := api.API.Name
+	if method.Async {
+		writeSwiftAsyncMethod(b, api, ifaceName, method, true, resolved)
+		return
+	}
 	funcName := CABIFunctionName(apiName, ifaceName, method.Name)
-	swiftMethodName := ToCamelCase(method.Name)
+	swiftMethodName := swiftName(method.Name)
 	hasError := method.Error != ""
 	hasReturn := method.Returns != nil
 
@@ -214,10 +625,10 @@ func writeSwiftInstanceMethod(b *strings.Builder, apiName, ifaceName string, met
 	var callArgs []string
 
 	// First arg is always the handle (self)
-	callArgs = append(callArgs, "handle")
+	callArgs = append(callArgs, swiftReceiverArg(api, method))
 
 	for _, p := range method.Parameters[1:] {
-		sp, ca := swiftParamAndCallArg(&p, resolved)
+		sp, ca := swiftParamAndCallArg(api, &p, resolved)
 		swiftParams = append(swiftParams, sp...)
 		callArgs = append(callArgs, ca...)
 	}
@@ -227,33 +638,47 @@ func writeSwiftInstanceMethod(b *strings.Builder, apiName, ifaceName string, met
 	// Determine return type
 	var swiftReturnType string
 	if hasReturn {
-		swiftReturnType = swiftType(method.Returns.Type, resolved)
+		swiftReturnType = swiftResultType(api, method.Returns, resolved)
 	}
 
-	if method.Description != "" {
-		fmt.Fprintf(b, "    /// %s\n", method.Description)
-	}
+	writeSwiftMethodDocComment(b, method, true)
 
+	_, bufReturn := returnBufferElem(method)
 	switch {
+	case bufReturn || returnsTable(method):
+		if hasError {
+			fmt.Fprintf(b, "    public func %s(%s) throws -> %s {\n", swiftMethodName, paramStr, swiftReturnType)
+		} else {
+			fmt.Fprintf(b, "    public func %s(%s) -> %s {\n", swiftMethodName, paramStr, swiftReturnType)
+		}
+		writeSwiftCCallBuffer(b, api, funcName, callArgs, method.Parameters[1:], resolved, method)
+	case returnHasPresenceFlag(method):
+		if hasError {
+			fmt.Fprintf(b, "    public func %s(%s) throws -> %s {\n", swiftMethodName, paramStr, swiftReturnType)
+		} else {
+			fmt.Fprintf(b, "    public func %s(%s) -> %s {\n", swiftMethodName, paramStr, swiftReturnType)
+		}
+		writeSwiftCCallOptional(b, api, funcName, callArgs, method.Parameters[1:], method, resolved)
 	case hasError && hasReturn:
 		fmt.Fprintf(b, "    public func %s(%s) throws -> %s {\n", swiftMethodName, paramStr, swiftReturnType)
 		if isHandleReturn(method.Returns.Type) {
 			fmt.Fprintf(b, "        var result: OpaquePointer?\n")
-			handleName, _ := model.IsHandle(method.Returns.Type)
-			writeSwiftCCall(b, funcName, callArgs, method.Parameters[1:], "result", true, swiftErrorEnumName(method.Error), handleName)
+			writeSwiftCCall(b, funcName, callArgs, method.Parameters[1:], resolved, "result", true, swiftErrorEnumName(api, method.Error), method.Returns)
 		} else {
 			fmt.Fprintf(b, "        var result: %s = %s\n", swiftCBridgeType(method.Returns.Type, resolved), swiftDefaultValue(method.Returns.Type))
-			writeSwiftCCallPrimitive(b, funcName, callArgs, method.Parameters[1:], "result", true, swiftErrorEnumName(method.Error))
+			writeSwiftCCallPrimitive(b, funcName, callArgs, method.Parameters[1:], resolved, "result", swiftResultExpr(api, method.Returns, "result", resolved), true, swiftErrorEnumName(api, method.Error))
 		}
 	case hasError && !hasReturn:
 		fmt.Fprintf(b, "    public func %s(%s) throws {\n", swiftMethodName, paramStr)
-		writeSwiftCCallVoid(b, funcName, callArgs, method.Parameters[1:], true, swiftErrorEnumName(method.Error))
+		writeSwiftCCallVoid(b, funcName, callArgs, method.Parameters[1:], resolved, true, swiftErrorEnumName(api, method.Error))
 	case !hasError && hasReturn:
 		fmt.Fprintf(b, "    public func %s(%s) -> %s {\n", swiftMethodName, paramStr, swiftReturnType)
-		writeSwiftCCallDirect(b, funcName, callArgs, method.Parameters[1:])
+		writeSwiftCCallDirect(b, funcName, callArgs, method.Parameters[1:], resolved, func(call string) string {
+			return swiftResultExpr(api, method.Returns, call, resolved)
+		})
 	default:
 		fmt.Fprintf(b, "    public func %s(%s) {\n", swiftMethodName, paramStr)
-		writeSwiftCCallVoid(b, funcName, callArgs, method.Parameters[1:], false, "")
+		writeSwiftCCallVoid(b, funcName, callArgs, method.Parameters[1:], resolved, false, "")
 	}
 
 	fmt.Fprintf(b, "    }\n\n")
@@ -315,15 +740,20 @@ func writeSwiftFreeFunctions(b *strings.Builder, api *model.APIDefinition, resol
 	pascalAPI := ToPascalCase(api.API.Name)
 	fmt.Fprintf(b, "public enum %s {\n", pascalAPI)
 	for _, fm := range freeMethods {
-		writeSwiftFreeFunction(b, api.API.Name, fm.iface, &fm.method, resolved)
+		writeSwiftFreeFunction(b, api, fm.iface, &fm.method, resolved)
 	}
 	b.WriteString("}\n\n")
 }
 
 // writeSwiftFreeFunction writes a single free function inside the namespace enum.
-func writeSwiftFreeFunction(b *strings.Builder, apiName, ifaceName string, method *model.MethodDef, resolved resolver.ResolvedTypes) {
+func writeSwiftFreeFunction(b *strings.Builder, api *model.APIDefinition, ifaceName string, method *model.MethodDef, resolved resolver.ResolvedTypes) {
+	apiName := api.API.Name
+	if method.Async {
+		writeSwiftAsyncMethod(b, api, ifaceName, method, false, resolved)
+		return
+	}
 	funcName := CABIFunctionName(apiName, ifaceName, method.Name)
-	swiftMethodName := ToCamelCase(method.Name)
+	swiftMethodName := swiftName(method.Name)
 	hasError := method.Error != ""
 	hasReturn := method.Returns != nil
 
@@ -331,7 +761,7 @@ func writeSwiftFreeFunction(b *strings.Builder, apiName, ifaceName string, metho
 	var callArgs []string
 
 	for _, p := range method.Parameters {
-		sp, ca := swiftParamAndCallArg(&p, resolved)
+		sp, ca := swiftParamAndCallArg(api, &p, resolved)
 		swiftParams = append(swiftParams, sp...)
 		callArgs = append(callArgs, ca...)
 	}
@@ -340,41 +770,344 @@ func writeSwiftFreeFunction(b *strings.Builder, apiName, ifaceName string, metho
 
 	var swiftReturnType string
 	if hasReturn {
-		swiftReturnType = swiftType(method.Returns.Type, resolved)
+		swiftReturnType = swiftResultType(api, method.Returns, resolved)
 	}
 
-	if method.Description != "" {
-		fmt.Fprintf(b, "    /// %s\n", method.Description)
+	writeSwiftMethodDocComment(b, method, false)
+	signature := swiftMethodName + "(" + paramStr + ")"
+	if hasError {
+		signature += " throws"
 	}
+	if hasReturn {
+		signature += " -> " + swiftReturnType
+	}
+	fmt.Fprintf(b, "    public static func %s {\n", signature)
+	writeSwiftABICheck(b, apiName)
 
+	_, bufReturn := returnBufferElem(method)
 	switch {
+	case bufReturn || returnsTable(method):
+		writeSwiftCCallBuffer(b, api, funcName, callArgs, method.Parameters, resolved, method)
+	case returnHasPresenceFlag(method):
+		writeSwiftCCallOptional(b, api, funcName, callArgs, method.Parameters, method, resolved)
 	case hasError && hasReturn:
-		fmt.Fprintf(b, "    public static func %s(%s) throws -> %s {\n", swiftMethodName, paramStr, swiftReturnType)
 		fmt.Fprintf(b, "        var result: %s = %s\n", swiftCBridgeType(method.Returns.Type, resolved), swiftDefaultValue(method.Returns.Type))
-		writeSwiftCCallPrimitive(b, funcName, callArgs, method.Parameters, "result", true, swiftErrorEnumName(method.Error))
+		writeSwiftCCallPrimitive(b, funcName, callArgs, method.Parameters, resolved, "result", swiftResultExpr(api, method.Returns, "result", resolved), true, swiftErrorEnumName(api, method.Error))
 	case hasError && !hasReturn:
-		fmt.Fprintf(b, "    public static func %s(%s) throws {\n", swiftMethodName, paramStr)
-		writeSwiftCCallVoid(b, funcName, callArgs, method.Parameters, true, swiftErrorEnumName(method.Error))
+		writeSwiftCCallVoid(b, funcName, callArgs, method.Parameters, resolved, true, swiftErrorEnumName(api, method.Error))
 	case !hasError && hasReturn:
-		fmt.Fprintf(b, "    public static func %s(%s) -> %s {\n", swiftMethodName, paramStr, swiftReturnType)
-		writeSwiftCCallDirect(b, funcName, callArgs, method.Parameters)
+		writeSwiftCCallDirect(b, funcName, callArgs, method.Parameters, resolved, func(call string) string {
+			return swiftResultExpr(api, method.Returns, call, resolved)
+		})
+	default:
+		writeSwiftCCallVoid(b, funcName, callArgs, method.Parameters, resolved, false, "")
+	}
+
+	fmt.Fprintf(b, "    }\n\n")
+}
+
+// writeSwiftAsyncSupport writes the start-failure error and the polling helper
+// shared by every async method.
+func writeSwiftAsyncSupport(b *strings.Builder, pascalAPI string) {
+	fmt.Fprintf(b, `/// Thrown by an async method when the implementation could not start the operation.
+public struct %[1]sAsyncStartError: Error {}
+
+enum %[1]sAsync {
+    /// Polls op until it finishes, backing off from %[2]d ms to %[3]d ms. Throws
+    /// CancellationError when the implementation cancels, and cancels the
+    /// operation when the calling task is cancelled first.
+    static func awaitOperation<T>(_ op: OpaquePointer?, poll: (OpaquePointer) -> (Int32, T), cancel: (OpaquePointer) -> Void) async throws -> T {
+        guard let op = op else { throw %[1]sAsyncStartError() }
+        var delayMs: UInt64 = %[2]d
+        while true {
+            let (status, result) = poll(op)
+            switch status {
+            case %[4]d:
+                break
+            case %[5]d:
+                return result
+            default:
+                throw CancellationError()
+            }
+            do {
+                try await Task.sleep(nanoseconds: delayMs * 1_000_000)
+            } catch {
+                cancel(op)
+                throw error
+            }
+            delayMs = min(delayMs * 2, %[3]d)
+        }
+    }
+}
+
+`, pascalAPI, AsyncPollMinDelayMS, AsyncPollMaxDelayMS, AsyncStatusPending, AsyncStatusDone)
+}
+
+// writeSwiftAsyncMethod writes an async throws method that starts the operation
+// and awaits it through the shared polling helper. Instance methods pass their
+// handle as the first argument; all others are static.
+func writeSwiftAsyncMethod(b *strings.Builder, api *model.APIDefinition, ifaceName string, method *model.MethodDef, instance bool, resolved resolver.ResolvedTypes) {
+	apiName := api.API.Name
+	swiftMethodName := swiftName(method.Name)
+	hasError := method.Error != ""
+	hasReturn := method.Returns != nil
+
+	params := method.Parameters
+	var swiftParams []string
+	var callArgs []string
+	if instance {
+		params = params[1:]
+		callArgs = append(callArgs, swiftReceiverArg(api, method))
+	}
+	for _, p := range params {
+		sp, ca := swiftParamAndCallArg(api, &p, resolved)
+		swiftParams = append(swiftParams, sp...)
+		callArgs = append(callArgs, ca...)
+	}
+
+	writeSwiftMethodDocComment(b, method, instance)
+	decl := "public func"
+	if !instance {
+		decl = "public static func"
+	}
+	if hasReturn {
+		fmt.Fprintf(b, "    %s %s(%s) async throws -> %s {\n", decl, swiftMethodName, strings.Join(swiftParams, ", "), swiftResultType(api, method.Returns, resolved))
+	} else {
+		fmt.Fprintf(b, "    %s %s(%s) async throws {\n", decl, swiftMethodName, strings.Join(swiftParams, ", "))
+	}
+	if !instance {
+		writeSwiftABICheck(b, apiName)
+	}
+
+	// Start: string, buffer and union arguments only need to live until start returns.
+	startCall := fmt.Sprintf("%s(%s)", AsyncStartFunctionName(apiName, ifaceName, method.Name), strings.Join(buildActualCallArgs(callArgs, params), ", "))
+	if len(collectStringParams(params)) > 0 || len(collectBufferParams(params)) > 0 || len(collectUnionParams(params, resolved)) > 0 {
+		writeSwiftCCallWrapped(b, params, resolved, "let op = ", func(b *strings.Builder, indent string) {
+			fmt.Fprintf(b, "%s%s\n", indent, startCall)
+		})
+	} else {
+		fmt.Fprintf(b, "        let op = %s\n", startCall)
+	}
+
+	// Poll: the closure hands the outputs back once the operation is done.
+	var outVars, pollArgs []string
+	pollArgs = append(pollArgs, "op")
+	if hasError {
+		outVars = append(outVars, "code")
+		pollArgs = append(pollArgs, "&code")
+	}
+	if hasReturn {
+		outVars = append(outVars, "result")
+		pollArgs = append(pollArgs, "&result")
+	}
+	if returnsTable(method) {
+		outVars = append(outVars, "resultLen")
+		pollArgs = append(pollArgs, "&resultLen")
+	}
+	outputs := "()"
+	switch len(outVars) {
+	case 0:
+	case 1:
+		outputs = outVars[0]
 	default:
-		fmt.Fprintf(b, "    public static func %s(%s) {\n", swiftMethodName, paramStr)
-		writeSwiftCCallVoid(b, funcName, callArgs, method.Parameters, false, "")
+		outputs = "(" + strings.Join(outVars, ", ") + ")"
+	}
+
+	binding := "        "
+	if len(outVars) > 0 {
+		binding = "        let " + outputs + " = "
+	}
+	fmt.Fprintf(b, "%stry await %sAsync.awaitOperation(op, poll: { op in\n", binding, ToPascalCase(apiName))
+	if hasError {
+		b.WriteString("            var code: Int32 = 0\n")
 	}
+	if returnsTable(method) {
+		b.WriteString("            var result: UnsafeMutablePointer<UInt8>? = nil\n")
+		b.WriteString("            var resultLen: UInt32 = 0\n")
+	} else if hasReturn {
+		fmt.Fprintf(b, "            var result: %s = %s\n", swiftCBridgeType(method.Returns.Type, resolved), swiftDefaultValue(method.Returns.Type))
+	}
+	fmt.Fprintf(b, "            let status = %s(%s)\n", AsyncPollFunctionName(apiName, ifaceName, method.Name), strings.Join(pollArgs, ", "))
+	fmt.Fprintf(b, "            return (status, %s)\n", outputs)
+	fmt.Fprintf(b, "        }, cancel: { %s($0) })\n", AsyncCancelFunctionName(apiName, ifaceName, method.Name))
 
+	if hasError {
+		errEnumName := swiftErrorEnumName(api, method.Error)
+		b.WriteString("        guard code == 0 else {\n")
+		fmt.Fprintf(b, "            throw %s(rawValue: code) ?? %s.internalError\n", errEnumName, errEnumName)
+		b.WriteString("        }\n")
+	}
+	if hasReturn {
+		if handleName, ok := model.IsHandle(method.Returns.Type); ok {
+			fmt.Fprintf(b, "        return %s(handle: result!)\n", handleName)
+		} else if returnsTable(method) {
+			fmt.Fprintf(b, "        return %s\n", swiftTableRoot(method.Returns.Type, fmt.Sprintf("%sBuffers.takeData(result, resultLen)", ToPascalCase(apiName))))
+		} else {
+			fmt.Fprintf(b, "        return %s\n", swiftReturnExpr(api, method.Returns.Type, "result", resolved))
+		}
+	}
 	fmt.Fprintf(b, "    }\n\n")
 }
 
+// writeSwiftStringSupport writes the helper that turns a returned C string into
+// a Swift String and hands the C copy back to the library.
+// takeOptional is only emitted when some method returns an optional string.
+func writeSwiftStringSupport(b *strings.Builder, apiName string, withOptional bool) {
+	fmt.Fprintf(b, `enum %[1]sStrings {
+    /// Copies str into a String and releases it with %[2]s. NULL reads as "".
+    static func take(_ str: UnsafeMutablePointer<CChar>?) -> String {
+        guard let str = str else { return "" }
+        defer { %[2]s(str) }
+        return String(cString: str)
+    }
+`, ToPascalCase(apiName), StringFreeFunctionName(apiName))
+	if withOptional {
+		b.WriteString(`
+    /// Like take, but NULL reads as nil.
+    static func takeOptional(_ str: UnsafeMutablePointer<CChar>?) -> String? {
+        guard str != nil else { return nil }
+        return take(str)
+    }
+`)
+	}
+	b.WriteString("}\n\n")
+}
+
+// writeSwiftOptionalCString writes the helper that passes an optional String
+// to C, as NULL when it is absent.
+func writeSwiftOptionalCString(b *strings.Builder) {
+	b.WriteString(`extension Optional where Wrapped == String {
+    /// Calls body with a C copy of the string, or with nil when it is absent.
+    fileprivate func withOptionalCString<R>(_ body: (UnsafePointer<CChar>?) throws -> R) rethrows -> R {
+        guard let str = self else { return try body(nil) }
+        return try str.withCString(body)
+    }
+}
+
+`)
+}
+
+// writeSwiftOptionalBytes writes the helper that passes an optional table's
+// FlatBuffer to C, as NULL when it is absent.
+func writeSwiftOptionalBytes(b *strings.Builder) {
+	b.WriteString(`extension Optional where Wrapped == Data {
+    /// Calls body with the bytes of the data, or with nil when it is absent.
+    fileprivate func withOptionalBytes<R>(_ body: (UnsafeRawBufferPointer?) throws -> R) rethrows -> R {
+        guard let data = self else { return try body(nil) }
+        return try data.withUnsafeBytes { try body($0) }
+    }
+}
+
+`)
+}
+
+// hasOptionalStringParams reports whether any method takes an optional string.
+func hasOptionalStringParams(api *model.APIDefinition) bool {
+	for _, iface := range api.Interfaces {
+		for _, method := range iface.Methods {
+			for _, p := range method.Parameters {
+				if p.Optional && model.IsString(p.Type) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// writeSwiftBufferSupport writes the helpers that turn a returned C buffer into
+// Data or an array and hand the C memory back to the library.
+func writeSwiftBufferSupport(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, `enum %[1]sBuffers {
+    /// Wraps a returned byte buffer in Data without copying; the memory is
+    /// released with %[2]s when the Data goes away. NULL reads as empty.
+    static func takeData<T>(_ ptr: UnsafeMutablePointer<T>?, _ count: UInt32) -> Data {
+        guard let ptr = ptr else { return Data() }
+        return Data(bytesNoCopy: ptr, count: Int(count) * MemoryLayout<T>.stride,
+                    deallocator: .custom { p, _ in %[2]s(p) })
+    }
+
+    /// Copies a returned buffer into an array and releases it with %[2]s.
+    /// NULL reads as empty.
+    static func take<T>(_ ptr: UnsafeMutablePointer<T>?, _ count: UInt32) -> [T] {
+        guard let ptr = ptr else { return [] }
+        defer { %[2]s(ptr) }
+        return Array(UnsafeBufferPointer(start: ptr, count: Int(count)))
+    }
+}
+
+`, ToPascalCase(apiName), BufferFreeFunctionName(apiName))
+}
+
+// swiftReturnExpr wraps expr, the raw C value of a return of type t, in the
+// conversion to its Swift return value. A union converts to its Swift enum,
+// or nil when its type is NONE.
+func swiftReturnExpr(api *model.APIDefinition, t, expr string, resolved resolver.ResolvedTypes) string {
+	if model.IsString(t) {
+		return ToPascalCase(api.API.Name) + "Strings.take(" + expr + ")"
+	}
+	if isUnionType(resolved, t) {
+		return swiftUnionName(api, t) + "(" + expr + ")"
+	}
+	return expr
+}
+
+// swiftResultExpr is swiftReturnExpr for a synchronous result: a handle is
+// wrapped in its class, and an absent optional string or handle becomes nil.
+func swiftResultExpr(api *model.APIDefinition, ret *model.ReturnDef, expr string, resolved resolver.ResolvedTypes) string {
+	if ret.Optional && model.IsString(ret.Type) {
+		return ToPascalCase(api.API.Name) + "Strings.takeOptional(" + expr + ")"
+	}
+	if _, ok := model.IsHandle(ret.Type); ok {
+		if ret.Optional {
+			return expr + ".map { " + swiftHandleInit(ret, "$0") + " }"
+		}
+		return swiftHandleInit(ret, expr+"!")
+	}
+	return swiftReturnExpr(api, ret.Type, expr, resolved)
+}
+
+// swiftHandleInit returns the expression wrapping a returned handle in its
+// class. A borrowed handle's wrapper does not own it.
+func swiftHandleInit(ret *model.ReturnDef, expr string) string {
+	handleName, _ := model.IsHandle(ret.Type)
+	if ret.Borrowed {
+		return handleName + "(handle: " + expr + ", owned: false)"
+	}
+	return handleName + "(handle: " + expr + ")"
+}
+
+// swiftResultType returns the Swift type a method returns. Optional results
+// are Swift optionals, as are unions, which may be NONE.
+func swiftResultType(api *model.APIDefinition, ret *model.ReturnDef, resolved resolver.ResolvedTypes) string {
+	t := swiftType(api, ret.Type, resolved)
+	if ret.Optional || isUnionType(resolved, ret.Type) {
+		t += "?"
+	}
+	return t
+}
+
+// swiftName returns the Swift name of a snake_case parameter or method name,
+// escaped if it is a keyword.
+func swiftName(name string) string {
+	return swiftIdent(ToCamelCase(name))
+}
+
 // swiftParamAndCallArg returns the Swift parameter declaration(s) and C call argument(s)
-// for a given parameter definition.
-func swiftParamAndCallArg(p *model.ParameterDef, resolved resolver.ResolvedTypes) (swiftParams []string, callArgs []string) {
-	paramName := ToCamelCase(p.Name)
+// for a given parameter definition. Optional parameters are Swift optionals;
+// absent primitives pass a cleared presence flag.
+func swiftParamAndCallArg(api *model.APIDefinition, p *model.ParameterDef, resolved resolver.ResolvedTypes) (swiftParams []string, callArgs []string) {
+	raw := ToCamelCase(p.Name)
+	paramName := swiftIdent(raw)
+	optional := ""
+	if p.Optional {
+		optional = "?"
+	}
 
 	if model.IsString(p.Type) {
-		swiftParams = append(swiftParams, paramName+": String")
+		swiftParams = append(swiftParams, paramName+": String"+optional)
 		// The call arg is handled specially via withCString
-		callArgs = append(callArgs, paramName)
+		callArgs = append(callArgs, raw)
 		return
 	}
 
@@ -385,14 +1118,41 @@ func swiftParamAndCallArg(p *model.ParameterDef, resolved resolver.ResolvedTypes
 			swiftParams = append(swiftParams, paramName+": Data")
 		}
 		// Buffer expands to pointer + length in the C call
-		callArgs = append(callArgs, paramName, paramName+"_len")
+		callArgs = append(callArgs, raw, raw+"_len")
+		return
+	}
+
+	// A table is passed as its finished FlatBuffer, e.g. FlatBufferBuilder.data
+	if p.Table {
+		swiftParams = append(swiftParams, paramName+": Data"+optional)
+		callArgs = append(callArgs, raw, raw+"_len")
 		return
 	}
 
-	if _, ok := model.IsHandle(p.Type); ok {
-		handleName, _ := model.IsHandle(p.Type)
-		swiftParams = append(swiftParams, paramName+": "+handleName)
-		callArgs = append(callArgs, paramName+".handle")
+	if handleName, ok := model.IsHandle(p.Type); ok {
+		swiftType := handleName + optional
+		if isBaseHandle(api, handleName) {
+			// Accept any class conforming to the base handle's protocol.
+			swiftType = "any " + swiftHandleProtocolName(handleName)
+			if p.Optional {
+				swiftType = "(" + swiftType + ")?"
+			}
+		}
+		swiftParams = append(swiftParams, paramName+": "+swiftType)
+		switch {
+		case !isMoveParam(p):
+			callArgs = append(callArgs, paramName+optional+".handle")
+		case isBaseHandle(api, handleName) && p.Optional:
+			callArgs = append(callArgs, paramName+".map { "+swiftTakeExpr(api, "$0", true)+" }")
+		default:
+			callArgs = append(callArgs, swiftTakeExpr(api, paramName+optional, isBaseHandle(api, handleName)))
+		}
+		return
+	}
+
+	if hasPresenceFlag(p) {
+		swiftParams = append(swiftParams, paramName+": "+swiftPrimitiveType(p.Type)+"?")
+		callArgs = append(callArgs, paramName+" != nil", paramName+" ?? "+swiftDefaultValue(p.Type))
 		return
 	}
 
@@ -402,9 +1162,17 @@ func swiftParamAndCallArg(p *model.ParameterDef, resolved resolver.ResolvedTypes
 		return
 	}
 
+	// A union passed by value takes the Swift enum and passes its C form,
+	// which is only valid within withCValue
+	if isUnionByValue(p, resolved) {
+		swiftParams = append(swiftParams, paramName+": "+swiftUnionName(api, p.Type))
+		callArgs = append(callArgs, raw+"C")
+		return
+	}
+
 	// FlatBuffer type — pass as opaque pointer
 	fbType := swiftFlatBufferParamType(p.Type, p.Transfer)
-	swiftParams = append(swiftParams, paramName+": "+fbType)
+	swiftParams = append(swiftParams, paramName+": "+fbType+optional)
 	callArgs = append(callArgs, paramName)
 	return
 }
@@ -417,9 +1185,10 @@ func swiftParamAndCallArg(p *model.ParameterDef, resolved resolver.ResolvedTypes
 //   - "return "     — method returns a value, infallible
 //   - "try "        — void method that may throw
 //   - ""            — void infallible method
-func writeSwiftCCallWrapped(b *strings.Builder, params []model.ParameterDef, firstClosurePrefix string, body func(*strings.Builder, string)) {
+func writeSwiftCCallWrapped(b *strings.Builder, params []model.ParameterDef, resolved resolver.ResolvedTypes, firstClosurePrefix string, body func(*strings.Builder, string)) {
 	stringParams := collectStringParams(params)
 	bufferParams := collectBufferParams(params)
+	unionParams := collectUnionParams(params, resolved)
 
 	indent := "        "
 	closingBraces := ""
@@ -435,7 +1204,11 @@ func writeSwiftCCallWrapped(b *strings.Builder, params []model.ParameterDef, fir
 
 	for _, sp := range stringParams {
 		paramName := ToCamelCase(sp.Name)
-		fmt.Fprintf(b, "%s%s%s.withCString { %sPtr in\n", indent, nextPrefix(), paramName, paramName)
+		withFunc := "withCString"
+		if sp.Optional {
+			withFunc = "withOptionalCString"
+		}
+		fmt.Fprintf(b, "%s%s%s.%s { %sPtr in\n", indent, nextPrefix(), swiftIdent(paramName), withFunc, paramName)
 		indent += "    "
 		closingBraces += indent[:len(indent)-4] + "}\n"
 	}
@@ -443,73 +1216,175 @@ func writeSwiftCCallWrapped(b *strings.Builder, params []model.ParameterDef, fir
 	for _, bp := range bufferParams {
 		paramName := ToCamelCase(bp.Name)
 		prefix := nextPrefix()
-		if bp.Transfer == "ref_mut" {
-			fmt.Fprintf(b, "%s%s%s.withUnsafeMutableBufferPointer { %sPtr in\n", indent, prefix, paramName, paramName)
-		} else {
-			fmt.Fprintf(b, "%s%s%s.withUnsafeBytes { %sPtr in\n", indent, prefix, paramName, paramName)
+		switch {
+		case bp.Transfer == "ref_mut":
+			fmt.Fprintf(b, "%s%s%s.withUnsafeMutableBufferPointer { %sPtr in\n", indent, prefix, swiftIdent(paramName), paramName)
+		case bp.Table && bp.Optional:
+			fmt.Fprintf(b, "%s%s%s.withOptionalBytes { %sPtr in\n", indent, prefix, swiftIdent(paramName), paramName)
+		default:
+			fmt.Fprintf(b, "%s%s%s.withUnsafeBytes { %sPtr in\n", indent, prefix, swiftIdent(paramName), paramName)
 		}
 		indent += "    "
 		closingBraces += indent[:len(indent)-4] + "}\n"
 	}
 
+	for _, up := range unionParams {
+		paramName := ToCamelCase(up.Name)
+		fmt.Fprintf(b, "%s%s%s.withCValue { %sC in\n", indent, nextPrefix(), swiftIdent(paramName), paramName)
+		indent += "    "
+		closingBraces += indent[:len(indent)-4] + "}\n"
+	}
+
 	body(b, indent)
 
 	b.WriteString(closingBraces)
 }
 
-// writeSwiftCCall writes the C function call for a factory method (returns handle, with out-param).
-func writeSwiftCCall(b *strings.Builder, funcName string, callArgs []string, params []model.ParameterDef, outVar string, hasError bool, errEnumName string, handleClass string) {
+// writeSwiftCCall writes the C function call for a method returning a handle:
+// through an out-param when fallible, else directly. An optional handle
+// result returns nil when the handle is NULL.
+func writeSwiftCCall(b *strings.Builder, funcName string, callArgs []string, params []model.ParameterDef, resolved resolver.ResolvedTypes, outVar string, hasError bool, errEnumName string, ret *model.ReturnDef) {
+	optional := ret.Optional
 	firstPrefix := "return "
 	if hasError {
 		firstPrefix = "return try "
 	}
-	writeSwiftCCallWrapped(b, params, firstPrefix, func(b *strings.Builder, indent string) {
+	writeSwiftCCallWrapped(b, params, resolved, firstPrefix, func(b *strings.Builder, indent string) {
+		actualArgs := buildActualCallArgs(callArgs, params)
+		if hasError {
+			actualArgs = append(actualArgs, "&"+outVar)
+		}
+		callStr := fmt.Sprintf("%s(%s)", funcName, strings.Join(actualArgs, ", "))
+		switch {
+		case hasError && optional:
+			fmt.Fprintf(b, "%slet code = %s\n", indent, callStr)
+			fmt.Fprintf(b, "%sguard code == 0 else {\n", indent)
+			fmt.Fprintf(b, "%s    throw %s(rawValue: code) ?? %s.internalError\n", indent, errEnumName, errEnumName)
+			fmt.Fprintf(b, "%s}\n", indent)
+			fmt.Fprintf(b, "%sreturn %s.map { %s }\n", indent, outVar, swiftHandleInit(ret, "$0"))
+		case hasError:
+			fmt.Fprintf(b, "%slet code = %s\n", indent, callStr)
+			fmt.Fprintf(b, "%sguard code == 0, let ptr = %s else {\n", indent, outVar)
+			fmt.Fprintf(b, "%s    throw %s(rawValue: code) ?? %s.internalError\n", indent, errEnumName, errEnumName)
+			fmt.Fprintf(b, "%s}\n", indent)
+			fmt.Fprintf(b, "%sreturn %s\n", indent, swiftHandleInit(ret, "ptr"))
+		case optional:
+			fmt.Fprintf(b, "%sreturn %s.map { %s }\n", indent, callStr, swiftHandleInit(ret, "$0"))
+		default:
+			fmt.Fprintf(b, "%sreturn %s\n", indent, swiftHandleInit(ret, callStr+"!"))
+		}
+	})
+}
+
+// writeSwiftCCallPrimitive writes a C call with a primitive, string, or FlatBuffer
+// out-parameter. retExpr is the Swift expression returned once outVar is filled.
+func writeSwiftCCallPrimitive(b *strings.Builder, funcName string, callArgs []string, params []model.ParameterDef, resolved resolver.ResolvedTypes, outVar, retExpr string, hasError bool, errEnumName string) {
+	firstPrefix := "return "
+	if hasError {
+		firstPrefix = "return try "
+	}
+	writeSwiftCCallWrapped(b, params, resolved, firstPrefix, func(b *strings.Builder, indent string) {
 		actualArgs := buildActualCallArgs(callArgs, params)
 		actualArgs = append(actualArgs, "&"+outVar)
 		callStr := fmt.Sprintf("%s(%s)", funcName, strings.Join(actualArgs, ", "))
 		if hasError {
 			fmt.Fprintf(b, "%slet code = %s\n", indent, callStr)
-			fmt.Fprintf(b, "%sguard code == 0, let ptr = %s else {\n", indent, outVar)
+			fmt.Fprintf(b, "%sguard code == 0 else {\n", indent)
 			fmt.Fprintf(b, "%s    throw %s(rawValue: code) ?? %s.internalError\n", indent, errEnumName, errEnumName)
 			fmt.Fprintf(b, "%s}\n", indent)
-			fmt.Fprintf(b, "%sreturn %s(handle: ptr)\n", indent, handleClass)
+			fmt.Fprintf(b, "%sreturn %s\n", indent, retExpr)
 		} else {
 			fmt.Fprintf(b, "%s_ = %s\n", indent, callStr)
-			fmt.Fprintf(b, "%sreturn %s(handle: %s!)\n", indent, handleClass, outVar)
+			fmt.Fprintf(b, "%sreturn %s\n", indent, retExpr)
 		}
 	})
 }
 
-// writeSwiftCCallPrimitive writes a C call with a primitive or FlatBuffer out-parameter.
-func writeSwiftCCallPrimitive(b *strings.Builder, funcName string, callArgs []string, params []model.ParameterDef, outVar string, hasError bool, errEnumName string) {
+// writeSwiftCCallBuffer writes a C call that passes a buffer<T> result out
+// through a data pointer and element count, then takes ownership of it. A
+// table result is read with its flatc accessor; an absent one is nil.
+func writeSwiftCCallBuffer(b *strings.Builder, api *model.APIDefinition, funcName string, callArgs []string, params []model.ParameterDef, resolved resolver.ResolvedTypes, method *model.MethodDef) {
+	elemType, _ := returnBufferElem(method)
+	hasError := method.Error != ""
+	errEnumName := swiftErrorEnumName(api, method.Error)
+
+	take := "take"
+	if swiftBufferIsData(elemType) {
+		take = "takeData"
+	}
+	pascalAPI := ToPascalCase(api.API.Name)
+	retExpr := fmt.Sprintf("%sBuffers.%s(result, resultLen)", pascalAPI, take)
+	if returnsTable(method) {
+		elemType = "uint8"
+		retExpr = swiftTableRoot(method.Returns.Type, fmt.Sprintf("%sBuffers.takeData(result, resultLen)", pascalAPI))
+	}
+
+	fmt.Fprintf(b, "        var result: UnsafeMutablePointer<%s>? = nil\n", swiftPrimitiveType(elemType))
+	b.WriteString("        var resultLen: UInt32 = 0\n")
+
 	firstPrefix := "return "
 	if hasError {
 		firstPrefix = "return try "
 	}
-	writeSwiftCCallWrapped(b, params, firstPrefix, func(b *strings.Builder, indent string) {
+	writeSwiftCCallWrapped(b, params, resolved, firstPrefix, func(b *strings.Builder, indent string) {
 		actualArgs := buildActualCallArgs(callArgs, params)
-		actualArgs = append(actualArgs, "&"+outVar)
+		actualArgs = append(actualArgs, "&result", "&resultLen")
 		callStr := fmt.Sprintf("%s(%s)", funcName, strings.Join(actualArgs, ", "))
 		if hasError {
 			fmt.Fprintf(b, "%slet code = %s\n", indent, callStr)
 			fmt.Fprintf(b, "%sguard code == 0 else {\n", indent)
 			fmt.Fprintf(b, "%s    throw %s(rawValue: code) ?? %s.internalError\n", indent, errEnumName, errEnumName)
 			fmt.Fprintf(b, "%s}\n", indent)
-			fmt.Fprintf(b, "%sreturn %s\n", indent, outVar)
 		} else {
-			fmt.Fprintf(b, "%s_ = %s\n", indent, callStr)
-			fmt.Fprintf(b, "%sreturn %s\n", indent, outVar)
+			fmt.Fprintf(b, "%s%s\n", indent, callStr)
+		}
+		if returnsTable(method) && method.Returns.Optional {
+			fmt.Fprintf(b, "%sguard result != nil else { return nil }\n", indent)
 		}
+		fmt.Fprintf(b, "%sreturn %s\n", indent, retExpr)
+	})
+}
+
+// writeSwiftCCallOptional writes a C call that passes an optional by-value
+// result out with a presence flag, returning nil when the flag is cleared.
+func writeSwiftCCallOptional(b *strings.Builder, api *model.APIDefinition, funcName string, callArgs []string, params []model.ParameterDef, method *model.MethodDef, resolved resolver.ResolvedTypes) {
+	hasError := method.Error != ""
+	errEnumName := swiftErrorEnumName(api, method.Error)
+
+	fmt.Fprintf(b, "        var result: %s = %s\n", swiftCBridgeType(method.Returns.Type, resolved), swiftDefaultValue(method.Returns.Type))
+	b.WriteString("        var hasResult = false\n")
+	value := "result"
+	if isUnionType(resolved, method.Returns.Type) {
+		value = swiftUnionName(api, method.Returns.Type) + "(result)"
+	}
+
+	firstPrefix := "return "
+	if hasError {
+		firstPrefix = "return try "
+	}
+	writeSwiftCCallWrapped(b, params, resolved, firstPrefix, func(b *strings.Builder, indent string) {
+		actualArgs := buildActualCallArgs(callArgs, params)
+		actualArgs = append(actualArgs, "&result", "&hasResult")
+		callStr := fmt.Sprintf("%s(%s)", funcName, strings.Join(actualArgs, ", "))
+		if hasError {
+			fmt.Fprintf(b, "%slet code = %s\n", indent, callStr)
+			fmt.Fprintf(b, "%sguard code == 0 else {\n", indent)
+			fmt.Fprintf(b, "%s    throw %s(rawValue: code) ?? %s.internalError\n", indent, errEnumName, errEnumName)
+			fmt.Fprintf(b, "%s}\n", indent)
+		} else {
+			fmt.Fprintf(b, "%s%s\n", indent, callStr)
+		}
+		fmt.Fprintf(b, "%sreturn hasResult ? %s : nil\n", indent, value)
 	})
 }
 
 // writeSwiftCCallVoid writes a C call with no return value.
-func writeSwiftCCallVoid(b *strings.Builder, funcName string, callArgs []string, params []model.ParameterDef, hasError bool, errEnumName string) {
+func writeSwiftCCallVoid(b *strings.Builder, funcName string, callArgs []string, params []model.ParameterDef, resolved resolver.ResolvedTypes, hasError bool, errEnumName string) {
 	firstPrefix := ""
 	if hasError {
 		firstPrefix = "try "
 	}
-	writeSwiftCCallWrapped(b, params, firstPrefix, func(b *strings.Builder, indent string) {
+	writeSwiftCCallWrapped(b, params, resolved, firstPrefix, func(b *strings.Builder, indent string) {
 		actualArgs := buildActualCallArgs(callArgs, params)
 		callStr := fmt.Sprintf("%s(%s)", funcName, strings.Join(actualArgs, ", "))
 		if hasError {
@@ -523,12 +1398,13 @@ func writeSwiftCCallVoid(b *strings.Builder, funcName string, callArgs []string,
 	})
 }
 
-// writeSwiftCCallDirect writes a C call that directly returns its value.
-func writeSwiftCCallDirect(b *strings.Builder, funcName string, callArgs []string, params []model.ParameterDef) {
-	writeSwiftCCallWrapped(b, params, "return ", func(b *strings.Builder, indent string) {
+// writeSwiftCCallDirect writes a C call that directly returns its value. wrap
+// converts the raw call expression into the Swift return value.
+func writeSwiftCCallDirect(b *strings.Builder, funcName string, callArgs []string, params []model.ParameterDef, resolved resolver.ResolvedTypes, wrap func(call string) string) {
+	writeSwiftCCallWrapped(b, params, resolved, "return ", func(b *strings.Builder, indent string) {
 		actualArgs := buildActualCallArgs(callArgs, params)
 		callStr := fmt.Sprintf("%s(%s)", funcName, strings.Join(actualArgs, ", "))
-		fmt.Fprintf(b, "%sreturn %s\n", indent, callStr)
+		fmt.Fprintf(b, "%sreturn %s\n", indent, wrap(callStr))
 	})
 }
 
@@ -543,17 +1419,35 @@ func collectStringParams(params []model.ParameterDef) []model.ParameterDef {
 	return result
 }
 
-// collectBufferParams returns parameters that are buffer<T> type.
+// collectBufferParams returns parameters that are buffer<T> type or tables,
+// which are passed as bytes.
 func collectBufferParams(params []model.ParameterDef) []model.ParameterDef {
 	var result []model.ParameterDef
 	for _, p := range params {
-		if _, ok := model.IsBuffer(p.Type); ok {
+		if _, ok := model.IsBuffer(p.Type); ok || p.Table {
 			result = append(result, p)
 		}
 	}
 	return result
 }
 
+// collectUnionParams returns the unions passed by value, whose C form is
+// built by withCValue.
+func collectUnionParams(params []model.ParameterDef, resolved resolver.ResolvedTypes) []model.ParameterDef {
+	var result []model.ParameterDef
+	for i := range params {
+		if isUnionByValue(&params[i], resolved) {
+			result = append(result, params[i])
+		}
+	}
+	return result
+}
+
+// isUnionByValue reports whether p is a union passed by value.
+func isUnionByValue(p *model.ParameterDef, resolved resolver.ResolvedTypes) bool {
+	return isUnionType(resolved, p.Type) && p.Transfer != "ref" && p.Transfer != "ref_mut"
+}
+
 // buildActualCallArgs translates Swift parameter names to actual C call arguments,
 // replacing string params with their withCString closure variable and buffer params
 // with pointer + count.
@@ -576,9 +1470,16 @@ func buildActualCallArgs(callArgs []string, params []model.ParameterDef) []strin
 			continue
 		}
 
-		// Check if this is a buffer param
+		// Check if this is an optional table param, which may be absent
+		if p, ok := paramTypes[arg]; ok && p.Table && p.Optional {
+			result = append(result, arg+"Ptr?.baseAddress?.assumingMemoryBound(to: UInt8.self)", "UInt32("+arg+"Ptr?.count ?? 0)")
+			i += 2
+			continue
+		}
+
+		// Check if this is a buffer or table param
 		if p, ok := paramTypes[arg]; ok {
-			if _, isBuf := model.IsBuffer(p.Type); isBuf {
+			if _, isBuf := model.IsBuffer(p.Type); isBuf || p.Table {
 				paramName := arg
 				if p.Transfer == "ref_mut" {
 					result = append(result, paramName+"Ptr.baseAddress")
@@ -587,7 +1488,7 @@ func buildActualCallArgs(callArgs []string, params []model.ParameterDef) []strin
 				}
 				// Skip the _len arg and replace with count
 				i++
-				result = append(result, "UInt32("+paramName+".count)")
+				result = append(result, "UInt32("+swiftIdent(paramName)+".count)")
 				i++
 				continue
 			}
@@ -606,12 +1507,15 @@ func isHandleReturn(retType string) bool {
 }
 
 // swiftType returns the Swift type for an API type.
-func swiftType(t string, resolved resolver.ResolvedTypes) string {
+func swiftType(api *model.APIDefinition, t string, resolved resolver.ResolvedTypes) string {
 	if model.IsString(t) {
 		return "String"
 	}
-	if _, ok := model.IsBuffer(t); ok {
-		return "Data"
+	if elemType, ok := model.IsBuffer(t); ok {
+		if swiftBufferIsData(elemType) {
+			return "Data"
+		}
+		return "[" + swiftPrimitiveType(elemType) + "]"
 	}
 	if handleName, ok := model.IsHandle(t); ok {
 		return handleName
@@ -619,10 +1523,19 @@ func swiftType(t string, resolved resolver.ResolvedTypes) string {
 	if model.IsPrimitive(t) {
 		return swiftPrimitiveType(t)
 	}
+	if isUnionType(resolved, t) {
+		return swiftUnionName(api, t)
+	}
 	// FlatBuffer type — use the C struct name
 	return model.FlatBufferCType(t)
 }
 
+// swiftBufferIsData reports whether a returned buffer<T> surfaces as Data.
+// Byte buffers do; wider elements surface as typed arrays.
+func swiftBufferIsData(elemType string) bool {
+	return elemType == "uint8" || elemType == "int8"
+}
+
 // swiftPrimitiveType converts an API primitive to its Swift equivalent.
 func swiftPrimitiveType(t string) string {
 	switch t {
@@ -655,6 +1568,9 @@ func swiftPrimitiveType(t string) string {
 
 // swiftCBridgeType returns the C bridge type for use in variable declarations.
 func swiftCBridgeType(t string, resolved resolver.ResolvedTypes) string {
+	if model.IsString(t) {
+		return "UnsafeMutablePointer<CChar>?"
+	}
 	if _, ok := model.IsHandle(t); ok {
 		return "OpaquePointer?"
 	}
@@ -667,6 +1583,9 @@ func swiftCBridgeType(t string, resolved resolver.ResolvedTypes) string {
 
 // swiftDefaultValue returns the default zero value for a type.
 func swiftDefaultValue(t string) string {
+	if model.IsString(t) {
+		return "nil"
+	}
 	if _, ok := model.IsHandle(t); ok {
 		return "nil"
 	}
@@ -182,19 +182,20 @@ func TestSwiftGenerator_FlatBufferParams(t *testing.T) {
 
 	content := string(files[0].Content)
 
-	// create_renderer takes a FlatBuffer config param with transfer: ref
-	if !strings.Contains(content, "config: UnsafePointer<Rendering_RendererConfig>") {
-		t.Error("missing FlatBuffer ref parameter type for config")
+	// Tables are passed as their finished FlatBuffers
+	if !strings.Contains(content, "createRenderer(config: Data) throws -> Renderer {") {
+		t.Error("missing table parameter type for config")
 	}
-
-	// push_touch_events takes FlatBuffer events param with transfer: ref
-	if !strings.Contains(content, "events: UnsafePointer<Input_TouchEventBatch>") {
-		t.Error("missing FlatBuffer ref parameter type for events")
+	if !strings.Contains(content, "events.withUnsafeBytes { eventsPtr in") {
+		t.Error("table parameter bytes should be borrowed for the call")
 	}
 
-	// poll_events takes FlatBuffer events param with transfer: ref_mut
-	if !strings.Contains(content, "events: UnsafeMutablePointer<Common_EventQueue>") {
-		t.Error("missing FlatBuffer ref_mut parameter type for events")
+	// poll_events returns a table, read with its flatc accessor
+	if !strings.Contains(content, "return Common_EventQueue.getRootAsEventQueue(bb: ByteBuffer(data: ExampleAppEngineBuffers.takeData(result, resultLen)))") {
+		t.Error("missing flatc accessor for the returned table")
+	}
+	if !strings.Contains(content, "import Foundation\nimport FlatBuffers\n") {
+		t.Error("tables need the FlatBuffers module")
 	}
 }
 
@@ -353,3 +354,370 @@ func TestSwiftGenerator_Registry(t *testing.T) {
 		t.Errorf("expected name 'swift', got %q", g.Name())
 	}
 }
+
+func TestSwiftGenerator_Events(t *testing.T) {
+	ctx := loadTestAPI(t, "events.yaml")
+	gen := &SwiftGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"public enum EventApiEventKind: UInt32 {\n    case touch = 1\n    case entitySpawned = 2\n}",
+		"public struct EventApiEvent {",
+		"public enum EventApiEvents {",
+		"public static func poll() -> EventApiEvent? {",
+		"event_api_event_poll(&kind, buf.baseAddress, UInt32(buf.count))",
+		"public static func drain(_ handler: (EventApiEvent) -> Void) -> Int {",
+		"public static func subscribe(queue: DispatchQueue = .main, _ handler: @escaping (EventApiEvent) -> Void) -> DispatchSourceRead? {",
+		"let fd = event_api_event_signal_fd()",
+		"DispatchSource.makeReadSource(fileDescriptor: fd, queue: queue)",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Swift file missing %q", want)
+		}
+	}
+}
+
+func TestSwiftGenerator_Async(t *testing.T) {
+	ctx := loadTestAPI(t, "async.yaml")
+	gen := &SwiftGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"public struct AsyncApiAsyncStartError: Error {}",
+		"static func awaitOperation<T>(_ op: OpaquePointer?, poll: (OpaquePointer) -> (Int32, T), cancel: (OpaquePointer) -> Void) async throws -> T {",
+		"try await Task.sleep(nanoseconds: delayMs * 1_000_000)",
+		"public func loadModel(path: String) async throws -> Common_EntityId {",
+		"let op = path.withCString { pathPtr in\n            async_api_assets_load_model_start(handle, pathPtr)\n        }",
+		"let (code, result, resultLen) = try await AsyncApiAsync.awaitOperation(op, poll: { op in",
+		"let status = async_api_assets_load_model_poll(op, &code, &result, &resultLen)",
+		"return Common_EntityId.getRootAsEntityId(bb: ByteBuffer(data: AsyncApiBuffers.takeData(result, resultLen)))",
+		"}, cancel: { async_api_assets_load_model_cancel($0) })",
+		"public func warmUp() async throws {",
+		"public func forkEngine() async throws -> Engine {",
+		"return Engine(handle: result!)",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Swift file missing %q", want)
+		}
+	}
+}
+
+func TestSwiftGenerator_StringReturns(t *testing.T) {
+	ctx := loadTestAPI(t, "strings.yaml")
+	gen := &SwiftGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"static func take(_ str: UnsafeMutablePointer<CChar>?) -> String {",
+		"defer { strings_api_string_free(str) }",
+		"public func getName() -> String {\n        return StringsApiStrings.take(strings_api_text_get_name(handle))\n    }",
+		"public func describe(key: String) throws -> String {\n        var result: UnsafeMutablePointer<CChar>? = nil",
+		"            return StringsApiStrings.take(result)\n",
+		"public func fetchLabel() async throws -> String {",
+		"        return StringsApiStrings.take(result)\n    }",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Swift file missing %q", want)
+		}
+	}
+
+	ctx = loadTestAPI(t, "minimal.yaml")
+	files, err = gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if strings.Contains(string(files[0].Content), "Strings.take") {
+		t.Error("string helper should only be emitted when strings are returned")
+	}
+}
+
+func TestSwiftGenerator_BufferReturns(t *testing.T) {
+	ctx := loadTestAPI(t, "buffers.yaml")
+	gen := &SwiftGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"enum BuffersApiBuffers {",
+		"deallocator: .custom { p, _ in buffers_api_buffer_free(p) })",
+		"defer { buffers_api_buffer_free(ptr) }",
+		"public func encodeFrame(pixels: Data) throws -> Data {",
+		"return BuffersApiBuffers.takeData(result, resultLen)",
+		"public func readTensor() -> [Float] {\n        var result: UnsafeMutablePointer<Float>? = nil\n        var resultLen: UInt32 = 0\n        buffers_api_media_read_tensor(handle, &result, &resultLen)\n        return BuffersApiBuffers.take(result, resultLen)\n    }",
+		"public func sampleIds(count: UInt32) throws -> [Int64] {",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Swift file missing %q", want)
+		}
+	}
+
+	ctx = loadTestAPI(t, "minimal.yaml")
+	files, err = gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if strings.Contains(string(files[0].Content), "Buffers.take") {
+		t.Error("buffer helpers should only be emitted when buffers are returned")
+	}
+}
+
+func TestSwiftGenerator_Optionals(t *testing.T) {
+	ctx := loadTestAPI(t, "optional.yaml")
+	gen := &SwiftGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"static func takeOptional(_ str: UnsafeMutablePointer<CChar>?) -> String? {",
+		"fileprivate func withOptionalCString<R>(_ body: (UnsafePointer<CChar>?) throws -> R) rethrows -> R {",
+		"public func setLabel(label: String?) {\n        label.withOptionalCString { labelPtr in",
+		"public func setLimit(limit: UInt32?) throws {",
+		"fileprivate func withOptionalBytes<R>(_ body: (UnsafeRawBufferPointer?) throws -> R) rethrows -> R {",
+		"public func attach(parent: Engine?, config: Data?) {\n        config.withOptionalBytes { configPtr in\n            optional_api_scene_attach(handle, parent?.handle, configPtr?.baseAddress?.assumingMemoryBound(to: UInt8.self), UInt32(configPtr?.count ?? 0))\n",
+		"public func findChild(name: String) throws -> Engine? {",
+		"public func getLimit() -> UInt32? {",
+		"var hasResult = false\n        optional_api_scene_get_limit(handle, &result, &hasResult)\n        return hasResult ? result : nil",
+		"public func getConfig() throws -> Rendering_RendererConfig? {",
+		"        guard result != nil else { return nil }\n        return Rendering_RendererConfig.getRootAsRendererConfig(",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Swift file missing %q", want)
+		}
+	}
+
+	ctx = loadTestAPI(t, "minimal.yaml")
+	files, err = gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	if strings.Contains(string(files[0].Content), "withOptionalCString") {
+		t.Error("optional C string helper should only be emitted for optional string parameters")
+	}
+}
+
+func TestSwiftGenerator_Extends(t *testing.T) {
+	ctx := loadTestAPI(t, "extends.yaml")
+	gen := &SwiftGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"public protocol NodeProtocol: AnyObject {\n    var handle: OpaquePointer { get }\n}",
+		"extension NodeProtocol {\n    public func setName(name: String) {",
+		"public final class Node: NodeProtocol {\n    public let handle: OpaquePointer",
+		"public final class Texture: NodeProtocol {\n    public let handle: OpaquePointer",
+		"public final class Engine {\n    let handle: OpaquePointer",
+		"public func attachTo(parent: any NodeProtocol) {",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Swift file missing %q", want)
+		}
+	}
+	if strings.Count(content, "public func setName(") != 1 {
+		t.Error("inherited methods should only be declared in the protocol extension")
+	}
+}
+
+func TestSwiftGenerator_Lifecycle(t *testing.T) {
+	ctx := loadTestAPI(t, "lifecycle.yaml")
+	gen := &SwiftGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"/// Legacy 2D sprite\n/// - Since: 1.0.0\n@available(*, deprecated, message: \"Sprites are drawn as textured quads now. Use Quad instead.\")\npublic final class Sprite {",
+		"/// - Important: Experimental: May change or be removed without a deprecation period.\npublic final class Probe {",
+		"    @available(*, deprecated, message: \"Pass options explicitly. Use createEngine instead.\")\n    public static func createEngineLegacy() throws -> Engine {",
+		"    /// - Since: 1.0.0\n    @available(*, deprecated, message: \"Quads replace sprites. Use drawQuad instead.\")\n    public func drawSprite(x: Float) {",
+		"    /// - Since: 1.2.0\n    public func drawQuad(x: Float) {",
+		"    @available(*, deprecated, message: \"The legacy \\\"immediate\\\" path is retired\")\n    public func flush() {",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Swift file missing %q", want)
+		}
+	}
+}
+
+func TestSwiftGenerator_ThreadAffinity(t *testing.T) {
+	ctx := loadTestAPI(t, "threading.yaml")
+	gen := &SwiftGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"/// UI window, main thread only\n/// - Note: Release the last reference on the main thread.\npublic final class Window {",
+		"    @MainActor\n    public static func createWindow() throws -> Window {",
+		"    @MainActor\n    public func setTitle(title: String) {",
+		"    /// - Note: Must be called on the thread that created the Session.\n    public func fetch() async throws -> Int32 {",
+		"    @MainActor\n    public func showCount() {",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Swift file missing %q", want)
+		}
+	}
+	if strings.Contains(content, "@MainActor\n    public func contentScale") {
+		t.Error("content_scale may be called from any thread")
+	}
+}
+
+func TestSwiftGenerator_Ownership(t *testing.T) {
+	ctx := loadTestAPI(t, "ownership.yaml")
+	gen := &SwiftGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"protocol OwnershipApiMovableHandle: AnyObject {\n    func take() -> OpaquePointer\n}",
+		"public final class Texture: NodeProtocol, OwnershipApiMovableHandle {\n    private var nativeHandle: OpaquePointer?\n    private let owned: Bool\n",
+		"public final class Material: OwnershipApiMovableHandle {",
+		"    init(handle: OpaquePointer, owned: Bool = true) {",
+		"        if owned, let handle = nativeHandle {\n            ownership_api_texture_destroy_texture(handle)\n        }",
+		"ownership_api_scene_attach(handle, (node as! OwnershipApiMovableHandle).take())",
+		"        return Node(handle: ownership_api_scene_root_node(handle)!, owned: false)",
+		"    public static func rootNode(scene: Scene) -> Node {\n        _ = OwnershipApiABI.checked\n        return Node(handle: ownership_api_scene_root_node(scene.handle)!, owned: false)",
+		"            return result.map { Texture(handle: $0, owned: false) }",
+		"ownership_api_scene_preload_start(handle, texture.take())",
+		"        ownership_api_texture_adopt_material(handle, material?.take())",
+		"    /// - Note: Takes ownership of this Texture, which cannot be used afterwards.\n    public func consume(scene: Scene) {\n        ownership_api_texture_consume(take(), scene.handle)",
+		"public final class Scene {\n    let handle: OpaquePointer\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Swift file missing %q", want)
+		}
+	}
+}
+
+func TestSwiftGenerator_Tables(t *testing.T) {
+	ctx := loadTestAPI(t, "fields.yaml")
+	gen := &SwiftGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"public static func createStream(config: Data) throws -> Stream {",
+		"fields_api_stream_reconfigure(handle, configPtr.baseAddress!.assumingMemoryBound(to: UInt8.self), UInt32(config.count))",
+		"public func info() -> Fields_StreamInfo {\n        var result: UnsafeMutablePointer<UInt8>? = nil\n        var resultLen: UInt32 = 0\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Swift output missing %q", want)
+		}
+	}
+	if strings.Contains(content, "extension Fields_StreamConfig") {
+		t.Error("tables are built with flatc, not as C structs")
+	}
+}
+
+func TestSwiftGenerator_BitFlags(t *testing.T) {
+	ctx := loadTestAPI(t, "flags.yaml")
+	gen := &SwiftGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"public struct MediaCaps: OptionSet, Hashable {\n    public let rawValue: UInt32\n\n    public init(rawValue: UInt32) {\n        self.rawValue = rawValue\n    }\n\n",
+		"    public static let read = MediaCaps(rawValue: 1)\n",
+		"    public static let hasAudio = MediaCaps(rawValue: 16)\n",
+		"public struct MediaChannels: OptionSet, Hashable {\n    public let rawValue: UInt64\n",
+		"    public static let surround = MediaChannels(rawValue: 1099511627776)\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Swift output missing %q", want)
+		}
+	}
+}
+
+func TestSwiftGenerator_Unions(t *testing.T) {
+	ctx := loadTestAPI(t, "unions.yaml")
+	gen := &SwiftGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"public enum SceneShape {\n    case circle(Data)\n    case rect(Scene_Rect)\n    case caption(Data)\n",
+		"    public init?(_ c: Scene_Shape) {\n        switch c.type {\n        case Scene_Shape_Circle:\n            self = .circle(UnionsApiBuffers.takeData(UnsafeMutablePointer(mutating: c.table), c.table_len))\n        case Scene_Shape_Rect:\n            self = .rect(c.value.Rect)\n",
+		"        default:\n            return nil\n",
+		"    public func withCValue<R>(_ body: (Scene_Shape) throws -> R) rethrows -> R {\n        var c = Scene_Shape()\n",
+		"        case .caption(let value):\n            c.type = Scene_Shape_Caption\n            return try value.withUnsafeBytes { bytes in\n                c.table = bytes.baseAddress?.assumingMemoryBound(to: UInt8.self)\n                c.table_len = UInt32(bytes.count)\n                return try body(c)\n",
+		"        case .rect(let value):\n            c.type = Scene_Shape_Rect\n            c.value.Rect = value\n",
+		"    public func draw(shape: SceneShape) throws {\n        try shape.withCValue { shapeC in\n            let code = unions_api_canvas_draw(handle, shapeC)\n",
+		"    public func lastShape() -> SceneShape? {\n        return SceneShape(unions_api_canvas_last_shape(handle))\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Swift output missing %q", want)
+		}
+	}
+}
+
+func TestSwiftGenerator_FixedArrays(t *testing.T) {
+	ctx := loadTestAPI(t, "arrays.yaml")
+	gen := &SwiftGenerator{}
+
+	files, err := gen.Generate(ctx)
+	if err != nil {
+		t.Fatalf("generation failed: %v", err)
+	}
+	content := string(files[0].Content)
+
+	for _, want := range []string{
+		"extension Geo_Mat4 {\n    /// m as an array of 16 elements.\n    public var mArray: [Float] {\n",
+		"            withUnsafeBytes(of: m) { Array($0.bindMemory(to: Float.self)) }\n",
+		`            precondition(newValue.count == 16, "Geo_Mat4.m holds 16 elements, got \(newValue.count)")` + "\n",
+		"            withUnsafeMutableBytes(of: &m) { dst in\n",
+		"    public var maxArray: [Double] {\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Swift output missing %q", want)
+		}
+	}
+}
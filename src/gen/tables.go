@@ -0,0 +1,330 @@
+package gen
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/benn-herrera/xplatter/model"
+	"github.com/benn-herrera/xplatter/resolver"
+)
+
+// markTables returns a copy of api in which every parameter and result whose
+// type is a FlatBuffers table is marked as one. Tables cross the C ABI as
+// wire-format buffers rather than C structs, so every generator needs to
+// tell them apart from structs and unions. Struct parameters are marked too,
+// for bindings that build them field by field.
+func markTables(api *model.APIDefinition, resolved resolver.ResolvedTypes) *model.APIDefinition {
+	if api == nil {
+		return nil
+	}
+	out := *api
+	out.Interfaces = make([]model.InterfaceDef, len(api.Interfaces))
+	for i, iface := range api.Interfaces {
+		iface.Constructors = markMethodTables(iface.Constructors, resolved)
+		iface.Methods = markMethodTables(iface.Methods, resolved)
+		out.Interfaces[i] = iface
+	}
+	return &out
+}
+
+func markMethodTables(methods []model.MethodDef, resolved resolver.ResolvedTypes) []model.MethodDef {
+	if methods == nil {
+		return nil
+	}
+	out := make([]model.MethodDef, len(methods))
+	for i, method := range methods {
+		if len(method.Parameters) > 0 {
+			params := make([]model.ParameterDef, len(method.Parameters))
+			for j, p := range method.Parameters {
+				p.Table = isTableType(resolved, p.Type)
+				p.Struct = isStructType(resolved, p.Type)
+				params[j] = p
+			}
+			method.Parameters = params
+		}
+		if method.Returns != nil {
+			ret := *method.Returns
+			ret.Table = isTableType(resolved, ret.Type)
+			method.Returns = &ret
+		}
+		out[i] = method
+	}
+	return out
+}
+
+// isTableType reports whether t names a FlatBuffers table.
+func isTableType(resolved resolver.ResolvedTypes, t string) bool {
+	info, ok := resolved[t]
+	return ok && info.Kind == resolver.TypeKindTable
+}
+
+// isStructType reports whether t names a FlatBuffers struct.
+func isStructType(resolved resolver.ResolvedTypes, t string) bool {
+	info, ok := resolved[t]
+	return ok && info.Kind == resolver.TypeKindStruct
+}
+
+// returnsTable reports whether a method returns a FlatBuffers table.
+func returnsTable(method *model.MethodDef) bool {
+	return method.Returns != nil && method.Returns.Table
+}
+
+// hasTableParams reports whether any constructor or method takes a table.
+func hasTableParams(api *model.APIDefinition) bool {
+	for _, iface := range api.Interfaces {
+		for _, methods := range [][]model.MethodDef{iface.Constructors, iface.Methods} {
+			for _, method := range methods {
+				for _, p := range method.Parameters {
+					if p.Table {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// hasOptionalTableParams reports whether any method takes an optional table.
+func hasOptionalTableParams(api *model.APIDefinition) bool {
+	for _, iface := range api.Interfaces {
+		for _, methods := range [][]model.MethodDef{iface.Constructors, iface.Methods} {
+			for _, method := range methods {
+				for _, p := range method.Parameters {
+					if p.Table && p.Optional {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// hasTableReturns reports whether any method returns a table.
+func hasTableReturns(api *model.APIDefinition) bool {
+	for _, iface := range api.Interfaces {
+		for i := range iface.Methods {
+			if returnsTable(&iface.Methods[i]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// usedTables returns the tables passed to or returned by the API's
+// constructors and methods, in order of first use.
+func usedTables(api *model.APIDefinition) []string {
+	seen := map[string]bool{}
+	var names []string
+	add := func(t string, table bool) {
+		if table && !seen[t] {
+			seen[t] = true
+			names = append(names, t)
+		}
+	}
+	for _, iface := range api.Interfaces {
+		for _, methods := range [][]model.MethodDef{iface.Constructors, iface.Methods} {
+			for _, method := range methods {
+				for _, p := range method.Parameters {
+					add(p.Type, p.Table)
+				}
+				if method.Returns != nil {
+					add(method.Returns.Type, method.Returns.Table)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// unionHasTables reports whether any member of a union is a table. Table
+// members cross the C ABI in wire format in the union's table slot.
+func unionHasTables(info *resolver.TypeInfo, resolved resolver.ResolvedTypes) bool {
+	for _, m := range info.Members {
+		if isTableType(resolved, m.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// unionHasStructs reports whether any member of a union is a struct, held
+// by value in the union's value slot.
+func unionHasStructs(info *resolver.TypeInfo, resolved resolver.ResolvedTypes) bool {
+	for _, m := range info.Members {
+		if !isTableType(resolved, m.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// tableCheckMessage is the diagnostic a shim prints before aborting on a
+// table parameter that fails verification.
+func tableCheckMessage(funcName string, p *model.ParameterDef) string {
+	return fmt.Sprintf("%s: %s is not a valid %s FlatBuffer", funcName, p.Name, p.Type)
+}
+
+// invalidArgumentCode returns the value of a fallible method's
+// InvalidArgument error, which shims return for a table that fails
+// verification. Methods without one abort instead.
+func invalidArgumentCode(method *model.MethodDef, resolved resolver.ResolvedTypes) (int64, bool) {
+	if method.Error == "" {
+		return 0, false
+	}
+	info, ok := resolved[method.Error]
+	if !ok {
+		return 0, false
+	}
+	for _, v := range info.EnumValues {
+		if strings.ReplaceAll(strings.ToLower(v.Name), "_", "") == "invalidargument" {
+			return v.Value, true
+		}
+	}
+	return 0, false
+}
+
+// flatcSchemaBase returns the base name of the schema declaring a type,
+// which names the flatc output for it, e.g. "specs/fields.fbs" → "fields".
+func flatcSchemaBase(resolved resolver.ResolvedTypes, t string) string {
+	file := filepath.Base(resolved[t].Pos.File)
+	return strings.TrimSuffix(file, filepath.Ext(file))
+}
+
+// cppTableType returns the flatc C++ class of a table,
+// e.g. "Fields.StreamConfig" → "Fields::StreamConfig".
+func cppTableType(t string) string {
+	return strings.ReplaceAll(t, ".", "::")
+}
+
+// cppTableHeaders returns the flatc C++ headers declaring the tables the API
+// passes or returns, sorted.
+func cppTableHeaders(api *model.APIDefinition, resolved resolver.ResolvedTypes) []string {
+	seen := map[string]bool{}
+	var headers []string
+	for _, t := range usedTables(api) {
+		h := flatcSchemaBase(resolved, t) + "_generated.h"
+		if !seen[h] {
+			seen[h] = true
+			headers = append(headers, h)
+		}
+	}
+	sort.Strings(headers)
+	return headers
+}
+
+// tableTypes returns the names of the tables in the schemas, sorted.
+func tableTypes(resolved resolver.ResolvedTypes) []string {
+	var names []string
+	for name, info := range resolved {
+		if info.Kind == resolver.TypeKindTable {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// flatcSchemaBases returns the base names of every schema declaring a type,
+// sorted. flatc writes one output file per schema.
+func flatcSchemaBases(resolved resolver.ResolvedTypes) []string {
+	seen := map[string]bool{}
+	var bases []string
+	for name := range resolved {
+		base := flatcSchemaBase(resolved, name)
+		if !seen[base] {
+			seen[base] = true
+			bases = append(bases, base)
+		}
+	}
+	sort.Strings(bases)
+	return bases
+}
+
+// rustTablePath returns the path of the flatc Rust struct of a table, whose
+// namespaces become snake_case modules of the schema's module,
+// e.g. "Fields.StreamConfig" → "crate::fields_generated::fields::StreamConfig".
+func rustTablePath(resolved resolver.ResolvedTypes, t string) string {
+	parts := strings.Split(t, ".")
+	path := []string{"crate", flatcSchemaBase(resolved, t) + "_generated"}
+	for _, ns := range parts[:len(parts)-1] {
+		path = append(path, strings.ToLower(flagConstName(ns)))
+	}
+	return strings.Join(append(path, parts[len(parts)-1]), "::")
+}
+
+// swiftTableRoot returns the flatc Swift call reading the root of a table
+// from the Data expression data, e.g. "Fields.StreamInfo" →
+// "Fields_StreamInfo.getRootAsStreamInfo(bb: ByteBuffer(data: data))".
+func swiftTableRoot(t, data string) string {
+	name := t[strings.LastIndex(t, ".")+1:]
+	return fmt.Sprintf("%s.getRootAs%s(bb: ByteBuffer(data: %s))", model.FlatBufferCType(t), name, data)
+}
+
+// GoFlatcModule returns the import path of the flatc Go output within the Go
+// implementation's module, e.g. "fields_api" → "fields-api/generated/flatbuffers/go".
+// flatc needs it to import the packages of other namespaces.
+func GoFlatcModule(apiName string) string {
+	return goModulePath(apiName) + "/generated/flatbuffers/go"
+}
+
+// goTablePackage returns the name the flatc Go package of a table's
+// namespace is imported as, e.g. "Input.TouchEventBatch" → "inputfb". flatc
+// names the package after the namespace, which would collide with an
+// interface of the same name.
+func goTablePackage(t string) string {
+	parts := strings.Split(t, ".")
+	return strings.ToLower(strings.Join(parts[:len(parts)-1], "")) + "fb"
+}
+
+// goTableType returns the flatc Go type of a table parameter, e.g.
+// "Fields.StreamConfig" → "*fieldsfb.StreamConfig".
+func goTableType(t string) string {
+	parts := strings.Split(t, ".")
+	if len(parts) < 2 {
+		return "*" + t
+	}
+	return "*" + goTablePackage(t) + "." + parts[len(parts)-1]
+}
+
+// goTableGetRoot returns the flatc Go call reading the root of a table from
+// the byte slice buf, e.g. "fieldsfb.GetRootAsStreamConfig(buf, 0)".
+func goTableGetRoot(t, buf string) string {
+	typ := strings.TrimPrefix(goTableType(t), "*")
+	pkg, name := "", typ
+	if i := strings.LastIndex(typ, "."); i >= 0 {
+		pkg, name = typ[:i+1], typ[i+1:]
+	}
+	return fmt.Sprintf("%sGetRootAs%s(%s, 0)", pkg, name, buf)
+}
+
+// goTableImports returns the named imports of the flatc Go packages declaring
+// the tables the interface methods take, sorted, e.g.
+// "fieldsfb fields-api/generated/flatbuffers/go/Fields". Constructors only verify
+// their tables, and table results are returned as finished FlatBuffers, so
+// neither needs an import.
+func goTableImports(api *model.APIDefinition) []string {
+	seen := map[string]bool{}
+	var paths []string
+	for _, iface := range api.Interfaces {
+		for _, method := range iface.Methods {
+			for _, p := range method.Parameters {
+				parts := strings.Split(p.Type, ".")
+				if !p.Table || len(parts) < 2 {
+					continue
+				}
+				path := goTablePackage(p.Type) + " " + GoFlatcModule(api.API.Name) + "/" + strings.Join(parts[:len(parts)-1], "/")
+				if !seen[path] {
+					seen[path] = true
+					paths = append(paths, path)
+				}
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
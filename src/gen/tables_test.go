@@ -0,0 +1,72 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkTables(t *testing.T) {
+	ctx := loadTestAPI(t, "fields.yaml")
+	iface := ctx.API.Interfaces[0]
+	if !iface.Constructors[0].Parameters[0].Table {
+		t.Error("expected create_stream's config to be marked as a table")
+	}
+	if iface.Methods[0].Parameters[0].Table {
+		t.Error("a handle parameter is not a table")
+	}
+	if !returnsTable(&iface.Methods[3]) {
+		t.Error("expected info to return a table")
+	}
+	if !hasTableParams(ctx.API) || !hasTableReturns(ctx.API) || hasOptionalTableParams(ctx.API) {
+		t.Error("unexpected table parameter and result summary for fields.yaml")
+	}
+	if got := usedTables(ctx.API); strings.Join(got, " ") != "Fields.StreamConfig Fields.StreamInfo" {
+		t.Errorf("unexpected tables %v", got)
+	}
+
+	minimal := loadTestAPI(t, "minimal.yaml")
+	if hasTableParams(minimal.API) || hasTableReturns(minimal.API) {
+		t.Error("minimal.yaml passes no tables")
+	}
+}
+
+func TestTableCheckMessage(t *testing.T) {
+	ctx := loadTestAPI(t, "fields.yaml")
+	iface := ctx.API.Interfaces[0]
+	p := &iface.Methods[0].Parameters[1]
+	if got := tableCheckMessage("f", p); got != "f: config is not a valid Fields.StreamConfig FlatBuffer" {
+		t.Errorf("unexpected message %q", got)
+	}
+
+	if code, ok := invalidArgumentCode(&iface.Methods[0], ctx.ResolvedTypes); !ok || code != 1 {
+		t.Errorf("expected InvalidArgument code 1, got %d (%v)", code, ok)
+	}
+	if _, ok := invalidArgumentCode(&iface.Methods[1], ctx.ResolvedTypes); ok {
+		t.Error("restart has no error type and should abort instead")
+	}
+}
+
+func TestTableTypeNames(t *testing.T) {
+	ctx := loadTestAPI(t, "fields.yaml")
+	tests := []struct{ got, want string }{
+		{cppTableType("Fields.StreamConfig"), "Fields::StreamConfig"},
+		{rustTablePath(ctx.ResolvedTypes, "Fields.StreamConfig"), "crate::fields_generated::fields::StreamConfig"},
+		{swiftTableRoot("Fields.StreamInfo", "data"), "Fields_StreamInfo.getRootAsStreamInfo(bb: ByteBuffer(data: data))"},
+		{kotlinTableRoot("Fields.StreamInfo", "bytes"), "Fields.StreamInfo.getRootAsStreamInfo(java.nio.ByteBuffer.wrap(bytes))"},
+		{goTablePackage("Input.TouchEventBatch"), "inputfb"},
+		{goTableType("Fields.StreamConfig"), "*fieldsfb.StreamConfig"},
+		{goTableGetRoot("Fields.StreamConfig", "buf"), "fieldsfb.GetRootAsStreamConfig(buf, 0)"},
+		{GoFlatcModule("fields_api"), "fields-api/generated/flatbuffers/go"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, tt.got)
+		}
+	}
+	if got := cppTableHeaders(ctx.API, ctx.ResolvedTypes); strings.Join(got, " ") != "fields_generated.h" {
+		t.Errorf("unexpected headers %v", got)
+	}
+	if got := goTableImports(ctx.API); strings.Join(got, " ") != "fieldsfb fields-api/generated/flatbuffers/go/Fields" {
+		t.Errorf("unexpected imports %v", got)
+	}
+}
@@ -0,0 +1,599 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/benn-herrera/xplatter/model"
+)
+
+// inheritThreadAffinity returns a copy of api in which every constructor and
+// method carries the thread affinity that applies to it, resolved from its
+// interface or handle by model.MethodThreadAffinity, so generators only need
+// to look at the method.
+func inheritThreadAffinity(api *model.APIDefinition) *model.APIDefinition {
+	if api == nil {
+		return nil
+	}
+	out := *api
+	out.Interfaces = make([]model.InterfaceDef, len(api.Interfaces))
+	for i, iface := range api.Interfaces {
+		iface.Constructors = resolveThreadAffinity(api, &api.Interfaces[i], iface.Constructors)
+		iface.Methods = resolveThreadAffinity(api, &api.Interfaces[i], iface.Methods)
+		out.Interfaces[i] = iface
+	}
+	return &out
+}
+
+func resolveThreadAffinity(api *model.APIDefinition, iface *model.InterfaceDef, methods []model.MethodDef) []model.MethodDef {
+	if methods == nil {
+		return nil
+	}
+	out := make([]model.MethodDef, len(methods))
+	for i, method := range methods {
+		method.ThreadAffinity = api.MethodThreadAffinity(iface, &method)
+		out[i] = method
+	}
+	return out
+}
+
+// threadAffinityDestructor returns the synthetic destructor of handleName in
+// iface with its thread affinity resolved.
+func threadAffinityDestructor(api *model.APIDefinition, iface *model.InterfaceDef, handleName string) model.MethodDef {
+	destructor := SyntheticDestructor(handleName)
+	destructor.ThreadAffinity = api.MethodThreadAffinity(iface, &destructor)
+	return destructor
+}
+
+// hasThreadChecks reports whether any function must be called on the main
+// thread or on the thread that created its handle.
+func hasThreadChecks(api *model.APIDefinition) bool {
+	for _, iface := range api.Interfaces {
+		for _, methods := range [][]model.MethodDef{iface.Constructors, iface.Methods} {
+			for _, method := range methods {
+				if checksThread(&method) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// checksThread reports whether a function's thread affinity is enforced.
+func checksThread(method *model.MethodDef) bool {
+	return method.ThreadAffinity == model.ThreadAffinityMain || method.ThreadAffinity == model.ThreadAffinityCreator
+}
+
+// recordsCreator reports whether constructors of handleName must record the
+// creating thread: some function called on the handle has creator affinity.
+func recordsCreator(api *model.APIDefinition, handleName string) bool {
+	if h := api.HandleByName(handleName); h != nil && h.ThreadAffinity == model.ThreadAffinityCreator {
+		return true
+	}
+	for i := range api.Interfaces {
+		iface := &api.Interfaces[i]
+		for j := range iface.Methods {
+			method := &iface.Methods[j]
+			if method.ThreadAffinity != model.ThreadAffinityCreator {
+				continue
+			}
+			if p := method.FirstHandleParam(); p != nil && p.Type == "handle:"+handleName {
+				return true
+			}
+		}
+		if constructed, ok := iface.ConstructorHandleName(); ok && constructed == handleName {
+			if threadAffinityDestructor(api, iface, handleName).ThreadAffinity == model.ThreadAffinityCreator {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// threadAffinityNote is the doc text stating a function's thread affinity, or
+// "" if it declares none.
+func threadAffinityNote(method *model.MethodDef) string {
+	switch method.ThreadAffinity {
+	case model.ThreadAffinityMain:
+		return "Must be called on the main thread."
+	case model.ThreadAffinityCreator:
+		if p := method.FirstHandleParam(); p != nil {
+			handleName, _ := model.IsHandle(p.Type)
+			return "Must be called on the thread that created the " + handleName + "."
+		}
+		if method.Returns != nil {
+			if handleName, ok := model.IsHandle(method.Returns.Type); ok {
+				return "The returned " + handleName + " must only be used on the calling thread."
+			}
+		}
+	case model.ThreadAffinityAny:
+		return "May be called from any thread."
+	}
+	return ""
+}
+
+// ThreadingHeaderName returns the generated thread-affinity check header.
+func ThreadingHeaderName(apiName string) string {
+	return apiName + "_threading.h"
+}
+
+// cThreadCheck returns the C statement asserting a function's thread
+// affinity, using the macros of the threading header, or "" if it has none.
+func cThreadCheck(apiName string, method *model.MethodDef) string {
+	upper := UpperSnakeCase(apiName)
+	switch method.ThreadAffinity {
+	case model.ThreadAffinityMain:
+		return upper + "_ASSERT_MAIN_THREAD();"
+	case model.ThreadAffinityCreator:
+		if p := method.FirstHandleParam(); p != nil {
+			return fmt.Sprintf("%s_ASSERT_CREATOR_THREAD(%s);", upper, cIdent(p.Name))
+		}
+	}
+	return ""
+}
+
+// generateCThreadingHeader produces <api>_threading.h, the debug-build
+// thread-affinity checks shared by the C and C++ implementations. Exactly
+// one translation unit defines <API>_THREADING_IMPLEMENTATION before
+// including it.
+func generateCThreadingHeader(apiName string) *OutputFile {
+	var b strings.Builder
+	fmt.Fprintf(&b, `#ifndef %[1]s_THREADING_H
+#define %[1]s_THREADING_H
+
+/* Thread-affinity checks. A function called on the wrong thread prints a
+ * message and aborts. Constructors record the creating thread of handles with
+ * creator affinity; handles never recorded are not checked. The checks
+ * compile to nothing when NDEBUG is defined. */
+
+#ifdef NDEBUG
+#define %[1]s_ASSERT_MAIN_THREAD()          ((void)0)
+#define %[1]s_ASSERT_CREATOR_THREAD(handle) ((void)0)
+#define %[1]s_THREAD_RECORD(handle)         ((void)0)
+#define %[1]s_THREAD_FORGET(handle)         ((void)0)
+#else
+#ifdef __cplusplus
+extern "C" {
+#endif
+void %[2]s_thread_assert_main(const char* func);
+void %[2]s_thread_assert_creator(const void* handle, const char* func);
+void %[2]s_thread_record(const void* handle);
+void %[2]s_thread_forget(const void* handle);
+#ifdef __cplusplus
+}
+#endif
+#define %[1]s_ASSERT_MAIN_THREAD()          %[2]s_thread_assert_main(__func__)
+#define %[1]s_ASSERT_CREATOR_THREAD(handle) %[2]s_thread_assert_creator((const void*)(handle), __func__)
+#define %[1]s_THREAD_RECORD(handle)         %[2]s_thread_record((const void*)(handle))
+#define %[1]s_THREAD_FORGET(handle)         %[2]s_thread_forget((const void*)(handle))
+#endif
+
+#if defined(%[1]s_THREADING_IMPLEMENTATION) && !defined(NDEBUG)
+
+#include <stdio.h>
+#include <stdlib.h>
+
+#if defined(_WIN32)
+#ifndef NOMINMAX
+#define NOMINMAX
+#endif
+#include <windows.h>
+typedef DWORD %[2]s_thread_id;
+static SRWLOCK %[2]s_thread_lock = SRWLOCK_INIT;
+#define %[1]s_THREAD_CURRENT()    GetCurrentThreadId()
+#define %[1]s_THREAD_EQUAL(a, b)  ((a) == (b))
+#define %[1]s_THREAD_LOCK()       AcquireSRWLockExclusive(&%[2]s_thread_lock)
+#define %[1]s_THREAD_UNLOCK()     ReleaseSRWLockExclusive(&%[2]s_thread_lock)
+#else
+#include <pthread.h>
+#if defined(__EMSCRIPTEN__)
+#include <emscripten/threading.h>
+#elif defined(__linux__)
+#include <sys/syscall.h>
+#include <unistd.h>
+#if !defined(__cplusplus) && defined(__GLIBC__) && !defined(__USE_MISC)
+long syscall(long number, ...); /* hidden by strict ISO modes */
+#endif
+#endif
+typedef pthread_t %[2]s_thread_id;
+static pthread_mutex_t %[2]s_thread_lock = PTHREAD_MUTEX_INITIALIZER;
+#define %[1]s_THREAD_CURRENT()    pthread_self()
+#define %[1]s_THREAD_EQUAL(a, b)  pthread_equal((a), (b))
+#define %[1]s_THREAD_LOCK()       pthread_mutex_lock(&%[2]s_thread_lock)
+#define %[1]s_THREAD_UNLOCK()     pthread_mutex_unlock(&%[2]s_thread_lock)
+#endif
+
+/* Creating threads of recorded handles. Debug builds only, so a linear scan
+ * is fine. */
+static struct %[2]s_thread_owner {
+    const void* handle;
+    %[2]s_thread_id thread;
+}* %[2]s_thread_owners;
+static size_t %[2]s_thread_owner_count;
+static size_t %[2]s_thread_owner_capacity;
+
+/* Where the platform cannot say which thread is the main one (Windows), the
+ * first thread to call a main-thread function is taken to be it. */
+static int %[2]s_thread_is_main(void) {
+#if defined(__APPLE__)
+    return pthread_main_np() != 0;
+#elif defined(__EMSCRIPTEN__)
+    return emscripten_is_main_browser_thread();
+#elif defined(__linux__)
+    return getpid() == (pid_t)syscall(SYS_gettid);
+#else
+    static int have_main;
+    static %[2]s_thread_id main_thread;
+    int is_main;
+    %[1]s_THREAD_LOCK();
+    if (!have_main) {
+        main_thread = %[1]s_THREAD_CURRENT();
+        have_main = 1;
+    }
+    is_main = %[1]s_THREAD_EQUAL(main_thread, %[1]s_THREAD_CURRENT());
+    %[1]s_THREAD_UNLOCK();
+    return is_main;
+#endif
+}
+
+static struct %[2]s_thread_owner* %[2]s_thread_find(const void* handle) {
+    for (size_t i = 0; i < %[2]s_thread_owner_count; i++) {
+        if (%[2]s_thread_owners[i].handle == handle) {
+            return &%[2]s_thread_owners[i];
+        }
+    }
+    return NULL;
+}
+
+void %[2]s_thread_assert_main(const char* func) {
+    if (!%[2]s_thread_is_main()) {
+        fprintf(stderr, "%[2]s: %%s called off the main thread\n", func);
+        abort();
+    }
+}
+
+void %[2]s_thread_assert_creator(const void* handle, const char* func) {
+    int wrong = 0;
+    %[1]s_THREAD_LOCK();
+    struct %[2]s_thread_owner* owner = %[2]s_thread_find(handle);
+    if (owner) {
+        wrong = !%[1]s_THREAD_EQUAL(owner->thread, %[1]s_THREAD_CURRENT());
+    }
+    %[1]s_THREAD_UNLOCK();
+    if (wrong) {
+        fprintf(stderr, "%[2]s: %%s called off the thread that created its handle\n", func);
+        abort();
+    }
+}
+
+void %[2]s_thread_record(const void* handle) {
+    %[1]s_THREAD_LOCK();
+    struct %[2]s_thread_owner* owner = %[2]s_thread_find(handle);
+    if (!owner && %[2]s_thread_owner_count == %[2]s_thread_owner_capacity) {
+        size_t capacity = %[2]s_thread_owner_capacity ? %[2]s_thread_owner_capacity * 2 : 16;
+        struct %[2]s_thread_owner* grown = (struct %[2]s_thread_owner*)realloc(
+            %[2]s_thread_owners, capacity * sizeof(*grown));
+        if (grown) {
+            %[2]s_thread_owners = grown;
+            %[2]s_thread_owner_capacity = capacity;
+        }
+    }
+    if (!owner && %[2]s_thread_owner_count < %[2]s_thread_owner_capacity) {
+        owner = &%[2]s_thread_owners[%[2]s_thread_owner_count++];
+        owner->handle = handle;
+    }
+    if (owner) {
+        owner->thread = %[1]s_THREAD_CURRENT();
+    }
+    %[1]s_THREAD_UNLOCK();
+}
+
+void %[2]s_thread_forget(const void* handle) {
+    %[1]s_THREAD_LOCK();
+    struct %[2]s_thread_owner* owner = %[2]s_thread_find(handle);
+    if (owner) {
+        *owner = %[2]s_thread_owners[--%[2]s_thread_owner_count];
+    }
+    %[1]s_THREAD_UNLOCK();
+}
+
+#endif /* %[1]s_THREADING_IMPLEMENTATION */
+
+#endif /* %[1]s_THREADING_H */
+`, UpperSnakeCase(apiName), apiName)
+
+	return &OutputFile{Path: ThreadingHeaderName(apiName), Content: []byte(b.String())}
+}
+
+// writeCThreadCheck writes the thread-affinity assertion that opens a C or C++
+// function body, if the function has one.
+func writeCThreadCheck(b *strings.Builder, apiName string, method *model.MethodDef) {
+	if check := cThreadCheck(apiName, method); check != "" {
+		fmt.Fprintf(b, "    %s\n", check)
+	}
+}
+
+// writeCThreadingInclude includes the threading header in the translation unit
+// that owns its implementation.
+func writeCThreadingInclude(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, "#define %s_THREADING_IMPLEMENTATION\n", UpperSnakeCase(apiName))
+	fmt.Fprintf(b, "#include \"%s\"\n", ThreadingHeaderName(apiName))
+}
+
+// writeCThreadNote writes a comment line stating a function's thread affinity,
+// if it declares one.
+func writeCThreadNote(b *strings.Builder, indent string, method *model.MethodDef) {
+	if note := threadAffinityNote(method); note != "" {
+		fmt.Fprintf(b, "%s/* %s */\n", indent, note)
+	}
+}
+
+// goThreadCheck returns the Go statement asserting a cgo export's thread
+// affinity, using the helpers of the threading files, or "" if it has none.
+func goThreadCheck(apiName, ifaceName string, method *model.MethodDef) string {
+	funcName := CABIFunctionName(apiName, ifaceName, method.Name)
+	if method.Async {
+		funcName = AsyncStartFunctionName(apiName, ifaceName, method.Name)
+	}
+	switch method.ThreadAffinity {
+	case model.ThreadAffinityMain:
+		return fmt.Sprintf("_assertMainThread(%q)", funcName)
+	case model.ThreadAffinityCreator:
+		if p := method.FirstHandleParam(); p != nil {
+			return fmt.Sprintf("_assertCreatorThread(uintptr(unsafe.Pointer(%s)), %q)", goIdent(p.Name), funcName)
+		}
+	}
+	return ""
+}
+
+// writeGoThreadCheck writes the thread-affinity assertion that opens a cgo
+// export, if it has one.
+func writeGoThreadCheck(b *strings.Builder, apiName, ifaceName string, method *model.MethodDef) {
+	if check := goThreadCheck(apiName, ifaceName, method); check != "" {
+		fmt.Fprintf(b, "\t%s\n", check)
+	}
+}
+
+// generateGoThreading produces the thread-affinity checks of the cgo shim: a
+// debug file, and a no-op one selected by the release build tag.
+func generateGoThreading(apiName, genHeader string) []*OutputFile {
+	pkgName := goPackageName(apiName)
+
+	debug := fmt.Sprintf(`
+package %s
+
+/*
+#include <stdint.h>
+
+#if defined(_WIN32)
+#include <windows.h>
+static uint64_t _current_thread(void) { return (uint64_t)GetCurrentThreadId(); }
+#else
+#include <pthread.h>
+static uint64_t _current_thread(void) { return (uint64_t)(uintptr_t)pthread_self(); }
+#endif
+
+#if defined(__linux__)
+#include <sys/syscall.h>
+#include <unistd.h>
+#endif
+
+// 1 on the main thread, 0 off it, -1 where the platform cannot tell.
+static int _is_main_thread(void) {
+#if defined(__APPLE__)
+    return pthread_main_np() != 0;
+#elif defined(__linux__)
+    return getpid() == (pid_t)syscall(SYS_gettid);
+#else
+    return -1;
+#endif
+}
+*/
+import "C"
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Thread-affinity checks. A function called on the wrong thread panics.
+// Constructors record the creating thread of handles with creator affinity;
+// handles never recorded are not checked. Build with -tags release to compile
+// the checks out; the Makefile's packaging targets do.
+var (
+	_threadOwners sync.Map // handle key -> creating thread
+	_mainThread   atomic.Uint64
+)
+
+func _assertMainThread(fn string) {
+	switch C._is_main_thread() {
+	case 1:
+		return
+	case 0:
+		panic("%[2]s: " + fn + " called off the main thread")
+	}
+	// The platform cannot say which thread is the main one, so the first
+	// thread to call a main-thread function is taken to be it.
+	self := uint64(C._current_thread())
+	if !_mainThread.CompareAndSwap(0, self) && _mainThread.Load() != self {
+		panic("%[2]s: " + fn + " called off the main thread")
+	}
+}
+
+func _recordCreator(key uintptr) {
+	_threadOwners.Store(key, uint64(C._current_thread()))
+}
+
+func _forgetCreator(key uintptr) {
+	_threadOwners.Delete(key)
+}
+
+func _assertCreatorThread(key uintptr, fn string) {
+	if owner, ok := _threadOwners.Load(key); ok && owner.(uint64) != uint64(C._current_thread()) {
+		panic("%[2]s: " + fn + " called off the thread that created its handle")
+	}
+}
+`, pkgName, apiName)
+
+	release := fmt.Sprintf(`
+package %s
+
+// Release builds compile the thread-affinity checks out.
+
+func _assertMainThread(string) {}
+
+func _recordCreator(uintptr) {}
+
+func _forgetCreator(uintptr) {}
+
+func _assertCreatorThread(uintptr, string) {}
+`, pkgName)
+
+	return []*OutputFile{
+		{Path: apiName + "_threading.go", Content: []byte("//go:build !release\n\n" + genHeader + debug)},
+		{Path: apiName + "_threading_release.go", Content: []byte("//go:build release\n\n" + genHeader + release)},
+	}
+}
+
+// rustThreadCheck returns the Rust statement asserting an FFI function's
+// thread affinity, using the thread_check module of the FFI file, or "" if it
+// has none.
+func rustThreadCheck(apiName, ifaceName string, method *model.MethodDef) string {
+	funcName := CABIFunctionName(apiName, ifaceName, method.Name)
+	if method.Async {
+		funcName = AsyncStartFunctionName(apiName, ifaceName, method.Name)
+	}
+	switch method.ThreadAffinity {
+	case model.ThreadAffinityMain:
+		return fmt.Sprintf("thread_check::assert_main(%q);", funcName)
+	case model.ThreadAffinityCreator:
+		if p := method.FirstHandleParam(); p != nil {
+			return fmt.Sprintf("thread_check::assert_creator(%s as usize, %q);", rustIdent(p.Name), funcName)
+		}
+	}
+	return ""
+}
+
+// writeRustThreadCheck writes the thread-affinity assertion that opens an FFI
+// function, if it has one.
+func writeRustThreadCheck(b *strings.Builder, apiName, ifaceName string, method *model.MethodDef) {
+	if check := rustThreadCheck(apiName, ifaceName, method); check != "" {
+		fmt.Fprintf(b, "    %s\n", check)
+	}
+}
+
+// writeRustThreadCheckModule writes the thread_check module of the FFI file.
+func writeRustThreadCheckModule(b *strings.Builder, apiName string) {
+	fmt.Fprintf(b, `/// Thread-affinity checks. A function called on the wrong thread panics.
+/// Constructors record the creating thread of handles with creator affinity;
+/// handles never recorded are not checked. The checks only run in builds with
+/// debug assertions.
+mod thread_check {
+    use std::collections::BTreeMap;
+    use std::sync::Mutex;
+    use std::thread::{self, ThreadId};
+
+    static OWNERS: Mutex<BTreeMap<usize, ThreadId>> = Mutex::new(BTreeMap::new());
+
+    #[cfg(any(target_os = "macos", target_os = "ios"))]
+    fn is_main_thread() -> bool {
+        extern "C" {
+            fn pthread_main_np() -> i32;
+        }
+        unsafe { pthread_main_np() != 0 }
+    }
+
+    #[cfg(any(target_os = "linux", target_os = "android"))]
+    fn is_main_thread() -> bool {
+        extern "C" {
+            fn getpid() -> i32;
+            fn gettid() -> i32;
+        }
+        unsafe { getpid() == gettid() }
+    }
+
+    #[cfg(target_family = "wasm")]
+    fn is_main_thread() -> bool {
+        true
+    }
+
+    /// Where the platform cannot say which thread is the main one, the first
+    /// thread to call a main-thread function is taken to be it.
+    #[cfg(not(any(target_os = "macos", target_os = "ios", target_os = "linux", target_os = "android", target_family = "wasm")))]
+    fn is_main_thread() -> bool {
+        static MAIN: std::sync::OnceLock<ThreadId> = std::sync::OnceLock::new();
+        *MAIN.get_or_init(|| thread::current().id()) == thread::current().id()
+    }
+
+    pub fn assert_main(func: &str) {
+        if cfg!(debug_assertions) && !is_main_thread() {
+            panic!("%[1]s: {} called off the main thread", func);
+        }
+    }
+
+    pub fn assert_creator(handle: usize, func: &str) {
+        if !cfg!(debug_assertions) {
+            return;
+        }
+        let owner = OWNERS.lock().unwrap_or_else(|e| e.into_inner()).get(&handle).copied();
+        if owner.is_some_and(|id| id != thread::current().id()) {
+            panic!("%[1]s: {} called off the thread that created its handle", func);
+        }
+    }
+
+    pub fn record(handle: usize) {
+        if cfg!(debug_assertions) {
+            OWNERS.lock().unwrap_or_else(|e| e.into_inner()).insert(handle, thread::current().id());
+        }
+    }
+
+    pub fn forget(handle: usize) {
+        if cfg!(debug_assertions) {
+            OWNERS.lock().unwrap_or_else(|e| e.into_inner()).remove(&handle);
+        }
+    }
+}
+
+`, apiName)
+}
+
+// usesThreadAffinity reports whether any function, synthetic destructors
+// included, resolves to the given thread affinity.
+func usesThreadAffinity(api *model.APIDefinition, affinity string) bool {
+	for i := range api.Interfaces {
+		iface := &api.Interfaces[i]
+		for _, methods := range [][]model.MethodDef{iface.Constructors, iface.Methods} {
+			for _, method := range methods {
+				if method.ThreadAffinity == affinity {
+					return true
+				}
+			}
+		}
+		if handleName, ok := iface.ConstructorHandleName(); ok {
+			if threadAffinityDestructor(api, iface, handleName).ThreadAffinity == affinity {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// handleReleaseNote is the doc text for a handle wrapper whose release runs
+// the destructor implicitly (a Swift deinit), stating the thread the last
+// reference must be dropped on, or "" if the destructor has no affinity.
+func handleReleaseNote(api *model.APIDefinition, handleName string) string {
+	for i := range api.Interfaces {
+		iface := &api.Interfaces[i]
+		if constructed, ok := iface.ConstructorHandleName(); !ok || constructed != handleName {
+			continue
+		}
+		switch threadAffinityDestructor(api, iface, handleName).ThreadAffinity {
+		case model.ThreadAffinityMain:
+			return "Release the last reference on the main thread."
+		case model.ThreadAffinityCreator:
+			return "Release the last reference on the thread that created it."
+		}
+	}
+	return ""
+}
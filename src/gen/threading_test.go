@@ -0,0 +1,99 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/benn-herrera/xplatter/model"
+)
+
+func TestInheritThreadAffinity(t *testing.T) {
+	ctx := loadTestAPI(t, "threading.yaml")
+	api := ctx.API
+
+	tests := []struct {
+		iface, method string
+		want          string
+	}{
+		{"window", "create_window", model.ThreadAffinityMain}, // returned handle
+		{"window", "set_title", model.ThreadAffinityMain},     // handle parameter
+		{"window", "content_scale", model.ThreadAffinityAny},  // own
+		{"session", "create_session", model.ThreadAffinityCreator},
+		{"session", "fetch", model.ThreadAffinityCreator},
+		{"counter", "create_counter", model.ThreadAffinityAny}, // interface
+		{"counter", "increment", model.ThreadAffinityAny},
+		{"display", "show_count", model.ThreadAffinityMain}, // interface over handle
+	}
+	for _, tt := range tests {
+		iface := api.InterfaceByName(tt.iface)
+		var got string
+		for _, methods := range [][]model.MethodDef{iface.Constructors, iface.Methods} {
+			for _, m := range methods {
+				if m.Name == tt.method {
+					got = m.ThreadAffinity
+				}
+			}
+		}
+		if got != tt.want {
+			t.Errorf("%s.%s: thread affinity %q, want %q", tt.iface, tt.method, got, tt.want)
+		}
+	}
+}
+
+func TestRecordsCreator(t *testing.T) {
+	api := loadTestAPI(t, "threading.yaml").API
+	for handle, want := range map[string]bool{"Window": false, "Session": true, "Counter": false} {
+		if got := recordsCreator(api, handle); got != want {
+			t.Errorf("recordsCreator(%s) = %v, want %v", handle, got, want)
+		}
+	}
+}
+
+func TestHasThreadChecks(t *testing.T) {
+	if !hasThreadChecks(loadTestAPI(t, "threading.yaml").API) {
+		t.Error("threading.yaml has main and creator affinities")
+	}
+	if hasThreadChecks(loadTestAPI(t, "minimal.yaml").API) {
+		t.Error("minimal.yaml declares no thread affinity")
+	}
+}
+
+func TestCThreadCheck(t *testing.T) {
+	session := model.ParameterDef{Name: "session", Type: "handle:Session"}
+	tests := []struct {
+		method model.MethodDef
+		want   string
+	}{
+		{model.MethodDef{ThreadAffinity: model.ThreadAffinityMain}, "MY_API_ASSERT_MAIN_THREAD();"},
+		{model.MethodDef{ThreadAffinity: model.ThreadAffinityCreator, Parameters: []model.ParameterDef{session}}, "MY_API_ASSERT_CREATOR_THREAD(session);"},
+		{model.MethodDef{ThreadAffinity: model.ThreadAffinityCreator}, ""},
+		{model.MethodDef{ThreadAffinity: model.ThreadAffinityAny, Parameters: []model.ParameterDef{session}}, ""},
+		{model.MethodDef{}, ""},
+	}
+	for _, tt := range tests {
+		if got := cThreadCheck("my_api", &tt.method); got != tt.want {
+			t.Errorf("cThreadCheck(%+v) = %q, want %q", tt.method, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateCThreadingHeader(t *testing.T) {
+	f := generateCThreadingHeader("my_api")
+	if f.Path != "my_api_threading.h" {
+		t.Errorf("path = %q", f.Path)
+	}
+	content := string(f.Content)
+	for _, want := range []string{
+		"#ifndef MY_API_THREADING_H",
+		"#ifdef NDEBUG\n#define MY_API_ASSERT_MAIN_THREAD()          ((void)0)",
+		"#define MY_API_ASSERT_CREATOR_THREAD(handle) my_api_thread_assert_creator((const void*)(handle), __func__)",
+		"#if defined(MY_API_THREADING_IMPLEMENTATION) && !defined(NDEBUG)",
+		"return pthread_main_np() != 0;",
+		"void my_api_thread_record(const void* handle) {",
+		"abort();",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("threading header missing %q", want)
+		}
+	}
+}
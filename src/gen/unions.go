@@ -0,0 +1,451 @@
+package gen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/benn-herrera/xplatter/model"
+	"github.com/benn-herrera/xplatter/resolver"
+)
+
+// unionTypes returns the names of the FlatBuffers unions, sorted.
+func unionTypes(resolved resolver.ResolvedTypes) []string {
+	var names []string
+	for name, info := range resolved {
+		if info.Kind == resolver.TypeKindUnion {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isUnionType reports whether t names a FlatBuffers union.
+func isUnionType(resolved resolver.ResolvedTypes, t string) bool {
+	info, ok := resolved[t]
+	return ok && info.Kind == resolver.TypeKindUnion
+}
+
+// unionMemberFieldName returns the snake_case name of a union member, e.g.
+// "Geo_Circle" → "geo_circle", for bindings whose field names are snake case.
+func unionMemberFieldName(member string) string {
+	return strings.ToLower(flagConstName(member))
+}
+
+// swiftUnionName returns the Swift enum name of a union, e.g. "Scene.Shape" →
+// "SceneShape".
+func swiftUnionName(api *model.APIDefinition, t string) string {
+	return swiftTypeName(api, t)
+}
+
+// writeSwiftUnion writes an enum with a case per union member. Struct members
+// hold their imported C struct and table members their finished FlatBuffer.
+// A returned union's table is owned by the caller, so init takes it.
+func writeSwiftUnion(b *strings.Builder, api *model.APIDefinition, name string, info *resolver.TypeInfo, resolved resolver.ResolvedTypes) {
+	swiftName := swiftUnionName(api, name)
+	cName := model.FlatBufferCType(name)
+	fmt.Fprintf(b, "/// %s: one of its member types.\n", name)
+	fmt.Fprintf(b, "public enum %s {\n", swiftName)
+	for _, m := range info.Members {
+		memberType := model.FlatBufferCType(m.Type)
+		if isTableType(resolved, m.Type) {
+			memberType = "Data"
+		}
+		fmt.Fprintf(b, "    case %s(%s)\n", ToCamelCase(m.Name), memberType)
+	}
+
+	b.WriteString("\n    /// Converts the C tagged union, or returns nil when its type is NONE.\n")
+	fmt.Fprintf(b, "    public init?(_ c: %s) {\n", cName)
+	b.WriteString("        switch c.type {\n")
+	for _, m := range info.Members {
+		fmt.Fprintf(b, "        case %s_%s:\n", cName, m.Name)
+		if isTableType(resolved, m.Type) {
+			fmt.Fprintf(b, "            self = .%s(%sBuffers.takeData(UnsafeMutablePointer(mutating: c.table), c.table_len))\n", ToCamelCase(m.Name), ToPascalCase(api.API.Name))
+		} else {
+			fmt.Fprintf(b, "            self = .%s(c.value.%s)\n", ToCamelCase(m.Name), m.Name)
+		}
+	}
+	b.WriteString("        default:\n            return nil\n        }\n    }\n")
+
+	b.WriteString("\n    /// Calls body with the C tagged union holding this value, which is only\n")
+	b.WriteString("    /// valid during the call.\n")
+	fmt.Fprintf(b, "    public func withCValue<R>(_ body: (%s) throws -> R) rethrows -> R {\n", cName)
+	fmt.Fprintf(b, "        var c = %s()\n", cName)
+	b.WriteString("        switch self {\n")
+	for _, m := range info.Members {
+		fmt.Fprintf(b, "        case .%s(let value):\n", ToCamelCase(m.Name))
+		fmt.Fprintf(b, "            c.type = %s_%s\n", cName, m.Name)
+		if isTableType(resolved, m.Type) {
+			b.WriteString("            return try value.withUnsafeBytes { bytes in\n")
+			b.WriteString("                c.table = bytes.baseAddress?.assumingMemoryBound(to: UInt8.self)\n")
+			b.WriteString("                c.table_len = UInt32(bytes.count)\n")
+			b.WriteString("                return try body(c)\n")
+			b.WriteString("            }\n")
+		} else {
+			fmt.Fprintf(b, "            c.value.%s = value\n", m.Name)
+		}
+	}
+	b.WriteString("        }\n        return try body(c)\n    }\n}\n\n")
+}
+
+// writeKotlinUnion writes a sealed class with a subclass per union member
+// wrapping the member's data class, or a table member's finished FlatBuffer,
+// and None for an unset union.
+func writeKotlinUnion(b *strings.Builder, name string, info *resolver.TypeInfo, resolved resolver.ResolvedTypes) {
+	ktName := kotlinFBSDataClassName(name)
+	fmt.Fprintf(b, "/** %s: one of its member types, or None. */\n", name)
+	fmt.Fprintf(b, "sealed class %s {\n", ktName)
+	for _, m := range info.Members {
+		valueType := kotlinFBSDataClassName(m.Type)
+		if isTableType(resolved, m.Type) {
+			valueType = "ByteArray"
+		}
+		fmt.Fprintf(b, "    data class %s(val value: %s) : %s()\n", ToPascalCase(m.Name), valueType, ktName)
+	}
+	fmt.Fprintf(b, "    object None : %s()\n", ktName)
+	b.WriteString("}\n\n")
+}
+
+// writeJNIUnionReturn emits JNI code that wraps the live member of the C
+// tagged union out_result in its sealed class subclass. A table member's
+// FlatBuffer is copied into a byte array and released.
+func writeJNIUnionReturn(b *strings.Builder, apiName, retType string, info *resolver.TypeInfo, resolved resolver.ResolvedTypes, pkgs kotlinPackages) {
+	classPath := pkgs.classPath(retType, kotlinFBSDataClassName(retType))
+	cName := model.FlatBufferCType(retType)
+	b.WriteString("    switch (out_result.type) {\n")
+	for _, m := range info.Members {
+		memberInfo := resolved[m.Type]
+		memberPath := pkgs.classPath(m.Type, kotlinFBSDataClassName(m.Type))
+		fmt.Fprintf(b, "    case %s_%s: {\n", cName, m.Name)
+		if isTableType(resolved, m.Type) {
+			b.WriteString("        jbyteArray member = (*env)->NewByteArray(env, (jsize)out_result.table_len);\n")
+			b.WriteString("        if (member != NULL && out_result.table_len > 0) {\n")
+			b.WriteString("            (*env)->SetByteArrayRegion(env, member, 0, (jsize)out_result.table_len, (const jbyte*)out_result.table);\n")
+			b.WriteString("        }\n")
+			fmt.Fprintf(b, "        %s((void*)out_result.table);\n", BufferFreeFunctionName(apiName))
+			fmt.Fprintf(b, "        jclass cls = (*env)->FindClass(env, \"%s$%s\");\n", classPath, ToPascalCase(m.Name))
+			b.WriteString("        jmethodID ctor = (*env)->GetMethodID(env, cls, \"<init>\", \"([B)V\");\n")
+			b.WriteString("        return (*env)->NewObject(env, cls, ctor, member);\n")
+			b.WriteString("    }\n")
+			continue
+		}
+		sig, args := writeJNIDataClassArgs(b, "        ", memberInfo, "out_result.value."+m.Name)
+		fmt.Fprintf(b, "        jclass member_cls = (*env)->FindClass(env, \"%s\");\n", memberPath)
+		fmt.Fprintf(b, "        jmethodID member_ctor = (*env)->GetMethodID(env, member_cls, \"<init>\", \"%s\");\n", sig)
+		fmt.Fprintf(b, "        jobject member = (*env)->NewObject(env, member_cls, member_ctor%s);\n", jniArgList(args))
+		fmt.Fprintf(b, "        jclass cls = (*env)->FindClass(env, \"%s$%s\");\n", classPath, ToPascalCase(m.Name))
+		fmt.Fprintf(b, "        jmethodID ctor = (*env)->GetMethodID(env, cls, \"<init>\", \"(L%s;)V\");\n", memberPath)
+		b.WriteString("        return (*env)->NewObject(env, cls, ctor, member);\n")
+		b.WriteString("    }\n")
+	}
+	b.WriteString("    default: {\n")
+	fmt.Fprintf(b, "        jclass cls = (*env)->FindClass(env, \"%s$None\");\n", classPath)
+	fmt.Fprintf(b, "        jfieldID none = (*env)->GetStaticFieldID(env, cls, \"INSTANCE\", \"L%s$None;\");\n", classPath)
+	b.WriteString("        return (*env)->GetStaticObjectField(env, cls, none);\n")
+	b.WriteString("    }\n")
+	b.WriteString("    }\n")
+}
+
+// jniArgList returns args as trailing call arguments.
+func jniArgList(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return ", " + strings.Join(args, ", ")
+}
+
+// writeJSUnionTypedefs documents the discriminated object each union keep
+// selects decodes to.
+func writeJSUnionTypedefs(b *strings.Builder, resolved resolver.ResolvedTypes, keep func(string) bool) {
+	for _, name := range unionTypes(resolved) {
+		if !keep(name) {
+			continue
+		}
+		var variants []string
+		for _, m := range resolved[name].Members {
+			valueType := "Object"
+			if isTableType(resolved, m.Type) {
+				valueType = "Uint8Array"
+			}
+			variants = append(variants, fmt.Sprintf("{type: '%s', value: %s}", m.Name, valueType))
+		}
+		fmt.Fprintf(b, "/**\n * %s as a discriminated object; an unset union is undefined.\n", name)
+		fmt.Fprintf(b, " * @typedef {%s} %s\n */\n\n", strings.Join(variants, " | "), strings.ReplaceAll(name, ".", ""))
+	}
+}
+
+// writeJSUnionReturn emits JS code that decodes the C tagged union at
+// _outPtr into a discriminated { type, value } object, or undefined for NONE.
+// A table member's value is a copy of its FlatBuffer.
+func writeJSUnionReturn(b *strings.Builder, indent, retType string, resolved resolver.ResolvedTypes) {
+	info := resolved[retType]
+	_, valueOffset, tableOffset := wasmUnionLayout(info, resolved)
+	fmt.Fprintf(b, "%sconst _view = new DataView(_memoryBuffer());\n", indent)
+	fmt.Fprintf(b, "%sswitch (_view.getInt32(_outPtr, true)) {\n", indent)
+	for _, m := range info.Members {
+		fmt.Fprintf(b, "%s  case %d:\n", indent, m.Value)
+		if isTableType(resolved, m.Type) {
+			fmt.Fprintf(b, "%s    return { type: '%s', value: _takeBuffer(_view.getUint32(_outPtr + %d, true), _view.getUint32(_outPtr + %d, true), Uint8Array) };\n",
+				indent, m.Name, tableOffset, tableOffset+4)
+			continue
+		}
+		_, fields := wasmStructLayout(m.Type, resolved)
+		fmt.Fprintf(b, "%s    return { type: '%s', value: %s };\n", indent, m.Name, jsStructObjectExpr(fields, valueOffset))
+	}
+	fmt.Fprintf(b, "%s  default:\n%s    return undefined;\n%s}\n", indent, indent, indent)
+}
+
+// wasmUnionLayout computes the WASM32 layout of a C tagged union: the 4-byte
+// type tag, then the struct member union at the alignment of its widest
+// member, then the table slot's data pointer and size, if it has table
+// members.
+func wasmUnionLayout(info *resolver.TypeInfo, resolved resolver.ResolvedTypes) (totalSize, valueOffset, tableOffset int) {
+	maxSize, maxAlign := 0, 4
+	for _, m := range info.Members {
+		if isTableType(resolved, m.Type) {
+			continue
+		}
+		size, fields := wasmStructLayout(m.Type, resolved)
+		if size > maxSize {
+			maxSize = size
+		}
+		for _, f := range fields {
+			if _, align := wasmFieldSize(f.Type); align > maxAlign {
+				maxAlign = align
+			}
+		}
+	}
+	valueOffset = alignUp(4, maxAlign)
+	end := valueOffset + maxSize
+	if unionHasTables(info, resolved) {
+		tableOffset = alignUp(end, 4)
+		end = tableOffset + 8
+	}
+	return alignUp(end, maxAlign), valueOffset, tableOffset
+}
+
+// alignUp rounds n up to a multiple of align.
+func alignUp(n, align int) int {
+	if rem := n % align; rem != 0 {
+		return n + align - rem
+	}
+	return n
+}
+
+// writeRustUnion writes the C tagged form of a union, constructors for each
+// member, and a borrowing enum of its members. Struct members are
+// ManuallyDrop because Rust unions only hold Copy types directly; table
+// members are FlatBuffer bytes in the table slot.
+func writeRustUnion(b *strings.Builder, name string, info *resolver.TypeInfo, resolved resolver.ResolvedTypes) {
+	rustName := rustFlatBufferType(name)
+	membersName := rustName + "Members"
+	refName := rustName + "Ref"
+	hasStructs := unionHasStructs(info, resolved)
+	hasTables := unionHasTables(info, resolved)
+
+	if hasStructs {
+		fmt.Fprintf(b, "/// The struct members of the C tagged union %s.\n", rustName)
+		fmt.Fprintf(b, "#[repr(C)]\npub union %s {\n", membersName)
+		for _, m := range info.Members {
+			if !isTableType(resolved, m.Type) {
+				fmt.Fprintf(b, "    pub %s: std::mem::ManuallyDrop<%s>,\n", unionMemberFieldName(m.Name), rustFlatBufferType(m.Type))
+			}
+		}
+		b.WriteString("}\n\n")
+	}
+
+	fmt.Fprintf(b, "/// %s as the C ABI passes it: a type tag and the member it selects.\n", name)
+	fmt.Fprintf(b, "#[repr(C)]\npub struct %s {\n    pub tag: i32,\n", rustName)
+	if hasStructs {
+		fmt.Fprintf(b, "    pub value: %s,\n", membersName)
+	}
+	if hasTables {
+		b.WriteString("    pub table: *const u8,\n    pub table_len: u32,\n")
+	}
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(b, "/// A %s member, borrowed from a %s. Tables are FlatBuffer bytes.\n", name, rustName)
+	fmt.Fprintf(b, "#[derive(Debug)]\npub enum %s<'a> {\n", refName)
+	for _, m := range info.Members {
+		if isTableType(resolved, m.Type) {
+			fmt.Fprintf(b, "    %s(&'a [u8]),\n", ToPascalCase(m.Name))
+		} else {
+			fmt.Fprintf(b, "    %s(&'a %s),\n", ToPascalCase(m.Name), rustFlatBufferType(m.Type))
+		}
+	}
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(b, "impl %s {\n", rustName)
+	b.WriteString("    pub const NONE: i32 = 0;\n")
+	for _, m := range info.Members {
+		fmt.Fprintf(b, "    pub const %s: i32 = %d;\n", flagConstName(m.Name), m.Value)
+	}
+	b.WriteString(`
+    /// An unset union.
+    pub fn none() -> Self {
+        // SAFETY: an all-zero union is NONE, and its members are plain data.
+        unsafe { std::mem::zeroed() }
+    }
+`)
+	for _, m := range info.Members {
+		field := unionMemberFieldName(m.Name)
+		if isTableType(resolved, m.Type) {
+			fmt.Fprintf(b, `
+    /// A %[3]s table. The bytes must outlive the union; a returned union
+    /// passes them to the caller, so allocate them with into_c_buffer.
+    pub fn %[1]s(table: &[u8]) -> Self {
+        Self {
+            tag: Self::%[2]s,
+            table: table.as_ptr(),
+            table_len: table.len() as u32,
+            ..Self::none()
+        }
+    }
+`, field, flagConstName(m.Name), m.Type)
+			continue
+		}
+		fmt.Fprintf(b, `
+    pub fn %[1]s(value: %[2]s) -> Self {
+        Self {
+            tag: Self::%[3]s,
+            value: %[4]s { %[1]s: std::mem::ManuallyDrop::new(value) },
+`, field, rustFlatBufferType(m.Type), flagConstName(m.Name), membersName)
+		if hasTables {
+			b.WriteString("            ..Self::none()\n")
+		}
+		b.WriteString("        }\n    }\n")
+	}
+	fmt.Fprintf(b, `
+    /// The member this union holds, or None when it is unset.
+    pub fn get(&self) -> Option<%s<'_>> {
+        // SAFETY: the tag selects the member that was written.
+        unsafe {
+            match self.tag {
+`, refName)
+	for _, m := range info.Members {
+		if isTableType(resolved, m.Type) {
+			fmt.Fprintf(b, "                Self::%s => Some(%s::%s(std::slice::from_raw_parts(self.table, self.table_len as usize))),\n", flagConstName(m.Name), refName, ToPascalCase(m.Name))
+		} else {
+			fmt.Fprintf(b, "                Self::%s => Some(%s::%s(&self.value.%s)),\n", flagConstName(m.Name), refName, ToPascalCase(m.Name), unionMemberFieldName(m.Name))
+		}
+	}
+	fmt.Fprintf(b, `                _ => None,
+            }
+        }
+    }
+}
+
+impl std::fmt::Debug for %s {
+    fn fmt(&self, f: &mut std::fmt::Formatter<'_>) -> std::fmt::Result {
+        self.get().fmt(f)
+    }
+}
+
+`, rustName)
+}
+
+// goUnionMemberName returns the Go type wrapping a union member, e.g.
+// ("Scene.Shape", "Circle") → "SceneShapeCircle".
+func goUnionMemberName(unionName, member string) string {
+	return goReturnStructName(unionName) + ToPascalCase(member)
+}
+
+// writeGoUnion writes an interface for a union and a struct per member that
+// implements it. A nil interface value is an unset union. A table member's
+// Value is its FlatBuffer.
+func writeGoUnion(b *strings.Builder, name string, info *resolver.TypeInfo, resolved resolver.ResolvedTypes) {
+	goName := goReturnStructName(name)
+	var memberNames []string
+	for _, m := range info.Members {
+		memberNames = append(memberNames, goUnionMemberName(name, m.Name))
+	}
+	fmt.Fprintf(b, "// %s is the Go representation of the %s FlatBuffer union: one of\n", goName, name)
+	fmt.Fprintf(b, "// %s, or nil when unset.\n", strings.Join(memberNames, ", "))
+	fmt.Fprintf(b, "type %s interface {\n\tis%s()\n}\n\n", goName, goName)
+	for i, m := range info.Members {
+		valueType := goReturnStructName(m.Type)
+		if isTableType(resolved, m.Type) {
+			fmt.Fprintf(b, "// %s is the %s member of %s, a finished %s FlatBuffer.\n", memberNames[i], m.Name, goName, m.Type)
+			valueType = "[]byte"
+		} else {
+			fmt.Fprintf(b, "// %s is the %s member of %s.\n", memberNames[i], m.Name, goName)
+		}
+		fmt.Fprintf(b, "type %s struct {\n\tValue %s\n}\n\n", memberNames[i], valueType)
+		fmt.Fprintf(b, "func (%s) is%s() {}\n\n", memberNames[i], goName)
+	}
+}
+
+// writeCgoUnionMarshal writes the member held by the Go union result into the
+// C tagged union out_result. cgo exposes the C union as bytes, so a struct
+// member is written through a pointer of its C type. A table member is
+// copied into the table slot, which the caller releases.
+func writeCgoUnionMarshal(b *strings.Builder, retType string, info *resolver.TypeInfo, resolved resolver.ResolvedTypes) {
+	cName := model.FlatBufferCType(retType)
+	binding := ""
+	for _, m := range info.Members {
+		if isTableType(resolved, m.Type) || len(resolved[m.Type].ActiveFields()) > 0 {
+			binding = "v := "
+		}
+	}
+	fmt.Fprintf(b, "\tswitch %sresult.(type) {\n", binding)
+	for _, m := range info.Members {
+		fmt.Fprintf(b, "\tcase %s:\n", goUnionMemberName(retType, m.Name))
+		fmt.Fprintf(b, "\t\tout_result._type = C.%s_%s\n", cName, m.Name)
+		if isTableType(resolved, m.Type) {
+			b.WriteString("\t\tdata, n := _cBuffer(v.Value)\n")
+			b.WriteString("\t\tout_result.table, out_result.table_len = (*C.uint8_t)(data), n\n")
+			continue
+		}
+		memberInfo := resolved[m.Type]
+		if len(memberInfo.ActiveFields()) == 0 {
+			continue
+		}
+		fmt.Fprintf(b, "\t\tvalue := (*C.%s)(unsafe.Pointer(&out_result.value))\n", model.FlatBufferCType(m.Type))
+		writeCgoStructMarshal(b, "\t\t", memberInfo, "value", "v.Value")
+	}
+	fmt.Fprintf(b, "\tdefault:\n\t\tout_result._type = C.%s_NONE\n\t}\n", cName)
+}
+
+// writeCgoUnionUnmarshal writes the conversion of the C tagged union
+// parameter p into the Go union goVar. A NULL ref is an unset union, and a
+// table member is copied out of the table slot.
+func writeCgoUnionUnmarshal(b *strings.Builder, p *model.ParameterDef, info *resolver.TypeInfo, goVar string, resolved resolver.ResolvedTypes) {
+	cName := model.FlatBufferCType(p.Type)
+	name := goIdent(p.Name)
+	src := name
+	indent := "\t"
+	fmt.Fprintf(b, "\tvar %s %s\n", goVar, goReturnStructName(p.Type))
+	if p.Transfer == "ref" {
+		fmt.Fprintf(b, "\tif %s != nil {\n", name)
+		src = "(*" + name + ")"
+		indent = "\t\t"
+	}
+	fmt.Fprintf(b, "%sswitch %s._type {\n", indent, src)
+	for _, m := range info.Members {
+		fmt.Fprintf(b, "%scase C.%s_%s:\n", indent, cName, m.Name)
+		if isTableType(resolved, m.Type) {
+			fmt.Fprintf(b, "%[1]s\t%[2]s = %[3]s{Value: C.GoBytes(unsafe.Pointer(%[4]s.table), C.int(%[4]s.table_len))}\n", indent, goVar, goUnionMemberName(p.Type, m.Name), src)
+			continue
+		}
+		memberInfo := resolved[m.Type]
+		var fields []string
+		if len(memberInfo.ActiveFields()) > 0 {
+			fmt.Fprintf(b, "%s\tvalue := (*C.%s)(unsafe.Pointer(&%s.value))\n", indent, model.FlatBufferCType(m.Type), src)
+		}
+		for _, f := range memberInfo.ActiveFields() {
+			expr := fmt.Sprintf("%s(value.%s)", fbsFieldToGoType(f.Type), f.Name)
+			if f.Type == "string" {
+				expr = fmt.Sprintf("C.GoString(value.%s)", f.Name)
+			} else if _, _, ok := resolver.ArrayField(f.Type); ok {
+				expr = fmt.Sprintf("*(*%s)(unsafe.Pointer(&value.%s))", fbsFieldToGoType(f.Type), f.Name)
+			}
+			fields = append(fields, fmt.Sprintf("%s: %s", ToPascalCase(f.Name), expr))
+		}
+		fmt.Fprintf(b, "%s\t%s = %s{Value: %s{%s}}\n", indent, goVar, goUnionMemberName(p.Type, m.Name), goReturnStructName(m.Type), strings.Join(fields, ", "))
+	}
+	fmt.Fprintf(b, "%s}\n", indent)
+	if p.Transfer == "ref" {
+		b.WriteString("\t}\n")
+	}
+}
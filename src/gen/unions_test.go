@@ -0,0 +1,39 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnionTypes(t *testing.T) {
+	ctx := loadTestAPI(t, "unions.yaml")
+	if got := unionTypes(ctx.ResolvedTypes); strings.Join(got, " ") != "Scene.Shape" {
+		t.Errorf("unexpected unions %v", got)
+	}
+	if !isUnionType(ctx.ResolvedTypes, "Scene.Shape") || isUnionType(ctx.ResolvedTypes, "Scene.Circle") {
+		t.Error("isUnionType misclassifies Scene types")
+	}
+	if got := unionMemberFieldName("Geo_Circle"); got != "geo_circle" {
+		t.Errorf("unionMemberFieldName(Geo_Circle) = %q", got)
+	}
+}
+
+func TestWasmUnionLayout(t *testing.T) {
+	ctx := loadTestAPI(t, "unions.yaml")
+	size, valueOffset, tableOffset := wasmUnionLayout(ctx.ResolvedTypes["Scene.Shape"], ctx.ResolvedTypes)
+	// A 4-byte tag, then the 8-byte Rect struct, then the table slot.
+	if size != 20 || valueOffset != 4 || tableOffset != 12 {
+		t.Errorf("expected size 20, value offset 4 and table offset 12, got %d, %d and %d", size, valueOffset, tableOffset)
+	}
+	if got := wasmOutParamSize("Scene.Shape", ctx.ResolvedTypes); got != 20 {
+		t.Errorf("expected out param size 20, got %d", got)
+	}
+}
+
+func TestUnionTableSlots(t *testing.T) {
+	ctx := loadTestAPI(t, "unions.yaml")
+	info := ctx.ResolvedTypes["Scene.Shape"]
+	if !unionHasTables(info, ctx.ResolvedTypes) || !unionHasStructs(info, ctx.ResolvedTypes) {
+		t.Error("expected Scene.Shape to have both table and struct members")
+	}
+}
@@ -59,6 +59,9 @@ func CParamType(paramType string, transfer string) string {
 
 // CReturnType returns the C type string for a return value.
 func CReturnType(retType string) string {
+	if model.IsString(retType) {
+		return "char*"
+	}
 	if handleName, ok := model.IsHandle(retType); ok {
 		return HandleTypedefName(handleName)
 	}
@@ -109,6 +112,11 @@ func ExportMacroName(apiName string) string {
 	return UpperSnakeCase(apiName) + "_EXPORT"
 }
 
+// DeprecatedMacroName returns the macro that marks a declaration deprecated, e.g. "HELLO_XPLATTER_DEPRECATED".
+func DeprecatedMacroName(apiName string) string {
+	return UpperSnakeCase(apiName) + "_DEPRECATED"
+}
+
 // BuildMacroName returns the build macro name for an API, e.g. "HELLO_XPLATTER_BUILD".
 func BuildMacroName(apiName string) string {
 	return UpperSnakeCase(apiName) + "_BUILD"
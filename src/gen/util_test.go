@@ -104,6 +104,7 @@ func TestCReturnType(t *testing.T) {
 		{"bool", "bool"},
 		{"handle:Engine", "engine_handle"},
 		{"Common.EntityId", "Common_EntityId"},
+		{"string", "char*"},
 	}
 	for _, tt := range tests {
 		got := CReturnType(tt.retType)
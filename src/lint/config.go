@@ -0,0 +1,113 @@
+package lint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigFile is the config file lint looks for next to an API
+// definition when none is given.
+const DefaultConfigFile = "xplatter-lint.yaml"
+
+// defaultHotPathTableFields is the field count above which hot-path-table
+// calls a table large.
+const defaultHotPathTableFields = 6
+
+// Config selects the severity of each rule and tunes the rules that take
+// options.
+type Config struct {
+	// Rules maps rule IDs to severities; rules left out keep their default.
+	Rules map[string]Severity
+	// HotPaths are "interface.method" patterns, in path.Match syntax, naming
+	// the methods hot-path-table checks. There are none by default, so the
+	// rule is opt-in.
+	HotPaths []string
+	// HotPathTableFields is the most fields a table a hot-path method takes
+	// may have before hot-path-table reports it as large.
+	HotPathTableFields int
+}
+
+// configFile is the YAML form of a Config.
+type configFile struct {
+	Rules              map[string]string `yaml:"rules"`
+	HotPaths           []string          `yaml:"hot_paths"`
+	HotPathTableFields *int              `yaml:"hot_path_table_fields"`
+}
+
+// DefaultConfig returns the config used without a config file.
+func DefaultConfig() *Config {
+	return &Config{Rules: map[string]Severity{}, HotPathTableFields: defaultHotPathTableFields}
+}
+
+// LoadConfig reads a lint config file.
+func LoadConfig(filePath string) (*Config, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading lint config: %w", err)
+	}
+	cfg, err := ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+	return cfg, nil
+}
+
+// ParseConfig parses a lint config, rejecting unknown keys, rule IDs and
+// severities.
+func ParseConfig(data []byte) (*Config, error) {
+	var raw configFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&raw); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing lint config: %w", err)
+	}
+
+	cfg := DefaultConfig()
+	for id, s := range raw.Rules {
+		if RuleByID(id) == nil {
+			return nil, fmt.Errorf("unknown lint rule %q", id)
+		}
+		severity, err := ParseSeverity(s)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", id, err)
+		}
+		cfg.Rules[id] = severity
+	}
+	for _, pattern := range raw.HotPaths {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("hot_paths: bad pattern %q", pattern)
+		}
+	}
+	cfg.HotPaths = raw.HotPaths
+	if raw.HotPathTableFields != nil {
+		if *raw.HotPathTableFields < 1 {
+			return nil, fmt.Errorf("hot_path_table_fields: must be at least 1, got %d", *raw.HotPathTableFields)
+		}
+		cfg.HotPathTableFields = *raw.HotPathTableFields
+	}
+	return cfg, nil
+}
+
+// Severity returns the severity the config gives rule.
+func (c *Config) Severity(rule *Rule) Severity {
+	if s, ok := c.Rules[rule.ID]; ok {
+		return s
+	}
+	return rule.DefaultSeverity
+}
+
+// isHotPath reports whether a method matches one of the hot_paths patterns.
+func (c *Config) isHotPath(ifaceName, methodName string) bool {
+	for _, pattern := range c.HotPaths {
+		if ok, _ := path.Match(pattern, ifaceName+"."+methodName); ok {
+			return true
+		}
+	}
+	return false
+}
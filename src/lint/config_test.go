@@ -0,0 +1,79 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`
+rules:
+  missing-description: off
+  mixed-naming: error
+hot_paths:
+  - "renderer.draw_*"
+hot_path_table_fields: 12
+`))
+	if err != nil {
+		t.Fatalf("ParseConfig: %v", err)
+	}
+	if got := cfg.Severity(RuleByID("missing-description")); got != SeverityOff {
+		t.Errorf("missing-description = %s, want off", got)
+	}
+	if got := cfg.Severity(RuleByID("mixed-naming")); got != SeverityError {
+		t.Errorf("mixed-naming = %s, want error", got)
+	}
+	if got := cfg.Severity(RuleByID("handle-never-destroyed")); got != SeverityWarning {
+		t.Errorf("handle-never-destroyed = %s, want its default, warning", got)
+	}
+	if !cfg.isHotPath("renderer", "draw_mesh") || cfg.isHotPath("renderer", "resize") {
+		t.Error("hot_paths should match renderer.draw_mesh only")
+	}
+	if cfg.HotPathTableFields != 12 {
+		t.Errorf("hot_path_table_fields = %d, want 12", cfg.HotPathTableFields)
+	}
+}
+
+func TestParseConfig_Empty(t *testing.T) {
+	cfg, err := ParseConfig(nil)
+	if err != nil {
+		t.Fatalf("ParseConfig: %v", err)
+	}
+	if len(cfg.HotPaths) != 0 || len(cfg.Rules) != 0 || cfg.HotPathTableFields != defaultHotPathTableFields {
+		t.Errorf("expected the default config, got %+v", cfg)
+	}
+}
+
+func TestParseConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		errMsg string
+	}{
+		{"unknown rule", "rules:\n  no-such-rule: error\n", `unknown lint rule "no-such-rule"`},
+		{"unknown severity", "rules:\n  mixed-naming: fatal\n", `unknown severity "fatal"`},
+		{"unknown key", "rule:\n  mixed-naming: error\n", "field rule not found"},
+		{"bad pattern", "hot_paths: [\"[\"]\n", "bad pattern"},
+		{"bad field count", "hot_path_table_fields: 0\n", "must be at least 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConfig([]byte(tt.config))
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultConfigFile)
+	if err := os.WriteFile(path, []byte("rules:\n  mixed-naming: bogus\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(path); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("expected an error naming %s, got %v", path, err)
+	}
+}
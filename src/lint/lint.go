@@ -0,0 +1,348 @@
+// Package lint checks an API definition that passed validation for designs
+// that are legal but likely to cause trouble. Each rule has an ID and a
+// default severity a config file can override.
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/benn-herrera/xplatter/model"
+	"github.com/benn-herrera/xplatter/resolver"
+)
+
+// Severity is how seriously a finding is reported.
+type Severity int
+
+const (
+	SeverityOff Severity = iota // the rule does not run
+	SeverityNote
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityNote:
+		return "note"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return "off"
+	}
+}
+
+// ParseSeverity parses a severity as written in a config file.
+func ParseSeverity(s string) (Severity, error) {
+	switch s {
+	case "off":
+		return SeverityOff, nil
+	case "note":
+		return SeverityNote, nil
+	case "warning":
+		return SeverityWarning, nil
+	case "error":
+		return SeverityError, nil
+	}
+	return SeverityOff, fmt.Errorf("unknown severity %q (want error, warning, note or off)", s)
+}
+
+// Finding is a single lint result. Unlike a validation error it carries the
+// rule that produced it and a severity.
+type Finding struct {
+	RuleID   string
+	Severity Severity
+	File     string // source file path, empty if unknown
+	Line     int    // 1-based line number, 0 if unknown
+	Column   int    // 1-based column number, 0 if unknown
+	Path     string // e.g., "interfaces[0].methods[1]"
+	Message  string
+}
+
+func (f *Finding) String() string {
+	loc := f.Path
+	if f.File != "" && f.Line > 0 {
+		loc = fmt.Sprintf("%s:%d: %s", f.File, f.Line, f.Path)
+	} else if f.File != "" {
+		loc = fmt.Sprintf("%s: %s", f.File, f.Path)
+	}
+	return fmt.Sprintf("%s: %s: %s [%s]", loc, f.Severity, f.Message, f.RuleID)
+}
+
+// Rule is a named lint check.
+type Rule struct {
+	ID              string
+	Description     string
+	DefaultSeverity Severity
+	check           func(l *linter)
+}
+
+// Rules lists every lint rule, in the order they run.
+var Rules = []Rule{
+	{
+		ID:              "missing-description",
+		Description:     "Handles, interfaces, constructors, methods and events should have a description, which becomes the doc comment of every binding.",
+		DefaultSeverity: SeverityWarning,
+		check:           checkMissingDescription,
+	},
+	{
+		ID:              "hot-path-table",
+		Description:     "Methods named by hot_paths should take fixed-size data as FlatBuffer structs, which cross as a pointer, rather than tables, which are built and verified on every call, and should not take large tables.",
+		DefaultSeverity: SeverityWarning,
+		check:           checkHotPathTable,
+	},
+	{
+		ID:              "handle-never-destroyed",
+		Description:     "A handle that methods create needs a destroy function, which only an interface constructing it provides.",
+		DefaultSeverity: SeverityWarning,
+		check:           checkHandleNeverDestroyed,
+	},
+	{
+		ID:              "error-enum-missing-ok",
+		Description:     "Error enums should have an Ok value of 0, which the shims return on success.",
+		DefaultSeverity: SeverityWarning,
+		check:           checkErrorEnumMissingOk,
+	},
+	{
+		ID:              "mixed-naming",
+		Description:     "The accessors of an interface should either all use a get_ prefix or none should.",
+		DefaultSeverity: SeverityNote,
+		check:           checkMixedNaming,
+	},
+}
+
+// RuleByID looks up a rule by ID.
+func RuleByID(id string) *Rule {
+	for i := range Rules {
+		if Rules[i].ID == id {
+			return &Rules[i]
+		}
+	}
+	return nil
+}
+
+// linter holds the state of one Run.
+type linter struct {
+	def      *model.APIDefinition
+	resolved resolver.ResolvedTypes
+	file     string
+	srcMap   model.SourceMap
+	config   *Config
+	rule     *Rule
+	severity Severity
+	findings []Finding
+}
+
+// Run lints an API definition that passed validation. resolvedTypes may be
+// nil, which skips the rules that inspect FlatBuffer types. file and srcMap
+// locate findings as for validate.Validate; cfg may be nil for the defaults.
+func Run(def *model.APIDefinition, resolvedTypes resolver.ResolvedTypes, file string, srcMap model.SourceMap, cfg *Config) []Finding {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+	l := &linter{def: def, resolved: resolvedTypes, file: file, srcMap: srcMap, config: cfg}
+	for i := range Rules {
+		l.rule = &Rules[i]
+		l.severity = cfg.Severity(l.rule)
+		if l.severity != SeverityOff {
+			l.rule.check(l)
+		}
+	}
+	return l.findings
+}
+
+// report adds a finding of the running rule at the source of path.
+func (l *linter) report(path, message string) {
+	loc, ok := l.srcMap[path]
+	if !ok || loc.File == "" {
+		loc.File = l.file
+	}
+	l.findings = append(l.findings, Finding{
+		RuleID:   l.rule.ID,
+		Severity: l.severity,
+		File:     loc.File,
+		Line:     loc.Line,
+		Column:   loc.Column,
+		Path:     path,
+		Message:  message,
+	})
+}
+
+// reportAt adds a finding of the running rule at a position in a schema.
+func (l *linter) reportAt(pos resolver.Pos, path, message string) {
+	l.findings = append(l.findings, Finding{
+		RuleID:   l.rule.ID,
+		Severity: l.severity,
+		File:     pos.File,
+		Line:     pos.Line,
+		Path:     path,
+		Message:  message,
+	})
+}
+
+// forEachMethod calls fn for every constructor and method, with its path.
+func (l *linter) forEachMethod(fn func(iface *model.InterfaceDef, method *model.MethodDef, path string, ctor bool)) {
+	for i := range l.def.Interfaces {
+		iface := &l.def.Interfaces[i]
+		for j := range iface.Constructors {
+			fn(iface, &iface.Constructors[j], fmt.Sprintf("interfaces[%d].constructors[%d]", i, j), true)
+		}
+		for j := range iface.Methods {
+			fn(iface, &iface.Methods[j], fmt.Sprintf("interfaces[%d].methods[%d]", i, j), false)
+		}
+	}
+}
+
+func checkMissingDescription(l *linter) {
+	for i, h := range l.def.Handles {
+		if h.Description == "" {
+			l.report(fmt.Sprintf("handles[%d]", i), fmt.Sprintf("handle %q has no description", h.Name))
+		}
+	}
+	for i, iface := range l.def.Interfaces {
+		if iface.Description == "" {
+			l.report(fmt.Sprintf("interfaces[%d]", i), fmt.Sprintf("interface %q has no description", iface.Name))
+		}
+	}
+	l.forEachMethod(func(iface *model.InterfaceDef, method *model.MethodDef, path string, ctor bool) {
+		if method.Description != "" {
+			return
+		}
+		kind := "method"
+		if ctor {
+			kind = "constructor"
+		}
+		l.report(path, fmt.Sprintf("%s %s.%s has no description", kind, iface.Name, method.Name))
+	})
+	for i, ev := range l.def.Events {
+		if ev.Description == "" {
+			l.report(fmt.Sprintf("events[%d]", i), fmt.Sprintf("event %q has no description", ev.Name))
+		}
+	}
+}
+
+// checkHotPathTable runs only on the methods hot_paths names; without it
+// the rule finds nothing, since a definition does not say which methods are
+// called per frame or per buffer. On those methods it reports tables that
+// could be structs, and tables with more than hot_path_table_fields fields.
+func checkHotPathTable(l *linter) {
+	if l.resolved == nil {
+		return
+	}
+	l.forEachMethod(func(iface *model.InterfaceDef, method *model.MethodDef, path string, ctor bool) {
+		if !l.config.isHotPath(iface.Name, method.Name) {
+			return
+		}
+		for k, param := range method.Parameters {
+			info, ok := l.resolved[param.Type]
+			if !ok || info.Kind != resolver.TypeKindTable {
+				continue
+			}
+			paramPath := fmt.Sprintf("%s.parameters[%d]", path, k)
+			if l.fixedSize(info) {
+				l.report(paramPath,
+					fmt.Sprintf("hot-path method %s.%s takes table %s, which the binding builds and the implementation verifies on every call; its fields are all fixed-size, so declare it as a struct and pass it with transfer: ref", iface.Name, method.Name, param.Type))
+			} else if n := len(info.ActiveFields()); n > l.config.HotPathTableFields {
+				l.report(paramPath,
+					fmt.Sprintf("hot-path method %s.%s takes table %s, whose %d fields the binding builds and the implementation verifies on every call; pass what changes per call as parameters and set the rest outside the hot path", iface.Name, method.Name, param.Type, n))
+			}
+		}
+	})
+}
+
+// fixedSize reports whether every field of a table could be a struct field:
+// a scalar, enum, struct or fixed-length array of those.
+func (l *linter) fixedSize(info *resolver.TypeInfo) bool {
+	for _, f := range info.ActiveFields() {
+		t := f.Type
+		if elem, _, ok := resolver.ArrayField(t); ok {
+			t = elem
+		}
+		if resolver.IsScalarField(t) {
+			continue
+		}
+		if target, ok := l.resolved[t]; ok && (target.Kind == resolver.TypeKindEnum || target.Kind == resolver.TypeKindStruct) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func checkHandleNeverDestroyed(l *linter) {
+	destroyed := make(map[string]bool)
+	for i := range l.def.Interfaces {
+		if handleName, ok := l.def.Interfaces[i].ConstructorHandleName(); ok {
+			destroyed[handleName] = true
+		}
+	}
+	reported := make(map[string]bool)
+	l.forEachMethod(func(iface *model.InterfaceDef, method *model.MethodDef, path string, ctor bool) {
+		if method.Returns == nil || method.Returns.Borrowed {
+			return
+		}
+		handleName, ok := model.IsHandle(method.Returns.Type)
+		if !ok || destroyed[handleName] || reported[handleName] {
+			return
+		}
+		reported[handleName] = true
+		l.report(path+".returns", fmt.Sprintf("%s.%s creates handle %q, but no interface constructs it, so it has no destroy function", iface.Name, method.Name, handleName))
+	})
+}
+
+func checkErrorEnumMissingOk(l *linter) {
+	if l.resolved == nil {
+		return
+	}
+	reported := make(map[string]bool)
+	l.forEachMethod(func(iface *model.InterfaceDef, method *model.MethodDef, path string, ctor bool) {
+		info, ok := l.resolved[method.Error]
+		if !ok || info.Kind != resolver.TypeKindEnum || reported[method.Error] {
+			return
+		}
+		reported[method.Error] = true
+		for _, v := range info.EnumValues {
+			if v.Value == 0 {
+				if v.Name != "Ok" {
+					l.reportAt(info.Pos, path+".error", fmt.Sprintf("error enum %s names its zero value %q; name it Ok, the value returned on success", method.Error, v.Name))
+				}
+				return
+			}
+		}
+		l.reportAt(info.Pos, path+".error", fmt.Sprintf("error enum %s has no zero value; add Ok = 0, the value returned on success", method.Error))
+	})
+}
+
+func checkMixedNaming(l *linter) {
+	for i := range l.def.Interfaces {
+		iface := &l.def.Interfaces[i]
+		var prefixed, bare []string
+		for _, method := range iface.Methods {
+			if !isAccessor(&method) {
+				continue
+			}
+			if strings.HasPrefix(method.Name, "get_") {
+				prefixed = append(prefixed, method.Name)
+			} else {
+				bare = append(bare, method.Name)
+			}
+		}
+		if len(prefixed) > 0 && len(bare) > 0 {
+			l.report(fmt.Sprintf("interfaces[%d]", i), fmt.Sprintf("interface %q mixes accessors with a get_ prefix (%s) and without (%s)",
+				iface.Name, strings.Join(prefixed, ", "), strings.Join(bare, ", ")))
+		}
+	}
+}
+
+// isAccessor reports whether a method reads a value of its receiver: it
+// takes only the handle it is called on and returns something other than a
+// bool, whose accessors read better as is_ or has_ questions.
+func isAccessor(method *model.MethodDef) bool {
+	if method.Async || method.Returns == nil || method.Returns.Type == "bool" || len(method.Parameters) != 1 {
+		return false
+	}
+	_, ok := model.IsHandle(method.Parameters[0].Type)
+	return ok
+}
@@ -0,0 +1,190 @@
+package lint
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/benn-herrera/xplatter/loader"
+	"github.com/benn-herrera/xplatter/model"
+	"github.com/benn-herrera/xplatter/resolver"
+)
+
+func loadLintAPI(t *testing.T) (*model.APIDefinition, resolver.ResolvedTypes, model.SourceMap) {
+	t.Helper()
+	path := filepath.Join("..", "testdata", "lint.yaml")
+	def, srcMap, err := loader.LoadAPIDefinition(path)
+	if err != nil {
+		t.Fatalf("failed to load lint.yaml: %v", err)
+	}
+	types, err := resolver.ParseFBSFiles([]string{filepath.Join("..", "testdata")}, def.FlatBuffers)
+	if err != nil {
+		t.Fatalf("failed to parse FBS: %v", err)
+	}
+	return def, types, srcMap
+}
+
+// findingsOf returns the findings of one rule.
+func findingsOf(findings []Finding, ruleID string) []Finding {
+	var of []Finding
+	for _, f := range findings {
+		if f.RuleID == ruleID {
+			of = append(of, f)
+		}
+	}
+	return of
+}
+
+func TestRun_Rules(t *testing.T) {
+	def, types, srcMap := loadLintAPI(t)
+	cfg := DefaultConfig()
+	cfg.HotPaths = []string{"mixer.*"}
+	findings := Run(def, types, "lint.yaml", srcMap, cfg)
+
+	tests := []struct {
+		rule     string
+		path     string
+		severity Severity
+		contains string
+	}{
+		{"missing-description", "handles[1]", SeverityWarning, `handle "Stream" has no description`},
+		{"missing-description", "interfaces[0].methods[2]", SeverityWarning, "method mixer.volume has no description"},
+		// StreamConfig has strings and a vector, so only StreamInfo could be a
+		// struct, but its 8 fields make it large.
+		{"hot-path-table", "interfaces[0].methods[0].parameters[1]", SeverityWarning, "takes table Fields.StreamConfig, whose 8 fields the binding builds"},
+		{"hot-path-table", "interfaces[0].methods[4].parameters[1]", SeverityWarning, "takes table Fields.StreamInfo, which the binding builds and the implementation verifies on every call"},
+		{"handle-never-destroyed", "interfaces[0].methods[0].returns", SeverityWarning, `creates handle "Stream"`},
+		{"error-enum-missing-ok", "interfaces[0].methods[3].error", SeverityWarning, `names its zero value "Debug"`},
+		{"mixed-naming", "interfaces[0]", SeverityNote, "(get_gain) and without (volume)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule+"/"+tt.path, func(t *testing.T) {
+			for _, f := range findingsOf(findings, tt.rule) {
+				if f.Path != tt.path {
+					continue
+				}
+				if f.Severity != tt.severity {
+					t.Errorf("severity = %s, want %s", f.Severity, tt.severity)
+				}
+				if !strings.Contains(f.Message, tt.contains) {
+					t.Errorf("message %q does not contain %q", f.Message, tt.contains)
+				}
+				return
+			}
+			t.Errorf("no %s finding at %s in %v", tt.rule, tt.path, findings)
+		})
+	}
+	if n := len(findings); n != len(tests) {
+		t.Errorf("got %d findings, want %d: %v", n, len(tests), findings)
+	}
+}
+
+func TestRun_SourceLocations(t *testing.T) {
+	def, types, srcMap := loadLintAPI(t)
+	findings := Run(def, types, "lint.yaml", srcMap, nil)
+
+	stream := findingsOf(findings, "missing-description")[0]
+	if stream.File != filepath.Join("..", "testdata", "lint.yaml") || stream.Line != 14 {
+		t.Errorf("missing-description at %s:%d, want lint.yaml:14", stream.File, stream.Line)
+	}
+	// Error enum findings point at the enum in its schema.
+	enum := findingsOf(findings, "error-enum-missing-ok")[0]
+	if filepath.Base(enum.File) != "common.fbs" || enum.Line == 0 {
+		t.Errorf("error-enum-missing-ok at %s:%d, want common.fbs", enum.File, enum.Line)
+	}
+}
+
+func TestRun_Config(t *testing.T) {
+	def, types, srcMap := loadLintAPI(t)
+
+	// hot-path-table is opt-in: without hot paths no method is checked.
+	if got := findingsOf(Run(def, types, "", srcMap, nil), "hot-path-table"); len(got) != 0 {
+		t.Errorf("expected no hot-path-table findings without hot_paths, got %v", got)
+	}
+
+	cfg := DefaultConfig()
+	cfg.HotPaths = []string{"mixer.report_info"}
+	if got := findingsOf(Run(def, types, "", srcMap, cfg), "hot-path-table"); len(got) != 1 {
+		t.Errorf("expected one hot-path-table finding for mixer.report_info, got %v", got)
+	}
+
+	// StreamConfig is large only above the configured field count.
+	cfg = DefaultConfig()
+	cfg.HotPaths = []string{"mixer.open_stream"}
+	cfg.HotPathTableFields = 8
+	if got := findingsOf(Run(def, types, "", srcMap, cfg), "hot-path-table"); len(got) != 0 {
+		t.Errorf("expected no hot-path-table findings for an 8-field table with a limit of 8, got %v", got)
+	}
+	cfg.HotPathTableFields = 7
+	if got := findingsOf(Run(def, types, "", srcMap, cfg), "hot-path-table"); len(got) != 1 {
+		t.Errorf("expected one hot-path-table finding for an 8-field table with a limit of 7, got %v", got)
+	}
+
+	cfg = DefaultConfig()
+	cfg.Rules["missing-description"] = SeverityOff
+	cfg.Rules["handle-never-destroyed"] = SeverityError
+	findings := Run(def, types, "", srcMap, cfg)
+	if got := findingsOf(findings, "missing-description"); len(got) != 0 {
+		t.Errorf("expected a rule turned off not to run, got %v", got)
+	}
+	if got := findingsOf(findings, "handle-never-destroyed"); len(got) != 1 || got[0].Severity != SeverityError {
+		t.Errorf("expected one handle-never-destroyed error, got %v", got)
+	}
+}
+
+func TestRun_CleanDefinition(t *testing.T) {
+	def := &model.APIDefinition{
+		Handles: []model.HandleDef{{Name: "Engine", Description: "Engine"}},
+		Interfaces: []model.InterfaceDef{{
+			Name:        "engine",
+			Description: "Engine",
+			Constructors: []model.MethodDef{{
+				Name:        "create",
+				Description: "Create an engine",
+				Returns:     &model.ReturnDef{Type: "handle:Engine"},
+				Error:       "Common.ErrorCode",
+			}},
+			Methods: []model.MethodDef{{
+				Name:        "get_child",
+				Description: "Borrow the engine's root",
+				Parameters:  []model.ParameterDef{{Name: "engine", Type: "handle:Engine"}},
+				Returns:     &model.ReturnDef{Type: "handle:Engine", Borrowed: true},
+			}},
+		}},
+	}
+	types := resolver.ResolvedTypes{
+		"Common.ErrorCode": {Kind: resolver.TypeKindEnum, EnumValues: []resolver.EnumValue{{Name: "Ok", Value: 0}}},
+	}
+	if findings := Run(def, types, "", nil, nil); len(findings) != 0 {
+		t.Errorf("expected no findings, got %v", findings)
+	}
+}
+
+func TestRun_ErrorEnumWithoutZero(t *testing.T) {
+	def := &model.APIDefinition{
+		Interfaces: []model.InterfaceDef{{
+			Name:        "engine",
+			Description: "Engine",
+			Methods:     []model.MethodDef{{Name: "run", Description: "Run", Error: "App.Status"}},
+		}},
+	}
+	types := resolver.ResolvedTypes{
+		"App.Status": {Kind: resolver.TypeKindEnum, EnumValues: []resolver.EnumValue{{Name: "Failed", Value: 1}}},
+	}
+	findings := Run(def, types, "", nil, nil)
+	if len(findings) != 1 || !strings.Contains(findings[0].Message, "has no zero value") {
+		t.Errorf("expected an error-enum-missing-ok finding, got %v", findings)
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	for _, s := range []Severity{SeverityOff, SeverityNote, SeverityWarning, SeverityError} {
+		got, err := ParseSeverity(s.String())
+		if err != nil || got != s {
+			t.Errorf("ParseSeverity(%q) = %v, %v", s.String(), got, err)
+		}
+	}
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Error("expected an error for an unknown severity")
+	}
+}
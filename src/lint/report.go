@@ -0,0 +1,193 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+// Formats lists the output formats WriteReport accepts.
+var Formats = []string{"text", "json", "sarif"}
+
+// WriteReport writes findings to w in format. toolVersion is recorded in
+// SARIF output.
+func WriteReport(w io.Writer, format string, findings []Finding, cfg *Config, toolVersion string) error {
+	switch format {
+	case "text":
+		return WriteText(w, findings)
+	case "json":
+		return WriteJSON(w, findings)
+	case "sarif":
+		return WriteSARIF(w, findings, cfg, toolVersion)
+	}
+	return fmt.Errorf("unknown format %q (want text, json or sarif)", format)
+}
+
+// WriteText writes one line per finding.
+func WriteText(w io.Writer, findings []Finding) error {
+	for i := range findings {
+		if _, err := fmt.Fprintln(w, findings[i].String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonFinding is the JSON form of a Finding.
+type jsonFinding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Path     string `json:"path"`
+	Message  string `json:"message"`
+}
+
+// WriteJSON writes findings as a JSON array.
+func WriteJSON(w io.Writer, findings []Finding) error {
+	out := make([]jsonFinding, 0, len(findings))
+	for _, f := range findings {
+		out = append(out, jsonFinding{
+			Rule:     f.RuleID,
+			Severity: f.Severity.String(),
+			File:     f.File,
+			Line:     f.Line,
+			Column:   f.Column,
+			Path:     f.Path,
+			Message:  f.Message,
+		})
+	}
+	return writeIndentedJSON(w, out)
+}
+
+// SARIF 2.1.0 log, limited to the properties lint fills in.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level   string `json:"level"`
+	Enabled bool   `json:"enabled"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// sarifLevel maps a severity to a SARIF level.
+func sarifLevel(s Severity) string {
+	if s == SeverityOff {
+		return "none"
+	}
+	return s.String()
+}
+
+// WriteSARIF writes findings as a SARIF 2.1.0 log, which code review tools
+// display inline. Rule levels reflect cfg.
+func WriteSARIF(w io.Writer, findings []Finding, cfg *Config, toolVersion string) error {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+	driver := sarifDriver{
+		Name:           "xplatter",
+		Version:        toolVersion,
+		InformationURI: "https://github.com/benn-herrera/xplatter",
+	}
+	ruleIndex := make(map[string]int)
+	for i := range Rules {
+		severity := cfg.Severity(&Rules[i])
+		ruleIndex[Rules[i].ID] = i
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   Rules[i].ID,
+			ShortDescription:     sarifMessage{Text: Rules[i].Description},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(severity), Enabled: severity != SeverityOff},
+		})
+	}
+
+	results := make([]sarifResult, 0, len(findings))
+	for _, f := range findings {
+		loc := sarifLocation{LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: f.Path}}}
+		if f.File != "" {
+			loc.PhysicalLocation = &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(f.File)}}
+			if f.Line > 0 {
+				loc.PhysicalLocation.Region = &sarifRegion{StartLine: f.Line, StartColumn: f.Column}
+			}
+		}
+		results = append(results, sarifResult{
+			RuleID:    f.RuleID,
+			RuleIndex: ruleIndex[f.RuleID],
+			Level:     sarifLevel(f.Severity),
+			Message:   sarifMessage{Text: f.Message},
+			Locations: []sarifLocation{loc},
+		})
+	}
+
+	return writeIndentedJSON(w, sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
+
+func writeIndentedJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
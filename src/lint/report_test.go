@@ -0,0 +1,102 @@
+package lint
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var reportFindings = []Finding{
+	{RuleID: "missing-description", Severity: SeverityWarning, File: "api.yaml", Line: 7, Column: 5, Path: "handles[0]", Message: `handle "Engine" has no description`},
+	{RuleID: "mixed-naming", Severity: SeverityError, Path: "interfaces[1]", Message: "mixed"},
+}
+
+func TestWriteText(t *testing.T) {
+	var b strings.Builder
+	if err := WriteText(&b, reportFindings); err != nil {
+		t.Fatal(err)
+	}
+	want := "api.yaml:7: handles[0]: warning: handle \"Engine\" has no description [missing-description]\n" +
+		"interfaces[1]: error: mixed [mixed-naming]\n"
+	if b.String() != want {
+		t.Errorf("text report:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var b strings.Builder
+	if err := WriteJSON(&b, reportFindings); err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]interface{}
+	if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(got) != 2 || got[0]["rule"] != "missing-description" || got[0]["severity"] != "warning" || got[0]["line"] != 7.0 || got[0]["column"] != 5.0 {
+		t.Errorf("unexpected JSON report: %s", b.String())
+	}
+	if _, ok := got[1]["file"]; ok {
+		t.Error("expected an unknown file to be omitted")
+	}
+
+	b.Reset()
+	if err := WriteJSON(&b, nil); err != nil || strings.TrimSpace(b.String()) != "[]" {
+		t.Errorf("expected an empty array for no findings, got %q", b.String())
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Rules["mixed-naming"] = SeverityError
+	cfg.Rules["hot-path-table"] = SeverityOff
+	var b strings.Builder
+	if err := WriteSARIF(&b, reportFindings, cfg, "1.2.3"); err != nil {
+		t.Fatal(err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal([]byte(b.String()), &log); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected SARIF log: %s", b.String())
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "xplatter" || run.Tool.Driver.Version != "1.2.3" || len(run.Tool.Driver.Rules) != len(Rules) {
+		t.Errorf("unexpected driver: %+v", run.Tool.Driver)
+	}
+	for _, rule := range run.Tool.Driver.Rules {
+		switch rule.ID {
+		case "mixed-naming":
+			if rule.DefaultConfiguration.Level != "error" {
+				t.Errorf("mixed-naming level = %q, want error", rule.DefaultConfiguration.Level)
+			}
+		case "hot-path-table":
+			if rule.DefaultConfiguration.Enabled {
+				t.Error("expected hot-path-table to be disabled")
+			}
+		}
+	}
+
+	if len(run.Results) != 2 {
+		t.Fatalf("got %d results, want 2", len(run.Results))
+	}
+	first := run.Results[0]
+	if first.Level != "warning" || first.RuleIndex != 0 {
+		t.Errorf("unexpected result: %+v", first)
+	}
+	loc := first.Locations[0]
+	if loc.PhysicalLocation == nil || loc.PhysicalLocation.ArtifactLocation.URI != "api.yaml" || loc.PhysicalLocation.Region.StartLine != 7 || loc.PhysicalLocation.Region.StartColumn != 5 {
+		t.Errorf("unexpected location: %+v", loc)
+	}
+	if second := run.Results[1]; second.Locations[0].PhysicalLocation != nil || second.Locations[0].LogicalLocations[0].FullyQualifiedName != "interfaces[1]" {
+		t.Errorf("expected a logical location only, got %+v", second.Locations[0])
+	}
+}
+
+func TestWriteReport_UnknownFormat(t *testing.T) {
+	var b strings.Builder
+	if err := WriteReport(&b, "xml", nil, nil, ""); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
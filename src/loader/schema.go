@@ -18,7 +18,11 @@ var schemaJSON = `{
   "title": "xplatter API Definition",
   "description": "Schema for xplatter API definition YAML files.",
   "type": "object",
-  "required": ["api", "flatbuffers", "interfaces"],
+  "required": ["api", "flatbuffers"],
+  "anyOf": [
+    { "required": ["interfaces"] },
+    { "required": ["imports"] }
+  ],
   "additionalProperties": false,
   "properties": {
     "api": { "$ref": "#/$defs/api_metadata" },
@@ -27,6 +31,7 @@ var schemaJSON = `{
       "items": { "type": "string", "pattern": "\\.fbs$" },
       "minItems": 1
     },
+    "imports": { "$ref": "#/$defs/imports" },
     "handles": {
       "type": "array",
       "items": { "$ref": "#/$defs/handle_definition" }
@@ -35,9 +40,41 @@ var schemaJSON = `{
       "type": "array",
       "items": { "$ref": "#/$defs/interface_definition" },
       "minItems": 1
+    },
+    "events": {
+      "type": "array",
+      "items": { "$ref": "#/$defs/event_definition" },
+      "minItems": 1
+    },
+    "namespaces": {
+      "type": "array",
+      "items": { "$ref": "#/$defs/namespace_mapping" },
+      "minItems": 1
     }
   },
   "$defs": {
+    "imports": {
+      "type": "array",
+      "items": { "type": "string", "pattern": "\\.ya?ml$" },
+      "minItems": 1,
+      "uniqueItems": true
+    },
+    "api_fragment": {
+      "description": "An imported file contributing handles and interfaces.",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "imports": { "$ref": "#/$defs/imports" },
+        "handles": {
+          "type": "array",
+          "items": { "$ref": "#/$defs/handle_definition" }
+        },
+        "interfaces": {
+          "type": "array",
+          "items": { "$ref": "#/$defs/interface_definition" }
+        }
+      }
+    },
     "api_metadata": {
       "type": "object",
       "required": ["name", "version", "impl_lang"],
@@ -61,7 +98,11 @@ var schemaJSON = `{
       "additionalProperties": false,
       "properties": {
         "name": { "type": "string", "pattern": "^[A-Z][a-zA-Z0-9]*$" },
-        "description": { "type": "string" }
+        "description": { "type": "string" },
+        "thread_affinity": { "$ref": "#/$defs/thread_affinity" },
+        "since": { "$ref": "#/$defs/since" },
+        "deprecated": { "$ref": "#/$defs/deprecation" },
+        "stability": { "$ref": "#/$defs/stability" }
       }
     },
     "interface_definition": {
@@ -71,6 +112,11 @@ var schemaJSON = `{
       "properties": {
         "name": { "type": "string", "pattern": "^[a-z][a-z0-9_]*$" },
         "description": { "type": "string" },
+        "extends": { "type": "string", "pattern": "^[a-z][a-z0-9_]*$" },
+        "thread_affinity": { "$ref": "#/$defs/thread_affinity" },
+        "since": { "$ref": "#/$defs/since" },
+        "deprecated": { "$ref": "#/$defs/deprecation" },
+        "stability": { "$ref": "#/$defs/stability" },
         "constructors": {
           "type": "array",
           "items": { "$ref": "#/$defs/constructor_definition" },
@@ -83,6 +129,16 @@ var schemaJSON = `{
         }
       }
     },
+    "event_definition": {
+      "type": "object",
+      "required": ["name", "type"],
+      "additionalProperties": false,
+      "properties": {
+        "name": { "type": "string", "pattern": "^[a-z][a-z0-9_]*$" },
+        "type": { "type": "string", "pattern": "^[A-Z][a-zA-Z0-9]*(\\.[A-Z][a-zA-Z0-9]*)*$" },
+        "description": { "type": "string" }
+      }
+    },
     "constructor_definition": {
       "type": "object",
       "required": ["name", "returns", "error"],
@@ -95,7 +151,11 @@ var schemaJSON = `{
           "items": { "$ref": "#/$defs/parameter_definition" }
         },
         "returns": { "$ref": "#/$defs/return_definition" },
-        "error": { "type": "string", "pattern": "^[A-Z][a-zA-Z0-9]*(\\.[A-Z][a-zA-Z0-9]*)*$" }
+        "error": { "type": "string", "pattern": "^[A-Z][a-zA-Z0-9]*(\\.[A-Z][a-zA-Z0-9]*)*$" },
+        "thread_affinity": { "$ref": "#/$defs/thread_affinity" },
+        "since": { "$ref": "#/$defs/since" },
+        "deprecated": { "$ref": "#/$defs/deprecation" },
+        "stability": { "$ref": "#/$defs/stability" }
       }
     },
     "method_definition": {
@@ -110,7 +170,12 @@ var schemaJSON = `{
           "items": { "$ref": "#/$defs/parameter_definition" }
         },
         "returns": { "$ref": "#/$defs/return_definition" },
-        "error": { "type": "string", "pattern": "^[A-Z][a-zA-Z0-9]*(\\.[A-Z][a-zA-Z0-9]*)*$" }
+        "error": { "type": "string", "pattern": "^[A-Z][a-zA-Z0-9]*(\\.[A-Z][a-zA-Z0-9]*)*$" },
+        "async": { "type": "boolean" },
+        "thread_affinity": { "$ref": "#/$defs/thread_affinity" },
+        "since": { "$ref": "#/$defs/since" },
+        "deprecated": { "$ref": "#/$defs/deprecation" },
+        "stability": { "$ref": "#/$defs/stability" }
       }
     },
     "parameter_definition": {
@@ -123,10 +188,36 @@ var schemaJSON = `{
           "type": "string",
           "pattern": "^(int8|int16|int32|int64|uint8|uint16|uint32|uint64|float32|float64|bool|string|buffer<(int8|int16|int32|int64|uint8|uint16|uint32|uint64|float32|float64)>|handle:[A-Z][a-zA-Z0-9]*|[A-Z][a-zA-Z0-9]*(\\.[A-Z][a-zA-Z0-9]*)*)$"
         },
-        "transfer": { "type": "string", "enum": ["value", "ref", "ref_mut"] },
+        "transfer": { "type": "string", "enum": ["value", "ref", "ref_mut", "move"] },
+        "optional": { "type": "boolean" },
         "description": { "type": "string" }
       }
     },
+    "namespace_mapping": {
+      "type": "object",
+      "required": ["name"],
+      "additionalProperties": false,
+      "properties": {
+        "name": { "type": "string", "pattern": "^[A-Z][a-zA-Z0-9]*(\\.[A-Z][a-zA-Z0-9]*)*$" },
+        "kotlin": { "type": "string", "pattern": "^[a-z][a-z0-9_]*(\\.[a-z][a-z0-9_]*)*$" },
+        "swift": { "type": "string", "pattern": "^[A-Z][a-zA-Z0-9]*$" },
+        "js": { "type": "string", "pattern": "^[a-z][a-zA-Z0-9]*$" },
+        "rust": { "type": "string", "pattern": "^[a-z][a-z0-9_]*$" },
+        "go": { "type": "string", "pattern": "^[a-z][a-z0-9]*(/[a-z][a-z0-9]*)*$" }
+      }
+    },
+    "since": { "type": "string", "pattern": "^\\d+\\.\\d+\\.\\d+$" },
+    "deprecation": {
+      "type": "object",
+      "required": ["message"],
+      "additionalProperties": false,
+      "properties": {
+        "message": { "type": "string", "minLength": 1 },
+        "replacement": { "type": "string", "pattern": "^[a-zA-Z][a-zA-Z0-9_]*$" }
+      }
+    },
+    "stability": { "type": "string", "enum": ["stable", "experimental"] },
+    "thread_affinity": { "type": "string", "enum": ["main", "creator", "any"] },
     "return_definition": {
       "type": "object",
       "required": ["type"],
@@ -134,15 +225,17 @@ var schemaJSON = `{
       "properties": {
         "type": {
           "type": "string",
-          "pattern": "^(int8|int16|int32|int64|uint8|uint16|uint32|uint64|float32|float64|bool|handle:[A-Z][a-zA-Z0-9]*|[A-Z][a-zA-Z0-9]*(\\.[A-Z][a-zA-Z0-9]*)*)$"
+          "pattern": "^(int8|int16|int32|int64|uint8|uint16|uint32|uint64|float32|float64|bool|string|buffer<(int8|int16|int32|int64|uint8|uint16|uint32|uint64|float32|float64)>|handle:[A-Z][a-zA-Z0-9]*|[A-Z][a-zA-Z0-9]*(\\.[A-Z][a-zA-Z0-9]*)*)$"
         },
+        "optional": { "type": "boolean" },
+        "borrowed": { "type": "boolean" },
         "description": { "type": "string" }
       }
     }
   }
 }`
 
-var compiledSchema *jsonschema.Schema
+var compiledSchema, compiledFragmentSchema *jsonschema.Schema
 
 func init() {
 	// Decode the schema JSON into a generic value first
@@ -160,13 +253,30 @@ func init() {
 	if err != nil {
 		panic(fmt.Sprintf("failed to compile schema: %v", err))
 	}
+	compiledFragmentSchema, err = c.Compile("schema.json#/$defs/api_fragment")
+	if err != nil {
+		panic(fmt.Sprintf("failed to compile fragment schema: %v", err))
+	}
 }
 
 // SchemaJSON returns the embedded API definition JSON Schema string.
 func SchemaJSON() string { return schemaJSON }
 
 // ValidateSchema validates raw YAML bytes against the API definition JSON Schema.
+// A schema violation is returned as a *SchemaError.
 func ValidateSchema(yamlData []byte) error {
+	return validateYAML(compiledSchema, "", yamlData)
+}
+
+// ValidateFragmentSchema validates raw YAML bytes of an imported file, which
+// may only contain imports, handles and interfaces.
+func ValidateFragmentSchema(yamlData []byte) error {
+	return validateYAML(compiledFragmentSchema, "", yamlData)
+}
+
+// validateYAML validates YAML bytes read from file, which locates the
+// violations of a returned *SchemaError.
+func validateYAML(schema *jsonschema.Schema, file string, yamlData []byte) error {
 	// Parse YAML into a generic structure
 	var raw interface{}
 	if err := yaml.Unmarshal(yamlData, &raw); err != nil {
@@ -176,7 +286,10 @@ func ValidateSchema(yamlData []byte) error {
 	// Convert to JSON-compatible types (yaml.v3 uses map[string]interface{} already)
 	converted := convertYAMLToJSON(raw)
 
-	err := compiledSchema.Validate(converted)
+	err := schema.Validate(converted)
+	if verr, ok := err.(*jsonschema.ValidationError); ok {
+		return newSchemaError(file, yamlData, verr)
+	}
 	if err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
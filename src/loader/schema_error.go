@@ -0,0 +1,245 @@
+package loader
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/benn-herrera/xplatter/model"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+var schemaPrinter = message.NewPrinter(language.English)
+
+// SchemaViolation is one place a YAML file breaks the schema, located in the
+// YAML rather than in the JSON instance the schema validated.
+type SchemaViolation struct {
+	Path    string // e.g., "interfaces[0].methods[1].name", empty for the document
+	Line    int    // 1-based line number, 0 if unknown
+	Column  int    // 1-based column number, 0 if unknown
+	Rule    string // "schema-" and the failing keyword, e.g., "schema-pattern"
+	Message string
+	Fix     string // suggested fix, empty if there is none
+}
+
+// SchemaError is returned when a YAML file does not match the schema.
+type SchemaError struct {
+	File       string // YAML file path, empty if validating bytes
+	Violations []SchemaViolation
+}
+
+func (e *SchemaError) Error() string {
+	var msgs []string
+	for _, v := range e.Violations {
+		loc := v.Path
+		if loc == "" {
+			loc = "(document)"
+		}
+		if e.File != "" && v.Line > 0 {
+			loc = fmt.Sprintf("%s:%d: %s", e.File, v.Line, loc)
+		} else if e.File != "" {
+			loc = fmt.Sprintf("%s: %s", e.File, loc)
+		}
+		msg := loc + ": " + v.Message
+		if v.Fix != "" {
+			msg += "; " + v.Fix
+		}
+		msgs = append(msgs, msg)
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// newSchemaError maps a jsonschema error to the YAML it came from. Each leaf
+// cause becomes a violation, located through the file's source map.
+func newSchemaError(file string, yamlData []byte, verr *jsonschema.ValidationError) *SchemaError {
+	e := &SchemaError{File: file}
+	lines := buildSourceMap(yamlData)
+	collectViolations(e, lines, verr)
+	sort.SliceStable(e.Violations, func(i, j int) bool {
+		a, b := e.Violations[i], e.Violations[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return e
+}
+
+func collectViolations(e *SchemaError, lines model.SourceMap, verr *jsonschema.ValidationError) {
+	path := instancePath(verr.InstanceLocation)
+	rule := schemaRule(verr.ErrorKind)
+	add := func(path, message, fix string) {
+		loc := lines[path]
+		if path == "" {
+			loc = model.SourceLocation{Line: 1, Column: 1}
+		}
+		e.Violations = append(e.Violations, SchemaViolation{
+			Path:    path,
+			Line:    loc.Line,
+			Column:  loc.Column,
+			Rule:    rule,
+			Message: message,
+			Fix:     fix,
+		})
+	}
+
+	switch k := verr.ErrorKind.(type) {
+	case *kind.AnyOf:
+		// A definition needs interfaces or imports; report the alternatives
+		// together rather than as one missing property each.
+		if missing, ok := anyOfRequired(verr.Causes); ok {
+			rule = schemaRule(&kind.Required{})
+			add(path, fmt.Sprintf("missing one of the properties %s", quoteJoin(missing, "'", ", ")),
+				fmt.Sprintf("add %s", quoteJoin(missing, `"`, " or ")))
+			return
+		}
+	case *kind.Required:
+		for _, name := range k.Missing {
+			add(path, (&kind.Required{Missing: []string{name}}).LocalizedString(schemaPrinter),
+				fmt.Sprintf("add %q", name))
+		}
+		return
+	case *kind.AdditionalProperties:
+		for _, name := range k.Properties {
+			add(joinPath(path, name), (&kind.AdditionalProperties{Properties: []string{name}}).LocalizedString(schemaPrinter),
+				fmt.Sprintf("remove %q", name))
+		}
+		return
+	case *kind.Pattern:
+		add(path, k.LocalizedString(schemaPrinter), renameFix(k))
+		return
+	}
+
+	if len(verr.Causes) == 0 {
+		add(path, verr.ErrorKind.LocalizedString(schemaPrinter), "")
+		return
+	}
+	for _, cause := range verr.Causes {
+		collectViolations(e, lines, cause)
+	}
+}
+
+// anyOfRequired returns the properties of an anyOf whose every branch failed
+// only for a missing property.
+func anyOfRequired(causes []*jsonschema.ValidationError) ([]string, bool) {
+	var missing []string
+	for _, cause := range causes {
+		req, ok := cause.ErrorKind.(*kind.Required)
+		if !ok || len(cause.Causes) > 0 {
+			return nil, false
+		}
+		missing = append(missing, req.Missing...)
+	}
+	return missing, len(missing) > 0
+}
+
+// instancePath converts a JSON instance location to the source map's path
+// form: ["interfaces", "0", "name"] becomes "interfaces[0].name".
+func instancePath(tokens []string) string {
+	var path string
+	for _, tok := range tokens {
+		if _, err := strconv.Atoi(tok); err == nil {
+			path += "[" + tok + "]"
+		} else {
+			path = joinPath(path, tok)
+		}
+	}
+	return path
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// schemaRule names the rule of a violation after its schema keyword, e.g.,
+// "schema-additional-properties".
+func schemaRule(k jsonschema.ErrorKind) string {
+	keyword := "schema"
+	if kw := k.KeywordPath(); len(kw) > 0 {
+		keyword = kw[0]
+	}
+	var sb strings.Builder
+	sb.WriteString("schema-")
+	for i, r := range keyword {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				sb.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// renameFix suggests renaming a name that breaks a naming pattern to its
+// snake_case or PascalCase form, when that form matches.
+func renameFix(k *kind.Pattern) string {
+	re, err := regexp.Compile(k.Want)
+	if err != nil {
+		return ""
+	}
+	for _, name := range []string{toSnakeCase(k.Got), toPascalCase(k.Got)} {
+		if name != k.Got && re.MatchString(name) {
+			return fmt.Sprintf("rename it %q", name)
+		}
+	}
+	return ""
+}
+
+// toSnakeCase converts camelCase, PascalCase or kebab-case to snake_case.
+func toSnakeCase(s string) string {
+	var sb strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case r == '-' || r == ' ':
+			sb.WriteByte('_')
+		case unicode.IsUpper(r):
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				i+1 < len(runes) && unicode.IsLower(runes[i+1])) && runes[i-1] != '_' {
+				sb.WriteByte('_')
+			}
+			sb.WriteRune(unicode.ToLower(r))
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// toPascalCase converts snake_case, camelCase or kebab-case to PascalCase.
+func toPascalCase(s string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range s {
+		if r == '_' || r == '-' || r == ' ' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// quoteJoin joins names, each enclosed in quote, with sep.
+func quoteJoin(names []string, quote, sep string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quote + name + quote
+	}
+	return strings.Join(quoted, sep)
+}
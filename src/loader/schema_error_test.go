@@ -0,0 +1,124 @@
+package loader
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSchemaError_Violations(t *testing.T) {
+	yaml := `api:
+  name: BadName
+  version: 0.1.0
+  impl_lang: cpp
+flatbuffers: [common.fbs]
+handles:
+  - name: Doc
+    extra: 1
+interfaces:
+  - name: doc
+    methods:
+      - name: frob
+        parameters:
+          - name: x
+`
+	err := ValidateSchema([]byte(yaml))
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("expected a *SchemaError, got %v", err)
+	}
+	want := []SchemaViolation{
+		{Path: "api.name", Line: 2, Column: 3, Rule: "schema-pattern", Fix: `rename it "bad_name"`},
+		{Path: "handles[0].extra", Line: 8, Column: 5, Rule: "schema-additional-properties", Fix: `remove "extra"`},
+		{Path: "interfaces[0].methods[0].parameters[0]", Line: 14, Column: 13, Rule: "schema-required", Fix: `add "type"`},
+	}
+	if len(schemaErr.Violations) != len(want) {
+		t.Fatalf("got %d violations, want %d:\n%v", len(schemaErr.Violations), len(want), err)
+	}
+	for i, w := range want {
+		got := schemaErr.Violations[i]
+		got.Message = ""
+		if got != w {
+			t.Errorf("violation %d = %+v, want %+v", i, got, w)
+		}
+	}
+	if !strings.Contains(err.Error(), "handles[0].extra: additional properties 'extra' not allowed; remove \"extra\"") {
+		t.Errorf("unexpected error text:\n%v", err)
+	}
+}
+
+func TestSchemaError_InterfacesOrImports(t *testing.T) {
+	yaml := `api:
+  name: test_api
+  version: 0.1.0
+  impl_lang: cpp
+flatbuffers: [common.fbs]
+`
+	var schemaErr *SchemaError
+	if err := ValidateSchema([]byte(yaml)); !errors.As(err, &schemaErr) {
+		t.Fatalf("expected a *SchemaError, got %v", err)
+	}
+	if len(schemaErr.Violations) != 1 {
+		t.Fatalf("got %d violations, want 1: %v", len(schemaErr.Violations), schemaErr)
+	}
+	v := schemaErr.Violations[0]
+	if v.Path != "" || v.Line != 1 || v.Rule != "schema-required" || v.Fix != `add "interfaces" or "imports"` {
+		t.Errorf("unexpected violation: %+v", v)
+	}
+}
+
+func TestLoadAPIDefinition_SchemaErrorFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.yaml")
+	yaml := "api:\n  name: test_api\n  version: 1\n  impl_lang: cpp\nflatbuffers: [a.fbs]\ninterfaces:\n  - name: x\n"
+	if err := os.WriteFile(path, []byte(yaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err := LoadAPIDefinition(path)
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("expected a *SchemaError, got %v", err)
+	}
+	if schemaErr.File != path || len(schemaErr.Violations) != 1 || schemaErr.Violations[0].Path != "api.version" {
+		t.Errorf("unexpected schema error: %+v", schemaErr)
+	}
+	if want := path + ":3: api.version: "; !strings.Contains(err.Error(), want) {
+		t.Errorf("expected %q in error, got:\n%v", want, err)
+	}
+}
+
+func TestInstancePath(t *testing.T) {
+	tests := []struct {
+		tokens []string
+		want   string
+	}{
+		{nil, ""},
+		{[]string{"api", "name"}, "api.name"},
+		{[]string{"interfaces", "0", "methods", "12", "parameters"}, "interfaces[0].methods[12].parameters"},
+	}
+	for _, tt := range tests {
+		if got := instancePath(tt.tokens); got != tt.want {
+			t.Errorf("instancePath(%q) = %q, want %q", tt.tokens, got, tt.want)
+		}
+	}
+}
+
+func TestNameCaseConversions(t *testing.T) {
+	tests := []struct {
+		in, snake, pascal string
+	}{
+		{"BadName", "bad_name", "BadName"},
+		{"getHTTPStatus", "get_http_status", "GetHTTPStatus"},
+		{"frame-count", "frame_count", "FrameCount"},
+		{"texture_cache", "texture_cache", "TextureCache"},
+	}
+	for _, tt := range tests {
+		if got := toSnakeCase(tt.in); got != tt.snake {
+			t.Errorf("toSnakeCase(%q) = %q, want %q", tt.in, got, tt.snake)
+		}
+		if got := toPascalCase(tt.in); got != tt.pascal {
+			t.Errorf("toPascalCase(%q) = %q, want %q", tt.in, got, tt.pascal)
+		}
+	}
+}
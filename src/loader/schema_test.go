@@ -1,6 +1,7 @@
 package loader
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -93,7 +94,7 @@ interfaces:
 	}
 }
 
-func TestValidateSchema_ReturnTypeStringBlocked(t *testing.T) {
+func TestValidateSchema_ReturnTypeStringValid(t *testing.T) {
 	yaml := `
 api:
   name: test_api
@@ -107,9 +108,49 @@ interfaces:
       - name: get_name
         returns:
           type: string
+`
+	if err := ValidateSchema([]byte(yaml)); err != nil {
+		t.Errorf("expected valid string return, got error: %v", err)
+	}
+}
+
+func TestValidateSchema_ReturnTypeBufferValid(t *testing.T) {
+	yaml := `
+api:
+  name: test_api
+  version: "1.0.0"
+  impl_lang: c
+flatbuffers:
+  - types.fbs
+interfaces:
+  - name: test
+    methods:
+      - name: get_data
+        returns:
+          type: "buffer<uint8>"
+`
+	if err := ValidateSchema([]byte(yaml)); err != nil {
+		t.Errorf("expected valid buffer return, got error: %v", err)
+	}
+}
+
+func TestValidateSchema_ReturnTypeBufferOfBoolBlocked(t *testing.T) {
+	yaml := `
+api:
+  name: test_api
+  version: "1.0.0"
+  impl_lang: c
+flatbuffers:
+  - types.fbs
+interfaces:
+  - name: test
+    methods:
+      - name: get_flags
+        returns:
+          type: "buffer<bool>"
 `
 	if err := ValidateSchema([]byte(yaml)); err == nil {
-		t.Error("expected error for string as return type")
+		t.Error("expected error for buffer<bool> as return type")
 	}
 }
 
@@ -173,3 +214,331 @@ interfaces:
 		t.Error("expected error for snake_case handle name (must be PascalCase)")
 	}
 }
+
+func TestValidateSchema_OptionalValid(t *testing.T) {
+	yaml := `
+api:
+  name: test_api
+  version: "1.0.0"
+  impl_lang: c
+flatbuffers:
+  - types.fbs
+interfaces:
+  - name: test
+    methods:
+      - name: find
+        parameters:
+          - name: key
+            type: string
+            optional: true
+        returns:
+          type: uint32
+          optional: true
+`
+	if err := ValidateSchema([]byte(yaml)); err != nil {
+		t.Errorf("expected valid optional param and return, got error: %v", err)
+	}
+}
+
+func TestValidateSchema_OptionalNotBoolean(t *testing.T) {
+	yaml := `
+api:
+  name: test_api
+  version: "1.0.0"
+  impl_lang: c
+flatbuffers:
+  - types.fbs
+interfaces:
+  - name: test
+    methods:
+      - name: find
+        parameters:
+          - name: key
+            type: string
+            optional: "yes"
+`
+	if err := ValidateSchema([]byte(yaml)); err == nil {
+		t.Error("expected error for non-boolean optional")
+	}
+}
+
+func TestValidateSchema_ExtendsValid(t *testing.T) {
+	yaml := `
+api:
+  name: test_api
+  version: "1.0.0"
+  impl_lang: c
+flatbuffers:
+  - types.fbs
+interfaces:
+  - name: texture
+    extends: node
+    methods:
+      - name: get_width
+`
+	if err := ValidateSchema([]byte(yaml)); err != nil {
+		t.Errorf("expected valid extends, got error: %v", err)
+	}
+}
+
+func TestValidateSchema_ExtendsInvalidName(t *testing.T) {
+	yaml := `
+api:
+  name: test_api
+  version: "1.0.0"
+  impl_lang: c
+flatbuffers:
+  - types.fbs
+interfaces:
+  - name: texture
+    extends: Node
+    methods:
+      - name: get_width
+`
+	if err := ValidateSchema([]byte(yaml)); err == nil {
+		t.Error("expected error for non-snake_case extends")
+	}
+}
+
+func TestValidateSchema_LifecycleValid(t *testing.T) {
+	yaml := `
+api:
+  name: test_api
+  version: "1.2.0"
+  impl_lang: c
+flatbuffers:
+  - types.fbs
+handles:
+  - name: Sprite
+    since: "1.0.0"
+    deprecated:
+      message: "Use quads"
+      replacement: Quad
+interfaces:
+  - name: renderer
+    stability: experimental
+    methods:
+      - name: draw_sprite
+        since: "1.0.0"
+        deprecated:
+          message: "Use draw_quad"
+          replacement: draw_quad
+`
+	if err := ValidateSchema([]byte(yaml)); err != nil {
+		t.Errorf("expected valid lifecycle annotations, got error: %v", err)
+	}
+}
+
+func TestValidateSchema_LifecycleInvalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+	}{
+		{"since not semver", `since: "1.0"`},
+		{"deprecated without message", "deprecated:\n          replacement: draw_quad"},
+		{"deprecated as bool", "deprecated: true"},
+		{"unknown stability", "stability: beta"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			yaml := `
+api:
+  name: test_api
+  version: "1.2.0"
+  impl_lang: c
+flatbuffers:
+  - types.fbs
+interfaces:
+  - name: renderer
+    methods:
+      - name: draw_sprite
+        ` + tt.annotation + `
+`
+			if err := ValidateSchema([]byte(yaml)); err == nil {
+				t.Errorf("expected schema error for %s", tt.name)
+			}
+		})
+	}
+}
+
+func TestValidateSchema_ThreadAffinity(t *testing.T) {
+	yaml := `
+api:
+  name: test_api
+  version: "1.0.0"
+  impl_lang: c
+flatbuffers:
+  - types.fbs
+handles:
+  - name: Window
+    thread_affinity: main
+interfaces:
+  - name: window
+    thread_affinity: creator
+    constructors:
+      - name: create_window
+        thread_affinity: any
+        returns:
+          type: handle:Window
+        error: Common.ErrorCode
+    methods:
+      - name: set_title
+        thread_affinity: main
+`
+	if err := ValidateSchema([]byte(yaml)); err != nil {
+		t.Errorf("expected valid thread affinities, got error: %v", err)
+	}
+
+	invalid := strings.Replace(yaml, "thread_affinity: main\n", "thread_affinity: ui\n", 1)
+	if err := ValidateSchema([]byte(invalid)); err == nil {
+		t.Error("expected schema error for unknown thread affinity")
+	}
+}
+
+func TestValidateSchema_Ownership(t *testing.T) {
+	yaml := `
+api:
+  name: test_api
+  version: "1.0.0"
+  impl_lang: c
+flatbuffers:
+  - types.fbs
+interfaces:
+  - name: scene
+    methods:
+      - name: attach
+        parameters:
+          - name: texture
+            type: handle:Texture
+            transfer: move
+      - name: root_node
+        returns:
+          type: handle:Node
+          borrowed: true
+`
+	if err := ValidateSchema([]byte(yaml)); err != nil {
+		t.Errorf("expected move transfer and borrowed return to be valid, got error: %v", err)
+	}
+
+	invalid := strings.Replace(yaml, "borrowed: true", "borrowed: yes please", 1)
+	if err := ValidateSchema([]byte(invalid)); err == nil {
+		t.Error("expected schema error for non-boolean borrowed")
+	}
+}
+
+func TestValidateSchema_ImportsValid(t *testing.T) {
+	yaml := `
+api:
+  name: test_api
+  version: "1.0.0"
+  impl_lang: c
+flatbuffers:
+  - types.fbs
+imports:
+  - graphics.yaml
+  - audio/mixer.yml
+`
+	if err := ValidateSchema([]byte(yaml)); err != nil {
+		t.Errorf("expected imports without interfaces to be valid, got error: %v", err)
+	}
+}
+
+func TestValidateSchema_ImportsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		rest string
+	}{
+		{"no interfaces or imports", ""},
+		{"empty imports", "imports: []\n"},
+		{"not a yaml file", "imports:\n  - types.fbs\n"},
+		{"repeated import", "imports:\n  - a.yaml\n  - a.yaml\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			yaml := `
+api:
+  name: test_api
+  version: "1.0.0"
+  impl_lang: c
+flatbuffers:
+  - types.fbs
+` + tt.rest
+			if err := ValidateSchema([]byte(yaml)); err == nil {
+				t.Errorf("expected schema error for %s", tt.name)
+			}
+		})
+	}
+}
+
+func TestValidateSchema_Namespaces(t *testing.T) {
+	base := `
+api:
+  name: test_api
+  version: "1.0.0"
+  impl_lang: c
+flatbuffers:
+  - types.fbs
+interfaces:
+  - name: test
+    methods:
+      - name: do_thing
+`
+	valid := base + `namespaces:
+  - name: Acme.Scene
+    kotlin: com.acme.scene
+    swift: Scn
+    js: scene
+    rust: scene
+    go: acme/scene
+`
+	if err := ValidateSchema([]byte(valid)); err != nil {
+		t.Errorf("expected valid namespaces, got error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		mapping string
+	}{
+		{"missing name", "  - kotlin: com.acme.scene\n"},
+		{"lowercase namespace", "  - name: scene\n"},
+		{"uppercase kotlin package", "  - name: Scene\n    kotlin: com.Acme\n"},
+		{"lowercase swift prefix", "  - name: Scene\n    swift: scn\n"},
+		{"dotted js sub-module", "  - name: Scene\n    js: acme.scene\n"},
+		{"rust path", "  - name: Scene\n    rust: acme::scene\n"},
+		{"absolute go path", "  - name: Scene\n    go: /scene\n"},
+		{"unknown language", "  - name: Scene\n    python: scene\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateSchema([]byte(base + "namespaces:\n" + tt.mapping)); err == nil {
+				t.Errorf("expected schema error for %s", tt.name)
+			}
+		})
+	}
+}
+
+func TestValidateFragmentSchema(t *testing.T) {
+	valid := `
+imports:
+  - shared.yaml
+handles:
+  - name: Texture
+interfaces:
+  - name: graphics
+    methods:
+      - name: draw
+`
+	if err := ValidateFragmentSchema([]byte(valid)); err != nil {
+		t.Errorf("expected valid fragment, got error: %v", err)
+	}
+
+	for _, key := range []string{
+		"api:\n  name: test_api\n",
+		"flatbuffers:\n  - types.fbs\n",
+		"events:\n  - name: tick\n",
+	} {
+		if err := ValidateFragmentSchema([]byte(valid + key)); err == nil {
+			t.Errorf("expected fragment error for top-level key %q", key)
+		}
+	}
+}
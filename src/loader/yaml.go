@@ -3,6 +3,10 @@ package loader
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/benn-herrera/xplatter/model"
 	"gopkg.in/yaml.v3"
@@ -10,16 +14,20 @@ import (
 
 // LoadAPIDefinition reads and parses a YAML API definition file.
 // It validates the YAML against the JSON Schema before unmarshalling.
-// The returned SourceMap maps JSONPath-style paths to 1-based line numbers.
-func LoadAPIDefinition(path string) (*model.APIDefinition, map[string]int, error) {
+// Files named in imports are loaded the same way and their handles and
+// interfaces appended to the definition, depth first in declaration order.
+// The returned SourceMap gives the file, line and column of every node. A
+// schema violation is returned as a wrapped *SchemaError.
+func LoadAPIDefinition(path string) (*model.APIDefinition, model.SourceMap, error) {
+	path = filepath.Clean(path)
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, nil, fmt.Errorf("reading API definition: %w", err)
 	}
 
 	// First validate against JSON Schema
-	if err := ValidateSchema(data); err != nil {
-		return nil, nil, fmt.Errorf("schema validation: %w", err)
+	if err := validateYAML(compiledSchema, path, data); err != nil {
+		return nil, nil, fmt.Errorf("schema validation:\n%w", err)
 	}
 
 	var def model.APIDefinition
@@ -27,7 +35,127 @@ func LoadAPIDefinition(path string) (*model.APIDefinition, map[string]int, error
 		return nil, nil, fmt.Errorf("parsing API definition: %w", err)
 	}
 
-	return &def, buildSourceMap(data), nil
+	lines := buildSourceMap(data)
+	srcMap := make(model.SourceMap)
+	addSourceLocations(srcMap, path, lines, 0, 0)
+
+	im := &importer{
+		def:     &def,
+		srcMap:  srcMap,
+		rootDir: filepath.Dir(path),
+		loaded:  map[string]bool{path: true},
+	}
+	if err := im.importFiles(path, def.Imports, lines, []string{path}); err != nil {
+		return nil, nil, err
+	}
+	return &def, srcMap, nil
+}
+
+// importer merges imported fragments into a definition as they are loaded.
+type importer struct {
+	def     *model.APIDefinition
+	srcMap  model.SourceMap
+	rootDir string
+	loaded  map[string]bool // cleaned paths of every file loaded so far
+}
+
+// importFiles loads the files listed in from's imports. lines is from's own
+// source map, used to point errors at the import entry. stack holds the chain
+// of files being imported, for cycle detection. A file reached a second time
+// by another route is only loaded once.
+func (im *importer) importFiles(from string, imports []string, lines model.SourceMap, stack []string) error {
+	for i, name := range imports {
+		where := fmt.Sprintf("%s:%d", from, lines[fmt.Sprintf("imports[%d]", i)].Line)
+		path, err := im.resolve(from, name)
+		if err != nil {
+			return fmt.Errorf("%s: import %q: %w", where, name, err)
+		}
+		if slices.Contains(stack, path) {
+			return fmt.Errorf("%s: import %q: import cycle %s", where, name, strings.Join(append(stack, path), " -> "))
+		}
+		if im.loaded[path] {
+			continue
+		}
+		im.loaded[path] = true
+		if err := im.importFile(path, append(stack, path)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importFile loads one fragment, appends its handles and interfaces to the
+// definition, and then loads its own imports.
+func (im *importer) importFile(path string, stack []string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading import: %w", err)
+	}
+	if err := validateYAML(compiledFragmentSchema, path, data); err != nil {
+		return fmt.Errorf("schema validation of %s:\n%w", path, err)
+	}
+	var frag model.APIFragment
+	if err := yaml.Unmarshal(data, &frag); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	lines := buildSourceMap(data)
+	addSourceLocations(im.srcMap, path, lines, len(im.def.Handles), len(im.def.Interfaces))
+	im.def.Handles = append(im.def.Handles, frag.Handles...)
+	im.def.Interfaces = append(im.def.Interfaces, frag.Interfaces...)
+
+	return im.importFiles(path, frag.Imports, lines, stack)
+}
+
+// resolve finds an imported file by searching the importing file's directory
+// and then the root definition's directory. Absolute paths are used as-is.
+func (im *importer) resolve(from, name string) (string, error) {
+	if filepath.IsAbs(name) {
+		return filepath.Clean(name), nil
+	}
+	searchDirs := []string{filepath.Dir(from)}
+	if im.rootDir != searchDirs[0] {
+		searchDirs = append(searchDirs, im.rootDir)
+	}
+	for _, dir := range searchDirs {
+		candidate := filepath.Join(dir, name)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("not found in search directories: %v", searchDirs)
+}
+
+// addSourceLocations records a file's path→position entries in srcMap. Handles and
+// interfaces are renumbered from handleOffset and ifaceOffset, their positions
+// in the merged definition. Paths already present keep their location, so the
+// root file owns shared top-level keys such as "interfaces".
+func addSourceLocations(srcMap model.SourceMap, file string, lines model.SourceMap, handleOffset, ifaceOffset int) {
+	for path, loc := range lines {
+		path = renumberPath(path, "handles[", handleOffset)
+		path = renumberPath(path, "interfaces[", ifaceOffset)
+		if _, ok := srcMap[path]; !ok {
+			loc.File = file
+			srcMap[path] = loc
+		}
+	}
+}
+
+// renumberPath adds offset to the index of a path that starts with prefix.
+func renumberPath(path, prefix string, offset int) string {
+	if offset == 0 || !strings.HasPrefix(path, prefix) {
+		return path
+	}
+	rest := path[len(prefix):]
+	end := strings.IndexByte(rest, ']')
+	if end < 0 {
+		return path
+	}
+	index, err := strconv.Atoi(rest[:end])
+	if err != nil {
+		return path
+	}
+	return fmt.Sprintf("%s%d%s", prefix, index+offset, rest[end:])
 }
 
 // LoadAPIDefinitionNoValidate reads and parses without schema validation.
@@ -42,23 +170,24 @@ func LoadAPIDefinitionNoValidate(data []byte) (*model.APIDefinition, error) {
 
 // buildSourceMap parses YAML bytes into a Node tree and returns a map from
 // JSONPath-style paths (e.g. "interfaces[0].methods[1].returns.type") to
-// 1-based line numbers.
-func buildSourceMap(data []byte) map[string]int {
+// 1-based line and column numbers. The locations have no file.
+func buildSourceMap(data []byte) model.SourceMap {
 	var doc yaml.Node
 	if err := yaml.Unmarshal(data, &doc); err != nil {
 		return nil
 	}
-	sm := make(map[string]int)
+	sm := make(model.SourceMap)
 	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
 		walkNode(sm, "", doc.Content[0])
 	}
 	return sm
 }
 
-// walkNode recursively populates sm with path→line entries.
-func walkNode(sm map[string]int, path string, node *yaml.Node) {
+// walkNode recursively populates sm with path→position entries. A mapping
+// key is located at the key, not its value.
+func walkNode(sm model.SourceMap, path string, node *yaml.Node) {
 	if path != "" {
-		sm[path] = node.Line
+		sm[path] = model.SourceLocation{Line: node.Line, Column: node.Column}
 	}
 	switch node.Kind {
 	case yaml.MappingNode:
@@ -69,8 +198,8 @@ func walkNode(sm map[string]int, path string, node *yaml.Node) {
 			if path != "" {
 				childPath = path + "." + keyNode.Value
 			}
-			sm[childPath] = keyNode.Line
 			walkNode(sm, childPath, valNode)
+			sm[childPath] = model.SourceLocation{Line: keyNode.Line, Column: keyNode.Column}
 		}
 	case yaml.SequenceNode:
 		for i, child := range node.Content {
@@ -3,7 +3,10 @@ package loader
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/benn-herrera/xplatter/model"
 )
 
 func TestLoadAPIDefinition_Minimal(t *testing.T) {
@@ -79,3 +82,81 @@ func TestLoadAPIDefinition_InvalidYAML(t *testing.T) {
 		t.Error("expected error for invalid YAML")
 	}
 }
+
+func TestLoadAPIDefinition_Imports(t *testing.T) {
+	path := filepath.Join("..", "testdata", "imports.yaml")
+	def, srcMap, err := LoadAPIDefinition(path)
+	if err != nil {
+		t.Fatalf("unexpected error loading imports.yaml: %v", err)
+	}
+
+	// The root file's own definitions come first, then each import depth
+	// first; shared.yaml is imported twice but merged once.
+	var handles, ifaces []string
+	for _, h := range def.Handles {
+		handles = append(handles, h.Name)
+	}
+	for _, iface := range def.Interfaces {
+		ifaces = append(ifaces, iface.Name)
+	}
+	if got, want := strings.Join(handles, ","), "Engine,Texture"; got != want {
+		t.Errorf("handles = %s, want %s", got, want)
+	}
+	if got, want := strings.Join(ifaces, ","), "lifecycle,graphics,diagnostics,audio"; got != want {
+		t.Errorf("interfaces = %s, want %s", got, want)
+	}
+
+	importsDir := filepath.Join("..", "testdata", "imports")
+	locations := []struct {
+		path string
+		want model.SourceLocation
+	}{
+		{"handles[0].name", model.SourceLocation{File: path, Line: 15, Column: 5}},
+		{"handles[1].name", model.SourceLocation{File: filepath.Join(importsDir, "graphics.yaml"), Line: 6, Column: 5}},
+		{"interfaces[0].name", model.SourceLocation{File: path, Line: 19, Column: 5}},
+		{"interfaces[2].methods[0].name", model.SourceLocation{File: filepath.Join(importsDir, "shared.yaml"), Line: 4, Column: 9}},
+		{"interfaces[3].name", model.SourceLocation{File: filepath.Join(importsDir, "audio.yaml"), Line: 7, Column: 5}},
+		{"imports[1]", model.SourceLocation{File: path, Line: 12, Column: 5}},
+	}
+	for _, tt := range locations {
+		if got := srcMap[tt.path]; got != tt.want {
+			t.Errorf("srcMap[%q] = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLoadAPIDefinition_ImportErrors(t *testing.T) {
+	root := `api:
+  name: test_api
+  version: 0.1.0
+  impl_lang: c
+flatbuffers:
+  - types.fbs
+imports:
+  - frag.yaml
+`
+	tests := []struct {
+		name    string
+		frag    string
+		wantErr string
+	}{
+		{"missing file", "imports:\n  - gone.yaml\n", `frag.yaml:2: import "gone.yaml": not found`},
+		{"cycle", "imports:\n  - api.yaml\n", "import cycle"},
+		{"api key in fragment", "api:\n  name: other\n", "schema validation of"},
+		{"invalid handle", "handles:\n  - name: lower\n", "schema validation of"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			os.WriteFile(filepath.Join(dir, "api.yaml"), []byte(root), 0644)
+			os.WriteFile(filepath.Join(dir, "frag.yaml"), []byte(tt.frag), 0644)
+			_, _, err := LoadAPIDefinition(filepath.Join(dir, "api.yaml"))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -9,8 +10,19 @@ import (
 type APIDefinition struct {
 	API         APIMetadata    `yaml:"api"`
 	FlatBuffers []string       `yaml:"flatbuffers"`
+	Imports     []string       `yaml:"imports,omitempty"`
 	Handles     []HandleDef    `yaml:"handles,omitempty"`
 	Interfaces  []InterfaceDef `yaml:"interfaces"`
+	Events      []EventDef     `yaml:"events,omitempty"`
+	Namespaces  []NamespaceDef `yaml:"namespaces,omitempty"`
+}
+
+// APIFragment is an imported YAML file contributing handles and interfaces to
+// the API definition that imports it.
+type APIFragment struct {
+	Imports    []string       `yaml:"imports,omitempty"`
+	Handles    []HandleDef    `yaml:"handles,omitempty"`
+	Interfaces []InterfaceDef `yaml:"interfaces,omitempty"`
 }
 
 // APIMetadata holds API-level metadata.
@@ -24,25 +36,84 @@ type APIMetadata struct {
 
 // HandleDef defines an opaque handle type.
 type HandleDef struct {
-	Name        string `yaml:"name"`
-	Description string `yaml:"description,omitempty"`
+	Name           string `yaml:"name"`
+	Description    string `yaml:"description,omitempty"`
+	ThreadAffinity string `yaml:"thread_affinity,omitempty"`
+	Lifecycle      `yaml:",inline"`
 }
 
 // InterfaceDef groups related methods.
 type InterfaceDef struct {
-	Name         string      `yaml:"name"`
-	Description  string      `yaml:"description,omitempty"`
-	Constructors []MethodDef `yaml:"constructors,omitempty"`
-	Methods      []MethodDef `yaml:"methods,omitempty"`
+	Name           string      `yaml:"name"`
+	Description    string      `yaml:"description,omitempty"`
+	Extends        string      `yaml:"extends,omitempty"`
+	ThreadAffinity string      `yaml:"thread_affinity,omitempty"`
+	Constructors   []MethodDef `yaml:"constructors,omitempty"`
+	Methods        []MethodDef `yaml:"methods,omitempty"`
+	Lifecycle      `yaml:",inline"`
+}
+
+// Lifecycle holds the versioning annotations shared by handles, interfaces
+// and methods.
+type Lifecycle struct {
+	Since      string       `yaml:"since,omitempty"`
+	Deprecated *Deprecation `yaml:"deprecated,omitempty"`
+	Stability  string       `yaml:"stability,omitempty"`
+}
+
+// Deprecation marks a handle, interface or method as retired.
+type Deprecation struct {
+	Message     string `yaml:"message"`
+	Replacement string `yaml:"replacement,omitempty"`
+}
+
+// StabilityExperimental marks an API element that may change or be removed
+// without a deprecation period.
+const StabilityExperimental = "experimental"
+
+// IsExperimental reports whether the element is marked experimental.
+func (l *Lifecycle) IsExperimental() bool {
+	return l.Stability == StabilityExperimental
+}
+
+// Thread affinity values say which threads may call the functions of a
+// handle, interface or method. An empty value means ThreadAffinityAny.
+const (
+	ThreadAffinityMain    = "main"    // the platform's main (UI) thread only
+	ThreadAffinityCreator = "creator" // the thread that created the handle only
+	ThreadAffinityAny     = "any"     // any thread
+)
+
+// EventDef names a FlatBuffer table type that the implementation delivers to
+// the binding through the event ring buffer.
+type EventDef struct {
+	Name        string `yaml:"name"`
+	Type        string `yaml:"type"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// NamespaceDef maps a FlatBuffers namespace to where each target language
+// puts the types generated for it. An empty field leaves the language's
+// default: the binding's own package, file or module.
+type NamespaceDef struct {
+	Name   string `yaml:"name"`
+	Kotlin string `yaml:"kotlin,omitempty"` // Package, e.g. "com.acme.common"
+	Swift  string `yaml:"swift,omitempty"`  // Type name prefix replacing the namespace, e.g. "Acme"
+	JS     string `yaml:"js,omitempty"`     // Sub-module the binding re-exports, e.g. "common"
+	Rust   string `yaml:"rust,omitempty"`   // Module of the types file, e.g. "common"
+	Go     string `yaml:"go,omitempty"`     // Package path under the generated directory, e.g. "types/common"
 }
 
 // MethodDef defines a single API method.
 type MethodDef struct {
-	Name        string         `yaml:"name"`
-	Description string         `yaml:"description,omitempty"`
-	Parameters  []ParameterDef `yaml:"parameters,omitempty"`
-	Returns     *ReturnDef     `yaml:"returns,omitempty"`
-	Error       string         `yaml:"error,omitempty"`
+	Name           string         `yaml:"name"`
+	Description    string         `yaml:"description,omitempty"`
+	Parameters     []ParameterDef `yaml:"parameters,omitempty"`
+	Returns        *ReturnDef     `yaml:"returns,omitempty"`
+	Error          string         `yaml:"error,omitempty"`
+	Async          bool           `yaml:"async,omitempty"`
+	ThreadAffinity string         `yaml:"thread_affinity,omitempty"`
+	Lifecycle      `yaml:",inline"`
 }
 
 // ParameterDef defines a method parameter.
@@ -50,13 +121,25 @@ type ParameterDef struct {
 	Name        string `yaml:"name"`
 	Type        string `yaml:"type"`
 	Transfer    string `yaml:"transfer,omitempty"`
+	Optional    bool   `yaml:"optional,omitempty"`
 	Description string `yaml:"description,omitempty"`
+
+	// Table is set when Type names a FlatBuffers table, which crosses the C
+	// ABI in wire format. It is derived from the schemas, not read from YAML.
+	Table bool `yaml:"-"`
+	// Struct is set when Type names a FlatBuffers struct; see Table.
+	Struct bool `yaml:"-"`
 }
 
 // ReturnDef defines a method return value.
 type ReturnDef struct {
 	Type        string `yaml:"type"`
+	Optional    bool   `yaml:"optional,omitempty"`
+	Borrowed    bool   `yaml:"borrowed,omitempty"`
 	Description string `yaml:"description,omitempty"`
+
+	// Table is set when Type names a FlatBuffers table; see ParameterDef.Table.
+	Table bool `yaml:"-"`
 }
 
 var primitiveTypes = map[string]bool{
@@ -138,6 +221,103 @@ func (iface *InterfaceDef) ConstructorHandleName() (string, bool) {
 	return IsHandle(iface.Constructors[0].Returns.Type)
 }
 
+// ReceiverHandleName returns the handle this interface operates on: the handle
+// its constructors return and every method takes as its first parameter.
+// It returns ("", false) if the interface has no constructors or methods, or
+// they disagree.
+func (iface *InterfaceDef) ReceiverHandleName() (string, bool) {
+	receiver := ""
+	agree := func(name string, ok bool) bool {
+		if !ok || (receiver != "" && name != receiver) {
+			return false
+		}
+		receiver = name
+		return true
+	}
+	for _, ctor := range iface.Constructors {
+		if ctor.Returns == nil || !agree(IsHandle(ctor.Returns.Type)) {
+			return "", false
+		}
+	}
+	for _, method := range iface.Methods {
+		if len(method.Parameters) == 0 || !agree(IsHandle(method.Parameters[0].Type)) {
+			return "", false
+		}
+	}
+	return receiver, receiver != ""
+}
+
+// InterfaceByName looks up an interface definition by name.
+func (a *APIDefinition) InterfaceByName(name string) *InterfaceDef {
+	for i := range a.Interfaces {
+		if a.Interfaces[i].Name == name {
+			return &a.Interfaces[i]
+		}
+	}
+	return nil
+}
+
+// BaseInterface returns the interface that iface extends, or nil.
+func (a *APIDefinition) BaseInterface(iface *InterfaceDef) *InterfaceDef {
+	if iface.Extends == "" {
+		return nil
+	}
+	return a.InterfaceByName(iface.Extends)
+}
+
+// ParentHandleName returns the handle that handleName extends: the receiver
+// handle of the base interface of an interface whose receiver is handleName.
+func (a *APIDefinition) ParentHandleName(handleName string) (string, bool) {
+	for i := range a.Interfaces {
+		iface := &a.Interfaces[i]
+		base := a.BaseInterface(iface)
+		if base == nil {
+			continue
+		}
+		if receiver, ok := iface.ReceiverHandleName(); !ok || receiver != handleName {
+			continue
+		}
+		if parent, ok := base.ReceiverHandleName(); ok {
+			return parent, true
+		}
+	}
+	return "", false
+}
+
+// FirstHandleParam returns the method's first handle-typed parameter, the
+// handle it is called on, or nil if it has none.
+func (m *MethodDef) FirstHandleParam() *ParameterDef {
+	for i := range m.Parameters {
+		if _, ok := IsHandle(m.Parameters[i].Type); ok {
+			return &m.Parameters[i]
+		}
+	}
+	return nil
+}
+
+// MethodThreadAffinity returns the thread affinity of a constructor or method
+// of iface: its own, else the interface's, else that of the handle it is
+// called on or, if it takes none, the handle it returns. It returns "" when
+// none of them declares one.
+func (a *APIDefinition) MethodThreadAffinity(iface *InterfaceDef, method *MethodDef) string {
+	if method.ThreadAffinity != "" {
+		return method.ThreadAffinity
+	}
+	if iface.ThreadAffinity != "" {
+		return iface.ThreadAffinity
+	}
+	var handleName string
+	if p := method.FirstHandleParam(); p != nil {
+		handleName, _ = IsHandle(p.Type)
+	} else if method.Returns != nil {
+		handleName, _ = IsHandle(method.Returns.Type)
+	}
+	if h := a.HandleByName(handleName); h != nil {
+		return h.ThreadAffinity
+	}
+	return ""
+}
+
 // HandleByName looks up a handle definition by name.
 func (a *APIDefinition) HandleByName(name string) *HandleDef {
 	for i := range a.Handles {
@@ -148,6 +328,62 @@ func (a *APIDefinition) HandleByName(name string) *HandleDef {
 	return nil
 }
 
+// FlatBufferTypeRefs returns the FlatBuffers types the API surface names
+// directly: constructor and method parameters, returns and errors, and event
+// payloads. The result is sorted and has no duplicates.
+func (a *APIDefinition) FlatBufferTypeRefs() []string {
+	seen := make(map[string]bool)
+	add := func(t string) {
+		if IsFlatBufferType(t) {
+			seen[t] = true
+		}
+	}
+	addMethods := func(methods []MethodDef) {
+		for _, m := range methods {
+			for _, p := range m.Parameters {
+				add(p.Type)
+			}
+			if m.Returns != nil {
+				add(m.Returns.Type)
+			}
+			add(m.Error)
+		}
+	}
+	for _, iface := range a.Interfaces {
+		addMethods(iface.Constructors)
+		addMethods(iface.Methods)
+	}
+	for _, e := range a.Events {
+		add(e.Type)
+	}
+	refs := make([]string, 0, len(seen))
+	for t := range seen {
+		refs = append(refs, t)
+	}
+	sort.Strings(refs)
+	return refs
+}
+
+// NamespaceByName returns the mapping of a FlatBuffers namespace, or nil if
+// the API does not map it.
+func (a *APIDefinition) NamespaceByName(name string) *NamespaceDef {
+	for i := range a.Namespaces {
+		if a.Namespaces[i].Name == name {
+			return &a.Namespaces[i]
+		}
+	}
+	return nil
+}
+
+// FlatBufferNamespace returns the namespace of a fully-qualified FlatBuffers
+// type name, e.g. "Geometry.Transform3D" → "Geometry".
+func FlatBufferNamespace(t string) string {
+	if i := strings.LastIndex(t, "."); i >= 0 {
+		return t[:i]
+	}
+	return ""
+}
+
 // PrimitiveCType returns the C type for a primitive type name.
 func PrimitiveCType(t string) string {
 	switch t {
@@ -0,0 +1,20 @@
+package model
+
+import "fmt"
+
+// SourceLocation is the position of a node in an API definition YAML file.
+type SourceLocation struct {
+	File   string // YAML file path, as given to the loader
+	Line   int    // 1-based line number
+	Column int    // 1-based column number, 0 if unknown
+}
+
+func (l SourceLocation) String() string {
+	return fmt.Sprintf("%s:%d", l.File, l.Line)
+}
+
+// SourceMap maps JSONPath-style paths in a loaded API definition (e.g.
+// "interfaces[0].methods[1].returns.type") to where they were declared. An
+// API split across imported files numbers handles and interfaces by their
+// position in the merged definition.
+type SourceMap map[string]SourceLocation
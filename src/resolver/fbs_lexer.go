@@ -0,0 +1,305 @@
+package resolver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Pos is a position in a .fbs file. Line and Col are 1-based; Col counts bytes.
+type Pos struct {
+	File string
+	Line int
+	Col  int
+}
+
+func (p Pos) String() string {
+	if p.File == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Col)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Col)
+}
+
+// ParseError reports .fbs input that could not be tokenized, parsed or
+// resolved, at the position of the offending token.
+type ParseError struct {
+	Pos Pos
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return e.Pos.String() + ": " + e.Msg
+}
+
+func errorAt(pos Pos, format string, args ...any) *ParseError {
+	return &ParseError{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokInt
+	tokFloat
+	tokString
+	tokPunct
+)
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokEOF:
+		return "end of file"
+	case tokIdent:
+		return "identifier"
+	case tokInt:
+		return "integer"
+	case tokFloat:
+		return "float"
+	case tokString:
+		return "string"
+	default:
+		return "punctuation"
+	}
+}
+
+// token is a single lexical token. Text is the source spelling, except for
+// strings, where it is the unquoted value. Doc holds the /// comment lines
+// directly preceding the token.
+type token struct {
+	Kind tokenKind
+	Text string
+	Pos  Pos
+	Doc  []string
+}
+
+// describe returns the token as it should appear in an error message.
+func (t token) describe() string {
+	switch t.Kind {
+	case tokEOF:
+		return "end of file"
+	case tokString:
+		return strconv.Quote(t.Text)
+	default:
+		return fmt.Sprintf("%q", t.Text)
+	}
+}
+
+// lexFBS splits .fbs source into tokens, ending with a tokEOF token.
+// Comments are dropped, except /// doc comments, which are attached to the
+// token that follows them.
+func lexFBS(file string, src []byte) ([]token, error) {
+	l := &fbsLexer{file: file, src: src, line: 1, col: 1}
+	var tokens []token
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tok)
+		if tok.Kind == tokEOF {
+			return tokens, nil
+		}
+	}
+}
+
+type fbsLexer struct {
+	file string
+	src  []byte
+	off  int
+	line int
+	col  int
+	doc  []string
+}
+
+func (l *fbsLexer) pos() Pos {
+	return Pos{File: l.file, Line: l.line, Col: l.col}
+}
+
+func (l *fbsLexer) peek(ahead int) byte {
+	if l.off+ahead >= len(l.src) {
+		return 0
+	}
+	return l.src[l.off+ahead]
+}
+
+func (l *fbsLexer) advance() {
+	if l.src[l.off] == '\n' {
+		l.line++
+		l.col = 1
+	} else {
+		l.col++
+	}
+	l.off++
+}
+
+// skipSpace skips whitespace and comments, collecting doc comments.
+func (l *fbsLexer) skipSpace() error {
+	for l.off < len(l.src) {
+		c := l.src[l.off]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			l.advance()
+		case c == '/' && l.peek(1) == '/':
+			start := l.off
+			for l.off < len(l.src) && l.src[l.off] != '\n' {
+				l.advance()
+			}
+			text := string(l.src[start:l.off])
+			if strings.HasPrefix(text, "///") && !strings.HasPrefix(text, "////") {
+				l.doc = append(l.doc, strings.TrimSpace(text[3:]))
+			}
+		case c == '/' && l.peek(1) == '*':
+			start := l.pos()
+			l.advance()
+			l.advance()
+			for {
+				if l.off >= len(l.src) {
+					return errorAt(start, "unterminated block comment")
+				}
+				if l.src[l.off] == '*' && l.peek(1) == '/' {
+					l.advance()
+					l.advance()
+					break
+				}
+				l.advance()
+			}
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (l *fbsLexer) next() (token, error) {
+	if err := l.skipSpace(); err != nil {
+		return token{}, err
+	}
+	tok := token{Pos: l.pos(), Doc: l.doc}
+	l.doc = nil
+	if l.off >= len(l.src) {
+		tok.Kind = tokEOF
+		return tok, nil
+	}
+
+	start := l.off
+	c := l.src[l.off]
+	switch {
+	case isIdentStart(c):
+		for l.off < len(l.src) && isIdentChar(l.src[l.off]) {
+			l.advance()
+		}
+		tok.Kind = tokIdent
+	case isDigit(c) || (c == '.' && isDigit(l.peek(1))):
+		kind, err := l.number()
+		if err != nil {
+			return token{}, err
+		}
+		tok.Kind = kind
+	case c == '"':
+		value, err := l.str()
+		if err != nil {
+			return token{}, err
+		}
+		tok.Kind = tokString
+		tok.Text = value
+		return tok, nil
+	case strings.IndexByte("{}()[]:;,=.-+", c) >= 0:
+		l.advance()
+		tok.Kind = tokPunct
+	default:
+		return token{}, errorAt(tok.Pos, "unexpected character %q", rune(c))
+	}
+	tok.Text = string(l.src[start:l.off])
+	return tok, nil
+}
+
+// number scans a decimal or hexadecimal integer, or a decimal float.
+func (l *fbsLexer) number() (tokenKind, error) {
+	start := l.pos()
+	if l.src[l.off] == '0' && (l.peek(1) == 'x' || l.peek(1) == 'X') {
+		l.advance()
+		l.advance()
+		digits := 0
+		for l.off < len(l.src) && isHexDigit(l.src[l.off]) {
+			l.advance()
+			digits++
+		}
+		if digits == 0 {
+			return 0, errorAt(start, "hexadecimal literal has no digits")
+		}
+		if l.off < len(l.src) && isIdentChar(l.src[l.off]) {
+			return 0, errorAt(start, "malformed hexadecimal literal")
+		}
+		return tokInt, nil
+	}
+
+	kind := tokInt
+	for l.off < len(l.src) && isDigit(l.src[l.off]) {
+		l.advance()
+	}
+	if l.off < len(l.src) && l.src[l.off] == '.' {
+		kind = tokFloat
+		l.advance()
+		for l.off < len(l.src) && isDigit(l.src[l.off]) {
+			l.advance()
+		}
+	}
+	if c := l.peek(0); c == 'e' || c == 'E' {
+		kind = tokFloat
+		l.advance()
+		if c := l.peek(0); c == '+' || c == '-' {
+			l.advance()
+		}
+		if !isDigit(l.peek(0)) {
+			return 0, errorAt(start, "float literal has no exponent digits")
+		}
+		for l.off < len(l.src) && isDigit(l.src[l.off]) {
+			l.advance()
+		}
+	}
+	if l.off < len(l.src) && isIdentChar(l.src[l.off]) {
+		return 0, errorAt(start, "malformed number literal")
+	}
+	return kind, nil
+}
+
+// str scans a double-quoted string literal and returns its unquoted value.
+func (l *fbsLexer) str() (string, error) {
+	start := l.pos()
+	begin := l.off
+	l.advance()
+	for {
+		if l.off >= len(l.src) || l.src[l.off] == '\n' {
+			return "", errorAt(start, "unterminated string literal")
+		}
+		c := l.src[l.off]
+		l.advance()
+		if c == '\\' && l.off < len(l.src) {
+			l.advance()
+		} else if c == '"' {
+			break
+		}
+	}
+	value, err := strconv.Unquote(string(l.src[begin:l.off]))
+	if err != nil {
+		return "", errorAt(start, "invalid string literal %s", l.src[begin:l.off])
+	}
+	return value, nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
@@ -0,0 +1,83 @@
+package resolver
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLexFBS_Tokens(t *testing.T) {
+	src := "table T { x: [float:16]; v: int = -0x1F; s: string (id: \"a\\\"b\"); f: double = 1.5e3; }"
+	tokens, err := lexFBS("t.fbs", []byte(src))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct {
+		kind tokenKind
+		text string
+	}{
+		{tokIdent, "table"}, {tokIdent, "T"}, {tokPunct, "{"},
+		{tokIdent, "x"}, {tokPunct, ":"}, {tokPunct, "["}, {tokIdent, "float"}, {tokPunct, ":"}, {tokInt, "16"}, {tokPunct, "]"}, {tokPunct, ";"},
+		{tokIdent, "v"}, {tokPunct, ":"}, {tokIdent, "int"}, {tokPunct, "="}, {tokPunct, "-"}, {tokInt, "0x1F"}, {tokPunct, ";"},
+		{tokIdent, "s"}, {tokPunct, ":"}, {tokIdent, "string"}, {tokPunct, "("}, {tokIdent, "id"}, {tokPunct, ":"}, {tokString, `a"b`}, {tokPunct, ")"}, {tokPunct, ";"},
+		{tokIdent, "f"}, {tokPunct, ":"}, {tokIdent, "double"}, {tokPunct, "="}, {tokFloat, "1.5e3"}, {tokPunct, ";"},
+		{tokPunct, "}"}, {tokEOF, ""},
+	}
+	if len(tokens) != len(want) {
+		t.Fatalf("expected %d tokens, got %d: %v", len(want), len(tokens), tokens)
+	}
+	for i, w := range want {
+		if tokens[i].Kind != w.kind || tokens[i].Text != w.text {
+			t.Errorf("token %d: expected %s %q, got %s %q", i, w.kind, w.text, tokens[i].Kind, tokens[i].Text)
+		}
+	}
+}
+
+func TestLexFBS_CommentsAndPositions(t *testing.T) {
+	src := "// line comment\n/* block\n   comment */ table\n  /// Doc one.\n  /// Doc two.\n  T"
+	tokens, err := lexFBS("t.fbs", []byte(src))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tokens) != 3 {
+		t.Fatalf("expected 3 tokens, got %d: %v", len(tokens), tokens)
+	}
+	if got := tokens[0].Pos; got != (Pos{File: "t.fbs", Line: 3, Col: 15}) {
+		t.Errorf("expected table at t.fbs:3:15, got %s", got)
+	}
+	if got := tokens[1].Pos; got != (Pos{File: "t.fbs", Line: 6, Col: 3}) {
+		t.Errorf("expected T at t.fbs:6:3, got %s", got)
+	}
+	if len(tokens[1].Doc) != 2 || tokens[1].Doc[0] != "Doc one." || tokens[1].Doc[1] != "Doc two." {
+		t.Errorf("expected doc comment on T, got %q", tokens[1].Doc)
+	}
+	if len(tokens[0].Doc) != 0 {
+		t.Errorf("expected no doc comment on table, got %q", tokens[0].Doc)
+	}
+}
+
+func TestLexFBS_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"unexpected character", "table T {\n  x: int @;\n}", "t.fbs:2:10: unexpected character '@'"},
+		{"unterminated block comment", "table T {}\n/* never closed", "t.fbs:2:1: unterminated block comment"},
+		{"unterminated string", "include \"a.fbs;\n", "t.fbs:1:9: unterminated string literal"},
+		{"empty hex literal", "enum E : int { A = 0x }", "t.fbs:1:20: hexadecimal literal has no digits"},
+		{"malformed number", "enum E : int { A = 12ab }", "t.fbs:1:20: malformed number literal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := lexFBS("t.fbs", []byte(tt.src))
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("expected *ParseError, got %v", err)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,626 @@
+package resolver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Schema is the syntax tree of a single .fbs file.
+type Schema struct {
+	File           string
+	Includes       []*IncludeDecl
+	Attributes     []*AttributeDecl
+	Types          []*TypeDecl
+	Services       []*ServiceDecl
+	RootType       *RootTypeDecl
+	FileIdentifier string
+	FileExtension  string
+}
+
+// IncludeDecl is an `include "path";` (or `native_include`) directive.
+type IncludeDecl struct {
+	Pos    Pos
+	Path   string
+	Native bool
+}
+
+// AttributeDecl declares a user-defined attribute: `attribute "name";`.
+type AttributeDecl struct {
+	Pos  Pos
+	Name string
+}
+
+// RootTypeDecl is a `root_type Name;` declaration.
+type RootTypeDecl struct {
+	Pos  Pos
+	Name string
+}
+
+// Attribute is one entry of a metadata list, e.g. `id: 3` or `deprecated`.
+// Value is the literal as written ("" if absent); strings are unquoted.
+type Attribute struct {
+	Pos   Pos
+	Name  string
+	Value string
+}
+
+// TypeDecl is a table, struct, enum or union declaration.
+type TypeDecl struct {
+	Pos        Pos
+	Kind       TypeKind
+	Namespace  string
+	Name       string
+	BaseType   string // Enums only, as written (e.g. "ubyte")
+	Attributes []Attribute
+	Fields     []*FieldDecl     // Tables and structs only
+	Values     []*EnumValueDecl // Enums and unions only
+	Doc        []string
+}
+
+// QualifiedName returns the declaration's namespace-qualified name.
+func (d *TypeDecl) QualifiedName() string {
+	return qualifiedName(d.Namespace, d.Name)
+}
+
+// TypeRef is a field type: a named type, a vector `[T]` or a fixed-length
+// array `[T:N]`. Name is the element type for vectors and arrays.
+type TypeRef struct {
+	Pos    Pos
+	Name   string
+	Vector bool
+	Length int // Fixed-length arrays only
+}
+
+func (t TypeRef) String() string {
+	switch {
+	case t.Length > 0:
+		return fmt.Sprintf("[%s:%d]", t.Name, t.Length)
+	case t.Vector:
+		return "[" + t.Name + "]"
+	default:
+		return t.Name
+	}
+}
+
+// FieldDecl is a field of a table or struct. Default is the default value
+// literal as written ("" if absent).
+type FieldDecl struct {
+	Pos        Pos
+	Name       string
+	Type       TypeRef
+	Default    string
+	Attributes []Attribute
+	Doc        []string
+}
+
+// EnumValueDecl is an enum value or union member. Value is the explicit
+// integer literal as written ("" if implicit). Type is the member type of a
+// union; for unaliased members it equals Name.
+type EnumValueDecl struct {
+	Pos        Pos
+	Name       string
+	Value      string
+	Type       string
+	Attributes []Attribute
+	Doc        []string
+}
+
+// ServiceDecl is an `rpc_service` declaration.
+type ServiceDecl struct {
+	Pos        Pos
+	Namespace  string
+	Name       string
+	Attributes []Attribute
+	Methods    []*RPCMethodDecl
+	Doc        []string
+}
+
+// RPCMethodDecl is a single `name(Request): Response;` method of a service.
+type RPCMethodDecl struct {
+	Pos        Pos
+	Name       string
+	Request    string
+	Response   string
+	Attributes []Attribute
+	Doc        []string
+}
+
+// ParseFBS parses .fbs source into a syntax tree. file is used only for
+// error positions. Errors are *ParseError values.
+func ParseFBS(file string, src []byte) (*Schema, error) {
+	tokens, err := lexFBS(file, src)
+	if err != nil {
+		return nil, err
+	}
+	p := &fbsParser{tokens: tokens, schema: &Schema{File: file}}
+	if err := p.parseSchema(); err != nil {
+		return nil, err
+	}
+	return p.schema, nil
+}
+
+type fbsParser struct {
+	tokens    []token
+	off       int
+	namespace string
+	schema    *Schema
+}
+
+func (p *fbsParser) peek() token {
+	return p.tokens[p.off]
+}
+
+func (p *fbsParser) next() token {
+	tok := p.tokens[p.off]
+	if tok.Kind != tokEOF {
+		p.off++
+	}
+	return tok
+}
+
+// isPunct reports whether the next token is the punctuation s.
+func (p *fbsParser) isPunct(s string) bool {
+	tok := p.peek()
+	return tok.Kind == tokPunct && tok.Text == s
+}
+
+// accept consumes the punctuation s if it is next.
+func (p *fbsParser) accept(s string) bool {
+	if p.isPunct(s) {
+		p.off++
+		return true
+	}
+	return false
+}
+
+func (p *fbsParser) expect(s string) (token, error) {
+	tok := p.next()
+	if tok.Kind != tokPunct || tok.Text != s {
+		return tok, errorAt(tok.Pos, "expected %q, found %s", s, tok.describe())
+	}
+	return tok, nil
+}
+
+func (p *fbsParser) expectIdent(what string) (token, error) {
+	tok := p.next()
+	if tok.Kind != tokIdent {
+		return tok, errorAt(tok.Pos, "expected %s, found %s", what, tok.describe())
+	}
+	return tok, nil
+}
+
+func (p *fbsParser) expectString(what string) (token, error) {
+	tok := p.next()
+	if tok.Kind != tokString {
+		return tok, errorAt(tok.Pos, "expected %s, found %s", what, tok.describe())
+	}
+	return tok, nil
+}
+
+// qualifiedIdent parses `ident ( . ident )*`.
+func (p *fbsParser) qualifiedIdent(what string) (string, Pos, error) {
+	first, err := p.expectIdent(what)
+	if err != nil {
+		return "", first.Pos, err
+	}
+	parts := []string{first.Text}
+	for p.accept(".") {
+		tok, err := p.expectIdent(what)
+		if err != nil {
+			return "", first.Pos, err
+		}
+		parts = append(parts, tok.Text)
+	}
+	return strings.Join(parts, "."), first.Pos, nil
+}
+
+func (p *fbsParser) parseSchema() error {
+	declared := false
+	for {
+		tok := p.peek()
+		switch tok.Kind {
+		case tokEOF:
+			return nil
+		case tokIdent:
+		case tokPunct:
+			if tok.Text == "{" {
+				return errorAt(tok.Pos, "JSON data in schema files is not supported")
+			}
+			if tok.Text == ";" {
+				p.next()
+				continue
+			}
+			return errorAt(tok.Pos, "expected a declaration, found %s", tok.describe())
+		default:
+			return errorAt(tok.Pos, "expected a declaration, found %s", tok.describe())
+		}
+
+		if tok.Text == "include" || tok.Text == "native_include" {
+			if declared {
+				return errorAt(tok.Pos, "includes must come before all declarations")
+			}
+			if err := p.parseInclude(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var err error
+		switch tok.Text {
+		case "namespace":
+			err = p.parseNamespace()
+		case "attribute":
+			err = p.parseAttributeDecl()
+		case "table", "struct", "enum", "union":
+			err = p.parseTypeDecl()
+		case "rpc_service":
+			err = p.parseService()
+		case "root_type":
+			err = p.parseRootType()
+		case "file_identifier", "file_extension":
+			err = p.parseFileDirective()
+		default:
+			return errorAt(tok.Pos, "unknown declaration %q", tok.Text)
+		}
+		if err != nil {
+			return err
+		}
+		declared = true
+	}
+}
+
+func (p *fbsParser) parseInclude() error {
+	kw := p.next()
+	path, err := p.expectString("include path string")
+	if err != nil {
+		return err
+	}
+	if _, err := p.expect(";"); err != nil {
+		return err
+	}
+	p.schema.Includes = append(p.schema.Includes, &IncludeDecl{
+		Pos:    kw.Pos,
+		Path:   path.Text,
+		Native: kw.Text == "native_include",
+	})
+	return nil
+}
+
+func (p *fbsParser) parseNamespace() error {
+	p.next()
+	if p.accept(";") {
+		p.namespace = ""
+		return nil
+	}
+	name, _, err := p.qualifiedIdent("namespace name")
+	if err != nil {
+		return err
+	}
+	if _, err := p.expect(";"); err != nil {
+		return err
+	}
+	p.namespace = name
+	return nil
+}
+
+func (p *fbsParser) parseAttributeDecl() error {
+	p.next()
+	tok := p.next()
+	if tok.Kind != tokString && tok.Kind != tokIdent {
+		return errorAt(tok.Pos, "expected attribute name, found %s", tok.describe())
+	}
+	if _, err := p.expect(";"); err != nil {
+		return err
+	}
+	p.schema.Attributes = append(p.schema.Attributes, &AttributeDecl{Pos: tok.Pos, Name: tok.Text})
+	return nil
+}
+
+func (p *fbsParser) parseRootType() error {
+	kw := p.next()
+	name, pos, err := p.qualifiedIdent("root type name")
+	if err != nil {
+		return err
+	}
+	if _, err := p.expect(";"); err != nil {
+		return err
+	}
+	if p.schema.RootType != nil {
+		return errorAt(kw.Pos, "root_type already declared at %s", p.schema.RootType.Pos)
+	}
+	p.schema.RootType = &RootTypeDecl{Pos: pos, Name: name}
+	return nil
+}
+
+func (p *fbsParser) parseFileDirective() error {
+	kw := p.next()
+	value, err := p.expectString(kw.Text + " string")
+	if err != nil {
+		return err
+	}
+	if _, err := p.expect(";"); err != nil {
+		return err
+	}
+	if kw.Text == "file_identifier" {
+		if len(value.Text) != 4 {
+			return errorAt(value.Pos, "file_identifier must be exactly 4 characters, got %q", value.Text)
+		}
+		p.schema.FileIdentifier = value.Text
+	} else {
+		p.schema.FileExtension = value.Text
+	}
+	return nil
+}
+
+func (p *fbsParser) parseTypeDecl() error {
+	kw := p.next()
+	name, err := p.expectIdent(kw.Text + " name")
+	if err != nil {
+		return err
+	}
+	decl := &TypeDecl{Pos: name.Pos, Namespace: p.namespace, Name: name.Text, Doc: kw.Doc}
+	switch kw.Text {
+	case "table":
+		decl.Kind = TypeKindTable
+	case "struct":
+		decl.Kind = TypeKindStruct
+	case "enum":
+		decl.Kind = TypeKindEnum
+		if !p.isPunct(":") {
+			tok := p.peek()
+			return errorAt(tok.Pos, "enum %s needs an underlying integer type (e.g. `enum %s : int32`)", name.Text, name.Text)
+		}
+		p.next()
+		base, err := p.expectIdent("enum underlying type")
+		if err != nil {
+			return err
+		}
+		decl.BaseType = base.Text
+	case "union":
+		decl.Kind = TypeKindUnion
+	}
+
+	if decl.Attributes, err = p.parseMetadata(); err != nil {
+		return err
+	}
+	if _, err := p.expect("{"); err != nil {
+		return err
+	}
+	if decl.Kind == TypeKindTable || decl.Kind == TypeKindStruct {
+		for !p.accept("}") {
+			field, err := p.parseField()
+			if err != nil {
+				return err
+			}
+			decl.Fields = append(decl.Fields, field)
+		}
+	} else {
+		for !p.accept("}") {
+			value, err := p.parseEnumValue(decl.Kind == TypeKindUnion)
+			if err != nil {
+				return err
+			}
+			decl.Values = append(decl.Values, value)
+			if !p.accept(",") && !p.isPunct("}") {
+				tok := p.peek()
+				return errorAt(tok.Pos, "expected \",\" or \"}\" after %s value, found %s", kw.Text, tok.describe())
+			}
+		}
+	}
+	p.schema.Types = append(p.schema.Types, decl)
+	return nil
+}
+
+func (p *fbsParser) parseField() (*FieldDecl, error) {
+	name, err := p.expectIdent("field name")
+	if err != nil {
+		return nil, err
+	}
+	field := &FieldDecl{Pos: name.Pos, Name: name.Text, Doc: name.Doc}
+	if _, err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	if field.Type, err = p.parseType(); err != nil {
+		return nil, err
+	}
+	if p.accept("=") {
+		if field.Default, err = p.parseValue("default value"); err != nil {
+			return nil, err
+		}
+	}
+	if field.Attributes, err = p.parseMetadata(); err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(";"); err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+// parseType parses a field type: `name`, `[type]` or `[type:length]`.
+func (p *fbsParser) parseType() (TypeRef, error) {
+	tok := p.peek()
+	if !p.accept("[") {
+		name, pos, err := p.qualifiedIdent("type name")
+		return TypeRef{Pos: pos, Name: name}, err
+	}
+	if p.isPunct("[") {
+		return TypeRef{}, errorAt(p.peek().Pos, "nested vector types are not supported")
+	}
+	name, _, err := p.qualifiedIdent("element type name")
+	if err != nil {
+		return TypeRef{}, err
+	}
+	ref := TypeRef{Pos: tok.Pos, Name: name, Vector: true}
+	if p.accept(":") {
+		n := p.next()
+		if n.Kind != tokInt {
+			return TypeRef{}, errorAt(n.Pos, "expected array length, found %s", n.describe())
+		}
+		length, err := strconv.ParseUint(n.Text, 0, 16)
+		if err != nil || length == 0 {
+			return TypeRef{}, errorAt(n.Pos, "array length %s must be between 1 and 65535", n.Text)
+		}
+		ref.Vector = false
+		ref.Length = int(length)
+	}
+	if _, err := p.expect("]"); err != nil {
+		return TypeRef{}, err
+	}
+	return ref, nil
+}
+
+// parseEnumValue parses `Name [= int] metadata` for enums, and
+// `[Alias :] Type [= int] metadata` for unions.
+func (p *fbsParser) parseEnumValue(union bool) (*EnumValueDecl, error) {
+	what := "enum value name"
+	if union {
+		what = "union member type"
+	}
+	doc := p.peek().Doc
+	name, pos, err := p.qualifiedIdent(what)
+	if err != nil {
+		return nil, err
+	}
+	value := &EnumValueDecl{Pos: pos, Name: name, Doc: doc}
+	if union {
+		value.Type = name
+		if p.accept(":") {
+			if strings.Contains(name, ".") {
+				return nil, errorAt(pos, "union member alias %q must be a plain identifier", name)
+			}
+			if value.Type, _, err = p.qualifiedIdent(what); err != nil {
+				return nil, err
+			}
+		}
+	} else if strings.Contains(name, ".") {
+		return nil, errorAt(pos, "enum value name %q must be a plain identifier", name)
+	}
+	if p.accept("=") {
+		sign := ""
+		if p.accept("-") {
+			sign = "-"
+		} else {
+			p.accept("+")
+		}
+		tok := p.next()
+		if tok.Kind != tokInt {
+			return nil, errorAt(tok.Pos, "expected integer value for %s, found %s", name, tok.describe())
+		}
+		value.Value = sign + tok.Text
+	}
+	if value.Attributes, err = p.parseMetadata(); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// parseMetadata parses an optional `( name [: value], ... )` attribute list.
+func (p *fbsParser) parseMetadata() ([]Attribute, error) {
+	if !p.accept("(") {
+		return nil, nil
+	}
+	var attrs []Attribute
+	for {
+		tok := p.next()
+		if tok.Kind != tokIdent && tok.Kind != tokString {
+			return nil, errorAt(tok.Pos, "expected attribute name, found %s", tok.describe())
+		}
+		attr := Attribute{Pos: tok.Pos, Name: tok.Text}
+		if p.accept(":") {
+			value, err := p.parseValue("attribute value")
+			if err != nil {
+				return nil, err
+			}
+			attr.Value = value
+		}
+		attrs = append(attrs, attr)
+		if p.accept(")") {
+			return attrs, nil
+		}
+		if _, err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseValue parses a scalar, identifier or string constant and returns it
+// as written (strings unquoted).
+func (p *fbsParser) parseValue(what string) (string, error) {
+	sign := ""
+	if p.accept("-") {
+		sign = "-"
+	} else {
+		p.accept("+")
+	}
+	tok := p.next()
+	switch tok.Kind {
+	case tokInt, tokFloat:
+		return sign + tok.Text, nil
+	case tokIdent:
+		if sign != "" && tok.Text != "inf" && tok.Text != "infinity" && tok.Text != "nan" {
+			return "", errorAt(tok.Pos, "unexpected sign before %q", tok.Text)
+		}
+		name := tok.Text
+		for p.accept(".") {
+			part, err := p.expectIdent(what)
+			if err != nil {
+				return "", err
+			}
+			name += "." + part.Text
+		}
+		return sign + name, nil
+	case tokString:
+		if sign == "" {
+			return tok.Text, nil
+		}
+	}
+	return "", errorAt(tok.Pos, "expected %s, found %s", what, tok.describe())
+}
+
+func (p *fbsParser) parseService() error {
+	kw := p.next()
+	name, err := p.expectIdent("rpc_service name")
+	if err != nil {
+		return err
+	}
+	svc := &ServiceDecl{Pos: name.Pos, Namespace: p.namespace, Name: name.Text, Doc: kw.Doc}
+	if svc.Attributes, err = p.parseMetadata(); err != nil {
+		return err
+	}
+	if _, err := p.expect("{"); err != nil {
+		return err
+	}
+	for !p.accept("}") {
+		mname, err := p.expectIdent("rpc method name")
+		if err != nil {
+			return err
+		}
+		method := &RPCMethodDecl{Pos: mname.Pos, Name: mname.Text, Doc: mname.Doc}
+		if _, err := p.expect("("); err != nil {
+			return err
+		}
+		if method.Request, _, err = p.qualifiedIdent("request type"); err != nil {
+			return err
+		}
+		if _, err := p.expect(")"); err != nil {
+			return err
+		}
+		if _, err := p.expect(":"); err != nil {
+			return err
+		}
+		if method.Response, _, err = p.qualifiedIdent("response type"); err != nil {
+			return err
+		}
+		if method.Attributes, err = p.parseMetadata(); err != nil {
+			return err
+		}
+		if _, err := p.expect(";"); err != nil {
+			return err
+		}
+		svc.Methods = append(svc.Methods, method)
+	}
+	p.schema.Services = append(p.schema.Services, svc)
+	return nil
+}
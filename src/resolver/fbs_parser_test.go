@@ -0,0 +1,185 @@
+package resolver
+
+import (
+	"errors"
+	"testing"
+)
+
+const fullSchema = `include "base.fbs";
+native_include "native.h";
+
+attribute "priority";
+
+namespace Game.Core;
+
+/// A colour.
+enum Color : ubyte (bit_flags) {
+  Red,
+  /// Green comes second.
+  Green = 0x4,
+  Blue,
+}
+
+union Payload { Monster, Alias: Other.Weapon }
+
+table Monster (priority: 1) {
+  hp: short = 100;
+  name: string (required, key);
+  color: Color = Blue;
+  pos: Vec3 (deprecated);
+  inventory: [ubyte];
+  ratio: float = -inf;
+  friendly: bool
+    = false
+    (id: 6);
+}
+
+struct Vec3 (force_align: 16) {
+  x: float;
+  m: [float:16];
+}
+
+rpc_service MonsterStorage {
+  Store(Monster): Monster (streaming: "none");
+  Retrieve(Game.Core.Monster): Monster;
+}
+
+root_type Monster;
+file_identifier "MONS";
+file_extension "mon";
+`
+
+func TestParseFBS_FullSchema(t *testing.T) {
+	schema, err := ParseFBS("full.fbs", []byte(fullSchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(schema.Includes) != 2 || schema.Includes[0].Path != "base.fbs" || schema.Includes[0].Native || !schema.Includes[1].Native {
+		t.Errorf("unexpected includes: %+v", schema.Includes)
+	}
+	if len(schema.Attributes) != 1 || schema.Attributes[0].Name != "priority" {
+		t.Errorf("unexpected attribute declarations: %+v", schema.Attributes)
+	}
+	if schema.RootType == nil || schema.RootType.Name != "Monster" {
+		t.Errorf("unexpected root_type: %+v", schema.RootType)
+	}
+	if schema.FileIdentifier != "MONS" || schema.FileExtension != "mon" {
+		t.Errorf("unexpected file directives: %q %q", schema.FileIdentifier, schema.FileExtension)
+	}
+	if len(schema.Types) != 4 {
+		t.Fatalf("expected 4 types, got %d", len(schema.Types))
+	}
+
+	color := schema.Types[0]
+	if color.QualifiedName() != "Game.Core.Color" || color.Kind != TypeKindEnum || color.BaseType != "ubyte" {
+		t.Errorf("unexpected enum: %+v", color)
+	}
+	if color.Pos != (Pos{File: "full.fbs", Line: 9, Col: 6}) {
+		t.Errorf("expected Color at full.fbs:9:6, got %s", color.Pos)
+	}
+	if len(color.Doc) != 1 || color.Doc[0] != "A colour." {
+		t.Errorf("unexpected enum doc: %q", color.Doc)
+	}
+	if len(color.Attributes) != 1 || color.Attributes[0].Name != "bit_flags" {
+		t.Errorf("unexpected enum attributes: %+v", color.Attributes)
+	}
+	if len(color.Values) != 3 || color.Values[1].Value != "0x4" || color.Values[1].Doc[0] != "Green comes second." {
+		t.Errorf("unexpected enum values: %+v", color.Values)
+	}
+
+	payload := schema.Types[1]
+	if payload.Kind != TypeKindUnion || len(payload.Values) != 2 {
+		t.Fatalf("unexpected union: %+v", payload)
+	}
+	if payload.Values[0].Name != "Monster" || payload.Values[0].Type != "Monster" {
+		t.Errorf("unexpected union member: %+v", payload.Values[0])
+	}
+	if payload.Values[1].Name != "Alias" || payload.Values[1].Type != "Other.Weapon" {
+		t.Errorf("unexpected aliased union member: %+v", payload.Values[1])
+	}
+
+	monster := schema.Types[2]
+	if len(monster.Attributes) != 1 || monster.Attributes[0].Name != "priority" || monster.Attributes[0].Value != "1" {
+		t.Errorf("unexpected table attributes: %+v", monster.Attributes)
+	}
+	fields := map[string]*FieldDecl{}
+	for _, f := range monster.Fields {
+		fields[f.Name] = f
+	}
+	if len(fields) != 7 {
+		t.Fatalf("expected 7 fields, got %d", len(fields))
+	}
+	if f := fields["hp"]; f.Type.String() != "short" || f.Default != "100" {
+		t.Errorf("unexpected hp field: %+v", f)
+	}
+	if f := fields["name"]; len(f.Attributes) != 2 || f.Attributes[0].Name != "required" || f.Attributes[1].Name != "key" {
+		t.Errorf("unexpected name attributes: %+v", f.Attributes)
+	}
+	if f := fields["color"]; f.Default != "Blue" {
+		t.Errorf("expected color default Blue, got %q", f.Default)
+	}
+	if f := fields["inventory"]; !f.Type.Vector || f.Type.Name != "ubyte" {
+		t.Errorf("unexpected inventory type: %+v", f.Type)
+	}
+	if f := fields["ratio"]; f.Default != "-inf" {
+		t.Errorf("expected ratio default -inf, got %q", f.Default)
+	}
+	if f := fields["friendly"]; f.Default != "false" || len(f.Attributes) != 1 || f.Attributes[0].Value != "6" {
+		t.Errorf("unexpected multi-line friendly field: %+v", f)
+	}
+
+	vec := schema.Types[3]
+	if vec.Kind != TypeKindStruct || vec.Fields[1].Type.Length != 16 || vec.Fields[1].Type.String() != "[float:16]" {
+		t.Errorf("unexpected struct: %+v", vec.Fields)
+	}
+
+	if len(schema.Services) != 1 {
+		t.Fatalf("expected 1 service, got %d", len(schema.Services))
+	}
+	svc := schema.Services[0]
+	if svc.Namespace != "Game.Core" || len(svc.Methods) != 2 {
+		t.Fatalf("unexpected service: %+v", svc)
+	}
+	if m := svc.Methods[1]; m.Request != "Game.Core.Monster" || m.Response != "Monster" {
+		t.Errorf("unexpected rpc method: %+v", m)
+	}
+	if m := svc.Methods[0]; len(m.Attributes) != 1 || m.Attributes[0].Value != "none" {
+		t.Errorf("unexpected rpc attributes: %+v", m.Attributes)
+	}
+}
+
+func TestParseFBS_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"missing default", "table T {\n  x: int = ;\n}", "t.fbs:2:12: expected default value, found \";\""},
+		{"missing semicolon", "table T {\n  x: int\n}", "t.fbs:3:1: expected \";\", found \"}\""},
+		{"enum without type", "enum E { A }", "t.fbs:1:8: enum E needs an underlying integer type (e.g. `enum E : int32`)"},
+		{"unknown declaration", "namespace A;\ninterface T {}", "t.fbs:2:1: unknown declaration \"interface\""},
+		{"late include", "namespace A;\ninclude \"b.fbs\";", "t.fbs:2:1: includes must come before all declarations"},
+		{"nested vector", "table T { x: [[int]]; }", "t.fbs:1:15: nested vector types are not supported"},
+		{"zero array length", "struct S { x: [int:0]; }", "t.fbs:1:20: array length 0 must be between 1 and 65535"},
+		{"missing enum comma", "enum E : int { A B }", "t.fbs:1:18: expected \",\" or \"}\" after enum value, found \"B\""},
+		{"qualified enum value", "enum E : int { A.B }", "t.fbs:1:16: enum value name \"A.B\" must be a plain identifier"},
+		{"non-integer enum value", "enum E : int { A = 1.5 }", "t.fbs:1:20: expected integer value for A, found \"1.5\""},
+		{"json object", "{ a: 1 }", "t.fbs:1:1: JSON data in schema files is not supported"},
+		{"bad file identifier", "file_identifier \"ABC\";", "t.fbs:1:17: file_identifier must be exactly 4 characters, got \"ABC\""},
+		{"unterminated table", "table T {\n  x: int;\n", "t.fbs:3:1: expected field name, found end of file"},
+		{"duplicate root type", "root_type A;\nroot_type B;", "t.fbs:2:1: root_type already declared at t.fbs:1:11"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFBS("t.fbs", []byte(tt.src))
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("expected *ParseError, got %v", err)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package resolver
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// scalarBits gives the width of each scalar field type; bool is 8 wide.
+var scalarBits = map[string]int{
+	"bool": 8, "int8": 8, "uint8": 8, "int16": 16, "uint16": 16,
+	"int32": 32, "uint32": 32, "int64": 64, "uint64": 64,
+	"float32": 32, "float64": 64,
+}
+
+// IsScalarField reports whether an FBS field type is a bool, integer or
+// float (enums are scalars too but are named types).
+func IsScalarField(t string) bool {
+	_, ok := scalarBits[t]
+	return ok
+}
+
+// ArrayField splits a fixed-length array field type such as "[float32:16]"
+// into its element type and length. ok is false for any other type,
+// including vectors.
+func ArrayField(t string) (elem string, length int, ok bool) {
+	if !strings.HasPrefix(t, "[") || !strings.HasSuffix(t, "]") {
+		return "", 0, false
+	}
+	i := strings.LastIndex(t, ":")
+	if i < 0 {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(t[i+1 : len(t)-1])
+	if err != nil {
+		return "", 0, false
+	}
+	return t[1:i], n, true
+}
+
+// typeFields converts the fields of a table or struct, interpreting the
+// metadata attributes flatc gives meaning to.
+func typeFields(decl *TypeDecl) ([]FieldDef, error) {
+	seen := make(map[string]bool)
+	ids := make(map[int]string)
+	var fields []FieldDef
+	var key string
+	for _, f := range decl.Fields {
+		if seen[f.Name] {
+			return nil, errorAt(f.Pos, "duplicate field %s in %s %s", f.Name, decl.Kind, decl.Name)
+		}
+		seen[f.Name] = true
+		ref := f.Type
+		ref.Name = fbsTypeAlias(ref.Name)
+		if decl.Kind == TypeKindStruct {
+			if ref.Vector || ref.Name == "string" {
+				return nil, errorAt(f.Pos, "struct field %s cannot be a vector or string", f.Name)
+			}
+			if f.Default != "" {
+				return nil, errorAt(f.Pos, "struct field %s cannot have a default value", f.Name)
+			}
+		} else if ref.Length > 0 {
+			return nil, errorAt(f.Pos, "fixed-length array field %s is only allowed in structs", f.Name)
+		}
+
+		field := FieldDef{Name: f.Name, Type: ref.String(), Default: f.Default, Attributes: f.Attributes, Pos: f.Pos}
+		for _, attr := range f.Attributes {
+			switch attr.Name {
+			case "deprecated":
+				if decl.Kind == TypeKindStruct {
+					return nil, errorAt(attr.Pos, "struct field %s cannot be deprecated", f.Name)
+				}
+				field.Deprecated = true
+			case "required":
+				if decl.Kind == TypeKindStruct {
+					return nil, errorAt(attr.Pos, "struct field %s cannot be required; struct fields are always present", f.Name)
+				}
+				field.Required = true
+			case "key":
+				if key != "" {
+					return nil, errorAt(attr.Pos, "%s %s already has key field %s", decl.Kind, decl.Name, key)
+				}
+				key = f.Name
+				field.Key = true
+			case "id":
+				id, err := strconv.ParseUint(attr.Value, 10, 16)
+				if err != nil {
+					return nil, errorAt(attr.Pos, "id of field %s must be a non-negative integer, got %q", f.Name, attr.Value)
+				}
+				if other, ok := ids[int(id)]; ok {
+					return nil, errorAt(attr.Pos, "field %s reuses id %d of field %s", f.Name, id, other)
+				}
+				ids[int(id)] = f.Name
+				field.ID = int(id)
+				field.HasID = true
+			}
+		}
+		fields = append(fields, field)
+	}
+	if len(ids) > 0 && len(ids) < len(fields) {
+		for _, f := range fields {
+			if !f.HasID {
+				return nil, errorAt(f.Pos, "field %s needs an id: either all fields of %s %s have one or none do", f.Name, decl.Kind, decl.Name)
+			}
+		}
+	}
+	return fields, nil
+}
+
+// resolveFields qualifies field type names, checks the field attributes that
+// depend on the field's type and normalizes default values, once every type
+// is known.
+func resolveFields(types ResolvedTypes) error {
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		info := types[name]
+		scope := namespaceOf(name)
+		for i := range info.Fields {
+			f := &info.Fields[i]
+			f.Type = qualifyFieldType(types, scope, f.Type)
+			var enum *TypeInfo
+			if target, _, ok := LookupType(types, scope, f.Type); ok && target.Kind == TypeKindEnum {
+				enum = target
+			}
+			scalar := IsScalarField(f.Type) || enum != nil
+			if f.Required && scalar {
+				return errorAt(f.Pos, "scalar field %s cannot be required", f.Name)
+			}
+			if f.Default == "" {
+				continue
+			}
+			if !scalar {
+				return errorAt(f.Pos, "field %s of type %s cannot have a default value; only scalar and enum fields can", f.Name, f.Type)
+			}
+			value, err := normalizeDefault(f.Type, f.Default, enum)
+			if err != nil {
+				return errorAt(f.Pos, "default value of field %s: %s", f.Name, err.Error())
+			}
+			f.Default = value
+		}
+	}
+	return nil
+}
+
+// qualifyFieldType rewrites the named type of a field, or the element type
+// of a vector or array, to its fully-qualified name so the generators need
+// no namespace scope. Unknown names are left as written.
+func qualifyFieldType(types ResolvedTypes, scope, t string) string {
+	if elem, n, ok := ArrayField(t); ok {
+		return fmt.Sprintf("[%s:%d]", qualifyFieldType(types, scope, elem), n)
+	}
+	if strings.HasPrefix(t, "[") && strings.HasSuffix(t, "]") {
+		return "[" + qualifyFieldType(types, scope, t[1:len(t)-1]) + "]"
+	}
+	if _, qualified, ok := LookupType(types, scope, t); ok {
+		return qualified
+	}
+	return t
+}
+
+// normalizeDefault converts a default value literal into the form the
+// generators use: "true"/"false" for bools, a decimal integer for integers
+// and enums (enum value names become their values), and a decimal float,
+// "nan", "inf" or "-inf" for floats.
+func normalizeDefault(fieldType, lit string, enum *TypeInfo) (string, error) {
+	if enum != nil {
+		for _, v := range enum.EnumValues {
+			if v.Name == lit {
+				return strconv.FormatInt(v.Value, 10), nil
+			}
+		}
+		n, err := strconv.ParseInt(lit, 0, 64)
+		if err != nil {
+			return "", fmt.Errorf("%q is not a value of the enum", lit)
+		}
+		for _, v := range enum.EnumValues {
+			if v.Value == n {
+				return strconv.FormatInt(n, 10), nil
+			}
+		}
+		return "", fmt.Errorf("%s is not a value of the enum", lit)
+	}
+
+	bits := scalarBits[fieldType]
+	switch {
+	case fieldType == "bool":
+		switch lit {
+		case "true", "1":
+			return "true", nil
+		case "false", "0":
+			return "false", nil
+		}
+		return "", fmt.Errorf("%q is not a bool", lit)
+	case strings.HasPrefix(fieldType, "float"):
+		switch strings.TrimPrefix(lit, "+") {
+		case "nan", "-nan":
+			return "nan", nil
+		case "inf", "infinity":
+			return "inf", nil
+		case "-inf", "-infinity":
+			return "-inf", nil
+		}
+		v, err := strconv.ParseFloat(lit, bits)
+		if err != nil || math.IsInf(v, 0) {
+			return "", fmt.Errorf("%q is not a %s", lit, fieldType)
+		}
+		return strconv.FormatFloat(v, 'g', -1, bits), nil
+	case strings.HasPrefix(fieldType, "uint"):
+		v, err := strconv.ParseUint(lit, 0, bits)
+		if err != nil {
+			return "", fmt.Errorf("%q is not a %s", lit, fieldType)
+		}
+		return strconv.FormatUint(v, 10), nil
+	default:
+		v, err := strconv.ParseInt(lit, 0, bits)
+		if err != nil {
+			return "", fmt.Errorf("%q is not a %s", lit, fieldType)
+		}
+		return strconv.FormatInt(v, 10), nil
+	}
+}
+
+// LookupType resolves a type name used inside namespace scope the way flatc
+// does: relative to scope and each enclosing namespace, innermost first.
+// It returns the type and its fully-qualified name.
+func LookupType(types ResolvedTypes, scope, name string) (*TypeInfo, string, bool) {
+	for {
+		qualified := qualifiedName(scope, name)
+		if info, ok := types[qualified]; ok {
+			return info, qualified, true
+		}
+		if scope == "" {
+			return nil, "", false
+		}
+		scope = namespaceOf(scope)
+	}
+}
+
+// namespaceOf returns the namespace part of a qualified name.
+func namespaceOf(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[:i]
+	}
+	return ""
+}
@@ -0,0 +1,224 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseFBSFile_FieldAttributes(t *testing.T) {
+	fbs := filepath.Join(t.TempDir(), "fields.fbs")
+	content := `namespace Test;
+
+enum Level : byte { Low = -1, Mid, High }
+
+table Config {
+  name: string (id: 0, required, key);
+  gain: float = 2 (id: 1);
+  level: Level = High (id: 2);
+  old: int (id: 3, deprecated);
+  ratio: double = -inf (id: 4, ui_hint: "slider", hidden);
+  mask: ulong = 0xFF (id: 5);
+}
+`
+	os.WriteFile(fbs, []byte(content), 0644)
+
+	types, err := ParseFBSFile(fbs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fields := types["Test.Config"].Fields
+	if len(fields) != 6 {
+		t.Fatalf("expected 6 fields, got %v", fields)
+	}
+
+	name := fields[0]
+	if !name.Required || !name.Key || name.Deprecated || !name.HasID || name.ID != 0 {
+		t.Errorf("unexpected name field: %+v", name)
+	}
+	wantDefaults := []string{"", "2", "1", "", "-inf", "255"}
+	for i, want := range wantDefaults {
+		if fields[i].Default != want {
+			t.Errorf("field %s: expected default %q, got %q", fields[i].Name, want, fields[i].Default)
+		}
+		if fields[i].ID != i {
+			t.Errorf("field %s: expected id %d, got %d", fields[i].Name, i, fields[i].ID)
+		}
+	}
+	if !fields[3].Deprecated {
+		t.Error("expected old to be deprecated")
+	}
+
+	ratio := fields[4]
+	if len(ratio.Attributes) != 3 || ratio.Attributes[1].Name != "ui_hint" || ratio.Attributes[1].Value != "slider" || ratio.Attributes[2].Name != "hidden" {
+		t.Errorf("expected custom attributes to be kept, got %+v", ratio.Attributes)
+	}
+
+	var active []string
+	for _, f := range types["Test.Config"].ActiveFields() {
+		active = append(active, f.Name)
+	}
+	if got := strings.Join(active, " "); got != "name gain level ratio mask" {
+		t.Errorf("expected active fields without old, got %q", got)
+	}
+}
+
+func TestParseFBSFile_EnumDefaultAcrossNamespaces(t *testing.T) {
+	fbs := filepath.Join(t.TempDir(), "ns.fbs")
+	content := `namespace A;
+enum Mode : int { Off, On }
+namespace A.B;
+table T { mode: Mode = On; }
+`
+	os.WriteFile(fbs, []byte(content), 0644)
+
+	types, err := ParseFBSFile(fbs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := types["A.B.T"].Fields[0].Default; got != "1" {
+		t.Errorf("expected A.Mode.On to resolve from A.B, got default %q", got)
+	}
+}
+
+func TestNormalizeDefault(t *testing.T) {
+	tests := []struct {
+		fieldType string
+		lit       string
+		want      string
+	}{
+		{"bool", "true", "true"},
+		{"bool", "0", "false"},
+		{"int8", "-128", "-128"},
+		{"uint16", "0x10", "16"},
+		{"int64", "-9223372036854775808", "-9223372036854775808"},
+		{"uint64", "18446744073709551615", "18446744073709551615"},
+		{"float32", "1e3", "1000"},
+		{"float64", "+inf", "inf"},
+		{"float64", "nan", "nan"},
+		{"float32", ".5", "0.5"},
+	}
+	for _, tt := range tests {
+		got, err := normalizeDefault(tt.fieldType, tt.lit, nil)
+		if err != nil {
+			t.Errorf("%s %s: unexpected error: %v", tt.fieldType, tt.lit, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s %s: expected %q, got %q", tt.fieldType, tt.lit, tt.want, got)
+		}
+	}
+}
+
+func TestParseFBSFile_FieldErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"required scalar", "table T {\n  x: int (required);\n}", ":2:3: scalar field x cannot be required"},
+		{"required struct field", "struct S { x: int (required); }", ":1:20: struct field x cannot be required; struct fields are always present"},
+		{"deprecated struct field", "struct S { x: int (deprecated); }", ":1:20: struct field x cannot be deprecated"},
+		{"two keys", "table T { a: int (key); b: int (key); }", ":1:33: table T already has key field a"},
+		{"bad id", "table T { a: int (id: x); }", ":1:19: id of field a must be a non-negative integer, got \"x\""},
+		{"reused id", "table T { a: int (id: 0); b: int (id: 0); }", ":1:35: field b reuses id 0 of field a"},
+		{"missing id", "table T { a: int (id: 0); b: int; }", ":1:27: field b needs an id: either all fields of table T have one or none do"},
+		{"default on string", "table T { s: string = 1; }", ":1:11: field s of type string cannot have a default value; only scalar and enum fields can"},
+		{"default out of range", "table T { x: ubyte = 256; }", ":1:11: default value of field x: \"256\" is not a uint8"},
+		{"unknown enum default", "enum E : int { A }\ntable T { e: E = B; }", ":2:11: default value of field e: \"B\" is not a value of the enum"},
+		{"bad bool default", "table T { b: bool = yes; }", ":1:11: default value of field b: \"yes\" is not a bool"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fbs := filepath.Join(t.TempDir(), "bad.fbs")
+			os.WriteFile(fbs, []byte(tt.src), 0644)
+			_, err := ParseFBSFile(fbs)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.HasPrefix(err.Error(), fbs+tt.want) {
+				t.Errorf("expected error starting with %q, got %q", fbs+tt.want, err.Error())
+			}
+		})
+	}
+}
+
+func TestArrayField(t *testing.T) {
+	tests := []struct {
+		typ    string
+		elem   string
+		length int
+		ok     bool
+	}{
+		{"[float32:16]", "float32", 16, true},
+		{"[Geo.Vec3:4]", "Geo.Vec3", 4, true},
+		{"[float32]", "", 0, false},
+		{"float32", "", 0, false},
+		{"Geo.Vec3", "", 0, false},
+	}
+	for _, tt := range tests {
+		elem, length, ok := ArrayField(tt.typ)
+		if elem != tt.elem || length != tt.length || ok != tt.ok {
+			t.Errorf("ArrayField(%q) = %q, %d, %v; want %q, %d, %v", tt.typ, elem, length, ok, tt.elem, tt.length, tt.ok)
+		}
+	}
+}
+
+func TestLookupType(t *testing.T) {
+	types := ResolvedTypes{
+		"Color":     {Kind: TypeKindEnum},
+		"A.Color":   {Kind: TypeKindEnum},
+		"A.B.Shape": {Kind: TypeKindTable},
+	}
+	tests := []struct {
+		scope, name, want string
+	}{
+		{"A.B", "Color", "A.Color"},
+		{"A.B", "Shape", "A.B.Shape"},
+		{"", "Color", "Color"},
+		{"C", "Color", "Color"},
+		{"A", "B.Shape", "A.B.Shape"},
+	}
+	for _, tt := range tests {
+		_, got, ok := LookupType(types, tt.scope, tt.name)
+		if !ok || got != tt.want {
+			t.Errorf("LookupType(%q, %q): expected %s, got %q (found %v)", tt.scope, tt.name, tt.want, got, ok)
+		}
+	}
+	if _, _, ok := LookupType(types, "A", "Shape"); ok {
+		t.Error("did not expect Shape to resolve from the enclosing namespace A")
+	}
+}
+
+func TestParseFBSFile_QualifiesFieldTypes(t *testing.T) {
+	fbs := filepath.Join(t.TempDir(), "qualified.fbs")
+	content := `namespace Geo;
+struct Vec3 { x: float; y: float; z: float; }
+enum Axis : ubyte { X, Y, Z }
+
+namespace Geo.Mesh;
+struct Corners { points: [Vec3:4]; axis: Axis; }
+table Mesh { vertices: [Geo.Vec3]; bounds: Corners; extra: [float:2] (deprecated); }
+`
+	os.WriteFile(fbs, []byte(content), 0644)
+
+	types, err := ParseFBSFile(fbs)
+	if err == nil {
+		t.Fatal("expected an error for an array in a table")
+	}
+	os.WriteFile(fbs, []byte(strings.Replace(content, " extra: [float:2] (deprecated);", "", 1)), 0644)
+	if types, err = ParseFBSFile(fbs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, name := range []string{"Geo.Mesh.Corners", "Geo.Mesh.Mesh"} {
+		for _, f := range types[name].Fields {
+			got = append(got, f.Type)
+		}
+	}
+	want := "[Geo.Vec3:4] Geo.Axis [Geo.Vec3] Geo.Mesh.Corners"
+	if strings.Join(got, " ") != want {
+		t.Errorf("got field types %v, want %s", got, want)
+	}
+}
@@ -0,0 +1,120 @@
+package resolver
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// FBSSet is a set of parsed .fbs files together with every file they
+// include, transitively.
+type FBSSet struct {
+	Types    ResolvedTypes
+	Files    []string            // Absolute paths, each included file before its includers
+	Listed   []string            // Absolute paths of the listed files, in the order given
+	Includes map[string][]string // Absolute path → the absolute paths it includes
+}
+
+// LoadFBSFiles parses the listed .fbs files and follows their include
+// directives. Listed paths are resolved against searchDirs. An include is
+// resolved against the including file's directory, then includeDirs, then
+// searchDirs. Each file is parsed once however often it is reached, and
+// include cycles are errors.
+func LoadFBSFiles(searchDirs, includeDirs, fbsPaths []string) (*FBSSet, error) {
+	l := &fbsLoader{
+		searchDirs:  searchDirs,
+		includeDirs: includeDirs,
+		set:         &FBSSet{Types: make(ResolvedTypes), Includes: make(map[string][]string)},
+		state:       make(map[string]loadState),
+	}
+	for _, p := range fbsPaths {
+		fullPath, err := ResolveFBSPath(p, searchDirs)
+		if err != nil {
+			return nil, fmt.Errorf("resolving %s: %w", p, err)
+		}
+		if err := l.load(fullPath, p); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", p, err)
+		}
+		abs, err := filepath.Abs(fullPath)
+		if err != nil {
+			return nil, err
+		}
+		l.set.Listed = append(l.set.Listed, abs)
+	}
+	if err := resolveTypes(l.set.Types); err != nil {
+		return nil, err
+	}
+	return l.set, nil
+}
+
+type loadState int
+
+const (
+	loadUnseen loadState = iota
+	loadActive
+	loadDone
+)
+
+type fbsLoader struct {
+	searchDirs  []string
+	includeDirs []string
+	set         *FBSSet
+	state       map[string]loadState
+	stack       []string // Display names of the files being loaded
+}
+
+// load parses path after everything it includes. name is how the file was
+// referred to, for cycle reports.
+func (l *fbsLoader) load(path, name string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if l.state[abs] == loadDone {
+		return nil
+	}
+	l.state[abs] = loadActive
+	l.stack = append(l.stack, name)
+
+	schema, err := parseSchemaFile(abs)
+	if err != nil {
+		return err
+	}
+	for _, inc := range schema.Includes {
+		if inc.Native {
+			continue
+		}
+		dirs := append([]string{filepath.Dir(abs)}, l.includeDirs...)
+		dirs = append(dirs, l.searchDirs...)
+		incPath, err := ResolveFBSPath(inc.Path, dirs)
+		if err != nil {
+			return errorAt(inc.Pos, "include %q not found (searched %s)", inc.Path, strings.Join(dirs, ", "))
+		}
+		incAbs, err := filepath.Abs(incPath)
+		if err != nil {
+			return err
+		}
+		if l.state[incAbs] == loadActive {
+			return errorAt(inc.Pos, "include cycle: %s -> %s", strings.Join(l.stack, " -> "), inc.Path)
+		}
+		if err := l.load(incAbs, inc.Path); err != nil {
+			return err
+		}
+		l.set.Includes[abs] = append(l.set.Includes[abs], incAbs)
+	}
+
+	types, err := schemaTypes(schema)
+	if err != nil {
+		return err
+	}
+	for name, info := range types {
+		if existing, ok := l.set.Types[name]; ok {
+			return errorAt(info.Pos, "duplicate type %s (defined as %s at %s and %s)", name, existing.Kind, existing.Pos, info.Kind)
+		}
+		l.set.Types[name] = info
+	}
+	l.set.Files = append(l.set.Files, abs)
+	l.state[abs] = loadDone
+	l.stack = l.stack[:len(l.stack)-1]
+	return nil
+}
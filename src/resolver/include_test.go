@@ -0,0 +1,162 @@
+package resolver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFBS writes .fbs files under dir, keyed by relative path.
+func writeFBS(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadFBSFiles_TransitiveIncludes(t *testing.T) {
+	tmp := t.TempDir()
+	writeFBS(t, tmp, map[string]string{
+		"app.fbs":         "include \"sub/mid.fbs\";\nnamespace App;\ntable Scene { root: Mid.Node; }\n",
+		"sub/mid.fbs":     "include \"base.fbs\";\nnamespace Mid;\ntable Node { id: Base.Id; }\n",
+		"sub/base.fbs":    "namespace Base;\nstruct Id { value: uint64; }\n",
+		"other/extra.fbs": "include \"sub/base.fbs\";\nnamespace Extra;\ntable Tag { id: Base.Id; }\n",
+	})
+
+	// other/extra.fbs reaches base.fbs through the search dir; base.fbs
+	// is reached twice and parsed once.
+	set, err := LoadFBSFiles([]string{tmp}, nil, []string{"app.fbs", "other/extra.fbs"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"App.Scene", "Mid.Node", "Base.Id", "Extra.Tag"} {
+		if _, ok := set.Types[name]; !ok {
+			t.Errorf("expected type %s", name)
+		}
+	}
+
+	var rel []string
+	for _, f := range set.Files {
+		r, _ := filepath.Rel(tmp, f)
+		rel = append(rel, filepath.ToSlash(r))
+	}
+	want := "sub/base.fbs sub/mid.fbs app.fbs other/extra.fbs"
+	if got := strings.Join(rel, " "); got != want {
+		t.Errorf("expected files %q, got %q", want, got)
+	}
+
+	if len(set.Listed) != 2 || set.Listed[0] != filepath.Join(tmp, "app.fbs") || set.Listed[1] != filepath.Join(tmp, "other", "extra.fbs") {
+		t.Errorf("expected listed app.fbs and other/extra.fbs, got %v", set.Listed)
+	}
+	if got := set.Includes[filepath.Join(tmp, "sub", "mid.fbs")]; len(got) != 1 || got[0] != filepath.Join(tmp, "sub", "base.fbs") {
+		t.Errorf("expected sub/mid.fbs to include sub/base.fbs, got %v", got)
+	}
+}
+
+func TestLoadFBSFiles_IncludeDirs(t *testing.T) {
+	tmp := t.TempDir()
+	shared := t.TempDir()
+	writeFBS(t, tmp, map[string]string{
+		"app.fbs": "include \"common/types.fbs\";\nnamespace App;\ntable T { c: Common.Color; }\n",
+	})
+	writeFBS(t, shared, map[string]string{
+		"common/types.fbs": "native_include \"color.h\";\nnamespace Common;\nenum Color : byte { Red }\n",
+	})
+
+	if _, err := LoadFBSFiles([]string{tmp}, nil, []string{"app.fbs"}); err == nil {
+		t.Fatal("expected error without include dir")
+	} else if !strings.Contains(err.Error(), "app.fbs:1:1: include \"common/types.fbs\" not found") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	set, err := LoadFBSFiles([]string{tmp}, []string{shared}, []string{"app.fbs"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := set.Types["Common.Color"]; !ok {
+		t.Error("expected Common.Color from include dir")
+	}
+	if len(set.Files) != 2 {
+		t.Errorf("expected 2 files, got %v", set.Files)
+	}
+}
+
+func TestLoadFBSFiles_ListedAndIncluded(t *testing.T) {
+	tmp := t.TempDir()
+	writeFBS(t, tmp, map[string]string{
+		"a.fbs": "include \"b.fbs\";\nnamespace A;\ntable T { x: int; }\n",
+		"b.fbs": "namespace B;\ntable U { y: int; }\n",
+	})
+
+	types, err := ParseFBSFiles([]string{tmp}, []string{"b.fbs", "a.fbs"})
+	if err != nil {
+		t.Fatalf("expected no duplicate type error, got: %v", err)
+	}
+	if len(types) != 2 {
+		t.Errorf("expected 2 types, got %d", len(types))
+	}
+}
+
+func TestLoadFBSFiles_Cycle(t *testing.T) {
+	tmp := t.TempDir()
+	writeFBS(t, tmp, map[string]string{
+		"a.fbs": "include \"b.fbs\";\nnamespace A;\n",
+		"b.fbs": "include \"c.fbs\";\nnamespace B;\n",
+		"c.fbs": "include \"a.fbs\";\nnamespace C;\n",
+	})
+
+	_, err := LoadFBSFiles([]string{tmp}, nil, []string{"a.fbs"})
+	if err == nil {
+		t.Fatal("expected include cycle error")
+	}
+	want := filepath.Join(tmp, "c.fbs") + ":1:1: include cycle: a.fbs -> b.fbs -> c.fbs -> a.fbs"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error containing %q, got %q", want, err.Error())
+	}
+}
+
+func TestLoadFBSFiles_DuplicateAcrossFiles(t *testing.T) {
+	tmp := t.TempDir()
+	writeFBS(t, tmp, map[string]string{
+		"a.fbs": "include \"b.fbs\";\nnamespace N;\ntable T { x: int; }\n",
+		"b.fbs": "namespace N;\nenum T : int { A }\n",
+	})
+
+	_, err := LoadFBSFiles([]string{tmp}, nil, []string{"a.fbs"})
+	if err == nil {
+		t.Fatal("expected duplicate type error")
+	}
+	want := "duplicate type N.T (defined as enum at " + filepath.Join(tmp, "b.fbs") + ":2:6 and table)"
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error containing %q, got %q", want, err.Error())
+	}
+}
+
+func TestLoadFBSFiles_ErrorPosition(t *testing.T) {
+	// Diagnostics locate a schema error from the ParseError under the
+	// "parsing <file>" wrapping.
+	tmp := t.TempDir()
+	writeFBS(t, tmp, map[string]string{
+		"a.fbs": "include \"b.fbs\";\nnamespace A;\n",
+		"b.fbs": "namespace B;\ntable T { x: int = ; }\n",
+	})
+
+	_, err := LoadFBSFiles([]string{tmp}, nil, []string{"a.fbs"})
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected a *ParseError, got %v", err)
+	}
+	want := Pos{File: filepath.Join(tmp, "b.fbs"), Line: 2, Col: 20}
+	if perr.Pos != want || perr.Msg != `expected default value, found ";"` {
+		t.Errorf("got %s: %s, want %s", perr.Pos, perr.Msg, want)
+	}
+}
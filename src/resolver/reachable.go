@@ -0,0 +1,40 @@
+package resolver
+
+import "strings"
+
+// Reachable returns the types in roots together with every type they reach
+// through their fields, the element types of vectors and arrays, and union
+// members. Roots that name no type, such as primitives, are ignored.
+func Reachable(types ResolvedTypes, roots []string) ResolvedTypes {
+	reached := make(ResolvedTypes)
+	var visit func(name string)
+	visit = func(name string) {
+		info, ok := types[name]
+		if !ok {
+			return
+		}
+		if _, seen := reached[name]; seen {
+			return
+		}
+		reached[name] = info
+		for _, f := range info.Fields {
+			visit(FieldTypeName(f.Type))
+		}
+		for _, m := range info.Members {
+			visit(m.Type)
+		}
+	}
+	for _, name := range roots {
+		visit(name)
+	}
+	return reached
+}
+
+// FieldTypeName strips the vector or array brackets from a field type,
+// leaving the element type, e.g. "[Geo.Point:4]" → "Geo.Point".
+func FieldTypeName(t string) string {
+	if elem, _, ok := ArrayField(t); ok {
+		return elem
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(t, "["), "]")
+}
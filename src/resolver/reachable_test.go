@@ -0,0 +1,43 @@
+package resolver
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestReachable(t *testing.T) {
+	tmp := t.TempDir()
+	writeFBS(t, tmp, map[string]string{
+		"shapes.fbs": `namespace Shapes;
+enum Color : byte { Red, Green }
+enum Unused : byte { A }
+struct Point { x: float; y: float; }
+struct Quad { corners: [Point:4]; }
+table Circle { center: Point; color: Color; }
+table Label { text: string; }
+union Shape { Circle, Label }
+table Scene { shapes: [Shape]; quads: [Quad]; }
+table Orphan { scene: Scene; }
+`,
+	})
+	types, err := ParseFBSFiles([]string{tmp}, []string{"shapes.fbs"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reached := Reachable(types, []string{"Shapes.Scene", "int32", "Missing.Type"})
+	var names []string
+	for name := range reached {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	want := "Shapes.Circle Shapes.Color Shapes.Label Shapes.Point Shapes.Quad Shapes.Scene Shapes.Shape"
+	if got := strings.Join(names, " "); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	if len(Reachable(types, nil)) != 0 {
+		t.Error("expected no types reachable from no roots")
+	}
+}
@@ -1,13 +1,11 @@
 package resolver
 
 import (
-	"bufio"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strconv"
-	"strings"
 )
 
 // TypeKind represents the kind of a FlatBuffers type definition.
@@ -41,33 +39,55 @@ type EnumValue struct {
 	Value int64
 }
 
+// UnionMember is one member of a FlatBuffers union: the table or struct it
+// carries and the type tag that identifies it.
+type UnionMember struct {
+	Name  string // Member name: the alias, or the type name with dots as underscores
+	Type  string // Fully-qualified member type
+	Value int64  // Type tag; 0 is the implicit NONE
+	Pos   Pos
+}
+
 // FieldDef represents a single field in a FlatBuffers table or struct.
 type FieldDef struct {
-	Name string
-	Type string // FBS field type: "string", "int32", "float", "[TouchEvent]", etc.
+	Name       string
+	Type       string // FBS field type: "string", "int32", "float32", "[TouchEvent]", "[float32:16]", etc.
+	Default    string // Normalized default value (see normalizeDefault), "" if none
+	ID         int    // Explicit (id: n), if HasID
+	HasID      bool
+	Deprecated bool        // (deprecated): kept in the wire format, left out of generated types
+	Required   bool        // (required): a table field that must be present
+	Key        bool        // (key): the field vectors of the table are sorted by
+	Attributes []Attribute // All metadata as written, including the attributes above
+	Pos        Pos
 }
 
 // TypeInfo holds full information about a FlatBuffers type definition.
 type TypeInfo struct {
 	Kind       TypeKind
-	BaseType   string      // Enums: underlying type (e.g., "int32")
-	EnumValues []EnumValue // Enums only
-	Fields     []FieldDef  // Tables/structs only
+	BaseType   string        // Enums: underlying type (e.g., "int32")
+	BitFlags   bool          // Enums declared (bit_flags): each value is a single bit
+	EnumValues []EnumValue   // Enums, and union members with their type tags
+	Fields     []FieldDef    // Tables/structs only
+	Members    []UnionMember // Unions only, in declaration order
+	Pos        Pos           // Where the type is declared
+}
+
+// ActiveFields returns the fields that are not deprecated, which are the ones
+// generated C structs and bindings carry.
+func (t *TypeInfo) ActiveFields() []FieldDef {
+	var fields []FieldDef
+	for _, f := range t.Fields {
+		if !f.Deprecated {
+			fields = append(fields, f)
+		}
+	}
+	return fields
 }
 
 // ResolvedTypes maps fully-qualified FlatBuffers type names to their type info.
 type ResolvedTypes map[string]*TypeInfo
 
-var (
-	namespacePattern = regexp.MustCompile(`^\s*namespace\s+([A-Za-z][A-Za-z0-9_.]*)\s*;`)
-	enumPattern      = regexp.MustCompile(`^\s*enum\s+([A-Z][a-zA-Z0-9]*)\s*:\s*(\w+)`)
-	tablePattern     = regexp.MustCompile(`^\s*table\s+([A-Z][a-zA-Z0-9]*)\s*`)
-	structPattern    = regexp.MustCompile(`^\s*struct\s+([A-Z][a-zA-Z0-9]*)\s*`)
-	unionPattern     = regexp.MustCompile(`^\s*union\s+([A-Z][a-zA-Z0-9]*)\s*`)
-	enumValuePattern = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_]*)\s*(?:=\s*(-?\d+))?\s*,?\s*$`)
-	fieldPattern     = regexp.MustCompile(`([a-z_][a-zA-Z0-9_]*)\s*:\s*(\S+?)\s*;`)
-)
-
 // fbsTypeAlias normalizes FBS type aliases to their canonical form.
 func fbsTypeAlias(t string) string {
 	switch t {
@@ -114,145 +134,148 @@ func ResolveFBSPath(relPath string, searchDirs []string) (string, error) {
 	return "", fmt.Errorf("%s not found in search directories: %v", relPath, searchDirs)
 }
 
-// ParseFBSFiles parses multiple .fbs files and returns all resolved types.
-// Relative paths are resolved by searching directories in order.
+// ParseFBSFiles parses multiple .fbs files, and the files they include, and
+// returns all resolved types. Relative paths are resolved by searching
+// directories in order.
 func ParseFBSFiles(searchDirs []string, fbsPaths []string) (ResolvedTypes, error) {
-	types := make(ResolvedTypes)
-	for _, p := range fbsPaths {
-		fullPath, err := ResolveFBSPath(p, searchDirs)
-		if err != nil {
-			return nil, fmt.Errorf("resolving %s: %w", p, err)
-		}
-		fileTypes, err := ParseFBSFile(fullPath)
-		if err != nil {
-			return nil, fmt.Errorf("parsing %s: %w", p, err)
-		}
-		for name, info := range fileTypes {
-			if existing, ok := types[name]; ok {
-				return nil, fmt.Errorf("duplicate type %s (defined as %s and %s)", name, existing.Kind, info.Kind)
-			}
-			types[name] = info
-		}
+	set, err := LoadFBSFiles(searchDirs, nil, fbsPaths)
+	if err != nil {
+		return nil, err
 	}
-	return types, nil
+	return set.Types, nil
 }
 
 // ParseFBSFile parses a single .fbs file and extracts type definitions
-// including enum values and table/struct fields.
+// including enum values and table/struct fields. Malformed or inconsistent
+// input is reported as a *ParseError with the file, line and column.
 func ParseFBSFile(path string) (ResolvedTypes, error) {
-	f, err := os.Open(path)
+	schema, err := parseSchemaFile(path)
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
-
-	types := make(ResolvedTypes)
-	namespace := ""
-
-	// State for tracking type bodies
-	var currentType *TypeInfo
-	var currentName string
-	var nextEnumValue int64
-	braceDepth := 0
+	types, err := schemaTypes(schema)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveTypes(types); err != nil {
+		return nil, err
+	}
+	return types, nil
+}
 
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		line := scanner.Text()
+func parseSchemaFile(path string) (*Schema, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFBS(path, src)
+}
 
-		// Strip comments
-		if idx := strings.Index(line, "//"); idx >= 0 {
-			line = line[:idx]
+// schemaTypes converts the type declarations of a parsed schema into
+// ResolvedTypes, checking the rules flatc enforces within a single file.
+func schemaTypes(schema *Schema) (ResolvedTypes, error) {
+	types := make(ResolvedTypes)
+	for _, decl := range schema.Types {
+		name := decl.QualifiedName()
+		if existing, ok := types[name]; ok {
+			return nil, errorAt(decl.Pos, "duplicate type %s (first defined at %s)", name, existing.Pos)
 		}
+		info := &TypeInfo{Kind: decl.Kind, Pos: decl.Pos}
+		var err error
+		switch decl.Kind {
+		case TypeKindEnum:
+			info.BaseType = fbsTypeAlias(decl.BaseType)
+			info.BitFlags = hasAttribute(decl.Attributes, "bit_flags")
+			info.EnumValues, err = enumValues(decl, info.BaseType, info.BitFlags)
+		case TypeKindUnion:
+			if hasAttribute(decl.Attributes, "bit_flags") {
+				err = errorAt(decl.Pos, "union %s cannot be bit_flags", decl.Name)
+				break
+			}
+			info.EnumValues, err = enumValues(decl, "uint8", false)
+			if err == nil {
+				info.Members = unionMembers(decl, info.EnumValues)
+			}
+		default:
+			info.Fields, err = typeFields(decl)
+		}
+		if err != nil {
+			return nil, err
+		}
+		types[name] = info
+	}
+	return types, nil
+}
 
-		// Track brace depth for type bodies
-		openBraces := strings.Count(line, "{")
-		closeBraces := strings.Count(line, "}")
+// enumIntRanges holds the value range of each integer type an enum can be
+// based on.
+var enumIntRanges = map[string][2]int64{
+	"int8":   {math.MinInt8, math.MaxInt8},
+	"uint8":  {0, math.MaxUint8},
+	"int16":  {math.MinInt16, math.MaxInt16},
+	"uint16": {0, math.MaxUint16},
+	"int32":  {math.MinInt32, math.MaxInt32},
+	"uint32": {0, math.MaxUint32},
+	"int64":  {math.MinInt64, math.MaxInt64},
+	"uint64": {0, math.MaxInt64},
+}
 
-		if m := namespacePattern.FindStringSubmatch(line); m != nil {
-			namespace = m[1]
-			continue
+// enumValues computes the values of an enum, or of a union's type tag.
+// Implicit values continue from the previous one; enums start at 0 and
+// unions at 1, since 0 is the implicit NONE member. In a bit_flags enum the
+// declared values are bit positions, and each value is 1 shifted by its
+// position.
+func enumValues(decl *TypeDecl, baseType string, bitFlags bool) ([]EnumValue, error) {
+	bounds, ok := enumIntRanges[baseType]
+	if !ok {
+		return nil, errorAt(decl.Pos, "enum %s underlying type %q must be an integer type", decl.Name, decl.BaseType)
+	}
+	next := int64(0)
+	if decl.Kind == TypeKindUnion {
+		next = 1
+	}
+	seen := make(map[string]bool)
+	var values []EnumValue
+	for i, v := range decl.Values {
+		if seen[v.Name] {
+			return nil, errorAt(v.Pos, "duplicate value %s in %s %s", v.Name, decl.Kind, decl.Name)
 		}
-
-		// Only parse type headers when not inside a type body
-		if currentType == nil {
-			if m := enumPattern.FindStringSubmatch(line); m != nil {
-				currentName = qualifiedName(namespace, m[1])
-				currentType = &TypeInfo{
-					Kind:     TypeKindEnum,
-					BaseType: fbsTypeAlias(m[2]),
-				}
-				nextEnumValue = 0
-				types[currentName] = currentType
-				braceDepth = openBraces - closeBraces
-				if braceDepth <= 0 {
-					currentType = nil
-				}
-				continue
-			} else if m := tablePattern.FindStringSubmatch(line); m != nil {
-				currentName = qualifiedName(namespace, m[1])
-				currentType = &TypeInfo{Kind: TypeKindTable}
-				types[currentName] = currentType
-				braceDepth = openBraces - closeBraces
-				if braceDepth <= 0 {
-					currentType = nil
-				}
-				continue
-			} else if m := structPattern.FindStringSubmatch(line); m != nil {
-				currentName = qualifiedName(namespace, m[1])
-				currentType = &TypeInfo{Kind: TypeKindStruct}
-				types[currentName] = currentType
-				braceDepth = openBraces - closeBraces
-				if braceDepth <= 0 {
-					currentType = nil
-				}
-				continue
-			} else if m := unionPattern.FindStringSubmatch(line); m != nil {
-				currentName = qualifiedName(namespace, m[1])
-				currentType = &TypeInfo{Kind: TypeKindUnion}
-				types[currentName] = currentType
-				braceDepth = openBraces - closeBraces
-				if braceDepth <= 0 {
-					currentType = nil
-				}
-				continue
+		seen[v.Name] = true
+		position := next
+		if v.Value != "" {
+			parsed, err := strconv.ParseInt(v.Value, 0, 64)
+			if err != nil {
+				return nil, errorAt(v.Pos, "value %s of %s is out of range", v.Value, v.Name)
 			}
-		} else {
-			// Inside a type body — parse fields/values
-			braceDepth += openBraces - closeBraces
-			if braceDepth <= 0 {
-				currentType = nil
-				continue
+			if i > 0 && parsed < next {
+				return nil, errorAt(v.Pos, "value %s of %s must be greater than the previous value %d", v.Value, v.Name, next-1)
 			}
-
-			switch currentType.Kind {
-			case TypeKindEnum:
-				if m := enumValuePattern.FindStringSubmatch(line); m != nil {
-					if m[2] != "" {
-						val, _ := strconv.ParseInt(m[2], 10, 64)
-						nextEnumValue = val
-					}
-					currentType.EnumValues = append(currentType.EnumValues, EnumValue{
-						Name:  m[1],
-						Value: nextEnumValue,
-					})
-					nextEnumValue++
-				}
-			case TypeKindTable, TypeKindStruct:
-				for _, m := range fieldPattern.FindAllStringSubmatch(line, -1) {
-					currentType.Fields = append(currentType.Fields, FieldDef{
-						Name: m[1],
-						Type: fbsTypeAlias(m[2]),
-					})
-				}
+			position = parsed
+		}
+		value := position
+		if bitFlags {
+			if position < 0 || position > 62 || int64(1)<<position > bounds[1] {
+				return nil, errorAt(v.Pos, "bit %d of %s does not fit in %s", position, v.Name, baseType)
 			}
+			value = int64(1) << position
+		}
+		if value < bounds[0] || value > bounds[1] {
+			return nil, errorAt(v.Pos, "value %d of %s does not fit in %s", value, v.Name, baseType)
 		}
+		values = append(values, EnumValue{Name: v.Name, Value: value})
+		next = position + 1
 	}
+	return values, nil
+}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("reading file: %w", err)
+// hasAttribute reports whether metadata includes the named attribute.
+func hasAttribute(attrs []Attribute, name string) bool {
+	for _, attr := range attrs {
+		if attr.Name == name {
+			return true
+		}
 	}
-
-	return types, nil
+	return false
 }
 
 func qualifiedName(namespace, name string) string {
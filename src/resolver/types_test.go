@@ -3,6 +3,7 @@ package resolver
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -173,3 +174,142 @@ table Real { y: int32; }
 		t.Error("did not expect Test.NotReal (commented out)")
 	}
 }
+
+func TestParseFBSFile_Syntax(t *testing.T) {
+	tmp := t.TempDir()
+	fbs := filepath.Join(tmp, "syntax.fbs")
+	content := `namespace Test;
+
+/* Block comments
+   table Hidden { x: int32; } */
+enum Flags : ushort { A = 0x10, B, C = 0x40 }
+
+union Shape { Circle, Square }
+table Circle { radius: float; }
+struct Square { side: float; }
+
+table Config {
+  width: uint32 = 640;
+  height: uint32 = 480 (priority: 1);
+  scale: float =
+    1.5;
+  data: [ubyte] (required);
+  name: string; label: string;
+}
+
+struct Mat4 { m: [float:16]; }
+`
+	os.WriteFile(fbs, []byte(content), 0644)
+
+	types, err := ParseFBSFile(fbs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := types["Test.Hidden"]; ok {
+		t.Error("did not expect Test.Hidden (commented out)")
+	}
+
+	flags := types["Test.Flags"]
+	if flags.BaseType != "uint16" {
+		t.Errorf("expected Flags base type uint16, got %s", flags.BaseType)
+	}
+	wantValues := []EnumValue{{"A", 0x10}, {"B", 0x11}, {"C", 0x40}}
+	if len(flags.EnumValues) != len(wantValues) {
+		t.Fatalf("expected %d Flags values, got %v", len(wantValues), flags.EnumValues)
+	}
+	for i, want := range wantValues {
+		if flags.EnumValues[i] != want {
+			t.Errorf("Flags value %d: expected %v, got %v", i, want, flags.EnumValues[i])
+		}
+	}
+	if flags.Pos.Line != 5 || flags.Pos.Col != 6 {
+		t.Errorf("expected Flags at line 5 col 6, got %s", flags.Pos)
+	}
+
+	shape := types["Test.Shape"]
+	if shape.Kind != TypeKindUnion || len(shape.EnumValues) != 2 || shape.EnumValues[0].Value != 1 || shape.EnumValues[1].Value != 2 {
+		t.Errorf("unexpected union members: %+v", shape)
+	}
+
+	config := types["Test.Config"]
+	wantFields := []FieldDef{
+		{Name: "width", Type: "uint32"}, {Name: "height", Type: "uint32"}, {Name: "scale", Type: "float32"},
+		{Name: "data", Type: "[uint8]"}, {Name: "name", Type: "string"}, {Name: "label", Type: "string"},
+	}
+	if len(config.Fields) != len(wantFields) {
+		t.Fatalf("expected %d Config fields, got %v", len(wantFields), config.Fields)
+	}
+	for i, want := range wantFields {
+		if got := config.Fields[i]; got.Name != want.Name || got.Type != want.Type {
+			t.Errorf("Config field %d: expected %s %s, got %s %s", i, want.Name, want.Type, got.Name, got.Type)
+		}
+	}
+
+	mat := types["Test.Mat4"]
+	if len(mat.Fields) != 1 || mat.Fields[0].Type != "[float32:16]" {
+		t.Errorf("unexpected Mat4 fields: %v", mat.Fields)
+	}
+}
+
+func TestParseFBSFile_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"syntax error", "table T {\n  x: int32 = ;\n}", ":2:14: expected default value, found \";\""},
+		{"duplicate type", "table T { x: int; }\nstruct T { y: int; }", ":2:8: duplicate type T (first defined at "},
+		{"duplicate field", "table T {\n  x: int;\n  x: float;\n}", ":3:3: duplicate field x in table T"},
+		{"non-integer enum", "enum E : float { A }", ":1:6: enum E underlying type \"float\" must be an integer type"},
+		{"descending enum", "enum E : int { A = 2, B = 1 }", ":1:23: value 1 of B must be greater than the previous value 2"},
+		{"enum overflow", "enum E : ubyte { A = 255, B }", ":1:27: value 256 of B does not fit in uint8"},
+		{"duplicate enum value", "enum E : int { A, A }", ":1:19: duplicate value A in enum E"},
+		{"string in struct", "struct S { s: string; }", ":1:12: struct field s cannot be a vector or string"},
+		{"struct default", "struct S { x: int = 1; }", ":1:12: struct field x cannot have a default value"},
+		{"array in table", "table T { m: [float:4]; }", ":1:11: fixed-length array field m is only allowed in structs"},
+		{"bit out of range", "enum E : ubyte (bit_flags) { A, B = 8 }", ":1:33: bit 8 of B does not fit in uint8"},
+		{"signed bit out of range", "enum E : byte (bit_flags) { A = 7 }", ":1:29: bit 7 of A does not fit in int8"},
+		{"bit_flags union", "table A {}\nunion U (bit_flags) { A }", ":2:7: union U cannot be bit_flags"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fbs := filepath.Join(t.TempDir(), "bad.fbs")
+			os.WriteFile(fbs, []byte(tt.src), 0644)
+			_, err := ParseFBSFile(fbs)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.HasPrefix(err.Error(), fbs+tt.want) {
+				t.Errorf("expected error starting with %q, got %q", fbs+tt.want, err.Error())
+			}
+		})
+	}
+}
+
+func TestParseFBSFile_BitFlags(t *testing.T) {
+	fbs := filepath.Join(t.TempDir(), "flags.fbs")
+	content := `namespace Test;
+enum Caps : uint32 (bit_flags) { Read, Write, Exec = 4, Big = 31 }
+enum Plain : uint32 { A, B, C }
+`
+	os.WriteFile(fbs, []byte(content), 0644)
+
+	types, err := ParseFBSFile(fbs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	caps := types["Test.Caps"]
+	if !caps.BitFlags {
+		t.Error("expected Caps to be bit_flags")
+	}
+	want := []EnumValue{{"Read", 1}, {"Write", 2}, {"Exec", 16}, {"Big", 1 << 31}}
+	for i, w := range want {
+		if caps.EnumValues[i] != w {
+			t.Errorf("Caps value %d: expected %v, got %v", i, w, caps.EnumValues[i])
+		}
+	}
+	if plain := types["Test.Plain"]; plain.BitFlags || plain.EnumValues[2].Value != 2 {
+		t.Errorf("expected Plain to keep sequential values, got %+v", plain)
+	}
+}
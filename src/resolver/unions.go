@@ -0,0 +1,54 @@
+package resolver
+
+import (
+	"sort"
+	"strings"
+)
+
+// unionMembers pairs the members of a union declaration with their type tags.
+// Member types are resolved later by resolveUnions, once every type is known.
+func unionMembers(decl *TypeDecl, values []EnumValue) []UnionMember {
+	members := make([]UnionMember, len(decl.Values))
+	for i, v := range decl.Values {
+		name := strings.ReplaceAll(v.Name, ".", "_")
+		values[i].Name = name
+		members[i] = UnionMember{Name: name, Type: v.Type, Value: values[i].Value, Pos: v.Pos}
+	}
+	return members
+}
+
+// resolveTypes runs the checks that need every type of a schema set.
+func resolveTypes(types ResolvedTypes) error {
+	if err := resolveUnions(types); err != nil {
+		return err
+	}
+	return resolveFields(types)
+}
+
+// resolveUnions qualifies the member types of each union and checks that
+// they are tables or structs, the types a union can carry across the ABI.
+func resolveUnions(types ResolvedTypes) error {
+	names := make([]string, 0, len(types))
+	for name, info := range types {
+		if info.Kind == TypeKindUnion {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		info := types[name]
+		scope := namespaceOf(name)
+		for i := range info.Members {
+			m := &info.Members[i]
+			target, qualified, ok := LookupType(types, scope, m.Type)
+			if !ok {
+				return errorAt(m.Pos, "union %s member %s: unknown type %s", name, m.Name, m.Type)
+			}
+			if target.Kind != TypeKindTable && target.Kind != TypeKindStruct {
+				return errorAt(m.Pos, "union %s member %s: %s %s cannot be a union member; only tables and structs can", name, m.Name, target.Kind, qualified)
+			}
+			m.Type = qualified
+		}
+	}
+	return nil
+}
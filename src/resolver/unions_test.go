@@ -0,0 +1,69 @@
+package resolver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseFBSFile_UnionMembers(t *testing.T) {
+	fbs := filepath.Join(t.TempDir(), "unions.fbs")
+	content := `namespace Geo;
+table Circle { radius: float; }
+struct Point { x: float; y: float; }
+
+namespace Scene;
+table Rect { w: float; h: float; }
+union Shape { Geo.Circle, Rect = 4, Pin: Geo.Point }
+`
+	os.WriteFile(fbs, []byte(content), 0644)
+
+	types, err := ParseFBSFile(fbs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	shape := types["Scene.Shape"]
+	want := []UnionMember{
+		{Name: "Geo_Circle", Type: "Geo.Circle", Value: 1},
+		{Name: "Rect", Type: "Scene.Rect", Value: 4},
+		{Name: "Pin", Type: "Geo.Point", Value: 5},
+	}
+	if len(shape.Members) != len(want) {
+		t.Fatalf("expected %d members, got %+v", len(want), shape.Members)
+	}
+	for i, w := range want {
+		got := shape.Members[i]
+		if got.Name != w.Name || got.Type != w.Type || got.Value != w.Value {
+			t.Errorf("member %d: expected %+v, got %+v", i, w, got)
+		}
+		if shape.EnumValues[i].Name != w.Name || shape.EnumValues[i].Value != w.Value {
+			t.Errorf("type tag %d: expected %s = %d, got %+v", i, w.Name, w.Value, shape.EnumValues[i])
+		}
+	}
+}
+
+func TestParseFBSFile_UnionErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"unknown member", "union U { Missing }", ":1:11: union U member Missing: unknown type Missing"},
+		{"enum member", "enum E : byte { A }\nunion U { E }", ":2:11: union U member E: enum E cannot be a union member; only tables and structs can"},
+		{"union member", "table A {}\nunion V { A }\nunion U { V }", ":3:11: union U member V: union V cannot be a union member; only tables and structs can"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fbs := filepath.Join(t.TempDir(), "bad.fbs")
+			os.WriteFile(fbs, []byte(tt.src), 0644)
+			_, err := ParseFBSFile(fbs)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.HasPrefix(err.Error(), fbs+tt.want) {
+				t.Errorf("expected error starting with %q, got %q", fbs+tt.want, err.Error())
+			}
+		})
+	}
+}
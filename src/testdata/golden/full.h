@@ -34,69 +34,12 @@ typedef enum {
     Common_ErrorCode_InternalError = 4
 } Common_ErrorCode;
 
-typedef enum {
-    Common_LogLevel_Debug = 0,
-    Common_LogLevel_Info = 1,
-    Common_LogLevel_Warn = 2,
-    Common_LogLevel_Error = 3
-} Common_LogLevel;
-
 typedef enum {
     Rendering_TextureFormat_RGBA8 = 0,
     Rendering_TextureFormat_RGB8 = 1,
     Rendering_TextureFormat_R8 = 2
 } Rendering_TextureFormat;
 
-typedef struct Geometry_Transform3D {
-    float m00;
-    float m01;
-    float m02;
-    float m03;
-    float m10;
-    float m11;
-    float m12;
-    float m13;
-    float m20;
-    float m21;
-    float m22;
-    float m23;
-    float m30;
-    float m31;
-    float m32;
-    float m33;
-} Geometry_Transform3D;
-
-typedef struct Common_EntityId {
-    uint64_t id;
-} Common_EntityId;
-
-typedef struct Common_EventQueue {
-    uint32_t capacity;
-} Common_EventQueue;
-
-typedef struct Input_TouchEvent {
-    int32_t pointer_id;
-    float x;
-    float y;
-    float pressure;
-    uint64_t timestamp_ns;
-} Input_TouchEvent;
-
-typedef struct Input_TouchEventBatch {
-    const TouchEvent* events;
-    uint32_t events_count;
-} Input_TouchEventBatch;
-
-typedef struct Rendering_RendererConfig {
-    uint32_t width;
-    uint32_t height;
-    bool vsync;
-} Rendering_RendererConfig;
-
-typedef struct Scene_EntityDefinition {
-    const char* name;
-} Scene_EntityDefinition;
-
 /* Platform services — implement these per platform */
 void example_app_engine_log_sink(int32_t level, const char* tag, const char* message);
 uint32_t example_app_engine_resource_count(void);
@@ -105,6 +48,17 @@ int32_t  example_app_engine_resource_exists(const char* name);
 uint32_t example_app_engine_resource_size(const char* name);
 int32_t  example_app_engine_resource_read(const char* name, uint8_t* buffer, uint32_t buffer_size);
 
+/* ABI handshake — returns "<version> <hash>": the API version and a hash of
+ * the C ABI this header declares. Bindings compare the hash with their own
+ * when they load the library. The string is static; do not free it. */
+EXAMPLE_APP_ENGINE_EXPORT const char* example_app_engine_abi_info(void);
+
+/* Returned buffers — buffer<T> results are passed out as a data pointer and
+ * an element count, and FlatBuffer tables as a finished buffer and its size in
+ * bytes. The data is allocated by the implementation and owned by the caller,
+ * who releases it with example_app_engine_buffer_free. An empty buffer may be NULL. */
+EXAMPLE_APP_ENGINE_EXPORT void example_app_engine_buffer_free(void* data);
+
 /* lifecycle */
 EXAMPLE_APP_ENGINE_EXPORT int32_t example_app_engine_lifecycle_create_engine(
     engine_handle* out_result);
@@ -114,7 +68,8 @@ EXAMPLE_APP_ENGINE_EXPORT void example_app_engine_lifecycle_destroy_engine(
 /* renderer */
 EXAMPLE_APP_ENGINE_EXPORT int32_t example_app_engine_renderer_create_renderer(
     engine_handle engine,
-    const Rendering_RendererConfig* config,
+    const uint8_t* config,
+    uint32_t config_len,
     renderer_handle* out_result);
 EXAMPLE_APP_ENGINE_EXPORT void example_app_engine_renderer_destroy_renderer(
     renderer_handle renderer);
@@ -140,12 +95,14 @@ EXAMPLE_APP_ENGINE_EXPORT void example_app_engine_texture_destroy_texture(
 /* input */
 EXAMPLE_APP_ENGINE_EXPORT int32_t example_app_engine_input_push_touch_events(
     engine_handle engine,
-    const Input_TouchEventBatch* events);
+    const uint8_t* events,
+    uint32_t events_len);
 
 /* events */
 EXAMPLE_APP_ENGINE_EXPORT int32_t example_app_engine_events_poll_events(
     engine_handle engine,
-    Common_EventQueue* events);
+    uint8_t** out_result,
+    uint32_t* out_result_len);
 
 #ifdef __cplusplus
 }
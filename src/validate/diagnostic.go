@@ -0,0 +1,59 @@
+package validate
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Diagnostic is an error or warning in the form validate and generate write
+// with --format json, for editors and hooks to parse.
+type Diagnostic struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"` // "error" or "warning"
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Message  string `json:"message"`
+	Fix      string `json:"fix,omitempty"`
+}
+
+// Diagnostic converts the error to a diagnostic of severity.
+func (e *ValidationError) Diagnostic(severity string) Diagnostic {
+	return Diagnostic{
+		Rule:     e.Rule,
+		Severity: severity,
+		File:     e.File,
+		Line:     e.Line,
+		Column:   e.Column,
+		Path:     e.Path,
+		Message:  e.Message,
+		Fix:      e.Fix,
+	}
+}
+
+// Diagnostics returns the errors, then the warnings, as diagnostics.
+func (r *ValidationResult) Diagnostics() []Diagnostic {
+	diags := make([]Diagnostic, 0, len(r.Errors)+len(r.Warnings))
+	for i := range r.Errors {
+		diags = append(diags, r.Errors[i].Diagnostic("error"))
+	}
+	for i := range r.Warnings {
+		diags = append(diags, r.Warnings[i].Diagnostic("warning"))
+	}
+	return diags
+}
+
+// WriteDiagnostics writes diagnostics as a JSON array, which is empty rather
+// than null when there are none.
+func WriteDiagnostics(w io.Writer, diags []Diagnostic) error {
+	if diags == nil {
+		diags = []Diagnostic{}
+	}
+	data, err := json.MarshalIndent(diags, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
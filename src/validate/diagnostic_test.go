@@ -0,0 +1,74 @@
+package validate
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/benn-herrera/xplatter/model"
+)
+
+func TestDiagnostics(t *testing.T) {
+	api := minimalAPI()
+	api.Handles = append(api.Handles, model.HandleDef{Name: "Engine"})
+	srcMap := model.SourceMap{
+		"handles[1].name": {File: "api.yaml", Line: 9, Column: 11},
+	}
+	result := Validate(api, nil, "api.yaml", srcMap)
+	result.addWarning("reserved-word", "interfaces[0].name", "reserved")
+
+	diags := result.Diagnostics()
+	if len(diags) != 2 {
+		t.Fatalf("got %d diagnostics, want 2: %+v", len(diags), diags)
+	}
+	want := Diagnostic{
+		Rule:     "duplicate-name",
+		Severity: "error",
+		File:     "api.yaml",
+		Line:     9,
+		Column:   11,
+		Path:     "handles[1].name",
+		Message:  `duplicate handle name "Engine"`,
+	}
+	if diags[0] != want {
+		t.Errorf("error diagnostic = %+v, want %+v", diags[0], want)
+	}
+	if diags[1].Severity != "warning" || diags[1].File != "api.yaml" || diags[1].Line != 0 {
+		t.Errorf("unexpected warning diagnostic: %+v", diags[1])
+	}
+}
+
+func TestValidationError_Fix(t *testing.T) {
+	e := ValidationError{File: "api.yaml", Line: 3, Path: "handles[0]", Message: "problem", Fix: "fix it"}
+	if got, want := e.Error(), "api.yaml:3: handles[0]: problem; fix it"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteDiagnostics(t *testing.T) {
+	diags := []Diagnostic{
+		{Rule: "undefined-type", Severity: "error", File: "api.yaml", Line: 4, Column: 15, Path: "interfaces[0]", Message: "m", Fix: "f"},
+		{Rule: "load", Severity: "error", Message: "m"},
+	}
+	var b strings.Builder
+	if err := WriteDiagnostics(&b, diags); err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]interface{}
+	if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(got) != 2 || got[0]["column"] != 15.0 || got[0]["fix"] != "f" || got[0]["rule"] != "undefined-type" {
+		t.Errorf("unexpected JSON: %s", b.String())
+	}
+	for _, key := range []string{"file", "line", "column", "path", "fix"} {
+		if _, ok := got[1][key]; ok {
+			t.Errorf("expected empty %s to be omitted: %s", key, b.String())
+		}
+	}
+
+	b.Reset()
+	if err := WriteDiagnostics(&b, nil); err != nil || strings.TrimSpace(b.String()) != "[]" {
+		t.Errorf("expected an empty array for no diagnostics, got %q", b.String())
+	}
+}
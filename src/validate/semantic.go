@@ -2,6 +2,8 @@ package validate
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/benn-herrera/xplatter/model"
@@ -20,30 +22,67 @@ func isValidConstructorName(name string) bool {
 type ValidationError struct {
 	File    string // source YAML file path, empty if unknown
 	Line    int    // 1-based line number, 0 if unknown
+	Column  int    // 1-based column number, 0 if unknown
 	Path    string // e.g., "interfaces[0].methods[1].returns.type"
+	Rule    string // ID of the failed check, e.g., "undefined-type"
 	Message string
+	Fix     string // suggested fix, empty if there is none
 }
 
 func (e *ValidationError) Error() string {
+	msg := e.Message
+	if e.Fix != "" {
+		msg += "; " + e.Fix
+	}
 	if e.File != "" && e.Line > 0 {
-		return fmt.Sprintf("%s:%d: %s: %s", e.File, e.Line, e.Path, e.Message)
+		return fmt.Sprintf("%s:%d: %s: %s", e.File, e.Line, e.Path, msg)
 	}
 	if e.File != "" {
-		return fmt.Sprintf("%s: %s: %s", e.File, e.Path, e.Message)
+		return fmt.Sprintf("%s: %s: %s", e.File, e.Path, msg)
 	}
-	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+	return fmt.Sprintf("%s: %s", e.Path, msg)
 }
 
-// ValidationResult holds all validation errors.
+// ValidationResult holds all validation errors, and warnings about
+// definitions that are valid but likely unintended.
 type ValidationResult struct {
-	Errors []ValidationError
-	file   string
-	srcMap map[string]int
+	Errors   []ValidationError
+	Warnings []ValidationError
+	file     string
+	srcMap   model.SourceMap
+}
+
+func (r *ValidationResult) addError(rule, path, message string) {
+	r.Errors = append(r.Errors, r.at(rule, path, message, ""))
+}
+
+// addErrorFix adds an error with a suggested fix, which may be empty.
+func (r *ValidationResult) addErrorFix(rule, path, message, fix string) {
+	r.Errors = append(r.Errors, r.at(rule, path, message, fix))
+}
+
+func (r *ValidationResult) addWarning(rule, path, message string) {
+	r.Warnings = append(r.Warnings, r.at(rule, path, message, ""))
 }
 
-func (r *ValidationResult) addError(path, message string) {
-	line := r.srcMap[path]
-	r.Errors = append(r.Errors, ValidationError{File: r.file, Line: line, Path: path, Message: message})
+// at locates a message at the source of path.
+func (r *ValidationResult) at(rule, path, message, fix string) ValidationError {
+	loc, ok := r.srcMap[path]
+	if !ok || loc.File == "" {
+		loc.File = r.file
+	}
+	return ValidationError{File: loc.File, Line: loc.Line, Column: loc.Column, Path: path, Rule: rule, Message: message, Fix: fix}
+}
+
+// firstDefinedAt returns a message suffix pointing at the earlier definition a
+// duplicate collides with, or "" when its location is unknown. Definitions
+// may come from different imported files.
+func (r *ValidationResult) firstDefinedAt(path string) string {
+	loc, ok := r.srcMap[path]
+	if !ok || loc.File == "" || loc.Line == 0 {
+		return ""
+	}
+	return " (first defined at " + loc.String() + ")"
 }
 
 func (r *ValidationResult) IsValid() bool {
@@ -63,34 +102,43 @@ func (r *ValidationResult) Error() string {
 
 // Validate performs semantic validation on a parsed API definition.
 // resolvedTypes may be nil if FBS files are not available (skips type resolution checks).
-// file is the source YAML path for error messages; srcMap maps YAML paths to line numbers.
+// file is the source YAML path for error messages, used for paths missing from
+// srcMap; srcMap maps YAML paths to the file and line that declared them.
 // Pass empty string and nil when source location is unavailable.
-func Validate(def *model.APIDefinition, resolvedTypes resolver.ResolvedTypes, file string, srcMap map[string]int) *ValidationResult {
+func Validate(def *model.APIDefinition, resolvedTypes resolver.ResolvedTypes, file string, srcMap model.SourceMap) *ValidationResult {
 	result := &ValidationResult{file: file, srcMap: srcMap}
 
+	// Imported files may contribute every interface, so the schema cannot
+	// require one.
+	if len(def.Interfaces) == 0 {
+		result.addError("empty-api", "interfaces", "API must define at least one interface")
+	}
+
 	handleNames := make(map[string]bool)
 	for _, h := range def.Handles {
 		handleNames[h.Name] = true
 	}
 
 	// Check for duplicate handle names
-	seen := make(map[string]bool)
+	seen := make(map[string]string)
 	for i, h := range def.Handles {
 		path := fmt.Sprintf("handles[%d].name", i)
-		if seen[h.Name] {
-			result.addError(path, fmt.Sprintf("duplicate handle name %q", h.Name))
+		if first, ok := seen[h.Name]; ok {
+			result.addError("duplicate-name", path, fmt.Sprintf("duplicate handle name %q%s", h.Name, result.firstDefinedAt(first)))
+			continue
 		}
-		seen[h.Name] = true
+		seen[h.Name] = path
 	}
 
 	// Check for duplicate interface names
-	ifaceSeen := make(map[string]bool)
+	ifaceSeen := make(map[string]string)
 	for i, iface := range def.Interfaces {
 		ifacePath := fmt.Sprintf("interfaces[%d]", i)
-		if ifaceSeen[iface.Name] {
-			result.addError(ifacePath+".name", fmt.Sprintf("duplicate interface name %q", iface.Name))
+		if first, ok := ifaceSeen[iface.Name]; ok {
+			result.addError("duplicate-name", ifacePath+".name", fmt.Sprintf("duplicate interface name %q%s", iface.Name, result.firstDefinedAt(first)))
+		} else {
+			ifaceSeen[iface.Name] = ifacePath + ".name"
 		}
-		ifaceSeen[iface.Name] = true
 
 		// Collect all names within this interface to detect collisions
 		allNames := make(map[string]bool)
@@ -100,39 +148,47 @@ func Validate(def *model.APIDefinition, resolvedTypes resolver.ResolvedTypes, fi
 		for j, ctor := range iface.Constructors {
 			ctorPath := fmt.Sprintf("%s.constructors[%d]", ifacePath, j)
 			if !isValidConstructorName(ctor.Name) {
-				result.addError(ctorPath+".name", fmt.Sprintf("constructor name %q must be \"create\" or start with \"create_\"", ctor.Name))
+				result.addError("constructor-name", ctorPath+".name", fmt.Sprintf("constructor name %q must be \"create\" or start with \"create_\"", ctor.Name))
 			}
 			if allNames[ctor.Name] {
-				result.addError(ctorPath+".name", fmt.Sprintf("duplicate name %q in interface %q", ctor.Name, iface.Name))
+				result.addError("duplicate-name", ctorPath+".name", fmt.Sprintf("duplicate name %q in interface %q", ctor.Name, iface.Name))
 			}
 			allNames[ctor.Name] = true
 
 			// Constructor must be fallible
 			if ctor.Error == "" {
-				result.addError(ctorPath+".error", fmt.Sprintf("constructor %q must declare an error type", ctor.Name))
+				result.addError("constructor-error", ctorPath+".error", fmt.Sprintf("constructor %q must declare an error type", ctor.Name))
+			}
+			// Constructor failure is reported through its error, never an absent handle
+			if ctor.Returns != nil && ctor.Returns.Optional {
+				result.addErrorFix("constructor-return", ctorPath+".returns.optional", fmt.Sprintf("constructor %q cannot have an optional return", ctor.Name), "report failure through its error type")
+			}
+			// The caller owns what a constructor creates
+			if ctor.Returns != nil && ctor.Returns.Borrowed {
+				result.addError("constructor-return", ctorPath+".returns.borrowed", fmt.Sprintf("constructor %q cannot return a borrowed handle; the caller owns the handles it creates", ctor.Name))
 			}
 			// Constructor must return a handle
 			if ctor.Returns == nil {
-				result.addError(ctorPath+".returns", fmt.Sprintf("constructor %q must return a handle type", ctor.Name))
+				result.addError("constructor-return", ctorPath+".returns", fmt.Sprintf("constructor %q must return a handle type", ctor.Name))
 			} else if hn, ok := model.IsHandle(ctor.Returns.Type); !ok {
-				result.addError(ctorPath+".returns.type", fmt.Sprintf("constructor %q must return a handle type, got %q", ctor.Name, ctor.Returns.Type))
+				result.addError("constructor-return", ctorPath+".returns.type", fmt.Sprintf("constructor %q must return a handle type, got %q", ctor.Name, ctor.Returns.Type))
 			} else {
 				// All constructors in an interface must return the same handle
 				if constructorHandleName == "" {
 					constructorHandleName = hn
 				} else if hn != constructorHandleName {
-					result.addError(ctorPath+".returns.type", fmt.Sprintf("constructor %q returns handle %q but interface already has constructors returning %q; all constructors must return the same handle type", ctor.Name, hn, constructorHandleName))
+					result.addError("constructor-return", ctorPath+".returns.type", fmt.Sprintf("constructor %q returns handle %q but interface already has constructors returning %q; all constructors must return the same handle type", ctor.Name, hn, constructorHandleName))
 				}
 				// Validate the handle is defined
 				if !handleNames[hn] {
-					result.addError(ctorPath+".returns.type", fmt.Sprintf("handle %q not defined in handles section", hn))
+					result.addErrorFix("undefined-handle", ctorPath+".returns.type", fmt.Sprintf("handle %q not defined in handles section", hn), didYouMean(hn, handleNames))
 				}
 			}
 			// Constructor must not take handle-typed input parameters
 			for k, param := range ctor.Parameters {
 				paramPath := fmt.Sprintf("%s.parameters[%d]", ctorPath, k)
 				if hn, ok := model.IsHandle(param.Type); ok {
-					result.addError(paramPath+".type", fmt.Sprintf("constructor %q must not take handle parameter %q of type handle:%s", ctor.Name, param.Name, hn))
+					result.addError("constructor-param", paramPath+".type", fmt.Sprintf("constructor %q must not take handle parameter %q of type handle:%s", ctor.Name, param.Name, hn))
 				}
 				validateParamType(result, paramPath, &param, handleNames, resolvedTypes)
 			}
@@ -140,9 +196,9 @@ func Validate(def *model.APIDefinition, resolvedTypes resolver.ResolvedTypes, fi
 			if ctor.Error != "" && resolvedTypes != nil {
 				info, ok := resolvedTypes[ctor.Error]
 				if !ok {
-					result.addError(ctorPath+".error", fmt.Sprintf("error type %q not found in FlatBuffers schemas", ctor.Error))
+					result.addErrorFix("undefined-type", ctorPath+".error", fmt.Sprintf("error type %q not found in FlatBuffers schemas", ctor.Error), didYouMean(ctor.Error, resolvedTypes))
 				} else if info.Kind != resolver.TypeKindEnum {
-					result.addError(ctorPath+".error", fmt.Sprintf("error type %q must be an enum, got %s", ctor.Error, info.Kind))
+					result.addError("error-type", ctorPath+".error", fmt.Sprintf("error type %q must be an enum, got %s", ctor.Error, info.Kind))
 				}
 			}
 		}
@@ -159,22 +215,541 @@ func Validate(def *model.APIDefinition, resolvedTypes resolver.ResolvedTypes, fi
 		for j, method := range iface.Methods {
 			methodPath := fmt.Sprintf("%s.methods[%d]", ifacePath, j)
 			if allNames[method.Name] {
-				result.addError(methodPath+".name", fmt.Sprintf("duplicate name %q in interface %q", method.Name, iface.Name))
+				result.addError("duplicate-name", methodPath+".name", fmt.Sprintf("duplicate name %q in interface %q", method.Name, iface.Name))
 			}
 			allNames[method.Name] = true
 
 			validateMethod(result, methodPath, &method, handleNames, resolvedTypes)
 		}
 
+		// Async methods expand to <name>_start/_poll/_cancel at the C ABI level;
+		// those symbols must not collide with anything else in the interface.
+		for j, method := range iface.Methods {
+			if !method.Async {
+				continue
+			}
+			methodPath := fmt.Sprintf("%s.methods[%d]", ifacePath, j)
+			for _, suffix := range []string{"_start", "_poll", "_cancel"} {
+				if allNames[method.Name+suffix] {
+					result.addError("name-collision", methodPath+".name", fmt.Sprintf("async method %q generates %q which collides with another name in interface %q", method.Name, method.Name+suffix, iface.Name))
+				}
+			}
+		}
+
 		// Interface must have at least one constructor or at least one method
 		if len(iface.Constructors) == 0 && len(iface.Methods) == 0 {
-			result.addError(ifacePath, fmt.Sprintf("interface %q must have at least one constructor or method", iface.Name))
+			result.addError("empty-interface", ifacePath, fmt.Sprintf("interface %q must have at least one constructor or method", iface.Name))
 		}
 	}
 
+	validateExtends(result, def)
+	validateLifecycle(result, def)
+	validateThreadAffinity(result, def)
+	validateEvents(result, def, resolvedTypes)
+	validateNamespaces(result, def, resolvedTypes)
+
 	return result
 }
 
+// CheckSchemaUse warns about each listed flatbuffers file that contributes no
+// type the API reaches from its parameters, returns, errors and event
+// payloads, neither declared in the file nor in the files it includes.
+func CheckSchemaUse(result *ValidationResult, def *model.APIDefinition, set *resolver.FBSSet) {
+	needed := make(map[string]bool)
+	for _, info := range resolver.Reachable(set.Types, def.FlatBufferTypeRefs()) {
+		needed[info.Pos.File] = true
+	}
+
+	for i, file := range set.Listed {
+		if i >= len(def.FlatBuffers) {
+			break
+		}
+		files := make(map[string]bool)
+		includeClosure(set, file, files)
+		used := false
+		for f := range files {
+			if needed[f] {
+				used = true
+				break
+			}
+		}
+		if !used {
+			result.addWarning("unused-schema", fmt.Sprintf("flatbuffers[%d]", i), fmt.Sprintf("schema %q contributes no types the API uses", def.FlatBuffers[i]))
+		}
+	}
+}
+
+// CheckReservedWords warns about each method and parameter name that is a
+// reserved word in a generated language, which the generators escape.
+// reservedIn returns the languages reserving a name, as gen.ReservedIn does.
+func CheckReservedWords(result *ValidationResult, def *model.APIDefinition, reservedIn func(name string) []string) {
+	check := func(path, kind, name string) {
+		if langs := reservedIn(name); len(langs) > 0 {
+			result.addWarning("reserved-word", path, fmt.Sprintf("%s name %q is reserved in %s; generated code escapes it", kind, name, strings.Join(langs, ", ")))
+		}
+	}
+	checkParams := func(path string, method *model.MethodDef) {
+		for k, p := range method.Parameters {
+			check(fmt.Sprintf("%s.parameters[%d].name", path, k), "parameter", p.Name)
+		}
+	}
+	for i, iface := range def.Interfaces {
+		for j := range iface.Constructors {
+			checkParams(fmt.Sprintf("interfaces[%d].constructors[%d]", i, j), &iface.Constructors[j])
+		}
+		for j := range iface.Methods {
+			path := fmt.Sprintf("interfaces[%d].methods[%d]", i, j)
+			check(path+".name", "method", iface.Methods[j].Name)
+			checkParams(path, &iface.Methods[j])
+		}
+	}
+}
+
+// includeClosure adds file and every file it includes, transitively, to seen.
+func includeClosure(set *resolver.FBSSet, file string, seen map[string]bool) {
+	if seen[file] {
+		return
+	}
+	seen[file] = true
+	for _, inc := range set.Includes[file] {
+		includeClosure(set, inc, seen)
+	}
+}
+
+// validateNamespaces checks the namespace mappings: each namespace is mapped
+// once and declares types in the schemas, no two namespaces share a target
+// package, prefix or module, and for a go implementation the Go packages of
+// mapped namespaces can import each other without a cycle and never need a
+// type left in the main package.
+func validateNamespaces(result *ValidationResult, def *model.APIDefinition, resolvedTypes resolver.ResolvedTypes) {
+	seen := make(map[string]bool)
+	targets := make(map[string]string)
+	for i, ns := range def.Namespaces {
+		path := fmt.Sprintf("namespaces[%d]", i)
+		if seen[ns.Name] {
+			result.addError("namespace", path+".name", fmt.Sprintf("duplicate namespace mapping %q", ns.Name))
+		}
+		seen[ns.Name] = true
+
+		if resolvedTypes != nil && !declaresNamespace(resolvedTypes, ns.Name) {
+			result.addError("namespace", path+".name", fmt.Sprintf("namespace %q declares no types in the FlatBuffers schemas", ns.Name))
+		}
+
+		for _, target := range []struct{ lang, value string }{
+			{"kotlin", ns.Kotlin}, {"swift", ns.Swift}, {"js", ns.JS}, {"rust", ns.Rust}, {"go", ns.Go},
+		} {
+			if target.value == "" {
+				continue
+			}
+			key := target.lang + ":" + target.value
+			if other, ok := targets[key]; ok {
+				result.addError("namespace", path+"."+target.lang, fmt.Sprintf("namespaces %q and %q both map to %s %q", other, ns.Name, target.lang, target.value))
+				continue
+			}
+			targets[key] = ns.Name
+		}
+	}
+
+	if resolvedTypes != nil && def.API.ImplLang == "go" {
+		validateGoNamespaces(result, def, resolvedTypes)
+	}
+}
+
+// declaresNamespace reports whether any type is declared directly in ns.
+func declaresNamespace(resolvedTypes resolver.ResolvedTypes, ns string) bool {
+	for name := range resolvedTypes {
+		if model.FlatBufferNamespace(name) == ns {
+			return true
+		}
+	}
+	return false
+}
+
+// validateGoNamespaces checks that the Go package of each mapped namespace
+// only refers to types of namespaces with Go packages of their own, and that
+// those packages do not import each other in a cycle. The main package
+// imports the namespace packages, so they cannot import it back.
+func validateGoNamespaces(result *ValidationResult, def *model.APIDefinition, resolvedTypes resolver.ResolvedTypes) {
+	pathOf := make(map[string]string)
+	for i, ns := range def.Namespaces {
+		if ns.Go != "" {
+			pathOf[ns.Name] = fmt.Sprintf("namespaces[%d].go", i)
+		}
+	}
+	if len(pathOf) == 0 {
+		return
+	}
+
+	imports := make(map[string]map[string]bool)
+	reached := resolver.Reachable(resolvedTypes, def.FlatBufferTypeRefs())
+	names := make([]string, 0, len(reached))
+	for name := range reached {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ns := model.FlatBufferNamespace(name)
+		if _, mapped := pathOf[ns]; !mapped {
+			continue
+		}
+		for _, ref := range typeRefs(resolvedTypes, reached[name]) {
+			refNS := model.FlatBufferNamespace(ref)
+			if refNS == ns {
+				continue
+			}
+			if _, mapped := pathOf[refNS]; !mapped {
+				result.addErrorFix("namespace", pathOf[ns], fmt.Sprintf("type %s refers to %s, whose namespace %q has no Go package", name, ref, refNS), "map it too")
+				continue
+			}
+			if imports[ns] == nil {
+				imports[ns] = make(map[string]bool)
+			}
+			imports[ns][refNS] = true
+		}
+	}
+
+	// Report each cycle once, at the namespace that closes it.
+	state := make(map[string]int) // 1: visiting, 2: done
+	var visit func(ns string, chain []string)
+	visit = func(ns string, chain []string) {
+		state[ns] = 1
+		deps := make([]string, 0, len(imports[ns]))
+		for dep := range imports[ns] {
+			deps = append(deps, dep)
+		}
+		sort.Strings(deps)
+		for _, dep := range deps {
+			switch state[dep] {
+			case 1:
+				result.addError("namespace", pathOf[ns], fmt.Sprintf("Go packages of namespaces import each other in a cycle: %s -> %s", strings.Join(append(chain, ns), " -> "), dep))
+			case 0:
+				visit(dep, append(chain, ns))
+			}
+		}
+		state[ns] = 2
+	}
+	for _, ns := range def.Namespaces {
+		if ns.Go != "" && state[ns.Name] == 0 {
+			visit(ns.Name, nil)
+		}
+	}
+}
+
+// typeRefs returns the struct, table and union types a type's fields and
+// union members name; enums are plain constants in Go.
+func typeRefs(resolvedTypes resolver.ResolvedTypes, info *resolver.TypeInfo) []string {
+	candidates := make([]string, 0, len(info.Fields)+len(info.Members))
+	for _, f := range info.Fields {
+		candidates = append(candidates, resolver.FieldTypeName(f.Type))
+	}
+	for _, m := range info.Members {
+		candidates = append(candidates, m.Type)
+	}
+	var refs []string
+	for _, t := range candidates {
+		if ref, ok := resolvedTypes[t]; ok && ref.Kind != resolver.TypeKindEnum {
+			refs = append(refs, t)
+		}
+	}
+	return refs
+}
+
+// validateExtends checks interface inheritance: bases exist, chains are
+// acyclic, every interface in a chain operates on a single receiver handle
+// distinct from its base's, base interfaces are abstract, each handle has at
+// most one parent, and no interface redeclares an inherited name.
+func validateExtends(result *ValidationResult, def *model.APIDefinition) {
+	parentOf := make(map[string]string)
+	ifaceNames := make(map[string]bool)
+	for _, iface := range def.Interfaces {
+		ifaceNames[iface.Name] = true
+	}
+	for i := range def.Interfaces {
+		iface := &def.Interfaces[i]
+		if iface.Extends == "" {
+			continue
+		}
+		path := fmt.Sprintf("interfaces[%d].extends", i)
+		base := def.BaseInterface(iface)
+		if base == nil {
+			result.addErrorFix("extends", path, fmt.Sprintf("interface %q extends undefined interface %q", iface.Name, iface.Extends), didYouMean(iface.Extends, ifaceNames))
+			continue
+		}
+		if chain, cyclic := inheritanceChain(def, iface); cyclic {
+			// A cycle further up the chain is reported on its own members.
+			if chain[len(chain)-1] == iface.Name {
+				result.addError("extends", path, fmt.Sprintf("interface %q has an inheritance cycle: %s", iface.Name, strings.Join(chain, " -> ")))
+			}
+			continue
+		}
+
+		if len(base.Constructors) > 0 {
+			result.addError("extends", path, fmt.Sprintf("base interface %q cannot declare constructors; handles are constructed by the interfaces that extend it", base.Name))
+		}
+		receiver, ok := iface.ReceiverHandleName()
+		if !ok {
+			result.addError("extends", path, fmt.Sprintf("interface %q must take the same handle as the first parameter of every method to extend %q", iface.Name, base.Name))
+		}
+		parent, baseOK := base.ReceiverHandleName()
+		if !baseOK {
+			result.addError("extends", path, fmt.Sprintf("base interface %q must take the same handle as the first parameter of every method", base.Name))
+		}
+		if ok && baseOK {
+			if receiver == parent {
+				result.addError("extends", path, fmt.Sprintf("interface %q and its base %q both operate on handle %q; a derived interface needs its own handle", iface.Name, base.Name, receiver))
+			} else if prev, seen := parentOf[receiver]; seen && prev != parent {
+				result.addError("extends", path, fmt.Sprintf("handle %q cannot extend both %q and %q", receiver, prev, parent))
+			} else {
+				parentOf[receiver] = parent
+			}
+		}
+
+		// Inherited methods share the derived class in every binding, so the
+		// derived interface cannot reuse any of their names.
+		own := make(map[string]bool)
+		for _, ctor := range iface.Constructors {
+			own[ctor.Name] = true
+		}
+		if handleName, ok := iface.ConstructorHandleName(); ok {
+			own["destroy_"+model.HandleToSnake(handleName)] = true
+		}
+		for _, method := range iface.Methods {
+			own[method.Name] = true
+		}
+		for ancestor := base; ancestor != nil; ancestor = def.BaseInterface(ancestor) {
+			for _, method := range ancestor.Methods {
+				if own[method.Name] {
+					result.addError("extends", path, fmt.Sprintf("interface %q declares %q, which collides with the method inherited from %q", iface.Name, method.Name, ancestor.Name))
+				}
+			}
+		}
+	}
+}
+
+// inheritanceChain follows extends from iface and returns the interface names
+// visited. If the chain revisits an interface it stops there and reports a
+// cycle; the last name is the one revisited.
+func inheritanceChain(def *model.APIDefinition, iface *model.InterfaceDef) ([]string, bool) {
+	chain := []string{iface.Name}
+	seen := map[string]bool{iface.Name: true}
+	for cur := def.BaseInterface(iface); cur != nil; cur = def.BaseInterface(cur) {
+		chain = append(chain, cur.Name)
+		if seen[cur.Name] {
+			return chain, true
+		}
+		seen[cur.Name] = true
+	}
+	return chain, false
+}
+
+// validateLifecycle checks since, deprecated and stability annotations: no
+// element is introduced after the API's own version or before its interface,
+// and every replacement names another element of the same kind — a handle, an
+// interface, or a constructor or method of the same interface.
+func validateLifecycle(result *ValidationResult, def *model.APIDefinition) {
+	checkSince := func(path, since string) {
+		if since != "" && compareVersions(since, def.API.Version) > 0 {
+			result.addError("lifecycle", path, fmt.Sprintf("since %q is later than the API version %q", since, def.API.Version))
+		}
+	}
+	checkReplacement := func(path, kind, name string, d *model.Deprecation, exists func(string) bool) {
+		if d == nil || d.Replacement == "" {
+			return
+		}
+		if d.Replacement == name {
+			result.addError("lifecycle", path, fmt.Sprintf("%s %q cannot name itself as its replacement", kind, name))
+		} else if !exists(d.Replacement) {
+			result.addError("lifecycle", path, fmt.Sprintf("%s %q names replacement %q, which is not defined", kind, name, d.Replacement))
+		}
+	}
+
+	for i, h := range def.Handles {
+		path := fmt.Sprintf("handles[%d]", i)
+		checkSince(path+".since", h.Since)
+		checkReplacement(path+".deprecated.replacement", "handle", h.Name, h.Deprecated, func(name string) bool {
+			return def.HandleByName(name) != nil
+		})
+	}
+
+	for i := range def.Interfaces {
+		iface := &def.Interfaces[i]
+		ifacePath := fmt.Sprintf("interfaces[%d]", i)
+		checkSince(ifacePath+".since", iface.Since)
+		checkReplacement(ifacePath+".deprecated.replacement", "interface", iface.Name, iface.Deprecated, func(name string) bool {
+			return def.InterfaceByName(name) != nil
+		})
+
+		members := make(map[string]bool)
+		for _, ctor := range iface.Constructors {
+			members[ctor.Name] = true
+		}
+		for _, method := range iface.Methods {
+			members[method.Name] = true
+		}
+		checkMethod := func(path string, method *model.MethodDef) {
+			checkSince(path+".since", method.Since)
+			if method.Since != "" && iface.Since != "" && compareVersions(method.Since, iface.Since) < 0 {
+				result.addError("lifecycle", path+".since", fmt.Sprintf("method %q is marked since %q, before its interface %q was introduced in %q", method.Name, method.Since, iface.Name, iface.Since))
+			}
+			checkReplacement(path+".deprecated.replacement", "method", method.Name, method.Deprecated, func(name string) bool {
+				return members[name]
+			})
+		}
+		for j := range iface.Constructors {
+			checkMethod(fmt.Sprintf("%s.constructors[%d]", ifacePath, j), &iface.Constructors[j])
+		}
+		for j := range iface.Methods {
+			checkMethod(fmt.Sprintf("%s.methods[%d]", ifacePath, j), &iface.Methods[j])
+		}
+	}
+}
+
+// validateThreadAffinity checks that creator affinity can be enforced: the
+// shims record a handle's creating thread in its constructor and check it
+// against the handle a method is called on.
+func validateThreadAffinity(result *ValidationResult, def *model.APIDefinition) {
+	created := make(map[string]bool)
+	for i := range def.Interfaces {
+		if handleName, ok := def.Interfaces[i].ConstructorHandleName(); ok {
+			created[handleName] = true
+		}
+	}
+	for i, h := range def.Handles {
+		if h.ThreadAffinity == model.ThreadAffinityCreator && !created[h.Name] {
+			result.addError("thread-affinity", fmt.Sprintf("handles[%d].thread_affinity", i), fmt.Sprintf("handle %q has thread affinity \"creator\" but no constructor creates it", h.Name))
+		}
+	}
+
+	for i := range def.Interfaces {
+		iface := &def.Interfaces[i]
+		for j := range iface.Methods {
+			method := &iface.Methods[j]
+			if def.MethodThreadAffinity(iface, method) != model.ThreadAffinityCreator || method.FirstHandleParam() != nil {
+				continue
+			}
+			if method.Returns != nil {
+				if _, ok := model.IsHandle(method.Returns.Type); ok {
+					// Like a constructor: the handle it returns is bound to the calling thread.
+					continue
+				}
+			}
+			path := fmt.Sprintf("interfaces[%d].methods[%d].thread_affinity", i, j)
+			if method.ThreadAffinity == "" {
+				path = fmt.Sprintf("interfaces[%d].thread_affinity", i)
+			}
+			result.addError("thread-affinity", path, fmt.Sprintf("method %q has thread affinity \"creator\" but takes no handle to check it against", method.Name))
+		}
+	}
+}
+
+// compareVersions orders two MAJOR.MINOR.PATCH versions, returning -1, 0 or 1.
+// Components that fail to parse compare as zero; the schema enforces the format.
+func compareVersions(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < 3; i++ {
+		var x, y int
+		if i < len(pa) {
+			x, _ = strconv.Atoi(pa[i])
+		}
+		if i < len(pb) {
+			y, _ = strconv.Atoi(pb[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// didYouMean suggests the defined name closest to an undefined one, or
+// returns "" when none is close enough to be a likely typo. Ties go to the
+// name that sorts first.
+func didYouMean[V any](name string, defined map[string]V) string {
+	best, bestDist := "", len(name)/3+1
+	for candidate := range defined {
+		d := editDistance(strings.ToLower(name), strings.ToLower(candidate))
+		if d < bestDist || d == bestDist && best != "" && candidate < best {
+			best, bestDist = candidate, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf("did you mean %q?", best)
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// validateEvents checks the events section: unique event names, table payload
+// types, and no collision between the generated <api>_event_* functions and
+// method C ABI symbols.
+func validateEvents(result *ValidationResult, def *model.APIDefinition, resolvedTypes resolver.ResolvedTypes) {
+	if len(def.Events) == 0 {
+		return
+	}
+
+	apiName := def.API.Name
+	symbols := make(map[string]bool)
+	for _, iface := range def.Interfaces {
+		for _, ctor := range iface.Constructors {
+			symbols[apiName+"_"+iface.Name+"_"+ctor.Name] = true
+		}
+		for _, method := range iface.Methods {
+			symbols[apiName+"_"+iface.Name+"_"+method.Name] = true
+		}
+	}
+	for _, fn := range []string{"poll", "signal_fd"} {
+		symbol := apiName + "_event_" + fn
+		if symbols[symbol] {
+			result.addError("name-collision", "events", fmt.Sprintf("generated event function %q collides with a method of the same C ABI name", symbol))
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i, ev := range def.Events {
+		path := fmt.Sprintf("events[%d]", i)
+		if seen[ev.Name] {
+			result.addError("duplicate-name", path+".name", fmt.Sprintf("duplicate event name %q", ev.Name))
+		}
+		seen[ev.Name] = true
+
+		if symbol := apiName + "_event_push_" + ev.Name; symbols[symbol] {
+			result.addError("name-collision", path+".name", fmt.Sprintf("generated event function %q collides with a method of the same C ABI name", symbol))
+		}
+
+		if resolvedTypes != nil {
+			info, ok := resolvedTypes[ev.Type]
+			if !ok {
+				result.addErrorFix("undefined-type", path+".type", fmt.Sprintf("FlatBuffer type %q not found in schemas", ev.Type), didYouMean(ev.Type, resolvedTypes))
+			} else if info.Kind != resolver.TypeKindTable && info.Kind != resolver.TypeKindUnion {
+				result.addError("event-type", path+".type", fmt.Sprintf("event payload type %q must be a table or union, got %s", ev.Type, info.Kind))
+			}
+		}
+	}
+}
+
 func validateMethod(result *ValidationResult, path string, method *model.MethodDef, handleNames map[string]bool, resolvedTypes resolver.ResolvedTypes) {
 	// Validate parameters
 	for k, param := range method.Parameters {
@@ -186,6 +761,16 @@ func validateMethod(result *ValidationResult, path string, method *model.MethodD
 	if method.Returns != nil {
 		retPath := path + ".returns"
 		validateReturnType(result, retPath, method.Returns.Type, handleNames, resolvedTypes)
+		if _, ok := model.IsBuffer(method.Returns.Type); ok && method.Returns.Optional {
+			result.addErrorFix("optional", retPath+".optional", "buffer<T> returns cannot be optional", "return an empty buffer")
+		}
+		if _, ok := model.IsHandle(method.Returns.Type); !ok && method.Returns.Borrowed {
+			result.addError("return-type", retPath+".borrowed", "only handle returns can be borrowed")
+		}
+	}
+
+	if method.Async {
+		validateAsyncMethod(result, path, method)
 	}
 
 	// Validate error type
@@ -194,73 +779,121 @@ func validateMethod(result *ValidationResult, path string, method *model.MethodD
 		if resolvedTypes != nil {
 			info, ok := resolvedTypes[method.Error]
 			if !ok {
-				result.addError(errPath, fmt.Sprintf("error type %q not found in FlatBuffers schemas", method.Error))
+				result.addErrorFix("undefined-type", errPath, fmt.Sprintf("error type %q not found in FlatBuffers schemas", method.Error), didYouMean(method.Error, resolvedTypes))
 			} else if info.Kind != resolver.TypeKindEnum {
-				result.addError(errPath, fmt.Sprintf("error type %q must be an enum, got %s", method.Error, info.Kind))
+				result.addError("error-type", errPath, fmt.Sprintf("error type %q must be an enum, got %s", method.Error, info.Kind))
 			}
 		}
 	}
 }
 
+// validateAsyncMethod checks the extra constraints on async methods: the
+// operation is owned by a handle, and no parameter may alias caller memory
+// beyond the start call.
+func validateAsyncMethod(result *ValidationResult, path string, method *model.MethodDef) {
+	hasHandle := false
+	for k, param := range method.Parameters {
+		if _, ok := model.IsHandle(param.Type); ok {
+			hasHandle = true
+		}
+		if param.Transfer == "ref_mut" {
+			paramPath := fmt.Sprintf("%s.parameters[%d].transfer", path, k)
+			result.addError("async", paramPath, fmt.Sprintf("async method %q must not take ref_mut parameter %q; the caller's memory is only valid until the operation starts", method.Name, param.Name))
+		}
+	}
+	if !hasHandle {
+		result.addError("async", path+".async", fmt.Sprintf("async method %q must take a handle parameter", method.Name))
+	}
+	if method.Returns != nil {
+		if _, ok := model.IsBuffer(method.Returns.Type); ok {
+			result.addErrorFix("async", path+".returns.type", fmt.Sprintf("async method %q cannot return %s", method.Name, method.Returns.Type), "return a FlatBuffer result type")
+		}
+		if method.Returns.Optional {
+			result.addError("async", path+".returns.optional", fmt.Sprintf("async method %q cannot have an optional return", method.Name))
+		}
+		// The owner may be destroyed before the result is delivered.
+		if method.Returns.Borrowed {
+			result.addError("async", path+".returns.borrowed", fmt.Sprintf("async method %q cannot return a borrowed handle", method.Name))
+		}
+	}
+}
+
 func validateParamType(result *ValidationResult, path string, param *model.ParameterDef, handleNames map[string]bool, resolvedTypes resolver.ResolvedTypes) {
 	typePath := path + ".type"
 	t := param.Type
 
 	if model.IsPrimitive(t) {
 		// Primitives are always valid as parameters
+		if param.Transfer == "move" {
+			result.addError("transfer", path+".transfer", "move transfer only applies to handle parameters")
+		}
 		return
 	}
 
 	if model.IsString(t) {
 		// String transfer is always ref (implicit), warn if specified differently
 		if param.Transfer != "" && param.Transfer != "ref" {
-			result.addError(path+".transfer", "string parameters always use ref transfer semantics")
+			result.addError("transfer", path+".transfer", "string parameters always use ref transfer semantics")
 		}
 		return
 	}
 
 	if elemType, ok := model.IsBuffer(t); ok {
 		if !model.IsPrimitive(elemType) {
-			result.addError(typePath, fmt.Sprintf("buffer element type %q must be a primitive type", elemType))
+			result.addError("param-type", typePath, fmt.Sprintf("buffer element type %q must be a primitive type", elemType))
 		}
-		if param.Transfer == "" || param.Transfer == "value" {
-			result.addError(path+".transfer", "buffer<T> parameters must specify ref or ref_mut transfer")
+		if param.Transfer != "ref" && param.Transfer != "ref_mut" {
+			result.addError("transfer", path+".transfer", "buffer<T> parameters must specify ref or ref_mut transfer")
+		}
+		if param.Optional {
+			result.addErrorFix("optional", path+".optional", "buffer<T> parameters cannot be optional", "pass an empty buffer")
 		}
 		return
 	}
 
 	if handleName, ok := model.IsHandle(t); ok {
 		if !handleNames[handleName] {
-			result.addError(typePath, fmt.Sprintf("handle %q not defined in handles section", handleName))
+			result.addErrorFix("undefined-handle", typePath, fmt.Sprintf("handle %q not defined in handles section", handleName), didYouMean(handleName, handleNames))
 		}
-		if param.Transfer != "" && param.Transfer != "value" {
-			result.addError(path+".transfer", "handle parameters always use value transfer (pointer copy)")
+		if param.Transfer != "" && param.Transfer != "value" && param.Transfer != "move" {
+			result.addError("transfer", path+".transfer", "handle parameters use value transfer (pointer copy) or move transfer (ownership passes to the callee)")
 		}
 		return
 	}
 
 	if model.IsFlatBufferType(t) {
 		if resolvedTypes != nil {
-			if _, ok := resolvedTypes[t]; !ok {
-				result.addError(typePath, fmt.Sprintf("FlatBuffer type %q not found in schemas", t))
+			info, ok := resolvedTypes[t]
+			if !ok {
+				result.addErrorFix("undefined-type", typePath, fmt.Sprintf("FlatBuffer type %q not found in schemas", t), didYouMean(t, resolvedTypes))
+			} else if info.Kind == resolver.TypeKindTable && param.Transfer == "ref_mut" {
+				// Tables cross the ABI as read-only wire-format buffers.
+				result.addError("transfer", path+".transfer", fmt.Sprintf("table parameter %q cannot use ref_mut transfer; FlatBuffer tables are immutable, so return a new table instead", param.Name))
 			}
 		}
+		if param.Transfer == "move" {
+			result.addError("transfer", path+".transfer", "move transfer only applies to handle parameters")
+		}
+		// Absent FlatBuffer values are passed as NULL, so they must be passed by pointer.
+		if param.Optional && param.Transfer != "ref" && param.Transfer != "ref_mut" {
+			result.addError("transfer", path+".transfer", "optional FlatBuffer parameters must specify ref or ref_mut transfer")
+		}
 		return
 	}
 
-	result.addError(typePath, fmt.Sprintf("unknown type %q", t))
+	result.addError("param-type", typePath, fmt.Sprintf("unknown type %q", t))
 }
 
 func validateReturnType(result *ValidationResult, path string, t string, handleNames map[string]bool, resolvedTypes resolver.ResolvedTypes) {
 	typePath := path + ".type"
 
-	// string and buffer<T> are not allowed as return types
+	// string returns are caller-owned (see <api>_string_free)
 	if model.IsString(t) {
-		result.addError(typePath, "string cannot be used as a return type; use a FlatBuffer result type")
 		return
 	}
+
+	// buffer<T> returns are caller-owned (see <api>_buffer_free)
 	if _, ok := model.IsBuffer(t); ok {
-		result.addError(typePath, "buffer<T> cannot be used as a return type; use a FlatBuffer result type")
 		return
 	}
 
@@ -270,7 +903,7 @@ func validateReturnType(result *ValidationResult, path string, t string, handleN
 
 	if handleName, ok := model.IsHandle(t); ok {
 		if !handleNames[handleName] {
-			result.addError(typePath, fmt.Sprintf("handle %q not defined in handles section", handleName))
+			result.addErrorFix("undefined-handle", typePath, fmt.Sprintf("handle %q not defined in handles section", handleName), didYouMean(handleName, handleNames))
 		}
 		return
 	}
@@ -278,11 +911,11 @@ func validateReturnType(result *ValidationResult, path string, t string, handleN
 	if model.IsFlatBufferType(t) {
 		if resolvedTypes != nil {
 			if _, ok := resolvedTypes[t]; !ok {
-				result.addError(typePath, fmt.Sprintf("FlatBuffer type %q not found in schemas", t))
+				result.addErrorFix("undefined-type", typePath, fmt.Sprintf("FlatBuffer type %q not found in schemas", t), didYouMean(t, resolvedTypes))
 			}
 		}
 		return
 	}
 
-	result.addError(typePath, fmt.Sprintf("unknown return type %q", t))
+	result.addError("return-type", typePath, fmt.Sprintf("unknown return type %q", t))
 }
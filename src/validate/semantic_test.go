@@ -78,17 +78,8 @@ func TestValidate_StringReturnType(t *testing.T) {
 	})
 
 	result := Validate(api, nil, "", nil)
-	if result.IsValid() {
-		t.Error("expected validation error for string return type")
-	}
-	found := false
-	for _, e := range result.Errors {
-		if strings.Contains(e.Message, "string cannot be used as a return type") {
-			found = true
-		}
-	}
-	if !found {
-		t.Errorf("expected string return type error, got: %s", result.Error())
+	if !result.IsValid() {
+		t.Errorf("string return type should be valid, got: %s", result.Error())
 	}
 }
 
@@ -99,9 +90,29 @@ func TestValidate_BufferReturnType(t *testing.T) {
 		Returns: &model.ReturnDef{Type: "buffer<uint8>"},
 	})
 
+	result := Validate(api, nil, "", nil)
+	if !result.IsValid() {
+		t.Errorf("buffer<T> return type should be valid, got: %s", result.Error())
+	}
+}
+
+func TestValidate_AsyncBufferReturnType(t *testing.T) {
+	api := minimalAPI()
+	api.Interfaces[0].Methods = append(api.Interfaces[0].Methods, model.MethodDef{
+		Name:  "read_data",
+		Async: true,
+		Parameters: []model.ParameterDef{
+			{Name: "engine", Type: "handle:Engine"},
+		},
+		Returns: &model.ReturnDef{Type: "buffer<uint8>"},
+	})
+
 	result := Validate(api, nil, "", nil)
 	if result.IsValid() {
-		t.Error("expected validation error for buffer<T> return type")
+		t.Fatal("expected validation error for async buffer<T> return type")
+	}
+	if !strings.Contains(result.Error(), "cannot return buffer<uint8>") {
+		t.Errorf("unexpected error: %s", result.Error())
 	}
 }
 
@@ -199,6 +210,28 @@ func TestValidate_UnresolvedFlatBufferType(t *testing.T) {
 	}
 }
 
+func TestValidate_TableRefMut(t *testing.T) {
+	api := minimalAPI()
+	api.Interfaces[0].Methods = append(api.Interfaces[0].Methods, model.MethodDef{
+		Name: "fill_queue",
+		Parameters: []model.ParameterDef{
+			{Name: "queue", Type: "Common.EventQueue", Transfer: "ref_mut"},
+			{Name: "transform", Type: "Geometry.Transform3D", Transfer: "ref_mut"},
+		},
+	})
+
+	types := resolver.ResolvedTypes{
+		"Common.ErrorCode":     &resolver.TypeInfo{Kind: resolver.TypeKindEnum},
+		"Common.EventQueue":    &resolver.TypeInfo{Kind: resolver.TypeKindTable},
+		"Geometry.Transform3D": &resolver.TypeInfo{Kind: resolver.TypeKindStruct},
+	}
+
+	result := Validate(api, types, "", nil)
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Error(), `table parameter "queue" cannot use ref_mut transfer`) {
+		t.Errorf("expected one ref_mut table error, got %v", result.Errors)
+	}
+}
+
 func TestValidate_MethodCollidesWithSyntheticDestructor(t *testing.T) {
 	api := &model.APIDefinition{
 		API: model.APIMetadata{
@@ -252,12 +285,14 @@ func TestValidate_MethodCollidesWithSyntheticDestructor(t *testing.T) {
 
 func TestValidate_CollectsAllErrors(t *testing.T) {
 	api := minimalAPI()
-	// Add multiple errors: duplicate handle + string return + unresolved handle ref
+	// Add multiple errors: duplicate handle + transfer on a handle + unresolved handle ref
 	api.Handles = append(api.Handles, model.HandleDef{Name: "Engine"})
 	api.Interfaces[0].Methods = append(api.Interfaces[0].Methods,
 		model.MethodDef{
-			Name:    "get_name",
-			Returns: &model.ReturnDef{Type: "string"},
+			Name: "pin_engine",
+			Parameters: []model.ParameterDef{
+				{Name: "engine", Type: "handle:Engine", Transfer: "ref"},
+			},
 		},
 		model.MethodDef{
 			Name: "use_widget",
@@ -275,3 +310,840 @@ func TestValidate_CollectsAllErrors(t *testing.T) {
 		t.Errorf("expected at least 3 errors, got %d: %s", len(result.Errors), result.Error())
 	}
 }
+
+func TestValidate_Events(t *testing.T) {
+	types := resolver.ResolvedTypes{
+		"Common.ErrorCode": &resolver.TypeInfo{Kind: resolver.TypeKindEnum},
+		"Input.TouchEvent": &resolver.TypeInfo{Kind: resolver.TypeKindTable},
+		"Geometry.Vector3": &resolver.TypeInfo{Kind: resolver.TypeKindStruct},
+		"Input.Gesture":    &resolver.TypeInfo{Kind: resolver.TypeKindUnion},
+	}
+
+	api := minimalAPI()
+	api.Events = []model.EventDef{{Name: "touch", Type: "Input.TouchEvent"}, {Name: "gesture", Type: "Input.Gesture"}}
+	if result := Validate(api, types, "", nil); !result.IsValid() {
+		t.Errorf("expected valid, got errors:\n%s", result.Error())
+	}
+
+	api.Events = append(api.Events,
+		model.EventDef{Name: "touch", Type: "Input.TouchEvent"},
+		model.EventDef{Name: "moved", Type: "Geometry.Vector3"},
+		model.EventDef{Name: "missing", Type: "Input.Missing"},
+	)
+	result := Validate(api, types, "", nil)
+	for _, want := range []string{`duplicate event name "touch"`, "must be a table or union", `"Input.Missing" not found`} {
+		found := false
+		for _, e := range result.Errors {
+			if strings.Contains(e.Message, want) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected error containing %q, got: %s", want, result.Error())
+		}
+	}
+}
+
+func TestValidate_EventFunctionCollision(t *testing.T) {
+	api := minimalAPI()
+	api.Events = []model.EventDef{{Name: "touch", Type: "Input.TouchEvent"}}
+	api.Interfaces = append(api.Interfaces, model.InterfaceDef{
+		Name: "event",
+		Methods: []model.MethodDef{
+			{Name: "poll"},
+			{Name: "push_touch"},
+		},
+	})
+
+	result := Validate(api, nil, "", nil)
+	for _, want := range []string{"test_api_event_poll", "test_api_event_push_touch"} {
+		found := false
+		for _, e := range result.Errors {
+			if strings.Contains(e.Message, want) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected collision error for %s, got: %s", want, result.Error())
+		}
+	}
+}
+
+func TestValidate_AsyncMethod(t *testing.T) {
+	api := minimalAPI()
+	api.Interfaces = append(api.Interfaces, model.InterfaceDef{
+		Name: "loader",
+		Methods: []model.MethodDef{
+			{
+				Name:  "load",
+				Async: true,
+				Parameters: []model.ParameterDef{
+					{Name: "engine", Type: "handle:Engine"},
+					{Name: "path", Type: "string"},
+				},
+				Returns: &model.ReturnDef{Type: "uint32"},
+			},
+		},
+	})
+	if result := Validate(api, nil, "", nil); !result.IsValid() {
+		t.Errorf("expected valid, got errors:\n%s", result.Error())
+	}
+
+	api.Interfaces[1].Methods = append(api.Interfaces[1].Methods,
+		model.MethodDef{Name: "load_start"},
+		model.MethodDef{
+			Name:  "decode",
+			Async: true,
+			Parameters: []model.ParameterDef{
+				{Name: "data", Type: "buffer<uint8>", Transfer: "ref_mut"},
+			},
+		},
+	)
+	result := Validate(api, nil, "", nil)
+	for _, want := range []string{`generates "load_start"`, `must not take ref_mut parameter "data"`, `async method "decode" must take a handle parameter`} {
+		found := false
+		for _, e := range result.Errors {
+			if strings.Contains(e.Message, want) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected error containing %q, got: %s", want, result.Error())
+		}
+	}
+}
+
+func TestValidate_Optionals(t *testing.T) {
+	types := resolver.ResolvedTypes{
+		"Common.ErrorCode":         &resolver.TypeInfo{Kind: resolver.TypeKindEnum},
+		"Rendering.RendererConfig": &resolver.TypeInfo{Kind: resolver.TypeKindStruct},
+	}
+	api := minimalAPI()
+	api.Interfaces = append(api.Interfaces, model.InterfaceDef{
+		Name: "scene",
+		Methods: []model.MethodDef{
+			{
+				Name: "set_label",
+				Parameters: []model.ParameterDef{
+					{Name: "engine", Type: "handle:Engine"},
+					{Name: "label", Type: "string", Optional: true},
+					{Name: "limit", Type: "uint32", Optional: true},
+					{Name: "parent", Type: "handle:Engine", Optional: true},
+					{Name: "config", Type: "Rendering.RendererConfig", Transfer: "ref", Optional: true},
+				},
+				Returns: &model.ReturnDef{Type: "string", Optional: true},
+			},
+		},
+	})
+	if result := Validate(api, types, "", nil); !result.IsValid() {
+		t.Fatalf("expected valid, got errors:\n%s", result.Error())
+	}
+
+	api.Interfaces[0].Constructors = []model.MethodDef{{
+		Name:    "open_engine",
+		Returns: &model.ReturnDef{Type: "handle:Engine", Optional: true},
+		Error:   "Common.ErrorCode",
+	}}
+	api.Interfaces[1].Methods = append(api.Interfaces[1].Methods,
+		model.MethodDef{
+			Name: "read",
+			Parameters: []model.ParameterDef{
+				{Name: "data", Type: "buffer<uint8>", Transfer: "ref", Optional: true},
+				{Name: "config", Type: "Rendering.RendererConfig", Optional: true},
+			},
+			Returns: &model.ReturnDef{Type: "buffer<uint8>", Optional: true},
+		},
+		model.MethodDef{
+			Name:       "fetch",
+			Async:      true,
+			Parameters: []model.ParameterDef{{Name: "engine", Type: "handle:Engine"}},
+			Returns:    &model.ReturnDef{Type: "uint32", Optional: true},
+		},
+	)
+	result := Validate(api, types, "", nil)
+	for _, want := range []string{
+		`constructor "open_engine" cannot have an optional return`,
+		"buffer<T> parameters cannot be optional",
+		"optional FlatBuffer parameters must specify ref or ref_mut transfer",
+		"buffer<T> returns cannot be optional",
+		`async method "fetch" cannot have an optional return`,
+	} {
+		found := false
+		for _, e := range result.Errors {
+			if strings.Contains(e.Message, want) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected error containing %q, got: %s", want, result.Error())
+		}
+	}
+}
+
+func TestValidate_Ownership(t *testing.T) {
+	types := resolver.ResolvedTypes{
+		"Common.ErrorCode":         &resolver.TypeInfo{Kind: resolver.TypeKindEnum},
+		"Rendering.RendererConfig": &resolver.TypeInfo{Kind: resolver.TypeKindStruct},
+	}
+	api := minimalAPI()
+	api.Handles = append(api.Handles, model.HandleDef{Name: "Texture"})
+	api.Interfaces = append(api.Interfaces, model.InterfaceDef{
+		Name: "scene",
+		Methods: []model.MethodDef{
+			{
+				Name: "attach_texture",
+				Parameters: []model.ParameterDef{
+					{Name: "engine", Type: "handle:Engine"},
+					{Name: "texture", Type: "handle:Texture", Transfer: "move"},
+				},
+			},
+			{
+				Name:       "default_texture",
+				Parameters: []model.ParameterDef{{Name: "engine", Type: "handle:Engine"}},
+				Returns:    &model.ReturnDef{Type: "handle:Texture", Borrowed: true, Optional: true},
+			},
+		},
+	})
+	if result := Validate(api, types, "", nil); !result.IsValid() {
+		t.Fatalf("expected valid, got errors:\n%s", result.Error())
+	}
+
+	api.Interfaces[0].Constructors = []model.MethodDef{{
+		Name:    "open_engine",
+		Returns: &model.ReturnDef{Type: "handle:Engine", Borrowed: true},
+		Error:   "Common.ErrorCode",
+	}}
+	api.Interfaces[1].Methods = append(api.Interfaces[1].Methods,
+		model.MethodDef{
+			Name: "load",
+			Parameters: []model.ParameterDef{
+				{Name: "engine", Type: "handle:Engine"},
+				{Name: "count", Type: "uint32", Transfer: "move"},
+				{Name: "config", Type: "Rendering.RendererConfig", Transfer: "move"},
+			},
+			Returns: &model.ReturnDef{Type: "uint32", Borrowed: true},
+		},
+		model.MethodDef{
+			Name:       "read",
+			Parameters: []model.ParameterDef{{Name: "data", Type: "buffer<uint8>", Transfer: "move"}},
+		},
+		model.MethodDef{
+			Name:       "fetch",
+			Async:      true,
+			Parameters: []model.ParameterDef{{Name: "engine", Type: "handle:Engine"}},
+			Returns:    &model.ReturnDef{Type: "handle:Texture", Borrowed: true},
+		},
+	)
+	result := Validate(api, types, "", nil)
+	for _, want := range []string{
+		`constructor "open_engine" cannot return a borrowed handle`,
+		"move transfer only applies to handle parameters",
+		"only handle returns can be borrowed",
+		"buffer<T> parameters must specify ref or ref_mut transfer",
+		`async method "fetch" cannot return a borrowed handle`,
+	} {
+		found := false
+		for _, e := range result.Errors {
+			if strings.Contains(e.Message, want) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected error containing %q, got: %s", want, result.Error())
+		}
+	}
+	moves := 0
+	for _, e := range result.Errors {
+		if strings.Contains(e.Message, "move transfer only applies") {
+			moves++
+		}
+	}
+	if moves != 2 {
+		t.Errorf("expected 2 move transfer errors, got %d: %s", moves, result.Error())
+	}
+}
+
+// extendsAPI returns a definition where texture extends the abstract node interface.
+func extendsAPI() *model.APIDefinition {
+	api := minimalAPI()
+	api.Handles = append(api.Handles, model.HandleDef{Name: "Node"}, model.HandleDef{Name: "Texture"})
+	api.Interfaces = append(api.Interfaces,
+		model.InterfaceDef{
+			Name: "node",
+			Methods: []model.MethodDef{{
+				Name:       "set_name",
+				Parameters: []model.ParameterDef{{Name: "node", Type: "handle:Node"}, {Name: "name", Type: "string"}},
+			}},
+		},
+		model.InterfaceDef{
+			Name:    "texture",
+			Extends: "node",
+			Methods: []model.MethodDef{{
+				Name:       "get_width",
+				Parameters: []model.ParameterDef{{Name: "texture", Type: "handle:Texture"}},
+				Returns:    &model.ReturnDef{Type: "uint32"},
+			}},
+		},
+	)
+	return api
+}
+
+func TestValidate_Extends(t *testing.T) {
+	if result := Validate(extendsAPI(), nil, "", nil); !result.IsValid() {
+		t.Errorf("expected valid, got errors:\n%s", result.Error())
+	}
+}
+
+func TestValidate_ExtendsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(api *model.APIDefinition)
+		want   string
+	}{
+		{
+			name:   "undefined base",
+			modify: func(api *model.APIDefinition) { api.Interfaces[2].Extends = "widget" },
+			want:   `interface "texture" extends undefined interface "widget"`,
+		},
+		{
+			name:   "self cycle",
+			modify: func(api *model.APIDefinition) { api.Interfaces[2].Extends = "texture" },
+			want:   "inheritance cycle: texture -> texture",
+		},
+		{
+			name:   "two-interface cycle",
+			modify: func(api *model.APIDefinition) { api.Interfaces[1].Extends = "texture" },
+			want:   "inheritance cycle: node -> texture -> node",
+		},
+		{
+			name: "inherited name collision",
+			modify: func(api *model.APIDefinition) {
+				api.Interfaces[2].Methods[0].Name = "set_name"
+			},
+			want: `interface "texture" declares "set_name", which collides with the method inherited from "node"`,
+		},
+		{
+			name: "same receiver",
+			modify: func(api *model.APIDefinition) {
+				api.Interfaces[2].Methods[0].Parameters[0].Type = "handle:Node"
+			},
+			want: `both operate on handle "Node"`,
+		},
+		{
+			name: "mixed receivers",
+			modify: func(api *model.APIDefinition) {
+				api.Interfaces[2].Methods = append(api.Interfaces[2].Methods, model.MethodDef{
+					Name:       "bind",
+					Parameters: []model.ParameterDef{{Name: "engine", Type: "handle:Engine"}},
+				})
+			},
+			want: `interface "texture" must take the same handle as the first parameter of every method to extend "node"`,
+		},
+		{
+			name: "base with constructors",
+			modify: func(api *model.APIDefinition) {
+				api.Interfaces[1].Constructors = []model.MethodDef{{
+					Name:    "create_node",
+					Returns: &model.ReturnDef{Type: "handle:Node"},
+					Error:   "Common.ErrorCode",
+				}}
+			},
+			want: `base interface "node" cannot declare constructors`,
+		},
+		{
+			name: "two parents",
+			modify: func(api *model.APIDefinition) {
+				api.Handles = append(api.Handles, model.HandleDef{Name: "Named"})
+				api.Interfaces = append(api.Interfaces,
+					model.InterfaceDef{
+						Name: "named",
+						Methods: []model.MethodDef{{
+							Name:       "get_label",
+							Parameters: []model.ParameterDef{{Name: "named", Type: "handle:Named"}},
+						}},
+					},
+					model.InterfaceDef{
+						Name:    "texture_labels",
+						Extends: "named",
+						Methods: []model.MethodDef{{
+							Name:       "get_texture_label",
+							Parameters: []model.ParameterDef{{Name: "texture", Type: "handle:Texture"}},
+						}},
+					},
+				)
+			},
+			want: `handle "Texture" cannot extend both "Node" and "Named"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := extendsAPI()
+			tt.modify(api)
+			result := Validate(api, nil, "", nil)
+			found := false
+			for _, e := range result.Errors {
+				if strings.Contains(e.Message, tt.want) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected error containing %q, got: %s", tt.want, result.Error())
+			}
+		})
+	}
+}
+
+// lifecycleAPI returns a valid API whose handles, interfaces and methods carry
+// lifecycle annotations.
+func lifecycleAPI() *model.APIDefinition {
+	api := minimalAPI()
+	api.API.Version = "1.2.0"
+	api.Handles = append(api.Handles,
+		model.HandleDef{Name: "Sprite", Lifecycle: model.Lifecycle{
+			Since:      "1.0.0",
+			Deprecated: &model.Deprecation{Message: "Use quads", Replacement: "Engine"},
+		}},
+	)
+	api.Interfaces[0].Since = "1.0.0"
+	api.Interfaces[0].Methods = append(api.Interfaces[0].Methods,
+		model.MethodDef{
+			Name:       "reset",
+			Parameters: []model.ParameterDef{{Name: "engine", Type: "handle:Engine"}},
+			Lifecycle:  model.Lifecycle{Since: "1.2.0", Stability: model.StabilityExperimental},
+		},
+		model.MethodDef{
+			Name:       "restart",
+			Parameters: []model.ParameterDef{{Name: "engine", Type: "handle:Engine"}},
+			Lifecycle: model.Lifecycle{
+				Deprecated: &model.Deprecation{Message: "Renamed", Replacement: "reset"},
+			},
+		},
+	)
+	api.Interfaces = append(api.Interfaces, model.InterfaceDef{
+		Name:      "legacy",
+		Lifecycle: model.Lifecycle{Deprecated: &model.Deprecation{Message: "Retired", Replacement: "lifecycle"}},
+		Methods: []model.MethodDef{{
+			Name:       "flush",
+			Parameters: []model.ParameterDef{{Name: "engine", Type: "handle:Engine"}},
+		}},
+	})
+	return api
+}
+
+func TestValidate_Lifecycle(t *testing.T) {
+	result := Validate(lifecycleAPI(), nil, "", nil)
+	if !result.IsValid() {
+		t.Errorf("expected valid, got errors:\n%s", result.Error())
+	}
+}
+
+func TestValidate_LifecycleErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(api *model.APIDefinition)
+		want   string
+	}{
+		{
+			name:   "handle since after API version",
+			modify: func(api *model.APIDefinition) { api.Handles[1].Since = "1.10.0" },
+			want:   `since "1.10.0" is later than the API version "1.2.0"`,
+		},
+		{
+			name:   "method since after API version",
+			modify: func(api *model.APIDefinition) { api.Interfaces[0].Methods[1].Since = "2.0.0" },
+			want:   `since "2.0.0" is later than the API version "1.2.0"`,
+		},
+		{
+			name: "method before its interface",
+			modify: func(api *model.APIDefinition) {
+				api.Interfaces[0].Since = "1.1.0"
+				api.Interfaces[0].Methods[1].Since = "1.0.5"
+			},
+			want: `method "reset" is marked since "1.0.5", before its interface "lifecycle" was introduced in "1.1.0"`,
+		},
+		{
+			name:   "undefined handle replacement",
+			modify: func(api *model.APIDefinition) { api.Handles[1].Deprecated.Replacement = "Quad" },
+			want:   `handle "Sprite" names replacement "Quad", which is not defined`,
+		},
+		{
+			name:   "undefined interface replacement",
+			modify: func(api *model.APIDefinition) { api.Interfaces[1].Deprecated.Replacement = "modern" },
+			want:   `interface "legacy" names replacement "modern", which is not defined`,
+		},
+		{
+			name:   "method replacement outside its interface",
+			modify: func(api *model.APIDefinition) { api.Interfaces[0].Methods[2].Deprecated.Replacement = "flush" },
+			want:   `method "restart" names replacement "flush", which is not defined`,
+		},
+		{
+			name:   "self replacement",
+			modify: func(api *model.APIDefinition) { api.Interfaces[0].Methods[2].Deprecated.Replacement = "restart" },
+			want:   `method "restart" cannot name itself as its replacement`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := lifecycleAPI()
+			tt.modify(api)
+			result := Validate(api, nil, "", nil)
+			found := false
+			for _, e := range result.Errors {
+				if strings.Contains(e.Message, tt.want) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected error containing %q, got: %s", tt.want, result.Error())
+			}
+		})
+	}
+}
+
+func TestValidate_ThreadAffinity(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(api *model.APIDefinition)
+		want   string // empty when the definition is valid
+	}{
+		{
+			name: "creator handle with constructor",
+			modify: func(api *model.APIDefinition) {
+				api.Handles[0].ThreadAffinity = model.ThreadAffinityCreator
+				api.Interfaces[0].Constructors = api.Interfaces[0].Methods
+				api.Interfaces[0].Methods = nil
+			},
+		},
+		{
+			name:   "creator handle without constructor",
+			modify: func(api *model.APIDefinition) { api.Handles[0].ThreadAffinity = model.ThreadAffinityCreator },
+			want:   `handle "Engine" has thread affinity "creator" but no constructor creates it`,
+		},
+		{
+			name: "creator method without handle",
+			modify: func(api *model.APIDefinition) {
+				api.Interfaces[0].Methods = append(api.Interfaces[0].Methods, model.MethodDef{
+					Name:           "tick",
+					ThreadAffinity: model.ThreadAffinityCreator,
+				})
+			},
+			want: `method "tick" has thread affinity "creator" but takes no handle to check it against`,
+		},
+		{
+			name: "creator interface with factory method",
+			modify: func(api *model.APIDefinition) {
+				api.Interfaces[0].ThreadAffinity = model.ThreadAffinityCreator
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := minimalAPI()
+			tt.modify(api)
+			result := Validate(api, nil, "", nil)
+			if tt.want == "" {
+				if !result.IsValid() {
+					t.Errorf("expected valid, got errors:\n%s", result.Error())
+				}
+				return
+			}
+			found := false
+			for _, e := range result.Errors {
+				if strings.Contains(e.Message, tt.want) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected error containing %q, got: %s", tt.want, result.Error())
+			}
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.0", "1.2.0", 0},
+		{"1.2.0", "1.10.0", -1},
+		{"2.0.0", "1.99.99", 1},
+		{"0.1.1", "0.1.0", 1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestValidate_DidYouMean(t *testing.T) {
+	api := minimalAPI()
+	api.Interfaces[0].Methods = append(api.Interfaces[0].Methods, model.MethodDef{
+		Name: "reset",
+		Parameters: []model.ParameterDef{
+			{Name: "engine", Type: "handle:Engin"},
+			{Name: "config", Type: "Common.Confg", Transfer: "ref"},
+			{Name: "other", Type: "Render.Mesh", Transfer: "ref"},
+		},
+	})
+	types := resolver.ResolvedTypes{
+		"Common.ErrorCode": &resolver.TypeInfo{Kind: resolver.TypeKindEnum},
+		"Common.Config":    &resolver.TypeInfo{Kind: resolver.TypeKindTable},
+	}
+
+	result := Validate(api, types, "", nil)
+	want := []struct{ path, rule, fix string }{
+		{"interfaces[0].methods[1].parameters[0].type", "undefined-handle", `did you mean "Engine"?`},
+		{"interfaces[0].methods[1].parameters[1].type", "undefined-type", `did you mean "Common.Config"?`},
+		{"interfaces[0].methods[1].parameters[2].type", "undefined-type", ""},
+	}
+	if len(result.Errors) != len(want) {
+		t.Fatalf("got %d errors, want %d:\n%s", len(result.Errors), len(want), result.Error())
+	}
+	for i, w := range want {
+		e := result.Errors[i]
+		if e.Path != w.path || e.Rule != w.rule || e.Fix != w.fix {
+			t.Errorf("error %d = %s [%s] fix %q, want %s [%s] fix %q", i, e.Path, e.Rule, e.Fix, w.path, w.rule, w.fix)
+		}
+	}
+	if !strings.Contains(result.Error(), `handle "Engin" not defined in handles section; did you mean "Engine"?`) {
+		t.Errorf("expected the fix in the error text, got:\n%s", result.Error())
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "abc", 3},
+		{"Engine", "Engine", 0},
+		{"Engin", "Engine", 1},
+		{"kitten", "sitting", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestValidate_NoInterfaces(t *testing.T) {
+	api := minimalAPI()
+	api.Interfaces = nil
+
+	result := Validate(api, nil, "", nil)
+	if result.IsValid() {
+		t.Fatal("expected validation error for an API without interfaces")
+	}
+	if !strings.Contains(result.Error(), "at least one interface") {
+		t.Errorf("unexpected error: %v", result.Error())
+	}
+}
+
+func TestValidate_SourceLocationsAcrossFiles(t *testing.T) {
+	api := minimalAPI()
+	api.Handles = append(api.Handles, model.HandleDef{Name: "Engine"})
+	api.Interfaces = append(api.Interfaces, model.InterfaceDef{Name: "lifecycle"})
+	srcMap := model.SourceMap{
+		"handles[0].name":    {File: "api.yaml", Line: 9},
+		"handles[1].name":    {File: "imports/render.yaml", Line: 2},
+		"interfaces[0].name": {File: "api.yaml", Line: 13},
+		"interfaces[1].name": {File: "imports/render.yaml", Line: 5},
+	}
+
+	result := Validate(api, nil, "api.yaml", srcMap)
+	want := []string{
+		`imports/render.yaml:2: handles[1].name: duplicate handle name "Engine" (first defined at api.yaml:9)`,
+		`imports/render.yaml:5: interfaces[1].name: duplicate interface name "lifecycle" (first defined at api.yaml:13)`,
+	}
+	for _, w := range want {
+		if !strings.Contains(result.Error(), w) {
+			t.Errorf("expected error %q, got:\n%s", w, result.Error())
+		}
+	}
+}
+
+func TestCheckSchemaUse(t *testing.T) {
+	api := minimalAPI()
+	api.FlatBuffers = []string{"specs/common.fbs", "specs/app.fbs", "specs/extra.fbs"}
+	api.Interfaces[0].Methods = append(api.Interfaces[0].Methods, model.MethodDef{
+		Name:       "render",
+		Parameters: []model.ParameterDef{{Name: "scene", Type: "App.Scene", Transfer: "ref"}},
+		Error:      "Common.ErrorCode",
+	})
+	// app.fbs reaches shapes.fbs through a field; extra.fbs declares a type
+	// nothing references and includes nothing the API uses.
+	set := &resolver.FBSSet{
+		Types: resolver.ResolvedTypes{
+			"Common.ErrorCode": {Kind: resolver.TypeKindEnum, Pos: resolver.Pos{File: "/s/common.fbs"}},
+			"App.Scene": {Kind: resolver.TypeKindTable, Pos: resolver.Pos{File: "/s/app.fbs"},
+				Fields: []resolver.FieldDef{{Name: "shape", Type: "Shapes.Circle"}}},
+			"Shapes.Circle": {Kind: resolver.TypeKindTable, Pos: resolver.Pos{File: "/s/shapes.fbs"}},
+			"Extra.Tag":     {Kind: resolver.TypeKindTable, Pos: resolver.Pos{File: "/s/extra.fbs"}},
+		},
+		Listed:   []string{"/s/common.fbs", "/s/app.fbs", "/s/extra.fbs"},
+		Includes: map[string][]string{"/s/app.fbs": {"/s/shapes.fbs"}},
+	}
+	srcMap := model.SourceMap{"flatbuffers[2]": {File: "api.yaml", Line: 8}}
+
+	result := Validate(api, set.Types, "api.yaml", srcMap)
+	CheckSchemaUse(result, api, set)
+	if !result.IsValid() {
+		t.Fatalf("expected valid, got errors:\n%s", result.Error())
+	}
+	if len(result.Warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %v", result.Warnings)
+	}
+	want := `api.yaml:8: flatbuffers[2]: schema "specs/extra.fbs" contributes no types the API uses`
+	if got := result.Warnings[0].Error(); got != want {
+		t.Errorf("expected warning %q, got %q", want, got)
+	}
+
+	// A file that only includes used schemas still contributes them.
+	set.Listed[2] = "/s/umbrella.fbs"
+	set.Includes["/s/umbrella.fbs"] = []string{"/s/shapes.fbs"}
+	result = Validate(api, set.Types, "api.yaml", srcMap)
+	CheckSchemaUse(result, api, set)
+	if len(result.Warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", result.Warnings)
+	}
+}
+
+func TestCheckReservedWords(t *testing.T) {
+	api := minimalAPI()
+	api.Interfaces[0].Methods = append(api.Interfaces[0].Methods, model.MethodDef{
+		Name: "delete",
+		Parameters: []model.ParameterDef{
+			{Name: "engine", Type: "handle:Engine"},
+			{Name: "type", Type: "string"},
+		},
+	})
+	reservedIn := func(name string) []string {
+		switch name {
+		case "delete":
+			return []string{"C++", "JavaScript"}
+		case "type":
+			return []string{"Go", "Rust"}
+		}
+		return nil
+	}
+	srcMap := model.SourceMap{"interfaces[0].methods[1].parameters[1].name": {File: "api.yaml", Line: 14}}
+
+	result := &ValidationResult{file: "api.yaml", srcMap: srcMap}
+	CheckReservedWords(result, api, reservedIn)
+	want := []string{
+		`api.yaml: interfaces[0].methods[1].name: method name "delete" is reserved in C++, JavaScript; generated code escapes it`,
+		`api.yaml:14: interfaces[0].methods[1].parameters[1].name: parameter name "type" is reserved in Go, Rust; generated code escapes it`,
+	}
+	if len(result.Warnings) != len(want) {
+		t.Fatalf("expected %d warnings, got %v", len(want), result.Warnings)
+	}
+	for i, w := range want {
+		if got := result.Warnings[i].Error(); got != w {
+			t.Errorf("warning %d: expected %q, got %q", i, w, got)
+		}
+	}
+}
+
+func TestValidate_Namespaces(t *testing.T) {
+	// Scene.Node refers to Geo.Point through a field and Geo.Shape to
+	// Scene.Node through a member.
+	types := resolver.ResolvedTypes{
+		"Common.ErrorCode": {Kind: resolver.TypeKindEnum},
+		"Scene.Node": {Kind: resolver.TypeKindStruct,
+			Fields: []resolver.FieldDef{{Name: "origin", Type: "Geo.Point"}, {Name: "code", Type: "Common.ErrorCode"}}},
+		"Geo.Point": {Kind: resolver.TypeKindStruct, Fields: []resolver.FieldDef{{Name: "x", Type: "float32"}}},
+		"Geo.Shape": {Kind: resolver.TypeKindUnion, Members: []resolver.UnionMember{{Name: "Node", Type: "Scene.Node"}}},
+	}
+	withNode := func(api *model.APIDefinition) {
+		api.Interfaces[0].Methods = append(api.Interfaces[0].Methods, model.MethodDef{
+			Name:       "root",
+			Parameters: []model.ParameterDef{{Name: "engine", Type: "handle:Engine"}},
+			Returns:    &model.ReturnDef{Type: "Scene.Node"},
+		})
+	}
+
+	tests := []struct {
+		name   string
+		modify func(api *model.APIDefinition)
+		want   string // "" for valid
+	}{
+		{
+			name: "valid mapping",
+			modify: func(api *model.APIDefinition) {
+				api.Namespaces = []model.NamespaceDef{
+					{Name: "Scene", Kotlin: "com.acme.scene", Swift: "Scn", JS: "scene", Rust: "scene"},
+					{Name: "Geo", Kotlin: "com.acme.geo"},
+				}
+			},
+		},
+		{
+			name: "duplicate namespace",
+			modify: func(api *model.APIDefinition) {
+				api.Namespaces = []model.NamespaceDef{{Name: "Scene", Rust: "scene"}, {Name: "Scene", Rust: "scene2"}}
+			},
+			want: `namespaces[1].name: duplicate namespace mapping "Scene"`,
+		},
+		{
+			name: "namespace without types",
+			modify: func(api *model.APIDefinition) {
+				api.Namespaces = []model.NamespaceDef{{Name: "Audio", Rust: "audio"}}
+			},
+			want: `namespaces[0].name: namespace "Audio" declares no types in the FlatBuffers schemas`,
+		},
+		{
+			name: "shared target",
+			modify: func(api *model.APIDefinition) {
+				api.Namespaces = []model.NamespaceDef{{Name: "Scene", JS: "shapes"}, {Name: "Geo", JS: "shapes"}}
+			},
+			want: `namespaces[1].js: namespaces "Scene" and "Geo" both map to js "shapes"`,
+		},
+		{
+			name: "go package refers to unmapped namespace",
+			modify: func(api *model.APIDefinition) {
+				api.API.ImplLang = "go"
+				withNode(api)
+				api.Namespaces = []model.NamespaceDef{{Name: "Scene", Go: "scene"}}
+			},
+			want: `namespaces[0].go: type Scene.Node refers to Geo.Point, whose namespace "Geo" has no Go package; map it too`,
+		},
+		{
+			name: "go packages in a cycle",
+			modify: func(api *model.APIDefinition) {
+				api.API.ImplLang = "go"
+				withNode(api)
+				api.Interfaces[0].Methods = append(api.Interfaces[0].Methods, model.MethodDef{
+					Name:       "pick",
+					Parameters: []model.ParameterDef{{Name: "engine", Type: "handle:Engine"}, {Name: "shape", Type: "Geo.Shape"}},
+				})
+				api.Namespaces = []model.NamespaceDef{{Name: "Scene", Go: "scene"}, {Name: "Geo", Go: "geo"}}
+			},
+			want: `Go packages of namespaces import each other in a cycle: Scene -> Geo -> Scene`,
+		},
+		{
+			name: "go rules skipped for other implementations",
+			modify: func(api *model.APIDefinition) {
+				withNode(api)
+				api.Namespaces = []model.NamespaceDef{{Name: "Scene", Go: "scene"}}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := minimalAPI()
+			tt.modify(api)
+			result := Validate(api, types, "", nil)
+			if tt.want == "" {
+				if !result.IsValid() {
+					t.Fatalf("expected valid, got errors:\n%s", result.Error())
+				}
+				return
+			}
+			if !strings.Contains(result.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got:\n%s", tt.want, result.Error())
+			}
+		})
+	}
+}